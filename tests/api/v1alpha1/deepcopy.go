@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// DeepCopyInto copies the run into out
+func (in *InstructLabRun) DeepCopyInto(out *InstructLabRun) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy copies the run
+func (in *InstructLabRun) DeepCopy() *InstructLabRun {
+	if in == nil {
+		return nil
+	}
+	out := new(InstructLabRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject copies the run
+func (in *InstructLabRun) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the list into out
+func (in *InstructLabRunList) DeepCopyInto(out *InstructLabRunList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]InstructLabRun, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy copies the list
+func (in *InstructLabRunList) DeepCopy() *InstructLabRunList {
+	if in == nil {
+		return nil
+	}
+	out := new(InstructLabRunList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject copies the list
+func (in *InstructLabRunList) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the spec into out
+func (in *InstructLabRunSpec) DeepCopyInto(out *InstructLabRunSpec) {
+	*out = *in
+	in.Training.DeepCopyInto(&out.Training)
+	if in.ObjectStore != nil {
+		out.ObjectStore = new(ObjectStoreRef)
+		*out.ObjectStore = *in.ObjectStore
+	}
+	if in.Params != nil {
+		out.Params = in.Params.DeepCopy()
+	}
+}
+
+// DeepCopyInto copies the training into out
+func (in *TrainingConfig) DeepCopyInto(out *TrainingConfig) {
+	*out = *in
+	if in.Seed != nil {
+		out.Seed = new(int64)
+		*out.Seed = *in.Seed
+	}
+	if in.NodeSelectors != nil {
+		out.NodeSelectors = make(map[string]string, len(in.NodeSelectors))
+		for key, value := range in.NodeSelectors {
+			out.NodeSelectors[key] = value
+		}
+	}
+	if in.Tolerations != nil {
+		out.Tolerations = make([]corev1.Toleration, len(in.Tolerations))
+		for i := range in.Tolerations {
+			in.Tolerations[i].DeepCopyInto(&out.Tolerations[i])
+		}
+	}
+}
+
+// DeepCopyInto copies the status into out
+func (in *InstructLabRunStatus) DeepCopyInto(out *InstructLabRunStatus) {
+	*out = *in
+	if in.Tasks != nil {
+		out.Tasks = make([]TaskStatus, len(in.Tasks))
+		copy(out.Tasks, in.Tasks)
+	}
+	if in.Conditions != nil {
+		out.Conditions = make([]metav1.Condition, len(in.Conditions))
+		for i := range in.Conditions {
+			in.Conditions[i].DeepCopyInto(&out.Conditions[i])
+		}
+	}
+}
@@ -0,0 +1,33 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package v1alpha1 defines the InstructLabRun custom resource, a run of the InstructLab pipeline driven by the
+// controller of the controllers package, in the ilab.opendatahub.io group. Its CRD is
+// config/crd/bases/ilab.opendatahub.io_instructlabruns.yaml.
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+)
+
+var (
+	// GroupVersion is the group and version of the resources of the package
+	GroupVersion = schema.GroupVersion{Group: "ilab.opendatahub.io", Version: "v1alpha1"}
+
+	// SchemeBuilder registers the resources of the package in a scheme
+	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
+
+	// AddToScheme adds the resources of the package to a scheme
+	AddToScheme = SchemeBuilder.AddToScheme
+)
@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Condition types of an InstructLabRun
+const (
+	// ConditionSubmitted is True once the run was submitted to the pipeline server, False when it cannot be
+	ConditionSubmitted = "Submitted"
+	// ConditionRunning is True until the run finishes
+	ConditionRunning = "Running"
+	// ConditionSucceeded is True when the run succeeded, False when it finished otherwise, Unknown until then
+	ConditionSucceeded = "Succeeded"
+)
+
+// InstructLabRunSpec is the run of the pipeline to start. The spec is read once, when the run is submitted.
+type InstructLabRunSpec struct {
+	// Pipeline is the display name of the pipeline on the pipeline server, instructlab when empty
+	Pipeline string    `json:"pipeline,omitempty"`
+	SDG      SDGConfig `json:"sdg"`
+	// Training is the training of the run, the defaults of the pipeline when empty
+	Training TrainingConfig `json:"training,omitempty"`
+	// TeacherSecret holds the endpoint, model and API key of the teacher model, sdg_teacher_secret
+	TeacherSecret string `json:"teacherSecret,omitempty"`
+	// JudgeSecret holds the endpoint, model and API key of the judge model, eval_judge_secret
+	JudgeSecret string `json:"judgeSecret,omitempty"`
+	// ObjectStore stores the artifacts of the run, under the default pipeline root of the pipeline server when nil
+	ObjectStore *ObjectStoreRef `json:"objectStore,omitempty"`
+	// StorageClass is the storage class of the PVCs of the run, k8s_storage_class_name
+	StorageClass string `json:"storageClass,omitempty"`
+	// Params are further pipeline parameters by name, e.g. the output model settings. The fields of the spec take
+	// precedence.
+	Params *runtime.RawExtension `json:"params,omitempty"`
+}
+
+// SDGConfig is the synthetic data generation of a run, from the sdg_* parameters
+type SDGConfig struct {
+	// RepoURL is the git repository of the taxonomy
+	RepoURL    string `json:"repoURL"`
+	RepoBranch string `json:"repoBranch,omitempty"`
+	// RepoPR is the pull request of the taxonomy to clone instead of the branch, none when zero
+	RepoPR int64 `json:"repoPR,omitempty"`
+	// RepoSecret holds the credentials of the taxonomy repository
+	RepoSecret string `json:"repoSecret,omitempty"`
+	// BaseModel is the URI of the base model, e.g. s3://bucket/granite-7b-starter
+	BaseModel   string `json:"baseModel,omitempty"`
+	Pipeline    string `json:"pipeline,omitempty"`
+	ScaleFactor int64  `json:"scaleFactor,omitempty"`
+	BatchSize   int64  `json:"batchSize,omitempty"`
+	NumWorkers  int64  `json:"numWorkers,omitempty"`
+}
+
+// TrainingConfig is the training of a run, from the train_* parameters
+type TrainingConfig struct {
+	NumWorkers   int64 `json:"numWorkers,omitempty"`
+	GPUPerWorker int64 `json:"gpuPerWorker,omitempty"`
+	// GPUIdentifier is the resource name of the GPUs, e.g. nvidia.com/gpu
+	GPUIdentifier   string `json:"gpuIdentifier,omitempty"`
+	CPUPerWorker    string `json:"cpuPerWorker,omitempty"`
+	MemoryPerWorker string `json:"memoryPerWorker,omitempty"`
+	NumEpochsPhase1 int64  `json:"numEpochsPhase1,omitempty"`
+	NumEpochsPhase2 int64  `json:"numEpochsPhase2,omitempty"`
+	Seed            *int64 `json:"seed,omitempty"`
+	// NodeSelectors and Tolerations place the training pods
+	NodeSelectors map[string]string   `json:"nodeSelectors,omitempty"`
+	Tolerations   []corev1.Toleration `json:"tolerations,omitempty"`
+}
+
+// ObjectStoreRef is the bucket storing the artifacts of a run
+type ObjectStoreRef struct {
+	// SecretName is a data connection secret of the namespace, its AWS_S3_BUCKET is the bucket of the artifacts
+	SecretName string `json:"secretName"`
+	// Prefix is the key prefix of the artifacts in the bucket, the name of the InstructLabRun when empty
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// InstructLabRunStatus is the state of the pipeline run of an InstructLabRun
+type InstructLabRunStatus struct {
+	// ObservedGeneration is the generation of the spec the run was submitted with
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// RunID is the ID of the run on the pipeline server
+	RunID string `json:"runId,omitempty"`
+	// State is the state of the run on the pipeline server, e.g. RUNNING or SUCCEEDED
+	State string       `json:"state,omitempty"`
+	Tasks []TaskStatus `json:"tasks,omitempty"`
+	// Conditions are the Submitted, Running and Succeeded conditions of the run
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// TaskStatus is the state of a task of the run
+type TaskStatus struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+}
+
+// InstructLabRun is a run of the InstructLab pipeline
+type InstructLabRun struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   InstructLabRunSpec   `json:"spec,omitempty"`
+	Status InstructLabRunStatus `json:"status,omitempty"`
+}
+
+// InstructLabRunList is a list of InstructLabRuns
+type InstructLabRunList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []InstructLabRun `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&InstructLabRun{}, &InstructLabRunList{})
+}
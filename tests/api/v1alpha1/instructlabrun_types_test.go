@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+type schemaProps struct {
+	Properties map[string]schemaProps `json:"properties"`
+	Items      *schemaProps           `json:"items"`
+}
+
+type crd struct {
+	Spec struct {
+		Group string `json:"group"`
+		Names struct {
+			Kind     string `json:"kind"`
+			ListKind string `json:"listKind"`
+		} `json:"names"`
+		Versions []struct {
+			Name   string `json:"name"`
+			Schema struct {
+				OpenAPIV3Schema schemaProps `json:"openAPIV3Schema"`
+			} `json:"schema"`
+		} `json:"versions"`
+	} `json:"spec"`
+}
+
+// opaque types are described by the CRD without their fields
+var opaque = map[reflect.Type]bool{
+	reflect.TypeOf(corev1.Toleration{}):    true,
+	reflect.TypeOf(metav1.Condition{}):     true,
+	reflect.TypeOf(runtime.RawExtension{}): true,
+}
+
+// checkFields verifies the CRD schema has a property for every JSON field of the type, and no other
+func checkFields(t *testing.T, path string, typ reflect.Type, props schemaProps) {
+	for typ.Kind() == reflect.Ptr || typ.Kind() == reflect.Slice {
+		if typ.Kind() == reflect.Slice {
+			require.NotNil(t, props.Items, "%s has no items", path)
+			props = *props.Items
+		}
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct || opaque[typ] {
+		return
+	}
+	var fields []string
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		fields = append(fields, name)
+		checkFields(t, path+"."+name, field.Type, props.Properties[name])
+	}
+	var properties []string
+	for name := range props.Properties {
+		properties = append(properties, name)
+	}
+	sort.Strings(fields)
+	sort.Strings(properties)
+	require.Equal(t, fields, properties, "properties of %s", path)
+}
+
+func TestCRDMatchesTypes(t *testing.T) {
+	data, err := os.ReadFile("../../config/crd/bases/ilab.opendatahub.io_instructlabruns.yaml")
+	require.NoError(t, err)
+	var definition crd
+	require.NoError(t, yaml.Unmarshal(data, &definition))
+	require.Equal(t, GroupVersion.Group, definition.Spec.Group)
+	require.Equal(t, "InstructLabRun", definition.Spec.Names.Kind)
+	require.Equal(t, "InstructLabRunList", definition.Spec.Names.ListKind)
+	require.Len(t, definition.Spec.Versions, 1)
+	require.Equal(t, GroupVersion.Version, definition.Spec.Versions[0].Name)
+
+	root := definition.Spec.Versions[0].Schema.OpenAPIV3Schema
+	checkFields(t, "spec", reflect.TypeOf(InstructLabRunSpec{}), root.Properties["spec"])
+	checkFields(t, "status", reflect.TypeOf(InstructLabRunStatus{}), root.Properties["status"])
+}
+
+func TestDeepCopy(t *testing.T) {
+	seed := int64(42)
+	run := &InstructLabRun{
+		Spec: InstructLabRunSpec{
+			Training:    TrainingConfig{Seed: &seed, NodeSelectors: map[string]string{"gpu": "a100"}, Tolerations: []corev1.Toleration{{Key: "gpu"}}},
+			ObjectStore: &ObjectStoreRef{SecretName: "bucket"},
+			Params:      &runtime.RawExtension{Raw: []byte(`{"train_seed":1}`)},
+		},
+		Status: InstructLabRunStatus{Tasks: []TaskStatus{{Name: "sdg-op"}}, Conditions: []metav1.Condition{{Type: ConditionSubmitted}}},
+	}
+	copied := run.DeepCopyObject().(*InstructLabRun)
+	require.Equal(t, run, copied)
+
+	*copied.Spec.Training.Seed = 7
+	copied.Spec.Training.NodeSelectors["gpu"] = "h100"
+	copied.Spec.Training.Tolerations[0].Key = "other"
+	copied.Spec.ObjectStore.SecretName = "other"
+	copied.Spec.Params.Raw[2] = 'x'
+	copied.Status.Tasks[0].Name = "other"
+	copied.Status.Conditions[0].Type = "other"
+	require.Equal(t, int64(42), *run.Spec.Training.Seed)
+	require.Equal(t, "a100", run.Spec.Training.NodeSelectors["gpu"])
+	require.Equal(t, "gpu", run.Spec.Training.Tolerations[0].Key)
+	require.Equal(t, "bucket", run.Spec.ObjectStore.SecretName)
+	require.Equal(t, `{"train_seed":1}`, string(run.Spec.Params.Raw))
+	require.Equal(t, "sdg-op", run.Status.Tasks[0].Name)
+	require.Equal(t, ConditionSubmitted, run.Status.Conditions[0].Type)
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// egress-probe checks the endpoints given as name=url arguments can be reached from its pod, and reads the address the
+// pod egresses with from an echo service, and prints the result as JSON:
+//
+//	egress-probe -echo-url https://api.ipify.org judge=https://judge.example.com/v1 object-store=https://s3.example.com
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/egressprobe"
+)
+
+func main() {
+	echoURL := flag.String("echo-url", "", "URL of a service answering with the address of the client in plain text, none by default")
+	timeout := flag.Duration("timeout", 10*time.Second, "timeout of the connection and request to each endpoint")
+	flag.Parse()
+
+	var targets []egressprobe.Target
+	for _, arg := range flag.Args() {
+		target, err := egressprobe.ParseTarget(arg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		targets = append(targets, target)
+	}
+	if len(targets) == 0 && *echoURL == "" {
+		log.Fatal("usage: egress-probe [-echo-url URL] [-timeout DURATION] name=url...")
+	}
+
+	for _, target := range targets {
+		log.Printf("Probing %s at %s", target.Name, target.URL)
+	}
+	result := egressprobe.Probe(context.Background(), targets, *echoURL, *timeout)
+	if err := json.NewEncoder(os.Stdout).Encode(result); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// gpu-queue shows and manages the queue of runs waiting for the GPU Lease of a shared cluster
+//
+//	gpu-queue -namespace <namespace> list                            shows the Lease holder and the queue in serving order
+//	gpu-queue -namespace <namespace> submit [flags] -- <command...>  queues for the Lease and runs the command holding it
+//	gpu-queue -namespace <namespace> remove <entry>                  removes an entry from the queue
+//	gpu-queue -namespace <namespace> release                         frees the Lease, e.g. after its holder crashed
+//
+// The Lease and the queue entries are named after the resource prefix of the e2e tests, RESOURCE_PREFIX when set.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"text/tabwriter"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/gpuqueue"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// defaultResourcePrefix matches the default resource prefix of the e2e tests
+const defaultResourcePrefix = "ilab-test-"
+
+func main() {
+	prefix := defaultResourcePrefix
+	if value, ok := os.LookupEnv("RESOURCE_PREFIX"); ok {
+		prefix = value
+	}
+	namespace := flag.String("namespace", "", "namespace of the Lease")
+	leaseName := flag.String("lease", prefix+gpuqueue.LeaseName, "name of the Lease")
+	flag.Parse()
+	if *namespace == "" || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		log.Fatalf("Failed to load Kubernetes client config: %v", err)
+	}
+	client := kubernetes.NewForConfigOrDie(config)
+	ctx := context.Background()
+
+	switch flag.Arg(0) {
+	case "list":
+		err = list(ctx, client, *namespace, *leaseName)
+	case "submit":
+		err = submit(ctx, client, gpuqueue.Request{Namespace: *namespace, Name: *leaseName, GenerateName: prefix + gpuqueue.EntryName + "-"}, flag.Args()[1:])
+	case "remove":
+		if flag.NArg() != 2 {
+			log.Fatal("remove expects the name of the entry")
+		}
+		err = gpuqueue.Remove(ctx, client, *namespace, flag.Arg(1))
+	case "release":
+		err = release(ctx, client, *namespace, *leaseName)
+	default:
+		err = fmt.Errorf("unknown command '%s'", flag.Arg(0))
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func list(ctx context.Context, client kubernetes.Interface, namespace, leaseName string) error {
+	lease, err := client.CoordinationV1().Leases(namespace).Get(ctx, leaseName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if lease.Spec.HolderIdentity != nil && *lease.Spec.HolderIdentity != "" {
+		fmt.Printf("Held by %s", *lease.Spec.HolderIdentity)
+		if lease.Spec.RenewTime != nil {
+			fmt.Printf(", renewed at %s", lease.Spec.RenewTime.Format(time.RFC3339))
+		}
+		fmt.Print("\n\n")
+	} else {
+		fmt.Print("Free\n\n")
+	}
+
+	entries, err := gpuqueue.List(ctx, client, namespace, leaseName)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "POSITION\tENTRY\tHOLDER\tTEAM\tPRIORITY\tENQUEUED\tHEARTBEAT")
+	for i, entry := range gpuqueue.Order(entries, gpuqueue.LastGrants(lease)) {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%s\t%s\n", i+1, entry.Name, entry.Holder, entry.Team, entry.Priority,
+			entry.Enqueued.Format(time.RFC3339), entry.Heartbeat.Format(time.RFC3339))
+	}
+	return w.Flush()
+}
+
+// submit queues for the Lease like the e2e tests do, runs the command once the Lease is acquired and releases it when
+// the command exits, exiting with the status of the command
+func submit(ctx context.Context, client kubernetes.Interface, request gpuqueue.Request, args []string) error {
+	hostname, _ := os.Hostname()
+	flags := flag.NewFlagSet("submit", flag.ExitOnError)
+	flags.StringVar(&request.Holder, "holder", hostname+"-"+rand.String(5), "identity of the holder")
+	flags.StringVar(&request.Team, "team", gpuqueue.DefaultTeam, "team the run is queued for")
+	flags.IntVar(&request.Priority, "priority", 0, "priority of the run, higher priorities are served first")
+	flags.DurationVar(&request.Takeover, "takeover", 30*time.Minute, "time after which a holder or a queue entry that stopped renewing is presumed crashed")
+	flags.DurationVar(&request.Timeout, "timeout", 6*time.Hour, "time to wait for the Lease")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return fmt.Errorf("submit expects the command to run holding the Lease")
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+	held, err := gpuqueue.Acquire(ctx, client, request, log.Printf)
+	if err != nil {
+		return err
+	}
+	log.Printf("Acquired lease %s as %s", request.Name, request.Holder)
+
+	command := exec.CommandContext(ctx, flags.Arg(0), flags.Args()[1:]...)
+	command.Stdin, command.Stdout, command.Stderr = os.Stdin, os.Stdout, os.Stderr
+	runErr := command.Run()
+	if err := held.Release(); err != nil {
+		log.Printf("Failed to release lease %s: %v", request.Name, err)
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(runErr, &exitErr) {
+		os.Exit(exitErr.ExitCode())
+	}
+	return runErr
+}
+
+func release(ctx context.Context, client kubernetes.Interface, namespace, leaseName string) error {
+	leases := client.CoordinationV1().Leases(namespace)
+	lease, err := leases.Get(ctx, leaseName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	lease.Spec.HolderIdentity = nil
+	_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
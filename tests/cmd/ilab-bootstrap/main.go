@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// ilab-bootstrap checks the prerequisites of the ilab pipeline on the cluster of the current kubeconfig: the Node
+// Feature Discovery and NVIDIA GPU operators, the GPU nodes, the OpenShift AI operator and the DataScienceCluster
+// components, and a storage class provisioning ReadWriteMany volumes, probed with a PVC in -namespace named after the
+// resource prefix of the e2e tests, RESOURCE_PREFIX when set. It prints what is ready and how to fix the rest, and
+// exits with status 1 unless everything is ready.
+//
+// With -install, the missing operators are subscribed to through OLM, their custom resources are created from the
+// examples of the operators, and the DataScienceCluster components are set to Managed. Each step is waited for before
+// the next one. The resources which exist are kept, the tool can be run again until the cluster is ready. GPU nodes
+// and storage are never installed.
+//
+// With -uninstall, the resources the tool created, labeled ilab.opendatahub.io/created-by=ilab-bootstrap, are deleted
+// and the DataScienceCluster components it enabled are set back to their previous state. The operators installed
+// before are kept. -dry-run prints what would be done.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/bootstrap"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// defaultResourcePrefix matches the default resource prefix of the e2e tests
+const defaultResourcePrefix = "ilab-test-"
+
+func main() {
+	prefix := defaultResourcePrefix
+	if value, ok := os.LookupEnv("RESOURCE_PREFIX"); ok {
+		prefix = value
+	}
+	namespace := flag.String("namespace", "", "data science project the ReadWriteMany storage is probed in, the storage is not probed when empty")
+	storageClass := flag.String("storage-class", "", "storage class of the pipeline PVCs, the default storage class when empty")
+	minGPUs := flag.Int64("min-gpus", 4, "number of GPUs a node needs for the training")
+	install := flag.Bool("install", false, "install the missing operators and components")
+	timeout := flag.Duration("timeout", bootstrap.DefaultTimeout, "time an installed step or the storage probe is waited for")
+	asJSON := flag.Bool("json", false, "print the results as JSON")
+	uninstall := flag.Bool("uninstall", false, "delete what the tool created instead")
+	dryRun := flag.Bool("dry-run", false, "with -uninstall, print what would be deleted")
+	flag.Parse()
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		log.Fatalf("Failed to load Kubernetes client config: %v", err)
+	}
+	b := &bootstrap.Bootstrap{
+		Client:         kubernetes.NewForConfigOrDie(config),
+		Dynamic:        dynamic.NewForConfigOrDie(config),
+		Namespace:      *namespace,
+		StorageClass:   *storageClass,
+		ResourcePrefix: prefix,
+		MinGPUsPerNode: *minGPUs,
+		Timeout:        *timeout,
+	}
+
+	if *uninstall {
+		actions, err := b.Uninstall(context.Background(), *dryRun)
+		for _, action := range actions {
+			fmt.Println(action)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	results := b.Run(context.Background(), b.Steps(), *install)
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		fmt.Print(bootstrap.RenderResults(results))
+	}
+	if !bootstrap.Ready(results) {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// ilab-operator runs the controller of the InstructLabRuns of a namespace, which submits their runs to the pipeline
+// server of the namespace and follows them into their status. In a pod of the cluster, it uses the service account of
+// the pod and its namespace, which needs a Data Science Pipelines application and the permissions to read and update
+// the InstructLabRuns and to read the object store secrets. The CRD is
+// config/crd/bases/ilab.opendatahub.io_instructlabruns.yaml.
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/api/v1alpha1"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/controllers"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/ilab"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+func main() {
+	kubeconfig := flag.String("kubeconfig", "", "kubeconfig to use, the default kubeconfig or the service account of the pod when empty")
+	pollInterval := flag.Duration("poll-interval", ilab.DefaultPollInterval, "time between two reads of a run not finished")
+	metricsAddress := flag.String("metrics-bind-address", "0", "address the metrics are served on, disabled with 0")
+	leaderElect := flag.Bool("leader-elect", false, "elect a leader among the replicas of the operator")
+	flag.Parse()
+
+	runs, err := ilab.NewClient(*kubeconfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = *kubeconfig
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		log.Fatalf("Failed to load the kubeconfig: %v", err)
+	}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		log.Fatal(err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		log.Fatal(err)
+	}
+	mgr, err := ctrl.NewManager(config, ctrl.Options{
+		Scheme:                  scheme,
+		Cache:                   cache.Options{DefaultNamespaces: map[string]cache.Config{runs.Namespace: {}}},
+		Metrics:                 metricsserver.Options{BindAddress: *metricsAddress},
+		LeaderElection:          *leaderElect,
+		LeaderElectionID:        "ilab-operator.ilab.opendatahub.io",
+		LeaderElectionNamespace: runs.Namespace,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create the manager: %v", err)
+	}
+	reconciler := &controllers.InstructLabRunReconciler{Client: mgr.GetClient(), Runs: runs, PollInterval: *pollInterval}
+	if err := reconciler.SetupWithManager(mgr); err != nil {
+		log.Fatalf("Failed to set up the controller: %v", err)
+	}
+
+	log.Printf("Reconciling the InstructLabRuns of namespace %s with the pipeline server %s", runs.Namespace, runs.Server.URL)
+	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+		log.Fatal(err)
+	}
+}
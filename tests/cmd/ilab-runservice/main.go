@@ -0,0 +1,58 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// ilab-runservice serves the REST API of pkg/runservice to submit, list and follow the ilab runs of a namespace. In a
+// pod of the cluster, it uses the service account of the pod and its namespace, which needs a Data Science Pipelines
+// application, the permissions to list its pods and the system:auth-delegator cluster role. The requests are
+// authenticated with a TokenReview of their bearer token, or of the token the OpenShift OAuth proxy forwards, and
+// authorized with the roles of the -roles file.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/ilab"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/runservice"
+)
+
+func main() {
+	listen := flag.String("listen", ":8080", "address to listen on")
+	kubeconfig := flag.String("kubeconfig", "", "kubeconfig to use, the default kubeconfig or the service account of the pod when empty")
+	pollInterval := flag.Duration("poll-interval", ilab.DefaultPollInterval, "time between two reads of a run while streaming its logs")
+	rolesFile := flag.String("roles", "", "YAML file binding the viewer, submitter and admin roles to users and groups")
+	noAuth := flag.Bool("insecure-no-auth", false, "serve without authentication, e.g. on a private cluster")
+	flag.Parse()
+	if *rolesFile == "" && !*noAuth {
+		log.Fatal("-roles is required unless -insecure-no-auth is set")
+	}
+
+	client, err := ilab.NewClient(*kubeconfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	client.PollInterval = *pollInterval
+
+	handler := runservice.NewHandler(client)
+	if !*noAuth {
+		roles, err := runservice.LoadRoles(*rolesFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		handler = runservice.NewAuthenticator(client.Kube, roles).Wrap(handler)
+	}
+
+	log.Printf("Serving the runs of %s on %s", client.Server.URL, *listen)
+	log.Fatal(http.ListenAndServe(*listen, handler))
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// log-shipper copies the container logs of the pipeline run pods of its namespace to a directory, a PVC mounted into
+// its pod, so they survive the deletion or eviction of the pods. The collected logs are served as a gzipped tarball
+// on /logs.tar.gz. The details of the shipping are logged while the level key of the -log-level-configmap ConfigMap is
+// debug, read every minute, so the verbosity can be raised without restarting the shipper.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/loglevel"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/logshipper"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func main() {
+	listen := flag.String("listen", ":8080", "address to listen on")
+	namespace := flag.String("namespace", "", "namespace of the pipeline runs")
+	dir := flag.String("dir", "/logs", "directory the logs are copied to")
+	selectors := flag.String("selectors", strings.Join(logshipper.DefaultSelectors, ";"), "label selectors of the pods, separated by ';'")
+	interval := flag.Duration("interval", 10*time.Second, "time between two listings of the pods")
+	logLevel := flag.String("log-level", "info", "log level until the ConfigMap sets one, info or debug")
+	logLevelConfigMap := flag.String("log-level-configmap", loglevel.DefaultConfigMap, "ConfigMap of the namespace setting the log level")
+	flag.Parse()
+	if *namespace == "" {
+		log.Fatal("-namespace must be set")
+	}
+	level, err := loglevel.ParseLevel(*logLevel)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		log.Fatalf("Failed to load the in-cluster config: %v", err)
+	}
+	client := kubernetes.NewForConfigOrDie(config)
+	logger := loglevel.New(level, log.Printf)
+	go logger.Watch(context.Background(), client, *namespace, *logLevelConfigMap, time.Minute)
+	shipper := &logshipper.Shipper{
+		Client:    client,
+		Namespace: *namespace,
+		Selectors: strings.Split(*selectors, ";"),
+		Dir:       *dir,
+		Interval:  *interval,
+		Logf:      log.Printf,
+		Debugf:    logger.Debugf,
+	}
+	go shipper.Run(context.Background())
+
+	http.HandleFunc("/logs.tar.gz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/gzip")
+		if err := logshipper.Archive(*dir, w); err != nil {
+			log.Printf("Failed to archive the logs: %v", err)
+		}
+	})
+	log.Printf("Shipping the logs of the pods %s in namespace %s to %s", *selectors, *namespace, *dir)
+	log.Fatal(http.ListenAndServe(*listen, nil))
+}
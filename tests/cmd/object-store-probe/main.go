@@ -0,0 +1,67 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// object-store-probe measures the upload and download throughput between its pod and the bucket of the object store
+// settings, read from the AWS_* environment variables of a data connection, and prints the result as JSON
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/objectprobe"
+)
+
+func main() {
+	size := flag.Int64("size", 256, "size of the probe object in MiB")
+	key := flag.String("key", fmt.Sprintf("object-store-probe/%d", time.Now().UnixNano()), "key of the probe object")
+	flag.Parse()
+
+	endpoint, bucket := os.Getenv("AWS_S3_ENDPOINT"), os.Getenv("AWS_S3_BUCKET")
+	if endpoint == "" || bucket == "" {
+		log.Fatal("AWS_S3_ENDPOINT and AWS_S3_BUCKET must be set")
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		log.Fatalf("Invalid object store endpoint %s: %v", endpoint, err)
+	}
+	client, err := minio.New(parsed.Host, &minio.Options{
+		Secure: parsed.Scheme != "http",
+		Region: os.Getenv("AWS_DEFAULT_REGION"),
+		Creds:  credentials.NewStaticV4(os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"), ""),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create the object store client: %v", err)
+	}
+
+	log.Printf("Probing bucket %s at %s with %d MiB", bucket, parsed.Host, *size)
+	result, err := objectprobe.Probe(context.Background(), objectprobe.S3Bucket{Client: client, Name: bucket}, *key, *size<<20)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(result); err != nil {
+		log.Fatal(err)
+	}
+}
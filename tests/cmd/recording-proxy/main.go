@@ -0,0 +1,51 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// recording-proxy forwards requests to a teacher or judge endpoint and prints a sample of the exchanges and the
+// token usage to stdout as JSON lines, so they can be collected from the pod logs
+package main
+
+import (
+	"crypto/tls"
+	"flag"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/recorder"
+)
+
+func main() {
+	listen := flag.String("listen", ":8080", "address to listen on")
+	target := flag.String("target", "", "scheme and host of the endpoint to forward to, e.g. https://judge.example.com")
+	sampleRate := flag.Float64("sample-rate", 0.1, "fraction of the exchanges to record")
+	maxBodyBytes := flag.Int("max-body-bytes", recorder.DefaultMaxBodyBytes, "number of bytes of a payload kept in a record")
+	insecure := flag.Bool("insecure-skip-verify", false, "skip the verification of the target certificate")
+	flag.Parse()
+
+	targetURL, err := url.Parse(*target)
+	if err != nil || targetURL.Host == "" {
+		log.Fatalf("invalid target '%s'", *target)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: *insecure}
+
+	proxy := recorder.NewProxy(targetURL, transport, os.Stdout)
+	proxy.SampleRate = *sampleRate
+	proxy.MaxBodyBytes = *maxBodyBytes
+
+	log.Printf("Recording %.2f of the exchanges with %s on %s", *sampleRate, targetURL.Host, *listen)
+	log.Fatal(http.ListenAndServe(*listen, proxy))
+}
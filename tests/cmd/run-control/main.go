@@ -0,0 +1,261 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// run-control pauses and resumes the training of a pipeline run, e.g. to free its GPUs for a while on a shared cluster
+//
+//	run-control -namespace <namespace> status <run-id>        shows whether the PyTorchJobs of the run are paused
+//	run-control -namespace <namespace> pause <run-id>         suspends the PyTorchJobs of the run, deleting their pods
+//	run-control -namespace <namespace> resume <run-id>        resumes the suspended PyTorchJobs of the run
+//	run-control -namespace <namespace> cancel <run-id>        terminates the run and deletes its PyTorchJobs and PVCs
+//	run-control -namespace <namespace> wait <run-id>          waits for both training phases of the run to succeed
+//	run-control -namespace <namespace> export-run <run-id>    exports what the run needs to be reproduced
+//	run-control -namespace <namespace> replay-run <manifest>  starts a new run from an exported manifest
+//
+// The training starts over when resumed, and the pause counts against the job timeout of the launcher task. A
+// canceled run is terminated on the pipeline server of PIPELINE_SERVER_URL with BEARER_TOKEN, as the e2e tests are
+// configured, and the logs of its pods and the cancellation record are saved under <ARTIFACTS_DIR>/<run-id>. wait
+// fails as soon as a PyTorchJob of the run fails, or after -timeout when set.
+//
+// export-run writes the manifest of the run, its pipeline version and parameters, pipeline root, image digests and
+// taxonomy commit, to <ARTIFACTS_DIR>/<run-id>/run-manifest.json. replay-run submits the manifest as a new run on the
+// pipeline server, refusing when the taxonomy branch moved since the export unless -force is set. With -wait, it waits
+// for the replay to finish and fails when its images resolved to other digests than the exported run.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/ilab"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/runcontrol"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/runmanifest"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/watcher"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func main() {
+	namespace := flag.String("namespace", "", "namespace of the pipeline run")
+	timeout := flag.Duration("timeout", 0, "time wait waits for, unlimited by default")
+	force := flag.Bool("force", false, "replay even when the taxonomy branch of the manifest moved")
+	waitReplay := flag.Bool("wait", false, "wait for the replay to finish and compare its images with the manifest")
+	flag.Parse()
+	if *namespace == "" || flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		log.Fatalf("Failed to load Kubernetes client config: %v", err)
+	}
+	client := kubernetes.NewForConfigOrDie(config)
+	dynamicClient := dynamic.NewForConfigOrDie(config)
+	ctx := context.Background()
+
+	runID := flag.Arg(1)
+	switch flag.Arg(0) {
+	case "cancel":
+		err = cancel(ctx, client, dynamicClient, *namespace, runID)
+	case "wait":
+		err = wait(ctx, client, config, *namespace, runID, *timeout)
+	case "export-run":
+		err = exportRun(ctx, client, *namespace, runID)
+	case "replay-run":
+		err = replayRun(ctx, client, *namespace, flag.Arg(1), *force, *waitReplay)
+	default:
+		var jobs []runcontrol.TrainingJob
+		jobs, err = runcontrol.RunTrainingJobs(ctx, client, dynamicClient, *namespace, runID)
+		if err != nil {
+			log.Fatal(err)
+		}
+		switch flag.Arg(0) {
+		case "status":
+			err = status(jobs)
+		case "pause":
+			err = setPaused(ctx, dynamicClient, *namespace, runID, jobs, true)
+		case "resume":
+			err = setPaused(ctx, dynamicClient, *namespace, runID, jobs, false)
+		default:
+			err = fmt.Errorf("unknown command '%s'", flag.Arg(0))
+		}
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func status(jobs []runcontrol.TrainingJob) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "JOB\tSUSPENDED\tSTATE\tCHANGED")
+	for _, job := range jobs {
+		fmt.Fprintf(w, "%s\t%t\t%s\t%s\n", job.Name, job.Suspended, job.State, job.Changed)
+	}
+	return w.Flush()
+}
+
+// setPaused pauses the jobs of the run not suspended yet, or resumes the suspended ones
+func setPaused(ctx context.Context, client dynamic.Interface, namespace, runID string, jobs []runcontrol.TrainingJob, pause bool) error {
+	action, apply := "resume", runcontrol.Resume
+	if pause {
+		action, apply = "pause", runcontrol.Pause
+	}
+	changed := 0
+	for _, job := range jobs {
+		if job.Suspended == pause {
+			continue
+		}
+		if err := apply(ctx, client, namespace, job.Name, time.Now()); err != nil {
+			return err
+		}
+		log.Printf("Applied %s to PyTorchJob %s", action, job.Name)
+		changed++
+	}
+	if changed == 0 {
+		return fmt.Errorf("run %s has no PyTorchJob to %s", runID, action)
+	}
+	return nil
+}
+
+// wait waits for the PyTorchJobs of both training phases of the run to succeed, in order
+func wait(ctx context.Context, client kubernetes.Interface, config *rest.Config, namespace, runID string, timeout time.Duration) error {
+	watchClient, err := watcher.NewClient(config)
+	if err != nil {
+		return fmt.Errorf("failed to create watch client: %w", err)
+	}
+	workflow, err := runcontrol.RunWorkflow(ctx, client, namespace, runID)
+	if err != nil {
+		return err
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	for _, name := range runcontrol.TrainingJobNames(workflow) {
+		log.Printf("Waiting for PyTorchJob %s...", name)
+		key := ctrlclient.ObjectKey{Namespace: namespace, Name: name}
+		if err := watcher.Until(ctx, watchClient, key, watcher.NewPyTorchJob(), watcher.PyTorchJobSucceeded); err != nil {
+			return err
+		}
+		log.Printf("PyTorchJob %s succeeded", name)
+	}
+	return nil
+}
+
+// pipelineServer returns the pipeline server of PIPELINE_SERVER_URL and BEARER_TOKEN
+func pipelineServer(command string) (runcontrol.PipelineServer, error) {
+	server := runcontrol.PipelineServer{URL: os.Getenv("PIPELINE_SERVER_URL"), BearerToken: os.Getenv("BEARER_TOKEN")}
+	if server.URL == "" || server.BearerToken == "" {
+		return server, fmt.Errorf("%s requires PIPELINE_SERVER_URL and BEARER_TOKEN", command)
+	}
+	return server, nil
+}
+
+// runDir returns the directory of ARTIFACTS_DIR the outputs of a run are saved to
+func runDir(runID string) string {
+	dir := os.Getenv("ARTIFACTS_DIR")
+	if dir == "" {
+		dir = "artifacts"
+	}
+	return filepath.Join(dir, runID)
+}
+
+// cancel terminates the run and cleans up after it, saving its logs and the cancellation record first
+func cancel(ctx context.Context, client kubernetes.Interface, dynamicClient dynamic.Interface, namespace, runID string) error {
+	server, err := pipelineServer("cancel")
+	if err != nil {
+		return err
+	}
+	dir := runDir(runID)
+
+	cancellation, err := runcontrol.Cancel(ctx, client, dynamicClient, server, namespace, runID, dir, time.Now())
+	log.Printf("Saved %d logs of run %s to %s", len(cancellation.Logs), runID, dir)
+	log.Printf("Deleted PyTorchJobs %v and PVCs %v", cancellation.DeletedJobs, cancellation.DeletedPVCs)
+	return err
+}
+
+// exportRun writes the manifest of the run to its directory of ARTIFACTS_DIR
+func exportRun(ctx context.Context, client kubernetes.Interface, namespace, runID string) error {
+	server, err := pipelineServer("export-run")
+	if err != nil {
+		return err
+	}
+	manifest, err := runmanifest.Export(ctx, &ilab.Client{Server: server, Kube: client, Namespace: namespace}, runID, time.Now())
+	if err != nil {
+		return err
+	}
+	dir := runDir(runID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, "run-manifest.json")
+	if err := runmanifest.Write(path, manifest); err != nil {
+		return err
+	}
+	log.Printf("Exported run %s with %d images and taxonomy commit %s to %s", runID, len(manifest.Images), manifest.Taxonomy.Commit, path)
+	return nil
+}
+
+// replayRun submits the manifest as a new run, and with wait compares the images of the replay with the manifest once
+// it finished
+func replayRun(ctx context.Context, client kubernetes.Interface, namespace, path string, force, watch bool) error {
+	server, err := pipelineServer("replay-run")
+	if err != nil {
+		return err
+	}
+	manifest, err := runmanifest.Read(path)
+	if err != nil {
+		return err
+	}
+	ilabClient := &ilab.Client{Server: server, Kube: client, Namespace: namespace}
+	runID, err := runmanifest.Replay(ctx, ilabClient, manifest, force)
+	if err != nil {
+		return err
+	}
+	log.Printf("Started run %s, replaying run %s", runID, manifest.RunID)
+	if !watch {
+		return nil
+	}
+	status, err := ilabClient.WatchRun(ctx, runID, func(status ilab.RunStatus) {
+		log.Printf("Run %s is %s", runID, status.State)
+	})
+	if err != nil {
+		return err
+	}
+	images, err := runmanifest.RunImages(ctx, ilabClient, runID)
+	if err != nil {
+		return err
+	}
+	problems := runmanifest.CheckImages(manifest, images)
+	for _, problem := range problems {
+		log.Print(problem)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("run %s did not run the images of run %s", runID, manifest.RunID)
+	}
+	if !status.Succeeded() {
+		return fmt.Errorf("run %s finished in state %s: %s", runID, status.State, status.Error)
+	}
+	return nil
+}
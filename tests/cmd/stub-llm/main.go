@@ -0,0 +1,45 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// stub-llm serves the fakellm OpenAI-compatible API, standing in for the teacher and judge model servers in mock runs
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/fakellm"
+)
+
+func main() {
+	var config fakellm.Config
+	listen := flag.String("listen", ":8080", "address to listen on")
+	flag.StringVar(&config.ModelName, "model-name", "stub", "name of the served model")
+	flag.Func("response", "canned completion, repeat for several responses (default \""+fakellm.DefaultResponse+"\")", func(value string) error {
+		config.Responses = append(config.Responses, value)
+		return nil
+	})
+	sdgRules := flag.Bool("sdg-rules", true, "answer the SDG prompt types with completions their parsers accept, the responses answer the other prompts")
+	flag.DurationVar(&config.Latency, "latency", 0, "delay of every completion")
+	flag.Float64Var(&config.ErrorRate, "error-rate", 0, "fraction of completions failed with the error status")
+	flag.IntVar(&config.ErrorStatus, "error-status", http.StatusInternalServerError, "status of the failed completions")
+	flag.StringVar(&config.APIKey, "api-key", "", "API key required as bearer token, none when empty")
+	flag.Parse()
+	if *sdgRules {
+		config.Rules = fakellm.DefaultRules
+	}
+
+	log.Printf("Serving %s on %s", config.ModelName, *listen)
+	log.Fatal(http.ListenAndServe(*listen, fakellm.NewServer(config)))
+}
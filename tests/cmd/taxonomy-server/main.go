@@ -0,0 +1,37 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// taxonomy-server serves the demo taxonomy as the git repository /taxonomy.git, cloned by the SDG task of the demo
+// scenario
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/demotaxonomy"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/gitserve"
+)
+
+func main() {
+	listen := flag.String("listen", ":8080", "address to listen on")
+	flag.Parse()
+
+	repo, err := demotaxonomy.Repository()
+	if err != nil {
+		log.Fatalf("Failed to commit the demo taxonomy: %v", err)
+	}
+	log.Printf("Serving the demo taxonomy at commit %s on %s", repo.Commit(), *listen)
+	log.Fatal(http.ListenAndServe(*listen, gitserve.Handler(map[string]*gitserve.Repository{"taxonomy": repo})))
+}
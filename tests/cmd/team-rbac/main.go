@@ -0,0 +1,60 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// team-rbac prints the RBAC manifest granting a team the permissions to run ilab workflows in a namespace
+//
+//	team-rbac -team <team> -namespace <namespace> role          a Role, ServiceAccount and RoleBinding
+//	team-rbac -team <team> -namespace <namespace> cluster-role  a ClusterRole shared by the namespaces of the teams
+//
+// The manifest is written to stdout, to be reviewed and applied with `oc apply -f -`.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/teamrbac"
+)
+
+func main() {
+	team := flag.String("team", "", "name of the team")
+	namespace := flag.String("namespace", "", "namespace the team runs its workflows in")
+	groups := flag.String("groups", "", "groups of the team members to bind along with its service account, separated by ','")
+	flag.Parse()
+	if *team == "" || *namespace == "" || flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	options := teamrbac.Options{Team: *team, Namespace: *namespace}
+	if *groups != "" {
+		options.Groups = strings.Split(*groups, ",")
+	}
+	switch flag.Arg(0) {
+	case "role":
+	case "cluster-role":
+		options.ClusterRole = true
+	default:
+		log.Fatalf("unknown command '%s'", flag.Arg(0))
+	}
+
+	manifest, err := teamrbac.Render(options)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err := os.Stdout.Write(manifest); err != nil {
+		log.Fatal(err)
+	}
+}
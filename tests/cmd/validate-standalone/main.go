@@ -0,0 +1,155 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// validate-standalone is the pre-merge gate of changes to the Python sources of the pipeline, run from the tests
+// directory on a small cluster such as kind or OpenShift Local. It checks, in order and failing at the first check
+// that fails:
+//
+//   - the parameter contract: the params of resources/pipeline_params.yaml, with the mock overrides, against the inputs
+//     of the compiled pipeline.yaml and the values a run accepts
+//   - the scenarios of tests/scenarios against their schema and the pipeline inputs
+//   - the mock run, TestPipelineRunMock, started with go test and configured by its environment variables, see
+//     pipeline/e2e/README.md
+//
+// The checks share -timeout, 20 minutes by default. -skip-mock runs the checks that do not need a cluster only.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/spf13/viper"
+)
+
+const (
+	pipelinePath    = "../pipeline.yaml"
+	resourcesDir    = "pipeline/e2e/resources/"
+	scenariosDir    = "scenarios"
+	mockTestPackage = "./pipeline/e2e/"
+)
+
+func main() {
+	timeout := flag.Duration("timeout", 20*time.Minute, "time all the checks must complete within")
+	local := flag.Bool("local", false, "run the mock run with the settings of local clusters, see MOCK_LOCAL_CLUSTER")
+	skipMock := flag.Bool("skip-mock", false, "skip the mock run, checking the parameters and scenarios only")
+	flag.Parse()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	start := time.Now()
+
+	definitions, err := TestUtil.LoadPipelineInputDefinitions(pipelinePath)
+	if err != nil {
+		log.Fatalf("Failed to load the compiled pipeline: %v", err)
+	}
+	problems, err := contractProblems(definitions, *local)
+	if err != nil {
+		log.Fatal(err)
+	}
+	problems = append(problems, scenarioProblems(definitions)...)
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			log.Print(problem)
+		}
+		log.Fatalf("%d problems found in %s", len(problems), time.Since(start).Round(time.Second))
+	}
+	log.Printf("Parameter contract and scenarios checked in %s", time.Since(start).Round(time.Second))
+	if *skipMock {
+		return
+	}
+
+	if err := mockRun(ctx, *local); err != nil {
+		if ctx.Err() != nil {
+			log.Fatalf("The checks did not complete within %s", *timeout)
+		}
+		log.Fatalf("Mock run failed: %v", err)
+	}
+	log.Printf("All checks passed in %s", time.Since(start).Round(time.Second))
+}
+
+// contractProblems checks the params of the runs against the pipeline inputs and the values a run accepts, as
+// TestPipelineParameterContract
+func contractProblems(definitions map[string]TestUtil.PipelineParameterSpec, local bool) ([]string, error) {
+	params, err := readParams("pipeline_params.yaml")
+	if err != nil {
+		return nil, err
+	}
+	files := []string{"mock_params.yaml"}
+	if local {
+		files = append(files, "mock_local_params.yaml")
+	}
+	problems := checkParams("pipeline_params.yaml", definitions, params)
+	for _, file := range files {
+		overrides, err := readParams(file)
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range overrides {
+			params[name] = value
+		}
+		problems = append(problems, checkParams(file, definitions, params)...)
+	}
+	return problems, nil
+}
+
+func readParams(file string) (map[string]interface{}, error) {
+	params := viper.New()
+	params.SetConfigFile(resourcesDir + file)
+	if err := params.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return params.AllSettings(), nil
+}
+
+func checkParams(file string, definitions map[string]TestUtil.PipelineParameterSpec, params map[string]interface{}) []string {
+	var problems []string
+	for _, problem := range append(TestUtil.CheckParameterContract(definitions, params), TestUtil.ValidatePipelineParams(params)...) {
+		problems = append(problems, fmt.Sprintf("Pipeline parameter contract violated by %s: %s", file, problem))
+	}
+	return problems
+}
+
+// scenarioProblems loads the scenarios, validating them against their schema, and checks their parameters are inputs
+// of the pipeline, as TestScenarios
+func scenarioProblems(definitions map[string]TestUtil.PipelineParameterSpec) []string {
+	scenarios, err := TestUtil.LoadScenarios(scenariosDir)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	var problems []string
+	for _, scenario := range scenarios {
+		if missing := TestUtil.MissingPipelineInputs(definitions, scenario.ParameterOverrides()); len(missing) > 0 {
+			problems = append(problems, fmt.Sprintf("Scenario %s sets parameters the pipeline does not expose: %v", scenario.Name, missing))
+		}
+	}
+	return problems
+}
+
+// mockRun runs TestPipelineRunMock with go test until it completes or the context expires
+func mockRun(ctx context.Context, local bool) error {
+	deadline, _ := ctx.Deadline()
+	test := exec.CommandContext(ctx, "go", "test", "-run", "^TestPipelineRunMock$", "-count=1", "-v",
+		"-timeout", time.Until(deadline).Round(time.Second).String(), mockTestPackage)
+	test.Stdout, test.Stderr = os.Stdout, os.Stderr
+	test.Env = append(os.Environ(), "ENABLE_ILAB_PIPELINE_TEST=true", "ENABLE_MOCK_TEST=true")
+	if local {
+		test.Env = append(test.Env, "MOCK_LOCAL_CLUSTER=true")
+	}
+	return test.Run()
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// verify-provenance verifies the signed provenance statement of a task directory downloaded from the artifacts of a
+// pipeline run, e.g. the upload-model-op directory holding the trained model, and every artifact it covers
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/provenance"
+)
+
+func main() {
+	keyPath := flag.String("key", "", "PEM public key the statement was signed with")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s -key <public key> <directory>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *keyPath == "" || flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(*keyPath)
+	if err != nil {
+		log.Fatalf("Failed to read the public key: %v", err)
+	}
+	key, err := provenance.ParsePublicKey(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+	statement, err := provenance.VerifyDir(flag.Arg(0), key)
+	if err != nil {
+		log.Fatalf("Verification of %s failed: %v", flag.Arg(0), err)
+	}
+	predicate := statement.Predicate
+	fmt.Printf("Verified %d artifacts of task %s of run %s, inputs %s, images %v\n",
+		len(statement.Subject), predicate.Task, predicate.RunID, predicate.ConfigDigest, predicate.Images)
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package controllers drives the InstructLabRuns of api/v1alpha1: the controller submits the pipeline run of every
+// InstructLabRun to the pipeline server, follows it into the status and the conditions of the resource, and
+// terminates it when the resource is deleted before the run finished.
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/api/v1alpha1"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/ilab"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Finalizer lets the controller terminate the run of a deleted InstructLabRun
+const Finalizer = "ilab.opendatahub.io/terminate-run"
+
+// DefaultPipeline is the display name of the pipeline of the InstructLabRuns which name none
+const DefaultPipeline = "instructlab"
+
+// ObjectStoreBucketKey is the key of the bucket in the data connection secret of an object store
+const ObjectStoreBucketKey = "AWS_S3_BUCKET"
+
+// Runs submits and follows the pipeline runs, *ilab.Client outside of the tests
+type Runs interface {
+	SubmitRun(ctx context.Context, spec ilab.RunSpec) (string, error)
+	GetRun(ctx context.Context, runID string) (ilab.RunStatus, error)
+	Terminate(ctx context.Context, runID string) error
+}
+
+// InstructLabRunReconciler reconciles the InstructLabRuns with their pipeline runs
+type InstructLabRunReconciler struct {
+	client.Client
+	Runs Runs
+	// PollInterval is the time between two reads of a run not finished, ilab.DefaultPollInterval when zero
+	PollInterval time.Duration
+}
+
+// SetupWithManager registers the reconciler with a manager
+func (r *InstructLabRunReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).For(&v1alpha1.InstructLabRun{}).Complete(r)
+}
+
+// Reconcile submits the run of an InstructLabRun, then reads its state until it finishes
+func (r *InstructLabRunReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	run := &v1alpha1.InstructLabRun{}
+	if err := r.Get(ctx, req.NamespacedName, run); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !run.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(run, Finalizer) {
+			return ctrl.Result{}, nil
+		}
+		if run.Status.RunID != "" && !(ilab.RunStatus{State: run.Status.State}).Finished() {
+			if err := r.Runs.Terminate(ctx, run.Status.RunID); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		controllerutil.RemoveFinalizer(run, Finalizer)
+		return ctrl.Result{}, r.Update(ctx, run)
+	}
+	if controllerutil.AddFinalizer(run, Finalizer) {
+		if err := r.Update(ctx, run); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if run.Status.RunID == "" {
+		return r.submit(ctx, run)
+	}
+	return r.follow(ctx, run)
+}
+
+// submit submits the run of the spec. A spec the pipeline cannot run is reported in the Submitted condition and not
+// retried, a failed submission is retried.
+func (r *InstructLabRunReconciler) submit(ctx context.Context, run *v1alpha1.InstructLabRun) (ctrl.Result, error) {
+	spec, err := r.runSpec(ctx, run)
+	if err != nil {
+		setCondition(run, v1alpha1.ConditionSubmitted, metav1.ConditionFalse, "InvalidSpec", err.Error())
+		return ctrl.Result{}, r.Status().Update(ctx, run)
+	}
+	runID, err := r.Runs.SubmitRun(ctx, spec)
+	if err != nil {
+		setCondition(run, v1alpha1.ConditionSubmitted, metav1.ConditionFalse, "SubmitFailed", err.Error())
+		if updateErr := r.Status().Update(ctx, run); updateErr != nil {
+			return ctrl.Result{}, updateErr
+		}
+		return ctrl.Result{}, err
+	}
+	run.Status.RunID = runID
+	run.Status.ObservedGeneration = run.Generation
+	setCondition(run, v1alpha1.ConditionSubmitted, metav1.ConditionTrue, "Submitted", fmt.Sprintf("Run %s of pipeline %s submitted", runID, spec.Pipeline))
+	setCondition(run, v1alpha1.ConditionRunning, metav1.ConditionTrue, "Submitted", "The run has not finished")
+	setCondition(run, v1alpha1.ConditionSucceeded, metav1.ConditionUnknown, "Running", "The run has not finished")
+	if err := r.Status().Update(ctx, run); err != nil {
+		// The run is submitted again on the next reconcile, terminate this one
+		if terminateErr := r.Runs.Terminate(ctx, runID); terminateErr != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to record run %s: %w, and to terminate it: %v", runID, err, terminateErr)
+		}
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: r.pollInterval()}, nil
+}
+
+// follow copies the state of the run into the status until the run finishes
+func (r *InstructLabRunReconciler) follow(ctx context.Context, run *v1alpha1.InstructLabRun) (ctrl.Result, error) {
+	if meta.IsStatusConditionPresentAndEqual(run.Status.Conditions, v1alpha1.ConditionRunning, metav1.ConditionFalse) {
+		return ctrl.Result{}, nil
+	}
+	status, err := r.Runs.GetRun(ctx, run.Status.RunID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	run.Status.State = status.State
+	run.Status.Tasks = nil
+	for _, task := range status.Tasks {
+		run.Status.Tasks = append(run.Status.Tasks, v1alpha1.TaskStatus{Name: task.Name, State: task.State})
+	}
+	result := ctrl.Result{RequeueAfter: r.pollInterval()}
+	switch {
+	case status.Succeeded():
+		setCondition(run, v1alpha1.ConditionRunning, metav1.ConditionFalse, status.State, "The run finished")
+		setCondition(run, v1alpha1.ConditionSucceeded, metav1.ConditionTrue, status.State, "The run succeeded")
+		result = ctrl.Result{}
+	case status.Finished():
+		message := fmt.Sprintf("The run finished in state %s", status.State)
+		if status.Error != "" {
+			message += ": " + status.Error
+		}
+		setCondition(run, v1alpha1.ConditionRunning, metav1.ConditionFalse, status.State, "The run finished")
+		setCondition(run, v1alpha1.ConditionSucceeded, metav1.ConditionFalse, status.State, message)
+		result = ctrl.Result{}
+	default:
+		setCondition(run, v1alpha1.ConditionRunning, metav1.ConditionTrue, status.State, "The run has not finished")
+	}
+	return result, r.Status().Update(ctx, run)
+}
+
+// runSpec returns the pipeline run of an InstructLabRun: its params, then the fields of the spec as the pipeline
+// parameters, and the pipeline root in the bucket of its object store
+func (r *InstructLabRunReconciler) runSpec(ctx context.Context, run *v1alpha1.InstructLabRun) (ilab.RunSpec, error) {
+	spec := ilab.RunSpec{Pipeline: run.Spec.Pipeline, DisplayName: run.Name}
+	if spec.Pipeline == "" {
+		spec.Pipeline = DefaultPipeline
+	}
+	params, err := RunParams(run.Spec)
+	if err != nil {
+		return spec, err
+	}
+	spec.Params = params
+
+	if store := run.Spec.ObjectStore; store != nil {
+		secret := &corev1.Secret{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: run.Namespace, Name: store.SecretName}, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				return spec, fmt.Errorf("object store secret %s not found", store.SecretName)
+			}
+			return spec, err
+		}
+		bucket := string(secret.Data[ObjectStoreBucketKey])
+		if bucket == "" {
+			return spec, fmt.Errorf("object store secret %s has no %s", store.SecretName, ObjectStoreBucketKey)
+		}
+		prefix := store.Prefix
+		if prefix == "" {
+			prefix = run.Name
+		}
+		spec.PipelineRoot = fmt.Sprintf("s3://%s/%s", bucket, prefix)
+	}
+	return spec, nil
+}
+
+// RunParams returns the pipeline parameters of a spec: its params, overridden by the fields set in the spec
+func RunParams(spec v1alpha1.InstructLabRunSpec) (map[string]interface{}, error) {
+	params := map[string]interface{}{}
+	if spec.Params != nil && len(spec.Params.Raw) > 0 {
+		if err := json.Unmarshal(spec.Params.Raw, &params); err != nil {
+			return nil, fmt.Errorf("params must be an object of pipeline parameters: %w", err)
+		}
+	}
+	setString := func(name, value string) {
+		if value != "" {
+			params[name] = value
+		}
+	}
+	setInt := func(name string, value int64) {
+		if value != 0 {
+			params[name] = value
+		}
+	}
+
+	sdg := spec.SDG
+	setString("sdg_repo_url", sdg.RepoURL)
+	setString("sdg_repo_branch", sdg.RepoBranch)
+	setInt("sdg_repo_pr", sdg.RepoPR)
+	setString("sdg_repo_secret", sdg.RepoSecret)
+	setString("sdg_base_model", sdg.BaseModel)
+	setString("sdg_pipeline", sdg.Pipeline)
+	setInt("sdg_scale_factor", sdg.ScaleFactor)
+	setInt("sdg_batch_size", sdg.BatchSize)
+	setInt("sdg_num_workers", sdg.NumWorkers)
+
+	training := spec.Training
+	setInt("train_num_workers", training.NumWorkers)
+	setInt("train_gpu_per_worker", training.GPUPerWorker)
+	setString("train_gpu_identifier", training.GPUIdentifier)
+	setString("train_cpu_per_worker", training.CPUPerWorker)
+	setString("train_memory_per_worker", training.MemoryPerWorker)
+	setInt("train_num_epochs_phase_1", training.NumEpochsPhase1)
+	setInt("train_num_epochs_phase_2", training.NumEpochsPhase2)
+	if training.Seed != nil {
+		params["train_seed"] = *training.Seed
+	}
+	if training.NodeSelectors != nil {
+		selectors := map[string]interface{}{}
+		for key, value := range training.NodeSelectors {
+			selectors[key] = value
+		}
+		params["train_node_selectors"] = selectors
+	}
+	if training.Tolerations != nil {
+		tolerations := []interface{}{}
+		for _, toleration := range training.Tolerations {
+			fields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&toleration)
+			if err != nil {
+				return nil, err
+			}
+			tolerations = append(tolerations, fields)
+		}
+		params["train_tolerations"] = tolerations
+	}
+
+	setString("sdg_teacher_secret", spec.TeacherSecret)
+	setString("eval_judge_secret", spec.JudgeSecret)
+	setString("k8s_storage_class_name", spec.StorageClass)
+	if params["sdg_repo_url"] == nil || params["sdg_repo_url"] == "" {
+		return nil, fmt.Errorf("sdg.repoURL is required")
+	}
+	return params, nil
+}
+
+func (r *InstructLabRunReconciler) pollInterval() time.Duration {
+	if r.PollInterval == 0 {
+		return ilab.DefaultPollInterval
+	}
+	return r.PollInterval
+}
+
+func setCondition(run *v1alpha1.InstructLabRun, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&run.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: run.Generation,
+		Reason:             reason,
+		Message:            message,
+	})
+}
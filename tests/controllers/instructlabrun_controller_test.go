@@ -0,0 +1,195 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/api/v1alpha1"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/ilab"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fakeRuns struct {
+	submitted  []ilab.RunSpec
+	states     map[string]ilab.RunStatus
+	terminated []string
+	submitErr  error
+}
+
+func (f *fakeRuns) SubmitRun(ctx context.Context, spec ilab.RunSpec) (string, error) {
+	if f.submitErr != nil {
+		return "", f.submitErr
+	}
+	f.submitted = append(f.submitted, spec)
+	return "run-1", nil
+}
+
+func (f *fakeRuns) GetRun(ctx context.Context, runID string) (ilab.RunStatus, error) {
+	return f.states[runID], nil
+}
+
+func (f *fakeRuns) Terminate(ctx context.Context, runID string) error {
+	f.terminated = append(f.terminated, runID)
+	return nil
+}
+
+func newReconciler(t *testing.T, runs *fakeRuns, objects ...client.Object) *InstructLabRunReconciler {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	kube := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).WithStatusSubresource(&v1alpha1.InstructLabRun{}).Build()
+	return &InstructLabRunReconciler{Client: kube, Runs: runs}
+}
+
+func reconcile(t *testing.T, r *InstructLabRunReconciler) (ctrl.Result, *v1alpha1.InstructLabRun) {
+	key := types.NamespacedName{Namespace: "ilab", Name: "granite"}
+	result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	run := &v1alpha1.InstructLabRun{}
+	if err := r.Get(context.Background(), key, run); err != nil {
+		require.True(t, client.IgnoreNotFound(err) == nil, err)
+		return result, nil
+	}
+	return result, run
+}
+
+func requireCondition(t *testing.T, run *v1alpha1.InstructLabRun, conditionType string, status metav1.ConditionStatus, reason string) {
+	condition := meta.FindStatusCondition(run.Status.Conditions, conditionType)
+	require.NotNil(t, condition, "no %s condition", conditionType)
+	require.Equal(t, status, condition.Status, conditionType)
+	require.Equal(t, reason, condition.Reason, conditionType)
+}
+
+func TestReconcile(t *testing.T) {
+	seed := int64(7)
+	run := &v1alpha1.InstructLabRun{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ilab", Name: "granite"},
+		Spec: v1alpha1.InstructLabRunSpec{
+			SDG:           v1alpha1.SDGConfig{RepoURL: "https://github.com/instructlab/taxonomy.git", ScaleFactor: 30},
+			Training:      v1alpha1.TrainingConfig{GPUPerWorker: 2, Seed: &seed, NodeSelectors: map[string]string{"gpu": "a100"}, Tolerations: []corev1.Toleration{{Key: "gpu", Operator: corev1.TolerationOpExists}}},
+			TeacherSecret: "teacher",
+			JudgeSecret:   "judge",
+			ObjectStore:   &v1alpha1.ObjectStoreRef{SecretName: "bucket"},
+			Params:        &runtime.RawExtension{Raw: []byte(`{"sdg_scale_factor":5,"output_model_name":"granite"}`)},
+		},
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ilab", Name: "bucket"}, Data: map[string][]byte{ObjectStoreBucketKey: []byte("artifacts")}}
+	runs := &fakeRuns{states: map[string]ilab.RunStatus{"run-1": {RunID: "run-1", State: "RUNNING", Tasks: []ilab.TaskStatus{{Name: "sdg-op", State: "RUNNING"}}}}}
+	r := newReconciler(t, runs, run, secret)
+
+	result, got := reconcile(t, r)
+	require.Equal(t, ilab.DefaultPollInterval, result.RequeueAfter)
+	require.Equal(t, []ilab.RunSpec{{
+		Pipeline:     DefaultPipeline,
+		DisplayName:  "granite",
+		PipelineRoot: "s3://artifacts/granite",
+		Params: map[string]interface{}{
+			"sdg_repo_url":         "https://github.com/instructlab/taxonomy.git",
+			"sdg_scale_factor":     int64(30),
+			"output_model_name":    "granite",
+			"train_gpu_per_worker": int64(2),
+			"train_seed":           int64(7),
+			"train_node_selectors": map[string]interface{}{"gpu": "a100"},
+			"train_tolerations":    []interface{}{map[string]interface{}{"key": "gpu", "operator": "Exists"}},
+			"sdg_teacher_secret":   "teacher",
+			"eval_judge_secret":    "judge",
+		},
+	}}, runs.submitted)
+	require.Equal(t, "run-1", got.Status.RunID)
+	require.Contains(t, got.Finalizers, Finalizer)
+	requireCondition(t, got, v1alpha1.ConditionSubmitted, metav1.ConditionTrue, "Submitted")
+	requireCondition(t, got, v1alpha1.ConditionSucceeded, metav1.ConditionUnknown, "Running")
+
+	_, got = reconcile(t, r)
+	require.Len(t, runs.submitted, 1, "a submitted run is not submitted again")
+	require.Equal(t, "RUNNING", got.Status.State)
+	require.Equal(t, []v1alpha1.TaskStatus{{Name: "sdg-op", State: "RUNNING"}}, got.Status.Tasks)
+	requireCondition(t, got, v1alpha1.ConditionRunning, metav1.ConditionTrue, "RUNNING")
+
+	runs.states["run-1"] = ilab.RunStatus{RunID: "run-1", State: "FAILED", Error: "sdg-op failed"}
+	result, got = reconcile(t, r)
+	require.Zero(t, result.RequeueAfter)
+	requireCondition(t, got, v1alpha1.ConditionRunning, metav1.ConditionFalse, "FAILED")
+	requireCondition(t, got, v1alpha1.ConditionSucceeded, metav1.ConditionFalse, "FAILED")
+	require.Equal(t, "The run finished in state FAILED: sdg-op failed", meta.FindStatusCondition(got.Status.Conditions, v1alpha1.ConditionSucceeded).Message)
+
+	require.NoError(t, r.Delete(context.Background(), got))
+	_, got = reconcile(t, r)
+	require.Nil(t, got, "the finalizer is removed")
+	require.Empty(t, runs.terminated, "a finished run is not terminated")
+}
+
+func TestReconcileDeleteRunning(t *testing.T) {
+	run := &v1alpha1.InstructLabRun{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ilab", Name: "granite"},
+		Spec:       v1alpha1.InstructLabRunSpec{SDG: v1alpha1.SDGConfig{RepoURL: "https://github.com/instructlab/taxonomy.git"}},
+	}
+	runs := &fakeRuns{states: map[string]ilab.RunStatus{"run-1": {RunID: "run-1", State: "RUNNING"}}}
+	r := newReconciler(t, runs, run)
+	reconcile(t, r)
+	_, got := reconcile(t, r)
+	require.Equal(t, "RUNNING", got.Status.State)
+
+	require.NoError(t, r.Delete(context.Background(), got))
+	_, got = reconcile(t, r)
+	require.Nil(t, got)
+	require.Equal(t, []string{"run-1"}, runs.terminated)
+}
+
+func TestReconcileInvalidSpec(t *testing.T) {
+	run := &v1alpha1.InstructLabRun{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ilab", Name: "granite"},
+		Spec: v1alpha1.InstructLabRunSpec{
+			SDG:         v1alpha1.SDGConfig{RepoURL: "https://github.com/instructlab/taxonomy.git"},
+			ObjectStore: &v1alpha1.ObjectStoreRef{SecretName: "missing"},
+		},
+	}
+	runs := &fakeRuns{}
+	r := newReconciler(t, runs, run)
+	result, got := reconcile(t, r)
+	require.Zero(t, result.RequeueAfter)
+	require.Empty(t, runs.submitted)
+	requireCondition(t, got, v1alpha1.ConditionSubmitted, metav1.ConditionFalse, "InvalidSpec")
+	require.Equal(t, "object store secret missing not found", meta.FindStatusCondition(got.Status.Conditions, v1alpha1.ConditionSubmitted).Message)
+
+	runs.submitErr = errors.New("pipeline server unavailable")
+	got.Spec.ObjectStore = nil
+	require.NoError(t, r.Update(context.Background(), got))
+	_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "ilab", Name: "granite"}})
+	require.EqualError(t, err, "pipeline server unavailable")
+	require.NoError(t, r.Get(context.Background(), client.ObjectKeyFromObject(got), got))
+	requireCondition(t, got, v1alpha1.ConditionSubmitted, metav1.ConditionFalse, "SubmitFailed")
+}
+
+func TestRunParams(t *testing.T) {
+	_, err := RunParams(v1alpha1.InstructLabRunSpec{})
+	require.EqualError(t, err, "sdg.repoURL is required")
+	params, err := RunParams(v1alpha1.InstructLabRunSpec{Params: &runtime.RawExtension{Raw: []byte(`{"sdg_repo_url":"https://example.com/taxonomy.git"}`)}})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"sdg_repo_url": "https://example.com/taxonomy.git"}, params)
+	_, err = RunParams(v1alpha1.InstructLabRunSpec{Params: &runtime.RawExtension{Raw: []byte(`[1]`)}})
+	require.ErrorContains(t, err, "params must be an object of pipeline parameters")
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+)
+
+// checkAPIBudget counts the API server requests issued by the service accounts of the run pods during the run and
+// fails when they exceed the budget of api_budget.yaml
+func checkAPIBudget(t *testing.T, runID string, start, end time.Time) {
+	t.Log("Counting the API requests of the run in the audit logs...")
+	client := TestUtil.NewKubeClient(t)
+	namespace := pipelineNamespace(t)
+	budget := TestUtil.LoadAPIBudget(t, "../e2e/resources/api_budget.yaml")
+
+	source := TestUtil.APIRequestSource{Users: map[string]bool{}, Pods: map[string]bool{}}
+	pods := append(TestUtil.GetRunPods(t, client, namespace, runID), TestUtil.GetTrainingPods(t, client, namespace, runID)...)
+	for _, pod := range pods {
+		source.Users[TestUtil.ServiceAccountUser(namespace, pod.Spec.ServiceAccountName)] = true
+		source.Pods[pod.Name] = true
+	}
+
+	stats := TestUtil.CollectAPIRequestStats(t, client, source, start, end)
+	t.Logf("The run issued %d API requests, peaking at %d per minute. Most frequent: %v", stats.Total, stats.PeakPerMinute(), TestUtil.TopRequests(stats, 5))
+	for _, failure := range TestUtil.CheckAPIBudget(stats, budget) {
+		t.Errorf("API budget exceeded: %s", failure)
+	}
+}
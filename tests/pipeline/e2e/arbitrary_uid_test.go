@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// defaultRestrictedUIDRange is far from the ranges OpenShift allocates, so UIDs baked into the images do not match it
+const defaultRestrictedUIDRange = "1999990000/10000"
+
+// TestPipelineRunRestrictedUIDRange runs the pipeline with the UIDs of the namespace assigned from an unusual range,
+// catching images writing to paths owned by root or by the UID they were built with
+func TestPipelineRunRestrictedUIDRange(t *testing.T) {
+	if os.Getenv("ENABLE_RESTRICTED_UID_RANGE_TEST") != "true" {
+		t.Skip("Skipping restricted UID range test. Set ENABLE_RESTRICTED_UID_RANGE_TEST=true to enable.")
+	}
+	config := loadPipelineTestConfig(t)
+	acquireGPULease(t)
+	namespace := pipelineNamespace(t)
+	client := TestUtil.NewKubeClient(t)
+
+	value := os.Getenv("RESTRICTED_UID_RANGE")
+	if value == "" {
+		value = defaultRestrictedUIDRange
+	}
+	uidRange, err := TestUtil.ParseUIDRange(value)
+	require.NoError(t, err, "Invalid RESTRICTED_UID_RANGE")
+	t.Cleanup(TestUtil.SetNamespaceUIDRange(t, client, namespace, uidRange))
+	t.Logf("Assigning the UIDs of namespace %s from %s", namespace, uidRange)
+
+	overrides := evalParameterOverrides(t)
+	prepareRuns(t, config, overrides)
+	run := startPipeline(t, config, overrides)
+	details, err := TestUtil.WaitForPipelineCompletionWithin(t, config.pipelineServerURL, run.runID, config.bearerToken, config.runTimeout)
+	require.NoError(t, err, "Pipeline run did not complete")
+
+	// Denied writes are reported whether or not they failed the run, some are retried or ignored by the tasks
+	runPods := TestUtil.GetRunPods(t, client, namespace, run.runID)
+	trainingPods, err := TestUtil.ListTrainingPods(client, namespace, runPods)
+	require.NoError(t, err, "Failed to list training pods")
+	denials, err := TestUtil.PodPermissionDenials(client, append(runPods, trainingPods...))
+	require.NoError(t, err, "Failed to read the logs of the run pods")
+	for container, lines := range denials {
+		t.Errorf("%s was denied writes under UID range %s:\n%s", container, uidRange, strings.Join(lines, "\n"))
+	}
+
+	rules := TestUtil.PolicyRules{ArbitraryUID: true}
+	for _, violation := range TestUtil.CheckRunPolicies(t, client, namespace, run.runID, rules) {
+		t.Errorf("Policy violation: %s", violation)
+	}
+	require.Equal(t, "SUCCEEDED", details.State, "Pipeline run failed under UID range %s: %s", uidRange, details.Error.Message)
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPipelineRunArm64 runs the pipeline with the arm64 images on arm64 GPU nodes, e.g. GH200 based clusters
+func TestPipelineRunArm64(t *testing.T) {
+	if os.Getenv("ENABLE_ARM64_TEST") != "true" {
+		t.Skip("Skipping arm64 pipeline test. Set ENABLE_ARM64_TEST=true to enable.")
+	}
+	config := loadPipelineTestConfig(t)
+	acquireGPULease(t)
+	namespace := pipelineNamespace(t)
+	client := TestUtil.NewKubeClient(t)
+
+	gpuNodes := TestUtil.GetGPUNodesByArch(t, client, TestUtil.DefaultGPUResource)
+	t.Logf("GPU nodes by architecture: %v", gpuNodes)
+	require.NotEmpty(t, gpuNodes["arm64"], "No arm64 GPU nodes found in the cluster")
+
+	archImages := TestUtil.LoadArchImages(t, "../e2e/resources/arch_images.yaml")
+	baseline, err := TestUtil.ImagesForArch(archImages, "amd64")
+	require.NoError(t, err)
+	images, err := TestUtil.ImagesForArch(archImages, "arm64")
+	require.NoError(t, err, "Set the arm64 images in resources/arch_images.yaml")
+
+	pipelineYAML, err := os.ReadFile("../../../pipeline.yaml")
+	require.NoError(t, err, "Failed to read the compiled pipeline")
+	pipelineYAML, err = TestUtil.PinPipelineNodeSelector(TestUtil.RewritePipelineImages(pipelineYAML, baseline, images), map[string]string{TestUtil.ArchLabel: "arm64"})
+	require.NoError(t, err, "Failed to pin the pipeline tasks to arm64 nodes")
+	name := fmt.Sprintf("%s-arm64-%d", config.pipelineDisplayName, time.Now().Unix())
+	pipelineID := uploadPipeline(t, config, name, pipelineYAML)
+
+	params := loadPipelineParams(t, map[string]interface{}{
+		"train_node_selectors": map[string]interface{}{TestUtil.ArchLabel: "arm64"},
+	})
+	runID, err := TestUtil.TriggerPipeline(t, config.pipelineServerURL, pipelineID, name, params, config.bearerToken)
+	require.NoError(t, err, "Failed to trigger pipeline")
+	t.Logf("Pipeline with name %s and run ID %s started....", name, runID)
+
+	err = TestUtil.WaitForPipelineSuccess(t, config.pipelineServerURL, runID, config.bearerToken)
+	require.NoError(t, err, "Pipeline did not complete successfully")
+
+	trainingPods := TestUtil.GetTrainingPods(t, client, namespace, runID)
+	require.NotEmpty(t, trainingPods, "No training pods found for the run")
+	pods := append(TestUtil.GetRunPods(t, client, namespace, runID), trainingPods...)
+	for _, problem := range TestUtil.CheckPodsArchitecture(t, client, pods, "arm64") {
+		t.Error(problem)
+	}
+}
+
+// applyArchGuard pins every workload of the run to nodes of the given architecture, so mixed x86 and arm clusters
+// never schedule a pod on nodes the images cannot run on. Training is pinned through the train_node_selectors
+// parameter, the task pods through a node selector on every executor of the returned copy of the pipeline.
+func applyArchGuard(t *testing.T, pipelineYAML []byte, params map[string]interface{}, arch string) []byte {
+	gpuNodes := TestUtil.GetGPUNodesByArch(t, TestUtil.NewKubeClient(t), TestUtil.DefaultGPUResource)
+	require.NotEmpty(t, gpuNodes[arch], "No %s GPU nodes found in the cluster, GPU nodes by architecture: %v", arch, gpuNodes)
+	if len(gpuNodes) > 1 {
+		t.Logf("Heterogeneous cluster with GPU nodes by architecture %v, selecting %s nodes", gpuNodes, arch)
+	}
+	params["train_node_selectors"] = TestUtil.WithArchNodeSelector(params["train_node_selectors"], arch)
+
+	pinned, err := TestUtil.PinPipelineNodeSelector(pipelineYAML, map[string]string{TestUtil.ArchLabel: arch})
+	require.NoError(t, err, "Failed to pin the pipeline tasks to %s nodes", arch)
+	return pinned
+}
+
+// checkArchGuard verifies no pod of the run, including the training pods, landed on a node of another architecture
+func checkArchGuard(t *testing.T, runID string, arch string) {
+	client := TestUtil.NewKubeClient(t)
+	namespace := pipelineNamespace(t)
+
+	pods := append(TestUtil.GetRunPods(t, client, namespace, runID), TestUtil.GetTrainingPods(t, client, namespace, runID)...)
+	for _, problem := range TestUtil.CheckPodsArchitecture(t, client, pods, arch) {
+		t.Errorf("Incompatible architecture: %s", problem)
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/provenance"
+	"github.com/stretchr/testify/require"
+)
+
+// signRunArtifacts stores a signed provenance statement next to the artifacts of every task of the run, with the key
+// of ARTIFACT_SIGNING_KEY, and verifies them back. The statements are also written to provenance.json in the
+// artifacts directory.
+func signRunArtifacts(t *testing.T, run pipelineRun) {
+	keyPath := os.Getenv("ARTIFACT_SIGNING_KEY")
+	require.NotEmpty(t, keyPath, "ARTIFACT_SIGNING_KEY environment variable must be set")
+	data, err := os.ReadFile(keyPath)
+	require.NoError(t, err, "Failed to read the signing key")
+	key, err := provenance.ParsePrivateKey(data)
+	require.NoError(t, err, "Invalid signing key")
+
+	store, err := TestUtil.NewArtifactStoreFromEnv()
+	require.NoError(t, err, "Failed to create artifact store")
+	tasks := TestUtil.GetRunTaskPods(t, TestUtil.NewKubeClient(t), pipelineNamespace(t), run.runID)
+	predicate := provenance.Predicate{Inputs: run.params, SignedAt: time.Now().UTC()}
+
+	t.Logf("Signing the artifacts of run %s...", run.runID)
+	statements, err := TestUtil.SignRunArtifacts(context.Background(), store, run.runPrefix, run.runID, predicate, TestUtil.TaskImages(tasks), key)
+	require.NoError(t, err, "Failed to sign the run artifacts")
+	verified, err := TestUtil.VerifyRunArtifacts(context.Background(), store, run.runPrefix, run.runID, key.Public())
+	require.NoError(t, err, "The signed run artifacts do not verify")
+	for prefix, statement := range verified {
+		t.Logf("Signed %d artifacts of %s with inputs %s", len(statement.Subject), prefix, statement.Predicate.ConfigDigest)
+	}
+
+	report, err := json.MarshalIndent(statements, "", "  ")
+	require.NoError(t, err)
+	path := TestUtil.WriteArtifact(t, "provenance.json", report)
+	t.Logf("Provenance statements written to %s", path)
+}
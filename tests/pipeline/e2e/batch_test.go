@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPipelineBatchRuns runs the pipeline sequentially for every base model of batch_runs.yaml and writes the
+// aggregated results into the artifacts directory
+func TestPipelineBatchRuns(t *testing.T) {
+	if os.Getenv("ENABLE_BATCH_TEST") != "true" {
+		t.Skip("Skipping batch runs. Set ENABLE_BATCH_TEST=true to enable.")
+	}
+	config := loadPipelineTestConfig(t)
+	acquireGPULease(t)
+
+	path := os.Getenv("BATCH_RUNS_FILE")
+	if path == "" {
+		path = "../e2e/resources/batch_runs.yaml"
+	}
+	runs := TestUtil.LoadBatchRuns(t, path)
+	require.NotEmpty(t, runs, "No runs found in %s", path)
+
+	pipelineID, err := TestUtil.RetrievePipelineId(t, config.pipelineServerURL, config.pipelineDisplayName, config.bearerToken)
+	require.NoError(t, err, "Failed to retrieve pipeline ID")
+
+	var results []TestUtil.BatchResult
+	for _, run := range runs {
+		name := fmt.Sprintf("%s-%s-%d", config.pipelineDisplayName, run.Name, time.Now().Unix())
+		t.Logf("Running %s with base model %v", run.Name, run.Params["sdg_base_model"])
+
+		result := TestUtil.BatchResult{Run: run}
+		start := time.Now()
+		result.RunID, err = TestUtil.TriggerPipeline(t, config.pipelineServerURL, pipelineID, name, loadPipelineParams(t, run.Params), config.bearerToken)
+		if err == nil {
+			err = TestUtil.WaitForPipelineSuccess(t, config.pipelineServerURL, result.RunID, config.bearerToken)
+		}
+		result.Duration = time.Since(start)
+		result.Err = err
+		results = append(results, result)
+
+		if err != nil {
+			t.Errorf("Run %s failed: %v", run.Name, err)
+		}
+	}
+
+	report := TestUtil.WriteArtifact(t, "batch-runs.md", []byte(TestUtil.RenderBatchReport(results)))
+	t.Logf("Batch report written to %s", report)
+}
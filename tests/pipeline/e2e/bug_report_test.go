@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"path/filepath"
+	"testing"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// writeBugReport classifies the failure of a run by the rules of resources/failure_classes.yaml, from the failed
+// containers of its task pods and training pods, and writes the bug report bundle of a product failure into
+// bug-report-<run ID> in the artifacts directory: summary.md to paste into the issue, and the logs and resource YAML
+// to attach. Environment failures and failures no rule matches are only logged.
+func writeBugReport(t *testing.T, run pipelineRun) {
+	client := TestUtil.NewKubeClient(t)
+	namespace := pipelineNamespace(t)
+	tasks, err := TestUtil.ListRunTaskPods(client, namespace, run.runID)
+	if err != nil {
+		t.Logf("WARNING: no bug report for run %s: %v", run.runID, err)
+		return
+	}
+	var pods []corev1.Pod
+	for _, task := range tasks {
+		pods = append(pods, task.Pod)
+	}
+	trainingPods, err := TestUtil.ListTrainingPods(client, namespace, pods)
+	if err != nil {
+		t.Logf("WARNING: no bug report for run %s: %v", run.runID, err)
+		return
+	}
+
+	failures := TestUtil.FailedContainers(append(pods, trainingPods...))
+	if len(failures) == 0 {
+		t.Logf("Run %s has no failed container, no bug report written", run.runID)
+		return
+	}
+	TestUtil.ReadFailureLogs(client, failures)
+	classification, ok := TestUtil.ClassifyRunFailure(TestUtil.LoadFailureRules(t, "../e2e/resources/failure_classes.yaml"), failures)
+	if !ok {
+		t.Logf("The failure of run %s matches no rule of failure_classes.yaml, no bug report written", run.runID)
+		return
+	}
+	t.Logf("The failure of run %s is a %s failure (%s) of pod %s: %s", run.runID, classification.Rule.Class, classification.Rule.Name,
+		classification.Failure.Pod.Name, classification.Evidence)
+	if classification.Rule.Class != TestUtil.FailureClassProduct {
+		return
+	}
+
+	settings := TestUtil.ResolveProductSettings(t, client)
+	report := TestUtil.CollectBugReport(client, TestUtil.NewDynamicClient(t), settings, namespace, run.runID, tasks, trainingPods, classification, failures, run.params)
+	dir := filepath.Join(TestUtil.ArtifactsDir(t), "bug-report-"+run.runID)
+	require.NoError(t, TestUtil.WriteBugReport(dir, report), "Failed to write the bug report of run %s", run.runID)
+	t.Logf("Bug report of run %s written to %s, paste summary.md into the issue and attach the logs and resources", run.runID, dir)
+}
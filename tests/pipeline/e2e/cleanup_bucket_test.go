@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCleanupBucket deletes the run prefixes older than the retention window from the bucket, listing them only
+// unless BUCKET_CLEANUP_DRY_RUN=false
+func TestCleanupBucket(t *testing.T) {
+	if os.Getenv("ENABLE_BUCKET_CLEANUP") != "true" {
+		t.Skip("Skipping bucket cleanup. Set ENABLE_BUCKET_CLEANUP=true to enable.")
+	}
+
+	retention := 7 * 24 * time.Hour
+	if value := os.Getenv("BUCKET_RETENTION"); value != "" {
+		var err error
+		retention, err = time.ParseDuration(value)
+		require.NoError(t, err, "BUCKET_RETENTION must be a duration, e.g. 168h")
+	}
+	dryRun := os.Getenv("BUCKET_CLEANUP_DRY_RUN") != "false"
+
+	store, err := TestUtil.NewArtifactStoreFromEnv()
+	require.NoError(t, err, "Failed to create artifact store")
+
+	prefixes, err := TestUtil.ListRunPrefixes(store)
+	require.NoError(t, err, "Failed to list run prefixes")
+
+	expired := TestUtil.ExpiredRunPrefixes(prefixes, time.Now(), retention)
+	t.Logf("%d of %d run prefixes are older than %s", len(expired), len(prefixes), retention)
+
+	var reclaimed int64
+	for _, prefix := range expired {
+		reclaimed += prefix.Size
+		if dryRun {
+			t.Logf("Would delete %s (%d objects, %d bytes)", prefix.Prefix, prefix.Objects, prefix.Size)
+			continue
+		}
+		t.Logf("Deleting %s (%d objects, %d bytes)", prefix.Prefix, prefix.Objects, prefix.Size)
+		require.NoError(t, TestUtil.DeleteRunPrefix(store, prefix.Prefix), "Failed to delete run prefix")
+	}
+
+	if dryRun {
+		t.Logf("Dry run, %d bytes would be reclaimed. Set BUCKET_CLEANUP_DRY_RUN=false to delete.", reclaimed)
+	} else {
+		t.Logf("%d bytes reclaimed", reclaimed)
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"testing"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPipelineParameterContract fails when the parameters the suite passes to a run drift from the inputs of the
+// compiled pipeline or hold values a run would fail on, so renamed or retyped pipeline arguments and malformed values
+// are caught without a cluster
+func TestPipelineParameterContract(t *testing.T) {
+	definitions, err := TestUtil.LoadPipelineInputDefinitions("../../../pipeline.yaml")
+	require.NoError(t, err, "Failed to load the compiled pipeline")
+
+	params := viper.New()
+	params.SetConfigFile("../e2e/resources/pipeline_params.yaml")
+	require.NoError(t, params.ReadInConfig(), "Error loading pipeline parameters")
+
+	for _, problem := range pipelineParamProblems(definitions, params.AllSettings()) {
+		t.Errorf("Pipeline parameter contract violated: %s", problem)
+	}
+
+	// Mock runs replace some of the parameters
+	mockParams := params.AllSettings()
+	for name, value := range loadMockOverrides(t) {
+		mockParams[name] = value
+	}
+	for _, problem := range pipelineParamProblems(definitions, mockParams) {
+		t.Errorf("Pipeline parameter contract violated by mock_params.yaml: %s", problem)
+	}
+	for name, value := range loadMockLocalOverrides(t) {
+		mockParams[name] = value
+	}
+	for _, problem := range pipelineParamProblems(definitions, mockParams) {
+		t.Errorf("Pipeline parameter contract violated by mock_local_params.yaml: %s", problem)
+	}
+
+	// So do the runs of the batch mode
+	for _, run := range TestUtil.LoadBatchRuns(t, "../e2e/resources/batch_runs.yaml") {
+		batchParams := params.AllSettings()
+		for name, value := range run.Params {
+			batchParams[name] = value
+		}
+		for _, problem := range pipelineParamProblems(definitions, batchParams) {
+			t.Errorf("Pipeline parameter contract violated by batch run %s: %s", run.Name, problem)
+		}
+	}
+}
+
+// pipelineParamProblems checks the parameters against the pipeline inputs and the values a run accepts
+func pipelineParamProblems(definitions map[string]TestUtil.PipelineParameterSpec, params map[string]interface{}) []string {
+	return append(TestUtil.CheckParameterContract(definitions, params), TestUtil.ValidatePipelineParams(params)...)
+}
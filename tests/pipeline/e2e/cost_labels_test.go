@@ -0,0 +1,52 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"testing"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// applyCostLabels labels the namespace with the cost labels of cost_labels.yaml until the end of the test, and
+// returns a copy of the pipeline setting them on every task pod
+func applyCostLabels(t *testing.T, pipelineYAML []byte) []byte {
+	costLabels := TestUtil.LoadCostLabels(t, "../e2e/resources/cost_labels.yaml")
+	namespace := pipelineNamespace(t)
+	t.Cleanup(TestUtil.LabelNamespace(t, TestUtil.NewKubeClient(t), namespace, costLabels))
+	t.Logf("Labeled namespace %s for cost attribution with %v", namespace, costLabels.Labels)
+
+	labeled, err := TestUtil.LabelPipelinePods(pipelineYAML, costLabels)
+	require.NoError(t, err, "Failed to set the cost labels on the pipeline tasks")
+	return labeled
+}
+
+// checkCostLabels verifies the cost labels reached every task pod of the run. The training pods are created by the
+// launcher task, which does not propagate them, so their GPU hours are attributed through the namespace labels.
+func checkCostLabels(t *testing.T, runID string) {
+	costLabels := TestUtil.LoadCostLabels(t, "../e2e/resources/cost_labels.yaml")
+	client := TestUtil.NewKubeClient(t)
+	namespace := pipelineNamespace(t)
+
+	for _, problem := range TestUtil.CheckCostLabels(TestUtil.GetRunPods(t, client, namespace, runID), costLabels) {
+		t.Errorf("Cost labels: %s", problem)
+	}
+	if problems := TestUtil.CheckCostLabels(TestUtil.GetTrainingPods(t, client, namespace, runID), costLabels); len(problems) > 0 {
+		t.Logf("WARNING: %d cost labels are missing on the training pods, attributed through namespace %s only", len(problems), namespace)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/demotaxonomy"
+	"github.com/stretchr/testify/require"
+)
+
+const taxonomyServerName = "demo-taxonomy"
+
+// serveDemoTaxonomy serves the demo taxonomy from the namespace for the run to clone, removed at the end of the test
+func serveDemoTaxonomy(t *testing.T, overrides map[string]interface{}) {
+	image := os.Getenv("TAXONOMY_SERVER_IMAGE")
+	require.NotEmpty(t, image, "TAXONOMY_SERVER_IMAGE environment variable must be set")
+	client := TestUtil.NewKubeClient(t)
+	namespace := pipelineNamespace(t)
+
+	t.Log("Serving the demo taxonomy...")
+	repoURL := TestUtil.DeployTaxonomyServer(t, client, namespace, taxonomyServerName, image, 5*time.Minute)
+	t.Cleanup(func() { TestUtil.DeleteTaxonomyServer(t, client, namespace, taxonomyServerName) })
+	overrides["sdg_repo_url"] = repoURL
+	overrides["sdg_repo_branch"] = ""
+	overrides["sdg_repo_pr"] = 0
+	t.Logf("Demo taxonomy served at %s", repoURL)
+}
+
+// printDemoSummary logs the guided summary of the demo run and writes it to demo-summary.md in the artifacts directory
+func printDemoSummary(t *testing.T, run pipelineRun) {
+	leaves, err := demotaxonomy.Leaves()
+	require.NoError(t, err, "Failed to read the demo taxonomy")
+	repo, err := demotaxonomy.Repository()
+	require.NoError(t, err, "Failed to commit the demo taxonomy")
+	tasks := TestUtil.GetRunTaskPods(t, TestUtil.NewKubeClient(t), pipelineNamespace(t), run.runID)
+
+	summary := TestUtil.RenderDemoSummary(TestUtil.DemoSummary{
+		RunID:    run.runID,
+		RepoURL:  run.params["sdg_repo_url"].(string),
+		Commit:   repo.Commit(),
+		Leaves:   leaves,
+		Duration: time.Since(run.start),
+		Phases:   TestUtil.PhaseDurations(tasks),
+		Scores:   scenarioScores(t, run),
+	})
+	path := TestUtil.WriteArtifact(t, "demo-summary.md", []byte(summary))
+	t.Logf("Demo summary, written to %s:\n\n%s", path, summary)
+}
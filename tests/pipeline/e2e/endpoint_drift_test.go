@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// modelServerSecretParams maps the model server secret parameters of the pipeline to the role of the model server
+var modelServerSecretParams = map[string]string{
+	"sdg_teacher_secret": "teacher",
+	"eval_judge_secret":  "judge",
+}
+
+// modelServerSecretNames returns the model server secrets of a run by parameter, the defaults of the compiled
+// pipeline apply to the parameters missing from params
+func modelServerSecretNames(t *testing.T, params map[string]interface{}) map[string]string {
+	defs, err := TestUtil.LoadPipelineInputDefinitions("../../../pipeline.yaml")
+	require.NoError(t, err, "Failed to load the compiled pipeline")
+
+	names := map[string]string{}
+	for param := range modelServerSecretParams {
+		name, ok := params[param].(string)
+		if !ok {
+			name = fmt.Sprint(defs[param].DefaultValue)
+		}
+		names[param] = name
+	}
+	return names
+}
+
+// watchEndpointDrift re-validates the teacher and judge endpoints of the run whenever it enters a new phase, failing
+// the test with the reason as soon as an endpoint stops resolving or authenticating
+func watchEndpointDrift(t *testing.T, runID string, params map[string]interface{}) (stop func()) {
+	secrets := map[string]string{}
+	for param, name := range modelServerSecretNames(t, params) {
+		secrets[modelServerSecretParams[param]] = name
+	}
+	return TestUtil.WatchEndpointDrift(TestUtil.NewKubeClient(t), pipelineNamespace(t), runID, secrets, time.Minute, func(phase, role string, err error) {
+		var endpointErr *TestUtil.EndpointError
+		if errors.As(err, &endpointErr) && endpointErr.Reason == TestUtil.EndpointClusterLocal {
+			t.Logf("Skipping the %s endpoint in phase %s: %s", role, phase, endpointErr.Message)
+			return
+		}
+		if err != nil {
+			t.Errorf("The %s endpoint drifted from its secret %s in phase %s: %v", role, secrets[role], phase, err)
+			return
+		}
+		t.Logf("The %s endpoint is valid in phase %s", role, phase)
+	})
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// runHistoryPath returns the run history file, RUN_HISTORY_FILE or run-history.jsonl in the artifacts directory
+func runHistoryPath(t *testing.T) string {
+	if path := os.Getenv("RUN_HISTORY_FILE"); path != "" {
+		return path
+	}
+	return filepath.Join(TestUtil.ArtifactsDir(t), "run-history.jsonl")
+}
+
+// watchETA logs the expected completion of every phase from the run history at the start of the run, and warns about
+// the phases exceeding ETA_OVERRUN_FACTOR times their historical median
+func watchETA(t *testing.T, run pipelineRun) func() {
+	factor := TestUtil.DefaultOverrunFactor
+	if value := os.Getenv("ETA_OVERRUN_FACTOR"); value != "" {
+		var err error
+		factor, err = strconv.ParseFloat(value, 64)
+		require.NoError(t, err, "ETA_OVERRUN_FACTOR must be a number")
+	}
+	history, err := TestUtil.LoadRunHistory(runHistoryPath(t))
+	require.NoError(t, err, "Failed to load the run history")
+	if len(history) == 0 {
+		t.Logf("No run history in %s yet, the phases of run %s are not estimated", runHistoryPath(t), run.runID)
+		return func() {}
+	}
+
+	medians := TestUtil.PhaseMedians(history)
+	for _, eta := range TestUtil.PhaseETAs(medians, run.start) {
+		t.Logf("Phase %s expected to take %s, complete by %s", eta.Phase, eta.Expected.Round(time.Minute), eta.ETA.Format(time.Kitchen))
+	}
+	return TestUtil.WatchPhaseOverruns(TestUtil.NewKubeClient(t), pipelineNamespace(t), run.runID, medians, factor, time.Minute, run.log.Debugf, func(overrun TestUtil.PhaseOverrun, err error) {
+		if err != nil {
+			t.Logf("Failed to check the phase durations: %v", err)
+			return
+		}
+		t.Logf("WARNING: pipeline run %s: %s", run.runID, overrun)
+	})
+}
+
+// recordRunHistory adds the phase durations of a successful run to the run history
+func recordRunHistory(t *testing.T, run pipelineRun) {
+	tasks := TestUtil.GetRunTaskPods(t, TestUtil.NewKubeClient(t), pipelineNamespace(t), run.runID)
+	entry := TestUtil.RunHistoryEntry{RunID: run.runID, Start: run.start, Phases: TestUtil.PhaseDurations(tasks), Params: TestUtil.NumericParams(run.params)}
+	err := TestUtil.AppendRunHistory(runHistoryPath(t), entry)
+	require.NoError(t, err, "Failed to record the run history")
+	t.Logf("Phase durations of run %s recorded in %s", run.runID, runHistoryPath(t))
+}
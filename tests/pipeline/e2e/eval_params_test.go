@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// evalParameterOverrides maps the eval tuning environment variables onto the MT Bench and final eval pipeline parameters
+func evalParameterOverrides(t *testing.T) map[string]interface{} {
+	overrides := map[string]interface{}{}
+
+	if batchSize := os.Getenv("EVAL_BATCH_SIZE"); batchSize != "" {
+		overrides["final_eval_batch_size"] = batchSize
+	}
+	if maxWorkers := os.Getenv("EVAL_MAX_WORKERS"); maxWorkers != "" {
+		overrides["mt_bench_max_workers"] = maxWorkers
+		overrides["final_eval_max_workers"] = maxWorkers
+	}
+	if merge := os.Getenv("EVAL_MERGE_SYSTEM_USER_MESSAGE"); merge != "" {
+		value, err := strconv.ParseBool(merge)
+		require.NoError(t, err, "EVAL_MERGE_SYSTEM_USER_MESSAGE must be true or false")
+		overrides["mt_bench_merge_system_user_message"] = value
+		overrides["final_eval_merge_system_user_message"] = value
+	}
+	return overrides
+}
+
+// checkEvalParameters verifies the eval task pods of the run received the eval parameters the run was started with
+func checkEvalParameters(t *testing.T, run pipelineRun) {
+	t.Log("Checking eval parameters of the eval tasks...")
+	tasks := TestUtil.GetRunTaskPods(t, TestUtil.NewKubeClient(t), pipelineNamespace(t), run.runID)
+
+	err := TestUtil.CheckTaskParameters(tasks, "run_mt_bench_op", map[string]interface{}{
+		"max_workers":               run.params["mt_bench_max_workers"],
+		"merge_system_user_message": run.params["mt_bench_merge_system_user_message"],
+	})
+	require.NoError(t, err, "MT Bench task parameters do not match the run configuration")
+
+	err = TestUtil.CheckTaskParameters(tasks, "run_final_eval_op", map[string]interface{}{
+		"batch_size":                run.params["final_eval_batch_size"],
+		"max_workers":               run.params["final_eval_max_workers"],
+		"merge_system_user_message": run.params["final_eval_merge_system_user_message"],
+	})
+	require.NoError(t, err, "Final eval task parameters do not match the run configuration")
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPipelineRunEviction evicts a task pod in the middle of a run, as a node drain does. The pipeline sets no retry
+// policy, so the run must fail with the evicted task, and a rerun must succeed reusing the cached tasks.
+func TestPipelineRunEviction(t *testing.T) {
+	if os.Getenv("ENABLE_EVICTION_TEST") != "true" {
+		t.Skip("Skipping eviction test. Set ENABLE_EVICTION_TEST=true to enable.")
+	}
+	config := loadPipelineTestConfig(t)
+	acquireGPULease(t)
+	namespace := pipelineNamespace(t)
+	client := TestUtil.NewKubeClient(t)
+
+	function := os.Getenv("EVICTION_TASK")
+	if function == "" {
+		function = "sdg_op"
+	}
+
+	pipelineID, err := TestUtil.RetrievePipelineId(t, config.pipelineServerURL, config.pipelineDisplayName, config.bearerToken)
+	require.NoError(t, err, "Failed to retrieve pipeline ID")
+	params := loadPipelineParams(t, evalParameterOverrides(t))
+	name := fmt.Sprintf("%s-eviction-%d", config.pipelineDisplayName, time.Now().Unix())
+	runID, err := TestUtil.TriggerPipeline(t, config.pipelineServerURL, pipelineID, name, params, config.bearerToken)
+	require.NoError(t, err, "Failed to trigger pipeline")
+	t.Logf("Pipeline with name %s and run ID %s started....", name, runID)
+
+	evicted, err := TestUtil.WaitForRunningTask(t, client, namespace, runID, function, 2*time.Hour)
+	require.NoError(t, err, "Task to evict did not start")
+	t.Logf("Evicting %s pod %s from node %s...", function, evicted.Pod.Name, evicted.Pod.Spec.NodeName)
+	TestUtil.EvictPod(t, client, evicted.Pod)
+
+	details, err := TestUtil.WaitForPipelineCompletion(t, config.pipelineServerURL, runID, config.bearerToken)
+	require.NoError(t, err, "Pipeline did not complete after the eviction of %s", evicted.Pod.Name)
+	require.NoError(t, TestUtil.CheckEvictionFailure(details, evicted), "Pipeline did not fail with the evicted task")
+
+	rerunID, err := TestUtil.TriggerPipeline(t, config.pipelineServerURL, pipelineID, name+"-rerun", params, config.bearerToken)
+	require.NoError(t, err, "Failed to trigger the rerun")
+	t.Logf("Rerun with run ID %s started....", rerunID)
+	rerun, err := TestUtil.WaitForPipelineCompletion(t, config.pipelineServerURL, rerunID, config.bearerToken)
+	require.NoError(t, err, "Rerun did not complete")
+	require.NoError(t, TestUtil.CheckRerunAfterEviction(rerun, evicted), "Rerun did not recover from the eviction")
+	t.Logf("Rerun reused the cached tasks %v", rerun.CachedTasks())
+}
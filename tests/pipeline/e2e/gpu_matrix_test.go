@@ -0,0 +1,92 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestPipelineRunGPUMatrix runs the pipeline training on a single worker with 1, 2, 4 and 8 GPUs, the sizes the free
+// GPUs of the nodes can hold, verifying the PyTorchJob of every run was scheduled with the requested processes per
+// node
+func TestPipelineRunGPUMatrix(t *testing.T) {
+	if os.Getenv("ENABLE_GPU_MATRIX_TEST") != "true" {
+		t.Skip("Skipping GPU matrix test. Set ENABLE_GPU_MATRIX_TEST=true to enable.")
+	}
+	sizes := TestUtil.DefaultGPUMatrixSizes
+	if value := os.Getenv("GPU_MATRIX_SIZES"); value != "" {
+		var err error
+		sizes, err = TestUtil.ParseGPUMatrixSizes(value)
+		require.NoError(t, err, "Invalid GPU_MATRIX_SIZES")
+	}
+
+	config := loadPipelineTestConfig(t)
+	acquireGPULease(t)
+	namespace := pipelineNamespace(t)
+	client := TestUtil.NewKubeClient(t)
+	dynamicClient := TestUtil.NewDynamicClient(t)
+
+	// The capacity is measured once the lease is held, so the runs of other suites are accounted for
+	gpuResource, nodeSelector := TestUtil.TrainingPlacement(loadPipelineParams(t, evalParameterOverrides(t)))
+	nodes, err := client.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err, "Failed to list nodes")
+	pods, err := client.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err, "Failed to list pods")
+	free := TestUtil.FreeGPUsPerNode(nodes.Items, pods.Items, gpuResource, nodeSelector)
+	t.Logf("Free GPUs of %s per node: %v", gpuResource, free)
+
+	var runs []TestUtil.GPUMatrixRun
+	for _, variant := range TestUtil.GPUMatrixVariants(free, sizes) {
+		result := TestUtil.GPUMatrixRun{GPUMatrixVariant: variant}
+		result.Passed = t.Run(fmt.Sprintf("%d-gpu", variant.GPUs), func(t *testing.T) {
+			if variant.Skip != "" {
+				t.Skipf("Skipping the %d GPU variant: %s", variant.GPUs, variant.Skip)
+			}
+			overrides := evalParameterOverrides(t)
+			overrides["train_num_workers"] = 1
+			overrides["train_gpu_per_worker"] = variant.GPUs
+			prepareRuns(t, config, overrides)
+			run := runPipeline(t, config, overrides)
+
+			trainingPods := TestUtil.GetTrainingPods(t, client, namespace, run.runID)
+			jobs := TestUtil.TrainingJobNames(TestUtil.GetRunPods(t, client, namespace, run.runID))
+			require.NotEmpty(t, jobs, "No PyTorchJobs found for run %s", run.runID)
+			for _, name := range jobs {
+				job, err := dynamicClient.Resource(TestUtil.PyTorchJobGVR).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+				require.NoError(t, err, "Failed to retrieve PyTorchJob %s", name)
+				for _, problem := range TestUtil.CheckNprocPerNode(job, trainingPods, gpuResource, variant.GPUs) {
+					t.Error(problem)
+				}
+			}
+			for job, duration := range TestUtil.TrainingJobDurations(trainingPods) {
+				t.Logf("%s trained with %d GPUs in %s", job, variant.GPUs, duration.Round(time.Second))
+				result.Training += duration
+			}
+		})
+		runs = append(runs, result)
+	}
+	path := TestUtil.WriteArtifact(t, "gpu-matrix.md", []byte(TestUtil.RenderGPUMatrix(runs)))
+	t.Logf("GPU matrix written to %s", path)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"testing"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// checkGPUFit verifies the training and eval phases of the run fit in the GPUs left free by the running pods, the
+// in-cluster judge included, before the run is started
+func checkGPUFit(t *testing.T, overrides map[string]interface{}) {
+	client := TestUtil.NewKubeClient(t)
+	requests := TestUtil.RunGPURequests(loadPipelineParams(t, overrides))
+	capacity := map[string]TestUtil.GPUSharing{}
+	for _, request := range requests {
+		if _, ok := capacity[request.Resource]; ok {
+			continue
+		}
+		sharing, err := TestUtil.GetGPUSharing(client, request.Resource)
+		require.NoError(t, err, "Failed to measure the GPU capacity of the cluster")
+		if sharing.Allocatable > 0 {
+			capacity[request.Resource] = sharing
+		}
+		t.Logf("%s: %d physical GPUs, %d allocatable (time-slicing %t), %d requested by running pods",
+			sharing.Resource, sharing.Physical, sharing.Allocatable, sharing.TimeSlicing, sharing.Requested)
+	}
+	for _, problem := range TestUtil.CheckGPUFit(capacity, requests) {
+		t.Errorf("GPU requests do not fit the cluster: %s", problem)
+	}
+	if t.Failed() {
+		t.FailNow()
+	}
+}
+
+// checkRunGPURequests verifies the training and eval pods of the run requested the GPUs the fit was checked with
+func checkRunGPURequests(t *testing.T, run pipelineRun) {
+	client := TestUtil.NewKubeClient(t)
+	namespace := pipelineNamespace(t)
+	requests := TestUtil.RunGPURequests(run.params)
+	trainingPods := TestUtil.GetTrainingPods(t, client, namespace, run.runID)
+	tasks := TestUtil.GetRunTaskPods(t, client, namespace, run.runID)
+	for _, problem := range TestUtil.CheckRunGPURequests(requests, trainingPods, tasks) {
+		t.Errorf("GPU request: %s", problem)
+	}
+}
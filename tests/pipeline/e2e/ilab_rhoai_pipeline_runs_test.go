@@ -17,17 +17,33 @@ limitations under the License.
 package odh
 
 import (
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/loglevel"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/runcontrol"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/rand"
 )
 
-func TestPipelineRun(t *testing.T) {
-	t.Log("Starting TestPipelineRun...")
+// pipelineTestConfig holds the settings shared by every pipeline run of the suite
+type pipelineTestConfig struct {
+	pipelineServerURL   string
+	bearerToken         string
+	pipelineDisplayName string
+	// runTimeout is the time runs are waited for
+	runTimeout time.Duration
+	// extensions are the names of the run extensions enabled besides those enabled by their environment variable
+	extensions map[string]bool
+}
 
+func loadPipelineTestConfig(t *testing.T) pipelineTestConfig {
 	if os.Getenv("ENABLE_ILAB_PIPELINE_TEST") != "true" {
 		t.Skip("Skipping iLab pipeline test. Set ENABLE_ILAB_PIPELINE_TEST=true to enable.")
 	}
@@ -43,33 +59,242 @@ func TestPipelineRun(t *testing.T) {
 	pipelineDisplayName := os.Getenv("PIPELINE_DISPLAY_NAME")
 	require.NotEmpty(t, pipelineDisplayName, "PIPELINE_DISPLAY_NAME environment variable must be set")
 
-	t.Logf("Retrieving pipeline ID for display name: %s", pipelineDisplayName)
+	return pipelineTestConfig{
+		pipelineServerURL:   pipelineServerURL,
+		bearerToken:         bearerToken,
+		pipelineDisplayName: pipelineDisplayName,
+		runTimeout:          TestUtil.DefaultRunTimeout,
+	}
+}
+
+func TestPipelineRun(t *testing.T) {
+	t.Log("Starting TestPipelineRun...")
+
+	config := loadPipelineTestConfig(t)
+
+	// Quantize the output model, skipped while the pipeline does not expose output_quantization
+	quantization := os.Getenv("OUTPUT_QUANTIZATION")
+	if quantization != "" {
+		definitions, err := TestUtil.LoadPipelineInputDefinitions("../../../pipeline.yaml")
+		require.NoError(t, err, "Failed to load the compiled pipeline")
+		if missing := TestUtil.MissingPipelineInputs(definitions, map[string]interface{}{"output_quantization": quantization}); len(missing) > 0 {
+			t.Skipf("Skipping the quantized pipeline run, the pipeline does not expose %v yet", missing)
+		}
+	}
+	overrides := evalParameterOverrides(t)
+	applyTimeBudget(t, &config, overrides)
+	acquireGPULease(t)
+
+	prepareRuns(t, config, overrides)
+
+	if quantization != "" {
+		overrides["output_quantization"] = quantization
+	}
+
+	runPipeline(t, config, overrides)
+}
+
+// pipelineRun is a run of the pipeline, successfully completed when returned by runPipeline
+type pipelineRun struct {
+	runID  string
+	params map[string]interface{}
+	// runPrefix is the bucket prefix of the run outputs, set with ENABLE_RUN_PREFIX
+	runPrefix string
+	start     time.Time
+	// server is the pipeline server the run was triggered on
+	server runcontrol.PipelineServer
+	// log logs the debug messages of the watchers of the run while the log level ConfigMap sets debug
+	log *loglevel.Logger
+}
+
+// runWatcher watches a pipeline run from its start until the returned stop function is called
+type runWatcher func(runID string) (stop func())
+
+// startPipeline triggers a run of the pipeline with the parameters from pipeline_params.yaml, replaced by the given
+// overrides
+func startPipeline(t *testing.T, config pipelineTestConfig, overrides map[string]interface{}) pipelineRun {
+	// Load input parameters for the pipeline
+	paramsMap := loadPipelineParams(t, overrides)
+	require.Empty(t, TestUtil.ValidatePipelineParams(paramsMap), "Invalid pipeline parameters")
+
+	// Runs pinned to an architecture or labeled for cost attribution use a copy of the compiled pipeline
+	var pipelineYAML []byte
+	var variants []string
+	if guardArch := os.Getenv("ARCH_GUARD"); guardArch != "" {
+		pipelineYAML = applyArchGuard(t, compiledPipeline(t, pipelineYAML), paramsMap, guardArch)
+		variants = append(variants, guardArch)
+	}
+	if os.Getenv("ENABLE_COST_LABELS") == "true" {
+		pipelineYAML = applyCostLabels(t, compiledPipeline(t, pipelineYAML))
+		variants = append(variants, "cost-labels")
+	}
+	if len(variants) > 0 {
+		config.pipelineDisplayName = fmt.Sprintf("%s-%s-%d", config.pipelineDisplayName, strings.Join(variants, "-"), time.Now().Unix())
+		uploadPipeline(t, config, config.pipelineDisplayName, pipelineYAML)
+	}
+
+	t.Logf("Retrieving pipeline ID for display name: %s", config.pipelineDisplayName)
 
 	// Retrieve the pipeline ID
-	pipelineID, err := TestUtil.RetrievePipelineId(t, pipelineServerURL, pipelineDisplayName, bearerToken)
+	pipelineID, err := TestUtil.RetrievePipelineId(t, config.pipelineServerURL, config.pipelineDisplayName, config.bearerToken)
 	require.NoError(t, err, "Failed to retrieve pipeline ID")
 	t.Log("Pipeline loaded successfully.")
+	start := time.Now()
 
-	// Load input parameters for the pipeline
+	// Store the run outputs under their own bucket prefix
+	var pipelineRoot, runPrefix string
+	if os.Getenv("ENABLE_RUN_PREFIX") == "true" {
+		store, err := TestUtil.NewArtifactStoreFromEnv()
+		require.NoError(t, err, "Failed to create artifact store")
+		runPrefix = TestUtil.NewRunPrefix(start)
+		pipelineRoot = store.PipelineRoot(runPrefix)
+		require.NotEmpty(t, pipelineRoot, "The artifact store cannot be used as a pipeline root")
+		t.Logf("Storing run outputs under %s", pipelineRoot)
+	}
+
+	// Trigger the pipeline run
+	runID, err := TestUtil.TriggerPipelineWithRoot(t, config.pipelineServerURL, pipelineID, config.pipelineDisplayName, paramsMap, pipelineRoot, config.bearerToken)
+	require.NoError(t, err, "Failed to trigger pipeline")
+	t.Logf("Pipeline with name %s and run ID %s started....", config.pipelineDisplayName, runID)
+	server := runcontrol.PipelineServer{URL: config.pipelineServerURL, BearerToken: config.bearerToken}
+	return pipelineRun{runID: runID, params: paramsMap, runPrefix: runPrefix, start: start, server: server, log: watchLogLevel(t)}
+}
+
+// compiledPipeline returns the pipeline rewritten so far, or reads the compiled pipeline when it was not rewritten
+func compiledPipeline(t *testing.T, rewritten []byte) []byte {
+	if rewritten != nil {
+		return rewritten
+	}
+	pipelineYAML, err := os.ReadFile("../../../pipeline.yaml")
+	require.NoError(t, err, "Failed to read the compiled pipeline")
+	return pipelineYAML
+}
+
+// uploadPipeline uploads a compiled pipeline under the display name and deletes it when the test completes
+func uploadPipeline(t *testing.T, config pipelineTestConfig, name string, pipelineYAML []byte) string {
+	pipelineID, err := TestUtil.UploadPipeline(t, config.pipelineServerURL, name, pipelineYAML, config.bearerToken)
+	require.NoError(t, err, "Failed to upload pipeline %s", name)
+	t.Cleanup(func() {
+		if err := TestUtil.DeletePipeline(t, config.pipelineServerURL, pipelineID, config.bearerToken); err != nil {
+			t.Logf("Failed to delete pipeline %s: %v", name, err)
+		}
+	})
+	return pipelineID
+}
+
+// runPipeline triggers a run of the pipeline with the parameters from pipeline_params.yaml, replaced by the given
+// overrides, and waits for its successful completion while the given watchers watch the run
+func runPipeline(t *testing.T, config pipelineTestConfig, overrides map[string]interface{}, watchers ...runWatcher) pipelineRun {
+	run := startPipeline(t, config, overrides)
+	runID := run.runID
+	defer watchRunProgress(t, run)()
+
+	for _, watch := range watchers {
+		stop := watch(runID)
+		defer stop()
+	}
+
+	for _, extension := range enabledRunExtensions(config) {
+		if extension.watch != nil {
+			stop := extension.watch(t, run)
+			defer stop()
+		}
+	}
+
+	// Verify the pipeline's successful completion
+	t.Log("Waiting for pipeline to complete successfully...")
+	err := TestUtil.WaitForPipelineSuccessWithin(t, config.pipelineServerURL, runID, config.bearerToken, config.runTimeout)
+	if err != nil {
+		for _, extension := range enabledRunExtensions(config) {
+			if extension.failed != nil {
+				extension.failed(t, run)
+			}
+		}
+	}
+	require.NoError(t, err, "Pipeline did not complete successfully")
+	t.Logf("Pipeline with name %s and run ID %s finished successfully!", config.pipelineDisplayName, runID)
+
+	if run.runPrefix != "" {
+		store, err := TestUtil.NewArtifactStoreFromEnv()
+		require.NoError(t, err, "Failed to create artifact store")
+		err = TestUtil.CheckRunArtifactsScoped(store, run.runPrefix, runID)
+		require.NoError(t, err, "Run outputs are not scoped to the run prefix")
+	}
+
+	if guardArch := os.Getenv("ARCH_GUARD"); guardArch != "" {
+		checkArchGuard(t, runID, guardArch)
+	}
+	if os.Getenv("ENABLE_COST_LABELS") == "true" {
+		checkCostLabels(t, runID)
+	}
+
+	for _, extension := range enabledRunExtensions(config) {
+		if extension.check != nil {
+			extension.check(t, run)
+		}
+	}
+
+	return run
+}
+
+// deployRawJudge serves the judge from JUDGE_MODEL_PVC with a plain Deployment and Service and returns the judge secret
+func deployRawJudge(t *testing.T) string {
+	modelPVC := os.Getenv("JUDGE_MODEL_PVC")
+	require.NotEmpty(t, modelPVC, "JUDGE_MODEL_PVC environment variable must be set")
+	modelName := os.Getenv("JUDGE_MODEL_NAME")
+	require.NotEmpty(t, modelName, "JUDGE_MODEL_NAME environment variable must be set")
+
+	gpus := 4
+	if value := os.Getenv("JUDGE_GPUS"); value != "" {
+		var err error
+		gpus, err = strconv.Atoi(value)
+		require.NoError(t, err, "JUDGE_GPUS must be a number of GPUs")
+	}
+
+	client := TestUtil.NewKubeClient(t)
+	namespace := pipelineNamespace(t)
+	name := TestUtil.ResourcePrefix() + "judge"
+	t.Cleanup(func() { TestUtil.DeleteRawJudge(t, client, namespace, name) })
+	ca, deleteCA := TestUtil.ModelServerCA(t, client, namespace, "judge")
+	t.Cleanup(deleteCA)
+
+	t.Log("Deploying the judge as a Deployment and Service...")
+	secretName := TestUtil.DeployRawJudge(t, client, TestUtil.RawJudgeConfig{
+		Name:      name,
+		Namespace: namespace,
+		Image:     os.Getenv("JUDGE_IMAGE"),
+		ModelPVC:  modelPVC,
+		ModelName: modelName,
+		APIToken:  rand.String(32),
+		GPUs:      gpus,
+		CA:        ca,
+	}, 30*time.Minute)
+	t.Logf("Judge is ready, using judge secret %s", secretName)
+	return secretName
+}
+
+// loadPipelineParams loads the run parameters from pipeline_params.yaml and applies the given overrides
+func loadPipelineParams(t *testing.T, overrides map[string]interface{}) map[string]interface{} {
 	t.Log("Loading pipeline parameters")
 	viper.SetConfigName("pipeline_params")
 	viper.SetConfigType("yaml")
 	viper.AddConfigPath("../e2e/resources/")
 
-	err = viper.ReadInConfig()
+	err := viper.ReadInConfig()
 	require.NoError(t, err, "Error loading pipeline parameters")
 	t.Log("Parameter config loaded successfully.")
 
 	paramsMap := viper.AllSettings()
+	for name, value := range overrides {
+		paramsMap[name] = value
+	}
 	t.Log("Successfully loaded and converted pipeline parameters.")
-	// Trigger the pipeline run
-	runID, err := TestUtil.TriggerPipeline(t, pipelineServerURL, pipelineID, pipelineDisplayName, paramsMap, bearerToken)
-	require.NoError(t, err, "Failed to trigger pipeline")
-	t.Logf("Pipeline with name %s and run ID %s started....", pipelineDisplayName, runID)
+	return paramsMap
+}
 
-	// Verify the pipeline's successful completion
-	t.Log("Waiting for pipeline to complete successfully...")
-	err = TestUtil.WaitForPipelineSuccess(t, pipelineServerURL, runID, bearerToken)
-	require.NoError(t, err, "Pipeline did not complete successfully")
-	t.Logf("Pipeline with name %s and run ID %s finished successfully!", pipelineDisplayName, runID)
+// pipelineNamespace returns the namespace of the pipeline server, required by the checks accessing the cluster
+func pipelineNamespace(t *testing.T) string {
+	namespace := os.Getenv("PIPELINE_NAMESPACE")
+	require.NotEmpty(t, namespace, "PIPELINE_NAMESPACE environment variable must be set")
+	return namespace
 }
@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+// runComparisonScenario runs the workflow of a scenario once with each training image candidate, in parallel in the
+// namespace of the candidate, and writes their durations, MT-Bench scores and resource usage side by side to
+// image-comparison.md in the artifacts directory. Each run is checked against the phases and duration threshold of
+// the scenario; the report is written whatever their outcome, a failed candidate being part of the comparison.
+func runComparisonScenario(t *testing.T, config pipelineTestConfig, scenario TestUtil.Scenario) {
+	baseline := TestUtil.LoadImageMatrix(t, "../e2e/resources/image_matrix.yaml").Baseline
+	if scenario.Images.SDG != "" {
+		baseline.SDGImage = scenario.Images.SDG
+	}
+
+	workflow, err := TestUtil.LoadWorkflow("../e2e/resources/workflow_tasks.yaml")
+	require.NoError(t, err, "Failed to load the workflow tasks")
+	runs := make([]TestUtil.ComparisonRun, len(scenario.Compare))
+	// The group returns once both candidates ran, the parallel subtests of a run only start when its parent returns
+	t.Run("candidates", func(t *testing.T) {
+		for i, candidate := range scenario.Compare {
+			i, candidate := i, candidate
+			runs[i] = TestUtil.ComparisonRun{Candidate: candidate.Name, Namespace: candidate.Namespace, TrainingImage: candidate.Training}
+			t.Run(candidate.Name, func(t *testing.T) {
+				t.Parallel()
+				images := baseline
+				images.TrainingImage = candidate.Training
+				run := runComparisonCandidate(t, config, scenario, workflow, candidate.Namespace, images, &runs[i])
+				require.NoError(t, run.err, "Run %s of candidate %s did not complete successfully", run.config.Name, candidate.Name)
+				checkWorkflowRun(t, scenario, run)
+			})
+		}
+	})
+
+	path := TestUtil.WriteArtifact(t, "image-comparison.md", []byte(TestUtil.RenderImageComparison(workflow, runs[0], runs[1])))
+	t.Logf("Comparison of the training images of %s and %s written to %s", runs[0].Candidate, runs[1].Candidate, path)
+}
+
+// runComparisonCandidate runs the workflow with the images of a candidate, sampling the resource usage of its pods,
+// and records the outcome of the run in result before the pods are deleted with the subtest
+func runComparisonCandidate(t *testing.T, config pipelineTestConfig, scenario TestUtil.Scenario, workflow TestUtil.Workflow, namespace string, images TestUtil.ImageCombination, result *TestUtil.ComparisonRun) workflowRun {
+	client := TestUtil.NewKubeClient(t)
+	usage := func(runID string) func() {
+		stop := TestUtil.WatchWorkflowResourceUsage(client, namespace, runID, workflow, 30*time.Second, func(err error) {
+			t.Logf("Failed to sample resource usage of candidate %s: %v", result.Candidate, err)
+		})
+		return func() { result.Usage = stop() }
+	}
+
+	name := TestUtil.GenerateName(scenario.Orchestrator) + rand.String(5)
+	run := runWorkflow(t, config, scenario, namespace, name, images, usage)
+	result.Duration, result.Err = run.duration, run.err
+
+	pods, err := TestUtil.ListWorkflowTaskPods(client, namespace, name)
+	if err != nil {
+		t.Logf("WARNING: %v", err)
+		return run
+	}
+	result.Tasks = TestUtil.WorkflowTaskDurations(pods)
+	for _, pod := range pods {
+		if !isMTBenchTask(workflow, pod.Labels[TestUtil.WorkflowTaskLabel]) || pod.Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+		logs, err := client.CoreV1().Pods(namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: TestUtil.WorkflowTaskContainer(pod)}).DoRaw(context.Background())
+		if err != nil {
+			t.Logf("WARNING: failed to read the logs of %s: %v", pod.Name, err)
+			continue
+		}
+		scores, err := TestUtil.ParseMTBenchScores(string(logs))
+		if err != nil {
+			t.Logf("WARNING: pod %s: %v", pod.Name, err)
+			continue
+		}
+		result.Scores = &scores
+	}
+	return run
+}
+
+// isMTBenchTask tells whether a task of the workflow runs MT-Bench
+func isMTBenchTask(workflow TestUtil.Workflow, name string) bool {
+	for _, task := range workflow.Tasks {
+		if task.Name == name {
+			return task.Phase == "mt-bench"
+		}
+	}
+	return false
+}
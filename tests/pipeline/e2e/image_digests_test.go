@@ -0,0 +1,51 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// reportImageDigests records the digests the images of the run pods, training pods included, resolved to. The report
+// is written to image-digests.md and image-digests.json in the artifacts directory. With IMAGE_DIGEST_ENFORCE, images
+// referenced by a mutable tag fail the test, as a rerun could pull other content.
+func reportImageDigests(t *testing.T, runID string) {
+	client := TestUtil.NewKubeClient(t)
+	namespace := pipelineNamespace(t)
+
+	pods := append(TestUtil.GetRunPods(t, client, namespace, runID), TestUtil.GetTrainingPods(t, client, namespace, runID)...)
+	digests := TestUtil.RunImageDigests(pods)
+	path := TestUtil.WriteArtifact(t, "image-digests.md", []byte(TestUtil.RenderImageDigests(digests)))
+	data, err := json.MarshalIndent(digests, "", "  ")
+	require.NoError(t, err)
+	TestUtil.WriteArtifact(t, "image-digests.json", data)
+	t.Logf("Digests of %d images written to %s", len(digests), path)
+
+	enforce := os.Getenv("IMAGE_DIGEST_ENFORCE") == "true"
+	for _, image := range TestUtil.MutableImages(digests) {
+		if enforce {
+			t.Errorf("Image %s of pods %v is referenced by a mutable tag, pin it to %s", image.Image, image.Pods, image.Digest)
+		} else {
+			t.Logf("Image %s is referenced by a mutable tag, resolved to %s", image.Image, image.Digest)
+		}
+	}
+}
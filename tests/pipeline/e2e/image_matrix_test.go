@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// TestImageMatrix runs the pipeline for every combination of the candidate images in image_matrix.yaml and writes a
+// compatibility matrix report into the artifacts directory
+func TestImageMatrix(t *testing.T) {
+	if os.Getenv("ENABLE_IMAGE_MATRIX_TEST") != "true" {
+		t.Skip("Skipping image matrix test. Set ENABLE_IMAGE_MATRIX_TEST=true to enable.")
+	}
+	config := loadPipelineTestConfig(t)
+	acquireGPULease(t)
+
+	matrix := TestUtil.LoadImageMatrix(t, "../e2e/resources/image_matrix.yaml")
+	pipelineYAML, err := os.ReadFile("../../../pipeline.yaml")
+	require.NoError(t, err, "Failed to read the compiled pipeline")
+	params := loadPipelineParams(t, nil)
+
+	var results []TestUtil.MatrixResult
+	for i, combination := range matrix.Combinations() {
+		name := fmt.Sprintf("%s-matrix-%d-%d", config.pipelineDisplayName, time.Now().Unix(), i)
+		t.Logf("Running combination %d: workbench %s, SDG %s, training %s", i, combination.WorkbenchImage, combination.SDGImage, combination.TrainingImage)
+
+		result := TestUtil.MatrixResult{Combination: combination}
+		start := time.Now()
+		pipelineID, err := TestUtil.UploadPipeline(t, config.pipelineServerURL, name, TestUtil.RewritePipelineImages(pipelineYAML, matrix.Baseline, combination), config.bearerToken)
+		if err == nil {
+			name := name
+			t.Cleanup(func() {
+				if err := TestUtil.DeletePipeline(t, config.pipelineServerURL, pipelineID, config.bearerToken); err != nil {
+					t.Logf("Failed to delete pipeline %s: %v", name, err)
+				}
+			})
+			result.RunID, err = TestUtil.TriggerPipeline(t, config.pipelineServerURL, pipelineID, name, params, config.bearerToken)
+		}
+		if err == nil {
+			err = TestUtil.WaitForPipelineSuccess(t, config.pipelineServerURL, result.RunID, config.bearerToken)
+		}
+		result.Duration = time.Since(start)
+		result.Err = err
+		results = append(results, result)
+
+		if err != nil {
+			t.Errorf("Combination %d failed: %v", i, err)
+		}
+	}
+
+	path := TestUtil.WriteArtifact(t, "image-matrix.md", []byte(TestUtil.RenderMatrixReport(results)))
+	t.Logf("Image compatibility matrix written to %s", path)
+}
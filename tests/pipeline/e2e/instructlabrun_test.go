@@ -0,0 +1,155 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/api/v1alpha1"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/controllers"
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/ilab"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/runcontrol"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/watcher"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+// TestPipelineRunInstructLabRun runs the pipeline through an InstructLabRun: the test creates the resource and waits
+// for its Succeeded condition, the controller, started in the test, submits and follows the run. The run extensions
+// are not applied, the controller submits the spec as is.
+func TestPipelineRunInstructLabRun(t *testing.T) {
+	if os.Getenv("ENABLE_INSTRUCTLABRUN_TEST") != "true" {
+		t.Skip("Skipping InstructLabRun test. Set ENABLE_INSTRUCTLABRUN_TEST=true to enable.")
+	}
+	config := loadPipelineTestConfig(t)
+	acquireGPULease(t)
+	namespace := pipelineNamespace(t)
+	dynamicClient := TestUtil.NewDynamicClient(t)
+
+	t.Log("Installing the InstructLabRun CRD...")
+	created, err := TestUtil.InstallCRD(t, dynamicClient, TestUtil.InstructLabRunCRDPath, 2*time.Minute)
+	require.NoError(t, err, "Failed to install the InstructLabRun CRD")
+	if created {
+		t.Cleanup(func() { TestUtil.DeleteCRD(t, dynamicClient, "instructlabruns.ilab.opendatahub.io") })
+	}
+
+	client := startInstructLabRunController(t, config, namespace)
+
+	params := loadPipelineParams(t, evalParameterOverrides(t))
+	raw, err := json.Marshal(params)
+	require.NoError(t, err, "Failed to encode the pipeline parameters")
+	repoURL, _ := params["sdg_repo_url"].(string)
+	teacherSecret, _ := params["sdg_teacher_secret"].(string)
+	judgeSecret, _ := params["eval_judge_secret"].(string)
+	run := &v1alpha1.InstructLabRun{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: TestUtil.GenerateName("ilab-run"), Namespace: namespace},
+		Spec: v1alpha1.InstructLabRunSpec{
+			Pipeline:      config.pipelineDisplayName,
+			SDG:           v1alpha1.SDGConfig{RepoURL: repoURL},
+			TeacherSecret: teacherSecret,
+			JudgeSecret:   judgeSecret,
+			Params:        &runtime.RawExtension{Raw: raw},
+		},
+	}
+	require.NoError(t, client.Create(context.Background(), run), "Failed to create the InstructLabRun")
+	// Deleted before the controller stops, registered later so it runs first, for the finalizer to terminate the run
+	t.Cleanup(func() {
+		if err := client.Delete(context.Background(), run); ctrlclient.IgnoreNotFound(err) != nil {
+			t.Logf("Failed to delete InstructLabRun %s: %v", run.Name, err)
+			return
+		}
+		deadline := time.Now().Add(2 * time.Minute)
+		for time.Now().Before(deadline) {
+			if err := client.Get(context.Background(), ctrlclient.ObjectKeyFromObject(run), &v1alpha1.InstructLabRun{}); apierrors.IsNotFound(err) {
+				return
+			}
+			time.Sleep(2 * time.Second)
+		}
+		t.Logf("InstructLabRun %s still has its finalizer after 2m", run.Name)
+	})
+	t.Logf("Created InstructLabRun %s", run.Name)
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.runTimeout)
+	defer cancel()
+	err = watcher.Until(ctx, TestUtil.NewWatchClient(t), ctrlclient.ObjectKeyFromObject(run), watcher.NewInstructLabRun(), watcher.InstructLabRunSucceeded)
+	require.NoError(t, client.Get(context.Background(), ctrlclient.ObjectKeyFromObject(run), run), "Failed to read InstructLabRun %s", run.Name)
+	require.NoError(t, err, "InstructLabRun %s did not succeed, run %s in state %s", run.Name, run.Status.RunID, run.Status.State)
+
+	require.NotEmpty(t, run.Status.RunID, "InstructLabRun %s has no run ID", run.Name)
+	require.Equal(t, "SUCCEEDED", run.Status.State)
+	require.True(t, meta.IsStatusConditionTrue(run.Status.Conditions, v1alpha1.ConditionSubmitted), "InstructLabRun %s not submitted", run.Name)
+	require.True(t, meta.IsStatusConditionFalse(run.Status.Conditions, v1alpha1.ConditionRunning), "InstructLabRun %s still running", run.Name)
+	for _, task := range run.Status.Tasks {
+		t.Logf("Task %s: %s", task.Name, task.State)
+	}
+	t.Logf("InstructLabRun %s succeeded with run %s", run.Name, run.Status.RunID)
+}
+
+// startInstructLabRunController runs the InstructLabRun controller on the namespace until the end of the test, and
+// returns a client of the InstructLabRuns
+func startInstructLabRunController(t *testing.T, config pipelineTestConfig, namespace string) ctrlclient.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+	kubeConfig := TestUtil.NewKubeConfig(t)
+
+	mgr, err := ctrl.NewManager(kubeConfig, ctrl.Options{
+		Scheme:  scheme,
+		Cache:   cache.Options{DefaultNamespaces: map[string]cache.Config{namespace: {}}},
+		Metrics: metricsserver.Options{BindAddress: "0"},
+	})
+	require.NoError(t, err, "Failed to create the controller manager")
+	runs := &ilab.Client{
+		Server:    runcontrol.PipelineServer{URL: config.pipelineServerURL, BearerToken: config.bearerToken},
+		Kube:      TestUtil.NewKubeClient(t),
+		Namespace: namespace,
+	}
+	reconciler := &controllers.InstructLabRunReconciler{Client: mgr.GetClient(), Runs: runs}
+	require.NoError(t, reconciler.SetupWithManager(mgr), "Failed to set up the InstructLabRun controller")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var stopped sync.WaitGroup
+	stopped.Add(1)
+	go func() {
+		defer stopped.Done()
+		if err := mgr.Start(ctx); err != nil {
+			t.Logf("InstructLabRun controller stopped: %v", err)
+		}
+	}()
+	t.Cleanup(func() {
+		cancel()
+		stopped.Wait()
+	})
+
+	client, err := ctrlclient.New(kubeConfig, ctrlclient.Options{Scheme: scheme})
+	require.NoError(t, err, "Failed to create the InstructLabRun client")
+	return client
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// discoverKServeJudge synthesizes the judge secret from the InferenceService JUDGE_INFERENCE_SERVICE and returns its
+// name, the secret is removed at the end of the test
+func discoverKServeJudge(t *testing.T) string {
+	name := os.Getenv("JUDGE_INFERENCE_SERVICE")
+	require.NotEmpty(t, name, "JUDGE_INFERENCE_SERVICE environment variable must be set")
+
+	client := TestUtil.NewKubeClient(t)
+	namespace := pipelineNamespace(t)
+	content, err := TestUtil.DiscoverModelServerSecret(context.Background(), client, TestUtil.NewDynamicClient(t), namespace, name)
+	require.NoError(t, err, "Failed to discover the judge InferenceService")
+
+	secretName := TestUtil.ResourcePrefix() + name + "-judge-secret"
+	TestUtil.CreateModelServerSecret(t, client, namespace, secretName, content)
+	t.Cleanup(func() { TestUtil.DeleteModelServerSecret(t, client, namespace, secretName) })
+	t.Logf("Using judge secret %s for InferenceService %s at %s", secretName, name, content.Endpoint)
+	return secretName
+}
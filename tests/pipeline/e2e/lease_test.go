@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/gpuqueue"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+// acquireGPULease waits for the GPU lease of the cluster when ENABLE_GPU_LEASE is set, and releases it at the end
+// of the test
+func acquireGPULease(t *testing.T) {
+	if os.Getenv("ENABLE_GPU_LEASE") != "true" {
+		return
+	}
+
+	namespace := os.Getenv("GPU_LEASE_NAMESPACE")
+	if namespace == "" {
+		namespace = pipelineNamespace(t)
+	}
+	takeover := 30 * time.Minute
+	if value := os.Getenv("GPU_LEASE_TAKEOVER_TIMEOUT"); value != "" {
+		var err error
+		takeover, err = time.ParseDuration(value)
+		require.NoError(t, err, "GPU_LEASE_TAKEOVER_TIMEOUT must be a duration, e.g. 30m")
+	}
+	holder := os.Getenv("GPU_LEASE_HOLDER")
+	if holder == "" {
+		hostname, _ := os.Hostname()
+		holder = hostname + "-" + rand.String(5)
+	}
+
+	priority := 0
+	if value := os.Getenv("GPU_LEASE_PRIORITY"); value != "" {
+		var err error
+		priority, err = strconv.Atoi(value)
+		require.NoError(t, err, "GPU_LEASE_PRIORITY must be an integer")
+	}
+
+	t.Logf("Acquiring GPU lease %s/%s as %s...", namespace, TestUtil.GPULeaseName(), holder)
+	lease, err := TestUtil.AcquireLease(t, TestUtil.NewKubeClient(t), gpuqueue.Request{
+		Namespace: namespace,
+		Name:      TestUtil.GPULeaseName(),
+		Holder:    holder,
+		Team:      os.Getenv("GPU_LEASE_TEAM"),
+		Priority:  priority,
+		Takeover:  takeover,
+		Timeout:   6 * time.Hour,
+	})
+	require.NoError(t, err, "Failed to acquire GPU lease")
+	t.Log("GPU lease acquired.")
+
+	t.Cleanup(func() {
+		if err := lease.Release(); err != nil {
+			t.Logf("Failed to release GPU lease: %v", err)
+		}
+	})
+}
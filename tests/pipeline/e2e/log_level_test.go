@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/loglevel"
+	"github.com/stretchr/testify/require"
+)
+
+// logLevelConfigMap returns the ConfigMap of the pipeline namespace setting the log level during the runs,
+// LOG_LEVEL_CONFIGMAP or ilab-log-level
+func logLevelConfigMap() string {
+	if name := os.Getenv("LOG_LEVEL_CONFIGMAP"); name != "" {
+		return name
+	}
+	return loglevel.DefaultConfigMap
+}
+
+// logLevelEnabled reports whether LOG_LEVEL or LOG_LEVEL_CONFIGMAP is set, the log level of the watchers is not
+// followed otherwise, as it needs PIPELINE_NAMESPACE and a kubeconfig
+func logLevelEnabled() bool {
+	return os.Getenv("LOG_LEVEL") != "" || os.Getenv("LOG_LEVEL_CONFIGMAP") != ""
+}
+
+// watchLogLevel returns the logger of the watchers of a run, at LOG_LEVEL until the level key of the log level
+// ConfigMap sets another level, read every 30 seconds until the test completes. Setting it to debug mid-run makes the
+// watchers log their every check and the progress of the run tasks:
+//
+//	oc create configmap ilab-log-level --from-literal=level=debug
+//
+// Without LOG_LEVEL and LOG_LEVEL_CONFIGMAP, the logger stays at info.
+func watchLogLevel(t *testing.T) *loglevel.Logger {
+	if !logLevelEnabled() {
+		return loglevel.New(loglevel.Info, t.Logf)
+	}
+	level := loglevel.Info
+	if value := os.Getenv("LOG_LEVEL"); value != "" {
+		var err error
+		level, err = loglevel.ParseLevel(value)
+		require.NoError(t, err, "Invalid LOG_LEVEL")
+	}
+	logger := loglevel.New(level, t.Logf)
+	client := TestUtil.NewKubeClient(t)
+	namespace := pipelineNamespace(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Watch(ctx, client, namespace, logLevelConfigMap(), 30*time.Second)
+	}()
+	// The watch logs with t, it must stop before the test completes
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return logger
+}
+
+// watchRunProgress logs the state of the task pods of the run every minute while the log level is debug, it does
+// nothing without LOG_LEVEL and LOG_LEVEL_CONFIGMAP
+func watchRunProgress(t *testing.T, run pipelineRun) func() {
+	if !logLevelEnabled() {
+		return func() {}
+	}
+	client := TestUtil.NewKubeClient(t)
+	namespace := pipelineNamespace(t)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			if run.log.Level() != loglevel.Debug {
+				continue
+			}
+			tasks, err := TestUtil.ListRunTaskPods(client, namespace, run.runID)
+			if err != nil {
+				run.log.Debugf("Failed to list the task pods of run %s: %v", run.runID, err)
+				continue
+			}
+			for _, task := range tasks {
+				since := "not started"
+				if task.Pod.Status.StartTime != nil {
+					since = time.Since(task.Pod.Status.StartTime.Time).Round(time.Second).String()
+				}
+				run.log.Debugf("Run %s: task %s of pod %s is %s, started %s ago", run.runID, task.Function, task.Pod.Name, task.Pod.Status.Phase, since)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// retainRunLogs deploys a log shipper copying the logs of the run pods to a dedicated PVC while they run, so logs of
+// deleted or evicted pods are kept. The logs are collected into run-logs.tar.gz in the artifacts directory at the end
+// of the test; the log PVC is kept when they cannot be collected.
+func retainRunLogs(t *testing.T, overrides map[string]interface{}) {
+	image := os.Getenv("LOG_SHIPPER_IMAGE")
+	require.NotEmpty(t, image, "LOG_SHIPPER_IMAGE environment variable must be set")
+	storageClass := os.Getenv("LOG_PVC_STORAGE_CLASS")
+	if storageClass == "" {
+		storageClass, _ = loadPipelineParams(t, overrides)["k8s_storage_class_name"].(string)
+	}
+
+	client := TestUtil.NewKubeClient(t)
+	namespace := pipelineNamespace(t)
+	name := TestUtil.ResourcePrefix() + "run-logs"
+	t.Cleanup(func() {
+		bundle, err := TestUtil.CollectLogBundle(client, namespace, name)
+		if err != nil {
+			t.Logf("Keeping log PVC %s: %v", name, err)
+			return
+		}
+		path := TestUtil.WriteArtifact(t, "run-logs.tar.gz", bundle)
+		t.Logf("Run logs written to %s", path)
+		TestUtil.DeleteLogShipper(t, client, namespace, name)
+	})
+
+	t.Logf("Deploying log shipper with a %s PVC...", storageClass)
+	TestUtil.DeployLogShipper(t, client, TestUtil.LogShipperConfig{
+		Name:              name,
+		Namespace:         namespace,
+		Image:             image,
+		StorageClass:      storageClass,
+		Size:              "1Gi",
+		LogLevelConfigMap: logLevelConfigMap(),
+	}, 5*time.Minute)
+}
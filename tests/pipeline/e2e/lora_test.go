@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPipelineRunLoRA runs the pipeline with parameter-efficient training, verifying the training pods request fewer
+// GPUs than full fine-tuning and an adapter rather than full weights is stored in the bucket
+func TestPipelineRunLoRA(t *testing.T) {
+	if os.Getenv("ENABLE_LORA_TEST") != "true" {
+		t.Skip("Skipping LoRA pipeline test. Set ENABLE_LORA_TEST=true to enable.")
+	}
+
+	loraParams := viper.New()
+	loraParams.SetConfigFile("../e2e/resources/lora_params.yaml")
+	require.NoError(t, loraParams.ReadInConfig(), "Error loading LoRA parameters")
+	overrides := loraParams.AllSettings()
+
+	definitions, err := TestUtil.LoadPipelineInputDefinitions("../../../pipeline.yaml")
+	require.NoError(t, err, "Failed to load the compiled pipeline")
+	if missing := TestUtil.MissingPipelineInputs(definitions, overrides); len(missing) > 0 {
+		t.Skipf("Skipping LoRA pipeline test, the pipeline does not expose %v yet", missing)
+	}
+
+	config := loadPipelineTestConfig(t)
+	acquireGPULease(t)
+	client := TestUtil.NewKubeClient(t)
+	store, err := TestUtil.NewArtifactStoreFromEnv()
+	require.NoError(t, err, "Failed to create artifact store")
+
+	pipelineID, err := TestUtil.RetrievePipelineId(t, config.pipelineServerURL, config.pipelineDisplayName, config.bearerToken)
+	require.NoError(t, err, "Failed to retrieve pipeline ID")
+
+	start := time.Now()
+	runPrefix := TestUtil.NewRunPrefix(start)
+	params := loadPipelineParams(t, overrides)
+	name := fmt.Sprintf("%s-lora-%d", config.pipelineDisplayName, start.Unix())
+	runID, err := TestUtil.TriggerPipelineWithRoot(t, config.pipelineServerURL, pipelineID, name, params, store.PipelineRoot(runPrefix), config.bearerToken)
+	require.NoError(t, err, "Failed to trigger pipeline")
+	t.Logf("Pipeline with name %s and run ID %s started....", name, runID)
+
+	err = TestUtil.WaitForPipelineSuccess(t, config.pipelineServerURL, runID, config.bearerToken)
+	require.NoError(t, err, "Pipeline did not complete successfully")
+
+	// Full fine-tuning uses the GPUs of pipeline_params.yaml
+	fullGPUs := viper.GetInt64("train_gpu_per_worker")
+	trainingPods := TestUtil.GetTrainingPods(t, client, pipelineNamespace(t), runID)
+	require.NotEmpty(t, trainingPods, "No training pods found for the run")
+	for pod, gpus := range TestUtil.PodGPURequests(trainingPods, TestUtil.DefaultGPUResource) {
+		if gpus > loraParams.GetInt64("train_gpu_per_worker") || (fullGPUs > 1 && gpus >= fullGPUs) {
+			t.Errorf("Training pod %s requests %d GPUs, LoRA training should request fewer than the %d of full fine-tuning", pod, gpus, fullGPUs)
+		}
+	}
+
+	keys, err := TestUtil.ListObjectKeys(store, runPrefix)
+	require.NoError(t, err, "Failed to list the run artifacts")
+	require.NoError(t, TestUtil.CheckAdapterArtifacts(keys), "LoRA adapter artifacts not found under %s", runPrefix)
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+// TestPipelineRunMock runs the whole pipeline on a cluster without GPUs: fake GPUs are advertised on the worker
+// nodes, a stub server stands in for the teacher and judge, and the sizes are minimized so the control flow
+// completes quickly enough to gate pull requests. The stub answers the SDG prompts by type so the generated data
+// survives the SDG filters, the run checks the orchestration and not the quality of its outputs.
+func TestPipelineRunMock(t *testing.T) {
+	if os.Getenv("ENABLE_MOCK_TEST") != "true" {
+		t.Skip("Skipping mock pipeline test. Set ENABLE_MOCK_TEST=true to enable.")
+	}
+	config := loadPipelineTestConfig(t)
+	maxDuration := 30 * time.Minute
+	if value := os.Getenv("MOCK_MAX_DURATION"); value != "" {
+		var err error
+		maxDuration, err = time.ParseDuration(value)
+		require.NoError(t, err, "MOCK_MAX_DURATION must be a duration, e.g. 30m")
+	}
+
+	overrides := prepareMockRun(t)
+	start := time.Now()
+	runPipeline(t, config, overrides)
+	duration := time.Since(start)
+	t.Logf("Mock run completed in %s", duration.Round(time.Second))
+	require.LessOrEqual(t, duration, maxDuration, "Mock run took longer than %s", maxDuration)
+}
+
+// prepareMockRun advertises fake GPUs and deploys the stub LLM server for the teacher and judge, removed at the end
+// of the test, and returns the parameter overrides of a mock run
+func prepareMockRun(t *testing.T) map[string]interface{} {
+	namespace := pipelineNamespace(t)
+	client := TestUtil.NewKubeClient(t)
+
+	image := os.Getenv("MOCK_STUB_IMAGE")
+	require.NotEmpty(t, image, "MOCK_STUB_IMAGE environment variable must be set")
+	baseModel := os.Getenv("MOCK_BASE_MODEL")
+	require.NotEmpty(t, baseModel, "MOCK_BASE_MODEL environment variable must be set")
+
+	// Local clusters such as kind and OpenShift Local run the pods on nodes without the worker role
+	local := os.Getenv("MOCK_LOCAL_CLUSTER") == "true"
+	selector := TestUtil.WorkerNodeLabel
+	if local {
+		selector = ""
+	}
+	nodes := TestUtil.AdvertiseFakeGPUs(t, client, 4, selector)
+	t.Cleanup(func() { TestUtil.RemoveFakeGPUs(t, client, nodes) })
+	require.NotEmpty(t, nodes, "No schedulable node to advertise fake GPUs on")
+	t.Logf("Advertised fake GPUs on nodes %v", nodes)
+
+	stub := TestUtil.StubLLMConfig{
+		Name:      TestUtil.ResourcePrefix() + "stub-llm",
+		Namespace: namespace,
+		Image:     image,
+		ModelName: "stub",
+		APIKey:    rand.String(16),
+	}
+	if value := os.Getenv("MOCK_STUB_LATENCY"); value != "" {
+		var err error
+		stub.Latency, err = time.ParseDuration(value)
+		require.NoError(t, err, "MOCK_STUB_LATENCY must be a duration, e.g. 500ms")
+	}
+	if value := os.Getenv("MOCK_STUB_ERROR_RATE"); value != "" {
+		var err error
+		stub.ErrorRate, err = strconv.ParseFloat(value, 64)
+		require.NoError(t, err, "MOCK_STUB_ERROR_RATE must be a number")
+	}
+
+	t.Cleanup(func() { TestUtil.DeleteStubLLM(t, client, namespace, stub.Name) })
+	endpoint := TestUtil.DeployStubLLM(t, client, stub, 5*time.Minute)
+	t.Logf("Stub LLM server is ready at %s", endpoint)
+
+	secretName := stub.Name + "-secret"
+	TestUtil.CreateModelServerSecret(t, client, namespace, secretName, TestUtil.ModelServerSecret{
+		APIToken:  stub.APIKey,
+		Endpoint:  endpoint,
+		ModelName: "stub",
+	})
+	t.Cleanup(func() { TestUtil.DeleteModelServerSecret(t, client, namespace, secretName) })
+
+	overrides := loadMockOverrides(t)
+	overrides["sdg_base_model"] = baseModel
+	overrides["sdg_teacher_secret"] = secretName
+	overrides["eval_judge_secret"] = secretName
+	if local {
+		for name, value := range loadMockLocalOverrides(t) {
+			overrides[name] = value
+		}
+		storageClass := os.Getenv("MOCK_STORAGE_CLASS")
+		if storageClass == "" {
+			var err error
+			storageClass, err = TestUtil.DefaultStorageClass(client)
+			require.NoError(t, err, "Failed to find the storage class of the local cluster, set MOCK_STORAGE_CLASS")
+		}
+		overrides["k8s_storage_class_name"] = storageClass
+		t.Logf("Running on a local cluster with storage class %s", storageClass)
+	}
+
+	return overrides
+}
+
+// loadMockOverrides loads the parameter overrides of mock runs from mock_params.yaml
+func loadMockOverrides(t *testing.T) map[string]interface{} {
+	params := viper.New()
+	params.SetConfigFile("../e2e/resources/mock_params.yaml")
+	require.NoError(t, params.ReadInConfig(), "Error loading mock parameters")
+	return params.AllSettings()
+}
+
+// loadMockLocalOverrides loads the overrides of mock_params.yaml for mock runs on local clusters from
+// mock_local_params.yaml
+func loadMockLocalOverrides(t *testing.T) map[string]interface{} {
+	params := viper.New()
+	params.SetConfigFile("../e2e/resources/mock_local_params.yaml")
+	require.NoError(t, params.ReadInConfig(), "Error loading local mock parameters")
+	return params.AllSettings()
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestPipelineRunNodeFailure stops the kubelet of the node of a running training pod for an outage and measures the
+// blast radius of the node loss: the pods lost with the node, the pods rescheduled and the wall-clock added to the
+// run. Destructive, for dedicated test clusters only.
+func TestPipelineRunNodeFailure(t *testing.T) {
+	if os.Getenv("ENABLE_NODE_FAILURE_TEST") != "true" {
+		t.Skip("Skipping node failure test. Set ENABLE_NODE_FAILURE_TEST=true to enable.")
+	}
+	config := loadPipelineTestConfig(t)
+	acquireGPULease(t)
+	namespace := pipelineNamespace(t)
+	client := TestUtil.NewKubeClient(t)
+
+	outage := 10 * time.Minute
+	if value := os.Getenv("NODE_FAILURE_OUTAGE"); value != "" {
+		var err error
+		outage, err = time.ParseDuration(value)
+		require.NoError(t, err, "NODE_FAILURE_OUTAGE must be a duration, e.g. 10m")
+	}
+	// The duration of an undisrupted run, to report the wall-clock added by the node failure
+	var baseline time.Duration
+	if value := os.Getenv("NODE_FAILURE_BASELINE"); value != "" {
+		var err error
+		baseline, err = time.ParseDuration(value)
+		require.NoError(t, err, "NODE_FAILURE_BASELINE must be a duration, e.g. 3h")
+	}
+	image := os.Getenv("NODE_FAILURE_IMAGE")
+	if image == "" {
+		image = "registry.access.redhat.com/ubi9/ubi:latest"
+	}
+
+	overrides := evalParameterOverrides(t)
+	prepareRuns(t, config, overrides)
+	run := startPipeline(t, config, overrides)
+
+	pod, err := TestUtil.WaitForRunningTrainingPod(t, client, namespace, run.runID, config.runTimeout)
+	require.NoError(t, err, "Training did not start")
+	node := pod.Spec.NodeName
+
+	var lost []string
+	for _, pod := range append(TestUtil.GetRunPods(t, client, namespace, run.runID), TestUtil.GetTrainingPods(t, client, namespace, run.runID)...) {
+		if pod.Spec.NodeName == node && pod.Status.Phase == corev1.PodRunning {
+			lost = append(lost, pod.Name)
+		}
+	}
+
+	t.Logf("Stopping the kubelet of node %s for %s, running %v...", node, outage, lost)
+	stopped := time.Now()
+	disruptor := TestUtil.StopKubeletPod(TestUtil.ResourcePrefix()+"stop-kubelet", node, image, outage)
+	_, err = client.CoreV1().Pods(namespace).Create(context.Background(), disruptor, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create the kubelet stopping pod")
+	t.Cleanup(func() {
+		var grace int64
+		if err := client.CoreV1().Pods(namespace).Delete(context.Background(), disruptor.Name, metav1.DeleteOptions{GracePeriodSeconds: &grace}); err != nil {
+			t.Logf("Failed to delete pod %s: %v", disruptor.Name, err)
+		}
+	})
+
+	err = TestUtil.WaitForNodeReady(client, node, false, 5*time.Minute)
+	require.NoError(t, err, "Node %s was not reported NotReady", node)
+	notReadyAfter := time.Since(stopped)
+	t.Logf("Node %s is NotReady after %s", node, notReadyAfter.Round(time.Second))
+
+	err = TestUtil.WaitForNodeReady(client, node, true, outage+15*time.Minute)
+	require.NoError(t, err, "Node %s did not recover after the outage", node)
+	t.Logf("Node %s is Ready again", node)
+
+	details, err := TestUtil.WaitForPipelineCompletionWithin(t, config.pipelineServerURL, run.runID, config.bearerToken, config.runTimeout)
+	require.NoError(t, err, "Pipeline run did not complete after the node failure")
+
+	pods := append(TestUtil.GetRunPods(t, client, namespace, run.runID), TestUtil.GetTrainingPods(t, client, namespace, run.runID)...)
+	report := TestUtil.NodeFailureReport{
+		Node:          node,
+		Outage:        outage,
+		NotReadyAfter: notReadyAfter,
+		LostPods:      lost,
+		Replacements:  TestUtil.PodReplacements(pods, stopped),
+		RunState:      details.State,
+		RunDuration:   time.Since(run.start),
+		Baseline:      baseline,
+	}
+	path := TestUtil.WriteArtifact(t, "node-failure.md", []byte(TestUtil.RenderNodeFailureReport(report)))
+	t.Logf("Pipeline run %s ended %s after %s with %d pods rescheduled, report written to %s", run.runID, details.State, report.RunDuration.Round(time.Second), len(report.Replacements), path)
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// notifyScenario creates the notification sinks of a scenario, failing the scenario before its run when one is not
+// configured, and notifies them of its outcome once the scenario ended, subtests included. Failed deliveries are
+// logged, they do not fail the scenario.
+func notifyScenario(t *testing.T, scenario TestUtil.Scenario) {
+	var sinks []TestUtil.NotificationSink
+	for _, notification := range scenario.Notify {
+		sink, err := TestUtil.NewNotificationSink(notification, os.Getenv)
+		require.NoError(t, err, "Failed to configure the notifications of scenario %s", scenario.Name)
+		sinks = append(sinks, sink)
+	}
+	if len(sinks) == 0 {
+		return
+	}
+
+	start := time.Now()
+	t.Cleanup(func() {
+		notification := TestUtil.Notification{
+			Scenario:  scenario.Name,
+			Succeeded: !t.Failed(),
+			Duration:  time.Since(start),
+			URL:       os.Getenv("NOTIFY_JOB_URL"),
+		}
+		for i, sink := range sinks {
+			if !scenario.Notify[i].Applies(notification.Succeeded) {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			err := sink.Notify(ctx, notification)
+			cancel()
+			if err != nil {
+				t.Logf("WARNING: failed to notify %s of scenario %s: %v", sink.Name(), scenario.Name, err)
+				continue
+			}
+			t.Logf("Notified %s: %s", sink.Name(), notification.Title())
+		}
+	})
+}
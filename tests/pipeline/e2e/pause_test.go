@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/runcontrol"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPipelineRunPause pauses the training of a run by suspending its PyTorchJob, as run-control does to free the
+// GPUs of a shared cluster, checks the job stays without pods while paused and that the run succeeds once resumed
+func TestPipelineRunPause(t *testing.T) {
+	if os.Getenv("ENABLE_PAUSE_TEST") != "true" {
+		t.Skip("Skipping pause test. Set ENABLE_PAUSE_TEST=true to enable.")
+	}
+	config := loadPipelineTestConfig(t)
+	acquireGPULease(t)
+	namespace := pipelineNamespace(t)
+	client := TestUtil.NewKubeClient(t)
+	dynamicClient := TestUtil.NewDynamicClient(t)
+
+	pause := 10 * time.Minute
+	if value := os.Getenv("PAUSE_DURATION"); value != "" {
+		var err error
+		pause, err = time.ParseDuration(value)
+		require.NoError(t, err, "PAUSE_DURATION must be a duration, e.g. 10m")
+	}
+
+	overrides := evalParameterOverrides(t)
+	prepareRuns(t, config, overrides)
+	run := startPipeline(t, config, overrides)
+
+	pod, err := TestUtil.WaitForRunningTrainingPod(t, client, namespace, run.runID, config.runTimeout)
+	require.NoError(t, err, "Training did not start")
+	job := pod.Labels[TestUtil.TrainingJobNameLabel]
+
+	t.Logf("Pausing PyTorchJob %s for %s...", job, pause)
+	require.NoError(t, runcontrol.Pause(context.Background(), dynamicClient, namespace, job, time.Now()))
+	err = TestUtil.WaitForTrainingJobPodsDeleted(client, namespace, job, 5*time.Minute)
+	require.NoError(t, err, "The paused PyTorchJob did not free its GPUs")
+
+	// The paused job must stay without pods for the whole pause
+	deadline := time.After(pause)
+	tick := time.Tick(time.Minute)
+	for paused := true; paused; {
+		select {
+		case <-deadline:
+			paused = false
+		case <-tick:
+			pods, err := TestUtil.ListTrainingJobPods(client, namespace, job)
+			require.NoError(t, err)
+			require.Empty(t, pods, "Pods of the paused PyTorchJob %s were recreated", job)
+		}
+	}
+
+	t.Logf("Resuming PyTorchJob %s...", job)
+	require.NoError(t, runcontrol.Resume(context.Background(), dynamicClient, namespace, job, time.Now()))
+	err = TestUtil.WaitForPipelineSuccessWithin(t, config.pipelineServerURL, run.runID, config.bearerToken, config.runTimeout)
+	require.NoError(t, err, "Pipeline did not complete successfully after the pause")
+	t.Logf("Pipeline run %s completed after a pause of %s", run.runID, pause)
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNamespaceAdminPersona runs the pipeline and the enabled checks as a namespace-admin, a customer who cannot
+// create cluster-scoped resources, validating the namespace-scoped RBAC mode end to end
+func TestNamespaceAdminPersona(t *testing.T) {
+	if os.Getenv("ENABLE_NAMESPACE_ADMIN_TEST") != "true" {
+		t.Skip("Skipping namespace-admin persona test. Set ENABLE_NAMESPACE_ADMIN_TEST=true to enable.")
+	}
+	config := loadPipelineTestConfig(t)
+	acquireGPULease(t)
+	namespace := pipelineNamespace(t)
+
+	// The persona is set up with the cluster-admin kubeconfig
+	adminConfig := TestUtil.NewKubeConfig(t)
+	adminClient := TestUtil.NewKubeClient(t)
+	persona := TestUtil.CreateNamespaceAdminPersona(t, adminClient, namespace, 4*time.Hour)
+	t.Cleanup(func() { TestUtil.DeleteNamespaceAdminPersona(t, adminClient, persona) })
+	t.Logf("Running as namespace-admin service account %s", persona.ServiceAccount)
+
+	kubeconfig, err := TestUtil.PersonaKubeconfig(adminConfig, persona)
+	require.NoError(t, err, "Failed to render the namespace-admin kubeconfig")
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(path, kubeconfig, 0o600), "Failed to write the namespace-admin kubeconfig")
+
+	// Every client created from here on, by the suite helpers too, authenticates as the persona
+	t.Setenv("KUBECONFIG", path)
+	for _, problem := range TestUtil.CheckAccess(t, TestUtil.NewKubeClient(t), TestUtil.NamespaceAdminAccessChecks(namespace)) {
+		t.Errorf("Namespace-admin access: %s", problem)
+	}
+
+	config.bearerToken = persona.Token
+	runPipeline(t, config, evalParameterOverrides(t))
+}
+
+// TestTeamPersona runs the pipeline as the service account of a team holding only the team RBAC of cmd/team-rbac,
+// validating the generated manifest customers apply is enough to run the workflows
+func TestTeamPersona(t *testing.T) {
+	if os.Getenv("ENABLE_TEAM_PERSONA_TEST") != "true" {
+		t.Skip("Skipping team persona test. Set ENABLE_TEAM_PERSONA_TEST=true to enable.")
+	}
+	config := loadPipelineTestConfig(t)
+	acquireGPULease(t)
+	namespace := pipelineNamespace(t)
+
+	adminConfig := TestUtil.NewKubeConfig(t)
+	adminClient := TestUtil.NewKubeClient(t)
+	persona := TestUtil.CreateTeamPersona(t, adminClient, namespace, "test-team", 4*time.Hour)
+	t.Cleanup(func() { TestUtil.DeleteTeamPersona(t, adminClient, persona) })
+	t.Logf("Running as team service account %s", persona.ServiceAccount)
+
+	kubeconfig, err := TestUtil.PersonaKubeconfig(adminConfig, persona)
+	require.NoError(t, err, "Failed to render the team kubeconfig")
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(path, kubeconfig, 0o600), "Failed to write the team kubeconfig")
+
+	t.Setenv("KUBECONFIG", path)
+	for _, problem := range TestUtil.CheckAccess(t, TestUtil.NewKubeClient(t), TestUtil.TeamAccessChecks(namespace)) {
+		t.Errorf("Team access: %s", problem)
+	}
+
+	config.bearerToken = persona.Token
+	runPipeline(t, config, evalParameterOverrides(t))
+}
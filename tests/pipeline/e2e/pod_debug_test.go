@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TestPipelineRunPodDebug inspects a training pod while the run trains: the GPUs nvidia-smi lists in it must match the
+// GPUs it requested, and its NCCL variables those of POD_DEBUG_NCCL_ENV. With JUDGE_INFERENCE_SERVICE, the judge is
+// reached through a port-forward to its pod.
+func TestPipelineRunPodDebug(t *testing.T) {
+	if os.Getenv("ENABLE_POD_DEBUG_TEST") != "true" {
+		t.Skip("Skipping pod debug test. Set ENABLE_POD_DEBUG_TEST=true to enable.")
+	}
+	var expectedNCCL map[string]string
+	if value := os.Getenv("POD_DEBUG_NCCL_ENV"); value != "" {
+		var err error
+		expectedNCCL, err = TestUtil.ParseEnvList(value)
+		require.NoError(t, err, "Invalid POD_DEBUG_NCCL_ENV")
+	}
+
+	config := loadPipelineTestConfig(t)
+	acquireGPULease(t)
+	namespace := pipelineNamespace(t)
+	client := TestUtil.NewKubeClient(t)
+
+	if judge := os.Getenv("JUDGE_INFERENCE_SERVICE"); judge != "" {
+		checkJudgeThroughPortForward(t, namespace, judge)
+	}
+
+	overrides := evalParameterOverrides(t)
+	prepareRuns(t, config, overrides)
+	run := startPipeline(t, config, overrides)
+
+	pod, output, err := TestUtil.ExecInTrainingPod(t, client, namespace, run.runID, config.runTimeout, TestUtil.NvidiaSMIQuery...)
+	require.NoError(t, err, "Failed to run nvidia-smi in a training pod")
+	gpus, err := TestUtil.ParseNvidiaSMI(output)
+	require.NoError(t, err)
+	gpuResource, _ := TestUtil.TrainingPlacement(run.params)
+	requested := TestUtil.PodGPURequests([]corev1.Pod{pod}, gpuResource)[pod.Name]
+	require.Len(t, gpus, int(requested), "Training pod %s sees %d GPUs, requested %d of %s", pod.Name, len(gpus), requested, gpuResource)
+
+	output, err = TestUtil.ExecInPod(t, client, namespace, pod.Name, TestUtil.TrainingContainerName, "env")
+	require.NoError(t, err, "Failed to read the environment of training pod %s", pod.Name)
+	nccl := TestUtil.NCCLEnv(output)
+	for _, problem := range TestUtil.CheckEnv(nccl, expectedNCCL) {
+		t.Errorf("Training pod %s: %s", pod.Name, problem)
+	}
+
+	report := TestUtil.RenderPodDebug(pod.Name, gpus, nccl)
+	path := TestUtil.WriteArtifact(t, "pod-debug.md", []byte(report))
+	t.Logf("Training pod state, written to %s:\n\n%s", path, report)
+
+	err = TestUtil.WaitForPipelineSuccessWithin(t, config.pipelineServerURL, run.runID, config.bearerToken, config.runTimeout)
+	require.NoError(t, err, "Pipeline did not complete successfully")
+}
+
+// checkJudgeThroughPortForward lists the models of the judge through a port-forward to a running predictor pod of its
+// InferenceService, on the first port of its first container
+func checkJudgeThroughPortForward(t *testing.T, namespace, name string) {
+	var judge *corev1.Pod
+	for _, pod := range TestUtil.GetInferenceServicePods(t, TestUtil.NewKubeClient(t), namespace, name) {
+		if pod.Status.Phase == corev1.PodRunning {
+			judge = &pod
+			break
+		}
+	}
+	require.NotNil(t, judge, "No running pod of InferenceService %s", name)
+	port := 8080
+	if ports := judge.Spec.Containers[0].Ports; len(ports) > 0 {
+		port = int(ports[0].ContainerPort)
+	}
+
+	local, err := TestUtil.PortForwardToPod(t, TestUtil.NewKubeClient(t), namespace, judge.Name, port)
+	require.NoError(t, err)
+	client := &http.Client{Timeout: 30 * time.Second}
+	response, err := client.Get(fmt.Sprintf("http://localhost:%d/v1/models", local))
+	require.NoError(t, err, "Failed to reach the judge through the port-forward to pod %s", judge.Name)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode, "The judge pod %s did not list its models", judge.Name)
+	t.Logf("Judge pod %s answered on port %d", judge.Name, port)
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// watchPVCBinding warns about the PVCs of the run still Pending after PVC_PENDING_ALERT, and fails and terminates the
+// run once one is still Pending after PVC_PENDING_TIMEOUT, instead of waiting for the pods mounting it until the run
+// timeout
+func watchPVCBinding(t *testing.T, run pipelineRun) func() {
+	alertAfter, failAfter := 2*time.Minute, 10*time.Minute
+	if value := os.Getenv("PVC_PENDING_ALERT"); value != "" {
+		var err error
+		alertAfter, err = time.ParseDuration(value)
+		require.NoError(t, err, "PVC_PENDING_ALERT must be a duration")
+	}
+	if value := os.Getenv("PVC_PENDING_TIMEOUT"); value != "" {
+		var err error
+		failAfter, err = time.ParseDuration(value)
+		require.NoError(t, err, "PVC_PENDING_TIMEOUT must be a duration")
+	}
+
+	return TestUtil.WatchPendingPVCs(TestUtil.NewKubeClient(t), pipelineNamespace(t), run.start, alertAfter, failAfter, 30*time.Second, run.log.Debugf, func(alert TestUtil.PVCAlert, err error) {
+		if err != nil {
+			t.Logf("Failed to check the PVCs of run %s: %v", run.runID, err)
+			return
+		}
+		if !alert.Failed {
+			t.Logf("WARNING: pipeline run %s: %s", run.runID, alert)
+			return
+		}
+		t.Errorf("Pipeline run %s cannot start its pods: %s", run.runID, alert)
+		if err := run.server.Terminate(context.Background(), run.runID); err != nil {
+			t.Logf("Failed to terminate pipeline run %s: %v", run.runID, err)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// checkQuantizedOutput verifies the quantized output model of the run is stored in the expected format and its
+// header can be loaded in a verification pod
+func checkQuantizedOutput(t *testing.T, run pipelineRun) {
+	format, _ := run.params["output_quantization"].(string)
+	require.NotEmpty(t, format, "The quantized output check requires OUTPUT_QUANTIZATION")
+	require.NotEmpty(t, run.runPrefix, "The quantized output check requires ENABLE_RUN_PREFIX=true")
+	t.Logf("Checking the %s output model...", format)
+
+	store, err := TestUtil.NewArtifactStoreFromEnv()
+	require.NoError(t, err, "Failed to create artifact store")
+	keys, err := TestUtil.ListObjectKeys(store, run.runPrefix)
+	require.NoError(t, err, "Failed to list the run artifacts")
+	key, err := TestUtil.FindQuantizedArtifact(keys, format)
+	require.NoError(t, err, "Quantized output model not found under %s", run.runPrefix)
+
+	artifactURL, err := store.SignedURL(context.Background(), key, time.Hour)
+	require.NoError(t, err, "Failed to sign a URL for %s", key)
+
+	image := os.Getenv("QUANTIZED_VERIFY_IMAGE")
+	if image == "" {
+		image = TestUtil.LoadImageMatrix(t, "../e2e/resources/image_matrix.yaml").Baseline.TrainingImage
+	}
+	report, err := TestUtil.VerifyQuantizedArtifact(t, TestUtil.NewKubeClient(t), pipelineNamespace(t), image, artifactURL, format, 10*time.Minute)
+	require.NoError(t, err, "Quantized output model %s is not loadable", key)
+	t.Logf("Quantized output model %s is loadable: %s", key, describeQuantizedReport(report))
+}
+
+func describeQuantizedReport(report TestUtil.QuantizedReport) string {
+	if report.Format == TestUtil.QuantizedFormatGGUF {
+		return fmt.Sprintf("GGUF v%d with %d tensors", report.Version, report.Tensors)
+	}
+	return fmt.Sprintf("safetensors with %d of %d tensors in INT8", report.Int8Tensors, report.Tensors)
+}
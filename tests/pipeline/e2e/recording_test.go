@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/recorder"
+	"github.com/stretchr/testify/require"
+)
+
+// recordModelEndpoints puts a recording proxy in front of the teacher and judge endpoints of the run and points
+// the run at secrets targeting the proxies. The recorded exchanges, the token usage and the validation of the judge
+// responses are written to the artifacts directory at the end of the test.
+func recordModelEndpoints(t *testing.T, overrides map[string]interface{}) {
+	image := os.Getenv("RECORDING_PROXY_IMAGE")
+	require.NotEmpty(t, image, "RECORDING_PROXY_IMAGE environment variable must be set")
+	sampleRate := 0.1
+	if value := os.Getenv("RECORDING_SAMPLE_RATE"); value != "" {
+		var err error
+		sampleRate, err = strconv.ParseFloat(value, 64)
+		require.NoError(t, err, "RECORDING_SAMPLE_RATE must be a number")
+	}
+
+	client := TestUtil.NewKubeClient(t)
+	namespace := pipelineNamespace(t)
+	proxies := map[string]string{}
+	t.Cleanup(func() {
+		usage := map[string]recorder.Usage{}
+		for role, name := range proxies {
+			recordings := TestUtil.CollectRecordings(t, client, namespace, name)
+			path := TestUtil.WriteArtifact(t, fmt.Sprintf("recordings-%s.jsonl", role), recordings)
+			t.Logf("Recorded %s exchanges written to %s", role, path)
+			if role == "judge" {
+				validateJudgeResponses(t, recordings)
+			}
+			usage[role] = TestUtil.CollectTokenUsage(t, client, namespace, name)
+			TestUtil.DeleteRecordingProxy(t, client, namespace, name)
+		}
+		if len(usage) > 0 {
+			path := TestUtil.WriteArtifact(t, "token-usage.md", []byte(TestUtil.RenderTokenUsage(usage)))
+			t.Logf("Token usage written to %s", path)
+		}
+	})
+
+	for param, secretName := range modelServerSecretNames(t, overrides) {
+		role := modelServerSecretParams[param]
+		secret := TestUtil.GetModelServerSecret(t, client, namespace, secretName)
+		target, _, err := TestUtil.ProxiedEndpoint(secret.Endpoint, "")
+		require.NoError(t, err, "Invalid %s endpoint", role)
+
+		name := TestUtil.ResourcePrefix() + "recording-" + role
+		proxies[role] = name
+		t.Logf("Deploying recording proxy for the %s at %s...", role, target)
+		proxyURL := TestUtil.DeployRecordingProxy(t, client, TestUtil.RecordingProxyConfig{
+			Name:               name,
+			Namespace:          namespace,
+			Image:              image,
+			Target:             target,
+			SampleRate:         sampleRate,
+			InsecureSkipVerify: os.Getenv("RECORDING_PROXY_INSECURE_SKIP_VERIFY") == "true",
+			DNS:                podDNS(t),
+		}, 5*time.Minute)
+
+		_, endpoint, _ := TestUtil.ProxiedEndpoint(secret.Endpoint, proxyURL)
+		secret.Endpoint = endpoint
+		TestUtil.CreateModelServerSecret(t, client, namespace, name+"-secret", secret)
+		overrides[param] = name + "-secret"
+	}
+}
+
+// validateJudgeResponses writes the validation of the sampled judge responses against the MT-Bench scoring schema
+// and flags the judgments without a valid rating, which explain low MT-Bench scores
+func validateJudgeResponses(t *testing.T, recordings []byte) {
+	report, err := TestUtil.ValidateJudgeResponses(recordings)
+	if err != nil {
+		t.Logf("Failed to validate the judge responses: %v", err)
+		return
+	}
+	path := TestUtil.WriteArtifact(t, "judge-responses.md", []byte(TestUtil.RenderJudgeResponses(report)))
+	t.Logf("Judge response validation written to %s", path)
+	if report.Flagged() > 0 {
+		t.Logf("WARNING: %d of %d sampled judge responses are malformed or refusals (%d malformed, %d refusals), MT-Bench scores them without a rating",
+			report.Flagged(), report.Total(), report.Counts[TestUtil.JudgeResponseMalformed], report.Counts[TestUtil.JudgeResponseRefusal])
+	}
+}
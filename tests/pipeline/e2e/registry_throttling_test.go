@@ -0,0 +1,60 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// watchRegistryThrottling recreates the training pods of the run throttled pulling their image after
+// REGISTRY_RETRY_BACKOFF, doubled on every retry, at most REGISTRY_RETRY_LIMIT times. A pod still throttled after the
+// retries fails and terminates the run. Task pods are only logged, as Argo would not recreate them.
+func watchRegistryThrottling(t *testing.T, run pipelineRun) func() {
+	policy := TestUtil.PullRetryPolicy{MaxRetries: 3, Backoff: time.Minute}
+	if value := os.Getenv("REGISTRY_RETRY_LIMIT"); value != "" {
+		var err error
+		policy.MaxRetries, err = strconv.Atoi(value)
+		require.NoError(t, err, "REGISTRY_RETRY_LIMIT must be a number of retries")
+	}
+	if value := os.Getenv("REGISTRY_RETRY_BACKOFF"); value != "" {
+		var err error
+		policy.Backoff, err = time.ParseDuration(value)
+		require.NoError(t, err, "REGISTRY_RETRY_BACKOFF must be a duration")
+	}
+
+	return TestUtil.WatchRegistryThrottling(TestUtil.NewKubeClient(t), pipelineNamespace(t), run.runID, policy, 30*time.Second, func(retry TestUtil.PullRetry, err error) {
+		if err != nil {
+			t.Logf("Failed to check the image pulls of run %s: %v", run.runID, err)
+			return
+		}
+		if !retry.Exhausted {
+			t.Logf("WARNING: pipeline run %s: %s", run.runID, retry)
+			return
+		}
+		t.Errorf("Pipeline run %s: %s", run.runID, retry)
+		if err := run.server.Terminate(context.Background(), run.runID); err != nil {
+			t.Logf("Failed to terminate pipeline run %s: %v", run.runID, err)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"os"
+	"testing"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPipelineRerun runs the pipeline a second time with the same parameters after a successful run, as users re-run
+// commands, and checks the second run either completes or fails with a clear "already exists" message
+func TestPipelineRerun(t *testing.T) {
+	if os.Getenv("ENABLE_RERUN_TEST") != "true" {
+		t.Skip("Skipping rerun test. Set ENABLE_RERUN_TEST=true to enable.")
+	}
+	config := loadPipelineTestConfig(t)
+	acquireGPULease(t)
+
+	first := runPipeline(t, config, evalParameterOverrides(t))
+
+	pipelineID, err := TestUtil.RetrievePipelineId(t, config.pipelineServerURL, config.pipelineDisplayName, config.bearerToken)
+	require.NoError(t, err, "Failed to retrieve pipeline ID")
+	runID, err := TestUtil.TriggerPipeline(t, config.pipelineServerURL, pipelineID, config.pipelineDisplayName, first.params, config.bearerToken)
+	require.NoError(t, err, "Failed to trigger the second run")
+	t.Logf("Second run %s of the same parameters as run %s started....", runID, first.runID)
+
+	details, err := TestUtil.WaitForPipelineCompletion(t, config.pipelineServerURL, runID, config.bearerToken)
+	require.NoError(t, err, "Second run did not complete")
+	require.NoError(t, TestUtil.CheckIdempotentRerun(details), "Second run is not idempotent")
+	if details.State == "SUCCEEDED" {
+		t.Logf("Second run succeeded, reusing the outputs of %d tasks: %v", len(details.CachedTasks()), details.CachedTasks())
+	} else {
+		t.Logf("Second run failed cleanly: %v", details.FailureMessages())
+	}
+}
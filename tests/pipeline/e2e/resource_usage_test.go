@@ -0,0 +1,36 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+)
+
+// watchResourceUsage samples the CPU and memory usage of the run pods by phase until the returned stop function is
+// called, which writes the peak and total usage to resource-usage.md in the artifacts directory
+func watchResourceUsage(t *testing.T, runID string) (stop func()) {
+	stopSampling := TestUtil.WatchResourceUsage(TestUtil.NewKubeClient(t), pipelineNamespace(t), runID, 30*time.Second, func(err error) {
+		t.Logf("Failed to sample resource usage: %v", err)
+	})
+	return func() {
+		path := TestUtil.WriteArtifact(t, "resource-usage.md", []byte(TestUtil.RenderResourceUsage(stopSampling())))
+		t.Logf("Resource usage written to %s", path)
+	}
+}
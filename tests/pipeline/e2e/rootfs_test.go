@@ -0,0 +1,62 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+)
+
+// auditReadOnlyRootFS audits the pods of a run for hardened cluster requirements. The findings are written to the
+// artifacts directory and fail the test when READ_ONLY_ROOT_FS_ENFORCE is true. With READ_ONLY_ROOT_FS_PROBE, every
+// image of the run is also run with a read-only root filesystem and emptyDir volumes for the audited writable paths.
+func auditReadOnlyRootFS(t *testing.T, runID string) {
+	client := TestUtil.NewKubeClient(t)
+	namespace := pipelineNamespace(t)
+
+	pods := append(TestUtil.GetRunPods(t, client, namespace, runID), TestUtil.GetTrainingPods(t, client, namespace, runID)...)
+	findings := TestUtil.AuditReadOnlyRootFS(pods)
+	path := TestUtil.WriteArtifact(t, "readonly-rootfs-audit.md", []byte(TestUtil.RenderRootFSAudit(findings)))
+	t.Logf("Read-only root filesystem audit written to %s", path)
+
+	enforce := os.Getenv("READ_ONLY_ROOT_FS_ENFORCE") == "true"
+	for _, finding := range findings {
+		if finding.ReadOnlyRootFS && len(finding.UncoveredWritables) == 0 {
+			continue
+		}
+		if enforce {
+			t.Errorf("Container %s of pod %s does not meet the read-only root filesystem requirement, writable paths without a volume: %v", finding.Container, finding.Pod, finding.UncoveredWritables)
+		} else {
+			t.Logf("Container %s of pod %s would need emptyDir volumes for %v under a read-only root filesystem", finding.Container, finding.Pod, finding.UncoveredWritables)
+		}
+	}
+
+	if os.Getenv("READ_ONLY_ROOT_FS_PROBE") != "true" {
+		return
+	}
+	for image, writables := range TestUtil.ImageWritablePaths(findings) {
+		pod := TestUtil.ReadOnlyRootFSProbePod(TestUtil.GenerateName("rootfs-probe"), image, writables)
+		if err := TestUtil.RunReadOnlyRootFSProbe(t, client, namespace, pod, 10*time.Minute); err != nil {
+			t.Errorf("Image %s does not run with a read-only root filesystem and emptyDir volumes for %v: %v", image, writables, err)
+		} else {
+			t.Logf("Image %s runs with a read-only root filesystem and emptyDir volumes for %v", image, writables)
+		}
+	}
+}
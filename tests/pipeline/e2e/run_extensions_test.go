@@ -0,0 +1,477 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/egressprobe"
+	"github.com/stretchr/testify/require"
+)
+
+// runExtension is an optional step of a pipeline run, enabled by its environment variable or by the checks of a
+// scenario. Prepare runs once before the runs of a test, watch from the start of every run until its completion,
+// check after every successful run and failed after every failed run. Capabilities are the optional permissions the
+// extension degrades without.
+type runExtension struct {
+	name         string
+	env          string
+	capabilities []string
+	prepare      func(t *testing.T, overrides map[string]interface{})
+	watch        func(t *testing.T, run pipelineRun) (stop func())
+	check        func(t *testing.T, run pipelineRun)
+	failed       func(t *testing.T, run pipelineRun)
+}
+
+// runExtensions are the optional steps of the pipeline runs, in the order they run
+var runExtensions = []runExtension{
+	{
+		// Enable the required DataScienceCluster components on fresh clusters
+		name: "dsc-setup",
+		env:  "ENABLE_DSC_SETUP",
+		prepare: func(t *testing.T, overrides map[string]interface{}) {
+			t.Logf("Enabling DataScienceCluster components %v...", TestUtil.RequiredDSCComponents)
+			dynamicClient := TestUtil.NewDynamicClient(t)
+			err := TestUtil.EnableDSCComponents(t, dynamicClient, TestUtil.RequiredDSCComponents)
+			require.NoError(t, err, "Failed to enable DataScienceCluster components")
+			err = TestUtil.WaitForDSCComponentsReady(t, dynamicClient, TestUtil.RequiredDSCComponents, 15*time.Minute)
+			require.NoError(t, err, "DataScienceCluster components did not become ready")
+			t.Log("DataScienceCluster components are ready.")
+		},
+	},
+	{
+		// Catch a broken Training Operator before committing to the full run
+		name: "training-preflight",
+		env:  "ENABLE_TRAINING_PREFLIGHT",
+		prepare: func(t *testing.T, overrides map[string]interface{}) {
+			t.Log("Running Training Operator preflight...")
+			client := TestUtil.NewKubeClient(t)
+			settings := TestUtil.ResolveProductSettings(t, client)
+			err := TestUtil.TrainingOperatorPreflight(t, client, TestUtil.NewDynamicClient(t), settings, pipelineNamespace(t))
+			require.NoError(t, err, "Training Operator preflight failed")
+			t.Log("Training Operator preflight passed.")
+		},
+	},
+	{
+		// Catch driver/CUDA mismatches of the training image before committing to the full run
+		name:    "cuda-preflight",
+		env:     "ENABLE_CUDA_PREFLIGHT",
+		prepare: runCUDAPreflight,
+	},
+	{
+		// Warn about storage too slow for checkpointing before committing to the full run
+		name:    "storage-preflight",
+		env:     "ENABLE_STORAGE_PREFLIGHT",
+		prepare: runStoragePreflight,
+	},
+	{
+		// Measure the egress to the bucket, a hidden cause of slow runs
+		name:    "object-store-preflight",
+		env:     "ENABLE_OBJECT_STORE_PREFLIGHT",
+		prepare: runObjectStorePreflight,
+	},
+	{
+		// Report the source addresses firewalls must allow and check the endpoints are reachable from the cluster
+		name:    "egress-preflight",
+		env:     "ENABLE_EGRESS_PREFLIGHT",
+		prepare: runEgressPreflight,
+	},
+	{
+		// Run on the embedded demo taxonomy and walk the audience through the run
+		name:    "demo",
+		env:     "ENABLE_DEMO_TAXONOMY",
+		prepare: serveDemoTaxonomy,
+		check:   printDemoSummary,
+	},
+	{
+		// Serve the judge without KServe
+		name: "raw-judge",
+		env:  "ENABLE_RAW_JUDGE",
+		prepare: func(t *testing.T, overrides map[string]interface{}) {
+			overrides["eval_judge_secret"] = deployRawJudge(t)
+		},
+	},
+	{
+		// Synthesize the judge secret from the InferenceService serving the judge
+		name: "kserve-judge",
+		env:  "ENABLE_KSERVE_JUDGE_DISCOVERY",
+		prepare: func(t *testing.T, overrides map[string]interface{}) {
+			overrides["eval_judge_secret"] = discoverKServeJudge(t)
+		},
+	},
+	{
+		// Serve the teacher and the judge from one model server
+		name:    "shared-endpoint",
+		env:     "ENABLE_SHARED_ENDPOINT",
+		prepare: prepareSharedEndpoint,
+		check:   checkSharedSecret,
+	},
+	{
+		// Check the run fits the GPUs left by the in-cluster judge, e.g. on small clusters sharing GPUs by time-slicing
+		name:    "gpu-sharing",
+		env:     "ENABLE_GPU_SHARING_CHECK",
+		prepare: checkGPUFit,
+		check:   checkRunGPURequests,
+	},
+	{
+		// Capture sampled teacher and judge exchanges for debugging
+		name:    "recording-proxy",
+		env:     "ENABLE_RECORDING_PROXY",
+		prepare: recordModelEndpoints,
+	},
+	{
+		// Keep the logs of deleted and evicted pods
+		name:    "log-retention",
+		env:     "ENABLE_LOG_RETENTION",
+		prepare: retainRunLogs,
+	},
+	{
+		// Fail fast on PVCs of the run which are never bound
+		name:         "pvc-watchdog",
+		env:          "ENABLE_PVC_WATCHDOG",
+		capabilities: []string{TestUtil.EventsCapability},
+		watch:        watchPVCBinding,
+	},
+	{
+		// Recreate the training pods throttled by the image registry instead of waiting for the pull backoff
+		name:         "registry-retry",
+		env:          "ENABLE_REGISTRY_RETRY",
+		capabilities: []string{TestUtil.EventsCapability},
+		watch:        watchRegistryThrottling,
+	},
+	{
+		// Annotate the run pods with the current phase while waiting
+		name: "phase-annotations",
+		env:  "ENABLE_PHASE_ANNOTATIONS",
+		watch: func(t *testing.T, run pipelineRun) func() {
+			return TestUtil.WatchRunPhase(TestUtil.NewKubeClient(t), pipelineNamespace(t), run.runID, time.Minute, func(phase TestUtil.RunPhase, err error) {
+				if err != nil {
+					t.Logf("Failed to annotate run phase: %v", err)
+					return
+				}
+				t.Logf("Pipeline run %s is in phase %s (%d%%)", run.runID, phase.Phase, phase.Percent)
+			})
+		},
+	},
+	{
+		// Estimate the completion of every phase from the history of the runs and flag the phases taking too long
+		name:  "eta",
+		env:   "ENABLE_ETA",
+		watch: watchETA,
+		check: recordRunHistory,
+	},
+	{
+		// Account the resource usage of every phase for quota sizing
+		name:         "resource-usage",
+		env:          "ENABLE_RESOURCE_USAGE",
+		capabilities: []string{TestUtil.PodMetricsCapability},
+		watch: func(t *testing.T, run pipelineRun) func() {
+			return watchResourceUsage(t, run.runID)
+		},
+	},
+	{
+		// Catch teacher and judge credentials expiring during the run
+		name: "endpoint-drift",
+		env:  "ENABLE_ENDPOINT_DRIFT_CHECK",
+		watch: func(t *testing.T, run pipelineRun) func() {
+			return watchEndpointDrift(t, run.runID, run.params)
+		},
+	},
+	{
+		name: "api-budget",
+		env:  "ENABLE_API_BUDGET_CHECK",
+		check: func(t *testing.T, run pipelineRun) {
+			checkAPIBudget(t, run.runID, run.start, time.Now())
+		},
+	},
+	{
+		name: "scheduling-latency",
+		env:  "ENABLE_SCHEDULING_LATENCY",
+		check: func(t *testing.T, run pipelineRun) {
+			measureSchedulingLatency(t, run.runID)
+		},
+	},
+	{
+		name: "read-only-root-fs-audit",
+		env:  "ENABLE_READ_ONLY_ROOT_FS_AUDIT",
+		check: func(t *testing.T, run pipelineRun) {
+			auditReadOnlyRootFS(t, run.runID)
+		},
+	},
+	{
+		// Find the resources of the run which dropped its labels, which tooling keyed on labels would miss
+		name: "label-propagation",
+		env:  "ENABLE_LABEL_PROPAGATION_CHECK",
+		check: func(t *testing.T, run pipelineRun) {
+			checkRunLabels(t, run.runID, run.start, pipelineRunLabels(t, run.runID), false)
+		},
+	},
+	{
+		// Record the image digests of the run for reproducibility
+		name: "image-digests",
+		env:  "ENABLE_IMAGE_DIGEST_REPORT",
+		check: func(t *testing.T, run pipelineRun) {
+			reportImageDigests(t, run.runID)
+		},
+	},
+	{
+		// Certify the workloads created by the run against the policy rules
+		name: "policy",
+		env:  "ENABLE_POLICY_CHECKS",
+		check: func(t *testing.T, run pipelineRun) {
+			t.Log("Checking pipeline run workloads against policy rules...")
+			rules := TestUtil.LoadPolicyRules(t, "../e2e/resources/policy_rules.yaml")
+			violations := TestUtil.CheckRunPolicies(t, TestUtil.NewKubeClient(t), pipelineNamespace(t), run.runID, rules)
+			for _, violation := range violations {
+				t.Errorf("Policy violation: %s", violation)
+			}
+		},
+	},
+	{
+		// Verify the eval knobs reached the eval tasks
+		name:  "eval-params",
+		env:   "ENABLE_EVAL_PARAMS_CHECK",
+		check: checkEvalParameters,
+	},
+	{
+		// Verify the training phases ran the requested epochs rather than a silent default
+		name:    "training-epochs",
+		env:     "ENABLE_TRAINING_EPOCHS_CHECK",
+		prepare: setTrainingEpochs,
+		check:   checkTrainingEpochs,
+	},
+	{
+		// Verify the generated dataset is complete and well formed
+		name:  "sdg-dataset",
+		env:   "ENABLE_SDG_DATASET_CHECK",
+		check: checkSDGDataset,
+	},
+	{
+		// Catch a teacher endpoint producing degenerate repeated samples
+		name:  "sdg-dedup",
+		env:   "ENABLE_SDG_DEDUP_CHECK",
+		check: checkSDGDuplicates,
+	},
+	{
+		// Report personal data and toxic content in a sample of the generated dataset
+		name:  "sdg-screen",
+		env:   "ENABLE_SDG_SCREEN",
+		check: screenSDGDataset,
+	},
+	{
+		// Show content authors the samples generated for each of their taxonomy leaves
+		name:  "sdg-coverage",
+		env:   "ENABLE_SDG_COVERAGE_REPORT",
+		check: reportSDGCoverage,
+	},
+	{
+		// Verify no taxonomy leaf was silently skipped by SDG
+		name:  "seed-examples",
+		env:   "ENABLE_SEED_EXAMPLE_CHECK",
+		check: checkSeedExamples,
+	},
+	{
+		// Verify the quantized output model is loadable
+		name:  "quantized-output",
+		env:   "ENABLE_QUANTIZED_OUTPUT_CHECK",
+		check: checkQuantizedOutput,
+	},
+	{
+		// Sign the run artifacts with their provenance once every other check passed
+		name:  "artifact-signing",
+		env:   "ENABLE_ARTIFACT_SIGNING",
+		check: signRunArtifacts,
+	},
+	{
+		// Save the back-and-forth of asking for the logs and YAML of a product failure
+		name:   "bug-report",
+		env:    "ENABLE_BUG_REPORT",
+		failed: writeBugReport,
+	},
+}
+
+// enabledRunExtensions returns the run extensions enabled by their environment variable or by the configuration
+func enabledRunExtensions(config pipelineTestConfig) []runExtension {
+	var enabled []runExtension
+	for _, extension := range runExtensions {
+		if os.Getenv(extension.env) == "true" || config.extensions[extension.name] {
+			enabled = append(enabled, extension)
+		}
+	}
+	return enabled
+}
+
+// prepareRuns runs the prepare steps of the enabled run extensions, once before the runs of a test
+func prepareRuns(t *testing.T, config pipelineTestConfig, overrides map[string]interface{}) {
+	logCapabilities(t, enabledRunExtensions(config))
+	for _, extension := range enabledRunExtensions(config) {
+		if extension.prepare != nil {
+			extension.prepare(t, overrides)
+		}
+	}
+}
+
+// logCapabilities logs which optional permissions of the enabled run extensions the suite holds. The extensions
+// continue without the missing ones, with less information.
+func logCapabilities(t *testing.T, extensions []runExtension) {
+	users := map[string][]string{}
+	for _, extension := range extensions {
+		for _, capability := range extension.capabilities {
+			users[capability] = append(users[capability], extension.name)
+		}
+	}
+	if len(users) == 0 {
+		return
+	}
+	capabilities := TestUtil.ObservabilityCapabilities(pipelineNamespace(t))
+	matrix, err := TestUtil.ProbeCapabilities(TestUtil.NewKubeClient(t), capabilities)
+	if err != nil {
+		t.Logf("Failed to probe the capabilities of the run extensions, assuming they are held: %v", err)
+		return
+	}
+	t.Logf("Capabilities of the run extensions:\n%s", TestUtil.RenderCapabilityMatrix(capabilities, matrix, users))
+	for _, capability := range capabilities {
+		if names := users[capability.Name]; len(names) > 0 && !matrix.Has(capability.Name) {
+			t.Logf("WARNING: capability %s is missing, %v continue without it", capability.Name, names)
+		}
+	}
+}
+
+// runCUDAPreflight runs the CUDA preflight of the training image on the GPU resource and nodes of the training
+// parameters
+func runCUDAPreflight(t *testing.T, overrides map[string]interface{}) {
+	image := os.Getenv("TRAINING_IMAGE")
+	if image == "" {
+		image = TestUtil.LoadImageMatrix(t, "../e2e/resources/image_matrix.yaml").Baseline.TrainingImage
+	}
+	gpuResource, nodeSelector := TestUtil.TrainingPlacement(loadPipelineParams(t, overrides))
+	if node := os.Getenv("CUDA_PREFLIGHT_NODE"); node != "" {
+		if nodeSelector == nil {
+			nodeSelector = map[string]string{}
+		}
+		nodeSelector["kubernetes.io/hostname"] = node
+	}
+	t.Logf("Running CUDA preflight in %s on %s nodes %v...", image, gpuResource, nodeSelector)
+	report, err := TestUtil.RunCUDAPreflight(t, TestUtil.NewKubeClient(t), pipelineNamespace(t), image, gpuResource, nodeSelector, 15*time.Minute)
+	require.NoError(t, err, "CUDA preflight failed")
+	t.Logf("CUDA preflight passed: torch %s (CUDA %s) on %s with driver %s (CUDA %s)", report.TorchVersion, report.TorchCUDA, report.Device, report.DriverVersion, report.DriverCUDA)
+}
+
+// runStoragePreflight runs the fio benchmark on the storage class of the run and warns about the measures below the
+// minimums of storage_preflight.yaml
+func runStoragePreflight(t *testing.T, overrides map[string]interface{}) {
+	image := os.Getenv("STORAGE_PREFLIGHT_IMAGE")
+	require.NotEmpty(t, image, "STORAGE_PREFLIGHT_IMAGE environment variable must be set to an image providing fio")
+	storageClass, _ := loadPipelineParams(t, overrides)["k8s_storage_class_name"].(string)
+	config := TestUtil.LoadStoragePreflightConfig(t, "../e2e/resources/storage_preflight.yaml")
+
+	t.Logf("Running fio storage preflight on storage class %s...", storageClass)
+	benchmark, err := TestUtil.RunStoragePreflight(t, TestUtil.NewKubeClient(t), pipelineNamespace(t), image, storageClass, config, config.Runtime*4+15*time.Minute)
+	require.NoError(t, err, "Storage preflight failed")
+	t.Logf("Storage class %s: sequential write %.0f MiB/s, sequential read %.0f MiB/s, random read %.0f IOPS, random write %.0f IOPS",
+		storageClass, benchmark.SeqWriteMiBps, benchmark.SeqReadMiBps, benchmark.RandReadIOPS, benchmark.RandWriteIOPS)
+	for _, shortfall := range benchmark.Shortfalls(config) {
+		t.Logf("WARNING: storage class %s: %s", storageClass, shortfall)
+	}
+}
+
+// runObjectStorePreflight measures the upload and download throughput between the cluster and the bucket of the object
+// store settings and writes it to the artifacts directory
+func runObjectStorePreflight(t *testing.T, overrides map[string]interface{}) {
+	image := os.Getenv("OBJECT_STORE_PROBE_IMAGE")
+	require.NotEmpty(t, image, "OBJECT_STORE_PROBE_IMAGE environment variable must be set")
+	sizeMiB := 256
+	if value := os.Getenv("OBJECT_STORE_PROBE_SIZE"); value != "" {
+		var err error
+		sizeMiB, err = strconv.Atoi(value)
+		require.NoError(t, err, "OBJECT_STORE_PROBE_SIZE must be a number of MiB")
+	}
+	config := TestUtil.ObjectStoreConfigFromEnv()
+
+	t.Logf("Probing the throughput to bucket %s from the cluster...", config.Bucket)
+	result, err := TestUtil.RunObjectStoreProbe(t, TestUtil.NewKubeClient(t), pipelineNamespace(t), image, config, sizeMiB, 30*time.Minute)
+	require.NoError(t, err, "Object store preflight failed")
+	path := TestUtil.WriteArtifact(t, "object-store-throughput.md", []byte(TestUtil.RenderObjectStoreThroughput(result, config.Bucket)))
+	t.Logf("Bucket %s: upload %.1f MiB/s, download %.1f MiB/s, report written to %s", config.Bucket, result.UploadMiBps, result.DownloadMiBps, path)
+}
+
+// runEgressPreflight probes the teacher, judge and object store endpoints from a pod of the cluster, writes the source
+// addresses to allow and the connectivity to the endpoints to the artifacts directory, and fails on unreachable
+// endpoints
+func runEgressPreflight(t *testing.T, overrides map[string]interface{}) {
+	image := os.Getenv("EGRESS_PROBE_IMAGE")
+	require.NotEmpty(t, image, "EGRESS_PROBE_IMAGE environment variable must be set")
+	client := TestUtil.NewKubeClient(t)
+	namespace := pipelineNamespace(t)
+
+	var targets []egressprobe.Target
+	for param, name := range modelServerSecretNames(t, overrides) {
+		secret := TestUtil.GetModelServerSecret(t, client, namespace, name)
+		targets = append(targets, egressprobe.Target{Name: modelServerSecretParams[param], URL: secret.Endpoint})
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Name < targets[j].Name })
+	if endpoint := TestUtil.ObjectStoreConfigFromEnv().Endpoint; endpoint != "" {
+		if !strings.Contains(endpoint, "://") {
+			endpoint = "https://" + endpoint
+		}
+		targets = append(targets, egressprobe.Target{Name: "object-store", URL: endpoint})
+	}
+
+	t.Logf("Probing the egress to %d endpoints from the cluster...", len(targets))
+	report, err := TestUtil.RunEgressProbe(t, client, TestUtil.NewDynamicClient(t), namespace, image, targets, os.Getenv("EGRESS_ECHO_URL"), podDNS(t), 10*time.Minute)
+	require.NoError(t, err, "Egress preflight failed")
+	path := TestUtil.WriteArtifact(t, "egress.md", []byte(TestUtil.RenderEgressReport(report)))
+	for _, address := range report.Addresses() {
+		t.Logf("Egress address %s: %s", address.Address, address.Source)
+	}
+	t.Logf("Egress report written to %s", path)
+	for _, target := range report.Probe.Unreachable() {
+		t.Errorf("The %s endpoint %s is not reachable from the cluster: %s", target.Name, target.URL, target.Error)
+	}
+	if t.Failed() {
+		t.FailNow()
+	}
+}
+
+// The scenario schema must offer the run extensions as checks
+func TestRunExtensionsSchema(t *testing.T) {
+	data, err := os.ReadFile("../../scenarios/schema.json")
+	require.NoError(t, err)
+	var schema struct {
+		Properties struct {
+			Checks struct {
+				Items struct {
+					Enum []string `json:"enum"`
+				} `json:"items"`
+			} `json:"checks"`
+		} `json:"properties"`
+	}
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	var names []string
+	for _, extension := range runExtensions {
+		names = append(names, extension.name)
+	}
+	require.Equal(t, names, schema.Properties.Checks.Items.Enum)
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// pipelineRunLabels returns the labels of a pipeline run: the run ID label the pipeline server sets, and the cost
+// labels when ENABLE_COST_LABELS sets them on the task pods
+func pipelineRunLabels(t *testing.T, runID string) map[string]string {
+	labels := map[string]string{TestUtil.RunIDLabel: runID}
+	if os.Getenv("ENABLE_COST_LABELS") == "true" {
+		for key, value := range TestUtil.LoadCostLabels(t, "../e2e/resources/cost_labels.yaml").Labels {
+			labels[key] = value
+		}
+	}
+	return labels
+}
+
+// checkRunLabels lists the Jobs, PyTorchJobs, PVCs and pods created since the start of a run and writes those missing
+// labels of the run, with where they were dropped, to run-labels.md in the artifacts directory. Missing labels fail
+// the test when the orchestrator is expected to propagate them and are logged otherwise.
+func checkRunLabels(t *testing.T, runID string, start time.Time, labels map[string]string, enforce bool) {
+	resources, err := TestUtil.ListRunResources(TestUtil.NewKubeClient(t), TestUtil.NewDynamicClient(t), pipelineNamespace(t), start)
+	require.NoError(t, err, "Failed to list the resources of run %s", runID)
+	drops := TestUtil.CheckRunLabels(resources, labels)
+	if len(drops) == 0 {
+		t.Logf("The %d resources of run %s carry its labels %v", len(resources), runID, labels)
+		return
+	}
+
+	path := TestUtil.WriteArtifact(t, "run-labels.md", []byte(TestUtil.RenderLabelDrops(runID, labels, drops)))
+	for _, drop := range drops {
+		if enforce {
+			t.Errorf("Run %s: %s", runID, drop)
+		} else {
+			t.Logf("WARNING: run %s: %s", runID, drop)
+		}
+	}
+	t.Logf("%d resources of run %s dropped its labels, diagnostics written to %s", len(drops), runID, path)
+}
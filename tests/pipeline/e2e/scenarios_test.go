@@ -0,0 +1,329 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestScenarios runs the pipeline once for every scenario file of tests/scenarios, so new scenarios only need a new
+// YAML file
+func TestScenarios(t *testing.T) {
+	if os.Getenv("ENABLE_SCENARIOS_TEST") != "true" {
+		t.Skip("Skipping scenarios. Set ENABLE_SCENARIOS_TEST=true to enable.")
+	}
+	config := loadPipelineTestConfig(t)
+	acquireGPULease(t)
+
+	dir := os.Getenv("SCENARIOS_DIR")
+	if dir == "" {
+		dir = "../../scenarios"
+	}
+	scenarios, err := TestUtil.LoadScenarios(dir)
+	require.NoError(t, err, "Failed to load scenarios")
+
+	// Scenario parameters must be inputs of the compiled pipeline
+	definitions, err := TestUtil.LoadPipelineInputDefinitions("../../../pipeline.yaml")
+	require.NoError(t, err, "Failed to load the compiled pipeline")
+	for _, scenario := range scenarios {
+		missing := TestUtil.MissingPipelineInputs(definitions, scenario.ParameterOverrides())
+		require.Empty(t, missing, "Scenario %s sets parameters the pipeline does not expose", scenario.Name)
+	}
+
+	selected := map[string]bool{}
+	if names := os.Getenv("SCENARIOS"); names != "" {
+		for _, name := range strings.Split(names, ",") {
+			selected[strings.TrimSpace(name)] = true
+		}
+	}
+
+	var zoneRuns []TestUtil.ZoneRun
+	for _, scenario := range scenarios {
+		scenario := scenario
+		if len(selected) > 0 && !selected[scenario.Name] {
+			continue
+		}
+		t.Run(scenario.Name, func(t *testing.T) {
+			if zoneRun := runScenario(t, config, scenario); zoneRun != nil {
+				zoneRuns = append(zoneRuns, *zoneRun)
+			}
+		})
+	}
+	if len(zoneRuns) > 0 {
+		path := TestUtil.WriteArtifact(t, "zone-comparison.md", []byte(TestUtil.RenderZoneComparison(zoneRuns)))
+		t.Logf("Comparison of the training placed by zone written to %s", path)
+	}
+}
+
+// runScenario runs the pipeline with the images, parameters and chaos actions of a scenario and checks the run
+// against its phases and thresholds. Returns the training placement and durations of a scenario placing its workers
+// by zone, nil otherwise.
+func runScenario(t *testing.T, config pipelineTestConfig, scenario TestUtil.Scenario) *TestUtil.ZoneRun {
+	if scenario.Description != "" {
+		t.Log(scenario.Description)
+	}
+	notifyScenario(t, scenario)
+	if scenario.Orchestrator != "" && scenario.Orchestrator != TestUtil.OrchestratorKFP {
+		runWorkflowScenario(t, config, scenario)
+		return nil
+	}
+
+	// Scenario images are run from a copy of the pipeline uploaded under the scenario name
+	if scenario.Images != (TestUtil.ScenarioImages{}) {
+		matrix := TestUtil.LoadImageMatrix(t, "../e2e/resources/image_matrix.yaml")
+		images := matrix.Baseline
+		if scenario.Images.SDG != "" {
+			images.SDGImage = scenario.Images.SDG
+		}
+		if scenario.Images.Training != "" {
+			images.TrainingImage = scenario.Images.Training
+		}
+		pipelineYAML, err := os.ReadFile("../../../pipeline.yaml")
+		require.NoError(t, err, "Failed to read the compiled pipeline")
+		config.pipelineDisplayName = fmt.Sprintf("%s-%s-%d", config.pipelineDisplayName, scenario.Name, time.Now().Unix())
+		uploadPipeline(t, config, config.pipelineDisplayName, TestUtil.RewritePipelineImages(pipelineYAML, matrix.Baseline, images))
+	}
+
+	// Runs are waited for beyond the duration threshold, so a slow run fails the threshold rather than the wait
+	if limit := scenario.Thresholds.MaxDuration; limit+10*time.Minute > config.runTimeout {
+		config.runTimeout = limit + 10*time.Minute
+	}
+
+	overrides := evalParameterOverrides(t)
+	for name, value := range scenario.ParameterOverrides() {
+		overrides[name] = value
+	}
+	if scenario.Zones != "" {
+		for name, value := range zonePlacementOverrides(t, scenario) {
+			overrides[name] = value
+		}
+	}
+
+	config.extensions = map[string]bool{}
+	for _, name := range scenario.Checks {
+		config.extensions[name] = true
+	}
+	prepareRuns(t, config, overrides)
+
+	// The budget must fit in the cluster before the run, so a scenario does not wait hours for capacity it cannot get
+	if !scenario.Budget.IsZero() {
+		free, err := TestUtil.GetClusterCapacity(TestUtil.NewKubeClient(t), pipelineNamespace(t), scenario.Budget.Resource())
+		require.NoError(t, err, "Failed to measure the cluster capacity")
+		problems, err := TestUtil.CheckBudgetCapacity(scenario.Budget, free)
+		require.NoError(t, err)
+		require.Empty(t, problems, "Scenario %s does not fit in the cluster", scenario.Name)
+	}
+
+	// A chaos action fails the run as the pipeline sets no retry policy, the scenario is then checked on a rerun
+	start := time.Now()
+	var disrupted *TestUtil.ChaosEvent
+	if len(scenario.Chaos) > 0 {
+		disrupted = runChaos(t, config, overrides, scenario.Chaos)
+	}
+
+	// Assertions may refer to the resource usage of the run phases, which the budget also bounds
+	var watchers []runWatcher
+	var usage TestUtil.ResourceUsage
+	if len(scenario.Assertions) > 0 || !scenario.Budget.IsZero() {
+		watchers = append(watchers, func(runID string) func() {
+			stop := TestUtil.WatchResourceUsage(TestUtil.NewKubeClient(t), pipelineNamespace(t), runID, 30*time.Second, func(err error) {
+				t.Logf("Failed to sample resource usage: %v", err)
+			})
+			return func() { usage = stop() }
+		})
+	}
+
+	run := runPipeline(t, config, overrides, watchers...)
+	duration := time.Since(start)
+	t.Logf("Scenario %s completed in %s", scenario.Name, duration.Round(time.Second))
+
+	if limit := scenario.Thresholds.MaxDuration; limit > 0 && duration > limit {
+		t.Errorf("Scenario %s took %s, more than the %s threshold", scenario.Name, duration.Round(time.Second), limit)
+	}
+
+	var tasks []TestUtil.TaskPod
+	if len(scenario.Phases) > 0 || len(scenario.Assertions) > 0 || !scenario.Budget.IsZero() || scenario.Zones != "" {
+		tasks = TestUtil.GetRunTaskPods(t, TestUtil.NewKubeClient(t), pipelineNamespace(t), run.runID)
+	}
+	for _, missing := range TestUtil.CheckScenarioPhases(tasks, scenario.Phases) {
+		t.Errorf("Scenario %s: %s", scenario.Name, missing)
+	}
+
+	if disrupted != nil {
+		rerun, err := TestUtil.GetRunDetails(t, config.pipelineServerURL, run.runID, config.bearerToken)
+		require.NoError(t, err, "Failed to retrieve the rerun details")
+		if err := TestUtil.CheckRerunAfterEviction(rerun, disrupted.Target); err != nil {
+			t.Errorf("Scenario %s did not recover from the %s of %s: %v", scenario.Name, disrupted.Action.Action, disrupted.Target.Pod.Name, err)
+		}
+	}
+
+	if rate := scenario.Thresholds.MaxSDGInvalidRowRate; rate != nil {
+		rules := TestUtil.LoadSDGDatasetRules(t, "../e2e/resources/sdg_dataset.yaml")
+		rules.MaxInvalidRowRate = *rate
+		checkSDGDatasetWithRules(t, run, rules)
+	}
+
+	if !scenario.Budget.IsZero() {
+		checkScenarioBudget(t, scenario, run, tasks, usage)
+	}
+
+	if len(scenario.Assertions) > 0 {
+		data := TestUtil.ScenarioData{Duration: duration, Phases: TestUtil.PhaseDurations(tasks), Usage: usage}
+		data.Scores = scenarioScores(t, run)
+		for _, failure := range TestUtil.EvaluateAssertions(scenario.Assertions, data) {
+			t.Errorf("Scenario %s: %s", scenario.Name, failure)
+		}
+	}
+
+	if scenario.Zones != "" {
+		return checkZonePlacement(t, scenario, run, tasks)
+	}
+	return nil
+}
+
+// zonePlacementOverrides returns the pipeline parameters placing the training workers of a scenario by zone, skipping
+// the scenario while the pipeline does not expose them
+func zonePlacementOverrides(t *testing.T, scenario TestUtil.Scenario) map[string]interface{} {
+	nodes, err := TestUtil.NewKubeClient(t).CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err, "Failed to list nodes")
+	gpuNodes := TestUtil.GPUNodesByZone(nodes.Items, TestUtil.DefaultGPUResource)
+	t.Logf("GPU nodes by zone: %v", gpuNodes)
+	overrides, err := scenario.ZonePlacementOverrides(gpuNodes)
+	require.NoError(t, err, "Scenario %s cannot place the training workers by zone", scenario.Name)
+
+	definitions, err := TestUtil.LoadPipelineInputDefinitions("../../../pipeline.yaml")
+	require.NoError(t, err, "Failed to load the compiled pipeline")
+	if missing := TestUtil.MissingPipelineInputs(definitions, overrides); len(missing) > 0 {
+		t.Skipf("Skipping scenario %s, the pipeline does not expose %v yet", scenario.Name, missing)
+	}
+	return overrides
+}
+
+// checkZonePlacement checks the training pods of a scenario run were placed in one zone, or spread across zones, and
+// returns their placement with the durations of the training phases
+func checkZonePlacement(t *testing.T, scenario TestUtil.Scenario, run pipelineRun, tasks []TestUtil.TaskPod) *TestUtil.ZoneRun {
+	client := TestUtil.NewKubeClient(t)
+	pods := TestUtil.GetTrainingPods(t, client, pipelineNamespace(t), run.runID)
+	require.NotEmpty(t, pods, "No training pods found for the run")
+	nodes, err := client.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err, "Failed to list nodes")
+
+	perZone := TestUtil.PodsPerDomain(pods, nodes.Items, TestUtil.ZoneLabel, pods[0].Spec.NodeSelector)
+	switch scenario.Zones {
+	case TestUtil.ZonesSingle:
+		var used []string
+		for zone, count := range perZone {
+			if count > 0 {
+				used = append(used, zone)
+			}
+		}
+		if len(used) > 1 {
+			t.Errorf("Scenario %s: the training pods ran in zones %v, expected a single zone", scenario.Name, used)
+		}
+	case TestUtil.ZonesSpread:
+		spread := TestUtil.TopologySpread{TopologyKey: TestUtil.ZoneLabel, MaxSkew: 1, WhenUnsatisfiable: string(corev1.DoNotSchedule)}
+		for _, problem := range TestUtil.CheckTopologySpread(pods, nodes.Items, []TestUtil.TopologySpread{spread}) {
+			t.Errorf("Scenario %s: %s", scenario.Name, problem)
+		}
+	}
+
+	zoneRun := &TestUtil.ZoneRun{Scenario: scenario.Name, Zones: scenario.Zones, PodsPerZone: perZone, Phases: map[string]time.Duration{}}
+	for phase, duration := range TestUtil.PhaseDurations(tasks) {
+		if strings.HasPrefix(phase, "training-phase-") {
+			zoneRun.Phases[phase] = duration
+		}
+	}
+	t.Logf("Scenario %s: training pods per zone %v, training phases %v", scenario.Name, perZone, zoneRun.Phases)
+	return zoneRun
+}
+
+// checkScenarioBudget checks the peak resources held by a scenario run stayed within the budget of the scenario
+func checkScenarioBudget(t *testing.T, scenario TestUtil.Scenario, run pipelineRun, tasks []TestUtil.TaskPod, usage TestUtil.ResourceUsage) {
+	client := TestUtil.NewKubeClient(t)
+	var pods []corev1.Pod
+	for _, task := range tasks {
+		pods = append(pods, task.Pod)
+	}
+	trainingPods, err := TestUtil.ListTrainingPods(client, pipelineNamespace(t), pods)
+	require.NoError(t, err, "Failed to list the training pods")
+	pvcs, err := client.CoreV1().PersistentVolumeClaims(pipelineNamespace(t)).List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err, "Failed to list the PVCs")
+	if len(usage) == 0 && (scenario.Budget.CPU != "" || scenario.Budget.Memory != "") {
+		t.Logf("Scenario %s: no resource usage sampled, the CPU and memory budget is not checked", scenario.Name)
+	}
+
+	peak := TestUtil.RunPeakUsage(usage, TestUtil.RunPodPhases(tasks, trainingPods), append(pods, trainingPods...), pvcs.Items, scenario.Budget.Resource(), run.start)
+	problems, err := TestUtil.CheckBudgetUsage(scenario.Budget, peak)
+	require.NoError(t, err)
+	for _, problem := range problems {
+		t.Errorf("Scenario %s: %s", scenario.Name, problem)
+	}
+}
+
+// runChaos runs the pipeline while applying the chaos actions of a scenario, and checks the run failed with the
+// disrupted task
+func runChaos(t *testing.T, config pipelineTestConfig, overrides map[string]interface{}, actions []TestUtil.ChaosAction) *TestUtil.ChaosEvent {
+	run := startPipeline(t, config, overrides)
+	stop := TestUtil.WatchChaos(TestUtil.NewKubeClient(t), pipelineNamespace(t), run.runID, actions, 10*time.Second)
+	details, err := TestUtil.WaitForPipelineCompletionWithin(t, config.pipelineServerURL, run.runID, config.bearerToken, config.runTimeout)
+	events := stop()
+	require.NoError(t, err, "Disrupted run did not complete")
+	require.Len(t, events, len(actions), "The chaos actions were not applied before the run completed")
+
+	event := events[0]
+	require.NoError(t, event.Err, "Failed to %s %s pod %s", event.Action.Action, event.Action.Task, event.Target.Pod.Name)
+	t.Logf("Applied %s to %s pod %s", event.Action.Action, event.Action.Task, event.Target.Pod.Name)
+	require.NoError(t, TestUtil.CheckEvictionFailure(details, event.Target), "Disrupted run did not fail with the %s task", event.Action.Task)
+	return &event
+}
+
+// scenarioScores reads the scores of scenario_scores.yaml from the eval reports of the run in the artifact store. The
+// scores are left out when no artifact store is configured.
+func scenarioScores(t *testing.T, run pipelineRun) map[string]float64 {
+	rules := TestUtil.LoadScoreRules(t, "../e2e/resources/scenario_scores.yaml")
+	store, err := TestUtil.NewArtifactStoreFromEnv()
+	if err != nil {
+		t.Logf("No scores available for the assertions, the artifact store is not configured: %v", err)
+		return map[string]float64{}
+	}
+	keys, err := TestUtil.ListObjectKeys(store, run.runPrefix)
+	require.NoError(t, err, "Failed to list the run artifacts")
+
+	reports := map[string][]byte{}
+	for artifact, key := range TestUtil.ReportArtifactKeys(keys, run.runID, rules) {
+		content, err := store.Get(context.Background(), key)
+		require.NoError(t, err, "Failed to read report %s", key)
+		reports[artifact], err = io.ReadAll(content)
+		content.Close()
+		require.NoError(t, err, "Failed to read report %s", key)
+	}
+	scores, err := TestUtil.ParseScores(reports, rules)
+	require.NoError(t, err, "Failed to parse scores")
+	t.Logf("Scores: %v", scores)
+	return scores
+}
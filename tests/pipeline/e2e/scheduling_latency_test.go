@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// measureSchedulingLatency measures how long every pod of the run took to be scheduled, to start and to log, by
+// phase, and writes it with the outliers to scheduling-latency.md in the artifacts directory
+func measureSchedulingLatency(t *testing.T, runID string) {
+	factor := 3.0
+	if value := os.Getenv("SCHEDULING_LATENCY_OUTLIER_FACTOR"); value != "" {
+		var err error
+		factor, err = strconv.ParseFloat(value, 64)
+		require.NoError(t, err, "SCHEDULING_LATENCY_OUTLIER_FACTOR must be a number")
+	}
+
+	client := TestUtil.NewKubeClient(t)
+	namespace := pipelineNamespace(t)
+	tasks := TestUtil.GetRunTaskPods(t, client, namespace, runID)
+	trainingPods := TestUtil.GetTrainingPods(t, client, namespace, runID)
+	phases := TestUtil.RunPodPhases(tasks, trainingPods)
+
+	pods := TestUtil.GetRunPods(t, client, namespace, runID)
+	pods = append(pods, trainingPods...)
+	var latencies []TestUtil.PodLatency
+	for _, pod := range pods {
+		phase, ok := phases[pod.Name]
+		if !ok {
+			continue
+		}
+		firstLog, err := TestUtil.FirstLogTime(client, pod)
+		if err != nil {
+			t.Logf("Failed to read the first log line of %s: %v", pod.Name, err)
+		}
+		if latency, ok := TestUtil.MeasurePodLatency(pod, phase, firstLog); ok {
+			latencies = append(latencies, latency)
+		}
+	}
+
+	outliers := TestUtil.LatencyOutliers(latencies, factor, 2*time.Minute)
+	for _, outlier := range outliers {
+		t.Logf("Pod %s of phase %s was slow to start: %s to schedule, %s to start", outlier.Pod, outlier.Phase, outlier.Scheduling, outlier.Startup)
+	}
+	path := TestUtil.WriteArtifact(t, "scheduling-latency.md", []byte(TestUtil.RenderSchedulingLatency(latencies, outliers)))
+	t.Logf("Scheduling latency of %d pods written to %s", len(latencies), path)
+}
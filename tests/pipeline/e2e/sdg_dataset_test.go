@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// checkSDGDataset validates the dataset generated by the run and fails when the rate of invalid rows exceeds the
+// tolerated rate
+func checkSDGDataset(t *testing.T, run pipelineRun) {
+	rules := TestUtil.LoadSDGDatasetRules(t, "../e2e/resources/sdg_dataset.yaml")
+	if rate := os.Getenv("SDG_MAX_INVALID_ROW_RATE"); rate != "" {
+		value, err := strconv.ParseFloat(rate, 64)
+		require.NoError(t, err, "SDG_MAX_INVALID_ROW_RATE must be a number")
+		rules.MaxInvalidRowRate = value
+	}
+	checkSDGDatasetWithRules(t, run, rules)
+}
+
+// checkSDGDatasetWithRules validates the sdg artifact of the run, read from the artifact store, against the given rules
+func checkSDGDatasetWithRules(t *testing.T, run pipelineRun, rules TestUtil.SDGDatasetRules) {
+	t.Log("Validating the SDG dataset...")
+	store, err := TestUtil.NewArtifactStoreFromEnv()
+	require.NoError(t, err, "The SDG dataset check reads the sdg artifact from the artifact store")
+	keys, err := TestUtil.ListObjectKeys(store, run.runPrefix)
+	require.NoError(t, err, "Failed to list the run artifacts")
+
+	var stats TestUtil.SDGDatasetStats
+	for path, key := range TestUtil.SDGDatasetKeys(keys, run.runID) {
+		content, err := store.Get(context.Background(), key)
+		require.NoError(t, err, "Failed to read %s", key)
+		file, err := TestUtil.ValidateSDGDatasetFile(path, content)
+		content.Close()
+		require.NoError(t, err, "Failed to read %s", key)
+		stats.Files = append(stats.Files, file)
+	}
+	t.Logf("SDG dataset: %d files, %d rows, %d training rows, invalid row rate %.2f",
+		len(stats.Files), stats.Rows(), stats.TrainingRows(), stats.InvalidRowRate())
+
+	for _, failure := range TestUtil.CheckSDGDatasetStats(stats, rules) {
+		t.Errorf("SDG dataset check failed: %s", failure)
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// checkSDGDuplicates computes the duplicate and near duplicate rates of the training mixes generated by the run and
+// fails when they exceed the tolerated rates
+func checkSDGDuplicates(t *testing.T, run pipelineRun) {
+	rules := TestUtil.LoadSDGDedupRules(t, "../e2e/resources/sdg_dedup.yaml")
+	for env, rate := range map[string]*float64{
+		"SDG_MAX_DUPLICATE_RATE":      &rules.MaxDuplicateRate,
+		"SDG_MAX_NEAR_DUPLICATE_RATE": &rules.MaxNearDuplicateRate,
+	} {
+		if value := os.Getenv(env); value != "" {
+			parsed, err := strconv.ParseFloat(value, 64)
+			require.NoError(t, err, "%s must be a number", env)
+			*rate = parsed
+		}
+	}
+	deduplicator, err := TestUtil.NewSDGDeduplicator(rules.NearDuplicateDistance)
+	require.NoError(t, err, "Invalid SDG duplicate rules")
+
+	t.Log("Computing the duplicates of the SDG dataset...")
+	store, err := TestUtil.NewArtifactStoreFromEnv()
+	require.NoError(t, err, "The SDG duplicate check reads the sdg artifact from the artifact store")
+	keys, err := TestUtil.ListObjectKeys(store, run.runPrefix)
+	require.NoError(t, err, "Failed to list the run artifacts")
+	for path, key := range TestUtil.SDGDatasetKeys(keys, run.runID) {
+		content, err := store.Get(context.Background(), key)
+		require.NoError(t, err, "Failed to read %s", key)
+		err = deduplicator.AddFile(path, content)
+		content.Close()
+		require.NoError(t, err, "Failed to read %s", key)
+	}
+
+	stats := deduplicator.Stats()
+	t.Logf("SDG duplicates: %d training rows, duplicate rate %.2f, near duplicate rate %.2f",
+		stats.Rows, stats.DuplicateRate(), stats.NearDuplicateRate())
+	for _, failure := range TestUtil.CheckSDGDedupStats(stats, rules) {
+		t.Errorf("SDG duplicate check failed: %s", failure)
+	}
+}
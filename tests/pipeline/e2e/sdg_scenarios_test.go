@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"os"
+	"testing"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TestSDGTeacherScenarios runs the pipeline once against a teacher served in the cluster and once against a remote teacher
+func TestSDGTeacherScenarios(t *testing.T) {
+	if os.Getenv("ENABLE_SDG_SCENARIOS_TEST") != "true" {
+		t.Skip("Skipping SDG teacher scenarios. Set ENABLE_SDG_SCENARIOS_TEST=true to enable.")
+	}
+	config := loadPipelineTestConfig(t)
+	acquireGPULease(t)
+	namespace := pipelineNamespace(t)
+	client := TestUtil.NewKubeClient(t)
+
+	t.Run("in-cluster", func(t *testing.T) {
+		teacherSecret := os.Getenv("SDG_IN_CLUSTER_TEACHER_SECRET")
+		require.NotEmpty(t, teacherSecret, "SDG_IN_CLUSTER_TEACHER_SECRET environment variable must be set")
+		teacherService := os.Getenv("SDG_IN_CLUSTER_TEACHER_INFERENCE_SERVICE")
+		require.NotEmpty(t, teacherService, "SDG_IN_CLUSTER_TEACHER_INFERENCE_SERVICE environment variable must be set")
+
+		teacher := TestUtil.GetModelServerSecret(t, client, namespace, teacherSecret)
+		require.True(t, TestUtil.IsInClusterEndpoint(teacher.Endpoint), "Teacher endpoint %s is not a cluster-local address", teacher.Endpoint)
+
+		runPipeline(t, config, map[string]interface{}{"sdg_teacher_secret": teacherSecret})
+
+		pods := TestUtil.GetInferenceServicePods(t, client, namespace, teacherService)
+		require.NotEmpty(t, pods, "No teacher pods found for InferenceService %s", teacherService)
+		for _, pod := range pods {
+			require.Equal(t, corev1.PodRunning, pod.Status.Phase, "Teacher pod %s is not running", pod.Name)
+		}
+	})
+
+	t.Run("remote", func(t *testing.T) {
+		teacherSecret := os.Getenv("SDG_REMOTE_TEACHER_SECRET")
+		require.NotEmpty(t, teacherSecret, "SDG_REMOTE_TEACHER_SECRET environment variable must be set")
+
+		teacher := TestUtil.GetModelServerSecret(t, client, namespace, teacherSecret)
+		require.False(t, TestUtil.IsInClusterEndpoint(teacher.Endpoint), "Teacher endpoint %s is a cluster-local address", teacher.Endpoint)
+
+		runPipeline(t, config, map[string]interface{}{"sdg_teacher_secret": teacherSecret})
+
+		// Only the judge may be served from the namespace when the teacher is remote
+		judgeService := os.Getenv("JUDGE_INFERENCE_SERVICE")
+		for _, pod := range TestUtil.GetInferenceServicePods(t, client, namespace, "") {
+			require.Equal(t, judgeService, pod.Labels[TestUtil.InferenceServiceLabel], "Unexpected model serving pod %s scheduled with a remote teacher", pod.Name)
+		}
+	})
+}
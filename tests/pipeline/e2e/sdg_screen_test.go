@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// screenSDGDataset scans a sample of the dataset generated by the run for personal data and toxic content and
+// reports the hit counts, optionally asking the model of SDG_SCREEN_MODEL_SECRET about every sampled row
+func screenSDGDataset(t *testing.T, run pipelineRun) {
+	rules := TestUtil.LoadSDGScreenRules(t, "../e2e/resources/sdg_screen.yaml")
+	screen, err := TestUtil.NewSDGScreen(rules)
+	require.NoError(t, err, "Invalid SDG screen rules")
+	if name := os.Getenv("SDG_SCREEN_MODEL_SECRET"); name != "" {
+		secret := TestUtil.GetModelServerSecret(t, TestUtil.NewKubeClient(t), pipelineNamespace(t), name)
+		if endpoint, err := url.Parse(secret.Endpoint); err == nil && TestUtil.IsClusterLocalHost(endpoint.Hostname()) {
+			t.Logf("Skipping the model-based SDG screen, %s is a service of the cluster the test host cannot reach", endpoint.Hostname())
+		} else {
+			screen.Classifier = TestUtil.ModelSDGScreenClassifier(&http.Client{Timeout: time.Minute}, secret)
+		}
+	}
+
+	t.Log("Screening the SDG dataset for PII and toxicity...")
+	store, err := TestUtil.NewArtifactStoreFromEnv()
+	require.NoError(t, err, "The SDG screen reads the sdg artifact from the artifact store")
+	keys, err := TestUtil.ListObjectKeys(store, run.runPrefix)
+	require.NoError(t, err, "Failed to list the run artifacts")
+
+	sampler := TestUtil.NewSDGRowSampler(rules.SampleSize)
+	for path, key := range TestUtil.SDGDatasetKeys(keys, run.runID) {
+		content, err := store.Get(context.Background(), key)
+		require.NoError(t, err, "Failed to read %s", key)
+		err = sampler.AddFile(path, content)
+		content.Close()
+		require.NoError(t, err, "Failed to read %s", key)
+	}
+
+	report := screen.Screen(context.Background(), sampler.Rows(), sampler.Seen())
+	path := TestUtil.WriteArtifact(t, "sdg-screen.md", []byte(TestUtil.RenderSDGScreen(report)))
+	t.Logf("SDG screen: %d of %d sampled rows flagged %v, written to %s", report.Flagged, report.Rows, report.Hits, path)
+}
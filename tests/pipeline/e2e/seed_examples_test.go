@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// checkSeedExamples verifies SDG generated samples for every leaf of the taxonomy at TAXONOMY_DIR, in proportion
+// to its seed examples
+func checkSeedExamples(t *testing.T, run pipelineRun) {
+	rules := TestUtil.LoadSeedExampleRules(t, "../e2e/resources/seed_examples.yaml")
+	leaves, _, samples := leafSamples(t, run, rules)
+	for _, failure := range TestUtil.CheckSeedProportionality(leaves, samples, rules.MaxRatioDeviation) {
+		t.Errorf("Seed example check failed: %s", failure)
+	}
+}
+
+// reportSDGCoverage writes the samples SDG generated for every leaf of the taxonomy at TAXONOMY_DIR and flags the
+// leaves without samples, so content authors see which contributions were dropped
+func reportSDGCoverage(t *testing.T, run pipelineRun) {
+	leaves, files, samples := leafSamples(t, run, TestUtil.LoadSeedExampleRules(t, "../e2e/resources/seed_examples.yaml"))
+	coverage := TestUtil.SDGCoverage(leaves, files, samples)
+	path := TestUtil.WriteArtifact(t, "sdg-coverage.md", []byte(TestUtil.RenderSDGCoverage(coverage)))
+	t.Logf("SDG coverage of %d taxonomy leaves written to %s", len(coverage), path)
+	for _, leaf := range coverage {
+		if leaf.Dropped() {
+			t.Logf("WARNING: taxonomy leaf %s has %d seed examples and no generated sample", leaf.Leaf.Path, leaf.Leaf.SeedExamples)
+		}
+	}
+}
+
+// leafSamples returns the leaves of the taxonomy at TAXONOMY_DIR, the keys of their node dataset files in the sdg
+// artifact and their valid samples, by leaf path
+func leafSamples(t *testing.T, run pipelineRun, rules TestUtil.SeedExampleRules) ([]TestUtil.TaxonomyLeaf, map[string]string, map[string]int) {
+	taxonomyDir := os.Getenv("TAXONOMY_DIR")
+	require.NotEmpty(t, taxonomyDir, "TAXONOMY_DIR environment variable must be set")
+
+	leaves, err := TestUtil.WalkTaxonomy(taxonomyDir)
+	require.NoError(t, err, "Failed to read the taxonomy")
+	require.NotEmpty(t, leaves, "No qna.yaml found in %s", taxonomyDir)
+	t.Logf("Reading the SDG output of %d taxonomy leaves...", len(leaves))
+
+	store, err := TestUtil.NewArtifactStoreFromEnv()
+	require.NoError(t, err, "The seed example checks read the sdg artifact from the artifact store")
+	keys, err := TestUtil.ListObjectKeys(store, run.runPrefix)
+	require.NoError(t, err, "Failed to list the run artifacts")
+
+	files := TestUtil.LeafDatasetKeys(keys, run.runID, leaves, rules)
+	samples := map[string]int{}
+	for leaf, key := range files {
+		content, err := store.Get(context.Background(), key)
+		require.NoError(t, err, "Failed to read %s", key)
+		file, err := TestUtil.ValidateSDGDatasetFile(key, content)
+		content.Close()
+		require.NoError(t, err, "Failed to read %s", key)
+		samples[leaf] = file.Rows - file.Invalid
+	}
+	return leaves, files, samples
+}
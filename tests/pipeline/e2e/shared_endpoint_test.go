@@ -0,0 +1,69 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// prepareSharedEndpoint uses SHARED_MODEL_SECRET, when set, as both teacher and judge secret, requires the teacher
+// and judge secrets of the run to point at the same model server and checks it serves SHARED_ENDPOINT_CONCURRENCY
+// concurrent requests, as SDG and eval send when they share it
+func prepareSharedEndpoint(t *testing.T, overrides map[string]interface{}) {
+	if name := os.Getenv("SHARED_MODEL_SECRET"); name != "" {
+		overrides["sdg_teacher_secret"] = name
+		overrides["eval_judge_secret"] = name
+	}
+	client := TestUtil.NewKubeClient(t)
+	namespace := pipelineNamespace(t)
+	names := modelServerSecretNames(t, overrides)
+	teacher := TestUtil.GetModelServerSecret(t, client, namespace, names["sdg_teacher_secret"])
+	judge := TestUtil.GetModelServerSecret(t, client, namespace, names["eval_judge_secret"])
+	problems := TestUtil.CheckSharedEndpoint(teacher, judge)
+	require.Empty(t, problems, "Teacher secret %s and judge secret %s do not share the model server", names["sdg_teacher_secret"], names["eval_judge_secret"])
+
+	concurrency := 8
+	if value := os.Getenv("SHARED_ENDPOINT_CONCURRENCY"); value != "" {
+		var err error
+		concurrency, err = strconv.Atoi(value)
+		require.NoError(t, err, "SHARED_ENDPOINT_CONCURRENCY must be a number")
+	}
+	if endpoint, err := url.Parse(teacher.Endpoint); err == nil && TestUtil.IsClusterLocalHost(endpoint.Hostname()) {
+		t.Logf("Skipping the concurrent load of %s, a service of the cluster", teacher.Endpoint)
+		return
+	}
+	result := TestUtil.ConcurrentLoad(context.Background(), &http.Client{Timeout: 5 * time.Minute}, teacher, concurrency, 4*concurrency)
+	t.Logf("The shared model server served %d requests from %d clients in %s, %d failed", result.Requests, concurrency, result.Duration.Round(time.Second), result.Failures)
+	require.Zero(t, result.Failures, "The shared model server failed under concurrent load: %v", result.Errors)
+}
+
+// checkSharedSecret verifies the SDG and eval tasks of the run read the same model server secret
+func checkSharedSecret(t *testing.T, run pipelineRun) {
+	tasks := TestUtil.GetRunTaskPods(t, TestUtil.NewKubeClient(t), pipelineNamespace(t), run.runID)
+	for _, problem := range TestUtil.CheckRunSharedSecret(tasks) {
+		t.Errorf("Pipeline run %s: %s", run.runID, problem)
+	}
+}
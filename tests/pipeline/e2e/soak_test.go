@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPipelineSoak runs the pipeline back to back in the same namespace for SOAK_DURATION, or SOAK_RUNS runs, and
+// checks the namespace resources left by the runs do not grow and the pipeline server and API server stay
+// available, validating the platform for repeated production use. Runs are mock runs unless SOAK_MODE is sampling.
+func TestPipelineSoak(t *testing.T) {
+	if os.Getenv("ENABLE_SOAK_TEST") != "true" {
+		t.Skip("Skipping soak test. Set ENABLE_SOAK_TEST=true to enable.")
+	}
+	config := loadPipelineTestConfig(t)
+	namespace := pipelineNamespace(t)
+	client := TestUtil.NewKubeClient(t)
+
+	duration := 24 * time.Hour
+	if value := os.Getenv("SOAK_DURATION"); value != "" {
+		var err error
+		duration, err = time.ParseDuration(value)
+		require.NoError(t, err, "SOAK_DURATION must be a duration, e.g. 48h")
+	}
+	maxRuns := 0
+	if value := os.Getenv("SOAK_RUNS"); value != "" {
+		var err error
+		maxRuns, err = strconv.Atoi(value)
+		require.NoError(t, err, "SOAK_RUNS must be a number of runs")
+	}
+	limits := TestUtil.LoadSoakLimits(t, "../e2e/resources/soak_limits.yaml")
+
+	var overrides map[string]interface{}
+	switch mode := os.Getenv("SOAK_MODE"); mode {
+	case "", "mock":
+		overrides = prepareMockRun(t)
+	case "sampling":
+		acquireGPULease(t)
+		overrides = map[string]interface{}{}
+	default:
+		require.Failf(t, "Invalid soak mode", "SOAK_MODE must be mock or sampling, got '%s'", mode)
+	}
+
+	stopProbes := TestUtil.ProbeAPIs(map[string]TestUtil.APIProbe{
+		"pipeline-server": TestUtil.HTTPProbe(&http.Client{Timeout: 30 * time.Second}, config.pipelineServerURL+"/apis/v2beta1/healthz", config.bearerToken),
+		"api-server": func(ctx context.Context) error {
+			_, err := client.Discovery().RESTClient().Get().AbsPath("/readyz").DoRaw(ctx)
+			return err
+		},
+	}, time.Minute)
+
+	baseline, err := TestUtil.CountNamespaceResources(context.Background(), client, namespace)
+	require.NoError(t, err, "Failed to count the namespace resources")
+	t.Logf("Namespace %s before the soak: %v", namespace, baseline)
+
+	var samples []TestUtil.SoakSample
+	deadline := time.Now().Add(duration)
+	for run := 1; time.Now().Before(deadline) && (maxRuns == 0 || run <= maxRuns); run++ {
+		sample := TestUtil.SoakSample{Run: run}
+		start := time.Now()
+		sample.Succeeded = t.Run(fmt.Sprintf("run-%d", run), func(t *testing.T) {
+			params := map[string]interface{}{}
+			for name, value := range overrides {
+				params[name] = value
+			}
+			sample.RunID = runPipeline(t, config, params).runID
+		})
+		sample.Duration = time.Since(start)
+		sample.Resources, err = TestUtil.CountNamespaceResources(context.Background(), client, namespace)
+		require.NoError(t, err, "Failed to count the namespace resources")
+		t.Logf("Soak run %d (%s) succeeded: %t in %s, namespace: %v", run, sample.RunID, sample.Succeeded, sample.Duration.Round(time.Second), sample.Resources)
+		samples = append(samples, sample)
+	}
+	probes := stopProbes()
+
+	report, err := json.MarshalIndent(map[string]interface{}{
+		"baseline": baseline,
+		"runs":     samples,
+		"growth":   TestUtil.ResourceGrowth(samples),
+		"probes":   probes,
+	}, "", "  ")
+	require.NoError(t, err, "Failed to encode the soak report")
+	t.Logf("Soak report written to %s", TestUtil.WriteArtifact(t, "soak-report.json", report))
+
+	for _, problem := range TestUtil.CheckSoak(samples, probes, limits) {
+		t.Errorf("Soak test of namespace %s: %s", namespace, problem)
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStorageClasses runs the pipeline with the same parameters on every storage class of STORAGE_CLASSES and writes
+// the phase durations of the runs into the artifacts directory, marking the phases whose duration depends on the
+// storage
+func TestStorageClasses(t *testing.T) {
+	if os.Getenv("ENABLE_STORAGE_CLASS_TEST") != "true" {
+		t.Skip("Skipping storage class comparison. Set ENABLE_STORAGE_CLASS_TEST=true to enable.")
+	}
+	config := loadPipelineTestConfig(t)
+	namespace := pipelineNamespace(t)
+	acquireGPULease(t)
+
+	var classes []string
+	for _, class := range strings.Split(os.Getenv("STORAGE_CLASSES"), ",") {
+		if class = strings.TrimSpace(class); class != "" {
+			classes = append(classes, class)
+		}
+	}
+	require.NotEmpty(t, classes, "STORAGE_CLASSES environment variable must list the storage classes to compare")
+
+	pipelineID, err := TestUtil.RetrievePipelineId(t, config.pipelineServerURL, config.pipelineDisplayName, config.bearerToken)
+	require.NoError(t, err, "Failed to retrieve pipeline ID")
+	client := TestUtil.NewKubeClient(t)
+
+	var results []TestUtil.StorageClassResult
+	for _, class := range classes {
+		name := fmt.Sprintf("%s-%s-%d", config.pipelineDisplayName, class, time.Now().Unix())
+		t.Logf("Running the pipeline on storage class %s", class)
+
+		overrides := evalParameterOverrides(t)
+		overrides["k8s_storage_class_name"] = class
+		result := TestUtil.StorageClassResult{StorageClass: class}
+		start := time.Now()
+		result.RunID, err = TestUtil.TriggerPipeline(t, config.pipelineServerURL, pipelineID, name, loadPipelineParams(t, overrides), config.bearerToken)
+		if err == nil {
+			err = TestUtil.WaitForPipelineSuccessWithin(t, config.pipelineServerURL, result.RunID, config.bearerToken, config.runTimeout)
+			result.Phases = TestUtil.PhaseDurations(TestUtil.GetRunTaskPods(t, client, namespace, result.RunID))
+		}
+		result.Duration = time.Since(start)
+		result.Err = err
+		results = append(results, result)
+
+		if err != nil {
+			t.Errorf("Run on storage class %s failed: %v", class, err)
+		}
+	}
+
+	path := TestUtil.WriteArtifact(t, "storage-classes.md", []byte(TestUtil.RenderStorageClassReport(results, TestUtil.DefaultIOBoundSpread)))
+	t.Logf("Storage class comparison written to %s, I/O-bound phases: %v", path, TestUtil.IOBoundPhases(results, TestUtil.DefaultIOBoundSpread))
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTaxonomyScenarios runs the pipeline once with a knowledge-heavy taxonomy and once with a skills-heavy taxonomy,
+// as the knowledge and skills code paths regress independently
+func TestTaxonomyScenarios(t *testing.T) {
+	if os.Getenv("ENABLE_TAXONOMY_SCENARIOS_TEST") != "true" {
+		t.Skip("Skipping taxonomy scenarios. Set ENABLE_TAXONOMY_SCENARIOS_TEST=true to enable.")
+	}
+	config := loadPipelineTestConfig(t)
+	acquireGPULease(t)
+	rules := TestUtil.LoadSDGRecipeRules(t, "../e2e/resources/taxonomy_scenarios.yaml")
+	client := TestUtil.NewKubeClient(t)
+	store, err := TestUtil.NewArtifactStoreFromEnv()
+	require.NoError(t, err, "The taxonomy scenarios read the sdg artifact from the artifact store")
+
+	for _, name := range []string{"knowledge", "skills"} {
+		scenario, ok := rules.Scenarios[name]
+		require.True(t, ok, "Scenario %s is missing from taxonomy_scenarios.yaml", name)
+
+		t.Run(name, func(t *testing.T) {
+			envPrefix := strings.ToUpper(name) + "_TAXONOMY_"
+			repoURL := os.Getenv(envPrefix + "REPO_URL")
+			require.NotEmpty(t, repoURL, "%sREPO_URL environment variable must be set", envPrefix)
+
+			overrides := map[string]interface{}{"sdg_repo_url": repoURL}
+			if branch := os.Getenv(envPrefix + "BRANCH"); branch != "" {
+				overrides["sdg_repo_branch"] = branch
+			}
+			run := runPipeline(t, config, overrides)
+
+			objects, err := store.List(context.Background(), run.runPrefix)
+			require.NoError(t, err, "Failed to list the run artifacts")
+			files := TestUtil.SDGArtifactFiles(objects, run.runID)
+			require.NotEmpty(t, files, "No sdg artifact found for run %s", run.runID)
+
+			counts, err := TestUtil.DetectSDGRecipes(files, rules.RecipeFiles)
+			require.NoError(t, err, "Failed to detect SDG recipes")
+			t.Logf("SDG recipes of the %s taxonomy: %v", name, counts)
+			for _, failure := range TestUtil.CheckSDGRecipes(counts, scenario) {
+				t.Errorf("SDG recipe check failed: %s", failure)
+			}
+
+			tasks := TestUtil.GetRunTaskPods(t, client, pipelineNamespace(t), run.runID)
+			// Phase 1 trains on the knowledge data, phase 2 on the skills data
+			require.NoError(t, TestUtil.CheckTrainingPhases(tasks, 1, 2), "Training phases did not both execute")
+		})
+	}
+}
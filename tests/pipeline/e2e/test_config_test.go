@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfigSchema = "resources/test_config.schema.json"
+
+// TestMain exports the test config Secret mounted at TEST_CONFIG_DIR before any test reads the environment. The
+// Secret is validated against its schema first, so a typo in a key or value fails the pod at startup rather than
+// hours into a run.
+func TestMain(m *testing.M) {
+	if dir := os.Getenv(TestUtil.TestConfigDirEnv); dir != "" {
+		if err := applyTestConfig(dir); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	os.Exit(m.Run())
+}
+
+func applyTestConfig(dir string) error {
+	schema, err := TestUtil.LoadJSONSchema(testConfigSchema)
+	if err != nil {
+		return err
+	}
+	values, err := TestUtil.LoadTestConfigDir(dir)
+	if err != nil {
+		return err
+	}
+	if problems := TestUtil.ValidateTestConfig(schema, values); len(problems) > 0 {
+		return fmt.Errorf("invalid test config %s:\n  %s", dir, strings.Join(problems, "\n  "))
+	}
+	applied, err := TestUtil.ApplyTestConfig(values)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Test config %s: %d inputs set, %d overridden by the environment\n", dir, len(applied), len(values)-len(applied))
+	return nil
+}
+
+// The test config schema must know every environment variable the suite reads, or the Secret could not set it
+func TestTestConfigSchema(t *testing.T) {
+	schema, err := TestUtil.LoadJSONSchema(testConfigSchema)
+	require.NoError(t, err)
+
+	// The variables read with a computed name
+	names := map[string]bool{
+		TestUtil.ObjectStoreEndpointKey: true, TestUtil.ObjectStoreBucketKey: true, TestUtil.ObjectStoreRegionKey: true,
+		TestUtil.ObjectStoreAccessKeyKey: true, TestUtil.ObjectStoreSecretKeyKey: true, TestUtil.ObjectStoreOIDCTokenKey: true,
+		TestUtil.ObjectStoreOIDCTokenURLKey: true, TestUtil.ObjectStoreOIDCClientIDKey: true,
+		TestUtil.ObjectStoreOIDCClientSecretKey: true,
+		"JUDGE_CA_PEM":                          true, "JUDGE_CA_FILE": true, "JUDGE_CA_SOURCE": true,
+		"KNOWLEDGE_TAXONOMY_REPO_URL": true, "KNOWLEDGE_TAXONOMY_BRANCH": true,
+		"SKILLS_TAXONOMY_REPO_URL": true, "SKILLS_TAXONOMY_BRANCH": true,
+		"SLACK_WEBHOOK_URL": true, "TEAMS_WEBHOOK_URL": true, "PAGERDUTY_ROUTING_KEY": true,
+		"SMTP_ADDRESS": true, "SMTP_FROM": true, "SMTP_USERNAME": true, "SMTP_PASSWORD": true,
+	}
+	for _, extension := range runExtensions {
+		names[extension.env] = true
+	}
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+	sources, err := filepath.Glob("util/*.go")
+	require.NoError(t, err)
+	for _, source := range sources {
+		if !strings.HasSuffix(source, "_test.go") {
+			files = append(files, source)
+		}
+	}
+	fset := token.NewFileSet()
+	for _, path := range files {
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		require.NoError(t, err)
+		ast.Inspect(file, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok || len(call.Args) != 1 {
+				return true
+			}
+			selector, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || (selector.Sel.Name != "Getenv" && selector.Sel.Name != "LookupEnv") {
+				return true
+			}
+			if pkg, ok := selector.X.(*ast.Ident); !ok || pkg.Name != "os" {
+				return true
+			}
+			if literal, ok := call.Args[0].(*ast.BasicLit); ok && literal.Kind == token.STRING {
+				name, err := strconv.Unquote(literal.Value)
+				require.NoError(t, err)
+				names[name] = true
+			}
+			return true
+		})
+	}
+	// The config dir itself and the kubeconfig cannot come from the Secret
+	delete(names, TestUtil.TestConfigDirEnv)
+	delete(names, "KUBECONFIG")
+
+	var missing []string
+	for name := range names {
+		if _, ok := schema.Properties[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	require.Empty(t, missing, "Add the variables to %s and the README", testConfigSchema)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// applyTimeBudget downscopes the run to the first configuration of the time budget mode expected to complete within
+// TIME_BUDGET, failing before the run starts when none is. The run is waited for the budget at most.
+func applyTimeBudget(t *testing.T, config *pipelineTestConfig, overrides map[string]interface{}) {
+	value := os.Getenv("TIME_BUDGET")
+	if value == "" {
+		return
+	}
+	budget, err := time.ParseDuration(value)
+	require.NoError(t, err, "TIME_BUDGET must be a duration")
+	path := os.Getenv("TIME_BUDGET_CONFIG")
+	if path == "" {
+		path = "resources/time_budget.yaml"
+	}
+	budgetConfig := TestUtil.LoadTimeBudget(t, path)
+
+	history, err := TestUtil.LoadRunHistory(runHistoryPath(t))
+	require.NoError(t, err, "Failed to load the run history")
+	definitions, err := TestUtil.LoadPipelineInputDefinitions("../../../pipeline.yaml")
+	require.NoError(t, err, "Failed to load the compiled pipeline")
+	estimates := budgetConfig.Estimate(history, loadPipelineParams(t, overrides), definitions)
+	report := TestUtil.RenderTimeBudget(budget, estimates)
+	TestUtil.WriteArtifact(t, "time-budget.md", []byte(report))
+
+	chosen, ok := TestUtil.FirstFitting(estimates, budget)
+	if !ok {
+		t.Fatalf("No configuration is expected to complete within the time budget of %s, run history %s:\n%s", budget, runHistoryPath(t), report)
+	}
+	for name, param := range chosen.Configuration.Params {
+		overrides[name] = param
+	}
+	config.runTimeout = budget
+	t.Logf("Running configuration %s of the time budget of %s, expected to take %s", chosen.Configuration.Name, budget, chosen.Total.Round(time.Minute))
+}
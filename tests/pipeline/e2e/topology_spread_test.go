@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestPipelineRunTopologySpread runs the pipeline with the training workers spread across hosts, then across zones,
+// verifying the training pods carry the topology spread constraints and are placed accordingly. The training
+// duration of the zone spread is compared to the host spread, the cost of the cross-zone traffic.
+func TestPipelineRunTopologySpread(t *testing.T) {
+	if os.Getenv("ENABLE_TOPOLOGY_SPREAD_TEST") != "true" {
+		t.Skip("Skipping topology spread test. Set ENABLE_TOPOLOGY_SPREAD_TEST=true to enable.")
+	}
+
+	spread := TestUtil.LoadTopologySpread(t, "../e2e/resources/topology_spread.yaml")
+	definitions, err := TestUtil.LoadPipelineInputDefinitions("../../../pipeline.yaml")
+	require.NoError(t, err, "Failed to load the compiled pipeline")
+	if missing := TestUtil.MissingPipelineInputs(definitions, map[string]interface{}{TestUtil.TopologySpreadParameter: nil}); len(missing) > 0 {
+		t.Skipf("Skipping topology spread test, the pipeline does not expose %v yet", missing)
+	}
+
+	config := loadPipelineTestConfig(t)
+	acquireGPULease(t)
+	namespace := pipelineNamespace(t)
+	client := TestUtil.NewKubeClient(t)
+
+	durations := map[string]time.Duration{}
+	for _, variant := range []string{"host", "zone"} {
+		spreads := spread.Variants[variant]
+		if len(spreads) == 0 {
+			continue
+		}
+		t.Run(variant, func(t *testing.T) {
+			overrides := evalParameterOverrides(t)
+			if spread.NumWorkers > 0 {
+				overrides["train_num_workers"] = spread.NumWorkers
+			}
+			overrides[TestUtil.TopologySpreadParameter] = TestUtil.TopologySpreadParameterValue(spreads)
+			prepareRuns(t, config, overrides)
+			run := runPipeline(t, config, overrides)
+
+			pods := TestUtil.GetTrainingPods(t, client, namespace, run.runID)
+			require.NotEmpty(t, pods, "No training pods found for the run")
+			nodes, err := client.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+			require.NoError(t, err, "Failed to list nodes")
+			for _, problem := range TestUtil.CheckTopologySpread(pods, nodes.Items, spreads) {
+				t.Error(problem)
+			}
+			for _, key := range []string{TestUtil.ZoneLabel, TestUtil.HostnameLabel} {
+				t.Logf("Training pods per %s: %v", key, TestUtil.PodsPerDomain(pods, nodes.Items, key, pods[0].Spec.NodeSelector))
+			}
+
+			var total time.Duration
+			for job, duration := range TestUtil.TrainingJobDurations(pods) {
+				t.Logf("%s trained in %s", job, duration.Round(time.Second))
+				total += duration
+			}
+			durations[variant] = total
+		})
+	}
+
+	if durations["host"] == 0 || durations["zone"] == 0 {
+		return
+	}
+	slowdown := float64(durations["zone"]) / float64(durations["host"])
+	t.Logf("Training across zones took %.2fx the training across hosts (%s / %s)", slowdown, durations["zone"].Round(time.Second), durations["host"].Round(time.Second))
+	if spread.MaxCrossZoneSlowdown > 0 {
+		require.LessOrEqual(t, slowdown, spread.MaxCrossZoneSlowdown, "Training across zones is slower than expected")
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"regexp"
+	"testing"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/stretchr/testify/require"
+)
+
+// setTrainingEpochs sets the epochs and early-stopping criteria of training_epochs.yaml on the run, failing when the
+// pipeline does not expose the early-stopping parameters rather than letting them be dropped
+func setTrainingEpochs(t *testing.T, overrides map[string]interface{}) {
+	config := TestUtil.LoadTrainingEpochsConfig(t, "../e2e/resources/training_epochs.yaml")
+	epochs := config.ParameterOverrides()
+	definitions, err := TestUtil.LoadPipelineInputDefinitions("../../../pipeline.yaml")
+	require.NoError(t, err, "Failed to load the compiled pipeline")
+	require.Empty(t, TestUtil.MissingPipelineInputs(definitions, epochs), "The pipeline does not expose the training epochs parameters")
+	for name, value := range epochs {
+		overrides[name] = value
+	}
+}
+
+// checkTrainingEpochs verifies from the logs of the master pod of every training phase the requested epochs were run
+func checkTrainingEpochs(t *testing.T, run pipelineRun) {
+	t.Log("Checking the epochs run by the training phases...")
+	config := TestUtil.LoadTrainingEpochsConfig(t, "../e2e/resources/training_epochs.yaml")
+	pattern := regexp.MustCompile(config.EpochPattern)
+	client := TestUtil.NewKubeClient(t)
+	namespace := pipelineNamespace(t)
+
+	jobs := TestUtil.TrainingJobNames(TestUtil.GetRunPods(t, client, namespace, run.runID))
+	require.Len(t, jobs, 2, "The workflow of the run was not found")
+	trainingPods := TestUtil.GetTrainingPods(t, client, namespace, run.runID)
+	for i, job := range jobs {
+		phase := i + 1
+		epochs, err := TestUtil.TrainingEpochsRun(client, trainingPods, job, pattern)
+		require.NoError(t, err, "Failed to count the epochs of training phase %d", phase)
+		t.Logf("Training phase %d ran %d epochs", phase, epochs)
+		if err := TestUtil.CheckEpochsRun(phase, config.Phase(phase), epochs); err != nil {
+			t.Error(err)
+		}
+	}
+}
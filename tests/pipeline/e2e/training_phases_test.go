@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"os"
+	"testing"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPipelineRunTrainingPhases runs the pipeline training only phase 1, then only phase 2 from a supplied phase-1
+// checkpoint, verifying the PyTorchJob of the other phase is not created and the launcher of the trained phase
+// received its inputs
+func TestPipelineRunTrainingPhases(t *testing.T) {
+	if os.Getenv("ENABLE_TRAINING_PHASES_TEST") != "true" {
+		t.Skip("Skipping training phases test. Set ENABLE_TRAINING_PHASES_TEST=true to enable.")
+	}
+
+	phaseParams := viper.New()
+	phaseParams.SetConfigFile("../e2e/resources/training_phases.yaml")
+	require.NoError(t, phaseParams.ReadInConfig(), "Error loading training phase parameters")
+	definitions, err := TestUtil.LoadPipelineInputDefinitions("../../../pipeline.yaml")
+	require.NoError(t, err, "Failed to load the compiled pipeline")
+	for _, variant := range []string{"phase_1_only", "phase_2_only"} {
+		if missing := TestUtil.MissingPipelineInputs(definitions, phaseParams.GetStringMap(variant)); len(missing) > 0 {
+			t.Skipf("Skipping training phases test, the pipeline does not expose %v yet", missing)
+		}
+	}
+
+	config := loadPipelineTestConfig(t)
+	acquireGPULease(t)
+	namespace := pipelineNamespace(t)
+	client := TestUtil.NewKubeClient(t)
+
+	t.Run("phase-1-only", func(t *testing.T) {
+		overrides := evalParameterOverrides(t)
+		for name, value := range phaseParams.GetStringMap("phase_1_only") {
+			overrides[name] = value
+		}
+		prepareRuns(t, config, overrides)
+		run := runPipeline(t, config, overrides)
+
+		tasks := TestUtil.GetRunTaskPods(t, client, namespace, run.runID)
+		require.NoError(t, TestUtil.CheckTrainingPhases(tasks, 1), "Training phase 1 did not complete")
+		runPods := TestUtil.GetRunPods(t, client, namespace, run.runID)
+		for _, problem := range TestUtil.CheckOnlyTrainingPhases(tasks, runPods, TestUtil.GetTrainingPods(t, client, namespace, run.runID), []int{1}) {
+			t.Error(problem)
+		}
+	})
+
+	t.Run("phase-2-only", func(t *testing.T) {
+		checkpoint := os.Getenv("TRAINING_PHASE_1_CHECKPOINT")
+		if checkpoint == "" {
+			t.Skip("Skipping the phase 2 only run. Set TRAINING_PHASE_1_CHECKPOINT to the phase-1 checkpoint to start from.")
+		}
+		overrides := evalParameterOverrides(t)
+		for name, value := range phaseParams.GetStringMap("phase_2_only") {
+			overrides[name] = value
+		}
+		overrides["train_phase_1_checkpoint"] = checkpoint
+		prepareRuns(t, config, overrides)
+		run := runPipeline(t, config, overrides)
+
+		tasks := TestUtil.GetRunTaskPods(t, client, namespace, run.runID)
+		require.NoError(t, TestUtil.CheckTrainingPhases(tasks, 2), "Training phase 2 did not complete")
+		runPods := TestUtil.GetRunPods(t, client, namespace, run.runID)
+		for _, problem := range TestUtil.CheckOnlyTrainingPhases(tasks, runPods, TestUtil.GetTrainingPods(t, client, namespace, run.runID), []int{2}) {
+			t.Error(problem)
+		}
+		require.NoError(t, TestUtil.CheckLauncherInput(tasks, 2, checkpoint), "The phase-1 checkpoint did not reach the phase 2 launcher")
+	})
+}
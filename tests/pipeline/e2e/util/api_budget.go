@@ -0,0 +1,218 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ControlPlaneNodeLabel selects the nodes running the API server
+const ControlPlaneNodeLabel = "node-role.kubernetes.io/master"
+
+// APIBudget limits the API server requests issued by the service accounts of a run
+type APIBudget struct {
+	MaxRequests          int `mapstructure:"max_requests"`
+	MaxRequestsPerMinute int `mapstructure:"max_requests_per_minute"`
+}
+
+// APIRequestStats counts the API server requests of a run
+type APIRequestStats struct {
+	Total int
+	// ByRequest counts the requests by verb and resource, e.g. "list pods"
+	ByRequest map[string]int
+	// ByMinute counts the requests by the minute they were received in
+	ByMinute map[time.Time]int
+}
+
+// PeakPerMinute is the highest number of requests received within a minute
+func (s APIRequestStats) PeakPerMinute() int {
+	peak := 0
+	for _, count := range s.ByMinute {
+		if count > peak {
+			peak = count
+		}
+	}
+	return peak
+}
+
+// APIRequestSource selects the requests of a run by service account user. The API server records the pod a bound
+// service account token was issued to, the requests carrying a pod name are only counted for the pods of the run, so
+// other runs sharing the service accounts are excluded. Requests of tokens without a pod, such as legacy secret based
+// tokens, are counted by user only.
+type APIRequestSource struct {
+	Users map[string]bool
+	Pods  map[string]bool
+}
+
+// auditPodNameKey is the user extra the API server records the pod of a bound service account token in
+const auditPodNameKey = "authentication.kubernetes.io/pod-name"
+
+// auditLogFile matches the current and the rotated API server audit logs, e.g. audit-2025-03-01T12-00-00.000.log
+var auditLogFile = regexp.MustCompile(`^audit(-(\d{4}-\d{2}-\d{2}T\d{2}-\d{2}-\d{2}\.\d{3}))?\.log$`)
+
+type auditEvent struct {
+	Stage string `json:"stage"`
+	Verb  string `json:"verb"`
+	User  struct {
+		Username string              `json:"username"`
+		Extra    map[string][]string `json:"extra"`
+	} `json:"user"`
+	ObjectRef struct {
+		Resource string `json:"resource"`
+	} `json:"objectRef"`
+	RequestReceivedTimestamp time.Time `json:"requestReceivedTimestamp"`
+}
+
+// LoadAPIBudget reads the API request budget from a YAML file
+func LoadAPIBudget(t *testing.T, path string) APIBudget {
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig(), "Error loading API budget")
+
+	var budget APIBudget
+	require.NoError(t, v.Unmarshal(&budget), "Error parsing API budget")
+	return budget
+}
+
+// ServiceAccountUser returns the user name the API server audits the requests of a service account as
+func ServiceAccountUser(namespace, name string) string {
+	return fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name)
+}
+
+// CountAPIRequests adds the completed requests of a run received within [since, until) in an audit log to the stats
+func CountAPIRequests(log io.Reader, source APIRequestSource, since, until time.Time, stats *APIRequestStats) error {
+	if stats.ByRequest == nil {
+		stats.ByRequest = map[string]int{}
+		stats.ByMinute = map[time.Time]int{}
+	}
+	scanner := bufio.NewScanner(log)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var event auditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		received := event.RequestReceivedTimestamp
+		if event.Stage != "ResponseComplete" || !source.Users[event.User.Username] || received.Before(since) || !received.Before(until) {
+			continue
+		}
+		if pods := event.User.Extra[auditPodNameKey]; len(pods) > 0 && !source.Pods[pods[0]] {
+			continue
+		}
+		stats.Total++
+		stats.ByRequest[event.Verb+" "+event.ObjectRef.Resource]++
+		stats.ByMinute[received.Truncate(time.Minute)]++
+	}
+	return scanner.Err()
+}
+
+// AuditLogFiles returns the audit logs of a node log directory listing that may hold requests received since the
+// given time: the current log and the logs rotated after that time
+func AuditLogFiles(listing string, since time.Time) []string {
+	var files []string
+	for _, match := range regexp.MustCompile(`href="([^"]+)"`).FindAllStringSubmatch(listing, -1) {
+		name := match[1]
+		parts := auditLogFile.FindStringSubmatch(name)
+		if parts == nil {
+			continue
+		}
+		if parts[2] != "" {
+			// A log is rotated once full, it holds no request received after its rotation
+			rotated, err := time.Parse("2006-01-02T15-04-05.000", parts[2])
+			if err == nil && rotated.Before(since) {
+				continue
+			}
+		}
+		files = append(files, name)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// CheckAPIBudget returns the limits of the budget exceeded by the requests of a run
+func CheckAPIBudget(stats APIRequestStats, budget APIBudget) []string {
+	var failures []string
+	if budget.MaxRequests > 0 && stats.Total > budget.MaxRequests {
+		failures = append(failures, fmt.Sprintf("%d API requests exceed the budget of %d", stats.Total, budget.MaxRequests))
+	}
+	if peak := stats.PeakPerMinute(); budget.MaxRequestsPerMinute > 0 && peak > budget.MaxRequestsPerMinute {
+		failures = append(failures, fmt.Sprintf("peak of %d API requests per minute exceeds the budget of %d", peak, budget.MaxRequestsPerMinute))
+	}
+	return failures
+}
+
+// TopRequests returns the most frequent requests, by verb and resource, with their counts
+func TopRequests(stats APIRequestStats, n int) []string {
+	requests := make([]string, 0, len(stats.ByRequest))
+	for request := range stats.ByRequest {
+		requests = append(requests, request)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		if stats.ByRequest[requests[i]] != stats.ByRequest[requests[j]] {
+			return stats.ByRequest[requests[i]] > stats.ByRequest[requests[j]]
+		}
+		return requests[i] < requests[j]
+	})
+	if len(requests) > n {
+		requests = requests[:n]
+	}
+	for i, request := range requests {
+		requests[i] = fmt.Sprintf("%s: %d", request, stats.ByRequest[request])
+	}
+	return requests
+}
+
+// CollectAPIRequestStats counts the requests of a run in the API server audit logs of every control plane node, the
+// logs rotated during the run included, streamed through the node logs API as `oc adm node-logs` does. Requires
+// cluster-admin.
+func CollectAPIRequestStats(t *testing.T, client kubernetes.Interface, source APIRequestSource, since, until time.Time) APIRequestStats {
+	nodes, err := client.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{LabelSelector: ControlPlaneNodeLabel})
+	require.NoError(t, err, "Failed to list control plane nodes")
+	require.NotEmpty(t, nodes.Items, "No control plane nodes found")
+
+	var stats APIRequestStats
+	for _, node := range nodes.Items {
+		// A single segment keeps the trailing slash the kubelet serves the directory listing on
+		listing, err := client.CoreV1().RESTClient().Get().
+			AbsPath(fmt.Sprintf("/api/v1/nodes/%s/proxy/logs/kube-apiserver/", node.Name)).
+			DoRaw(context.Background())
+		require.NoError(t, err, "Failed to list the audit logs of node %s", node.Name)
+		files := AuditLogFiles(string(listing), since)
+		require.NotEmpty(t, files, "No audit log found on node %s", node.Name)
+
+		for _, file := range files {
+			log, err := client.CoreV1().RESTClient().Get().
+				AbsPath("/api/v1/nodes", node.Name, "proxy/logs/kube-apiserver", file).
+				Stream(context.Background())
+			require.NoError(t, err, "Failed to read the audit log %s of node %s", file, node.Name)
+			err = CountAPIRequests(log, source, since, until, &stats)
+			log.Close()
+			require.NoError(t, err, "Failed to parse the audit log %s of node %s", file, node.Name)
+		}
+	}
+	return stats
+}
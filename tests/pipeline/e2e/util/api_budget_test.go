@@ -0,0 +1,60 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckAPIBudget(t *testing.T) {
+	runner := ServiceAccountUser("ilab", "pipeline-runner-dspa")
+	log := strings.Join([]string{
+		`{"stage":"ResponseComplete","verb":"list","user":{"username":"` + runner + `"},"objectRef":{"resource":"pods"},"requestReceivedTimestamp":"2025-03-01T12:00:10Z"}`,
+		`{"stage":"ResponseStarted","verb":"watch","user":{"username":"` + runner + `"},"objectRef":{"resource":"pods"},"requestReceivedTimestamp":"2025-03-01T12:00:20Z"}`,
+		`{"stage":"ResponseComplete","verb":"get","user":{"username":"` + runner + `"},"objectRef":{"resource":"secrets"},"requestReceivedTimestamp":"2025-03-01T12:00:30Z"}`,
+		`{"stage":"ResponseComplete","verb":"list","user":{"username":"` + runner + `","extra":{"authentication.kubernetes.io/pod-name":["run-sdg-1"]}},"objectRef":{"resource":"pods"},"requestReceivedTimestamp":"2025-03-01T12:01:10Z"}`,
+		`{"stage":"ResponseComplete","verb":"list","user":{"username":"` + runner + `","extra":{"authentication.kubernetes.io/pod-name":["other-run-sdg-1"]}},"objectRef":{"resource":"pods"},"requestReceivedTimestamp":"2025-03-01T12:01:20Z"}`,
+		`{"stage":"ResponseComplete","verb":"list","user":{"username":"system:admin"},"objectRef":{"resource":"pods"},"requestReceivedTimestamp":"2025-03-01T12:00:40Z"}`,
+		`{"stage":"ResponseComplete","verb":"list","user":{"username":"` + runner + `"},"objectRef":{"resource":"pods"},"requestReceivedTimestamp":"2025-03-01T13:00:00Z"}`,
+		`not json`,
+	}, "\n")
+
+	since := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	var stats APIRequestStats
+	require.NoError(t, CountAPIRequests(strings.NewReader(log), APIRequestSource{Users: map[string]bool{runner: true}, Pods: map[string]bool{"run-sdg-1": true}}, since, since.Add(time.Hour), &stats))
+	require.Equal(t, 3, stats.Total)
+	require.Equal(t, 2, stats.PeakPerMinute())
+	require.Equal(t, []string{"list pods: 2", "get secrets: 1"}, TopRequests(stats, 5))
+
+	require.Empty(t, CheckAPIBudget(stats, APIBudget{MaxRequests: 3, MaxRequestsPerMinute: 2}))
+	require.Equal(t, []string{
+		"3 API requests exceed the budget of 2",
+		"peak of 2 API requests per minute exceeds the budget of 1",
+	}, CheckAPIBudget(stats, APIBudget{MaxRequests: 2, MaxRequestsPerMinute: 1}))
+}
+
+func TestAuditLogFiles(t *testing.T) {
+	listing := `<pre>
+<a href="audit-2025-03-01T11-30-00.000.log">audit-2025-03-01T11-30-00.000.log</a>
+<a href="audit-2025-03-01T12-45-00.000.log">audit-2025-03-01T12-45-00.000.log</a>
+<a href="audit.log">audit.log</a>
+<a href="termination.log">termination.log</a>
+<a href="audit-archive.log.gz">audit-archive.log.gz</a>
+</pre>`
+	require.Equal(t, []string{"audit-2025-03-01T12-45-00.000.log", "audit.log"}, AuditLogFiles(listing, time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// UIDRangeAnnotation holds the range the UIDs of the pods of a namespace are assigned from, e.g. "1000680000/10000"
+	UIDRangeAnnotation = "openshift.io/sa.scc.uid-range"
+	// SupplementalGroupsAnnotation holds the range the fsGroup and supplemental groups of a namespace are assigned from
+	SupplementalGroupsAnnotation = "openshift.io/sa.scc.supplemental-groups"
+	// SCCAnnotation is set on every pod to the security context constraint it was admitted with
+	SCCAnnotation = "openshift.io/scc"
+)
+
+// arbitraryUIDSCCs are the security context constraints assigning the UID from the range of the namespace
+var arbitraryUIDSCCs = map[string]bool{"restricted": true, "restricted-v2": true}
+
+// UIDRange is a range of UIDs or GIDs of a namespace, the zero range contains no ID
+type UIDRange struct {
+	Start int64
+	Size  int64
+}
+
+// ParseUIDRange reads a range of the "<start>/<size>" form of the namespace annotations, the first one of a comma
+// separated list
+func ParseUIDRange(value string) (UIDRange, error) {
+	first, _, _ := strings.Cut(value, ",")
+	start, size, found := strings.Cut(first, "/")
+	if !found {
+		return UIDRange{}, fmt.Errorf("invalid UID range '%s', expected <start>/<size>", value)
+	}
+	var r UIDRange
+	var err error
+	if r.Start, err = strconv.ParseInt(start, 10, 64); err != nil {
+		return UIDRange{}, fmt.Errorf("invalid UID range '%s': %w", value, err)
+	}
+	if r.Size, err = strconv.ParseInt(size, 10, 64); err != nil {
+		return UIDRange{}, fmt.Errorf("invalid UID range '%s': %w", value, err)
+	}
+	return r, nil
+}
+
+func (r UIDRange) String() string {
+	return fmt.Sprintf("%d/%d", r.Start, r.Size)
+}
+
+// Contains reports whether the ID is in the range
+func (r UIDRange) Contains(id int64) bool {
+	return id >= r.Start && id < r.Start+r.Size
+}
+
+// NamespaceUIDRanges returns the UID and group ranges of a namespace, zero when it has none, e.g. outside OpenShift
+func NamespaceUIDRanges(namespace *corev1.Namespace) (UIDRange, UIDRange, error) {
+	var uids, groups UIDRange
+	var err error
+	if value := namespace.Annotations[UIDRangeAnnotation]; value != "" {
+		if uids, err = ParseUIDRange(value); err != nil {
+			return UIDRange{}, UIDRange{}, err
+		}
+	}
+	if value := namespace.Annotations[SupplementalGroupsAnnotation]; value != "" {
+		if groups, err = ParseUIDRange(value); err != nil {
+			return UIDRange{}, UIDRange{}, err
+		}
+	}
+	return uids, groups, nil
+}
+
+// CheckArbitraryUID returns the violations of a pod running with a UID or fsGroup set by its workload rather than
+// assigned from the ranges of the namespace, or admitted with a security context constraint allowing fixed UIDs
+func CheckArbitraryUID(pod *corev1.Pod, uids, groups UIDRange) []PolicyViolation {
+	var violations []PolicyViolation
+	resource := fmt.Sprintf("Pod/%s", pod.Name)
+	if scc, ok := pod.Annotations[SCCAnnotation]; ok && !arbitraryUIDSCCs[scc] {
+		violations = append(violations, PolicyViolation{resource, "arbitrary-uid", fmt.Sprintf("admitted with security context constraint %q, which does not assign arbitrary UIDs", scc)})
+	}
+	if security := pod.Spec.SecurityContext; security != nil {
+		if security.RunAsUser != nil && !uids.Contains(*security.RunAsUser) {
+			violations = append(violations, PolicyViolation{resource, "arbitrary-uid", fmt.Sprintf("runAsUser %d is outside the UID range %s of the namespace", *security.RunAsUser, uids)})
+		}
+		if security.FSGroup != nil && !groups.Contains(*security.FSGroup) {
+			violations = append(violations, PolicyViolation{resource, "arbitrary-uid", fmt.Sprintf("fsGroup %d is outside the group range %s of the namespace", *security.FSGroup, groups)})
+		}
+	}
+	containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, container := range containers {
+		if container.SecurityContext != nil && container.SecurityContext.RunAsUser != nil && !uids.Contains(*container.SecurityContext.RunAsUser) {
+			violations = append(violations, PolicyViolation{resource, "arbitrary-uid", fmt.Sprintf("container %q sets runAsUser %d, outside the UID range %s of the namespace", container.Name, *container.SecurityContext.RunAsUser, uids)})
+		}
+	}
+	return violations
+}
+
+// SetNamespaceUIDRange assigns the UIDs and groups of the pods created from now on in the namespace from the range,
+// and returns a function restoring the previous ranges
+func SetNamespaceUIDRange(t *testing.T, client kubernetes.Interface, namespace string, uidRange UIDRange) func() {
+	current, err := client.CoreV1().Namespaces().Get(context.Background(), namespace, metav1.GetOptions{})
+	require.NoError(t, err, "Failed to retrieve namespace %s", namespace)
+	previous := map[string]interface{}{}
+	for _, annotation := range []string{UIDRangeAnnotation, SupplementalGroupsAnnotation} {
+		if value, ok := current.Annotations[annotation]; ok {
+			previous[annotation] = value
+		} else {
+			previous[annotation] = nil
+		}
+	}
+
+	patch := func(annotations map[string]interface{}) error {
+		data, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"annotations": annotations}})
+		if err != nil {
+			return err
+		}
+		_, err = client.CoreV1().Namespaces().Patch(context.Background(), namespace, types.MergePatchType, data, metav1.PatchOptions{})
+		return err
+	}
+	err = patch(map[string]interface{}{UIDRangeAnnotation: uidRange.String(), SupplementalGroupsAnnotation: uidRange.String()})
+	require.NoError(t, err, "Failed to set the UID range of namespace %s", namespace)
+	return func() {
+		if err := patch(previous); err != nil {
+			t.Errorf("Failed to restore the UID range of namespace %s: %v", namespace, err)
+		}
+	}
+}
+
+// permissionDenied matches the errors of a process writing to a path it does not own
+var permissionDenied = regexp.MustCompile(`(?i)permission denied|read-only file system|operation not permitted`)
+
+// PermissionDeniedLines returns the log lines reporting a write denied to the user of the container, e.g. to a path
+// owned by root in the image
+func PermissionDeniedLines(logs []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(logs))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if permissionDenied.Match(scanner.Bytes()) {
+			lines = append(lines, scanner.Text())
+		}
+	}
+	return lines
+}
+
+// PodPermissionDenials returns, by pod and container, the log lines of the pods reporting a denied write
+func PodPermissionDenials(client kubernetes.Interface, pods []corev1.Pod) (map[string][]string, error) {
+	denials := map[string][]string{}
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			logs, err := client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: container.Name}).DoRaw(context.Background())
+			if err != nil {
+				return nil, fmt.Errorf("failed to retrieve logs of container %s of pod %s: %w", container.Name, pod.Name, err)
+			}
+			if lines := PermissionDeniedLines(logs); len(lines) > 0 {
+				denials[pod.Name+"/"+container.Name] = lines
+			}
+		}
+	}
+	return denials, nil
+}
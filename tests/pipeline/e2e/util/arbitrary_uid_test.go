@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func TestParseUIDRange(t *testing.T) {
+	r, err := ParseUIDRange("1000680000/10000")
+	require.NoError(t, err)
+	require.Equal(t, UIDRange{Start: 1000680000, Size: 10000}, r)
+	require.True(t, r.Contains(1000680000))
+	require.False(t, r.Contains(1000690000))
+
+	r, err = ParseUIDRange("1000680000/10000,2000000000/10000")
+	require.NoError(t, err)
+	require.Equal(t, "1000680000/10000", r.String())
+
+	_, err = ParseUIDRange("1000680000")
+	require.Error(t, err)
+	require.False(t, UIDRange{}.Contains(0))
+}
+
+func TestCheckArbitraryUID(t *testing.T) {
+	uids := UIDRange{Start: 1000680000, Size: 10000}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "sdg", Annotations: map[string]string{SCCAnnotation: "restricted-v2"}},
+		Spec: corev1.PodSpec{
+			SecurityContext: &corev1.PodSecurityContext{FSGroup: ptr.To[int64](1000680000)},
+			Containers:      []corev1.Container{{Name: "main", SecurityContext: &corev1.SecurityContext{RunAsUser: ptr.To[int64](1000680000)}}},
+		},
+	}
+	require.Empty(t, CheckArbitraryUID(pod, uids, uids))
+
+	pod.Annotations[SCCAnnotation] = "anyuid"
+	pod.Spec.SecurityContext.RunAsUser = ptr.To[int64](0)
+	pod.Spec.Containers[0].SecurityContext.RunAsUser = ptr.To[int64](1001)
+	violations := CheckArbitraryUID(pod, uids, uids)
+	require.Len(t, violations, 3)
+	require.Contains(t, violations[0].Message, `"anyuid"`)
+	require.Contains(t, violations[1].Message, "runAsUser 0")
+	require.Contains(t, violations[2].Message, `container "main" sets runAsUser 1001`)
+
+	// Outside OpenShift the namespace has no range, any UID set by the workload is hard-coded
+	require.Len(t, CheckArbitraryUID(pod, UIDRange{}, UIDRange{}), 4)
+}
+
+func TestSetNamespaceUIDRange(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "ilab",
+		Annotations: map[string]string{UIDRangeAnnotation: "1000680000/10000"},
+	}})
+	restore := SetNamespaceUIDRange(t, client, "ilab", UIDRange{Start: 1999990000, Size: 10000})
+
+	namespace, err := client.CoreV1().Namespaces().Get(context.Background(), "ilab", metav1.GetOptions{})
+	require.NoError(t, err)
+	uids, groups, err := NamespaceUIDRanges(namespace)
+	require.NoError(t, err)
+	require.Equal(t, UIDRange{Start: 1999990000, Size: 10000}, uids)
+	require.Equal(t, uids, groups)
+
+	restore()
+	namespace, err = client.CoreV1().Namespaces().Get(context.Background(), "ilab", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{UIDRangeAnnotation: "1000680000/10000"}, namespace.Annotations)
+}
+
+func TestPermissionDeniedLines(t *testing.T) {
+	logs := []byte("loading model\nOSError: [Errno 13] Permission denied: '/opt/app-root/.cache'\nmkdir: cannot create directory '/data': Read-only file system\ndone\n")
+	require.Equal(t, []string{
+		"OSError: [Errno 13] Permission denied: '/opt/app-root/.cache'",
+		"mkdir: cannot create directory '/data': Read-only file system",
+	}, PermissionDeniedLines(logs))
+}
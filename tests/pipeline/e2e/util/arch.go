@@ -0,0 +1,220 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/runcontrol"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ArchLabel is the well-known node label holding the CPU architecture
+	ArchLabel = "kubernetes.io/arch"
+	// TrainingJobNameLabel is set by the Training Operator on every PyTorchJob pod
+	TrainingJobNameLabel = "training.kubeflow.org/job-name"
+	// WorkflowLabel is set by Argo on every pod of a workflow, i.e. of a pipeline run
+	WorkflowLabel = "workflows.argoproj.io/workflow"
+	// DefaultGPUResource is the resource name of NVIDIA GPUs, matching the pipeline's default GPU identifier
+	DefaultGPUResource = "nvidia.com/gpu"
+)
+
+// GetGPUNodesByArch groups the names of the nodes with allocatable GPUs of the given resource by CPU architecture
+func GetGPUNodesByArch(t *testing.T, client kubernetes.Interface, gpuResource string) map[string][]string {
+	nodes, err := client.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err, "Failed to list nodes")
+
+	byArch := map[string][]string{}
+	for _, node := range nodes.Items {
+		gpus, ok := node.Status.Allocatable[corev1.ResourceName(gpuResource)]
+		if !ok || gpus.IsZero() {
+			continue
+		}
+		arch := node.Labels[ArchLabel]
+		byArch[arch] = append(byArch[arch], node.Name)
+	}
+	return byArch
+}
+
+// LoadArchImages reads the images to use on each CPU architecture from a YAML file
+func LoadArchImages(t *testing.T, path string) map[string]ImageCombination {
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig(), "Error loading architecture images")
+
+	var images map[string]ImageCombination
+	require.NoError(t, v.Unmarshal(&images), "Error parsing architecture images")
+	return images
+}
+
+// ImagesForArch selects the images for an architecture, failing when any of them is not configured
+func ImagesForArch(images map[string]ImageCombination, arch string) (ImageCombination, error) {
+	selected, ok := images[arch]
+	if !ok || selected.WorkbenchImage == "" || selected.SDGImage == "" || selected.TrainingImage == "" {
+		return ImageCombination{}, fmt.Errorf("images for architecture '%s' are not configured", arch)
+	}
+	return selected, nil
+}
+
+// TrainingJobNames returns the names of the PyTorchJobs the training phases of a run create, derived from the Argo
+// workflow of the run pods
+func TrainingJobNames(runPods []corev1.Pod) []string {
+	for _, pod := range runPods {
+		if workflow := pod.Labels[WorkflowLabel]; workflow != "" {
+			return runcontrol.TrainingJobNames(workflow)
+		}
+	}
+	return nil
+}
+
+// ListTrainingPods lists the PyTorchJob pods of a pipeline run, leaving out the training pods of other runs
+func ListTrainingPods(client kubernetes.Interface, namespace string, runPods []corev1.Pod) ([]corev1.Pod, error) {
+	jobs := TrainingJobNames(runPods)
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+	pods, err := client.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s in (%s)", TrainingJobNameLabel, strings.Join(jobs, ",")),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list training pods: %w", err)
+	}
+	return pods.Items, nil
+}
+
+// GetTrainingPods lists the PyTorchJob pods of a pipeline run
+func GetTrainingPods(t *testing.T, client kubernetes.Interface, namespace, runID string) []corev1.Pod {
+	pods, err := ListTrainingPods(client, namespace, GetRunPods(t, client, namespace, runID))
+	require.NoError(t, err, "Failed to list training pods")
+	return pods
+}
+
+// CheckPodsArchitecture reports the pods that were scheduled on nodes of another architecture than expected
+func CheckPodsArchitecture(t *testing.T, client kubernetes.Interface, pods []corev1.Pod, arch string) []string {
+	var problems []string
+	nodeArch := map[string]string{}
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		if _, ok := nodeArch[pod.Spec.NodeName]; !ok {
+			node, err := client.CoreV1().Nodes().Get(context.Background(), pod.Spec.NodeName, metav1.GetOptions{})
+			require.NoError(t, err, "Failed to retrieve node %s", pod.Spec.NodeName)
+			nodeArch[pod.Spec.NodeName] = node.Labels[ArchLabel]
+		}
+		if nodeArch[pod.Spec.NodeName] != arch {
+			problems = append(problems, fmt.Sprintf("pod %s ran on node %s with architecture '%s', expected '%s'", pod.Name, pod.Spec.NodeName, nodeArch[pod.Spec.NodeName], arch))
+		}
+	}
+	return problems
+}
+
+// PinPipelineNodeSelector adds the node selector labels to every executor of a compiled pipeline through its
+// Kubernetes platform spec, so every task pod of a run of the pipeline is scheduled on the selected nodes
+func PinPipelineNodeSelector(pipelineYAML []byte, labels map[string]string) ([]byte, error) {
+	selector := map[string]interface{}{}
+	for key, value := range labels {
+		selector[key] = value
+	}
+	return rewritePlatformExecutors(pipelineYAML, func(executor map[string]interface{}) {
+		executor["nodeSelector"] = map[string]interface{}{"labels": selector}
+	})
+}
+
+// rewritePlatformExecutors applies the rewrite to the Kubernetes platform spec of every executor of a compiled
+// pipeline, adding the platform spec when the pipeline has none
+func rewritePlatformExecutors(pipelineYAML []byte, rewrite func(executor map[string]interface{})) ([]byte, error) {
+	var documents []map[string]interface{}
+	decoder := yaml.NewDecoder(bytes.NewReader(pipelineYAML))
+	for {
+		var document map[string]interface{}
+		if err := decoder.Decode(&document); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse the pipeline: %w", err)
+		}
+		documents = append(documents, document)
+	}
+	if len(documents) == 0 {
+		return nil, fmt.Errorf("the pipeline is empty")
+	}
+
+	deploymentSpec, _ := documents[0]["deploymentSpec"].(map[string]interface{})
+	executors, _ := deploymentSpec["executors"].(map[string]interface{})
+	if len(executors) == 0 {
+		return nil, fmt.Errorf("the pipeline has no executors")
+	}
+	if len(documents) == 1 {
+		documents = append(documents, map[string]interface{}{})
+	}
+	platformExecutors := nestedMap(documents[1], "platforms", "kubernetes", "deploymentSpec", "executors")
+
+	for name := range executors {
+		executor, ok := platformExecutors[name].(map[string]interface{})
+		if !ok {
+			executor = map[string]interface{}{}
+			platformExecutors[name] = executor
+		}
+		rewrite(executor)
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	for _, document := range documents {
+		if err := encoder.Encode(document); err != nil {
+			return nil, err
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// nestedMap returns the map at the path of keys, creating the missing maps
+func nestedMap(m map[string]interface{}, keys ...string) map[string]interface{} {
+	for _, key := range keys {
+		next, ok := m[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			m[key] = next
+		}
+		m = next
+	}
+	return m
+}
+
+// WithArchNodeSelector adds the architecture node selector to the value of the train_node_selectors pipeline parameter
+func WithArchNodeSelector(selectors interface{}, arch string) map[string]interface{} {
+	merged := map[string]interface{}{}
+	if existing, ok := selectors.(map[string]interface{}); ok {
+		for key, value := range existing {
+			merged[key] = value
+		}
+	}
+	merged[ArchLabel] = arch
+	return merged
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTrainingJobNames(t *testing.T) {
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "no-workflow"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "task", Labels: map[string]string{WorkflowLabel: "instructlab-x7kgd"}}},
+	}
+	// rstrip("-sdg") also strips the trailing "gd" of the workflow name
+	require.Equal(t, []string{"train-phase-1-instructlab-x7k", "train-phase-2-instructlab-x7k"}, TrainingJobNames(pods))
+	require.Empty(t, TrainingJobNames(pods[:1]))
+}
+
+func TestPinPipelineNodeSelector(t *testing.T) {
+	pipeline := []byte(`deploymentSpec:
+  executors:
+    exec-a:
+      container:
+        image: a
+    exec-b:
+      container:
+        image: b
+---
+platforms:
+  kubernetes:
+    deploymentSpec:
+      executors:
+        exec-a:
+          pvcMount:
+          - mountPath: /data
+`)
+	pinned, err := PinPipelineNodeSelector(pipeline, map[string]string{ArchLabel: "arm64"})
+	require.NoError(t, err)
+
+	var spec, platform map[string]interface{}
+	documents := yaml.NewDecoder(bytes.NewReader(pinned))
+	require.NoError(t, documents.Decode(&spec))
+	require.NoError(t, documents.Decode(&platform))
+	executors := platform["platforms"].(map[string]interface{})["kubernetes"].(map[string]interface{})["deploymentSpec"].(map[string]interface{})["executors"].(map[string]interface{})
+	selector := map[string]interface{}{"labels": map[string]interface{}{ArchLabel: "arm64"}}
+	require.Equal(t, selector, executors["exec-a"].(map[string]interface{})["nodeSelector"])
+	require.NotNil(t, executors["exec-a"].(map[string]interface{})["pvcMount"])
+	require.Equal(t, selector, executors["exec-b"].(map[string]interface{})["nodeSelector"])
+	require.Contains(t, spec, "deploymentSpec")
+
+	_, err = PinPipelineNodeSelector([]byte("pipelineInfo:\n  name: empty\n"), nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/dag"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/watcher"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// ArgoWorkflowLabel is the label Argo sets on the pods of a Workflow
+const ArgoWorkflowLabel = "workflows.argoproj.io/workflow"
+
+var ArgoWorkflowGVR = schema.GroupVersionResource{
+	Group:    "argoproj.io",
+	Version:  "v1alpha1",
+	Resource: "workflows",
+}
+
+// argoNodeStates maps the phases of the nodes of a Workflow to task states, the other phases are running
+var argoNodeStates = map[string]dag.State{
+	"Pending":   dag.Pending,
+	"Succeeded": dag.Succeeded,
+	"Failed":    dag.Failed,
+	"Error":     dag.Failed,
+}
+
+// ArgoBackend runs the workflow as a Workflow of upstream Argo Workflows
+type ArgoBackend struct{}
+
+func (ArgoBackend) CRD() string                           { return "workflows.argoproj.io" }
+func (ArgoBackend) Kind() schema.GroupVersionKind         { return watcher.ArgoWorkflowGVK }
+func (ArgoBackend) Resource() schema.GroupVersionResource { return ArgoWorkflowGVR }
+
+func (ArgoBackend) Succeeded(run *unstructured.Unstructured) (bool, error) {
+	return watcher.ArgoWorkflowSucceeded(run)
+}
+
+func (ArgoBackend) PodSelector(name string) string {
+	return fmt.Sprintf("%s=%s", ArgoWorkflowLabel, name)
+}
+
+// NewRun builds a Workflow running the tasks as a DAG, each task depending on the tasks producing its inputs. Its pods carry the
+// name of the Workflow as run ID label, so the checks selecting the pods of a run by run ID apply to it. The Workflow,
+// its pods and its data PVC carry the labels of the run.
+func (ArgoBackend) NewRun(workflow Workflow, config WorkflowRunConfig) (*unstructured.Unstructured, error) {
+	names, err := workflowParams(workflow, config)
+	if err != nil {
+		return nil, err
+	}
+	graph, err := workflow.Graph()
+	if err != nil {
+		return nil, err
+	}
+
+	var dag, templates []interface{}
+	for _, task := range workflow.Tasks {
+		script := workflowTaskContainer(task, config, func(param string) string { return fmt.Sprintf("{{inputs.parameters.%s}}", param) },
+			"/data", "/model")
+		script["command"] = []interface{}{"sh"}
+		script["source"] = task.Script
+		script["volumeMounts"] = []interface{}{
+			map[string]interface{}{"name": "data", "mountPath": "/data"},
+			map[string]interface{}{"name": "model", "mountPath": "/model", "readOnly": true},
+		}
+
+		var inputs, arguments []interface{}
+		for _, name := range task.Params {
+			inputs = append(inputs, map[string]interface{}{"name": name})
+			arguments = append(arguments, map[string]interface{}{"name": name, "value": fmt.Sprintf("{{workflow.parameters.%s}}", name)})
+		}
+		template := map[string]interface{}{
+			"name":     task.Name,
+			"metadata": map[string]interface{}{"labels": map[string]interface{}{WorkflowTaskLabel: task.Name}},
+			"script":   script,
+		}
+		dagTask := map[string]interface{}{"name": task.Name, "template": task.Name}
+		if len(inputs) > 0 {
+			template["inputs"] = map[string]interface{}{"parameters": inputs}
+			dagTask["arguments"] = map[string]interface{}{"parameters": arguments}
+		}
+		if upstream := graph.Upstream(task.Name); len(upstream) > 0 {
+			dagTask["dependencies"] = unstructuredStrings(upstream)
+		}
+		templates = append(templates, template)
+		dag = append(dag, dagTask)
+	}
+	templates = append([]interface{}{map[string]interface{}{"name": "ilab", "dag": map[string]interface{}{"tasks": dag}}}, templates...)
+
+	var parameters []interface{}
+	for _, name := range names {
+		parameters = append(parameters, map[string]interface{}{"name": name, "value": fmt.Sprint(config.Params[name])})
+	}
+
+	spec := map[string]interface{}{
+		"entrypoint":  "ilab",
+		"arguments":   map[string]interface{}{"parameters": parameters},
+		"templates":   templates,
+		"podMetadata": map[string]interface{}{"labels": unstructuredLabels(config.RunLabels())},
+	}
+	volumes := []interface{}{map[string]interface{}{
+		"name":                  "model",
+		"persistentVolumeClaim": map[string]interface{}{"claimName": config.ModelPVC, "readOnly": true},
+	}}
+	if config.DataPVC != "" {
+		volumes = append(volumes, map[string]interface{}{
+			"name":                  "data",
+			"persistentVolumeClaim": map[string]interface{}{"claimName": config.DataPVC},
+		})
+	} else {
+		spec["volumeClaimTemplates"] = []interface{}{map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "data", "labels": unstructuredLabels(config.RunLabels())},
+			"spec":     workflowClaimSpec(workflow, config),
+		}}
+	}
+	spec["volumes"] = volumes
+	if config.Timeout > 0 {
+		spec["activeDeadlineSeconds"] = int64(config.Timeout.Seconds())
+	}
+	// The Workflow spec has the DNS fields of a pod spec, applied to every pod of the workflow
+	dns, err := config.DNS.Unstructured()
+	if err != nil {
+		return nil, err
+	}
+	for field, value := range dns {
+		spec[field] = value
+	}
+
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Workflow",
+		"metadata": map[string]interface{}{
+			"name":   config.Name,
+			"labels": unstructuredLabels(config.RunLabels()),
+		},
+		"spec": spec,
+	}}, nil
+}
+
+// TaskStates reads the state of the tasks from the nodes of a Workflow
+func (ArgoBackend) TaskStates(client dynamic.Interface, namespace, name string, workflow Workflow) ([]WorkflowTaskState, error) {
+	run, err := client.Resource(ArgoWorkflowGVR).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Workflow %s: %w", name, err)
+	}
+	return ArgoNodeStates(workflow, run), nil
+}
+
+// ArgoNodeStates returns the state of the pod nodes of the workflow tasks in a Workflow, skipping the node of the DAG
+// itself and the nodes of other templates
+func ArgoNodeStates(workflow Workflow, run *unstructured.Unstructured) []WorkflowTaskState {
+	nodes, _, _ := unstructured.NestedMap(run.Object, "status", "nodes")
+	var ids []string
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var states []WorkflowTaskState
+	for _, id := range ids {
+		node, ok := nodes[id].(map[string]interface{})
+		if !ok || node["type"] != "Pod" {
+			continue
+		}
+		task, _ := node["displayName"].(string)
+		phase, ok := workflow.taskPhase(task)
+		if !ok {
+			continue
+		}
+		state := dag.Running
+		if nodePhase, _ := node["phase"].(string); argoNodeStates[nodePhase] != "" {
+			state = argoNodeStates[nodePhase]
+		}
+		states = append(states, WorkflowTaskState{Task: task, Phase: phase, State: state})
+	}
+	return states
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// Backends of the artifact store, selected with ARTIFACT_STORE
+const (
+	ArtifactStoreS3    = "s3"
+	ArtifactStoreGCS   = "gcs"
+	ArtifactStoreAzure = "azure"
+	ArtifactStorePVC   = "pvc"
+
+	gcsInteroperabilityEndpoint = "https://storage.googleapis.com"
+)
+
+// ArtifactObject is an object of an artifact store
+type ArtifactObject struct {
+	Key  string
+	Size int64
+}
+
+// ArtifactStore is the storage holding the run artifacts. The helpers accessing run artifacts only use this
+// interface, so a new backend only needs an implementation and a case in NewArtifactStoreFromEnv.
+type ArtifactStore interface {
+	// Put stores size bytes read from content under the key
+	Put(ctx context.Context, key string, content io.Reader, size int64) error
+	// Get returns the content stored under the key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// List returns the objects stored under the prefix, recursively
+	List(ctx context.Context, prefix string) ([]ArtifactObject, error)
+	// Delete removes the objects stored under the keys, missing objects are ignored
+	Delete(ctx context.Context, keys ...string) error
+	// SignedURL returns a URL granting read access to the object stored under the key until the expiry
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+	// PipelineRoot returns the pipeline root storing the artifacts of a run under the prefix, empty when the
+	// backend cannot be used as a pipeline root
+	PipelineRoot(prefix string) string
+}
+
+// NewArtifactStoreFromEnv creates the artifact store selected with ARTIFACT_STORE, s3 by default
+func NewArtifactStoreFromEnv() (ArtifactStore, error) {
+	switch backend := os.Getenv("ARTIFACT_STORE"); backend {
+	case "", ArtifactStoreS3:
+		return NewS3ArtifactStore(ObjectStoreConfigFromEnv())
+	case ArtifactStoreGCS:
+		return NewGCSArtifactStore(ObjectStoreConfigFromEnv())
+	case ArtifactStoreAzure:
+		return NewAzureArtifactStore(AzureStoreConfigFromEnv())
+	case ArtifactStorePVC:
+		return NewPVCArtifactStore(os.Getenv("ARTIFACT_STORE_PATH"))
+	default:
+		return nil, fmt.Errorf("unknown artifact store '%s'", backend)
+	}
+}
+
+// s3ArtifactStore stores the artifacts in an S3 compatible bucket
+type s3ArtifactStore struct {
+	client *minio.Client
+	bucket string
+	scheme string
+}
+
+// NewS3ArtifactStore creates an artifact store for the bucket of the object store settings
+func NewS3ArtifactStore(config ObjectStoreConfig) (ArtifactStore, error) {
+	client, err := NewObjectStoreClient(config)
+	if err != nil {
+		return nil, err
+	}
+	return &s3ArtifactStore{client: client, bucket: config.Bucket, scheme: "s3"}, nil
+}
+
+// NewGCSArtifactStore creates an artifact store for a Google Cloud Storage bucket, accessed through its S3
+// interoperability API with an HMAC key set as access/secret key pair
+func NewGCSArtifactStore(config ObjectStoreConfig) (ArtifactStore, error) {
+	if config.Endpoint == "" {
+		config.Endpoint = gcsInteroperabilityEndpoint
+	}
+	client, err := NewObjectStoreClient(config)
+	if err != nil {
+		return nil, err
+	}
+	return &s3ArtifactStore{client: client, bucket: config.Bucket, scheme: "gs"}, nil
+}
+
+func (s *s3ArtifactStore) Put(ctx context.Context, key string, content io.Reader, size int64) error {
+	if _, err := s.client.PutObject(ctx, s.bucket, key, content, size, minio.PutObjectOptions{}); err != nil {
+		return fmt.Errorf("failed to store %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *s3ArtifactStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	object, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	return object, nil
+}
+
+func (s *s3ArtifactStore) List(ctx context.Context, prefix string) ([]ArtifactObject, error) {
+	var objects []ArtifactObject
+	for object := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("failed to list bucket %s: %w", s.bucket, object.Err)
+		}
+		objects = append(objects, ArtifactObject{Key: object.Key, Size: object.Size})
+	}
+	return objects, nil
+}
+
+func (s *s3ArtifactStore) Delete(ctx context.Context, keys ...string) error {
+	objects := make(chan minio.ObjectInfo, len(keys))
+	for _, key := range keys {
+		objects <- minio.ObjectInfo{Key: key}
+	}
+	close(objects)
+	for result := range s.client.RemoveObjects(ctx, s.bucket, objects, minio.RemoveObjectsOptions{}) {
+		if result.Err != nil {
+			return fmt.Errorf("failed to delete %s: %w", result.ObjectName, result.Err)
+		}
+	}
+	return nil
+}
+
+func (s *s3ArtifactStore) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	signed, err := s.client.PresignedGetObject(ctx, s.bucket, key, expiry, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign %s: %w", key, err)
+	}
+	return signed.String(), nil
+}
+
+func (s *s3ArtifactStore) PipelineRoot(prefix string) string {
+	return fmt.Sprintf("%s://%s/%s", s.scheme, s.bucket, prefix)
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	azureSASVersion = "2020-12-06"
+	// azureRequestExpiry is the validity of the SAS signing a single request
+	azureRequestExpiry = time.Hour
+)
+
+// AzureStoreConfig describes how to reach the Azure Blob Storage container holding the run artifacts. Either the
+// account key or a container SAS token is used for authentication.
+type AzureStoreConfig struct {
+	Account   string
+	Container string
+	// Endpoint is https://<account>.blob.core.windows.net by default, or e.g. the URL of an Azurite emulator
+	Endpoint   string
+	AccountKey string
+	SASToken   string
+}
+
+// AzureStoreConfigFromEnv reads the Azure Blob Storage settings from environment variables
+func AzureStoreConfigFromEnv() AzureStoreConfig {
+	return AzureStoreConfig{
+		Account:    os.Getenv("AZURE_STORAGE_ACCOUNT"),
+		Container:  os.Getenv("AZURE_STORAGE_CONTAINER"),
+		Endpoint:   os.Getenv("AZURE_STORAGE_ENDPOINT"),
+		AccountKey: os.Getenv("AZURE_STORAGE_KEY"),
+		SASToken:   os.Getenv("AZURE_STORAGE_SAS_TOKEN"),
+	}
+}
+
+// azureArtifactStore stores the artifacts in an Azure Blob Storage container through its REST API
+type azureArtifactStore struct {
+	config AzureStoreConfig
+	key    []byte
+	client *http.Client
+}
+
+type azureBlobList struct {
+	Blobs struct {
+		Blob []struct {
+			Name       string `xml:"Name"`
+			Properties struct {
+				ContentLength int64 `xml:"Content-Length"`
+			} `xml:"Properties"`
+		} `xml:"Blob"`
+	} `xml:"Blobs"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+// NewAzureArtifactStore creates an artifact store for an Azure Blob Storage container
+func NewAzureArtifactStore(config AzureStoreConfig) (ArtifactStore, error) {
+	if config.Account == "" || config.Container == "" {
+		return nil, fmt.Errorf("azure storage account and container must be set")
+	}
+	if config.AccountKey == "" && config.SASToken == "" {
+		return nil, fmt.Errorf("azure storage account key or SAS token must be set")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", config.Account)
+	}
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+	config.SASToken = strings.TrimPrefix(config.SASToken, "?")
+
+	store := &azureArtifactStore{config: config, client: http.DefaultClient}
+	if config.AccountKey != "" {
+		key, err := base64.StdEncoding.DecodeString(config.AccountKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid azure storage account key: %w", err)
+		}
+		store.key = key
+	}
+	return store, nil
+}
+
+func (s *azureArtifactStore) Put(ctx context.Context, key string, content io.Reader, size int64) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, s.blobURL(key, s.containerSAS()), content)
+	if err != nil {
+		return err
+	}
+	request.ContentLength = size
+	request.Header.Set("x-ms-blob-type", "BlockBlob")
+	response, err := s.do(request, http.StatusCreated)
+	if err != nil {
+		return fmt.Errorf("failed to store %s: %w", key, err)
+	}
+	return response.Body.Close()
+}
+
+func (s *azureArtifactStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, s.blobURL(key, s.containerSAS()), nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := s.do(request, http.StatusOK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	return response.Body, nil
+}
+
+func (s *azureArtifactStore) List(ctx context.Context, prefix string) ([]ArtifactObject, error) {
+	var objects []ArtifactObject
+	marker := ""
+	for {
+		query := s.containerSAS()
+		query.Set("restype", "container")
+		query.Set("comp", "list")
+		query.Set("prefix", prefix)
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, s.config.Endpoint+"/"+url.PathEscape(s.config.Container)+"?"+query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		response, err := s.do(request, http.StatusOK)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list container %s: %w", s.config.Container, err)
+		}
+		var list azureBlobList
+		err = xml.NewDecoder(response.Body).Decode(&list)
+		response.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("invalid blob list of container %s: %w", s.config.Container, err)
+		}
+
+		for _, blob := range list.Blobs.Blob {
+			objects = append(objects, ArtifactObject{Key: blob.Name, Size: blob.Properties.ContentLength})
+		}
+		if list.NextMarker == "" {
+			return objects, nil
+		}
+		marker = list.NextMarker
+	}
+}
+
+func (s *azureArtifactStore) Delete(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		request, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.blobURL(key, s.containerSAS()), nil)
+		if err != nil {
+			return err
+		}
+		response, err := s.do(request, http.StatusAccepted, http.StatusNotFound)
+		if err != nil {
+			return fmt.Errorf("failed to delete %s: %w", key, err)
+		}
+		response.Body.Close()
+	}
+	return nil
+}
+
+func (s *azureArtifactStore) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	if s.key == nil {
+		// A SAS token cannot be narrowed down, the URL grants the access of the configured token
+		return s.blobURL(key, s.containerSAS()), nil
+	}
+	return s.blobURL(key, s.sign("b", "r", key, time.Now().Add(expiry))), nil
+}
+
+func (s *azureArtifactStore) PipelineRoot(prefix string) string {
+	return fmt.Sprintf("azblob://%s/%s", s.config.Container, prefix)
+}
+
+// containerSAS returns the query authorizing requests on the container
+func (s *azureArtifactStore) containerSAS() url.Values {
+	if s.key == nil {
+		query, _ := url.ParseQuery(s.config.SASToken)
+		return query
+	}
+	return s.sign("c", "racwdl", "", time.Now().Add(azureRequestExpiry))
+}
+
+// sign returns the query of a service SAS for the container, or for a blob of the container with resource "b"
+func (s *azureArtifactStore) sign(resource, permissions, blob string, expiry time.Time) url.Values {
+	canonical := fmt.Sprintf("/blob/%s/%s", s.config.Account, s.config.Container)
+	if blob != "" {
+		canonical += "/" + blob
+	}
+	signedExpiry := expiry.UTC().Format(time.RFC3339)
+	// Fields: permissions, start, expiry, resource, identifier, IP, protocol, version, resource type, snapshot time,
+	// encryption scope and the cache-control, content-disposition, -encoding, -language and -type overrides
+	stringToSign := strings.Join([]string{
+		permissions, "", signedExpiry, canonical, "", "", "", azureSASVersion, resource, "", "", "", "", "", "", "",
+	}, "\n")
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(stringToSign))
+
+	query := url.Values{}
+	query.Set("sv", azureSASVersion)
+	query.Set("sp", permissions)
+	query.Set("se", signedExpiry)
+	query.Set("sr", resource)
+	query.Set("sig", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	return query
+}
+
+func (s *azureArtifactStore) blobURL(key string, query url.Values) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return fmt.Sprintf("%s/%s/%s?%s", s.config.Endpoint, url.PathEscape(s.config.Container), strings.Join(segments, "/"), query.Encode())
+}
+
+// do sends the request and returns the response when its status is one of the expected ones
+func (s *azureArtifactStore) do(request *http.Request, expected ...int) (*http.Response, error) {
+	request.Header.Set("x-ms-version", azureSASVersion)
+	response, err := s.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range expected {
+		if response.StatusCode == status {
+			return response, nil
+		}
+	}
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+	response.Body.Close()
+	return nil, fmt.Errorf("unexpected status %s: %s", response.Status, body)
+}
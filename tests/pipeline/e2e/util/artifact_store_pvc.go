@@ -0,0 +1,135 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// pvcArtifactStore stores the artifacts in a PVC mounted into the pod running the suite
+type pvcArtifactStore struct {
+	root string
+}
+
+// NewPVCArtifactStore creates an artifact store for the PVC mounted at the given path. It is meant for suites running
+// in a pod of the cluster; signed URLs are not supported and it cannot be used as a pipeline root.
+func NewPVCArtifactStore(mountPath string) (ArtifactStore, error) {
+	if mountPath == "" {
+		return nil, fmt.Errorf("PVC mount path must be set")
+	}
+	info, err := os.Stat(mountPath)
+	if err != nil {
+		return nil, fmt.Errorf("PVC mount path '%s' is not accessible: %w", mountPath, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("PVC mount path '%s' is not a directory", mountPath)
+	}
+	return &pvcArtifactStore{root: mountPath}, nil
+}
+
+func (s *pvcArtifactStore) Put(ctx context.Context, key string, content io.Reader, size int64) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to store %s: %w", key, err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to store %s: %w", key, err)
+	}
+	defer file.Close()
+	if _, err := io.CopyN(file, content, size); err != nil {
+		return fmt.Errorf("failed to store %s: %w", key, err)
+	}
+	return file.Close()
+}
+
+func (s *pvcArtifactStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	return file, nil
+}
+
+func (s *pvcArtifactStore) List(ctx context.Context, prefix string) ([]ArtifactObject, error) {
+	var objects []ArtifactObject
+	err := filepath.WalkDir(s.root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		relative, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(relative)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, ArtifactObject{Key: key, Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", s.root, err)
+	}
+	return objects, nil
+}
+
+func (s *pvcArtifactStore) Delete(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		path, err := s.path(key)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to delete %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+func (s *pvcArtifactStore) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return "", fmt.Errorf("signed URLs are not supported by the PVC artifact store")
+}
+
+func (s *pvcArtifactStore) PipelineRoot(prefix string) string {
+	return ""
+}
+
+// path returns the file of a key, keys escaping the mount path are rejected
+func (s *pvcArtifactStore) path(key string) (string, error) {
+	path := filepath.Join(s.root, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, filepath.Clean(s.root)+string(filepath.Separator)) {
+		return "", fmt.Errorf("key '%s' is outside of the PVC", key)
+	}
+	return path, nil
+}
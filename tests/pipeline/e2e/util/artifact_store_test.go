@@ -0,0 +1,108 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPVCArtifactStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewPVCArtifactStore(t.TempDir())
+	require.NoError(t, err)
+
+	prefix := "runs/20250301T120000Z-abc/"
+	for _, key := range []string{prefix + "sdg/data.jsonl", prefix + "model/config.json", "other/file"} {
+		require.NoError(t, store.Put(ctx, key, strings.NewReader("content"), 7))
+	}
+
+	keys, err := ListObjectKeys(store, prefix)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{prefix + "sdg/data.jsonl", prefix + "model/config.json"}, keys)
+
+	prefixes, err := ListRunPrefixes(store)
+	require.NoError(t, err)
+	require.Len(t, prefixes, 1)
+	require.Equal(t, RunPrefixUsage{Prefix: prefix, Start: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC), Objects: 2, Size: 14}, prefixes[0])
+
+	content, err := store.Get(ctx, prefix+"model/config.json")
+	require.NoError(t, err)
+	data, err := io.ReadAll(content)
+	require.NoError(t, err)
+	require.NoError(t, content.Close())
+	require.Equal(t, "content", string(data))
+
+	require.NoError(t, DeleteRunPrefix(store, prefix))
+	keys, err = ListObjectKeys(store, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"other/file"}, keys)
+
+	require.Error(t, store.Put(ctx, "../escape", strings.NewReader(""), 0))
+	_, err = store.SignedURL(ctx, "other/file", time.Hour)
+	require.Error(t, err)
+	require.Empty(t, store.PipelineRoot(prefix))
+}
+
+func TestAzureArtifactStore(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "c", query.Get("sr"))
+		assert.NotEmpty(t, query.Get("sig"))
+
+		switch {
+		case r.Method == http.MethodGet && query.Get("comp") == "list" && query.Get("marker") == "":
+			_, _ = io.WriteString(w, `<EnumerationResults><Blobs><Blob><Name>runs/a/one</Name><Properties><Content-Length>3</Content-Length></Properties></Blob></Blobs><NextMarker>next</NextMarker></EnumerationResults>`)
+		case r.Method == http.MethodGet && query.Get("comp") == "list":
+			_, _ = io.WriteString(w, `<EnumerationResults><Blobs><Blob><Name>runs/a/two</Name><Properties><Content-Length>5</Content-Length></Properties></Blob></Blobs><NextMarker/></EnumerationResults>`)
+		case r.Method == http.MethodPut:
+			assert.Equal(t, "/artifacts/runs/a/my%20file", r.URL.EscapedPath())
+			assert.Equal(t, "BlockBlob", r.Header.Get("x-ms-blob-type"))
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	store, err := NewAzureArtifactStore(AzureStoreConfig{
+		Account:    "account",
+		Container:  "artifacts",
+		Endpoint:   server.URL,
+		AccountKey: "a2V5",
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	objects, err := store.List(ctx, "runs/")
+	require.NoError(t, err)
+	require.Equal(t, []ArtifactObject{{Key: "runs/a/one", Size: 3}, {Key: "runs/a/two", Size: 5}}, objects)
+	require.NoError(t, store.Put(ctx, "runs/a/my file", strings.NewReader("x"), 1))
+	_, err = store.Get(ctx, "runs/a/one")
+	require.ErrorContains(t, err, "403 Forbidden")
+
+	signed, err := store.SignedURL(ctx, "runs/a/one", time.Hour)
+	require.NoError(t, err)
+	require.Contains(t, signed, "sp=r")
+	require.Contains(t, signed, "sr=b")
+	require.Equal(t, "azblob://artifacts/runs/a/", store.PipelineRoot("runs/a/"))
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+// BatchRun is one run of the batch mode, e.g. one base model to certify
+type BatchRun struct {
+	Name   string                 `mapstructure:"name"`
+	Params map[string]interface{} `mapstructure:"params"`
+}
+
+// BatchResult is the outcome of a run of the batch mode
+type BatchResult struct {
+	Run      BatchRun
+	RunID    string
+	Duration time.Duration
+	Err      error
+}
+
+// LoadBatchRuns reads the runs of the batch mode from a YAML file
+func LoadBatchRuns(t *testing.T, path string) []BatchRun {
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig(), "Error loading batch runs")
+
+	var batch struct {
+		Runs []BatchRun `mapstructure:"runs"`
+	}
+	require.NoError(t, v.Unmarshal(&batch), "Error parsing batch runs")
+	return batch.Runs
+}
+
+// RenderBatchReport renders the results of the batch mode as a markdown table
+func RenderBatchReport(results []BatchResult) string {
+	var report strings.Builder
+	passed := 0
+	for _, result := range results {
+		if result.Err == nil {
+			passed++
+		}
+	}
+	report.WriteString("# Batch runs\n\n")
+	fmt.Fprintf(&report, "%d of %d runs passed.\n\n", passed, len(results))
+	report.WriteString("| Run | Base model | Result | Duration | Run ID |\n")
+	report.WriteString("|---|---|---|---|---|\n")
+	for _, result := range results {
+		status := "PASS"
+		if result.Err != nil {
+			status = fmt.Sprintf("FAIL: %s", result.Err)
+		}
+		fmt.Fprintf(&report, "| %s | %v | %s | %s | %s |\n",
+			result.Run.Name, result.Run.Params["sdg_base_model"], status, result.Duration.Round(time.Second), result.RunID)
+	}
+	return report.String()
+}
@@ -0,0 +1,32 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderBatchReport(t *testing.T) {
+	report := RenderBatchReport([]BatchResult{
+		{Run: BatchRun{Name: "granite", Params: map[string]interface{}{"sdg_base_model": "s3://models/granite"}}, RunID: "run-1", Duration: 90 * time.Minute},
+		{Run: BatchRun{Name: "mistral", Params: map[string]interface{}{"sdg_base_model": "s3://models/mistral"}}, Duration: time.Minute, Err: errors.New("pipeline run failed with status: FAILED")},
+	})
+	require.Contains(t, report, "1 of 2 runs passed.")
+	require.Contains(t, report, "| granite | s3://models/granite | PASS | 1h30m0s | run-1 |")
+	require.Contains(t, report, "| mistral | s3://models/mistral | FAIL: pipeline run failed with status: FAILED | 1m0s |  |")
+}
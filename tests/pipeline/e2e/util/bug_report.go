@@ -0,0 +1,372 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// FailureClassProduct is a defect of the pipeline, its images or the operators
+	FailureClassProduct = "product"
+	// FailureClassEnvironment is a problem of the cluster, its capacity or its configuration
+	FailureClassEnvironment = "environment"
+)
+
+// bugReportLogLines is the length of the log tails of the bug report bundles
+const bugReportLogLines = 500
+
+// bugReportExcerptLines is the length of the log excerpt of the summary of a bug report
+const bugReportExcerptLines = 30
+
+// waitingFailures are the waiting reasons of containers which never start without a change to the run
+var waitingFailures = map[string]bool{
+	"ErrImagePull":               true,
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CrashLoopBackOff":           true,
+}
+
+// FailureRule classifies the failed containers whose reason or log matches its pattern
+type FailureRule struct {
+	Name    string `mapstructure:"name"`
+	Class   string `mapstructure:"class"`
+	Pattern string `mapstructure:"pattern"`
+}
+
+// LoadFailureRules reads the failure classes of failure_classes.yaml, in order
+func LoadFailureRules(t *testing.T, path string) []FailureRule {
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig(), "Error loading failure classes")
+
+	var config struct {
+		Rules []FailureRule `mapstructure:"rules"`
+	}
+	require.NoError(t, v.UnmarshalExact(&config), "Error parsing failure classes")
+	for _, rule := range config.Rules {
+		require.Contains(t, []string{FailureClassProduct, FailureClassEnvironment}, rule.Class, "Invalid class of failure rule %s", rule.Name)
+		_, err := regexp.Compile(rule.Pattern)
+		require.NoError(t, err, "Invalid pattern of failure rule %s", rule.Name)
+	}
+	return config.Rules
+}
+
+// PodFailure is a container of a run pod which failed, terminated with a non-zero exit code or unable to start
+type PodFailure struct {
+	Pod       corev1.Pod
+	Container string
+	// Reason is the termination or waiting reason of the container, with its message
+	Reason   string
+	ExitCode int32
+	// Log is the tail of the log of the container, empty until read
+	Log string
+}
+
+// FailedContainers returns the failed containers of the pods, init containers included
+func FailedContainers(pods []corev1.Pod) []PodFailure {
+	var failures []PodFailure
+	for _, pod := range pods {
+		statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			failure := PodFailure{Pod: pod, Container: status.Name}
+			switch {
+			case status.State.Terminated != nil && status.State.Terminated.ExitCode != 0:
+				failure.Reason = strings.TrimSpace(status.State.Terminated.Reason + " " + status.State.Terminated.Message)
+				failure.ExitCode = status.State.Terminated.ExitCode
+			case status.State.Waiting != nil && waitingFailures[status.State.Waiting.Reason]:
+				failure.Reason = strings.TrimSpace(status.State.Waiting.Reason + " " + status.State.Waiting.Message)
+			default:
+				continue
+			}
+			failures = append(failures, failure)
+		}
+	}
+	return failures
+}
+
+// ReadFailureLogs reads the log tail of every failed container, the containers which never started have none
+func ReadFailureLogs(client kubernetes.Interface, failures []PodFailure) {
+	tail := int64(bugReportLogLines)
+	for i, failure := range failures {
+		logs, err := client.CoreV1().Pods(failure.Pod.Namespace).GetLogs(failure.Pod.Name, &corev1.PodLogOptions{Container: failure.Container, TailLines: &tail}).DoRaw(context.Background())
+		if err == nil {
+			failures[i].Log = string(logs)
+		}
+	}
+}
+
+// FailureClassification is the rule classifying a run failure and the failed container it matched
+type FailureClassification struct {
+	Rule    FailureRule
+	Failure PodFailure
+	// Evidence is the reason or log line the rule matched
+	Evidence string
+}
+
+// ClassifyRunFailure returns the first rule matching a failed container of the run, false when none does
+func ClassifyRunFailure(rules []FailureRule, failures []PodFailure) (FailureClassification, bool) {
+	for _, rule := range rules {
+		pattern := regexp.MustCompile(rule.Pattern)
+		for _, failure := range failures {
+			if pattern.MatchString(failure.Reason) {
+				return FailureClassification{Rule: rule, Failure: failure, Evidence: failure.Reason}, true
+			}
+			for _, line := range strings.Split(failure.Log, "\n") {
+				if pattern.MatchString(line) {
+					return FailureClassification{Rule: rule, Failure: failure, Evidence: strings.TrimSpace(line)}, true
+				}
+			}
+		}
+	}
+	return FailureClassification{}, false
+}
+
+// BugReport is the issue report bundle of a run failure: a summary to paste into the tracker, and the logs and
+// resource YAML usually asked for
+type BugReport struct {
+	RunID          string
+	Classification FailureClassification
+	Failures       []PodFailure
+	Params         map[string]interface{}
+	// Environment is the environment matrix, the images of the tasks included, by name
+	Environment map[string]string
+	// Logs and Resources are the contents of the files of the bundle by file name
+	Logs      map[string]string
+	Resources map[string][]byte
+	// Missing is what could not be collected, with why
+	Missing []string
+}
+
+// CollectBugReport gathers the bundle of a run failure: the failed containers with their log tails, the YAML of
+// their pods and of the PyTorchJobs of the run, the log of the Training Operator and the environment matrix.
+// Collection failures are recorded in Missing, the bundle is written with what could be collected.
+func CollectBugReport(client kubernetes.Interface, dynamicClient dynamic.Interface, settings ProductSettings, namespace, runID string, tasks []TaskPod, trainingPods []corev1.Pod, classification FailureClassification, failures []PodFailure, params map[string]interface{}) BugReport {
+	report := BugReport{
+		RunID:          runID,
+		Classification: classification,
+		Failures:       failures,
+		Params:         params,
+		Environment:    map[string]string{"Product": string(settings.Mode)},
+		Logs:           map[string]string{},
+		Resources:      map[string][]byte{},
+	}
+
+	failedPods := map[string]bool{}
+	for _, failure := range failures {
+		if failure.Log != "" {
+			report.Logs[fmt.Sprintf("%s_%s.log", failure.Pod.Name, failure.Container)] = failure.Log
+		}
+		if failedPods[failure.Pod.Name] {
+			continue
+		}
+		failedPods[failure.Pod.Name] = true
+		pod := failure.Pod.DeepCopy()
+		pod.ManagedFields = nil
+		report.addResource("Pod", pod.Name, pod)
+	}
+
+	var runPods []corev1.Pod
+	for _, task := range tasks {
+		runPods = append(runPods, task.Pod)
+	}
+	for _, name := range TrainingJobNames(runPods) {
+		job, err := dynamicClient.Resource(PyTorchJobGVR).Namespace(namespace).Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			report.Missing = append(report.Missing, fmt.Sprintf("PyTorchJob %s: %v", name, err))
+			continue
+		}
+		unstructured.RemoveNestedField(job.Object, "metadata", "managedFields")
+		report.addResource("PyTorchJob", name, job.Object)
+	}
+	for _, pod := range trainingPods {
+		if failedPods[pod.Name] {
+			continue
+		}
+		// The logs of the workers which did not fail show how far the training went
+		tail := int64(bugReportLogLines)
+		logs, err := client.CoreV1().Pods(namespace).GetLogs(pod.Name, &corev1.PodLogOptions{TailLines: &tail}).DoRaw(context.Background())
+		if err != nil {
+			report.Missing = append(report.Missing, fmt.Sprintf("log of training pod %s: %v", pod.Name, err))
+			continue
+		}
+		report.Logs[pod.Name+".log"] = string(logs)
+	}
+
+	report.collectOperator(client, settings.ApplicationsNamespace)
+	if version, err := client.Discovery().ServerVersion(); err != nil {
+		report.Missing = append(report.Missing, fmt.Sprintf("Kubernetes version: %v", err))
+	} else {
+		report.Environment["Kubernetes"] = version.GitVersion
+	}
+	if nodes, err := client.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{LabelSelector: "nvidia.com/gpu.product"}); err != nil {
+		report.Missing = append(report.Missing, fmt.Sprintf("GPU nodes: %v", err))
+	} else {
+		gpus := map[string]int{}
+		for _, node := range nodes.Items {
+			gpus[node.Labels["nvidia.com/gpu.product"]]++
+		}
+		var products []string
+		for product, count := range gpus {
+			products = append(products, fmt.Sprintf("%d x %s", count, product))
+		}
+		sort.Strings(products)
+		report.Environment["GPU nodes"] = strings.Join(products, ", ")
+	}
+	for task, images := range TaskImages(tasks) {
+		report.Environment["Image of "+task] = strings.Join(images, ", ")
+	}
+	return report
+}
+
+// collectOperator adds the image of the Training Operator to the environment matrix and the log tail of its pods
+func (r *BugReport) collectOperator(client kubernetes.Interface, namespace string) {
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.Background(), TrainingOperatorDeployment, metav1.GetOptions{})
+	if err != nil {
+		r.Missing = append(r.Missing, fmt.Sprintf("Training Operator: %v", err))
+		return
+	}
+	if containers := deployment.Spec.Template.Spec.Containers; len(containers) > 0 {
+		r.Environment["Training Operator"] = containers[0].Image
+	}
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		r.Missing = append(r.Missing, fmt.Sprintf("Training Operator pods: %v", err))
+		return
+	}
+	pods, err := client.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		r.Missing = append(r.Missing, fmt.Sprintf("Training Operator pods: %v", err))
+		return
+	}
+	tail := int64(bugReportLogLines)
+	for _, pod := range pods.Items {
+		logs, err := client.CoreV1().Pods(namespace).GetLogs(pod.Name, &corev1.PodLogOptions{TailLines: &tail}).DoRaw(context.Background())
+		if err != nil {
+			r.Missing = append(r.Missing, fmt.Sprintf("log of Training Operator pod %s: %v", pod.Name, err))
+			continue
+		}
+		r.Logs["training-operator_"+pod.Name+".log"] = string(logs)
+	}
+}
+
+func (r *BugReport) addResource(kind, name string, object interface{}) {
+	data, err := yaml.Marshal(object)
+	if err != nil {
+		r.Missing = append(r.Missing, fmt.Sprintf("%s %s: %v", kind, name, err))
+		return
+	}
+	r.Resources[fmt.Sprintf("%s_%s.yaml", strings.ToLower(kind), name)] = data
+}
+
+// WriteBugReport writes the bundle into the directory: summary.md, and the logs and resource YAML under logs and
+// resources
+func WriteBugReport(dir string, report BugReport) error {
+	files := map[string][]byte{"summary.md": []byte(RenderBugReport(report))}
+	for name, content := range report.Logs {
+		files[filepath.Join("logs", name)] = []byte(content)
+	}
+	for name, content := range report.Resources {
+		files[filepath.Join("resources", name)] = content
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenderBugReport renders the summary of a bug report as Markdown, ready to paste into an issue
+func RenderBugReport(report BugReport) string {
+	c := report.Classification
+	var summary strings.Builder
+	fmt.Fprintf(&summary, "# %s in %s of pipeline run %s\n\n", c.Rule.Name, c.Failure.Pod.Name, report.RunID)
+	fmt.Fprintf(&summary, "Classified as a %s failure by rule `%s`: container `%s` of pod `%s` failed", c.Rule.Class, c.Rule.Name, c.Failure.Container, c.Failure.Pod.Name)
+	if c.Failure.ExitCode != 0 {
+		fmt.Fprintf(&summary, " with exit code %d", c.Failure.ExitCode)
+	}
+	fmt.Fprintf(&summary, ".\n\n```\n%s\n```\n", c.Evidence)
+
+	summary.WriteString("\n## Failed containers\n\n| Pod | Container | Reason | Exit code |\n|---|---|---|---|\n")
+	for _, failure := range report.Failures {
+		fmt.Fprintf(&summary, "| %s | %s | %s | %d |\n", failure.Pod.Name, failure.Container, strings.ReplaceAll(failure.Reason, "\n", " "), failure.ExitCode)
+	}
+
+	if excerpt := lastLines(c.Failure.Log, bugReportExcerptLines); excerpt != "" {
+		fmt.Fprintf(&summary, "\n## Log excerpt\n\nThe last %d lines of `%s`:\n\n```\n%s\n```\n", bugReportExcerptLines, c.Failure.Container, excerpt)
+	}
+
+	summary.WriteString("\n## Environment\n\n| | |\n|---|---|\n")
+	for _, name := range sortedKeys(report.Environment) {
+		fmt.Fprintf(&summary, "| %s | %s |\n", name, report.Environment[name])
+	}
+
+	if len(report.Params) > 0 {
+		params, err := yaml.Marshal(report.Params)
+		if err == nil {
+			fmt.Fprintf(&summary, "\n## Pipeline parameters\n\n```yaml\n%s```\n", params)
+		}
+	}
+
+	summary.WriteString("\n## Attachments\n\n")
+	for _, name := range sortedKeys(report.Logs) {
+		fmt.Fprintf(&summary, "- logs/%s\n", name)
+	}
+	var resources []string
+	for name := range report.Resources {
+		resources = append(resources, name)
+	}
+	sort.Strings(resources)
+	for _, name := range resources {
+		fmt.Fprintf(&summary, "- resources/%s\n", name)
+	}
+	if len(report.Missing) > 0 {
+		summary.WriteString("\nNot collected:\n\n")
+		for _, missing := range report.Missing {
+			fmt.Fprintf(&summary, "- %s\n", missing)
+		}
+	}
+	return summary.String()
+}
+
+// lastLines returns the last lines of a text, without the trailing newline
+func lastLines(text string, n int) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
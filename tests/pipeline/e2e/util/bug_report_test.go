@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestClassifyRunFailure(t *testing.T) {
+	rules := LoadFailureRules(t, "../resources/failure_classes.yaml")
+	terminated := func(name string, exitCode int32, reason string) corev1.ContainerStatus {
+		return corev1.ContainerStatus{Name: name, State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Reason: reason}}}
+	}
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "sdg"}, Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{terminated("main", 0, "Completed")}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "launcher"}, Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{terminated("kfp-launcher", 0, "Completed")},
+			ContainerStatuses:     []corev1.ContainerStatus{terminated("main", 1, "Error")},
+		}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "eval"}, Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "main", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"}}},
+		}}},
+	}
+	failures := FailedContainers(pods)
+	require.Len(t, failures, 2)
+	require.Equal(t, "Error", failures[0].Reason)
+	require.Equal(t, int32(1), failures[0].ExitCode)
+	require.Equal(t, "ImagePullBackOff Back-off pulling image", failures[1].Reason)
+
+	// The environment rules come first, the image pull matches before the traceback of the launcher
+	failures[0].Log = "Traceback (most recent call last):\n  File \"launcher.py\"\nRuntimeError: PyTorchJob train-phase-1 failed\n"
+	classification, ok := ClassifyRunFailure(rules, failures)
+	require.True(t, ok)
+	require.Equal(t, "image-pull", classification.Rule.Name)
+	require.Equal(t, FailureClassEnvironment, classification.Rule.Class)
+
+	classification, ok = ClassifyRunFailure(rules, failures[:1])
+	require.True(t, ok)
+	require.Equal(t, "training-job-failed", classification.Rule.Name)
+	require.Equal(t, FailureClassProduct, classification.Rule.Class)
+	require.Equal(t, "RuntimeError: PyTorchJob train-phase-1 failed", classification.Evidence)
+
+	failures[0].Log = "openai.APIConnectionError: Connection error.\nTraceback (most recent call last):\n"
+	classification, _ = ClassifyRunFailure(rules, failures[:1])
+	require.Equal(t, "model-server-unreachable", classification.Rule.Name)
+
+	failures[0].Log = "exit status 1"
+	_, ok = ClassifyRunFailure(rules, failures[:1])
+	require.False(t, ok)
+}
+
+func TestCollectBugReport(t *testing.T) {
+	launcher := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "launcher", Namespace: "ns", Labels: map[string]string{WorkflowLabel: "instructlab-abcde"}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "main", Image: "quay.io/ilab/training:1.4"}}},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "main", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}}},
+		}},
+	}
+	worker := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "train-phase-1-instructlab-abcde-worker-0", Namespace: "ns"}}
+	operator := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: TrainingOperatorDeployment, Namespace: "opendatahub"},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "training-operator"}},
+			Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "operator", Image: "quay.io/kubeflow/training-operator:1.8"}}}},
+		},
+	}
+	client := fake.NewSimpleClientset(&launcher, &worker, operator,
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "training-operator-0", Namespace: "opendatahub", Labels: map[string]string{"app": "training-operator"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "gpu-0", Labels: map[string]string{"nvidia.com/gpu.product": "NVIDIA-A100-SXM4-80GB"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "gpu-1", Labels: map[string]string{"nvidia.com/gpu.product": "NVIDIA-A100-SXM4-80GB"}}},
+	)
+	job := &unstructured.Unstructured{}
+	job.SetAPIVersion("kubeflow.org/v1")
+	job.SetKind("PyTorchJob")
+	job.SetNamespace("ns")
+	job.SetName("train-phase-1-instructlab-abcde")
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		PyTorchJobGVR: "PyTorchJobList",
+	}, job)
+
+	failures := FailedContainers([]corev1.Pod{launcher})
+	failures[0].Log = "Traceback (most recent call last):\nRuntimeError: PyTorchJob train-phase-1-instructlab-abcde failed\n"
+	classification, ok := ClassifyRunFailure(LoadFailureRules(t, "../resources/failure_classes.yaml"), failures)
+	require.True(t, ok)
+	tasks := []TaskPod{{Pod: launcher, Function: "pytorch_job_launcher_op"}}
+	report := CollectBugReport(client, dynamicClient, productSettings[ProductModeODH], "ns", "run-1", tasks, []corev1.Pod{worker},
+		classification, failures, map[string]interface{}{"train_num_epochs_phase_1": 2})
+
+	require.Equal(t, "quay.io/kubeflow/training-operator:1.8", report.Environment["Training Operator"])
+	require.Equal(t, "2 x NVIDIA-A100-SXM4-80GB", report.Environment["GPU nodes"])
+	require.Equal(t, "quay.io/ilab/training:1.4", report.Environment["Image of pytorch-job-launcher-op"])
+	require.Contains(t, report.Logs, "launcher_main.log")
+	require.Contains(t, report.Logs, "train-phase-1-instructlab-abcde-worker-0.log")
+	require.Contains(t, report.Logs, "training-operator_training-operator-0.log")
+	require.Contains(t, string(report.Resources["pytorchjob_train-phase-1-instructlab-abcde.yaml"]), "kind: PyTorchJob")
+	require.Contains(t, string(report.Resources["pod_launcher.yaml"]), "name: launcher")
+	// The second PyTorchJob was never created
+	require.Len(t, report.Missing, 1)
+	require.Contains(t, report.Missing[0], "PyTorchJob train-phase-2-instructlab-abcde")
+
+	dir := t.TempDir()
+	require.NoError(t, WriteBugReport(dir, report))
+	summary, err := os.ReadFile(filepath.Join(dir, "summary.md"))
+	require.NoError(t, err)
+	for _, section := range []string{
+		"# training-job-failed in launcher of pipeline run run-1\n",
+		"Classified as a product failure by rule `training-job-failed`: container `main` of pod `launcher` failed with exit code 1.",
+		"```\nRuntimeError: PyTorchJob train-phase-1-instructlab-abcde failed\n```",
+		"| launcher | main | Error | 1 |\n",
+		"| Product | odh |\n",
+		"```yaml\ntrain_num_epochs_phase_1: 2\n```",
+		"- logs/launcher_main.log\n",
+		"- resources/pytorchjob_train-phase-1-instructlab-abcde.yaml\n",
+		"Not collected:",
+	} {
+		require.Contains(t, string(summary), section)
+	}
+	_, err = os.Stat(filepath.Join(dir, "resources", "pod_launcher.yaml"))
+	require.NoError(t, err)
+	log, err := os.ReadFile(filepath.Join(dir, "logs", "launcher_main.log"))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(log), "Traceback"))
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// EventsCapability lists the events of the namespace, which give the reasons PVCs stay pending and images fail
+	// to pull
+	EventsCapability = "events"
+	// PodMetricsCapability reads the pod usage of the metrics server
+	PodMetricsCapability = "pod-metrics"
+)
+
+// Capability is an optional permission of the diagnostics and watchers, which continue with less information when
+// it is missing instead of requiring the full cluster role
+type Capability struct {
+	Name       string
+	Attributes authorizationv1.ResourceAttributes
+}
+
+// ObservabilityCapabilities lists the optional permissions of the diagnostics and watchers in a namespace
+func ObservabilityCapabilities(namespace string) []Capability {
+	return []Capability{
+		{EventsCapability, authorizationv1.ResourceAttributes{Namespace: namespace, Verb: "list", Resource: "events"}},
+		{PodMetricsCapability, authorizationv1.ResourceAttributes{Namespace: namespace, Verb: "list", Group: "metrics.k8s.io", Resource: "pods"}},
+	}
+}
+
+// CapabilityMatrix tells which capabilities the user of the suite holds
+type CapabilityMatrix map[string]bool
+
+// Has tells whether the capability is held, capabilities which were not probed are assumed to be
+func (m CapabilityMatrix) Has(name string) bool {
+	held, probed := m[name]
+	return held || !probed
+}
+
+// ProbeCapabilities reviews the capabilities as the user of the client
+func ProbeCapabilities(client kubernetes.Interface, capabilities []Capability) (CapabilityMatrix, error) {
+	matrix := CapabilityMatrix{}
+	for _, capability := range capabilities {
+		attributes := capability.Attributes
+		review, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(context.Background(), &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &attributes},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to review capability %s: %w", capability.Name, err)
+		}
+		matrix[capability.Name] = review.Status.Allowed
+	}
+	return matrix, nil
+}
+
+// RenderCapabilityMatrix renders the capabilities with the permission they stand for, whether they are held and the
+// features using them, given by capability
+func RenderCapabilityMatrix(capabilities []Capability, matrix CapabilityMatrix, users map[string][]string) string {
+	var report strings.Builder
+	report.WriteString("| Capability | Permission | Available | Used by |\n")
+	report.WriteString("|---|---|---|---|\n")
+	for _, capability := range capabilities {
+		attributes := capability.Attributes
+		resource := attributes.Resource
+		if attributes.Group != "" {
+			resource += "." + attributes.Group
+		}
+		available := "yes"
+		if !matrix.Has(capability.Name) {
+			available = "no"
+		}
+		usedBy := strings.Join(users[capability.Name], ", ")
+		if usedBy == "" {
+			usedBy = "-"
+		}
+		fmt.Fprintf(&report, "| %s | %s %s | %s | %s |\n", capability.Name, attributes.Verb, resource, available, usedBy)
+	}
+	return report.String()
+}
+
+// MissingCapabilityError is reported once by a watcher continuing without an optional capability
+type MissingCapabilityError struct {
+	Capability string
+	Err        error
+}
+
+func (e *MissingCapabilityError) Error() string {
+	return fmt.Sprintf("capability %s is not available, continuing without it: %v", e.Capability, e.Err)
+}
+
+func (e *MissingCapabilityError) Unwrap() error { return e.Err }
+
+// IsMissingCapability tells whether the error of an optional request means the permission or the API is missing,
+// e.g. events forbidden or the metrics API not served, rather than a transient failure
+func IsMissingCapability(err error) bool {
+	return apierrors.IsForbidden(err) || apierrors.IsNotFound(err)
+}
+
+// capabilityGate remembers an optional capability found missing, so a watcher requests it until it is denied and
+// then continues without it
+type capabilityGate struct {
+	capability string
+	err        error
+	noticed    bool
+}
+
+func (g *capabilityGate) available() bool { return g.err == nil }
+
+// deny records the error when it means the capability is missing and tells whether it did
+func (g *capabilityGate) deny(err error) bool {
+	if !IsMissingCapability(err) {
+		return false
+	}
+	g.err = err
+	return true
+}
+
+// notice returns the missing capability the first time only
+func (g *capabilityGate) notice() error {
+	if g.err == nil || g.noticed {
+		return nil
+	}
+	g.noticed = true
+	return &MissingCapabilityError{Capability: g.capability, Err: g.err}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestProbeCapabilities(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = review.Spec.ResourceAttributes.Resource == "events"
+		return true, review, nil
+	})
+
+	capabilities := ObservabilityCapabilities("ns")
+	matrix, err := ProbeCapabilities(client, capabilities)
+	require.NoError(t, err)
+	require.Equal(t, CapabilityMatrix{EventsCapability: true, PodMetricsCapability: false}, matrix)
+	require.True(t, matrix.Has("nodes"))
+
+	require.Equal(t, `| Capability | Permission | Available | Used by |
+|---|---|---|---|
+| events | list events | yes | pvc-watchdog, registry-retry |
+| pod-metrics | list pods.metrics.k8s.io | no | - |
+`, RenderCapabilityMatrix(capabilities, matrix, map[string][]string{EventsCapability: {"pvc-watchdog", "registry-retry"}}))
+}
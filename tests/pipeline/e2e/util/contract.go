@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PipelineParameterSpec is the definition of a pipeline input parameter in the compiled pipeline
+type PipelineParameterSpec struct {
+	ParameterType string      `yaml:"parameterType"`
+	IsOptional    bool        `yaml:"isOptional"`
+	DefaultValue  interface{} `yaml:"defaultValue"`
+}
+
+type compiledPipeline struct {
+	Root struct {
+		InputDefinitions struct {
+			Parameters map[string]PipelineParameterSpec `yaml:"parameters"`
+		} `yaml:"inputDefinitions"`
+	} `yaml:"root"`
+}
+
+// LoadPipelineInputDefinitions reads the input parameters of a compiled pipeline, e.g. pipeline.yaml
+func LoadPipelineInputDefinitions(path string) (map[string]PipelineParameterSpec, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// The pipeline spec is the first document, the platform spec may follow
+	var pipeline compiledPipeline
+	if err := yaml.NewDecoder(file).Decode(&pipeline); err != nil {
+		return nil, fmt.Errorf("failed to parse compiled pipeline %s: %w", path, err)
+	}
+	if len(pipeline.Root.InputDefinitions.Parameters) == 0 {
+		return nil, fmt.Errorf("no input parameters found in compiled pipeline %s", path)
+	}
+	return pipeline.Root.InputDefinitions.Parameters, nil
+}
+
+// CheckParameterContract compares the parameters the test passes to a run against the pipeline input definitions,
+// reporting unknown parameters, missing required parameters and values of the wrong type
+func CheckParameterContract(definitions map[string]PipelineParameterSpec, params map[string]interface{}) []string {
+	var problems []string
+
+	for name, value := range params {
+		definition, ok := definitions[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("parameter '%s' is not an input of the pipeline", name))
+			continue
+		}
+		if !parameterValueMatchesType(value, definition.ParameterType) {
+			problems = append(problems, fmt.Sprintf("parameter '%s' has value %v of type %T, the pipeline expects %s", name, value, value, definition.ParameterType))
+		}
+	}
+
+	for name, definition := range definitions {
+		if _, ok := params[name]; !ok && !definition.IsOptional && definition.DefaultValue == nil {
+			problems = append(problems, fmt.Sprintf("required pipeline parameter '%s' is not set", name))
+		}
+	}
+
+	sort.Strings(problems)
+	return problems
+}
+
+func parameterValueMatchesType(value interface{}, parameterType string) bool {
+	switch parameterType {
+	case "STRING":
+		_, ok := value.(string)
+		return ok
+	case "BOOLEAN":
+		_, ok := value.(bool)
+		return ok
+	case "NUMBER_INTEGER":
+		switch v := value.(type) {
+		case int, int64:
+			return true
+		case float64:
+			return v == float64(int64(v))
+		}
+		return false
+	case "NUMBER_DOUBLE":
+		switch value.(type) {
+		case int, int64, float64:
+			return true
+		}
+		return false
+	case "LIST":
+		_, ok := value.([]interface{})
+		return ok
+	case "STRUCT":
+		_, ok := value.(map[string]interface{})
+		return ok
+	}
+	return true
+}
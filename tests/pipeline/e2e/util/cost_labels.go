@@ -0,0 +1,154 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+)
+
+// CostLabels are the labels and annotations attributing the namespace and the pods of the runs, e.g. to a cost center
+// and a team, so chargeback tooling can account the GPU hours of ilab runs
+type CostLabels struct {
+	Labels      map[string]string `mapstructure:"labels"`
+	Annotations map[string]string `mapstructure:"annotations"`
+}
+
+// LoadCostLabels reads the cost labels from a YAML file, failing on labels Kubernetes would reject
+func LoadCostLabels(t *testing.T, path string) CostLabels {
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig(), "Error loading cost labels")
+
+	var costLabels CostLabels
+	require.NoError(t, v.UnmarshalExact(&costLabels), "Error parsing cost labels")
+	require.NoError(t, costLabels.Validate(), "Invalid cost labels in %s", path)
+	return costLabels
+}
+
+// Validate checks the keys of the labels and annotations and the values of the labels are valid
+func (c CostLabels) Validate() error {
+	var problems []string
+	for key, value := range c.Labels {
+		for _, problem := range append(validation.IsQualifiedName(key), validation.IsValidLabelValue(value)...) {
+			problems = append(problems, fmt.Sprintf("label %s=%s: %s", key, value, problem))
+		}
+	}
+	for key := range c.Annotations {
+		for _, problem := range validation.IsQualifiedName(key) {
+			problems = append(problems, fmt.Sprintf("annotation %s: %s", key, problem))
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// LabelPipelinePods sets the cost labels and annotations on the pod metadata of every executor of a compiled
+// pipeline, so every task pod of its runs carries them
+func LabelPipelinePods(pipelineYAML []byte, costLabels CostLabels) ([]byte, error) {
+	return rewritePlatformExecutors(pipelineYAML, func(executor map[string]interface{}) {
+		metadata := nestedMap(executor, "podMetadata")
+		labels := nestedMap(metadata, "labels")
+		for key, value := range costLabels.Labels {
+			labels[key] = value
+		}
+		annotations := nestedMap(metadata, "annotations")
+		for key, value := range costLabels.Annotations {
+			annotations[key] = value
+		}
+	})
+}
+
+// LabelNamespace sets the cost labels and annotations on the namespace, and returns a function restoring the previous
+// values
+func LabelNamespace(t *testing.T, client kubernetes.Interface, namespace string, costLabels CostLabels) func() {
+	current, err := client.CoreV1().Namespaces().Get(context.Background(), namespace, metav1.GetOptions{})
+	require.NoError(t, err, "Failed to retrieve namespace %s", namespace)
+
+	previousLabels, labels := map[string]interface{}{}, map[string]interface{}{}
+	for key, value := range costLabels.Labels {
+		previousLabels[key] = previousValue(current.Labels, key)
+		labels[key] = value
+	}
+	previousAnnotations, annotations := map[string]interface{}{}, map[string]interface{}{}
+	for key, value := range costLabels.Annotations {
+		previousAnnotations[key] = previousValue(current.Annotations, key)
+		annotations[key] = value
+	}
+
+	patch := func(labels, annotations map[string]interface{}) error {
+		data, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"labels": labels, "annotations": annotations}})
+		if err != nil {
+			return err
+		}
+		_, err = client.CoreV1().Namespaces().Patch(context.Background(), namespace, types.MergePatchType, data, metav1.PatchOptions{})
+		return err
+	}
+	require.NoError(t, patch(labels, annotations), "Failed to label namespace %s", namespace)
+	return func() {
+		if err := patch(previousLabels, previousAnnotations); err != nil {
+			t.Errorf("Failed to restore the labels of namespace %s: %v", namespace, err)
+		}
+	}
+}
+
+// previousValue returns the value of a key for a merge patch restoring it, nil removing a key that was not set
+func previousValue(values map[string]string, key string) interface{} {
+	if value, ok := values[key]; ok {
+		return value
+	}
+	return nil
+}
+
+// CheckCostLabels returns the pods missing a cost label or annotation, or carrying another value
+func CheckCostLabels(pods []corev1.Pod, costLabels CostLabels) []string {
+	var problems []string
+	for _, pod := range pods {
+		for _, key := range sortedKeys(costLabels.Labels) {
+			if value, ok := pod.Labels[key]; !ok || value != costLabels.Labels[key] {
+				problems = append(problems, fmt.Sprintf("pod %s has label %s=%q, expected %q", pod.Name, key, value, costLabels.Labels[key]))
+			}
+		}
+		for _, key := range sortedKeys(costLabels.Annotations) {
+			if value, ok := pod.Annotations[key]; !ok || value != costLabels.Annotations[key] {
+				problems = append(problems, fmt.Sprintf("pod %s has annotation %s=%q, expected %q", pod.Name, key, value, costLabels.Annotations[key]))
+			}
+		}
+	}
+	return problems
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var testCostLabels = CostLabels{
+	Labels:      map[string]string{"cost-center": "cc-42", "team": "ds"},
+	Annotations: map[string]string{"opendatahub.io/cost-attribution": "ilab runs"},
+}
+
+func TestCostLabelsValidate(t *testing.T) {
+	require.NoError(t, testCostLabels.Validate())
+	invalid := CostLabels{Labels: map[string]string{"team": "data science"}, Annotations: map[string]string{"bad key": ""}}
+	err := invalid.Validate()
+	require.ErrorContains(t, err, "label team=data science")
+	require.ErrorContains(t, err, "annotation bad key")
+}
+
+func TestLabelPipelinePods(t *testing.T) {
+	pipelineYAML := []byte("deploymentSpec:\n  executors:\n    exec-sdg-op: {}\n---\nplatforms:\n  kubernetes:\n    deploymentSpec:\n      executors:\n        exec-sdg-op:\n          podMetadata:\n            labels:\n              app: sdg\n")
+	labeled, err := LabelPipelinePods(pipelineYAML, testCostLabels)
+	require.NoError(t, err)
+
+	decoder := yaml.NewDecoder(bytes.NewReader(labeled))
+	var pipeline, platform map[string]interface{}
+	require.NoError(t, decoder.Decode(&pipeline))
+	require.NoError(t, decoder.Decode(&platform))
+	executor := nestedMap(platform, "platforms", "kubernetes", "deploymentSpec", "executors", "exec-sdg-op")
+	require.Equal(t, map[string]interface{}{"app": "sdg", "cost-center": "cc-42", "team": "ds"}, nestedMap(executor, "podMetadata", "labels"))
+	require.Equal(t, map[string]interface{}{"opendatahub.io/cost-attribution": "ilab runs"}, nestedMap(executor, "podMetadata", "annotations"))
+}
+
+func TestLabelNamespace(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "ilab",
+		Labels: map[string]string{"team": "previous"},
+	}})
+	restore := LabelNamespace(t, client, "ilab", testCostLabels)
+	namespace, err := client.CoreV1().Namespaces().Get(context.Background(), "ilab", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, testCostLabels.Labels, namespace.Labels)
+	require.Equal(t, testCostLabels.Annotations, namespace.Annotations)
+
+	restore()
+	namespace, err = client.CoreV1().Namespaces().Get(context.Background(), "ilab", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"team": "previous"}, namespace.Labels)
+	require.Empty(t, namespace.Annotations)
+}
+
+func TestCheckCostLabels(t *testing.T) {
+	labeled := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "sdg", Labels: testCostLabels.Labels, Annotations: testCostLabels.Annotations}}
+	unlabeled := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "train", Labels: map[string]string{"team": "other"}}}
+	require.Equal(t, []string{
+		`pod train has label cost-center="", expected "cc-42"`,
+		`pod train has label team="other", expected "ds"`,
+		`pod train has annotation opendatahub.io/cost-attribution="", expected "ilab runs"`,
+	}, CheckCostLabels([]corev1.Pod{labeled, unlabeled}, testCostLabels))
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// cudaPreflightScript prints the driver and the CUDA version it supports, then checks torch can use the GPU
+const cudaPreflightScript = `nvidia-smi
+python3 - <<'EOF'
+import sys
+import torch
+print(f"torch={torch.__version__} torch_cuda={torch.version.cuda} cuda_available={torch.cuda.is_available()}")
+if not torch.cuda.is_available():
+    sys.exit(1)
+x = torch.ones(1, device="cuda")
+print(f"device={torch.cuda.get_device_name(0)} result={(x + x).item()}")
+EOF`
+
+var (
+	driverVersionPattern = regexp.MustCompile(`Driver Version:\s*(\S+)`)
+	driverCUDAPattern    = regexp.MustCompile(`CUDA Version:\s*(\S+)`)
+	torchPattern         = regexp.MustCompile(`torch=(\S+) torch_cuda=(\S+) cuda_available=(\S+)`)
+	devicePattern        = regexp.MustCompile(`device=(.+) result=`)
+)
+
+// CUDAReport is what the CUDA preflight found inside the training image
+type CUDAReport struct {
+	DriverVersion string
+	// DriverCUDA is the latest CUDA version supported by the driver
+	DriverCUDA    string
+	TorchVersion  string
+	TorchCUDA     string
+	CUDAAvailable bool
+	Device        string
+}
+
+// ParseCUDAPreflight reads the output of the CUDA preflight
+func ParseCUDAPreflight(logs string) CUDAReport {
+	var report CUDAReport
+	if match := driverVersionPattern.FindStringSubmatch(logs); match != nil {
+		report.DriverVersion = match[1]
+	}
+	if match := driverCUDAPattern.FindStringSubmatch(logs); match != nil {
+		report.DriverCUDA = match[1]
+	}
+	if match := torchPattern.FindStringSubmatch(logs); match != nil {
+		report.TorchVersion, report.TorchCUDA, report.CUDAAvailable = match[1], match[2], match[3] == "True"
+	}
+	if match := devicePattern.FindStringSubmatch(logs); match != nil {
+		report.Device = strings.TrimSpace(match[1])
+	}
+	return report
+}
+
+// CheckCUDACompatibility returns the driver/CUDA mismatch details of a report, or nil when torch can use the GPU
+func CheckCUDACompatibility(report CUDAReport) error {
+	switch {
+	case report.DriverVersion == "":
+		return fmt.Errorf("nvidia-smi found no NVIDIA driver")
+	case report.TorchVersion == "":
+		return fmt.Errorf("torch could not be imported in the training image (driver %s, CUDA %s)", report.DriverVersion, report.DriverCUDA)
+	case report.TorchCUDA == "None":
+		return fmt.Errorf("torch %s is not built with CUDA (driver %s)", report.TorchVersion, report.DriverVersion)
+	case compareVersions(report.TorchCUDA, report.DriverCUDA) > 0:
+		return fmt.Errorf("torch %s is built for CUDA %s, driver %s only supports CUDA %s", report.TorchVersion, report.TorchCUDA, report.DriverVersion, report.DriverCUDA)
+	case !report.CUDAAvailable:
+		return fmt.Errorf("torch %s (CUDA %s) cannot use the GPU with driver %s (CUDA %s)", report.TorchVersion, report.TorchCUDA, report.DriverVersion, report.DriverCUDA)
+	case report.Device == "":
+		return fmt.Errorf("torch %s failed to run on the GPU with driver %s", report.TorchVersion, report.DriverVersion)
+	}
+	return nil
+}
+
+// TrainingPlacement returns the GPU resource and the node selectors training uses with the pipeline parameters, from
+// train_gpu_identifier and train_node_selectors, so the preflight runs where the training pods will
+func TrainingPlacement(params map[string]interface{}) (string, map[string]string) {
+	gpuResource := DefaultGPUResource
+	if identifier, ok := params["train_gpu_identifier"].(string); ok && identifier != "" {
+		gpuResource = identifier
+	}
+	var nodeSelector map[string]string
+	if selectors, ok := params["train_node_selectors"].(map[string]interface{}); ok && len(selectors) > 0 {
+		nodeSelector = map[string]string{}
+		for key, value := range selectors {
+			nodeSelector[key] = fmt.Sprint(value)
+		}
+	}
+	return gpuResource, nodeSelector
+}
+
+// RunCUDAPreflight runs the CUDA preflight in the training image on a GPU node and returns its report. The error
+// holds the driver/CUDA mismatch details and the output of the preflight.
+func RunCUDAPreflight(t *testing.T, client kubernetes.Interface, namespace, image, gpuResource string, nodeSelector map[string]string, timeout time.Duration) (CUDAReport, error) {
+	gpus := resource.MustParse("1")
+	resources := corev1.ResourceList{
+		corev1.ResourceCPU:               resource.MustParse("1"),
+		corev1.ResourceMemory:            resource.MustParse("4Gi"),
+		corev1.ResourceName(gpuResource): gpus,
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: GenerateName("cuda-preflight")},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			NodeSelector:  nodeSelector,
+			Tolerations:   []corev1.Toleration{{Key: gpuResource, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+			Containers: []corev1.Container{{
+				Name:      "preflight",
+				Image:     image,
+				Command:   []string{"sh", "-c", cudaPreflightScript},
+				Resources: corev1.ResourceRequirements{Requests: resources, Limits: resources},
+			}},
+		},
+	}
+
+	pods := client.CoreV1().Pods(namespace)
+	created, err := pods.Create(context.Background(), pod, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create CUDA preflight pod")
+	defer func() { _ = pods.Delete(context.Background(), created.Name, metav1.DeleteOptions{}) }()
+
+	deadline := time.After(timeout)
+	tick := time.Tick(10 * time.Second)
+	for {
+		select {
+		case <-deadline:
+			return CUDAReport{}, fmt.Errorf("CUDA preflight pod %s did not complete within %s", created.Name, timeout)
+		case <-tick:
+			current, err := pods.Get(context.Background(), created.Name, metav1.GetOptions{})
+			require.NoError(t, err, "Failed to retrieve CUDA preflight pod")
+			if current.Status.Phase != corev1.PodSucceeded && current.Status.Phase != corev1.PodFailed {
+				continue
+			}
+
+			logs, err := pods.GetLogs(created.Name, &corev1.PodLogOptions{}).DoRaw(context.Background())
+			require.NoError(t, err, "Failed to retrieve CUDA preflight logs")
+			report := ParseCUDAPreflight(string(logs))
+			if err := CheckCUDACompatibility(report); err != nil {
+				return report, fmt.Errorf("%w on node %s, preflight output:\n%s", err, current.Spec.NodeName, logs)
+			}
+			return report, nil
+		}
+	}
+}
+
+// compareVersions compares dotted numeric versions, e.g. 12.4 and 12.10
+func compareVersions(a, b string) int {
+	partsA, partsB := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(partsA) || i < len(partsB); i++ {
+		var x, y int
+		if i < len(partsA) {
+			x, _ = strconv.Atoi(partsA[i])
+		}
+		if i < len(partsB) {
+			y, _ = strconv.Atoi(partsB[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const nvidiaSMIHeader = `+-----------------------------------------------------------------------------------------+
+| NVIDIA-SMI 550.54.15              Driver Version: 550.54.15      CUDA Version: 12.4     |
+|-----------------------------------------+------------------------+----------------------+
+`
+
+func TestCUDAPreflight(t *testing.T) {
+	report := ParseCUDAPreflight(nvidiaSMIHeader + "torch=2.4.1+cu121 torch_cuda=12.1 cuda_available=True\ndevice=NVIDIA A100-SXM4-80GB result=2.0\n")
+	require.Equal(t, CUDAReport{
+		DriverVersion: "550.54.15",
+		DriverCUDA:    "12.4",
+		TorchVersion:  "2.4.1+cu121",
+		TorchCUDA:     "12.1",
+		CUDAAvailable: true,
+		Device:        "NVIDIA A100-SXM4-80GB",
+	}, report)
+	require.NoError(t, CheckCUDACompatibility(report))
+
+	report = ParseCUDAPreflight(nvidiaSMIHeader + "torch=2.6.0+cu126 torch_cuda=12.6 cuda_available=False\n")
+	err := CheckCUDACompatibility(report)
+	require.ErrorContains(t, err, "built for CUDA 12.6, driver 550.54.15 only supports CUDA 12.4")
+
+	require.ErrorContains(t, CheckCUDACompatibility(ParseCUDAPreflight("sh: nvidia-smi: command not found")), "no NVIDIA driver")
+	require.Equal(t, 1, compareVersions("12.10", "12.4"))
+}
+
+func TestTrainingPlacement(t *testing.T) {
+	gpuResource, nodeSelector := TrainingPlacement(map[string]interface{}{})
+	require.Equal(t, DefaultGPUResource, gpuResource)
+	require.Nil(t, nodeSelector)
+
+	gpuResource, nodeSelector = TrainingPlacement(map[string]interface{}{
+		"train_gpu_identifier": "amd.com/gpu",
+		"train_node_selectors": map[string]interface{}{"gpu-type": "mi300", "kubernetes.io/arch": "amd64"},
+	})
+	require.Equal(t, "amd.com/gpu", gpuResource)
+	require.Equal(t, map[string]string{"gpu-type": "mi300", "kubernetes.io/arch": "amd64"}, nodeSelector)
+}
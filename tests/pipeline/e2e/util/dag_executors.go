@@ -0,0 +1,266 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/dag"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/watcher"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/clock"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PipelineGraph returns the phases of the compiled pipeline as a DAG of the artifacts they pass each other, in the
+// order of PipelinePhases. The prerequisites produce no artifact, the checked marker orders them first.
+func PipelineGraph() *dag.Graph {
+	graph, err := dag.New([]dag.Phase{
+		{Name: "prerequisites", Inputs: []string{"teacher", "judge"}, Outputs: []string{"checked"}},
+		{Name: "sdg", Inputs: []string{"checked", "taxonomy", "teacher"}, Outputs: []string{"sdg"}},
+		{Name: "data-processing", Inputs: []string{"sdg", "tokenizer"}, Outputs: []string{"processed-data"}},
+		{Name: "model-to-pvc", Inputs: []string{"checked", "base-model"}, Outputs: []string{"model-pvc"}},
+		{Name: "training-phase-1", Inputs: []string{"processed-data", "model-pvc"}, Outputs: []string{"phase-1-checkpoints"}},
+		{Name: "training-phase-2", Inputs: []string{"processed-data", "phase-1-checkpoints"}, Outputs: []string{"phase-2-checkpoints"}},
+		{Name: "mt-bench", Inputs: []string{"phase-2-checkpoints", "judge"}, Outputs: []string{"mt-bench-report", "best-checkpoint"}},
+		{Name: "final-eval", Inputs: []string{"best-checkpoint", "model-pvc", "judge"}, Outputs: []string{"final-eval-report"}},
+		{Name: "metrics-report", Inputs: []string{"mt-bench-report", "final-eval-report"}, Outputs: []string{"metrics"}},
+		{Name: "upload-model", Inputs: []string{"best-checkpoint", "metrics"}, Outputs: []string{"registered-model"}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	return graph
+}
+
+// WorkflowExecutor runs the tasks of a subgraph of the workflow as one custom resource of a workflow engine, a
+// Tekton PipelineRun or an Argo Workflow. It polls the run at every interval, reports the state of its tasks and
+// annotates the run and its pods with its phase.
+type WorkflowExecutor struct {
+	Backend       WorkflowBackend
+	Client        kubernetes.Interface
+	DynamicClient dynamic.Interface
+	Namespace     string
+	Workflow      Workflow
+	Config        WorkflowRunConfig
+	Interval      time.Duration
+	// Logf logs the failures to annotate the run, which are informational only
+	Logf  func(format string, args ...interface{})
+	clock clock.Clock
+}
+
+// Execute creates the run of the tasks of the graph and waits for its completion. The outputs are on the data PVC
+// of the run; a run with a PVC created from a claim template returns the run as their location, and a later run
+// cannot resume from them.
+func (e *WorkflowExecutor) Execute(ctx context.Context, graph *dag.Graph, inputs dag.Artifacts, report func(dag.Status)) (dag.Artifacts, error) {
+	config, err := executorConfig(e.Config, graph, inputs)
+	if err != nil {
+		return nil, err
+	}
+	workflow := e.Workflow.Subset(graph)
+	run, err := e.Backend.NewRun(workflow, config)
+	if err != nil {
+		return nil, err
+	}
+	kind := e.Backend.Kind().Kind
+	runs := e.DynamicClient.Resource(e.Backend.Resource()).Namespace(e.Namespace)
+	if _, err := runs.Create(ctx, run, metav1.CreateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to create %s %s: %w", kind, config.Name, err)
+	}
+
+	clk := e.clock
+	if clk == nil {
+		clk = clock.RealClock{}
+	}
+	tick := clk.Tick(e.Interval)
+	reported := map[string]dag.State{}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-tick:
+		}
+
+		current, err := runs.Get(ctx, config.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s %s: %w", kind, config.Name, err)
+		}
+		states, err := e.Backend.TaskStates(e.DynamicClient, e.Namespace, config.Name, workflow)
+		if err != nil {
+			return nil, err
+		}
+		for _, state := range states {
+			if reported[state.Task] != state.State {
+				reported[state.Task] = state.State
+				report(dag.Status{Phase: state.Task, State: state.State})
+			}
+		}
+		if err := annotateWorkflowRun(e.Client, e.DynamicClient, e.Backend, e.Namespace, config.Name, WorkflowRunPhase(states)); err != nil && e.Logf != nil {
+			e.Logf("Failed to annotate run phase: %v", err)
+		}
+
+		done, err := e.Backend.Succeeded(current)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			break
+		}
+	}
+
+	location := config.DataPVC
+	if location == "" {
+		location = kind + "/" + config.Name
+	}
+	outputs := dag.Artifacts{}
+	for _, task := range workflow.Tasks {
+		for _, output := range task.Outputs {
+			outputs[output] = location
+		}
+	}
+	return outputs, nil
+}
+
+// PodExecutor runs the tasks of a subgraph of the workflow as standalone pods, one after the other, without a
+// workflow engine. The pods share a data PVC named after the run, created unless the run resumes from the PVC of an
+// earlier run, and are labeled with the run name as run ID and annotated with the phase of their task. The pods and
+// the PVC are left for the caller to delete.
+type PodExecutor struct {
+	Client    ctrlclient.WithWatch
+	Namespace string
+	Workflow  Workflow
+	Config    WorkflowRunConfig
+}
+
+// Execute runs the pods of the tasks in execution order, stopping at the first failure. The outputs are on the
+// data PVC, which later runs can resume from.
+func (e *PodExecutor) Execute(ctx context.Context, graph *dag.Graph, inputs dag.Artifacts, report func(dag.Status)) (dag.Artifacts, error) {
+	config, err := executorConfig(e.Config, graph, inputs)
+	if err != nil {
+		return nil, err
+	}
+	workflow := e.Workflow.Subset(graph)
+	if _, err := workflowParams(workflow, config); err != nil {
+		return nil, err
+	}
+
+	if config.DataPVC == "" {
+		config.DataPVC = config.Name + "-data"
+		storage, err := resource.ParseQuantity(workflow.WorkspaceSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid workspace size: %w", err)
+		}
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: config.DataPVC, Namespace: e.Namespace, Labels: config.RunLabels()},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources:   corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: storage}},
+			},
+		}
+		if config.StorageClass != "" {
+			pvc.Spec.StorageClassName = &config.StorageClass
+		}
+		if err := e.Client.Create(ctx, pvc); err != nil {
+			return nil, fmt.Errorf("failed to create PVC %s: %w", pvc.Name, err)
+		}
+	}
+
+	outputs := dag.Artifacts{}
+	for i, task := range workflow.Tasks {
+		pod, err := workflowTaskPod(task, config)
+		if err != nil {
+			return outputs, err
+		}
+		pod.Namespace = e.Namespace
+		pod.Annotations = map[string]string{
+			PhaseAnnotation:    task.Phase,
+			ProgressAnnotation: fmt.Sprint(i * 100 / len(workflow.Tasks)),
+		}
+		if err := e.Client.Create(ctx, pod); err != nil {
+			return outputs, fmt.Errorf("failed to create pod %s: %w", pod.Name, err)
+		}
+		report(dag.Status{Phase: task.Name, State: dag.Running})
+		if err := watcher.Until(ctx, e.Client, ctrlclient.ObjectKeyFromObject(pod), &corev1.Pod{}, watcher.PodSucceeded); err != nil {
+			report(dag.Status{Phase: task.Name, State: dag.Failed})
+			return outputs, fmt.Errorf("task %s: %w", task.Name, err)
+		}
+		report(dag.Status{Phase: task.Name, State: dag.Succeeded})
+		for _, output := range task.Outputs {
+			outputs[output] = config.DataPVC
+		}
+	}
+	return outputs, nil
+}
+
+// workflowTaskPod returns the pod running a task with the parameter values of the run
+func workflowTaskPod(task WorkflowTask, config WorkflowRunConfig) (*corev1.Pod, error) {
+	fields := workflowTaskContainer(task, config, func(param string) string { return fmt.Sprint(config.Params[param]) }, "/data", "/model")
+	fields["name"] = task.Name
+	fields["command"] = []interface{}{"sh", "-c", task.Script}
+	fields["volumeMounts"] = []interface{}{
+		map[string]interface{}{"name": "data", "mountPath": "/data"},
+		map[string]interface{}{"name": "model", "mountPath": "/model", "readOnly": true},
+	}
+	var container corev1.Container
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(fields, &container); err != nil {
+		return nil, fmt.Errorf("task %s: %w", task.Name, err)
+	}
+
+	labels := config.RunLabels()
+	labels[WorkflowTaskLabel] = task.Name
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   config.Name + "-" + task.Name,
+			Labels: labels,
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers:    []corev1.Container{container},
+			Volumes: []corev1.Volume{
+				{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: config.DataPVC}}},
+				{Name: "model", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: config.ModelPVC, ReadOnly: true}}},
+			},
+		},
+	}
+	config.DNS.ApplyTo(&pod.Spec)
+	return pod, nil
+}
+
+// executorConfig sets the PVCs of a run from the inputs of the graph: the base model PVC, and the data PVC holding
+// the outputs of the earlier run the graph resumes from
+func executorConfig(config WorkflowRunConfig, graph *dag.Graph, inputs dag.Artifacts) (WorkflowRunConfig, error) {
+	for _, input := range graph.Inputs() {
+		location := inputs[input]
+		if input == WorkflowModelInput {
+			config.ModelPVC = location
+			continue
+		}
+		if strings.Contains(location, "/") {
+			return config, fmt.Errorf("input %s is in %s, runs can only resume from a data PVC", input, location)
+		}
+		if config.DataPVC != "" && config.DataPVC != location {
+			return config, fmt.Errorf("input %s is on PVC %s, the other inputs on PVC %s", input, location, config.DataPVC)
+		}
+		config.DataPVC = location
+	}
+	return config, nil
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/dag"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	testingclock "k8s.io/utils/clock/testing"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPipelineGraph(t *testing.T) {
+	graph := PipelineGraph()
+	require.Equal(t, PipelinePhases, graph.Names())
+	require.Equal(t, []string{"base-model", "judge", "taxonomy", "teacher", "tokenizer"}, graph.Inputs())
+
+	resume, err := graph.Range("mt-bench", "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"mt-bench", "final-eval", "metrics-report", "upload-model"}, resume.Names())
+}
+
+func TestWorkflowExecutor(t *testing.T) {
+	workflow, err := LoadWorkflow("../resources/workflow_tasks.yaml")
+	require.NoError(t, err)
+	graph, err := workflow.Graph()
+	require.NoError(t, err)
+	resume, err := graph.Range("train", "")
+	require.NoError(t, err)
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		TektonPipelineRunGVR: "PipelineRunList",
+		TektonTaskRunGVR:     "TaskRunList",
+	}, testTaskRun("ilab-x1", "train", "Unknown"))
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "ilab-x1-train-pod", Namespace: "ilab", Labels: map[string]string{TektonPipelineRunLabel: "ilab-x1"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	clock := testingclock.NewFakeClock(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	executor := &WorkflowExecutor{
+		Backend:       TektonBackend{},
+		Client:        fake.NewSimpleClientset(pod),
+		DynamicClient: dynamicClient,
+		Namespace:     "ilab",
+		Workflow:      workflow,
+		Config:        testWorkflowRunConfig(),
+		Interval:      time.Minute,
+		clock:         clock,
+	}
+
+	_, err = dag.Run(context.Background(), executor, resume, dag.Artifacts{WorkflowModelInput: "granite", "sdg-data": "PipelineRun/ilab-x0"}, func(dag.Status) {})
+	require.EqualError(t, err, "input sdg-data is in PipelineRun/ilab-x0, runs can only resume from a data PVC")
+
+	statuses := make(chan dag.Status, 10)
+	type result struct {
+		outputs dag.Artifacts
+		err     error
+	}
+	results := make(chan result)
+	go func() {
+		outputs, err := dag.Run(context.Background(), executor, resume, dag.Artifacts{WorkflowModelInput: "granite", "sdg-data": "ilab-x0-data"}, func(status dag.Status) {
+			statuses <- status
+		})
+		results <- result{outputs, err}
+	}()
+	for !clock.HasWaiters() {
+		time.Sleep(time.Millisecond)
+	}
+	clock.Step(time.Minute)
+	require.Equal(t, dag.Status{Phase: "train", State: dag.Running}, <-statuses)
+
+	runs := dynamicClient.Resource(TektonPipelineRunGVR).Namespace("ilab")
+	run, err := runs.Get(context.Background(), "ilab-x1", metav1.GetOptions{})
+	require.NoError(t, err)
+	tasks, _, _ := unstructured.NestedSlice(run.Object, "spec", "pipelineSpec", "tasks")
+	require.Len(t, tasks, 2)
+	require.NotContains(t, tasks[0], "runAfter")
+	workspaces, _, _ := unstructured.NestedSlice(run.Object, "spec", "workspaces")
+	claim, _, _ := unstructured.NestedString(workspaces[0].(map[string]interface{}), "persistentVolumeClaim", "claimName")
+	require.Equal(t, "ilab-x0-data", claim)
+
+	require.NoError(t, unstructured.SetNestedSlice(run.Object, []interface{}{
+		map[string]interface{}{"type": "Succeeded", "status": "True"},
+	}, "status", "conditions"))
+	_, err = runs.Update(context.Background(), run, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	_, err = dynamicClient.Resource(TektonTaskRunGVR).Namespace("ilab").Update(context.Background(), testTaskRun("ilab-x1", "train", "True"), metav1.UpdateOptions{})
+	require.NoError(t, err)
+	clock.Step(time.Minute)
+	require.Equal(t, dag.Status{Phase: "train", State: dag.Succeeded}, <-statuses)
+	done := <-results
+	require.NoError(t, done.err)
+	require.Equal(t, dag.Artifacts{"checkpoints": "ilab-x0-data", "mt-bench-report": "ilab-x0-data"}, done.outputs)
+
+	annotated, err := runs.Get(context.Background(), "ilab-x1", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "training-phase-1", annotated.GetAnnotations()[PhaseAnnotation])
+	annotatedPod, err := executor.Client.CoreV1().Pods("ilab").Get(context.Background(), "ilab-x1-train-pod", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "40", annotatedPod.Annotations[ProgressAnnotation])
+}
+
+func TestPodExecutor(t *testing.T) {
+	workflow, err := LoadWorkflow("../resources/workflow_tasks.yaml")
+	require.NoError(t, err)
+	graph, err := workflow.Graph()
+	require.NoError(t, err)
+
+	client := ctrlfake.NewClientBuilder().Build()
+	// The pods of the tasks complete as soon as they run, the evaluation fails
+	complete := func(status dag.Status) {
+		if status.State != dag.Running {
+			return
+		}
+		pod := &corev1.Pod{}
+		require.NoError(t, client.Get(context.Background(), ctrlclient.ObjectKey{Namespace: "ilab", Name: "ilab-x1-" + status.Phase}, pod))
+		pod.Status.Phase = corev1.PodSucceeded
+		if status.Phase == "eval" {
+			pod.Status.Phase = corev1.PodFailed
+		}
+		require.NoError(t, client.Status().Update(context.Background(), pod))
+	}
+	var statuses []dag.Status
+	executor := &PodExecutor{Client: client, Namespace: "ilab", Workflow: workflow, Config: testWorkflowRunConfig()}
+	outputs, err := dag.Run(context.Background(), executor, graph, dag.Artifacts{WorkflowModelInput: "granite"}, func(status dag.Status) {
+		statuses = append(statuses, status)
+		complete(status)
+	})
+	require.ErrorContains(t, err, "task eval: ")
+	require.Equal(t, dag.Artifacts{"sdg-data": "ilab-x1-data", "checkpoints": "ilab-x1-data"}, outputs)
+	require.Equal(t, []dag.Status{
+		{Phase: "sdg", State: dag.Running}, {Phase: "sdg", State: dag.Succeeded},
+		{Phase: "train", State: dag.Running}, {Phase: "train", State: dag.Succeeded},
+		{Phase: "eval", State: dag.Running}, {Phase: "eval", State: dag.Failed},
+	}, statuses)
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	require.NoError(t, client.Get(context.Background(), ctrlclient.ObjectKey{Namespace: "ilab", Name: "ilab-x1-data"}, pvc))
+	require.Equal(t, "ilab-x1", pvc.Labels[RunIDLabel])
+	require.Equal(t, "nfs-csi", *pvc.Spec.StorageClassName)
+
+	train := &corev1.Pod{}
+	require.NoError(t, client.Get(context.Background(), ctrlclient.ObjectKey{Namespace: "ilab", Name: "ilab-x1-train"}, train))
+	require.Equal(t, "training-phase-1", train.Annotations[PhaseAnnotation])
+	require.Equal(t, "train", train.Labels[WorkflowTaskLabel])
+	container := train.Spec.Containers[0]
+	require.Equal(t, "training:1", container.Image)
+	require.Equal(t, "sh", container.Command[0])
+	require.Contains(t, container.Env, corev1.EnvVar{Name: "TRAIN_SEED", Value: "42"})
+	gpus := container.Resources.Limits["nvidia.com/gpu"]
+	require.Equal(t, "2", gpus.String())
+	require.Equal(t, "granite", train.Spec.Volumes[1].PersistentVolumeClaim.ClaimName)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/demotaxonomy"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+const taxonomyServerPort = 8080
+
+// DemoTarget is the duration the demo scenario completes within on a single GPU
+const DemoTarget = 2 * time.Hour
+
+// DeployTaxonomyServer deploys taxonomy-server, built from tests/Containerfile into image, and waits for it to become
+// ready. It returns the URL of the demo taxonomy repository to use as sdg_repo_url.
+func DeployTaxonomyServer(t *testing.T, client kubernetes.Interface, namespace, name, image string, timeout time.Duration) string {
+	labels := map[string]string{"app": name}
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Spec: corev1.ServiceSpec{
+			Selector: labels,
+			Ports:    []corev1.ServicePort{{Name: "http", Port: taxonomyServerPort, TargetPort: intstr.FromInt(taxonomyServerPort)}},
+		},
+	}
+	_, err := client.CoreV1().Services(namespace).Create(context.Background(), service, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create taxonomy server service")
+
+	resources := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("100m"),
+		corev1.ResourceMemory: resource.MustParse("64Mi"),
+	}
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:      "taxonomy-server",
+						Image:     image,
+						Command:   []string{"taxonomy-server"},
+						Args:      []string{fmt.Sprintf("--listen=:%d", taxonomyServerPort)},
+						Ports:     []corev1.ContainerPort{{ContainerPort: taxonomyServerPort}},
+						Resources: corev1.ResourceRequirements{Requests: resources, Limits: resources},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/taxonomy.git/HEAD", Port: intstr.FromInt(taxonomyServerPort)}},
+						},
+					}},
+				},
+			},
+		},
+	}
+	_, err = client.AppsV1().Deployments(namespace).Create(context.Background(), deployment, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create taxonomy server deployment")
+
+	require.NoError(t, WaitForDeploymentReady(t, client, namespace, name, timeout), "Taxonomy server did not become ready")
+	return fmt.Sprintf("http://%s.%s.svc:%d/taxonomy.git", name, namespace, taxonomyServerPort)
+}
+
+// DeleteTaxonomyServer removes everything DeployTaxonomyServer created
+func DeleteTaxonomyServer(t *testing.T, client kubernetes.Interface, namespace, name string) {
+	ctx := context.Background()
+	_ = client.AppsV1().Deployments(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	_ = client.CoreV1().Services(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+// DemoSummary is the outcome of a run of the demo scenario
+type DemoSummary struct {
+	RunID    string
+	RepoURL  string
+	Commit   string
+	Leaves   []demotaxonomy.Leaf
+	Duration time.Duration
+	// Phases are the durations of the phases, by phase
+	Phases map[string]time.Duration
+	// Scores are the eval scores of scenario_scores.yaml, empty without an artifact store
+	Scores map[string]float64
+}
+
+// demoPhaseSteps explain what every phase of the run did, for the audience of the demo
+var demoPhaseSteps = map[string]string{
+	"prerequisites":    "checked the teacher and judge endpoints, the model registry and the training operator",
+	"sdg":              "the teacher model expanded the seed examples into synthetic training data",
+	"data-processing":  "the synthetic data was tokenized into the training mixes",
+	"model-to-pvc":     "the base model was copied to the shared volume",
+	"training-phase-1": "the base model was trained on the knowledge mix",
+	"training-phase-2": "the phase 1 model was trained on the skills mix, the skills of the taxonomy",
+	"mt-bench":         "the judge model scored the checkpoints on MT-Bench, the best one was kept",
+	"final-eval":       "the best checkpoint was compared to the base model on the taxonomy",
+	"metrics-report":   "the evaluation scores were gathered into the metrics of the run",
+	"upload-model":     "the trained model was uploaded to the output storage",
+}
+
+// RenderDemoSummary renders the run of the demo scenario as a guided walk through what the pipeline did with the demo
+// taxonomy, phase by phase
+func RenderDemoSummary(summary DemoSummary) string {
+	var out strings.Builder
+	out.WriteString("# InstructLab on OpenShift AI demo\n\n")
+	fmt.Fprintf(&out, "Run %s trained the base model on %d skills in %s", summary.RunID, len(summary.Leaves), formatDuration(summary.Duration))
+	if summary.Duration <= DemoTarget {
+		fmt.Fprintf(&out, ", within the %s target.\n\n", DemoTarget)
+	} else {
+		fmt.Fprintf(&out, ", over the %s target.\n\n", DemoTarget)
+	}
+
+	fmt.Fprintf(&out, "## 1. The taxonomy\n\nThe skills were cloned from %s at commit %s:\n\n", summary.RepoURL, summary.Commit)
+	for _, leaf := range summary.Leaves {
+		kind := "freeform skill"
+		if leaf.Grounded {
+			kind = "grounded skill"
+		}
+		fmt.Fprintf(&out, "- `%s`, %s with %d seed examples: %s\n", leaf.Path, kind, leaf.SeedExamples, leaf.TaskDescription)
+	}
+
+	out.WriteString("\n## 2. The pipeline\n\n")
+	for _, phase := range PipelinePhases {
+		duration, ok := summary.Phases[phase]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&out, "- %s (%s): %s\n", phase, formatDuration(duration), demoPhaseSteps[phase])
+	}
+
+	out.WriteString("\n## 3. The results\n\n")
+	if len(summary.Scores) == 0 {
+		out.WriteString("No scores read, set the artifact store settings to show the eval scores of the run.\n")
+	} else {
+		names := make([]string, 0, len(summary.Scores))
+		for name := range summary.Scores {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(&out, "- %s: %.2f\n", name, summary.Scores[name])
+		}
+	}
+	out.WriteString("\nTo try it with your own skills, add a leaf to the taxonomy, push it to a repository and rerun with `sdg_repo_url` pointing at it.\n")
+	return out.String()
+}
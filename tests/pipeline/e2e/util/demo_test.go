@@ -0,0 +1,47 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/demotaxonomy"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderDemoSummary(t *testing.T) {
+	summary := DemoSummary{
+		RunID:   "run-1",
+		RepoURL: "http://demo-taxonomy.ilab.svc:8080/taxonomy.git",
+		Commit:  "0123abcd",
+		Leaves: []demotaxonomy.Leaf{
+			{Path: "compositional_skills/writing/grounded/summarization/release_notes", TaskDescription: "Summarize a release note.", SeedExamples: 5, Grounded: true},
+		},
+		Duration: 95 * time.Minute,
+		Phases:   map[string]time.Duration{"training-phase-1": 20 * time.Minute, "sdg": 30 * time.Minute},
+	}
+	report := RenderDemoSummary(summary)
+	require.Contains(t, report, "Run run-1 trained the base model on 1 skills in 1h35m0s, within the 2h0m0s target.")
+	require.Contains(t, report, "- `compositional_skills/writing/grounded/summarization/release_notes`, grounded skill with 5 seed examples: Summarize a release note.\n")
+	// The phases are walked through in pipeline order
+	require.Regexp(t, `(?s)- sdg \(30m0s\): the teacher model.*- training-phase-1 \(20m0s\)`, report)
+	require.Contains(t, report, "No scores read")
+
+	summary.Duration = 3 * time.Hour
+	summary.Scores = map[string]float64{"mt_bench": 6.25}
+	report = RenderDemoSummary(summary)
+	require.Contains(t, report, "over the 2h0m0s target")
+	require.Contains(t, report, "- mt_bench: 6.25\n")
+}
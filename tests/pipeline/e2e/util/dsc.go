@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+var DataScienceClusterGVR = schema.GroupVersionResource{
+	Group:    "datasciencecluster.opendatahub.io",
+	Version:  "v1",
+	Resource: "datascienceclusters",
+}
+
+// RequiredDSCComponents are the DataScienceCluster components the InstructLab pipeline depends on
+var RequiredDSCComponents = []string{"trainingoperator", "datasciencepipelines"}
+
+// Ready condition types reported for each component, older operator releases use the lowercase variants
+var dscComponentReadyConditions = map[string][]string{
+	"trainingoperator":     {"TrainingOperatorReady", "trainingoperatorReady"},
+	"datasciencepipelines": {"DataSciencePipelinesReady", "data-science-pipelines-operatorReady"},
+}
+
+// GetDataScienceCluster returns the single DataScienceCluster of the cluster
+func GetDataScienceCluster(t *testing.T, client dynamic.Interface) (*unstructured.Unstructured, error) {
+	list, err := client.Resource(DataScienceClusterGVR).List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err, "Failed to list DataScienceClusters")
+
+	if len(list.Items) != 1 {
+		return nil, fmt.Errorf("expected exactly one DataScienceCluster, found %d", len(list.Items))
+	}
+	return &list.Items[0], nil
+}
+
+// EnableDSCComponents sets the given components of the DataScienceCluster to Managed
+func EnableDSCComponents(t *testing.T, client dynamic.Interface, components []string) error {
+	dsc, err := GetDataScienceCluster(t, client)
+	if err != nil {
+		return err
+	}
+
+	patchComponents := map[string]interface{}{}
+	for _, component := range components {
+		patchComponents[component] = map[string]interface{}{"managementState": "Managed"}
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"components": patchComponents},
+	})
+	require.NoError(t, err, "Failed to marshal DataScienceCluster patch")
+
+	_, err = client.Resource(DataScienceClusterGVR).Patch(context.Background(), dsc.GetName(), types.MergePatchType, patch, metav1.PatchOptions{})
+	require.NoError(t, err, "Failed to patch DataScienceCluster")
+	return nil
+}
+
+// WaitForDSCComponentsReady polls the DataScienceCluster until all given components report ready or the timeout expires
+func WaitForDSCComponentsReady(t *testing.T, client dynamic.Interface, components []string, timeout time.Duration) error {
+	deadline := time.After(timeout)
+	tick := time.Tick(10 * time.Second)
+
+	for {
+		dsc, err := GetDataScienceCluster(t, client)
+		if err != nil {
+			return err
+		}
+
+		var notReady []string
+		for _, component := range components {
+			if !dscComponentReady(dsc, component) {
+				notReady = append(notReady, component)
+			}
+		}
+		if len(notReady) == 0 {
+			return nil
+		}
+
+		select {
+		case <-deadline:
+			return fmt.Errorf("DataScienceCluster components %v not ready after %s", notReady, timeout)
+		case <-tick:
+		}
+	}
+}
+
+func dscComponentReady(dsc *unstructured.Unstructured, component string) bool {
+	for _, readyType := range dscComponentReadyConditions[component] {
+		if hasTrueCondition(dsc, readyType) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/egressprobe"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// EgressIPGVR is the resource of the OVN-Kubernetes egress IPs, assigning fixed source addresses to namespaces
+var EgressIPGVR = schema.GroupVersionResource{Group: "k8s.ovn.org", Version: "v1", Resource: "egressips"}
+
+// EgressAddress is a source address the traffic of the run may leave the cluster with
+type EgressAddress struct {
+	Address string
+	// Source explains where the address comes from
+	Source string
+}
+
+// EgressReport is the outcome of the egress preflight
+type EgressReport struct {
+	Probe    egressprobe.Result
+	EchoURL  string
+	NodeName string
+	// HostIP is the address of the node of the probe pod, the source address without an egress IP
+	HostIP    string
+	EgressIPs []EgressAddress
+}
+
+// Addresses returns the source addresses firewalls in front of the endpoints must allow: the address seen by the
+// echo service, the egress IPs of the namespace or, without them, the address of the node of the probe pod
+func (r EgressReport) Addresses() []EgressAddress {
+	var addresses []EgressAddress
+	if r.Probe.EgressIP != "" {
+		addresses = append(addresses, EgressAddress{Address: r.Probe.EgressIP, Source: "seen by " + r.EchoURL})
+	}
+	addresses = append(addresses, r.EgressIPs...)
+	if len(r.EgressIPs) == 0 && r.HostIP != "" {
+		addresses = append(addresses, EgressAddress{Address: r.HostIP, Source: fmt.Sprintf("node %s of the probe pod, the run pods leave the cluster with the address of their node unless a NAT gateway translates it", r.NodeName)})
+	}
+	return addresses
+}
+
+// NamespaceEgressIPs returns the egress IPs assigned to a namespace, the assigned addresses of the status or the
+// requested ones before they are assigned. Clusters without OVN-Kubernetes egress IPs have none.
+func NamespaceEgressIPs(ctx context.Context, client kubernetes.Interface, dynamicClient dynamic.Interface, namespace string) ([]EgressAddress, error) {
+	ns, err := client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+	}
+	list, err := dynamicClient.Resource(EgressIPGVR).List(ctx, metav1.ListOptions{})
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the egress IPs: %w", err)
+	}
+
+	var addresses []EgressAddress
+	for _, egressIP := range list.Items {
+		selects, err := selectorMatches(egressIP.Object, labels.Set(ns.Labels), "spec", "namespaceSelector")
+		if err != nil {
+			return nil, fmt.Errorf("egress IP %s: %w", egressIP.GetName(), err)
+		}
+		if !selects {
+			continue
+		}
+		source := "EgressIP " + egressIP.GetName()
+		if podSelector, found, _ := unstructured.NestedMap(egressIP.Object, "spec", "podSelector"); found && len(podSelector) > 0 {
+			source += ", for the pods of its podSelector only"
+		}
+		var assigned []string
+		items, _, _ := unstructured.NestedSlice(egressIP.Object, "status", "items")
+		for _, item := range items {
+			if address, _, _ := unstructured.NestedString(item.(map[string]interface{}), "egressIP"); address != "" {
+				assigned = append(assigned, address)
+			}
+		}
+		if len(assigned) == 0 {
+			assigned, _, _ = unstructured.NestedStringSlice(egressIP.Object, "spec", "egressIPs")
+			source += ", not assigned to a node yet"
+		}
+		for _, address := range assigned {
+			addresses = append(addresses, EgressAddress{Address: address, Source: source})
+		}
+	}
+	return addresses, nil
+}
+
+// selectorMatches reports whether the label selector at the fields of an object matches the labels, an absent
+// selector matching nothing
+func selectorMatches(object map[string]interface{}, set labels.Set, fields ...string) (bool, error) {
+	value, found, err := unstructured.NestedMap(object, fields...)
+	if err != nil || !found {
+		return false, err
+	}
+	var selector metav1.LabelSelector
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(value, &selector); err != nil {
+		return false, err
+	}
+	parsed, err := metav1.LabelSelectorAsSelector(&selector)
+	if err != nil {
+		return false, err
+	}
+	return parsed.Matches(set), nil
+}
+
+// RunEgressProbe runs egress-probe from image in a pod of the namespace with the name resolution of the pods the tests
+// create, so the targets are reached the way the run reaches them, and reads the egress addresses of the namespace
+func RunEgressProbe(t *testing.T, client kubernetes.Interface, dynamicClient dynamic.Interface, namespace, image string, targets []egressprobe.Target, echoURL string, dns PodDNS, timeout time.Duration) (EgressReport, error) {
+	ctx := context.Background()
+	report := EgressReport{EchoURL: echoURL}
+	egressIPs, err := NamespaceEgressIPs(ctx, client, dynamicClient, namespace)
+	if err != nil {
+		return report, err
+	}
+	report.EgressIPs = egressIPs
+
+	var args []string
+	if echoURL != "" {
+		args = append(args, "-echo-url", echoURL)
+	}
+	for _, target := range targets {
+		args = append(args, target.Name+"="+target.URL)
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: GenerateName("egress-probe")},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{{
+				Name:    "probe",
+				Image:   image,
+				Command: []string{"egress-probe"},
+				Args:    args,
+			}},
+		},
+	}
+	dns.ApplyTo(&pod.Spec)
+	pods := client.CoreV1().Pods(namespace)
+	created, err := pods.Create(ctx, pod, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create egress probe pod")
+	defer func() { _ = pods.Delete(context.Background(), created.Name, metav1.DeleteOptions{}) }()
+
+	deadline := time.After(timeout)
+	tick := time.Tick(5 * time.Second)
+	for {
+		select {
+		case <-deadline:
+			return report, fmt.Errorf("egress probe pod %s did not complete within %s", created.Name, timeout)
+		case <-tick:
+			current, err := pods.Get(ctx, created.Name, metav1.GetOptions{})
+			require.NoError(t, err, "Failed to retrieve egress probe pod")
+			if current.Status.Phase != corev1.PodSucceeded && current.Status.Phase != corev1.PodFailed {
+				continue
+			}
+			report.NodeName, report.HostIP = current.Spec.NodeName, current.Status.HostIP
+
+			logs, err := pods.GetLogs(created.Name, &corev1.PodLogOptions{}).DoRaw(ctx)
+			require.NoError(t, err, "Failed to retrieve egress probe logs")
+			if current.Status.Phase == corev1.PodFailed {
+				return report, fmt.Errorf("egress probe failed, output:\n%s", logs)
+			}
+			report.Probe, err = ParseEgressProbe(logs)
+			return report, err
+		}
+	}
+}
+
+// ParseEgressProbe reads the result printed by egress-probe on the last line of its output
+func ParseEgressProbe(logs []byte) (egressprobe.Result, error) {
+	var result egressprobe.Result
+	lines := bytes.Split(bytes.TrimSpace(logs), []byte("\n"))
+	if err := json.Unmarshal(lines[len(lines)-1], &result); err != nil {
+		return result, fmt.Errorf("failed to parse egress probe output: %w", err)
+	}
+	return result, nil
+}
+
+// RenderEgressReport renders the source addresses to allow and the connectivity to the endpoints as markdown
+func RenderEgressReport(report EgressReport) string {
+	var out strings.Builder
+	out.WriteString("# Egress preflight\n\n")
+	out.WriteString("## Source addresses\n\n")
+	out.WriteString("Allow these source addresses in the firewalls in front of the endpoints:\n\n")
+	for _, address := range report.Addresses() {
+		fmt.Fprintf(&out, "- %s: %s\n", address.Address, address.Source)
+	}
+	if report.Probe.EchoError != "" {
+		fmt.Fprintf(&out, "\nThe echo service %s did not give the address: %s\n", report.EchoURL, report.Probe.EchoError)
+	}
+
+	out.WriteString("\n## Endpoints\n\n")
+	out.WriteString("| Endpoint | URL | Resolved addresses | Connected | HTTP status | Error |\n")
+	out.WriteString("|---|---|---|---|---|---|\n")
+	for _, target := range report.Probe.Targets {
+		connected := "no"
+		if target.Connected {
+			connected = fmt.Sprintf("yes (%s)", target.Latency.Round(time.Millisecond))
+		}
+		status := ""
+		if target.Status != 0 {
+			status = fmt.Sprint(target.Status)
+		}
+		fmt.Fprintf(&out, "| %s | %s | %s | %s | %s | %s |\n", target.Name, target.URL, strings.Join(target.Addresses, ", "), connected, status, target.Error)
+	}
+	return out.String()
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNamespaceEgressIPs(t *testing.T) {
+	egressIP := func(name string, spec, status map[string]interface{}) *unstructured.Unstructured {
+		object := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+		if status != nil {
+			object.Object["status"] = status
+		}
+		object.SetAPIVersion("k8s.ovn.org/v1")
+		object.SetKind("EgressIP")
+		object.SetName(name)
+		return object
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{EgressIPGVR: "EgressIPList"},
+		egressIP("ilab", map[string]interface{}{
+			"egressIPs":         []interface{}{"10.0.5.4"},
+			"namespaceSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"team": "ilab"}},
+		}, map[string]interface{}{"items": []interface{}{map[string]interface{}{"node": "worker-1", "egressIP": "10.0.5.4"}}}),
+		egressIP("pending", map[string]interface{}{
+			"egressIPs":         []interface{}{"10.0.5.9"},
+			"namespaceSelector": map[string]interface{}{"matchExpressions": []interface{}{map[string]interface{}{"key": "team", "operator": "Exists"}}},
+			"podSelector":       map[string]interface{}{"matchLabels": map[string]interface{}{"app": "ilab"}},
+		}, nil),
+		egressIP("other", map[string]interface{}{
+			"egressIPs":         []interface{}{"10.0.6.1"},
+			"namespaceSelector": map[string]interface{}{"matchLabels": map[string]interface{}{"team": "other"}},
+		}, nil),
+	)
+	client := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "ilab", Labels: map[string]string{"team": "ilab"}}})
+
+	addresses, err := NamespaceEgressIPs(context.Background(), client, dynamicClient, "ilab")
+	require.NoError(t, err)
+	require.Equal(t, []EgressAddress{
+		{Address: "10.0.5.4", Source: "EgressIP ilab"},
+		{Address: "10.0.5.9", Source: "EgressIP pending, for the pods of its podSelector only, not assigned to a node yet"},
+	}, addresses)
+}
+
+func TestRenderEgressReport(t *testing.T) {
+	probe, err := ParseEgressProbe([]byte(`2025/03/01 12:00:00 Probing judge at https://judge.example.com/v1
+{"egress_ip":"203.0.113.7","targets":[{"name":"judge","url":"https://judge.example.com/v1","addresses":["198.51.100.4"],"connected":true,"latency":12000000,"status":401},{"name":"object-store","url":"https://s3.internal","connected":false,"error":"failed to resolve s3.internal: no such host"}]}
+`))
+	require.NoError(t, err)
+	report := EgressReport{Probe: probe, EchoURL: "https://api.ipify.org", NodeName: "worker-1", HostIP: "10.0.1.12"}
+	require.Equal(t, []string{"object-store"}, []string{probe.Unreachable()[0].Name})
+	require.Equal(t, `# Egress preflight
+
+## Source addresses
+
+Allow these source addresses in the firewalls in front of the endpoints:
+
+- 203.0.113.7: seen by https://api.ipify.org
+- 10.0.1.12: node worker-1 of the probe pod, the run pods leave the cluster with the address of their node unless a NAT gateway translates it
+
+## Endpoints
+
+| Endpoint | URL | Resolved addresses | Connected | HTTP status | Error |
+|---|---|---|---|---|---|
+| judge | https://judge.example.com/v1 | 198.51.100.4 | yes (12ms) | 401 |  |
+| object-store | https://s3.internal |  | no |  | failed to resolve s3.internal: no such host |
+`, RenderEgressReport(report))
+
+	// The egress IPs of the namespace replace the address of the node
+	report.EgressIPs = []EgressAddress{{Address: "10.0.5.4", Source: "EgressIP ilab"}}
+	require.Equal(t, []EgressAddress{{Address: "203.0.113.7", Source: "seen by https://api.ipify.org"}, report.EgressIPs[0]}, report.Addresses())
+
+	_, err = ParseEgressProbe([]byte("usage: egress-probe\n"))
+	require.ErrorContains(t, err, "failed to parse egress probe output")
+}
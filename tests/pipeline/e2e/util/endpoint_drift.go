@@ -0,0 +1,149 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+// Reasons of an endpoint validation failure
+const (
+	EndpointUnresolvable = "unresolvable"
+	EndpointUnreachable  = "unreachable"
+	EndpointUnauthorized = "unauthorized"
+	EndpointModelMissing = "model-missing"
+	EndpointUnexpected   = "unexpected-response"
+	// EndpointClusterLocal is not a failure, the endpoint is a service of the cluster the test host cannot resolve
+	EndpointClusterLocal = "cluster-local"
+)
+
+// EndpointError is a failed validation of a model server endpoint against its secret
+type EndpointError struct {
+	Reason  string
+	Message string
+}
+
+func (e *EndpointError) Error() string {
+	return fmt.Sprintf("[%s] %s", e.Reason, e.Message)
+}
+
+// ValidateModelEndpoint checks the endpoint of a model server secret resolves, accepts the API token of the secret
+// and serves the model of the secret, by listing the models of the OpenAI compatible API
+func ValidateModelEndpoint(ctx context.Context, client *http.Client, secret ModelServerSecret) error {
+	endpoint, err := url.Parse(secret.Endpoint)
+	if err != nil || endpoint.Hostname() == "" {
+		return &EndpointError{EndpointUnresolvable, fmt.Sprintf("invalid endpoint '%s'", secret.Endpoint)}
+	}
+	if IsClusterLocalHost(endpoint.Hostname()) {
+		return &EndpointError{EndpointClusterLocal, fmt.Sprintf("host %s is a service of the cluster, it is not validated from the test host", endpoint.Hostname())}
+	}
+	if _, err := net.DefaultResolver.LookupHost(ctx, endpoint.Hostname()); err != nil {
+		return &EndpointError{EndpointUnresolvable, fmt.Sprintf("host %s does not resolve: %v", endpoint.Hostname(), err)}
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(secret.Endpoint, "/")+"/models", nil)
+	if err != nil {
+		return &EndpointError{EndpointUnexpected, err.Error()}
+	}
+	request.Header.Set("Authorization", "Bearer "+secret.APIToken)
+	response, err := client.Do(request)
+	if err != nil {
+		return &EndpointError{EndpointUnreachable, err.Error()}
+	}
+	defer response.Body.Close()
+
+	switch {
+	case response.StatusCode == http.StatusUnauthorized || response.StatusCode == http.StatusForbidden:
+		return &EndpointError{EndpointUnauthorized, fmt.Sprintf("the API token was rejected with %s, the credentials may have expired", response.Status)}
+	case response.StatusCode != http.StatusOK:
+		return &EndpointError{EndpointUnexpected, fmt.Sprintf("listing the models returned %s", response.Status)}
+	}
+
+	var models struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&models); err != nil {
+		return &EndpointError{EndpointUnexpected, fmt.Sprintf("invalid model list: %v", err)}
+	}
+	for _, model := range models.Data {
+		if model.ID == secret.ModelName {
+			return nil
+		}
+	}
+	return &EndpointError{EndpointModelMissing, fmt.Sprintf("model %s is not served", secret.ModelName)}
+}
+
+// IsClusterLocalHost tells whether a host name only resolves inside the cluster, such as the in-cluster judge, the
+// recording proxies and the stub LLM services
+func IsClusterLocalHost(host string) bool {
+	host = strings.TrimSuffix(host, ".")
+	if net.ParseIP(host) != nil {
+		return false
+	}
+	return !strings.Contains(host, ".") || strings.HasSuffix(host, ".svc") || strings.HasSuffix(host, ".cluster.local")
+}
+
+// WatchEndpointDrift re-reads the model server secrets, by role, and validates their endpoints whenever the pipeline
+// run enters a new phase, checking the run phase at every interval, until the returned stop function is called.
+// Every validation is passed to report.
+func WatchEndpointDrift(client kubernetes.Interface, namespace, runID string, secrets map[string]string, interval time.Duration, report func(phase, role string, err error)) (stop func()) {
+	roles := make([]string, 0, len(secrets))
+	for role := range secrets {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	done := make(chan struct{})
+	go func() {
+		validated := ""
+		tick := time.Tick(interval)
+		for {
+			select {
+			case <-done:
+				return
+			case <-tick:
+				tasks, err := ListRunTaskPods(client, namespace, runID)
+				if err != nil {
+					continue
+				}
+				phase := CurrentRunPhase(tasks).Phase
+				if phase == validated {
+					continue
+				}
+				validated = phase
+				for _, role := range roles {
+					secret, err := ReadModelServerSecret(client, namespace, secrets[role])
+					if err == nil {
+						err = ValidateModelEndpoint(context.Background(), httpClient, secret)
+					}
+					report(phase, role, err)
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateModelEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" || r.Header.Get("Authorization") != "Bearer valid" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = io.WriteString(w, `{"object": "list", "data": [{"id": "mistral-7b-instruct"}]}`)
+	}))
+	defer server.Close()
+
+	reason := func(err error) string {
+		var endpointErr *EndpointError
+		if errors.As(err, &endpointErr) {
+			return endpointErr.Reason
+		}
+		return ""
+	}
+	validate := func(secret ModelServerSecret) error {
+		return ValidateModelEndpoint(context.Background(), server.Client(), secret)
+	}
+
+	require.NoError(t, validate(ModelServerSecret{Endpoint: server.URL + "/v1", APIToken: "valid", ModelName: "mistral-7b-instruct"}))
+	require.Equal(t, EndpointUnauthorized, reason(validate(ModelServerSecret{Endpoint: server.URL + "/v1", APIToken: "expired", ModelName: "mistral-7b-instruct"})))
+	require.Equal(t, EndpointModelMissing, reason(validate(ModelServerSecret{Endpoint: server.URL + "/v1/", APIToken: "valid", ModelName: "prometheus-8x7b"})))
+	require.Equal(t, EndpointUnresolvable, reason(validate(ModelServerSecret{Endpoint: "not a url", APIToken: "valid"})))
+	require.Equal(t, EndpointClusterLocal, reason(validate(ModelServerSecret{Endpoint: "http://judge.ilab.svc:8080/v1", APIToken: "valid"})))
+}
+
+func TestIsClusterLocalHost(t *testing.T) {
+	for host, local := range map[string]bool{
+		"judge.ilab.svc":                true,
+		"judge.ilab.svc.cluster.local.": true,
+		"stub-llm":                      true,
+		"127.0.0.1":                     false,
+		"mistral.apps.example.com":      false,
+		"judge-ilab.apps.cluster.com":   false,
+	} {
+		require.Equal(t, local, IsClusterLocalHost(host), host)
+	}
+}
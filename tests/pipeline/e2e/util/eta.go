@@ -0,0 +1,202 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/clock"
+)
+
+// DefaultOverrunFactor is the multiple of the historical median duration of a phase above which the phase is flagged
+const DefaultOverrunFactor = 1.5
+
+// RunHistoryEntry records the phase durations of a successful run, from which the durations of the next runs are
+// expected
+type RunHistoryEntry struct {
+	RunID  string                   `json:"run_id"`
+	Start  time.Time                `json:"start"`
+	Phases map[string]time.Duration `json:"phases"`
+	// Params are the numeric pipeline parameters of the run, scaling the estimates of the time budget mode
+	Params map[string]float64 `json:"params,omitempty"`
+}
+
+// LoadRunHistory reads the run history from a JSON lines file, a missing file being an empty history
+func LoadRunHistory(path string) ([]RunHistoryEntry, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open run history: %w", err)
+	}
+	defer file.Close()
+
+	var history []RunHistoryEntry
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var entry RunHistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("invalid run history entry on line %d of %s: %w", line, path, err)
+		}
+		history = append(history, entry)
+	}
+	return history, scanner.Err()
+}
+
+// AppendRunHistory adds an entry to the run history file
+func AppendRunHistory(path string, entry RunHistoryEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open run history: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to append to run history: %w", err)
+	}
+	return file.Close()
+}
+
+// PhaseMedians returns the median duration of every phase in the run history
+func PhaseMedians(history []RunHistoryEntry) map[string]time.Duration {
+	durations := map[string][]time.Duration{}
+	for _, entry := range history {
+		for phase, duration := range entry.Phases {
+			durations[phase] = append(durations[phase], duration)
+		}
+	}
+	medians := map[string]time.Duration{}
+	for phase, values := range durations {
+		medians[phase] = medianDuration(values)
+	}
+	return medians
+}
+
+// PhaseETA is the expected completion of a phase of a run
+type PhaseETA struct {
+	Phase    string
+	Expected time.Duration
+	ETA      time.Time
+}
+
+// PhaseETAs returns the expected completion of the phases with a historical median, in pipeline order, for a run
+// started at start. Phases run one after the other, so every phase is expected to complete its median after the
+// previous one.
+func PhaseETAs(medians map[string]time.Duration, start time.Time) []PhaseETA {
+	var etas []PhaseETA
+	eta := start
+	for _, phase := range PipelinePhases {
+		median, ok := medians[phase]
+		if !ok {
+			continue
+		}
+		eta = eta.Add(median)
+		etas = append(etas, PhaseETA{Phase: phase, Expected: median, ETA: eta})
+	}
+	return etas
+}
+
+// PhaseOverrun is a phase running for longer than expected
+type PhaseOverrun struct {
+	Phase   string
+	Elapsed time.Duration
+	Median  time.Duration
+}
+
+func (o PhaseOverrun) String() string {
+	return fmt.Sprintf("phase %s has been running for %s, %.0f%% of its historical median of %s",
+		o.Phase, o.Elapsed.Round(time.Second), float64(o.Elapsed)/float64(o.Median)*100, o.Median.Round(time.Second))
+}
+
+// PhaseOverruns returns the phases of the task pods running, or that ran, for longer than factor times their median.
+// Running phases are timed until now.
+func PhaseOverruns(tasks []TaskPod, medians map[string]time.Duration, factor float64, now time.Time) []PhaseOverrun {
+	elapsed := PhaseDurations(tasks)
+	starts := map[string]time.Time{}
+	for _, task := range tasks {
+		phase := TaskPhase(task)
+		if phase == "" || task.Pod.Status.StartTime == nil {
+			continue
+		}
+		if start, ok := starts[phase]; !ok || task.Pod.Status.StartTime.Time.Before(start) {
+			starts[phase] = task.Pod.Status.StartTime.Time
+		}
+	}
+	for _, task := range tasks {
+		phase := TaskPhase(task)
+		if start, ok := starts[phase]; ok && task.Pod.Status.Phase != corev1.PodSucceeded && task.Pod.Status.Phase != corev1.PodFailed {
+			elapsed[phase] = now.Sub(start)
+		}
+	}
+
+	var overruns []PhaseOverrun
+	for _, phase := range PipelinePhases {
+		median, ok := medians[phase]
+		if ok && median > 0 && float64(elapsed[phase]) > factor*float64(median) {
+			overruns = append(overruns, PhaseOverrun{Phase: phase, Elapsed: elapsed[phase], Median: median})
+		}
+	}
+	return overruns
+}
+
+// WatchPhaseOverruns reports once every phase of a pipeline run exceeding factor times its median, checking at every
+// interval until the returned stop function is called. Every check is logged with debugf.
+func WatchPhaseOverruns(client kubernetes.Interface, namespace, runID string, medians map[string]time.Duration, factor float64, interval time.Duration, debugf func(string, ...interface{}), report func(PhaseOverrun, error)) (stop func()) {
+	return watchPhaseOverruns(clock.RealClock{}, client, namespace, runID, medians, factor, interval, debugf, report)
+}
+
+func watchPhaseOverruns(clk clock.Clock, client kubernetes.Interface, namespace, runID string, medians map[string]time.Duration, factor float64, interval time.Duration, debugf func(string, ...interface{}), report func(PhaseOverrun, error)) (stop func()) {
+	done := make(chan struct{})
+	tick := clk.Tick(interval)
+	reported := map[string]bool{}
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-tick:
+				tasks, err := ListRunTaskPods(client, namespace, runID)
+				if err != nil {
+					report(PhaseOverrun{}, err)
+					continue
+				}
+				overruns := PhaseOverruns(tasks, medians, factor, clk.Now())
+				debugf("Checked the phases of %d task pods of run %s, %d over %.1f times their median", len(tasks), runID, len(overruns), factor)
+				for _, overrun := range overruns {
+					if !reported[overrun.Phase] {
+						reported[overrun.Phase] = true
+						report(overrun, nil)
+					}
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
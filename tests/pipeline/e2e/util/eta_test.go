@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestRunHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run-history.jsonl")
+	history, err := LoadRunHistory(path)
+	require.NoError(t, err)
+	require.Empty(t, history)
+
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, sdg := range []time.Duration{time.Hour, 3 * time.Hour, 2 * time.Hour} {
+		require.NoError(t, AppendRunHistory(path, RunHistoryEntry{RunID: "run", Start: start.Add(time.Duration(i) * 24 * time.Hour), Phases: map[string]time.Duration{
+			"sdg": sdg, "training-phase-1": 4 * time.Hour,
+		}}))
+	}
+	history, err = LoadRunHistory(path)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+
+	medians := PhaseMedians(history)
+	require.Equal(t, map[string]time.Duration{"sdg": 2 * time.Hour, "training-phase-1": 4 * time.Hour}, medians)
+	require.Equal(t, []PhaseETA{
+		{Phase: "sdg", Expected: 2 * time.Hour, ETA: start.Add(2 * time.Hour)},
+		{Phase: "training-phase-1", Expected: 4 * time.Hour, ETA: start.Add(6 * time.Hour)},
+	}, PhaseETAs(medians, start))
+}
+
+// sdgTaskPod returns the pod of an SDG task started at start, finished at end unless zero
+func sdgTaskPod(start, end time.Time) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "sdg", Namespace: "ns", Labels: map[string]string{RunIDLabel: "run-1"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: "main",
+			Args: []string{"--executor_input", `{"inputs":{"parameterValues":{"num_instructions_to_generate":30}}}`, "--function_to_execute", "sdg_op"},
+		}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, StartTime: &metav1.Time{Time: start}},
+	}
+	if !end.IsZero() {
+		pod.Status.Phase = corev1.PodSucceeded
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{FinishedAt: metav1.Time{Time: end}}}}}
+	}
+	return pod
+}
+
+func TestPhaseOverruns(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	medians := map[string]time.Duration{"sdg": 2 * time.Hour}
+	running, ok := ParseTaskPod(*sdgTaskPod(start, time.Time{}))
+	require.True(t, ok)
+
+	require.Empty(t, PhaseOverruns([]TaskPod{running}, medians, DefaultOverrunFactor, start.Add(3*time.Hour)))
+	overruns := PhaseOverruns([]TaskPod{running}, medians, DefaultOverrunFactor, start.Add(4*time.Hour))
+	require.Equal(t, []PhaseOverrun{{Phase: "sdg", Elapsed: 4 * time.Hour, Median: 2 * time.Hour}}, overruns)
+	require.Equal(t, "phase sdg has been running for 4h0m0s, 200% of its historical median of 2h0m0s", overruns[0].String())
+
+	// Finished phases are timed until their end
+	finished, _ := ParseTaskPod(*sdgTaskPod(start, start.Add(time.Hour)))
+	require.Empty(t, PhaseOverruns([]TaskPod{finished}, medians, DefaultOverrunFactor, start.Add(10*time.Hour)))
+}
+
+func TestWatchPhaseOverruns(t *testing.T) {
+	clock := testingclock.NewFakeClock(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	client := fake.NewSimpleClientset(sdgTaskPod(clock.Now(), time.Time{}))
+	overruns := make(chan PhaseOverrun, 10)
+	stop := watchPhaseOverruns(clock, client, "ns", "run-1", map[string]time.Duration{"sdg": time.Hour}, DefaultOverrunFactor, 10*time.Minute, t.Logf, func(overrun PhaseOverrun, err error) {
+		require.NoError(t, err)
+		overruns <- overrun
+	})
+	defer stop()
+
+	// The overrun is reported once, on the first check past 150% of the median
+	for i := 0; i < 12; i++ {
+		clock.Step(10 * time.Minute)
+	}
+	overrun := <-overruns
+	require.Equal(t, "sdg", overrun.Phase)
+	require.GreaterOrEqual(t, overrun.Elapsed, 90*time.Minute)
+	require.Never(t, func() bool { return len(overruns) > 0 }, 100*time.Millisecond, 10*time.Millisecond)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// WaitForRunningTask waits for a pod of the run executing the given component function to be running
+func WaitForRunningTask(t *testing.T, client kubernetes.Interface, namespace, runID, function string, timeout time.Duration) (TaskPod, error) {
+	deadline := time.After(timeout)
+	tick := time.Tick(10 * time.Second)
+	for {
+		select {
+		case <-deadline:
+			return TaskPod{}, fmt.Errorf("no %s pod of run %s was running within %s", function, runID, timeout)
+		case <-tick:
+			for _, task := range GetRunTaskPods(t, client, namespace, runID) {
+				if task.Function == function && task.Pod.Status.Phase == corev1.PodRunning {
+					return task, nil
+				}
+			}
+		}
+	}
+}
+
+// EvictPod evicts a pod through the eviction API, as a node drain does, so its disruption budget is respected
+func EvictPod(t *testing.T, client kubernetes.Interface, pod corev1.Pod) {
+	eviction := &policyv1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace}}
+	err := client.PolicyV1().Evictions(pod.Namespace).Evict(context.Background(), eviction)
+	require.NoError(t, err, "Failed to evict pod %s", pod.Name)
+}
+
+// CheckEvictionFailure verifies a run failed at the eviction of a task pod. The pipeline sets no retry policy, so the
+// pipeline server does not restart an evicted task and the run fails with the task.
+func CheckEvictionFailure(details RunDetails, evicted TaskPod) error {
+	if details.State != "FAILED" {
+		return fmt.Errorf("run ended with status %s after the eviction of %s pod %s, expected it to fail", details.State, evicted.Function, evicted.Pod.Name)
+	}
+	for _, task := range details.RunDetails.TaskDetails {
+		if task.State == "FAILED" && isTaskOf(task.DisplayName, evicted.Function) {
+			return nil
+		}
+	}
+	return fmt.Errorf("run failed without a failed %s task: %s", evicted.Function, strings.Join(details.FailureMessages(), "; "))
+}
+
+// CheckRerunAfterEviction verifies a rerun of a run that failed at the eviction of a task pod succeeded, reused the
+// cached tasks, and executed the evicted task again
+func CheckRerunAfterEviction(rerun RunDetails, evicted TaskPod) error {
+	if rerun.State != "SUCCEEDED" {
+		return fmt.Errorf("rerun ended with status %s: %s", rerun.State, strings.Join(rerun.FailureMessages(), "; "))
+	}
+	cached := rerun.CachedTasks()
+	if len(cached) == 0 {
+		return fmt.Errorf("rerun did not reuse any cached task")
+	}
+	for _, task := range cached {
+		if isTaskOf(task, evicted.Function) {
+			return fmt.Errorf("rerun reused a cached %s task although the evicted task never completed", evicted.Function)
+		}
+	}
+	return nil
+}
+
+// isTaskOf tells whether a task display name is the name of a task of the given component function. The compiled
+// pipeline names tasks after their function with dashes, suffixed with a number from the second task on.
+func isTaskOf(displayName, function string) bool {
+	name := strings.ReplaceAll(function, "_", "-")
+	if displayName == name {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(displayName, name+"-")
+	if !ok || suffix == "" {
+		return false
+	}
+	for _, c := range suffix {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
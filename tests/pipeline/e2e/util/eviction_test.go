@@ -0,0 +1,58 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCheckEvictionFailure(t *testing.T) {
+	parse := func(body string) RunDetails {
+		var details RunDetails
+		require.NoError(t, json.Unmarshal([]byte(body), &details))
+		return details
+	}
+	evicted := TaskPod{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "run-sdg-1"}}, Function: "sdg_op"}
+
+	failed := parse(`{"state": "FAILED", "run_details": {"task_details": [
+		{"display_name": "prerequisites-check-op", "state": "SUCCEEDED"}, {"display_name": "sdg-op", "state": "FAILED"}]}}`)
+	require.NoError(t, CheckEvictionFailure(failed, evicted))
+	require.EqualError(t, CheckEvictionFailure(RunDetails{State: "SUCCEEDED"}, evicted), "run ended with status SUCCEEDED after the eviction of sdg_op pod run-sdg-1, expected it to fail")
+
+	other := parse(`{"state": "FAILED", "run_details": {"task_details": [
+		{"display_name": "sdg-op", "state": "SUCCEEDED"}, {"display_name": "sdg-to-artifact-op", "state": "FAILED", "error": {"message": "copy failed"}}]}}`)
+	require.EqualError(t, CheckEvictionFailure(other, evicted), "run failed without a failed sdg_op task: sdg-to-artifact-op: copy failed")
+
+	launcher := TaskPod{Function: "pytorch_job_launcher_op"}
+	phase2 := parse(`{"state": "FAILED", "run_details": {"task_details": [
+		{"display_name": "pytorch-job-launcher-op", "state": "SUCCEEDED"}, {"display_name": "pytorch-job-launcher-op-2", "state": "FAILED"}]}}`)
+	require.NoError(t, CheckEvictionFailure(phase2, launcher))
+	unrelated := parse(`{"state": "FAILED", "run_details": {"task_details": [{"display_name": "pytorch-job-launcher-op-x", "state": "FAILED"}]}}`)
+	require.Error(t, CheckEvictionFailure(unrelated, launcher))
+
+	evicted = TaskPod{Function: "sdg_op"}
+	rerun := parse(`{"state": "SUCCEEDED", "run_details": {"task_details": [
+		{"display_name": "createpvc", "state": "CACHED"}, {"display_name": "sdg-op", "state": "SUCCEEDED"}]}}`)
+	require.NoError(t, CheckRerunAfterEviction(rerun, evicted))
+	uncached := parse(`{"state": "SUCCEEDED", "run_details": {"task_details": [{"display_name": "sdg-op", "state": "SUCCEEDED"}]}}`)
+	require.EqualError(t, CheckRerunAfterEviction(uncached, evicted), "rerun did not reuse any cached task")
+	stale := parse(`{"state": "SUCCEEDED", "run_details": {"task_details": [{"display_name": "sdg-op", "state": "CACHED"}]}}`)
+	require.EqualError(t, CheckRerunAfterEviction(stale, evicted), "rerun reused a cached sdg_op task although the evicted task never completed")
+	require.EqualError(t, CheckRerunAfterEviction(parse(`{"state": "FAILED", "error": {"message": "exit status 1"}}`), evicted), "rerun ended with status FAILED: exit status 1")
+}
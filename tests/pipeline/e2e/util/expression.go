@@ -0,0 +1,538 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expression is a parsed assertion in the subset of the CEL syntax needed for thresholds: number, string and bool
+// literals, variables, map access with m["key"] or m.key, "key" in m, the ! and - unary operators, + - * /,
+// comparisons, && and ||, and the c ? a : b conditional. Numbers are doubles. Evaluation follows CEL, except that &&
+// and || short-circuit from left to right.
+type Expression struct {
+	source string
+	root   exprNode
+}
+
+type exprNode interface {
+	eval(vars map[string]interface{}) (interface{}, error)
+}
+
+// ParseExpression parses an expression, rejecting references to variables other than the given ones
+func ParseExpression(source string, variables []string) (*Expression, error) {
+	tokens, err := tokenizeExpression(source)
+	if err != nil {
+		return nil, err
+	}
+	known := map[string]bool{}
+	for _, name := range variables {
+		known[name] = true
+	}
+	p := &exprParser{tokens: tokens, variables: known}
+	root, err := p.parseConditional()
+	if err != nil {
+		return nil, err
+	}
+	if token := p.peek(); token.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at offset %d", token.text, token.offset)
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+// Eval evaluates the expression against variables holding float64, string, bool or map[string]interface{} values
+func (e *Expression) Eval(vars map[string]interface{}) (interface{}, error) {
+	return e.root.eval(vars)
+}
+
+func (e *Expression) String() string {
+	return e.source
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOperator
+)
+
+type exprToken struct {
+	kind   tokenKind
+	text   string
+	offset int
+}
+
+var exprOperators = []string{"&&", "||", "<=", ">=", "==", "!=", "<", ">", "+", "-", "*", "/", "!", "(", ")", "[", "]", ".", "?", ":"}
+
+func tokenizeExpression(source string) ([]exprToken, error) {
+	var tokens []exprToken
+	for i := 0; i < len(source); {
+		c := rune(source[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c) || (c == '.' && i+1 < len(source) && unicode.IsDigit(rune(source[i+1]))):
+			start := i
+			for i < len(source) && (unicode.IsDigit(rune(source[i])) || source[i] == '.' || source[i] == 'e' || source[i] == 'E' ||
+				((source[i] == '+' || source[i] == '-') && (source[i-1] == 'e' || source[i-1] == 'E'))) {
+				i++
+			}
+			tokens = append(tokens, exprToken{tokenNumber, source[start:i], start})
+		case c == '"' || c == '\'':
+			start := i
+			i++
+			for i < len(source) && rune(source[i]) != c {
+				if source[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(source) {
+				return nil, fmt.Errorf("unterminated string at offset %d", start)
+			}
+			i++
+			body := source[start+1 : i-1]
+			if c == '\'' {
+				body = singleToDoubleQuoted(body)
+			}
+			text, err := strconv.Unquote(`"` + body + `"`)
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at offset %d: %w", start, err)
+			}
+			tokens = append(tokens, exprToken{tokenString, text, start})
+		case c == '_' || unicode.IsLetter(c):
+			start := i
+			for i < len(source) && (source[i] == '_' || unicode.IsLetter(rune(source[i])) || unicode.IsDigit(rune(source[i]))) {
+				i++
+			}
+			tokens = append(tokens, exprToken{tokenIdent, source[start:i], start})
+		default:
+			matched := false
+			for _, operator := range exprOperators {
+				if strings.HasPrefix(source[i:], operator) {
+					tokens = append(tokens, exprToken{tokenOperator, operator, i})
+					i += len(operator)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q at offset %d", c, i)
+			}
+		}
+	}
+	return append(tokens, exprToken{tokenEOF, "end of expression", len(source)}), nil
+}
+
+// singleToDoubleQuoted rewrites the body of a single-quoted literal for strconv.Unquote: bare double quotes are
+// escaped and escaped single quotes are not
+func singleToDoubleQuoted(body string) string {
+	var quoted strings.Builder
+	for i := 0; i < len(body); i++ {
+		switch {
+		case body[i] == '\\' && i+1 < len(body):
+			if body[i+1] != '\'' {
+				quoted.WriteByte('\\')
+			}
+			quoted.WriteByte(body[i+1])
+			i++
+		case body[i] == '"':
+			quoted.WriteString(`\"`)
+		default:
+			quoted.WriteByte(body[i])
+		}
+	}
+	return quoted.String()
+}
+
+type exprParser struct {
+	tokens    []exprToken
+	pos       int
+	variables map[string]bool
+}
+
+func (p *exprParser) peek() exprToken {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) accept(operators ...string) (string, bool) {
+	token := p.peek()
+	if token.kind != tokenOperator && !(token.kind == tokenIdent && token.text == "in") {
+		return "", false
+	}
+	for _, operator := range operators {
+		if token.text == operator {
+			p.pos++
+			return operator, true
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) expect(operator string) error {
+	if _, ok := p.accept(operator); !ok {
+		token := p.peek()
+		return fmt.Errorf("expected %q at offset %d, found %q", operator, token.offset, token.text)
+	}
+	return nil
+}
+
+func (p *exprParser) parseConditional() (exprNode, error) {
+	condition, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.accept("?"); !ok {
+		return condition, nil
+	}
+	then, err := p.parseConditional()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	otherwise, err := p.parseConditional()
+	if err != nil {
+		return nil, err
+	}
+	return conditionalNode{condition, then, otherwise}, nil
+}
+
+// exprPrecedence lists the binary operators from the lowest to the highest precedence
+var exprPrecedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"<", "<=", ">", ">=", "==", "!=", "in"},
+	{"+", "-"},
+	{"*", "/"},
+}
+
+func (p *exprParser) parseBinary(level int) (exprNode, error) {
+	if level == len(exprPrecedence) {
+		return p.parseUnary()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		operator, ok := p.accept(exprPrecedence[level]...)
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{operator, left, right}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if operator, ok := p.accept("!", "-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{operator, operand}, nil
+	}
+	return p.parseMember()
+}
+
+func (p *exprParser) parseMember() (exprNode, error) {
+	node, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("."); ok {
+			token := p.peek()
+			if token.kind != tokenIdent {
+				return nil, fmt.Errorf("expected a field name at offset %d, found %q", token.offset, token.text)
+			}
+			p.pos++
+			node = indexNode{node, literalNode{token.text}}
+		} else if _, ok := p.accept("["); ok {
+			key, err := p.parseConditional()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			node = indexNode{node, key}
+		} else {
+			return node, nil
+		}
+	}
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	token := p.peek()
+	switch token.kind {
+	case tokenNumber:
+		p.pos++
+		value, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at offset %d", token.text, token.offset)
+		}
+		return literalNode{value}, nil
+	case tokenString:
+		p.pos++
+		return literalNode{token.text}, nil
+	case tokenIdent:
+		p.pos++
+		switch token.text {
+		case "true", "false":
+			return literalNode{token.text == "true"}, nil
+		}
+		if !p.variables[token.text] {
+			return nil, fmt.Errorf("undeclared reference to '%s' at offset %d", token.text, token.offset)
+		}
+		return variableNode{token.text}, nil
+	}
+	if _, ok := p.accept("("); ok {
+		node, err := p.parseConditional()
+		if err != nil {
+			return nil, err
+		}
+		return node, p.expect(")")
+	}
+	return nil, fmt.Errorf("unexpected %q at offset %d", token.text, token.offset)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n literalNode) eval(map[string]interface{}) (interface{}, error) {
+	return n.value, nil
+}
+
+type variableNode struct {
+	name string
+}
+
+func (n variableNode) eval(vars map[string]interface{}) (interface{}, error) {
+	value, ok := vars[n.name]
+	if !ok {
+		return nil, fmt.Errorf("no such attribute: %s", n.name)
+	}
+	return value, nil
+}
+
+type indexNode struct {
+	target, key exprNode
+}
+
+func (n indexNode) eval(vars map[string]interface{}) (interface{}, error) {
+	target, err := n.target.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	key, err := n.key.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := target.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("no such overload: %s[%s]", typeName(target), typeName(key))
+	}
+	name, ok := key.(string)
+	if !ok {
+		return nil, fmt.Errorf("no such overload: map[%s]", typeName(key))
+	}
+	value, ok := m[name]
+	if !ok {
+		return nil, fmt.Errorf("no such key: %s", name)
+	}
+	return value, nil
+}
+
+type unaryNode struct {
+	operator string
+	operand  exprNode
+}
+
+func (n unaryNode) eval(vars map[string]interface{}) (interface{}, error) {
+	value, err := n.operand.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch v := value.(type) {
+	case bool:
+		if n.operator == "!" {
+			return !v, nil
+		}
+	case float64:
+		if n.operator == "-" {
+			return -v, nil
+		}
+	}
+	return nil, fmt.Errorf("no such overload: %s%s", n.operator, typeName(value))
+}
+
+type conditionalNode struct {
+	condition, then, otherwise exprNode
+}
+
+func (n conditionalNode) eval(vars map[string]interface{}) (interface{}, error) {
+	condition, err := n.condition.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := condition.(bool)
+	if !ok {
+		return nil, fmt.Errorf("no such overload: %s ? _ : _", typeName(condition))
+	}
+	if value {
+		return n.then.eval(vars)
+	}
+	return n.otherwise.eval(vars)
+}
+
+type binaryNode struct {
+	operator    string
+	left, right exprNode
+}
+
+func (n binaryNode) eval(vars map[string]interface{}) (interface{}, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	if n.operator == "&&" || n.operator == "||" {
+		value, ok := left.(bool)
+		if !ok {
+			return nil, fmt.Errorf("no such overload: %s %s _", typeName(left), n.operator)
+		}
+		if value == (n.operator == "||") {
+			return value, nil
+		}
+		right, err := n.right.eval(vars)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := right.(bool); !ok {
+			return nil, fmt.Errorf("no such overload: bool %s %s", n.operator, typeName(right))
+		}
+		return right, nil
+	}
+
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	switch n.operator {
+	case "==":
+		return equalValues(left, right), nil
+	case "!=":
+		return !equalValues(left, right), nil
+	case "in":
+		m, ok := right.(map[string]interface{})
+		key, isString := left.(string)
+		if !ok || !isString {
+			break
+		}
+		_, found := m[key]
+		return found, nil
+	}
+
+	if l, ok := left.(float64); ok {
+		if r, ok := right.(float64); ok {
+			switch n.operator {
+			case "+":
+				return l + r, nil
+			case "-":
+				return l - r, nil
+			case "*":
+				return l * r, nil
+			case "/":
+				if r == 0 {
+					return nil, fmt.Errorf("division by zero")
+				}
+				return l / r, nil
+			case "<":
+				return l < r, nil
+			case "<=":
+				return l <= r, nil
+			case ">":
+				return l > r, nil
+			case ">=":
+				return l >= r, nil
+			}
+		}
+	}
+	if l, ok := left.(string); ok {
+		if r, ok := right.(string); ok {
+			switch n.operator {
+			case "+":
+				return l + r, nil
+			case "<":
+				return l < r, nil
+			case "<=":
+				return l <= r, nil
+			case ">":
+				return l > r, nil
+			case ">=":
+				return l >= r, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("no such overload: %s %s %s", typeName(left), n.operator, typeName(right))
+}
+
+func equalValues(left, right interface{}) bool {
+	switch l := left.(type) {
+	case map[string]interface{}:
+		r, ok := right.(map[string]interface{})
+		if !ok || len(l) != len(r) {
+			return false
+		}
+		for key, value := range l {
+			other, ok := r[key]
+			if !ok || !equalValues(value, other) {
+				return false
+			}
+		}
+		return true
+	case float64, string, bool:
+		return left == right
+	}
+	return false
+}
+
+func typeName(value interface{}) string {
+	switch v := value.(type) {
+	case float64:
+		return "double"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case map[string]interface{}:
+		for _, element := range v {
+			return fmt.Sprintf("map(string, %s)", typeName(element))
+		}
+		return "map"
+	}
+	return fmt.Sprintf("%T", value)
+}
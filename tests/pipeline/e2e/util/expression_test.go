@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpression(t *testing.T) {
+	vars := map[string]interface{}{
+		"duration": 5400.0,
+		"phases":   map[string]interface{}{"sdg": 1200.0, "training-phase-1": 3000.0},
+		"usage":    map[string]interface{}{"sdg": map[string]interface{}{"peak_cpu_millis": 4000.0}},
+		"name":     "granite",
+	}
+	variables := []string{"duration", "phases", "usage", "name"}
+	eval := func(source string) (interface{}, error) {
+		expression, err := ParseExpression(source, variables)
+		require.NoError(t, err, source)
+		return expression.Eval(vars)
+	}
+
+	for source, want := range map[string]interface{}{
+		"1 + 2 * 3 - 4 / 2": 5.0,
+		"-(1 + 2) * 3":      -9.0,
+		"1.5e3 + .5":        1500.5,
+		"duration / 60.0 < 100.0 && !(duration > 6000)":      true,
+		`phases["sdg"] + phases['training-phase-1'] == 4200`: true,
+		"usage.sdg.peak_cpu_millis >= 4000":                  true,
+		`"sdg" in phases && !("mt-bench" in phases)`:         true,
+		`"mt-bench" in phases ? phases["mt-bench"] : -1.0`:   -1.0,
+		`name + "-7b" == "granite-7b" || phases["x"] > 0`:    true,
+		`false && phases["x"] > 0`:                           false,
+		`name < "llama" == true`:                             true,
+		"usage == usage":                                     true,
+		"1 != 2 && 2 <= 2 && 3 > 2 && true != false":         true,
+		`"a\"b" == 'a"b'`:                                    true,
+		`'it\'s \"ok\"' == "it's \"ok\""`:                    true,
+	} {
+		got, err := eval(source)
+		require.NoError(t, err, source)
+		require.Equal(t, want, got, source)
+	}
+
+	for source, message := range map[string]string{
+		`phases["mt-bench"] > 0`: "no such key: mt-bench",
+		`duration && true`:       "no such overload: double && _",
+		`name * 2`:               "no such overload: string * double",
+		`phases.sdg.x`:           "no such overload: double[string]",
+		`duration / 0`:           "division by zero",
+	} {
+		_, err := eval(source)
+		require.EqualError(t, err, message, source)
+	}
+
+	for source, message := range map[string]string{
+		"runtime < 10":     "undeclared reference to 'runtime' at offset 0",
+		"duration <":       `unexpected "end of expression" at offset 10`,
+		"(duration < 10":   `expected ")" at offset 14, found "end of expression"`,
+		"duration < 10 10": `unexpected "10" at offset 14`,
+		`phases["sdg]`:     "unterminated string at offset 7",
+		"duration # 2":     "unexpected character '#' at offset 9",
+		"duration ? 1":     `expected ":" at offset 12, found "end of expression"`,
+		"phases.'sdg'":     `expected a field name at offset 7, found "sdg"`,
+	} {
+		_, err := ParseExpression(source, variables)
+		require.EqualError(t, err, message, source)
+	}
+}
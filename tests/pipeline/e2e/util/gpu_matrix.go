@@ -0,0 +1,180 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// DefaultGPUMatrixSizes are the GPUs per node of the variants of the GPU matrix
+var DefaultGPUMatrixSizes = []int64{1, 2, 4, 8}
+
+// ParseGPUMatrixSizes reads comma-separated GPU counts, e.g. 1,2,4
+func ParseGPUMatrixSizes(value string) ([]int64, error) {
+	var sizes []int64
+	for _, field := range strings.Split(value, ",") {
+		size, err := strconv.ParseInt(strings.TrimSpace(field), 10, 64)
+		if err != nil || size < 1 {
+			return nil, fmt.Errorf("invalid GPU count '%s', expected a positive integer", field)
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes, nil
+}
+
+// FreeGPUsPerNode returns the GPUs of the resource left free on every node matching the node selector by the pods
+// which are not terminated
+func FreeGPUsPerNode(nodes []corev1.Node, pods []corev1.Pod, gpuResource string, nodeSelector map[string]string) map[string]int64 {
+	free := map[string]int64{}
+	selector := labels.SelectorFromSet(nodeSelector)
+	for _, node := range nodes {
+		gpus, ok := node.Status.Allocatable[corev1.ResourceName(gpuResource)]
+		if !ok || gpus.IsZero() || node.Spec.Unschedulable || !selector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+		free[node.Name] = gpus.Value()
+	}
+	var running []corev1.Pod
+	nodeOf := map[string]string{}
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		running = append(running, pod)
+		nodeOf[pod.Name] = pod.Spec.NodeName
+	}
+	for pod, gpus := range PodGPURequests(running, gpuResource) {
+		if _, ok := free[nodeOf[pod]]; ok {
+			free[nodeOf[pod]] -= gpus
+		}
+	}
+	return free
+}
+
+// GPUMatrixVariant is a run of the GPU matrix training on a single worker with GPUs processes on one node
+type GPUMatrixVariant struct {
+	GPUs int64
+	// Node is a node with the GPUs free, the one with the fewest free GPUs
+	Node string
+	// Skip explains why the variant does not fit in the cluster
+	Skip string
+}
+
+// GPUMatrixVariants plans the variants of the sizes given the free GPUs per node, skipping the sizes no node can hold
+func GPUMatrixVariants(freePerNode map[string]int64, sizes []int64) []GPUMatrixVariant {
+	nodes := make([]string, 0, len(freePerNode))
+	for node := range freePerNode {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if freePerNode[nodes[i]] != freePerNode[nodes[j]] {
+			return freePerNode[nodes[i]] < freePerNode[nodes[j]]
+		}
+		return nodes[i] < nodes[j]
+	})
+
+	var most int64
+	for _, node := range nodes {
+		if freePerNode[node] > most {
+			most = freePerNode[node]
+		}
+	}
+	variants := make([]GPUMatrixVariant, 0, len(sizes))
+	for _, size := range sizes {
+		variant := GPUMatrixVariant{GPUs: size, Skip: fmt.Sprintf("no node has %d free GPUs, %d at most", size, most)}
+		for _, node := range nodes {
+			if freePerNode[node] >= size {
+				variant.Node, variant.Skip = node, ""
+				break
+			}
+		}
+		variants = append(variants, variant)
+	}
+	return variants
+}
+
+// CheckNprocPerNode verifies a PyTorchJob was set up with nprocPerNode processes per node and its pods were scheduled
+// with as many GPUs: the nprocPerNode of the job, the NPROC_PER_NODE passed by the pipeline and the PET_NPROC_PER_NODE
+// set by the Training Operator, and the GPU requests of the pods
+func CheckNprocPerNode(job *unstructured.Unstructured, pods []corev1.Pod, gpuResource string, nprocPerNode int64) []string {
+	var problems []string
+	expected := strconv.FormatInt(nprocPerNode, 10)
+	// nprocPerNode is a string in kubeflow.org/v1, an integer in older PyTorchJobs
+	value, found, _ := unstructured.NestedFieldNoCopy(job.Object, "spec", "nprocPerNode")
+	if actual := fmt.Sprint(value); !found || actual != expected {
+		problems = append(problems, fmt.Sprintf("PyTorchJob %s has nprocPerNode %v, expected %s", job.GetName(), value, expected))
+	}
+
+	var jobPods []corev1.Pod
+	for _, pod := range pods {
+		if pod.Labels[TrainingJobNameLabel] == job.GetName() {
+			jobPods = append(jobPods, pod)
+		}
+	}
+	if len(jobPods) == 0 {
+		return append(problems, fmt.Sprintf("no pods found for PyTorchJob %s", job.GetName()))
+	}
+	requests := PodGPURequests(jobPods, gpuResource)
+	for _, pod := range jobPods {
+		if pod.Spec.NodeName == "" {
+			problems = append(problems, fmt.Sprintf("pod %s of PyTorchJob %s was not scheduled", pod.Name, job.GetName()))
+		}
+		if requests[pod.Name] != nprocPerNode {
+			problems = append(problems, fmt.Sprintf("pod %s requested %d GPUs of %s, expected %d", pod.Name, requests[pod.Name], gpuResource, nprocPerNode))
+		}
+		for _, container := range pod.Spec.Containers {
+			for _, env := range container.Env {
+				if (env.Name == "NPROC_PER_NODE" || env.Name == "PET_NPROC_PER_NODE") && env.Value != expected {
+					problems = append(problems, fmt.Sprintf("pod %s has %s=%s, expected %s", pod.Name, env.Name, env.Value, expected))
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// GPUMatrixRun is the outcome of a variant of the GPU matrix
+type GPUMatrixRun struct {
+	GPUMatrixVariant
+	Passed bool
+	// Training is how long the PyTorchJobs of the run trained
+	Training time.Duration
+}
+
+// RenderGPUMatrix renders the variants of the GPU matrix as a Markdown report
+func RenderGPUMatrix(runs []GPUMatrixRun) string {
+	var report strings.Builder
+	report.WriteString("# GPU matrix\n\n")
+	report.WriteString("| GPUs per node | Node | Result | Training |\n")
+	report.WriteString("|---|---|---|---|\n")
+	for _, run := range runs {
+		result := "failed"
+		switch {
+		case run.Skip != "":
+			result = "skipped: " + run.Skip
+		case run.Passed:
+			result = "passed"
+		}
+		fmt.Fprintf(&report, "| %d | %s | %s | %s |\n", run.GPUs, run.Node, result, formatDuration(run.Training))
+	}
+	return report.String()
+}
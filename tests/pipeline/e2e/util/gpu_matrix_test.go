@@ -0,0 +1,101 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestGPUMatrixVariants(t *testing.T) {
+	node := func(name, gpus string, labels map[string]string) corev1.Node {
+		node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+		node.Status.Allocatable = corev1.ResourceList{DefaultGPUResource: resource.MustParse(gpus)}
+		return node
+	}
+	pod := func(name, node string, gpus string, phase corev1.PodPhase) corev1.Pod {
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: corev1.PodSpec{NodeName: node}}
+		pod.Spec.Containers = []corev1.Container{{Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{DefaultGPUResource: resource.MustParse(gpus)}}}}
+		pod.Status.Phase = phase
+		return pod
+	}
+	nodes := []corev1.Node{
+		node("a100", "8", map[string]string{"gpu": "a100"}),
+		node("l4", "2", map[string]string{"gpu": "l4"}),
+		node("cpu", "0", nil),
+	}
+	pods := []corev1.Pod{
+		pod("judge", "a100", "1", corev1.PodRunning),
+		pod("done", "a100", "4", corev1.PodSucceeded),
+	}
+	free := FreeGPUsPerNode(nodes, pods, DefaultGPUResource, nil)
+	require.Equal(t, map[string]int64{"a100": 7, "l4": 2}, free)
+	require.Equal(t, map[string]int64{"l4": 2}, FreeGPUsPerNode(nodes, pods, DefaultGPUResource, map[string]string{"gpu": "l4"}))
+
+	// Every variant goes to the node with the fewest free GPUs holding it
+	require.Equal(t, []GPUMatrixVariant{
+		{GPUs: 1, Node: "l4"},
+		{GPUs: 2, Node: "l4"},
+		{GPUs: 4, Node: "a100"},
+		{GPUs: 8, Skip: "no node has 8 free GPUs, 7 at most"},
+	}, GPUMatrixVariants(free, DefaultGPUMatrixSizes))
+
+	sizes, err := ParseGPUMatrixSizes("1, 2,4")
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2, 4}, sizes)
+	_, err = ParseGPUMatrixSizes("1,0")
+	require.EqualError(t, err, "invalid GPU count '0', expected a positive integer")
+
+	report := RenderGPUMatrix([]GPUMatrixRun{
+		{GPUMatrixVariant: GPUMatrixVariant{GPUs: 2, Node: "l4"}, Passed: true, Training: 90 * time.Minute},
+		{GPUMatrixVariant: GPUMatrixVariant{GPUs: 4, Node: "a100"}},
+		{GPUMatrixVariant: GPUMatrixVariant{GPUs: 8, Skip: "no node has 8 free GPUs, 7 at most"}, Passed: true},
+	})
+	require.Contains(t, report, "| 2 | l4 | passed | 1h30m0s |\n| 4 | a100 | failed | n/a |\n| 8 |  | skipped: no node has 8 free GPUs, 7 at most | n/a |\n")
+}
+
+func TestCheckNprocPerNode(t *testing.T) {
+	job := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "train-phase-1"},
+		"spec":     map[string]interface{}{"nprocPerNode": "4"},
+	}}
+	pod := func(name, gpus, nproc, node string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{TrainingJobNameLabel: "train-phase-1"}},
+			Spec: corev1.PodSpec{NodeName: node, Containers: []corev1.Container{{
+				Name:      "pytorch",
+				Env:       []corev1.EnvVar{{Name: "NPROC_PER_NODE", Value: "4"}, {Name: "PET_NPROC_PER_NODE", Value: nproc}},
+				Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{DefaultGPUResource: resource.MustParse(gpus)}},
+			}}},
+		}
+	}
+	other := pod("other-master-0", "1", "1", "n2")
+	other.Labels[TrainingJobNameLabel] = "other"
+	require.Empty(t, CheckNprocPerNode(job, []corev1.Pod{pod("train-phase-1-master-0", "4", "4", "n1"), other}, DefaultGPUResource, 4))
+
+	job.Object["spec"] = map[string]interface{}{"nprocPerNode": int64(2)}
+	require.Equal(t, []string{
+		"PyTorchJob train-phase-1 has nprocPerNode 2, expected 4",
+		"pod train-phase-1-master-0 of PyTorchJob train-phase-1 was not scheduled",
+		"pod train-phase-1-master-0 requested 2 GPUs of nvidia.com/gpu, expected 4",
+		"pod train-phase-1-master-0 has PET_NPROC_PER_NODE=2, expected 4",
+	}, CheckNprocPerNode(job, []corev1.Pod{pod("train-phase-1-master-0", "2", "2", "")}, DefaultGPUResource, 4))
+	require.Equal(t, []string{"no pods found for PyTorchJob train-phase-1"}, CheckNprocPerNode(job, nil, DefaultGPUResource, 2))
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// GPUCountLabel is set by the GPU feature discovery to the number of physical GPUs of a node
+	GPUCountLabel = "nvidia.com/gpu.count"
+	// GPUSharingStrategyLabel is set by the GPU feature discovery to "time-slicing" when the GPUs of a node are shared
+	GPUSharingStrategyLabel = "nvidia.com/gpu.sharing-strategy"
+)
+
+// GPUSharing is the capacity of the cluster in a GPU resource. With time-slicing, Allocatable counts the slices of
+// the Physical GPUs.
+type GPUSharing struct {
+	Resource    string
+	Physical    int64
+	Allocatable int64
+	// Requested is held by the running pods, e.g. an in-cluster judge
+	Requested   int64
+	TimeSlicing bool
+}
+
+// Free returns the GPUs the pods of a run can still be scheduled on
+func (s GPUSharing) Free() int64 {
+	return s.Allocatable - s.Requested
+}
+
+// GetGPUSharing measures the capacity of the cluster in a GPU resource, from the allocatable GPUs of the nodes and
+// the requests of the pods which are not terminated
+func GetGPUSharing(client kubernetes.Interface, gpuResource string) (GPUSharing, error) {
+	sharing := GPUSharing{Resource: gpuResource}
+	nodes, err := client.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return GPUSharing{}, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	for _, node := range nodes.Items {
+		gpus, ok := node.Status.Allocatable[corev1.ResourceName(gpuResource)]
+		if !ok || gpus.IsZero() {
+			continue
+		}
+		sharing.Allocatable += gpus.Value()
+		if count, err := strconv.ParseInt(node.Labels[GPUCountLabel], 10, 64); err == nil {
+			sharing.Physical += count
+		}
+		if node.Labels[GPUSharingStrategyLabel] == "time-slicing" {
+			sharing.TimeSlicing = true
+		}
+	}
+
+	pods, err := client.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{
+		FieldSelector: "status.phase!=Succeeded,status.phase!=Failed",
+	})
+	if err != nil {
+		return GPUSharing{}, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for _, gpus := range PodGPURequests(pods.Items, gpuResource) {
+		sharing.Requested += gpus
+	}
+	return sharing, nil
+}
+
+// GPURequest is the GPUs the pods of a phase of a run request at once
+type GPURequest struct {
+	Phase    string
+	Resource string
+	Pods     int64
+	PerPod   int64
+}
+
+// GPUs returns the GPUs the phase holds while it runs
+func (r GPURequest) GPUs() int64 {
+	return r.Pods * r.PerPod
+}
+
+// RunGPURequests returns the GPUs the training and eval phases request with the pipeline parameters. Every training
+// worker requests train_gpu_per_worker GPUs, and the eval tasks run vLLM on a single GPU.
+func RunGPURequests(params map[string]interface{}) []GPURequest {
+	trainResource, _ := TrainingPlacement(params)
+	evalResource := DefaultGPUResource
+	if identifier, ok := params["eval_gpu_identifier"].(string); ok && identifier != "" {
+		evalResource = identifier
+	}
+	perWorker, _ := jsonNumber(params["train_gpu_per_worker"])
+	workers, _ := jsonNumber(params["train_num_workers"])
+	return []GPURequest{
+		{Phase: "training", Resource: trainResource, Pods: int64(workers), PerPod: int64(perWorker)},
+		{Phase: "mt-bench", Resource: evalResource, Pods: 1, PerPod: 1},
+		{Phase: "final-eval", Resource: evalResource, Pods: 1, PerPod: 1},
+	}
+}
+
+// CheckGPUFit returns the phases whose GPUs do not fit in the free capacity of their resource. The phases of a run do
+// not overlap, so each of them must fit alongside the pods already holding GPUs.
+func CheckGPUFit(capacity map[string]GPUSharing, requests []GPURequest) []string {
+	var problems []string
+	for _, request := range requests {
+		sharing, ok := capacity[request.Resource]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s requests %d GPUs of %s, which no node provides", request.Phase, request.GPUs(), request.Resource))
+			continue
+		}
+		if request.GPUs() > sharing.Free() {
+			problems = append(problems, fmt.Sprintf("%s requests %d GPUs of %s, only %d of the %d allocatable are free", request.Phase, request.GPUs(), request.Resource, sharing.Free(), sharing.Allocatable))
+		}
+	}
+	return problems
+}
+
+// CheckRunGPURequests verifies every training pod and eval task pod of a run requested the GPUs of the plan, so a run
+// fitting the cluster did so with the planned requests
+func CheckRunGPURequests(requests []GPURequest, trainingPods []corev1.Pod, tasks []TaskPod) []string {
+	functions := map[string]string{"mt-bench": "run_mt_bench_op", "final-eval": "run_final_eval_op"}
+	var problems []string
+	for _, request := range requests {
+		var pods []corev1.Pod
+		if request.Phase == "training" {
+			pods = trainingPods
+		} else {
+			for _, task := range tasks {
+				if task.Function == functions[request.Phase] {
+					pods = append(pods, task.Pod)
+				}
+			}
+		}
+		for pod, gpus := range PodGPURequests(pods, request.Resource) {
+			if gpus != request.PerPod {
+				problems = append(problems, fmt.Sprintf("%s pod %s requested %d GPUs of %s, the plan was %d", request.Phase, pod, gpus, request.Resource, request.PerPod))
+			}
+		}
+	}
+	return problems
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func gpuPod(name string, gpus string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ilab"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "main", Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{DefaultGPUResource: resource.MustParse(gpus)},
+		}}}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestGetGPUSharing(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu-node", Labels: map[string]string{GPUCountLabel: "2", GPUSharingStrategyLabel: "time-slicing"}},
+		Status:     corev1.NodeStatus{Allocatable: corev1.ResourceList{DefaultGPUResource: resource.MustParse("4")}},
+	}
+	cpuNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "cpu-node"}}
+	sharing, err := GetGPUSharing(fake.NewSimpleClientset(node, cpuNode, gpuPod("judge", "1")), DefaultGPUResource)
+	require.NoError(t, err)
+	require.Equal(t, GPUSharing{Resource: DefaultGPUResource, Physical: 2, Allocatable: 4, Requested: 1, TimeSlicing: true}, sharing)
+	require.Equal(t, int64(3), sharing.Free())
+}
+
+func TestCheckGPUFit(t *testing.T) {
+	requests := RunGPURequests(map[string]interface{}{"train_gpu_per_worker": 1, "train_num_workers": float64(2)})
+	require.Equal(t, []GPURequest{
+		{Phase: "training", Resource: DefaultGPUResource, Pods: 2, PerPod: 1},
+		{Phase: "mt-bench", Resource: DefaultGPUResource, Pods: 1, PerPod: 1},
+		{Phase: "final-eval", Resource: DefaultGPUResource, Pods: 1, PerPod: 1},
+	}, requests)
+
+	// Two GPUs sliced in two with the judge holding one slice
+	capacity := map[string]GPUSharing{DefaultGPUResource: {Resource: DefaultGPUResource, Physical: 2, Allocatable: 4, Requested: 1}}
+	require.Empty(t, CheckGPUFit(capacity, requests))
+
+	// Without time-slicing the judge leaves a single GPU
+	capacity[DefaultGPUResource] = GPUSharing{Resource: DefaultGPUResource, Physical: 2, Allocatable: 2, Requested: 1}
+	require.Equal(t, []string{"training requests 2 GPUs of nvidia.com/gpu, only 1 of the 2 allocatable are free"}, CheckGPUFit(capacity, requests))
+
+	requests[1].Resource = "nvidia.com/gpu.shared"
+	require.Contains(t, CheckGPUFit(capacity, requests), "mt-bench requests 1 GPUs of nvidia.com/gpu.shared, which no node provides")
+}
+
+func TestCheckRunGPURequests(t *testing.T) {
+	requests := []GPURequest{
+		{Phase: "training", Resource: DefaultGPUResource, Pods: 2, PerPod: 1},
+		{Phase: "mt-bench", Resource: DefaultGPUResource, Pods: 1, PerPod: 1},
+	}
+	trainingPods := []corev1.Pod{*gpuPod("train-master", "1"), *gpuPod("train-worker", "2")}
+	tasks := []TaskPod{{Pod: *gpuPod("mt-bench", "1"), Function: "run_mt_bench_op"}}
+	require.Equal(t, []string{"training pod train-worker requested 2 GPUs of nvidia.com/gpu, the plan was 1"}, CheckRunGPURequests(requests, trainingPods, tasks))
+}
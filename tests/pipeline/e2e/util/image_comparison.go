@@ -0,0 +1,232 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	mtBenchOverallPattern = regexp.MustCompile(`(?m)^## MODEL \(SCORE\)\s*\n.*\((-?[0-9.]+)/10\.0\)`)
+	mtBenchTurnPattern    = regexp.MustCompile(`(?m)^### TURN (ONE|TWO) \(0\.0 to 10\.0\):\s*\n\s*(-?[0-9.]+)`)
+)
+
+// MTBenchScores are the scores out of 10 of the MT-Bench report of `ilab model evaluate`
+type MTBenchScores struct {
+	Overall float64
+	TurnOne float64
+	TurnTwo float64
+}
+
+// ParseMTBenchScores reads the scores of the MT-Bench report `ilab model evaluate --benchmark mt_bench` logs
+func ParseMTBenchScores(log string) (MTBenchScores, error) {
+	var scores MTBenchScores
+	match := mtBenchOverallPattern.FindStringSubmatch(log)
+	if match == nil {
+		return scores, fmt.Errorf("no MT-Bench model score in the log")
+	}
+	overall, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return scores, fmt.Errorf("invalid MT-Bench model score %s: %w", match[1], err)
+	}
+	scores.Overall = overall
+
+	turns := map[string]*float64{"ONE": &scores.TurnOne, "TWO": &scores.TurnTwo}
+	for _, match := range mtBenchTurnPattern.FindAllStringSubmatch(log, -1) {
+		score, err := strconv.ParseFloat(match[2], 64)
+		if err != nil {
+			return scores, fmt.Errorf("invalid MT-Bench turn %s score %s: %w", strings.ToLower(match[1]), match[2], err)
+		}
+		*turns[match[1]] = score
+	}
+	return scores, nil
+}
+
+// ListWorkflowTaskPods returns the pods of the tasks of a workflow run, those with the run ID and a task label
+func ListWorkflowTaskPods(client kubernetes.Interface, namespace, runID string) ([]corev1.Pod, error) {
+	pods, err := client.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s", RunIDLabel, runID, WorkflowTaskLabel),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the task pods of run %s: %w", runID, err)
+	}
+	return pods.Items, nil
+}
+
+// WorkflowTaskContainer returns the container of a task pod running the task script: the main container of the pods
+// of Argo, which run a wait sidecar, the only container otherwise
+func WorkflowTaskContainer(pod corev1.Pod) string {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == "main" {
+			return container.Name
+		}
+	}
+	if len(pod.Spec.Containers) == 0 {
+		return ""
+	}
+	return pod.Spec.Containers[0].Name
+}
+
+// WorkflowTaskDurations returns how long the pod of each task ran, from its start to the end of its last container.
+// The pods not finished yet are left out.
+func WorkflowTaskDurations(pods []corev1.Pod) map[string]time.Duration {
+	durations := map[string]time.Duration{}
+	for _, pod := range pods {
+		task := pod.Labels[WorkflowTaskLabel]
+		if task == "" || pod.Status.StartTime == nil {
+			continue
+		}
+		var finished time.Time
+		for _, status := range pod.Status.ContainerStatuses {
+			if terminated := status.State.Terminated; terminated != nil && terminated.FinishedAt.After(finished) {
+				finished = terminated.FinishedAt.Time
+			}
+		}
+		if finished.IsZero() {
+			continue
+		}
+		durations[task] = finished.Sub(pod.Status.StartTime.Time)
+	}
+	return durations
+}
+
+// ComparisonRun is the run of the workflow with one training image candidate of an image comparison
+type ComparisonRun struct {
+	Candidate     string
+	Namespace     string
+	TrainingImage string
+	Duration      time.Duration
+	Tasks         map[string]time.Duration
+	// Scores is nil when the run has no MT-Bench report
+	Scores *MTBenchScores
+	Usage  ResourceUsage
+	Err    error
+}
+
+// RenderImageComparison renders the runs of two training image candidates side by side as a Markdown report, with
+// the difference of the second to the first
+func RenderImageComparison(workflow Workflow, a, b ComparisonRun) string {
+	var report strings.Builder
+	report.WriteString("# Training image comparison\n\n")
+	fmt.Fprintf(&report, "| | %s | %s | Delta |\n|---|---|---|---|\n", a.Candidate, b.Candidate)
+	fmt.Fprintf(&report, "| Training image | %s | %s | |\n", a.TrainingImage, b.TrainingImage)
+	fmt.Fprintf(&report, "| Namespace | %s | %s | |\n", a.Namespace, b.Namespace)
+	fmt.Fprintf(&report, "| Result | %s | %s | |\n", comparisonResult(a), comparisonResult(b))
+	writeDurationRow(&report, "Duration", a.Duration, b.Duration)
+
+	report.WriteString("\n## MT-Bench scores\n\n")
+	fmt.Fprintf(&report, "| Score | %s | %s | Delta |\n|---|---|---|---|\n", a.Candidate, b.Candidate)
+	scores := []struct {
+		name  string
+		score func(MTBenchScores) float64
+	}{
+		{"Overall", func(s MTBenchScores) float64 { return s.Overall }},
+		{"Turn one", func(s MTBenchScores) float64 { return s.TurnOne }},
+		{"Turn two", func(s MTBenchScores) float64 { return s.TurnTwo }},
+	}
+	for _, score := range scores {
+		if a.Scores == nil || b.Scores == nil {
+			fmt.Fprintf(&report, "| %s | %s | %s | |\n", score.name, formatScore(a.Scores, score.score), formatScore(b.Scores, score.score))
+			continue
+		}
+		sa, sb := score.score(*a.Scores), score.score(*b.Scores)
+		fmt.Fprintf(&report, "| %s | %.2f | %.2f | %+.2f |\n", score.name, sa, sb, sb-sa)
+	}
+
+	report.WriteString("\n## Task durations\n\n")
+	fmt.Fprintf(&report, "| Task | %s | %s | Delta |\n|---|---|---|---|\n", a.Candidate, b.Candidate)
+	for _, task := range workflow.Tasks {
+		writeDurationRow(&report, task.Name, a.Tasks[task.Name], b.Tasks[task.Name])
+	}
+
+	const gib = 1 << 30
+	report.WriteString("\n## Resource usage\n\n")
+	fmt.Fprintf(&report, "| Phase | Metric | %s | %s | Delta |\n|---|---|---|---|---|\n", a.Candidate, b.Candidate)
+	metrics := []struct {
+		name  string
+		value func(*PhaseUsage) float64
+	}{
+		{"Peak CPU (cores)", func(u *PhaseUsage) float64 { return float64(u.PeakCPUMillis) / 1000 }},
+		{"Peak memory (GiB)", func(u *PhaseUsage) float64 { return float64(u.PeakMemoryBytes) / gib }},
+		{"CPU (core-hours)", func(u *PhaseUsage) float64 { return u.CPUCoreSeconds / 3600 }},
+		{"Memory (GiB-hours)", func(u *PhaseUsage) float64 { return u.MemoryByteSeconds / gib / 3600 }},
+	}
+	for _, phase := range PipelinePhases {
+		ua, ub := a.Usage[phase], b.Usage[phase]
+		if ua == nil && ub == nil {
+			continue
+		}
+		for _, metric := range metrics {
+			if ua == nil || ub == nil {
+				fmt.Fprintf(&report, "| %s | %s | %s | %s | |\n", phase, metric.name, formatUsage(ua, metric.value), formatUsage(ub, metric.value))
+				continue
+			}
+			va, vb := metric.value(ua), metric.value(ub)
+			fmt.Fprintf(&report, "| %s | %s | %.3f | %.3f | %+.3f |\n", phase, metric.name, va, vb, vb-va)
+		}
+	}
+	return report.String()
+}
+
+func comparisonResult(run ComparisonRun) string {
+	if run.Err != nil {
+		return "failed: " + strings.ReplaceAll(run.Err.Error(), "\n", " ")
+	}
+	return "succeeded"
+}
+
+// writeDurationRow writes a row of durations, a zero duration is not measured
+func writeDurationRow(report *strings.Builder, name string, a, b time.Duration) {
+	if a == 0 || b == 0 {
+		fmt.Fprintf(report, "| %s | %s | %s | |\n", name, formatDuration(a), formatDuration(b))
+		return
+	}
+	delta := (b - a).Round(time.Second)
+	sign := "+"
+	if delta < 0 {
+		sign = "-"
+		delta = -delta
+	}
+	fmt.Fprintf(report, "| %s | %s | %s | %s%s |\n", name, formatDuration(a), formatDuration(b), sign, delta)
+}
+
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return "n/a"
+	}
+	return d.Round(time.Second).String()
+}
+
+func formatScore(scores *MTBenchScores, score func(MTBenchScores) float64) string {
+	if scores == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.2f", score(*scores))
+}
+
+func formatUsage(usage *PhaseUsage, value func(*PhaseUsage) float64) string {
+	if usage == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%.3f", value(usage))
+}
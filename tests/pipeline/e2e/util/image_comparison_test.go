@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const mtBenchLog = `INFO 2025-03-01 12:00:00 Generating answers...
+# SKILL EVALUATION REPORT
+
+## MODEL (SCORE)
+/data/checkpoints/hf_format/samples_1024 (6.8/10.0)
+
+### TURN ONE (0.0 to 10.0):
+7.03
+
+### TURN TWO (0.0 to 10.0):
+6.57
+`
+
+func TestParseMTBenchScores(t *testing.T) {
+	scores, err := ParseMTBenchScores(mtBenchLog)
+	require.NoError(t, err)
+	require.Equal(t, MTBenchScores{Overall: 6.8, TurnOne: 7.03, TurnTwo: 6.57}, scores)
+
+	_, err = ParseMTBenchScores("Generating answers...\nTraceback (most recent call last):")
+	require.Error(t, err)
+}
+
+func TestWorkflowTaskDurations(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	pod := func(name, task string, finished ...time.Duration) corev1.Pod {
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", Labels: map[string]string{RunIDLabel: "run-1", WorkflowTaskLabel: task}}}
+		pod.Status.StartTime = &metav1.Time{Time: start}
+		for _, d := range finished {
+			pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{FinishedAt: metav1.NewTime(start.Add(d))}},
+			})
+		}
+		return pod
+	}
+	other := pod("other", "sdg", time.Hour)
+	other.Labels[RunIDLabel] = "run-2"
+	client := fake.NewSimpleClientset(&other)
+	for _, p := range []corev1.Pod{pod("run-1-sdg", "sdg", time.Minute, 90*time.Minute), pod("run-1-train", "train"), pod("run-1-eval", "eval", 20*time.Minute)} {
+		p := p
+		_, err := client.CoreV1().Pods("ns").Create(context.Background(), &p, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	pods, err := ListWorkflowTaskPods(client, "ns", "run-1")
+	require.NoError(t, err)
+	require.Len(t, pods, 3)
+	require.Equal(t, map[string]time.Duration{"sdg": 90 * time.Minute, "eval": 20 * time.Minute}, WorkflowTaskDurations(pods))
+}
+
+func TestRenderImageComparison(t *testing.T) {
+	workflow, err := LoadWorkflow("../resources/workflow_tasks.yaml")
+	require.NoError(t, err)
+	a := ComparisonRun{
+		Candidate:     "current",
+		Namespace:     "ilab-a",
+		TrainingImage: "quay.io/ilab/training:1.4",
+		Duration:      3 * time.Hour,
+		Tasks:         map[string]time.Duration{"sdg": time.Hour, "train": 90 * time.Minute, "eval": 30 * time.Minute},
+		Scores:        &MTBenchScores{Overall: 6.5, TurnOne: 7, TurnTwo: 6},
+		Usage:         ResourceUsage{"training-phase-1": {PeakCPUMillis: 4000, CPUCoreSeconds: 7200, Pods: map[string]bool{"run-a-train": true}}},
+	}
+	b := ComparisonRun{
+		Candidate:     "candidate",
+		Namespace:     "ilab-b",
+		TrainingImage: "quay.io/ilab/training:1.5",
+		Duration:      2*time.Hour + 30*time.Minute,
+		Tasks:         map[string]time.Duration{"sdg": time.Hour, "train": time.Hour},
+		Usage:         ResourceUsage{"training-phase-1": {PeakCPUMillis: 6000, CPUCoreSeconds: 3600, Pods: map[string]bool{"run-b-train": true}}},
+		Err:           errors.New("task eval: pod failed"),
+	}
+
+	report := RenderImageComparison(workflow, a, b)
+	for _, row := range []string{
+		"| Training image | quay.io/ilab/training:1.4 | quay.io/ilab/training:1.5 | |",
+		"| Result | succeeded | failed: task eval: pod failed | |",
+		"| Duration | 3h0m0s | 2h30m0s | -30m0s |",
+		"| Overall | 6.50 | n/a | |",
+		"| train | 1h30m0s | 1h0m0s | -30m0s |",
+		"| eval | 30m0s | n/a | |",
+		"| training-phase-1 | Peak CPU (cores) | 4.000 | 6.000 | +2.000 |",
+		"| training-phase-1 | CPU (core-hours) | 2.000 | 1.000 | -1.000 |",
+	} {
+		require.Contains(t, report, row)
+	}
+	require.False(t, strings.Contains(report, "| sdg | Peak"), "phases without usage are left out")
+
+	b.Scores = &MTBenchScores{Overall: 7, TurnOne: 7.25, TurnTwo: 6.75}
+	require.Contains(t, RenderImageComparison(workflow, a, b), "| Overall | 6.50 | 7.00 | +0.50 |")
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ImageDigest is an image referenced by the pods of a run and the digest the kubelet resolved it to
+type ImageDigest struct {
+	// Image is the reference of the pod spec
+	Image string `json:"image"`
+	// Digest is the resolved digest, e.g. sha256:..., empty when no container of the image started
+	Digest string `json:"digest"`
+	// Pinned is true when the reference holds a digest, false for a mutable tag
+	Pinned bool     `json:"pinned"`
+	Pods   []string `json:"pods"`
+}
+
+// RunImageDigests returns the images of the containers of the pods, init containers included, with the digests
+// resolved in the container statuses, by image
+func RunImageDigests(pods []corev1.Pod) []ImageDigest {
+	digests := map[string]*ImageDigest{}
+	record := func(pod corev1.Pod, containers []corev1.Container, statuses []corev1.ContainerStatus) {
+		resolved := map[string]string{}
+		for _, status := range statuses {
+			resolved[status.Name] = imageIDDigest(status.ImageID)
+		}
+		for _, container := range containers {
+			digest, ok := digests[container.Image]
+			if !ok {
+				digest = &ImageDigest{Image: container.Image, Pinned: IsPinnedImage(container.Image)}
+				digests[container.Image] = digest
+			}
+			if resolved[container.Name] != "" {
+				digest.Digest = resolved[container.Name]
+			}
+			if len(digest.Pods) == 0 || digest.Pods[len(digest.Pods)-1] != pod.Name {
+				digest.Pods = append(digest.Pods, pod.Name)
+			}
+		}
+	}
+	for _, pod := range pods {
+		record(pod, pod.Spec.InitContainers, pod.Status.InitContainerStatuses)
+		record(pod, pod.Spec.Containers, pod.Status.ContainerStatuses)
+	}
+
+	result := make([]ImageDigest, 0, len(digests))
+	for _, digest := range digests {
+		result = append(result, *digest)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Image < result[j].Image })
+	return result
+}
+
+// IsPinnedImage reports whether an image reference holds a digest, so it cannot resolve to other content later
+func IsPinnedImage(image string) bool {
+	return strings.Contains(image, "@sha256:")
+}
+
+// imageIDDigest returns the digest of the image ID of a container status, e.g. docker-pullable://quay.io/x@sha256:...
+// or sha256:... depending on the container runtime
+func imageIDDigest(imageID string) string {
+	if index := strings.LastIndex(imageID, "@"); index >= 0 {
+		return imageID[index+1:]
+	}
+	if strings.HasPrefix(imageID, "sha256:") {
+		return imageID
+	}
+	return ""
+}
+
+// MutableImages returns the images referenced by a mutable tag
+func MutableImages(digests []ImageDigest) []ImageDigest {
+	var mutable []ImageDigest
+	for _, digest := range digests {
+		if !digest.Pinned {
+			mutable = append(mutable, digest)
+		}
+	}
+	return mutable
+}
+
+// RenderImageDigests renders the images of a run and their resolved digests as a Markdown report
+func RenderImageDigests(digests []ImageDigest) string {
+	var report strings.Builder
+	report.WriteString("# Image digests\n\n")
+	report.WriteString("| Image | Resolved digest | Pinned | Pods |\n")
+	report.WriteString("|---|---|---|---|\n")
+	for _, digest := range digests {
+		resolved := digest.Digest
+		if resolved == "" {
+			resolved = "unresolved"
+		}
+		fmt.Fprintf(&report, "| %s | %s | %t | %s |\n", digest.Image, resolved, digest.Pinned, strings.Join(digest.Pods, ", "))
+	}
+	return report.String()
+}
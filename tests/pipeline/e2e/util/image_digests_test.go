@@ -0,0 +1,63 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRunImageDigests(t *testing.T) {
+	const (
+		sdgDigest      = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+		launcherDigest = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+	)
+	pinned := "quay.io/opendatahub/launcher@" + launcherDigest
+	pods := []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "sdg-op"},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "kfp-launcher", Image: pinned}},
+				Containers:     []corev1.Container{{Name: "main", Image: "quay.io/opendatahub/sdg:latest"}},
+			},
+			Status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{{Name: "kfp-launcher", ImageID: pinned}},
+				ContainerStatuses:     []corev1.ContainerStatus{{Name: "main", ImageID: "docker-pullable://quay.io/opendatahub/sdg@" + sdgDigest}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "upload-model-op"},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "kfp-launcher", Image: pinned}},
+				Containers:     []corev1.Container{{Name: "main", Image: "quay.io/opendatahub/upload:1.0"}},
+			},
+			Status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{{Name: "kfp-launcher", ImageID: pinned}},
+			},
+		},
+	}
+
+	digests := RunImageDigests(pods)
+	require.Equal(t, []ImageDigest{
+		{Image: pinned, Digest: launcherDigest, Pinned: true, Pods: []string{"sdg-op", "upload-model-op"}},
+		{Image: "quay.io/opendatahub/sdg:latest", Digest: sdgDigest, Pods: []string{"sdg-op"}},
+		{Image: "quay.io/opendatahub/upload:1.0", Pods: []string{"upload-model-op"}},
+	}, digests)
+	require.Len(t, MutableImages(digests), 2)
+	require.Contains(t, RenderImageDigests(digests), "| quay.io/opendatahub/upload:1.0 | unresolved | false | upload-model-op |")
+	require.Equal(t, launcherDigest, imageIDDigest(launcherDigest))
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"sigs.k8s.io/yaml"
+)
+
+// InstructLabRunCRDPath is the CRD of the InstructLabRuns, from the e2e tests
+const InstructLabRunCRDPath = "../../config/crd/bases/ilab.opendatahub.io_instructlabruns.yaml"
+
+// InstallCRD creates the CustomResourceDefinition of the file unless it exists, and waits for it to be established. It
+// returns whether it created the CRD, for the caller to delete it at the end.
+func InstallCRD(t *testing.T, client dynamic.Interface, path string, timeout time.Duration) (bool, error) {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err, "Failed to read CRD %s", path)
+	crd := &unstructured.Unstructured{}
+	require.NoError(t, yaml.Unmarshal(data, &crd.Object), "Failed to parse CRD %s", path)
+
+	created := true
+	_, err = client.Resource(CustomResourceDefinitionGVR).Create(context.Background(), crd, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		created = false
+	} else if err != nil {
+		return false, fmt.Errorf("failed to create CRD '%s': %w", crd.GetName(), err)
+	}
+
+	deadline := time.After(timeout)
+	tick := time.Tick(2 * time.Second)
+	for {
+		if CheckCRDEstablished(t, client, crd.GetName()) == nil {
+			return created, nil
+		}
+		select {
+		case <-deadline:
+			return created, fmt.Errorf("CRD '%s' not established after %s", crd.GetName(), timeout)
+		case <-tick:
+		}
+	}
+}
+
+// DeleteCRD deletes the named CustomResourceDefinition, and with it all its resources
+func DeleteCRD(t *testing.T, client dynamic.Interface, name string) {
+	err := client.Resource(CustomResourceDefinitionGVR).Delete(context.Background(), name, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		t.Logf("Failed to delete CRD '%s': %v", name, err)
+	}
+}
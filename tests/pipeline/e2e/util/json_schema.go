@@ -0,0 +1,208 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+)
+
+// JSONSchema is the subset of JSON Schema used by the schemas of the test inputs: type, properties,
+// additionalProperties, required, enum, items, maxItems, minLength, pattern, minimum and maximum. Schemas using other
+// keywords are rejected, so a schema cannot silently outgrow the validator.
+type JSONSchema struct {
+	Schema               string                 `json:"$schema"`
+	Title                string                 `json:"title"`
+	Description          string                 `json:"description"`
+	Type                 string                 `json:"type"`
+	Properties           map[string]*JSONSchema `json:"properties"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Required             []string               `json:"required"`
+	Enum                 []interface{}          `json:"enum"`
+	Items                *JSONSchema            `json:"items"`
+	MaxItems             *int                   `json:"maxItems"`
+	MinLength            *int                   `json:"minLength"`
+	Pattern              string                 `json:"pattern"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+
+	pattern *regexp.Regexp
+}
+
+// LoadJSONSchema reads a JSON schema file
+func LoadJSONSchema(path string) (*JSONSchema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var schema JSONSchema
+	if err := decoder.Decode(&schema); err != nil {
+		return nil, fmt.Errorf("failed to parse schema %s: %w", path, err)
+	}
+	if err := schema.compile(); err != nil {
+		return nil, fmt.Errorf("invalid schema %s: %w", path, err)
+	}
+	return &schema, nil
+}
+
+// Validate returns the places where a value decoded from JSON or YAML breaks the schema
+func (s *JSONSchema) Validate(value interface{}) []string {
+	return s.validate("$", value)
+}
+
+func (s *JSONSchema) compile() error {
+	switch s.Type {
+	case "", "object", "array", "string", "boolean", "number", "integer", "null":
+	default:
+		return fmt.Errorf("unsupported type '%s'", s.Type)
+	}
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+		s.pattern = re
+	}
+	for _, property := range s.Properties {
+		if err := property.compile(); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		return s.Items.compile()
+	}
+	return nil
+}
+
+func (s *JSONSchema) validate(path string, value interface{}) []string {
+	if len(s.Enum) > 0 {
+		for _, allowed := range s.Enum {
+			if reflect.DeepEqual(normalizeJSONValue(allowed), normalizeJSONValue(value)) {
+				return nil
+			}
+		}
+		return []string{fmt.Sprintf("%s: %v is not one of %v", path, value, s.Enum)}
+	}
+	if s.Type != "" && !hasJSONType(value, s.Type) {
+		return []string{fmt.Sprintf("%s: expected %s, found %T", path, s.Type, value)}
+	}
+
+	var problems []string
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := value[name]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: %s is required", path, name))
+			}
+		}
+		names := make([]string, 0, len(value))
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					problems = append(problems, fmt.Sprintf("%s: unknown property %s", path, name))
+				}
+				continue
+			}
+			problems = append(problems, property.validate(path+"."+name, value[name])...)
+		}
+	case []interface{}:
+		if s.MaxItems != nil && len(value) > *s.MaxItems {
+			problems = append(problems, fmt.Sprintf("%s: %d items, at most %d allowed", path, len(value), *s.MaxItems))
+		}
+		if s.Items != nil {
+			for i, item := range value {
+				problems = append(problems, s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item)...)
+			}
+		}
+	case string:
+		if s.MinLength != nil && len(value) < *s.MinLength {
+			problems = append(problems, fmt.Sprintf("%s: '%s' is shorter than %d characters", path, value, *s.MinLength))
+		}
+		if s.pattern != nil && !s.pattern.MatchString(value) {
+			problems = append(problems, fmt.Sprintf("%s: '%s' does not match %s", path, value, s.Pattern))
+		}
+	default:
+		if number, ok := jsonNumber(value); ok {
+			if s.Minimum != nil && number < *s.Minimum {
+				problems = append(problems, fmt.Sprintf("%s: %v is less than %v", path, value, *s.Minimum))
+			}
+			if s.Maximum != nil && number > *s.Maximum {
+				problems = append(problems, fmt.Sprintf("%s: %v is greater than %v", path, value, *s.Maximum))
+			}
+		}
+	}
+	return problems
+}
+
+func hasJSONType(value interface{}, jsonType string) bool {
+	switch jsonType {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := jsonNumber(value)
+		return ok
+	case "integer":
+		number, ok := jsonNumber(value)
+		return ok && number == math.Trunc(number)
+	case "null":
+		return value == nil
+	}
+	return false
+}
+
+// jsonNumber converts the numbers decoded by encoding/json and yaml.v3 to float64
+func jsonNumber(value interface{}) (float64, bool) {
+	switch value := value.(type) {
+	case int:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	case uint64:
+		return float64(value), true
+	case float64:
+		return value, true
+	}
+	return 0, false
+}
+
+func normalizeJSONValue(value interface{}) interface{} {
+	if number, ok := jsonNumber(value); ok {
+		return number
+	}
+	return value
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONSchema(t *testing.T) {
+	dir := t.TempDir()
+	load := func(content string) (*JSONSchema, error) {
+		path := filepath.Join(dir, "schema.json")
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return LoadJSONSchema(path)
+	}
+
+	schema, err := load(`{"type": "object", "required": ["count"], "properties": {
+		"count": {"type": "integer", "minimum": 1},
+		"ratio": {"type": "number", "maximum": 1},
+		"tags": {"type": "array", "items": {"type": "string", "pattern": "^[a-z]+$"}},
+		"enabled": {"type": "boolean"}}}`)
+	require.NoError(t, err)
+	require.Empty(t, schema.Validate(map[string]interface{}{"count": 2, "ratio": 0.5, "tags": []interface{}{"sdg"}, "enabled": true, "other": 1}))
+	require.Equal(t, []string{
+		"$: count is required",
+		"$.ratio: expected number, found string",
+		"$.tags[1]: 'SDG' does not match ^[a-z]+$",
+	}, schema.Validate(map[string]interface{}{"ratio": "high", "tags": []interface{}{"sdg", "SDG"}}))
+	require.Equal(t, []string{"$.count: expected integer, found float64"}, schema.Validate(map[string]interface{}{"count": 1.5}))
+	require.Equal(t, []string{"$: expected object, found []interface {}"}, schema.Validate([]interface{}{}))
+
+	_, err = load(`{"type": "object", "oneOf": []}`)
+	require.ErrorContains(t, err, `unknown field "oneOf"`)
+	_, err = load(`{"type": "tuple"}`)
+	require.ErrorContains(t, err, "unsupported type 'tuple'")
+	_, err = load(`{"properties": {"name": {"pattern": "("}}}`)
+	require.ErrorContains(t, err, "invalid pattern")
+}
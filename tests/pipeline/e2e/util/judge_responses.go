@@ -0,0 +1,235 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/recorder"
+)
+
+// Categories of a recorded judge response
+const (
+	JudgeResponseValid     = "valid"
+	JudgeResponseMalformed = "malformed"
+	JudgeResponseRefusal   = "refusal"
+	JudgeResponseError     = "error"
+	// JudgeResponseUnparsed is a response recorded truncated, which cannot be validated
+	JudgeResponseUnparsed = "unparsed"
+)
+
+// judgeResponseCategories is the order of the categories in the report
+var judgeResponseCategories = []string{JudgeResponseValid, JudgeResponseMalformed, JudgeResponseRefusal, JudgeResponseError, JudgeResponseUnparsed}
+
+// maxJudgeResponseExamples bounds the flagged responses quoted in the report
+const maxJudgeResponseExamples = 10
+
+var (
+	// The rating patterns of the single answer grading prompts of MT-Bench, the judgment must end with "[[rating]]"
+	judgeRatingPattern       = regexp.MustCompile(`\[\[(\d+\.?\d*)\]\]`)
+	judgeRatingBackupPattern = regexp.MustCompile(`\[(\d+\.?\d*)\]`)
+	judgeRefusalPhrases      = []string{"i'm sorry", "i am sorry", "i cannot", "i can't", "i apologize", "as an ai"}
+)
+
+// JudgeResponseIssue is a judge response flagged as malformed, a refusal or an error
+type JudgeResponseIssue struct {
+	Category string
+	Reason   string
+	Excerpt  string
+}
+
+// JudgeResponseReport counts the recorded judge responses by category and quotes the first flagged ones
+type JudgeResponseReport struct {
+	Counts map[string]int
+	Issues []JudgeResponseIssue
+}
+
+// Total returns the number of validated responses
+func (r JudgeResponseReport) Total() int {
+	total := 0
+	for _, count := range r.Counts {
+		total += count
+	}
+	return total
+}
+
+// Flagged returns the number of malformed and refusal responses, which MT-Bench scores without a rating
+func (r JudgeResponseReport) Flagged() int {
+	return r.Counts[JudgeResponseMalformed] + r.Counts[JudgeResponseRefusal]
+}
+
+// ValidateJudgeResponses checks the completions recorded by the recording proxy of the judge, as JSON lines, conform
+// to the MT-Bench scoring schema: a judgment ending with a rating between 1 and 10 in double brackets
+func ValidateJudgeResponses(recordings []byte) (JudgeResponseReport, error) {
+	report := JudgeResponseReport{Counts: map[string]int{}}
+	scanner := bufio.NewScanner(bytes.NewReader(recordings))
+	scanner.Buffer(nil, 4*recorder.DefaultMaxBodyBytes)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var exchange recorder.Exchange
+		if err := json.Unmarshal(scanner.Bytes(), &exchange); err != nil {
+			return report, fmt.Errorf("invalid recording: %w", err)
+		}
+		if !strings.HasSuffix(exchange.Path, "/completions") {
+			continue
+		}
+
+		category, reason, excerpt := classifyJudgeExchange(exchange)
+		report.Counts[category]++
+		if category != JudgeResponseValid && category != JudgeResponseUnparsed && len(report.Issues) < maxJudgeResponseExamples {
+			report.Issues = append(report.Issues, JudgeResponseIssue{Category: category, Reason: reason, Excerpt: excerpt})
+		}
+	}
+	return report, scanner.Err()
+}
+
+// classifyJudgeExchange returns the category of a recorded judge exchange, why it is flagged and an excerpt of the
+// response
+func classifyJudgeExchange(exchange recorder.Exchange) (string, string, string) {
+	if exchange.Status < 200 || exchange.Status >= 300 {
+		return JudgeResponseError, fmt.Sprintf("status %d", exchange.Status), judgeExcerpt(string(exchange.Response))
+	}
+
+	var response struct {
+		Choices []struct {
+			Text    *string `json:"text"`
+			Message *struct {
+				Content *string `json:"content"`
+			} `json:"message"`
+			FinishReason string `json:"finish_reason"`
+		} `json:"choices"`
+	}
+	// Truncated and streamed payloads are recorded as strings
+	var text string
+	if json.Unmarshal(exchange.Response, &text) == nil {
+		switch {
+		case strings.HasSuffix(text, "...(truncated)"):
+			return JudgeResponseUnparsed, "", ""
+		case strings.Contains(text, "data:"):
+			judgment := streamedJudgment(text)
+			category, reason := ClassifyJudgment(judgment)
+			return category, reason, judgeExcerpt(judgment)
+		}
+		return JudgeResponseMalformed, "the response is not a JSON object", judgeExcerpt(text)
+	}
+	if err := json.Unmarshal(exchange.Response, &response); err != nil {
+		return JudgeResponseMalformed, "the response is not a JSON object", judgeExcerpt(string(exchange.Response))
+	}
+	if len(response.Choices) == 0 {
+		return JudgeResponseMalformed, "the response has no choices", judgeExcerpt(string(exchange.Response))
+	}
+
+	choice := response.Choices[0]
+	var content *string
+	if choice.Message != nil {
+		content = choice.Message.Content
+	} else {
+		content = choice.Text
+	}
+	if content == nil {
+		return JudgeResponseMalformed, "the choice has no content", judgeExcerpt(string(exchange.Response))
+	}
+	category, reason := ClassifyJudgment(*content)
+	if category == JudgeResponseMalformed && choice.FinishReason == "length" {
+		reason += ", the judgment was cut at the token limit"
+	}
+	return category, reason, judgeExcerpt(*content)
+}
+
+// ClassifyJudgment returns the category of the text of a judgment, and why it is flagged
+func ClassifyJudgment(judgment string) (string, string) {
+	match := judgeRatingPattern.FindStringSubmatch(judgment)
+	if match == nil {
+		match = judgeRatingBackupPattern.FindStringSubmatch(judgment)
+	}
+	if match == nil {
+		lower := strings.ToLower(judgment)
+		for _, phrase := range judgeRefusalPhrases {
+			if strings.Contains(lower, phrase) {
+				return JudgeResponseRefusal, fmt.Sprintf("no rating, the judge answered '%s'", phrase)
+			}
+		}
+		if strings.TrimSpace(judgment) == "" {
+			return JudgeResponseMalformed, "empty judgment"
+		}
+		return JudgeResponseMalformed, "no rating in [[rating]] format"
+	}
+	rating, err := strconv.ParseFloat(match[1], 64)
+	if err != nil || rating < 1 || rating > 10 {
+		return JudgeResponseMalformed, fmt.Sprintf("rating %s is outside 1-10", match[1])
+	}
+	return JudgeResponseValid, ""
+}
+
+// streamedJudgment concatenates the content of the chunks of a streamed completion
+func streamedJudgment(stream string) string {
+	var judgment strings.Builder
+	for _, line := range strings.Split(stream, "\n") {
+		data, ok := strings.CutPrefix(strings.TrimSpace(line), "data:")
+		if !ok {
+			continue
+		}
+		var chunk struct {
+			Choices []struct {
+				Text  string `json:"text"`
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+		}
+		if json.Unmarshal([]byte(strings.TrimSpace(data)), &chunk) == nil && len(chunk.Choices) > 0 {
+			judgment.WriteString(chunk.Choices[0].Delta.Content + chunk.Choices[0].Text)
+		}
+	}
+	return judgment.String()
+}
+
+// RenderJudgeResponses renders the validation of the judge responses as markdown
+func RenderJudgeResponses(report JudgeResponseReport) string {
+	var b strings.Builder
+	b.WriteString("# Judge responses\n\n")
+	b.WriteString("| Category | Responses |\n|---|---|\n")
+	for _, category := range judgeResponseCategories {
+		fmt.Fprintf(&b, "| %s | %d |\n", category, report.Counts[category])
+	}
+	if report.Flagged() > 0 {
+		fmt.Fprintf(&b, "\n%d of %d sampled judgments have no valid rating. MT-Bench does not score them, which lowers and skews the score.\n",
+			report.Flagged(), report.Total())
+	}
+	if len(report.Issues) > 0 {
+		b.WriteString("\n| Category | Reason | Excerpt |\n|---|---|---|\n")
+		for _, issue := range report.Issues {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", issue.Category, issue.Reason, strings.ReplaceAll(issue.Excerpt, "|", `\|`))
+		}
+	}
+	return b.String()
+}
+
+// judgeExcerpt returns the end of a judgment on one line, where the rating is expected
+func judgeExcerpt(text string) string {
+	const length = 160
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > length {
+		return "..." + string(runes[len(runes)-length:])
+	}
+	return text
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyJudgment(t *testing.T) {
+	for judgment, category := range map[string]string{
+		"The answer is accurate and detailed.\n\nRating: [[8]]":       JudgeResponseValid,
+		"The response is concise. Rating: [7.5]":                      JudgeResponseValid,
+		"The answer is excellent. Rating: [[11]]":                     JudgeResponseMalformed,
+		"The answer is helpful and relevant, I would rate it highly.": JudgeResponseMalformed,
+		"": JudgeResponseMalformed,
+		"I'm sorry, but I can't evaluate content of this kind.":        JudgeResponseRefusal,
+		"As an AI language model, I cannot provide a rating. [[5]] ok": JudgeResponseValid,
+	} {
+		got, _ := ClassifyJudgment(judgment)
+		require.Equal(t, category, got, judgment)
+	}
+}
+
+func TestValidateJudgeResponses(t *testing.T) {
+	recordings := strings.Join([]string{
+		`{"method":"POST","path":"/v1/chat/completions","status":200,"response":{"choices":[{"message":{"content":"Accurate. Rating: [[9]]"},"finish_reason":"stop"}]}}`,
+		`{"method":"POST","path":"/v1/chat/completions","status":200,"response":{"choices":[{"message":{"content":"The answer covers"},"finish_reason":"length"}]}}`,
+		`{"method":"POST","path":"/v1/chat/completions","status":200,"response":{"choices":[{"message":{"content":"I cannot assess this | answer."}}]}}`,
+		`{"method":"POST","path":"/v1/chat/completions","status":200,"response":{"choices":[]}}`,
+		`{"method":"POST","path":"/v1/chat/completions","status":200,"response":"{\"choices\":[{\"message\":{\"content\":\"long...(truncated)"}`,
+		`{"method":"POST","path":"/v1/chat/completions","status":200,"response":"data: {\"choices\":[{\"delta\":{\"content\":\"Good. Rating: [[\"}}]}\n\ndata: {\"choices\":[{\"delta\":{\"content\":\"6]]\"}}]}\n\ndata: [DONE]\n"}`,
+		`{"method":"POST","path":"/v1/chat/completions","status":429,"response":{"error":"rate limited"}}`,
+		`{"method":"GET","path":"/v1/models","status":200,"response":{"data":[]}}`,
+		``,
+	}, "\n")
+	report, err := ValidateJudgeResponses([]byte(recordings))
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{
+		JudgeResponseValid:     2,
+		JudgeResponseMalformed: 2,
+		JudgeResponseRefusal:   1,
+		JudgeResponseError:     1,
+		JudgeResponseUnparsed:  1,
+	}, report.Counts)
+	require.Equal(t, 3, report.Flagged())
+	require.Equal(t, JudgeResponseIssue{
+		Category: JudgeResponseMalformed,
+		Reason:   "no rating in [[rating]] format, the judgment was cut at the token limit",
+		Excerpt:  "The answer covers",
+	}, report.Issues[0])
+
+	rendered := RenderJudgeResponses(report)
+	require.Contains(t, rendered, "| malformed | 2 |")
+	require.Contains(t, rendered, "3 of 7 sampled judgments have no valid rating")
+	require.Contains(t, rendered, `| refusal | no rating, the judge answered 'i cannot' | I cannot assess this \| answer. |`)
+
+	_, err = ValidateJudgeResponses([]byte("not json\n"))
+	require.ErrorContains(t, err, "invalid recording")
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// DefaultVLLMImage is the vLLM image of the serving runtimes in manifests/
+	DefaultVLLMImage = "quay.io/modh/vllm@sha256:3c56d4c2a5a9565e8b07ba17a6624290c4fb39ac9097b99b946326c09a8b40c8"
+	// ServingCertAnnotation makes the OpenShift service CA operator generate a TLS secret for a Service
+	ServingCertAnnotation = "service.beta.openshift.io/serving-cert-secret-name"
+
+	judgeServingPort = 8443
+	judgeModelPath   = "/mnt/model"
+)
+
+// RawJudgeConfig describes a judge model served by a plain Deployment and Service, for clusters without KServe
+type RawJudgeConfig struct {
+	Name      string
+	Namespace string
+	Image     string
+	// ModelPVC is the claim holding the judge model at its root, as prepared by manifests/prometheus_serve
+	ModelPVC  string
+	ModelName string
+	APIToken  string
+	GPUs      int
+	// CA is set in the judge secret, e.g. the service CA the serving certificate is signed by
+	CA CASource
+}
+
+// DeployRawJudge deploys the judge as a Deployment and Service with TLS from the service serving certificate,
+// waits for it to become ready and creates the judge secret pointing at it. It returns the name of the judge secret.
+func DeployRawJudge(t *testing.T, client kubernetes.Interface, config RawJudgeConfig, timeout time.Duration) string {
+	if config.Image == "" {
+		config.Image = DefaultVLLMImage
+	}
+	labels := map[string]string{"app": config.Name}
+	tlsSecretName := config.Name + "-tls"
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        config.Name,
+			Labels:      labels,
+			Annotations: map[string]string{ServingCertAnnotation: tlsSecretName},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: labels,
+			Ports:    []corev1.ServicePort{{Name: "https", Port: judgeServingPort, TargetPort: intstr.FromInt(judgeServingPort)}},
+		},
+	}
+	_, err := client.CoreV1().Services(config.Namespace).Create(context.Background(), service, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create judge service")
+
+	gpus := resource.MustParse(strconv.Itoa(config.GPUs))
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: config.Name, Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:    "vllm",
+						Image:   config.Image,
+						Command: []string{"python", "-m", "vllm.entrypoints.openai.api_server"},
+						Args: []string{
+							fmt.Sprintf("--port=%d", judgeServingPort),
+							"--model=" + judgeModelPath,
+							"--served-model-name=" + config.ModelName,
+							"--tensor-parallel-size=" + strconv.Itoa(config.GPUs),
+							"--distributed-executor-backend=mp",
+							"--api-key=" + config.APIToken,
+							"--ssl-certfile=/etc/tls/tls.crt",
+							"--ssl-keyfile=/etc/tls/tls.key",
+						},
+						Env:   []corev1.EnvVar{{Name: "HF_HOME", Value: "/tmp/hf_home"}},
+						Ports: []corev1.ContainerPort{{ContainerPort: judgeServingPort}},
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{DefaultGPUResource: gpus},
+							Limits:   corev1.ResourceList{DefaultGPUResource: gpus},
+						},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{
+								Path:   "/health",
+								Port:   intstr.FromInt(judgeServingPort),
+								Scheme: corev1.URISchemeHTTPS,
+							}},
+							PeriodSeconds: 10,
+						},
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "model", MountPath: judgeModelPath, ReadOnly: true},
+							{Name: "tls", MountPath: "/etc/tls", ReadOnly: true},
+							{Name: "shm", MountPath: "/dev/shm"},
+						},
+					}},
+					Tolerations: []corev1.Toleration{{Key: DefaultGPUResource, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+					Volumes: []corev1.Volume{
+						{Name: "model", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: config.ModelPVC, ReadOnly: true}}},
+						{Name: "tls", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: tlsSecretName}}},
+						{Name: "shm", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{Medium: corev1.StorageMediumMemory}}},
+					},
+				},
+			},
+		},
+	}
+	_, err = client.AppsV1().Deployments(config.Namespace).Create(context.Background(), deployment, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create judge deployment")
+
+	require.NoError(t, WaitForDeploymentReady(t, client, config.Namespace, config.Name, timeout), "Judge deployment did not become ready")
+
+	secretName := config.Name + "-secret"
+	CreateModelServerSecret(t, client, config.Namespace, secretName, ModelServerSecret{
+		APIToken:  config.APIToken,
+		Endpoint:  fmt.Sprintf("https://%s.%s.svc:%d/v1", config.Name, config.Namespace, judgeServingPort),
+		ModelName: config.ModelName,
+		CA:        config.CA,
+	})
+	return secretName
+}
+
+// DeleteRawJudge removes everything DeployRawJudge created
+func DeleteRawJudge(t *testing.T, client kubernetes.Interface, namespace, name string) {
+	ctx := context.Background()
+	_ = client.AppsV1().Deployments(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	_ = client.CoreV1().Services(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	_ = client.CoreV1().Secrets(namespace).Delete(ctx, name+"-tls", metav1.DeleteOptions{})
+	_ = client.CoreV1().Secrets(namespace).Delete(ctx, name+"-secret", metav1.DeleteOptions{})
+}
+
+// CreateModelServerSecret creates a teacher or judge secret in the layout expected by the pipeline, with the CA
+// ConfigMap and its key when set
+func CreateModelServerSecret(t *testing.T, client kubernetes.Interface, namespace, name string, content ModelServerSecret) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		StringData: map[string]string{
+			"api_token":  content.APIToken,
+			"endpoint":   content.Endpoint,
+			"model_name": content.ModelName,
+		},
+		Type: corev1.SecretTypeOpaque,
+	}
+	if content.CA.ConfigMap != "" {
+		secret.StringData["ca_cert_config_map"] = content.CA.ConfigMap
+		secret.StringData["ca_cert_config_map_key"] = content.CA.Key
+	}
+	_, err := client.CoreV1().Secrets(namespace).Create(context.Background(), secret, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create model server secret")
+}
+
+// DeleteModelServerSecret removes a teacher or judge secret created by the suite
+func DeleteModelServerSecret(t *testing.T, client kubernetes.Interface, namespace, name string) {
+	_ = client.CoreV1().Secrets(namespace).Delete(context.Background(), name, metav1.DeleteOptions{})
+}
+
+// WaitForDeploymentReady polls a deployment until all its replicas are ready or the timeout expires
+func WaitForDeploymentReady(t *testing.T, client kubernetes.Interface, namespace, name string, timeout time.Duration) error {
+	deadline := time.After(timeout)
+	tick := time.Tick(10 * time.Second)
+	for {
+		deployment, err := client.AppsV1().Deployments(namespace).Get(context.Background(), name, metav1.GetOptions{})
+		require.NoError(t, err, "Failed to retrieve deployment %s", name)
+
+		if deployment.Spec.Replicas != nil && deployment.Status.ReadyReplicas >= *deployment.Spec.Replicas {
+			return nil
+		}
+
+		select {
+		case <-deadline:
+			return fmt.Errorf("deployment %s not ready after %s", name, timeout)
+		case <-tick:
+		}
+	}
+}
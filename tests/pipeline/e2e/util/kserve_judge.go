@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+var InferenceServiceGVR = schema.GroupVersionResource{
+	Group:    "serving.kserve.io",
+	Version:  "v1beta1",
+	Resource: "inferenceservices",
+}
+
+const (
+	// InferenceServiceAuthAnnotation enables token authentication of an InferenceService served by OpenShift AI
+	InferenceServiceAuthAnnotation = "security.opendatahub.io/enable-auth"
+	// ServiceAccountNameAnnotation names the service account of a service account token secret
+	ServiceAccountNameAnnotation = "kubernetes.io/service-account.name"
+)
+
+// InferenceServiceTokenSecret returns the first token secret of the service account OpenShift AI creates for an
+// InferenceService with token authentication, <name>-sa
+func InferenceServiceTokenSecret(secrets []corev1.Secret, name string) (corev1.Secret, bool) {
+	for _, secret := range secrets {
+		if secret.Type == corev1.SecretTypeServiceAccountToken && secret.Annotations[ServiceAccountNameAnnotation] == name+"-sa" && len(secret.Data[corev1.ServiceAccountTokenKey]) > 0 {
+			return secret, true
+		}
+	}
+	return corev1.Secret{}, false
+}
+
+// ModelServerSecretFromInferenceService builds the content of a model server secret from a ready InferenceService:
+// its in-cluster address, or its URL, its served model name, the token of its service account when token
+// authentication is enabled and the service CA when it is served with TLS inside the cluster
+func ModelServerSecretFromInferenceService(isvc *unstructured.Unstructured, secrets []corev1.Secret) (ModelServerSecret, error) {
+	name := isvc.GetName()
+	if !inferenceServiceReady(isvc) {
+		return ModelServerSecret{}, fmt.Errorf("InferenceService %s is not ready", name)
+	}
+
+	address, _, _ := unstructured.NestedString(isvc.Object, "status", "address", "url")
+	if address == "" {
+		address, _, _ = unstructured.NestedString(isvc.Object, "status", "url")
+	}
+	endpoint, err := url.Parse(address)
+	if err != nil || endpoint.Hostname() == "" {
+		return ModelServerSecret{}, fmt.Errorf("InferenceService %s has no URL", name)
+	}
+
+	content := ModelServerSecret{
+		Endpoint:  strings.TrimSuffix(address, "/") + "/v1",
+		ModelName: servedModelName(isvc),
+	}
+	if endpoint.Scheme == "https" && IsInClusterEndpoint(address) {
+		content.CA = ServiceCA
+	}
+	if isvc.GetAnnotations()[InferenceServiceAuthAnnotation] == "true" {
+		secret, ok := InferenceServiceTokenSecret(secrets, name)
+		if !ok {
+			return ModelServerSecret{}, fmt.Errorf("InferenceService %s requires a token, no token secret of service account %s-sa found", name, name)
+		}
+		content.APIToken = string(secret.Data[corev1.ServiceAccountTokenKey])
+	}
+	return content, nil
+}
+
+// DiscoverModelServerSecret reads an InferenceService of the namespace and the token secrets of the namespace to
+// build a model server secret for it, see ModelServerSecretFromInferenceService
+func DiscoverModelServerSecret(ctx context.Context, client kubernetes.Interface, dynamicClient dynamic.Interface, namespace, name string) (ModelServerSecret, error) {
+	isvc, err := dynamicClient.Resource(InferenceServiceGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return ModelServerSecret{}, fmt.Errorf("failed to get InferenceService %s: %w", name, err)
+	}
+	secrets, err := client.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "type=" + string(corev1.SecretTypeServiceAccountToken),
+	})
+	if err != nil {
+		return ModelServerSecret{}, fmt.Errorf("failed to list token secrets: %w", err)
+	}
+	return ModelServerSecretFromInferenceService(isvc, secrets.Items)
+}
+
+func inferenceServiceReady(isvc *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(isvc.Object, "status", "conditions")
+	for _, condition := range conditions {
+		condition, ok := condition.(map[string]interface{})
+		if ok && condition["type"] == "Ready" {
+			return condition["status"] == "True"
+		}
+	}
+	return false
+}
+
+// servedModelName returns the --served-model-name argument of the predictor, the InferenceService name the serving
+// runtimes of OpenShift AI serve the model as otherwise
+func servedModelName(isvc *unstructured.Unstructured) string {
+	args, _, _ := unstructured.NestedStringSlice(isvc.Object, "spec", "predictor", "model", "args")
+	for i, arg := range args {
+		if value, ok := strings.CutPrefix(arg, "--served-model-name="); ok {
+			return value
+		}
+		if arg == "--served-model-name" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return isvc.GetName()
+}
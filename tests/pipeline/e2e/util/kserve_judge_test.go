@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testInferenceService(name, address string, ready bool, annotations map[string]interface{}, args ...interface{}) *unstructured.Unstructured {
+	status := "False"
+	if ready {
+		status = "True"
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "serving.kserve.io/v1beta1",
+		"kind":       "InferenceService",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "ilab", "annotations": annotations},
+		"spec":       map[string]interface{}{"predictor": map[string]interface{}{"model": map[string]interface{}{"args": args}}},
+		"status": map[string]interface{}{
+			"url":        "https://" + name + "-ilab.apps.example.com",
+			"address":    map[string]interface{}{"url": address},
+			"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": status}},
+		},
+	}}
+}
+
+func tokenSecret(name, serviceAccount, token string) corev1.Secret {
+	return corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ilab", Annotations: map[string]string{ServiceAccountNameAnnotation: serviceAccount}},
+		Type:       corev1.SecretTypeServiceAccountToken,
+		Data:       map[string][]byte{corev1.ServiceAccountTokenKey: []byte(token)},
+	}
+}
+
+func TestModelServerSecretFromInferenceService(t *testing.T) {
+	secrets := []corev1.Secret{
+		tokenSecret("default-token", "default", "wrong"),
+		tokenSecret("token-judge-sa", "judge-sa", "secret-token"),
+	}
+
+	isvc := testInferenceService("judge", "https://judge-predictor.ilab.svc.cluster.local", true,
+		map[string]interface{}{InferenceServiceAuthAnnotation: "true"}, "--served-model-name=prometheus")
+	content, err := ModelServerSecretFromInferenceService(isvc, secrets)
+	require.NoError(t, err)
+	require.Equal(t, ModelServerSecret{
+		APIToken:  "secret-token",
+		Endpoint:  "https://judge-predictor.ilab.svc.cluster.local/v1",
+		ModelName: "prometheus",
+		CA:        ServiceCA,
+	}, content)
+
+	// Without an in-cluster address or authentication, the external URL is used without token
+	isvc = testInferenceService("judge", "", true, nil, "--served-model-name", "prometheus-8x7b")
+	content, err = ModelServerSecretFromInferenceService(isvc, nil)
+	require.NoError(t, err)
+	require.Equal(t, ModelServerSecret{Endpoint: "https://judge-ilab.apps.example.com/v1", ModelName: "prometheus-8x7b"}, content)
+
+	isvc = testInferenceService("judge", "http://judge.ilab.svc.cluster.local", true, nil)
+	content, err = ModelServerSecretFromInferenceService(isvc, nil)
+	require.NoError(t, err)
+	require.Equal(t, ModelServerSecret{Endpoint: "http://judge.ilab.svc.cluster.local/v1", ModelName: "judge"}, content)
+
+	_, err = ModelServerSecretFromInferenceService(testInferenceService("judge", "", false, nil), nil)
+	require.EqualError(t, err, "InferenceService judge is not ready")
+	isvc = testInferenceService("judge", "", true, map[string]interface{}{InferenceServiceAuthAnnotation: "true"})
+	_, err = ModelServerSecretFromInferenceService(isvc, secrets[:1])
+	require.EqualError(t, err, "InferenceService judge requires a token, no token secret of service account judge-sa found")
+}
+
+func TestDiscoverModelServerSecret(t *testing.T) {
+	isvc := testInferenceService("judge", "https://judge-predictor.ilab.svc.cluster.local", true, nil)
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{InferenceServiceGVR: "InferenceServiceList"}, isvc)
+
+	content, err := DiscoverModelServerSecret(context.Background(), fake.NewSimpleClientset(), dynamicClient, "ilab", "judge")
+	require.NoError(t, err)
+	require.Equal(t, "https://judge-predictor.ilab.svc.cluster.local/v1", content.Endpoint)
+
+	_, err = DiscoverModelServerSecret(context.Background(), fake.NewSimpleClientset(), dynamicClient, "ilab", "missing")
+	require.ErrorContains(t, err, "failed to get InferenceService missing")
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/watcher"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// RunIDLabel is the label the pipeline server sets on every pod of a pipeline run
+	RunIDLabel = "pipeline/runid"
+	// DefaultResourcePrefix is prepended to the names of all resources created by the suite
+	DefaultResourcePrefix = "ilab-test-"
+)
+
+// ResourcePrefix returns the RESOURCE_PREFIX environment variable, or the default prefix when not set
+func ResourcePrefix() string {
+	if prefix, ok := os.LookupEnv("RESOURCE_PREFIX"); ok {
+		return prefix
+	}
+	return DefaultResourcePrefix
+}
+
+// GenerateName returns the generateName used for a resource of the suite, e.g. "ilab-test-preflight-"
+func GenerateName(name string) string {
+	return ResourcePrefix() + name + "-"
+}
+
+// NewKubeConfig loads the client config from KUBECONFIG, the default kubeconfig location or the in-cluster config
+func NewKubeConfig(t *testing.T) *rest.Config {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{}).ClientConfig()
+	require.NoError(t, err, "Failed to load Kubernetes client config")
+	return config
+}
+
+// NewKubeClient builds a typed Kubernetes client
+func NewKubeClient(t *testing.T) kubernetes.Interface {
+	client, err := kubernetes.NewForConfig(NewKubeConfig(t))
+	require.NoError(t, err, "Failed to create Kubernetes client")
+	return client
+}
+
+// NewDynamicClient builds a dynamic client for custom resources
+func NewDynamicClient(t *testing.T) dynamic.Interface {
+	client, err := dynamic.NewForConfig(NewKubeConfig(t))
+	require.NoError(t, err, "Failed to create dynamic Kubernetes client")
+	return client
+}
+
+// NewWatchClient builds a controller-runtime client to wait for objects with the watcher package
+func NewWatchClient(t *testing.T) ctrlclient.WithWatch {
+	client, err := watcher.NewClient(NewKubeConfig(t))
+	require.NoError(t, err, "Failed to create watch client")
+	return client
+}
+
+// GetRunPods lists the pods of a pipeline run
+func GetRunPods(t *testing.T, client kubernetes.Interface, namespace, runID string) []corev1.Pod {
+	pods, err := client.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", RunIDLabel, runID),
+	})
+	require.NoError(t, err, "Failed to list pipeline run pods")
+	return pods.Items
+}
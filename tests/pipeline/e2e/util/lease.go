@@ -0,0 +1,36 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/gpuqueue"
+	"k8s.io/client-go/kubernetes"
+)
+
+// GPULeaseName returns the name of the Lease serializing the GPU-heavy runs of a shared cluster, e.g. "ilab-test-gpu"
+func GPULeaseName() string {
+	return ResourcePrefix() + gpuqueue.LeaseName
+}
+
+// AcquireLease queues for the Lease of the request and takes it once it is available and the request is first in the
+// queue, see gpuqueue.Acquire. Queue entries are named after GenerateName unless the request names them.
+func AcquireLease(t *testing.T, client kubernetes.Interface, request gpuqueue.Request) (*gpuqueue.Held, error) {
+	if request.GenerateName == "" {
+		request.GenerateName = GenerateName(gpuqueue.EntryName)
+	}
+	return gpuqueue.Acquire(context.Background(), client, request, t.Logf)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/loglevel"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+const logShipperPort = 8080
+
+// LogShipperConfig describes a log shipper copying the logs of the run pods to a dedicated PVC
+type LogShipperConfig struct {
+	Name      string
+	Namespace string
+	// Image is built from tests/Containerfile
+	Image string
+	// StorageClass must support ReadWriteMany, empty for the default storage class
+	StorageClass string
+	Size         string
+	// LogLevelConfigMap sets the log level of the shipper during the run, loglevel.DefaultConfigMap when empty
+	LogLevelConfigMap string
+}
+
+// DeployLogShipper creates the log PVC and the log shipper with the permissions to read the pod logs of its
+// namespace and its log level ConfigMap, and waits for it to become ready
+func DeployLogShipper(t *testing.T, client kubernetes.Interface, config LogShipperConfig, timeout time.Duration) {
+	ctx := context.Background()
+	labels := map[string]string{"app": config.Name}
+	meta := metav1.ObjectMeta{Name: config.Name, Labels: labels}
+	logLevelConfigMap := config.LogLevelConfigMap
+	if logLevelConfigMap == "" {
+		logLevelConfigMap = loglevel.DefaultConfigMap
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: meta,
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+			Resources:   corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(config.Size)}},
+		},
+	}
+	if config.StorageClass != "" {
+		pvc.Spec.StorageClassName = &config.StorageClass
+	}
+	_, err := client.CoreV1().PersistentVolumeClaims(config.Namespace).Create(ctx, pvc, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create log PVC")
+
+	_, err = client.CoreV1().ServiceAccounts(config.Namespace).Create(ctx, &corev1.ServiceAccount{ObjectMeta: meta}, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create log shipper service account")
+	_, err = client.RbacV1().Roles(config.Namespace).Create(ctx, &rbacv1.Role{
+		ObjectMeta: meta,
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list", "watch"}},
+			{APIGroups: []string{""}, Resources: []string{"pods/log"}, Verbs: []string{"get"}},
+			{APIGroups: []string{""}, Resources: []string{"configmaps"}, ResourceNames: []string{logLevelConfigMap}, Verbs: []string{"get"}},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create log shipper role")
+	_, err = client.RbacV1().RoleBindings(config.Namespace).Create(ctx, &rbacv1.RoleBinding{
+		ObjectMeta: meta,
+		RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "Role", Name: config.Name},
+		Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: config.Name, Namespace: config.Namespace}},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create log shipper role binding")
+
+	_, err = client.CoreV1().Services(config.Namespace).Create(ctx, &corev1.Service{
+		ObjectMeta: meta,
+		Spec: corev1.ServiceSpec{
+			Selector: labels,
+			Ports:    []corev1.ServicePort{{Name: "http", Port: logShipperPort, TargetPort: intstr.FromInt(logShipperPort)}},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create log shipper service")
+
+	resources := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("250m"),
+		corev1.ResourceMemory: resource.MustParse("256Mi"),
+	}
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: meta,
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: config.Name,
+					Containers: []corev1.Container{{
+						Name:    "shipper",
+						Image:   config.Image,
+						Command: []string{"log-shipper"},
+						Args: []string{
+							"--listen=:" + strconv.Itoa(logShipperPort),
+							"--namespace=" + config.Namespace,
+							"--dir=/logs",
+							"--log-level-configmap=" + logLevelConfigMap,
+						},
+						Ports:        []corev1.ContainerPort{{ContainerPort: logShipperPort}},
+						Resources:    corev1.ResourceRequirements{Requests: resources, Limits: resources},
+						VolumeMounts: []corev1.VolumeMount{{Name: "logs", MountPath: "/logs"}},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(logShipperPort)}},
+						},
+					}},
+					Volumes: []corev1.Volume{{
+						Name:         "logs",
+						VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: config.Name}},
+					}},
+				},
+			},
+		},
+	}
+	_, err = client.AppsV1().Deployments(config.Namespace).Create(ctx, deployment, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create log shipper deployment")
+
+	require.NoError(t, WaitForDeploymentReady(t, client, config.Namespace, config.Name, timeout), "Log shipper did not become ready")
+}
+
+// CollectLogBundle downloads the logs copied to the log PVC as a gzipped tarball, through the service proxy of the
+// API server
+func CollectLogBundle(client kubernetes.Interface, namespace, name string) ([]byte, error) {
+	bundle, err := client.CoreV1().Services(namespace).ProxyGet("http", name, strconv.Itoa(logShipperPort), "logs.tar.gz", nil).DoRaw(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect the logs of log shipper %s: %w", name, err)
+	}
+	return bundle, nil
+}
+
+// DeleteLogShipper removes everything DeployLogShipper created, the log PVC included
+func DeleteLogShipper(t *testing.T, client kubernetes.Interface, namespace, name string) {
+	ctx := context.Background()
+	_ = client.AppsV1().Deployments(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	_ = client.CoreV1().Services(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	_ = client.RbacV1().RoleBindings(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	_ = client.RbacV1().Roles(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	_ = client.CoreV1().ServiceAccounts(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	_ = client.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
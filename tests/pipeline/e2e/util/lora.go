@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// fullWeightsPattern matches the weight files of a full model, single file or sharded
+var fullWeightsPattern = regexp.MustCompile(`^(model|pytorch_model)(-\d+-of-\d+)?\.(safetensors|bin)$`)
+
+// MissingPipelineInputs returns the names of the params that are not inputs of the compiled pipeline
+func MissingPipelineInputs(definitions map[string]PipelineParameterSpec, params map[string]interface{}) []string {
+	var missing []string
+	for name := range params {
+		if _, ok := definitions[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// PodGPURequests returns the largest GPU request of the containers of each pod
+func PodGPURequests(pods []corev1.Pod, gpuResource string) map[string]int64 {
+	requests := map[string]int64{}
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			quantity, ok := container.Resources.Limits[corev1.ResourceName(gpuResource)]
+			if !ok {
+				quantity = container.Resources.Requests[corev1.ResourceName(gpuResource)]
+			}
+			if quantity.Value() > requests[pod.Name] {
+				requests[pod.Name] = quantity.Value()
+			}
+		}
+	}
+	return requests
+}
+
+// CheckAdapterArtifacts verifies the stored model is a LoRA adapter: adapter config and weights are present and no
+// full model weights were written
+func CheckAdapterArtifacts(keys []string) error {
+	var config, weights bool
+	for _, key := range keys {
+		switch name := path.Base(key); {
+		case name == "adapter_config.json":
+			config = true
+		case name == "adapter_model.safetensors" || name == "adapter_model.bin":
+			weights = true
+		case fullWeightsPattern.MatchString(name):
+			return fmt.Errorf("full model weights %s were stored instead of an adapter", key)
+		}
+	}
+	if !config || !weights {
+		return fmt.Errorf("adapter artifacts missing, found adapter config: %t, adapter weights: %t", config, weights)
+	}
+	return nil
+}
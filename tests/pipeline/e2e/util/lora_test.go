@@ -0,0 +1,42 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCheckAdapterArtifacts(t *testing.T) {
+	prefix := "runs/20250301T120000Z-abc/ilab/run/upload-model-op/model/"
+	require.NoError(t, CheckAdapterArtifacts([]string{prefix + "adapter_config.json", prefix + "adapter_model.safetensors", prefix + "tokenizer.json"}))
+	require.ErrorContains(t, CheckAdapterArtifacts([]string{prefix + "adapter_config.json", prefix + "adapter_model.safetensors", prefix + "model-00001-of-00003.safetensors"}), "full model weights")
+	require.ErrorContains(t, CheckAdapterArtifacts([]string{prefix + "config.json"}), "adapter artifacts missing")
+}
+
+func TestPodGPURequests(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "train-phase-1-master-0"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{DefaultGPUResource: resource.MustParse("2")}},
+		}}},
+	}
+	require.Equal(t, map[string]int64{"train-phase-1-master-0": 2}, PodGPURequests([]corev1.Pod{pod}, DefaultGPUResource))
+
+	require.Equal(t, []string{"train_lora_rank"}, MissingPipelineInputs(map[string]PipelineParameterSpec{"train_seed": {}}, map[string]interface{}{"train_seed": 1, "train_lora_rank": 8}))
+}
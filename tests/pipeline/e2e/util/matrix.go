@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+// ImageCombination is one set of images a pipeline run is certified with
+type ImageCombination struct {
+	WorkbenchImage string `mapstructure:"workbench_image"`
+	SDGImage       string `mapstructure:"sdg_image"`
+	TrainingImage  string `mapstructure:"training_image"`
+}
+
+// ImageMatrix lists the candidate images of each kind. The baseline holds the images compiled into pipeline.yaml,
+// which are replaced by the candidates of every combination.
+type ImageMatrix struct {
+	Baseline        ImageCombination `mapstructure:"baseline"`
+	WorkbenchImages []string         `mapstructure:"workbench_images"`
+	SDGImages       []string         `mapstructure:"sdg_images"`
+	TrainingImages  []string         `mapstructure:"training_images"`
+}
+
+// MatrixResult is the outcome of the pipeline run for one image combination
+type MatrixResult struct {
+	Combination ImageCombination
+	RunID       string
+	Duration    time.Duration
+	Err         error
+}
+
+// LoadImageMatrix reads the image matrix from a YAML file
+func LoadImageMatrix(t *testing.T, path string) ImageMatrix {
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig(), "Error loading image matrix")
+
+	var matrix ImageMatrix
+	require.NoError(t, v.Unmarshal(&matrix), "Error parsing image matrix")
+	return matrix
+}
+
+// Combinations returns every workbench × SDG × training image combination, an empty list keeps the baseline image
+func (m ImageMatrix) Combinations() []ImageCombination {
+	orBaseline := func(images []string, baseline string) []string {
+		if len(images) == 0 {
+			return []string{baseline}
+		}
+		return images
+	}
+
+	var combinations []ImageCombination
+	for _, workbench := range orBaseline(m.WorkbenchImages, m.Baseline.WorkbenchImage) {
+		for _, sdg := range orBaseline(m.SDGImages, m.Baseline.SDGImage) {
+			for _, training := range orBaseline(m.TrainingImages, m.Baseline.TrainingImage) {
+				combinations = append(combinations, ImageCombination{workbench, sdg, training})
+			}
+		}
+	}
+	return combinations
+}
+
+// RewritePipelineImages replaces the baseline images of a compiled pipeline with the images of a combination.
+// The SDG image is the image of the component containers, the training image is passed to the PyTorchJob launcher as
+// a constant, so both can be swapped independently even though they share the same baseline.
+func RewritePipelineImages(pipelineYAML []byte, baseline, combination ImageCombination) []byte {
+	rewritten := bytes.ReplaceAll(pipelineYAML, []byte("constant: "+baseline.TrainingImage), []byte("constant: "+combination.TrainingImage))
+	rewritten = bytes.ReplaceAll(rewritten, []byte("image: "+baseline.SDGImage), []byte("image: "+combination.SDGImage))
+	return bytes.ReplaceAll(rewritten, []byte(baseline.WorkbenchImage), []byte(combination.WorkbenchImage))
+}
+
+// RenderMatrixReport renders the results as a markdown compatibility matrix
+func RenderMatrixReport(results []MatrixResult) string {
+	var report strings.Builder
+	report.WriteString("# Image compatibility matrix\n\n")
+	report.WriteString("| Workbench image | SDG image | Training image | Result | Duration | Run ID |\n")
+	report.WriteString("|---|---|---|---|---|---|\n")
+	for _, result := range results {
+		status := "PASS"
+		if result.Err != nil {
+			status = fmt.Sprintf("FAIL: %s", result.Err)
+		}
+		fmt.Fprintf(&report, "| %s | %s | %s | %s | %s | %s |\n",
+			result.Combination.WorkbenchImage, result.Combination.SDGImage, result.Combination.TrainingImage,
+			status, result.Duration.Round(time.Second), result.RunID)
+	}
+	return report.String()
+}
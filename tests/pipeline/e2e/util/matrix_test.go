@@ -0,0 +1,40 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageMatrixCombinations(t *testing.T) {
+	matrix := ImageMatrix{
+		Baseline:        ImageCombination{"workbench:base", "rhelai:base", "rhelai:base"},
+		WorkbenchImages: []string{"workbench:1", "workbench:2"},
+		SDGImages:       []string{"rhelai:1", "rhelai:2"},
+	}
+
+	combinations := matrix.Combinations()
+	require.Len(t, combinations, 4)
+	require.Equal(t, ImageCombination{"workbench:2", "rhelai:1", "rhelai:base"}, combinations[2])
+}
+
+func TestRewritePipelineImages(t *testing.T) {
+	baseline := ImageCombination{"workbench:base", "rhelai:base", "rhelai:base"}
+	pipeline := []byte("image: workbench:base\nimage: rhelai:base\nconstant: rhelai:base\n")
+
+	rewritten := RewritePipelineImages(pipeline, baseline, ImageCombination{"workbench:1", "rhelai:sdg", "rhelai:train"})
+	require.Equal(t, "image: workbench:1\nimage: rhelai:sdg\nconstant: rhelai:train\n", string(rewritten))
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// FakeGPUResource is the extended resource advertised on CPU-only nodes in place of GPUs for mock runs
+	FakeGPUResource = "ilab.opendatahub.io/fake-gpu"
+	// WorkerNodeLabel selects the nodes the fake GPUs are advertised on
+	WorkerNodeLabel = "node-role.kubernetes.io/worker"
+	// ControlPlaneTaint keeps workloads off the control plane nodes
+	ControlPlaneTaint = "node-role.kubernetes.io/control-plane"
+
+	stubLLMPort = 8080
+)
+
+// AdvertiseFakeGPUs adds the given number of fake GPUs to the capacity of every schedulable node matching the label
+// selector and returns the names of the nodes. The fake GPUs only satisfy the scheduler, pods requesting them get no
+// device.
+func AdvertiseFakeGPUs(t *testing.T, client kubernetes.Interface, count int, selector string) []string {
+	nodes, err := client.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{LabelSelector: selector})
+	require.NoError(t, err, "Failed to list nodes")
+
+	patch := fakeGPUPatch("add", count)
+	var names []string
+	for _, node := range nodes.Items {
+		if !schedulable(node) {
+			continue
+		}
+		_, err := client.CoreV1().Nodes().Patch(context.Background(), node.Name, types.JSONPatchType, patch, metav1.PatchOptions{}, "status")
+		require.NoError(t, err, "Failed to advertise fake GPUs on node %s", node.Name)
+		names = append(names, node.Name)
+	}
+	return names
+}
+
+// schedulable reports whether run pods can be scheduled on the node, control plane nodes of multi-node kind
+// clusters being tainted
+func schedulable(node corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == ControlPlaneTaint && taint.Effect == corev1.TaintEffectNoSchedule {
+			return false
+		}
+	}
+	return true
+}
+
+// DefaultStorageClass returns the name of the default storage class of the cluster, e.g. standard on kind or
+// crc-csi-hostpath-provisioner on OpenShift Local
+func DefaultStorageClass(client kubernetes.Interface) (string, error) {
+	classes, err := client.StorageV1().StorageClasses().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, class := range classes.Items {
+		if class.Annotations["storageclass.kubernetes.io/is-default-class"] == "true" {
+			return class.Name, nil
+		}
+	}
+	return "", fmt.Errorf("no default storage class found")
+}
+
+// RemoveFakeGPUs removes the fake GPUs from the capacity of the given nodes
+func RemoveFakeGPUs(t *testing.T, client kubernetes.Interface, nodes []string) {
+	patch := fakeGPUPatch("remove", 0)
+	for _, node := range nodes {
+		if _, err := client.CoreV1().Nodes().Patch(context.Background(), node, types.JSONPatchType, patch, metav1.PatchOptions{}, "status"); err != nil {
+			t.Logf("Failed to remove fake GPUs from node %s: %v", node, err)
+		}
+	}
+}
+
+func fakeGPUPatch(op string, count int) []byte {
+	path := "/status/capacity/" + strings.ReplaceAll(FakeGPUResource, "/", "~1")
+	operation := map[string]interface{}{"op": op, "path": path}
+	if op != "remove" {
+		operation["value"] = strconv.Itoa(count)
+	}
+	patch, _ := json.Marshal([]interface{}{operation})
+	return patch
+}
+
+// StubLLMConfig describes a stub OpenAI-compatible server standing in for the teacher and judge
+type StubLLMConfig struct {
+	Name      string
+	Namespace string
+	// Image is built from tests/Containerfile
+	Image     string
+	ModelName string
+	APIKey    string
+	// Latency and ErrorRate are injected into the completions
+	Latency   time.Duration
+	ErrorRate float64
+}
+
+// DeployStubLLM deploys the stub LLM server and waits for it to become ready. It returns the endpoint to put in the
+// teacher and judge secrets.
+func DeployStubLLM(t *testing.T, client kubernetes.Interface, config StubLLMConfig, timeout time.Duration) string {
+	labels := map[string]string{"app": config.Name}
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: config.Name, Labels: labels},
+		Spec: corev1.ServiceSpec{
+			Selector: labels,
+			Ports:    []corev1.ServicePort{{Name: "http", Port: stubLLMPort, TargetPort: intstr.FromInt(stubLLMPort)}},
+		},
+	}
+	_, err := client.CoreV1().Services(config.Namespace).Create(context.Background(), service, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create stub LLM service")
+
+	resources := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("128Mi"),
+	}
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: config.Name, Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:    "stub-llm",
+						Image:   config.Image,
+						Command: []string{"stub-llm"},
+						Args: []string{
+							fmt.Sprintf("--listen=:%d", stubLLMPort),
+							"--model-name=" + config.ModelName,
+							"--api-key=" + config.APIKey,
+							"--latency=" + config.Latency.String(),
+							"--error-rate=" + strconv.FormatFloat(config.ErrorRate, 'f', -1, 64),
+						},
+						Ports:     []corev1.ContainerPort{{ContainerPort: stubLLMPort}},
+						Resources: corev1.ResourceRequirements{Requests: resources, Limits: resources},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/health", Port: intstr.FromInt(stubLLMPort)}},
+						},
+					}},
+				},
+			},
+		},
+	}
+	_, err = client.AppsV1().Deployments(config.Namespace).Create(context.Background(), deployment, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create stub LLM deployment")
+
+	require.NoError(t, WaitForDeploymentReady(t, client, config.Namespace, config.Name, timeout), "Stub LLM server did not become ready")
+	return fmt.Sprintf("http://%s.%s.svc:%d/v1", config.Name, config.Namespace, stubLLMPort)
+}
+
+// DeleteStubLLM removes everything DeployStubLLM created
+func DeleteStubLLM(t *testing.T, client kubernetes.Interface, namespace, name string) {
+	ctx := context.Background()
+	_ = client.AppsV1().Deployments(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	_ = client.CoreV1().Services(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestAdvertiseFakeGPUs(t *testing.T) {
+	node := func(name string, labels map[string]string, taints ...corev1.Taint) *corev1.Node {
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}, Spec: corev1.NodeSpec{Taints: taints},
+			Status: corev1.NodeStatus{Capacity: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")}}}
+	}
+	controlPlane := map[string]string{ControlPlaneTaint: ""}
+	worker := map[string]string{WorkerNodeLabel: ""}
+	client := fake.NewSimpleClientset(
+		node("control-plane", controlPlane, corev1.Taint{Key: ControlPlaneTaint, Effect: corev1.TaintEffectNoSchedule}),
+		node("worker", worker),
+		node("kind", controlPlane),
+	)
+
+	require.Equal(t, []string{"worker"}, AdvertiseFakeGPUs(t, client, 4, WorkerNodeLabel))
+	require.Equal(t, []string{"kind", "worker"}, AdvertiseFakeGPUs(t, client, 4, ""))
+
+	advertised, err := client.CoreV1().Nodes().Get(context.Background(), "kind", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, resource.MustParse("4"), advertised.Status.Capacity[FakeGPUResource])
+
+	RemoveFakeGPUs(t, client, []string{"kind"})
+	advertised, err = client.CoreV1().Nodes().Get(context.Background(), "kind", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotContains(t, advertised.Status.Capacity, corev1.ResourceName(FakeGPUResource))
+}
+
+func TestDefaultStorageClass(t *testing.T) {
+	class := func(name string, annotations map[string]string) *storagev1.StorageClass {
+		return &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations}}
+	}
+
+	_, err := DefaultStorageClass(fake.NewSimpleClientset(class("nfs-csi", nil)))
+	require.Error(t, err)
+
+	name, err := DefaultStorageClass(fake.NewSimpleClientset(class("nfs-csi", nil), class("standard", map[string]string{"storageclass.kubernetes.io/is-default-class": "true"})))
+	require.NoError(t, err)
+	require.Equal(t, "standard", name)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// CAKey is the key of the CA bundle in the ConfigMaps created for model servers, as in kube-root-ca.crt
+	CAKey = "ca.crt"
+	// TrustedCABundleLabel makes the cluster network operator inject the trusted CA bundle of the cluster, the proxy
+	// CAs included, in a ConfigMap under TrustedCABundleKey
+	TrustedCABundleLabel = "config.openshift.io/inject-trusted-cabundle"
+	TrustedCABundleKey   = "ca-bundle.crt"
+)
+
+var (
+	// KubeRootCA is the ConfigMap of the cluster CA Kubernetes publishes in every namespace
+	KubeRootCA = CASource{ConfigMap: "kube-root-ca.crt", Key: CAKey}
+	// ServiceCA is the ConfigMap of the service CA OpenShift publishes in every namespace, which signs the service
+	// serving certificates
+	ServiceCA = CASource{ConfigMap: "openshift-service-ca.crt", Key: "service-ca.crt"}
+)
+
+// CASource is the ConfigMap and key of the CA bundle a model server secret refers to
+type CASource struct {
+	ConfigMap string
+	Key       string
+}
+
+// ParseCAPEM checks a PEM bundle only holds certificates, at least one, and returns them
+func ParseCAPEM(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected %s block in the CA bundle, only certificates are allowed", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate in the CA bundle: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM certificate in the CA bundle")
+	}
+	return certs, nil
+}
+
+// CAPEMFromEnv returns the CA bundle of a model server role from <ROLE>_CA_PEM, or from the file named by
+// <ROLE>_CA_FILE, and nil when neither is set
+func CAPEMFromEnv(role string) ([]byte, error) {
+	prefix := strings.ToUpper(role) + "_CA_"
+	if value := os.Getenv(prefix + "PEM"); value != "" {
+		return []byte(value), nil
+	}
+	if path := os.Getenv(prefix + "FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %sFILE: %w", prefix, err)
+		}
+		return data, nil
+	}
+	return nil, nil
+}
+
+// CreateCAConfigMap stores a PEM CA bundle in a ConfigMap with a generated name under CAKey
+func CreateCAConfigMap(t *testing.T, client kubernetes.Interface, namespace, role string, data []byte) CASource {
+	_, err := ParseCAPEM(data)
+	require.NoError(t, err, "Invalid %s CA bundle", role)
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: GenerateName(role + "-ca")},
+		Data:       map[string]string{CAKey: string(data)},
+	}
+	created, err := client.CoreV1().ConfigMaps(namespace).Create(context.Background(), configMap, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create the %s CA ConfigMap", role)
+	return CASource{ConfigMap: created.Name, Key: CAKey}
+}
+
+// CreateTrustedCABundle creates a ConfigMap with a generated name for the cluster network operator to inject the
+// trusted CA bundle in, and waits for the injection
+func CreateTrustedCABundle(t *testing.T, client kubernetes.Interface, namespace, role string, timeout time.Duration) CASource {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: GenerateName(role + "-trusted-ca"),
+			Labels:       map[string]string{TrustedCABundleLabel: "true"},
+		},
+	}
+	created, err := client.CoreV1().ConfigMaps(namespace).Create(context.Background(), configMap, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create the %s trusted CA bundle ConfigMap", role)
+
+	deadline := time.After(timeout)
+	tick := time.Tick(2 * time.Second)
+	for {
+		configMap, err := client.CoreV1().ConfigMaps(namespace).Get(context.Background(), created.Name, metav1.GetOptions{})
+		require.NoError(t, err, "Failed to retrieve ConfigMap %s", created.Name)
+		if configMap.Data[TrustedCABundleKey] != "" {
+			return CASource{ConfigMap: created.Name, Key: TrustedCABundleKey}
+		}
+
+		select {
+		case <-deadline:
+			_ = client.CoreV1().ConfigMaps(namespace).Delete(context.Background(), created.Name, metav1.DeleteOptions{})
+			require.Failf(t, "Trusted CA bundle not injected", "the cluster network operator did not inject the trusted CA bundle in ConfigMap %s within %s", created.Name, timeout)
+		case <-tick:
+		}
+	}
+}
+
+// ModelServerCA returns the CA source of a model server role: a ConfigMap created from the PEM bundle of
+// CAPEMFromEnv, or the source named by <ROLE>_CA_SOURCE: kube-root-ca for the cluster CA, service-ca for the service
+// CA, trusted-ca-bundle for the trusted CA bundle of the cluster proxy configuration. Without either, the secret has
+// no CA. The returned function removes the ConfigMap created.
+func ModelServerCA(t *testing.T, client kubernetes.Interface, namespace, role string) (CASource, func()) {
+	data, err := CAPEMFromEnv(role)
+	require.NoError(t, err, "Failed to read the %s CA bundle", role)
+	if data != nil {
+		source := CreateCAConfigMap(t, client, namespace, role, data)
+		return source, func() {
+			_ = client.CoreV1().ConfigMaps(namespace).Delete(context.Background(), source.ConfigMap, metav1.DeleteOptions{})
+		}
+	}
+
+	switch value := os.Getenv(strings.ToUpper(role) + "_CA_SOURCE"); value {
+	case "":
+		return CASource{}, func() {}
+	case "kube-root-ca":
+		return KubeRootCA, func() {}
+	case "service-ca":
+		return ServiceCA, func() {}
+	case "trusted-ca-bundle":
+		source := CreateTrustedCABundle(t, client, namespace, role, 2*time.Minute)
+		return source, func() {
+			_ = client.CoreV1().ConfigMaps(namespace).Delete(context.Background(), source.ConfigMap, metav1.DeleteOptions{})
+		}
+	default:
+		require.Failf(t, "Invalid CA source", "%s_CA_SOURCE must be kube-root-ca, service-ca or trusted-ca-bundle, got '%s'", strings.ToUpper(role), value)
+		return CASource{}, nil
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func testCAPEM(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "internal-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestParseCAPEM(t *testing.T) {
+	cert, key := testCAPEM(t)
+	certs, err := ParseCAPEM(append(append([]byte{}, cert...), cert...))
+	require.NoError(t, err)
+	require.Len(t, certs, 2)
+	require.Equal(t, "internal-ca", certs[0].Subject.CommonName)
+
+	_, err = ParseCAPEM(append(append([]byte{}, cert...), key...))
+	require.EqualError(t, err, "unexpected EC PRIVATE KEY block in the CA bundle, only certificates are allowed")
+	_, err = ParseCAPEM([]byte("not a certificate"))
+	require.EqualError(t, err, "no PEM certificate in the CA bundle")
+}
+
+func TestCAPEMFromEnv(t *testing.T) {
+	data, err := CAPEMFromEnv("judge")
+	require.NoError(t, err)
+	require.Nil(t, data)
+
+	cert, _ := testCAPEM(t)
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(path, cert, 0o600))
+	t.Setenv("JUDGE_CA_FILE", path)
+	data, err = CAPEMFromEnv("judge")
+	require.NoError(t, err)
+	require.Equal(t, cert, data)
+
+	t.Setenv("JUDGE_CA_PEM", "inline")
+	data, err = CAPEMFromEnv("judge")
+	require.NoError(t, err)
+	require.Equal(t, []byte("inline"), data)
+}
+
+func TestModelServerCA(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	source, cleanup := ModelServerCA(t, client, "ns", "judge")
+	require.Equal(t, CASource{}, source)
+	cleanup()
+
+	t.Setenv("JUDGE_CA_SOURCE", "kube-root-ca")
+	source, _ = ModelServerCA(t, client, "ns", "judge")
+	require.Equal(t, KubeRootCA, source)
+
+	t.Setenv("JUDGE_CA_SOURCE", "service-ca")
+	source, _ = ModelServerCA(t, client, "ns", "judge")
+	require.Equal(t, CASource{ConfigMap: "openshift-service-ca.crt", Key: "service-ca.crt"}, source)
+
+	// The PEM bundle takes precedence and is stored under CAKey
+	cert, _ := testCAPEM(t)
+	t.Setenv("JUDGE_CA_PEM", string(cert))
+	source, _ = ModelServerCA(t, client, "ns", "judge")
+	require.Equal(t, CAKey, source.Key)
+	configMaps, err := client.CoreV1().ConfigMaps("ns").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, configMaps.Items, 1)
+	require.Equal(t, GenerateName("judge-ca"), configMaps.Items[0].GenerateName)
+	require.Equal(t, string(cert), configMaps.Items[0].Data[CAKey])
+}
+
+func TestCreateTrustedCABundle(t *testing.T) {
+	cert, _ := testCAPEM(t)
+	client := fake.NewSimpleClientset()
+	// Inject the bundle as the cluster network operator does, the fake client does not generate names
+	client.PrependReactor("create", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		configMap := action.(k8stesting.CreateAction).GetObject().(*corev1.ConfigMap)
+		if configMap.Labels[TrustedCABundleLabel] == "true" {
+			configMap.Name = configMap.GenerateName + "abcde"
+			configMap.Data = map[string]string{TrustedCABundleKey: string(cert)}
+		}
+		return false, nil, nil
+	})
+
+	source := CreateTrustedCABundle(t, client, "ns", "teacher", time.Minute)
+	require.Equal(t, CASource{ConfigMap: GenerateName("teacher-trusted-ca") + "abcde", Key: "ca-bundle.crt"}, source)
+}
+
+func TestModelServerSecretCA(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	CreateModelServerSecret(t, client, "ns", "judge-secret", ModelServerSecret{APIToken: "token", Endpoint: "https://judge", ModelName: "judge", CA: KubeRootCA})
+	secret, err := client.CoreV1().Secrets("ns").Get(context.Background(), "judge-secret", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "kube-root-ca.crt", secret.StringData["ca_cert_config_map"])
+	require.Equal(t, "ca.crt", secret.StringData["ca_cert_config_map_key"])
+}
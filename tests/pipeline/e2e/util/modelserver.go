@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// InferenceServiceLabel is the label KServe sets on the predictor pods of an InferenceService
+const InferenceServiceLabel = "serving.kserve.io/inferenceservice"
+
+// ModelServerSecret is the content of a teacher or judge secret, see manifests/README.md
+type ModelServerSecret struct {
+	APIToken  string
+	Endpoint  string
+	ModelName string
+	// CA is the ConfigMap holding the CA bundle the endpoint is verified with, unset for the default trust
+	CA CASource
+}
+
+// GetModelServerSecret reads a teacher or judge secret
+func GetModelServerSecret(t *testing.T, client kubernetes.Interface, namespace, name string) ModelServerSecret {
+	secret, err := ReadModelServerSecret(client, namespace, name)
+	require.NoError(t, err, "Failed to retrieve model server secret")
+	return secret
+}
+
+// ReadModelServerSecret reads a teacher or judge secret, returning errors for callers outside of the test goroutine
+func ReadModelServerSecret(client kubernetes.Interface, namespace, name string) (ModelServerSecret, error) {
+	secret, err := client.CoreV1().Secrets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return ModelServerSecret{}, err
+	}
+	return ModelServerSecret{
+		APIToken:  string(secret.Data["api_token"]),
+		Endpoint:  string(secret.Data["endpoint"]),
+		ModelName: string(secret.Data["model_name"]),
+		CA: CASource{
+			ConfigMap: string(secret.Data["ca_cert_config_map"]),
+			Key:       string(secret.Data["ca_cert_config_map_key"]),
+		},
+	}, nil
+}
+
+// IsInClusterEndpoint reports whether the endpoint is a cluster-local service address
+func IsInClusterEndpoint(endpoint string) bool {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Hostname() == "" {
+		return false
+	}
+	host := parsed.Hostname()
+	return !strings.Contains(host, ".") || strings.HasSuffix(host, ".svc") || strings.HasSuffix(host, ".svc.cluster.local")
+}
+
+// GetInferenceServicePods lists the predictor pods of an InferenceService, or of all InferenceServices when name is empty
+func GetInferenceServicePods(t *testing.T, client kubernetes.Interface, namespace, name string) []corev1.Pod {
+	selector := InferenceServiceLabel
+	if name != "" {
+		selector = fmt.Sprintf("%s=%s", InferenceServiceLabel, name)
+	}
+	pods, err := client.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: selector})
+	require.NoError(t, err, "Failed to list InferenceService pods")
+	return pods.Items
+}
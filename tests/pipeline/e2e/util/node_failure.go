@@ -0,0 +1,140 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// StopKubeletPod returns a privileged pod stopping the kubelet of the node for the outage, then starting it again.
+// The pod keeps running while the kubelet is stopped, the container runtime is not affected, so the node recovers
+// even when the test is interrupted. The image must provide nsenter.
+func StopKubeletPod(name, node, image string, outage time.Duration) *corev1.Pod {
+	privileged := true
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: corev1.PodSpec{
+			NodeName:      node,
+			HostPID:       true,
+			RestartPolicy: corev1.RestartPolicyNever,
+			Tolerations:   []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+			Containers: []corev1.Container{{
+				Name:  "stop-kubelet",
+				Image: image,
+				Command: []string{"nsenter", "-t", "1", "-m", "-u", "-i", "-n", "-p", "--", "sh", "-c",
+					fmt.Sprintf("systemctl stop kubelet; sleep %d; systemctl start kubelet", int(outage.Seconds()))},
+				SecurityContext: &corev1.SecurityContext{Privileged: &privileged},
+			}},
+		},
+	}
+}
+
+// WaitForNodeReady waits until the Ready condition of the node is the given state
+func WaitForNodeReady(client kubernetes.Interface, node string, ready bool, timeout time.Duration) error {
+	deadline := time.After(timeout)
+	tick := time.Tick(10 * time.Second)
+	for {
+		select {
+		case <-deadline:
+			return fmt.Errorf("node %s was not ready=%t within %s", node, ready, timeout)
+		case <-tick:
+			current, err := client.CoreV1().Nodes().Get(context.Background(), node, metav1.GetOptions{})
+			if err != nil {
+				continue
+			}
+			if NodeReady(*current) == ready {
+				return nil
+			}
+		}
+	}
+}
+
+// NodeReady reports whether the Ready condition of the node is true
+func NodeReady(node corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// PodReplacement is a pod of the run created after the loss of a node
+type PodReplacement struct {
+	Pod   string
+	Node  string
+	Delay time.Duration
+}
+
+// PodReplacements returns the pods created after the node was stopped, in creation order, with the delay of their
+// creation after the stop
+func PodReplacements(pods []corev1.Pod, stopped time.Time) []PodReplacement {
+	var replacements []PodReplacement
+	for _, pod := range pods {
+		if created := pod.CreationTimestamp.Time; created.After(stopped) {
+			replacements = append(replacements, PodReplacement{Pod: pod.Name, Node: pod.Spec.NodeName, Delay: created.Sub(stopped)})
+		}
+	}
+	sort.Slice(replacements, func(i, j int) bool { return replacements[i].Delay < replacements[j].Delay })
+	return replacements
+}
+
+// NodeFailureReport is the blast radius of the loss of a node during a run
+type NodeFailureReport struct {
+	Node   string
+	Outage time.Duration
+	// NotReadyAfter is the time the node took to be reported NotReady after its kubelet was stopped
+	NotReadyAfter time.Duration
+	// LostPods were running on the node when it was stopped
+	LostPods     []string
+	Replacements []PodReplacement
+	RunState     string
+	RunDuration  time.Duration
+	// Baseline is the duration of an undisrupted run, zero when unknown
+	Baseline time.Duration
+}
+
+// RenderNodeFailureReport renders the report as markdown
+func RenderNodeFailureReport(report NodeFailureReport) string {
+	var b strings.Builder
+	b.WriteString("# Node failure\n\n")
+	fmt.Fprintf(&b, "Kubelet of node %s stopped for %s, reported NotReady after %s.\n\n", report.Node, report.Outage, report.NotReadyAfter.Round(time.Second))
+	fmt.Fprintf(&b, "Run ended %s after %s", report.RunState, report.RunDuration.Round(time.Second))
+	if report.Baseline > 0 {
+		fmt.Fprintf(&b, ", %s more than the %s baseline", (report.RunDuration - report.Baseline).Round(time.Second), report.Baseline)
+	}
+	b.WriteString(".\n\n## Pods running on the node\n\n")
+	for _, pod := range report.LostPods {
+		fmt.Fprintf(&b, "- %s\n", pod)
+	}
+	b.WriteString("\n## Pods created after the stop\n\n")
+	if len(report.Replacements) == 0 {
+		b.WriteString("None, no pod was rescheduled.\n")
+		return b.String()
+	}
+	b.WriteString("| Pod | Node | Created after |\n")
+	b.WriteString("|---|---|---|\n")
+	for _, replacement := range report.Replacements {
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", replacement.Pod, replacement.Node, replacement.Delay.Round(time.Second))
+	}
+	return b.String()
+}
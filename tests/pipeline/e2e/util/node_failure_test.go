@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNodeFailureReport(t *testing.T) {
+	stopped := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	pod := func(name, node string, created time.Time) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)}, Spec: corev1.PodSpec{NodeName: node}}
+	}
+	replacements := PodReplacements([]corev1.Pod{
+		pod("sdg", "gpu-1", stopped.Add(-time.Hour)),
+		pod("train-phase-1-worker-0", "gpu-2", stopped.Add(6*time.Minute)),
+		pod("train-phase-1-master-0", "gpu-3", stopped.Add(5*time.Minute)),
+	}, stopped)
+	require.Equal(t, []PodReplacement{
+		{Pod: "train-phase-1-master-0", Node: "gpu-3", Delay: 5 * time.Minute},
+		{Pod: "train-phase-1-worker-0", Node: "gpu-2", Delay: 6 * time.Minute},
+	}, replacements)
+
+	report := RenderNodeFailureReport(NodeFailureReport{
+		Node:          "gpu-1",
+		Outage:        10 * time.Minute,
+		NotReadyAfter: 42 * time.Second,
+		LostPods:      []string{"train-phase-1-master-0"},
+		Replacements:  replacements,
+		RunState:      "SUCCEEDED",
+		RunDuration:   3 * time.Hour,
+		Baseline:      2*time.Hour + 30*time.Minute,
+	})
+	require.Contains(t, report, "reported NotReady after 42s")
+	require.Contains(t, report, "Run ended SUCCEEDED after 3h0m0s, 30m0s more than the 2h30m0s baseline.")
+	require.Contains(t, report, "| train-phase-1-master-0 | gpu-3 | 5m0s |")
+
+	require.Contains(t, RenderNodeFailureReport(NodeFailureReport{RunState: "FAILED"}), "None, no pod was rescheduled.")
+}
+
+func TestNodeReady(t *testing.T) {
+	node := func(status corev1.ConditionStatus) corev1.Node {
+		return corev1.Node{Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}}}}
+	}
+	require.True(t, NodeReady(node(corev1.ConditionTrue)))
+	require.False(t, NodeReady(node(corev1.ConditionUnknown)))
+	require.False(t, NodeReady(corev1.Node{}))
+}
@@ -0,0 +1,289 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	SinkSlack     = "slack"
+	SinkTeams     = "teams"
+	SinkPagerDuty = "pagerduty"
+	SinkEmail     = "email"
+)
+
+const (
+	// NotifyOnFailure sends the notifications of failed runs only, the default
+	NotifyOnFailure = "failure"
+	// NotifyAlways sends the notifications of every run
+	NotifyAlways = "always"
+)
+
+// PagerDutyEventsURL is the endpoint of the PagerDuty Events API v2
+const PagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// sinkSecretEnv is the environment variable holding the webhook URL, routing key or SMTP password of each sink
+// when the notification does not name one
+var sinkSecretEnv = map[string]string{
+	SinkSlack:     "SLACK_WEBHOOK_URL",
+	SinkTeams:     "TEAMS_WEBHOOK_URL",
+	SinkPagerDuty: "PAGERDUTY_ROUTING_KEY",
+	SinkEmail:     "SMTP_PASSWORD",
+}
+
+// Notification is the outcome of a scenario run sent to the notification sinks
+type Notification struct {
+	Scenario  string
+	Succeeded bool
+	Duration  time.Duration
+	// URL links to the job which ran the scenario, empty when unknown
+	URL string
+}
+
+// Title is the one-line summary of the notification
+func (n Notification) Title() string {
+	result := "succeeded"
+	if !n.Succeeded {
+		result = "failed"
+	}
+	return fmt.Sprintf("Scenario %s %s after %s", n.Scenario, result, n.Duration.Round(time.Second))
+}
+
+// Text is the body of the notification
+func (n Notification) Text() string {
+	text := n.Title()
+	if n.URL != "" {
+		text += "\n" + n.URL
+	}
+	return text
+}
+
+// NotificationSink delivers the notifications of the runs where a team monitors them
+type NotificationSink interface {
+	Name() string
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// ScenarioNotification is a sink a scenario notifies of its runs
+type ScenarioNotification struct {
+	Sink string `yaml:"sink"`
+	// On is NotifyOnFailure or NotifyAlways
+	On string `yaml:"on"`
+	// To are the recipients of the email sink
+	To []string `yaml:"to"`
+	// SecretEnv replaces the environment variable of sinkSecretEnv, e.g. to notify the Slack channel of a team
+	SecretEnv string `yaml:"secret_env"`
+}
+
+// Validate returns the problems of the notification the schema cannot check
+func (n ScenarioNotification) Validate() []string {
+	var problems []string
+	if n.Sink == SinkEmail && len(n.To) == 0 {
+		problems = append(problems, "the email sink needs recipients in 'to'")
+	}
+	if n.Sink != SinkEmail && len(n.To) > 0 {
+		problems = append(problems, fmt.Sprintf("'to' is not supported by the %s sink", n.Sink))
+	}
+	return problems
+}
+
+// Applies tells whether a run with the outcome is notified
+func (n ScenarioNotification) Applies(succeeded bool) bool {
+	return !succeeded || n.On == NotifyAlways
+}
+
+// NewNotificationSink creates the sink of a notification, reading its secret from the environment through getenv.
+// The email sink also reads SMTP_ADDRESS (host:port), SMTP_FROM and SMTP_USERNAME.
+func NewNotificationSink(notification ScenarioNotification, getenv func(string) string) (NotificationSink, error) {
+	env := notification.SecretEnv
+	if env == "" {
+		env = sinkSecretEnv[notification.Sink]
+	}
+	secret := getenv(env)
+	client := &http.Client{Timeout: 30 * time.Second}
+	switch notification.Sink {
+	case SinkSlack, SinkTeams, SinkPagerDuty:
+		if secret == "" {
+			return nil, fmt.Errorf("the %s sink needs %s", notification.Sink, env)
+		}
+		if notification.Sink == SinkSlack {
+			return &SlackSink{WebhookURL: secret, Client: client}, nil
+		}
+		if notification.Sink == SinkTeams {
+			return &TeamsSink{WebhookURL: secret, Client: client}, nil
+		}
+		return &PagerDutySink{RoutingKey: secret, URL: PagerDutyEventsURL, Client: client}, nil
+	case SinkEmail:
+		sink := &EmailSink{Address: getenv("SMTP_ADDRESS"), From: getenv("SMTP_FROM"), Username: getenv("SMTP_USERNAME"), Password: secret, To: notification.To}
+		if sink.Address == "" || sink.From == "" {
+			return nil, fmt.Errorf("the email sink needs SMTP_ADDRESS and SMTP_FROM")
+		}
+		return sink, nil
+	}
+	return nil, fmt.Errorf("unknown notification sink '%s'", notification.Sink)
+}
+
+// SlackSink posts the notifications to a Slack incoming webhook
+type SlackSink struct {
+	WebhookURL string
+	Client     *http.Client
+}
+
+func (s *SlackSink) Name() string { return SinkSlack }
+
+func (s *SlackSink) Notify(ctx context.Context, notification Notification) error {
+	icon := ":white_check_mark:"
+	if !notification.Succeeded {
+		icon = ":x:"
+	}
+	return postJSON(ctx, s.Client, s.WebhookURL, map[string]interface{}{"text": icon + " " + notification.Text()})
+}
+
+// TeamsSink posts the notifications to a Microsoft Teams incoming webhook as message cards
+type TeamsSink struct {
+	WebhookURL string
+	Client     *http.Client
+}
+
+func (s *TeamsSink) Name() string { return SinkTeams }
+
+func (s *TeamsSink) Notify(ctx context.Context, notification Notification) error {
+	color := "2EB886"
+	if !notification.Succeeded {
+		color = "D00000"
+	}
+	card := map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    notification.Title(),
+		"themeColor": color,
+		"title":      notification.Title(),
+		"text":       notification.Scenario,
+	}
+	if notification.URL != "" {
+		card["potentialAction"] = []interface{}{map[string]interface{}{
+			"@type":   "OpenUri",
+			"name":    "Open the job",
+			"targets": []interface{}{map[string]interface{}{"os": "default", "uri": notification.URL}},
+		}}
+	}
+	return postJSON(ctx, s.Client, s.WebhookURL, card)
+}
+
+// PagerDutySink sends the notifications as events of the PagerDuty Events API v2. A failure triggers an incident
+// keyed by the scenario, which the next successful run of the scenario resolves.
+type PagerDutySink struct {
+	RoutingKey string
+	URL        string
+	Client     *http.Client
+}
+
+func (s *PagerDutySink) Name() string { return SinkPagerDuty }
+
+func (s *PagerDutySink) Notify(ctx context.Context, notification Notification) error {
+	event := map[string]interface{}{
+		"routing_key":  s.RoutingKey,
+		"event_action": "trigger",
+		"dedup_key":    "ilab-e2e/" + notification.Scenario,
+		"payload": map[string]interface{}{
+			"summary":  notification.Title(),
+			"source":   "ilab-on-ocp e2e",
+			"severity": "error",
+			"custom_details": map[string]interface{}{
+				"scenario": notification.Scenario,
+				"duration": notification.Duration.Round(time.Second).String(),
+			},
+		},
+	}
+	if notification.Succeeded {
+		event = map[string]interface{}{"routing_key": s.RoutingKey, "event_action": "resolve", "dedup_key": event["dedup_key"]}
+	}
+	if notification.URL != "" && !notification.Succeeded {
+		event["links"] = []interface{}{map[string]interface{}{"href": notification.URL, "text": "Job"}}
+	}
+	return postJSON(ctx, s.Client, s.URL, event)
+}
+
+// EmailSink mails the notifications through an SMTP server, authenticating with PLAIN when Username is set
+type EmailSink struct {
+	Address  string
+	From     string
+	Username string
+	Password string
+	To       []string
+	// send replaces smtp.SendMail in tests
+	send func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func (s *EmailSink) Name() string { return SinkEmail }
+
+func (s *EmailSink) Notify(ctx context.Context, notification Notification) error {
+	var auth smtp.Auth
+	if s.Username != "" {
+		host, _, err := net.SplitHostPort(s.Address)
+		if err != nil {
+			return fmt.Errorf("invalid SMTP address %s: %w", s.Address, err)
+		}
+		auth = smtp.PlainAuth("", s.Username, s.Password, host)
+	}
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		s.From, strings.Join(s.To, ", "), notification.Title(), strings.ReplaceAll(notification.Text(), "\n", "\r\n"))
+	send := s.send
+	if send == nil {
+		send = smtp.SendMail
+	}
+	if err := send(s.Address, auth, s.From, s.To, []byte(message)); err != nil {
+		return fmt.Errorf("failed to send the email: %w", err)
+	}
+	return nil
+}
+
+func postJSON(ctx context.Context, client *http.Client, endpoint string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("invalid notification URL")
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := client.Do(request)
+	if err != nil {
+		// The URL of a webhook is its secret, keep it out of the logs
+		var urlError *url.Error
+		if errors.As(err, &urlError) {
+			return fmt.Errorf("failed to post the notification: %w", urlError.Err)
+		}
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode/100 != 2 {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("notification rejected with status %d: %s", response.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
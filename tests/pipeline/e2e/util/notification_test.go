@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationSinks(t *testing.T) {
+	var received []map[string]interface{}
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		received = append(received, body)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("invalid_token"))
+	}))
+	defer server.Close()
+
+	env := map[string]string{
+		"SLACK_WEBHOOK_URL":     server.URL + "/slack",
+		"QE_TEAMS_WEBHOOK":      server.URL + "/teams",
+		"PAGERDUTY_ROUTING_KEY": "R0UT1NG",
+		"SMTP_ADDRESS":          "smtp.example.com:587",
+		"SMTP_FROM":             "ilab-e2e@example.com",
+		"SMTP_USERNAME":         "ilab-e2e",
+		"SMTP_PASSWORD":         "secret",
+	}
+	getenv := func(name string) string { return env[name] }
+	failed := Notification{Scenario: "baseline", Duration: 3*time.Hour + 1500*time.Millisecond, URL: "https://ci.example.com/job/42"}
+	require.Equal(t, "Scenario baseline failed after 3h0m2s", failed.Title())
+
+	slack, err := NewNotificationSink(ScenarioNotification{Sink: SinkSlack}, getenv)
+	require.NoError(t, err)
+	require.NoError(t, slack.Notify(context.Background(), failed))
+	require.Equal(t, ":x: Scenario baseline failed after 3h0m2s\nhttps://ci.example.com/job/42", received[0]["text"])
+
+	teams, err := NewNotificationSink(ScenarioNotification{Sink: SinkTeams, SecretEnv: "QE_TEAMS_WEBHOOK"}, getenv)
+	require.NoError(t, err)
+	require.NoError(t, teams.Notify(context.Background(), failed))
+	require.Equal(t, "MessageCard", received[1]["@type"])
+	require.Equal(t, "D00000", received[1]["themeColor"])
+	require.Len(t, received[1]["potentialAction"], 1)
+
+	pagerDuty, err := NewNotificationSink(ScenarioNotification{Sink: SinkPagerDuty}, getenv)
+	require.NoError(t, err)
+	pagerDuty.(*PagerDutySink).URL = server.URL + "/enqueue"
+	require.NoError(t, pagerDuty.Notify(context.Background(), failed))
+	require.Equal(t, "trigger", received[2]["event_action"])
+	require.Equal(t, "R0UT1NG", received[2]["routing_key"])
+	require.Equal(t, "ilab-e2e/baseline", received[2]["dedup_key"])
+	require.Equal(t, "error", received[2]["payload"].(map[string]interface{})["severity"])
+	succeeded := failed
+	succeeded.Succeeded = true
+	require.NoError(t, pagerDuty.Notify(context.Background(), succeeded))
+	require.Equal(t, map[string]interface{}{"routing_key": "R0UT1NG", "event_action": "resolve", "dedup_key": "ilab-e2e/baseline"}, received[3])
+
+	status = http.StatusForbidden
+	require.EqualError(t, slack.Notify(context.Background(), failed), "notification rejected with status 403: invalid_token")
+	// The webhook URL is a secret, the errors leave it out
+	unreachable := &SlackSink{WebhookURL: "http://127.0.0.1:1/services/T000/B000/XXXX", Client: http.DefaultClient}
+	err = unreachable.Notify(context.Background(), failed)
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "XXXX")
+
+	email, err := NewNotificationSink(ScenarioNotification{Sink: SinkEmail, To: []string{"qe@example.com", "dev@example.com"}}, getenv)
+	require.NoError(t, err)
+	var sent struct {
+		addr, from string
+		to         []string
+		auth       smtp.Auth
+		message    string
+	}
+	email.(*EmailSink).send = func(addr string, auth smtp.Auth, from string, to []string, message []byte) error {
+		sent.addr, sent.auth, sent.from, sent.to, sent.message = addr, auth, from, to, string(message)
+		return nil
+	}
+	require.NoError(t, email.Notify(context.Background(), failed))
+	require.Equal(t, "smtp.example.com:587", sent.addr)
+	require.NotNil(t, sent.auth)
+	require.Equal(t, []string{"qe@example.com", "dev@example.com"}, sent.to)
+	require.Equal(t, "From: ilab-e2e@example.com\r\nTo: qe@example.com, dev@example.com\r\nSubject: Scenario baseline failed after 3h0m2s\r\n"+
+		"Content-Type: text/plain; charset=UTF-8\r\n\r\nScenario baseline failed after 3h0m2s\r\nhttps://ci.example.com/job/42\r\n", sent.message)
+
+	_, err = NewNotificationSink(ScenarioNotification{Sink: SinkTeams}, getenv)
+	require.EqualError(t, err, "the teams sink needs TEAMS_WEBHOOK_URL")
+	delete(env, "SMTP_FROM")
+	_, err = NewNotificationSink(ScenarioNotification{Sink: SinkEmail, To: []string{"qe@example.com"}}, getenv)
+	require.EqualError(t, err, "the email sink needs SMTP_ADDRESS and SMTP_FROM")
+
+	require.True(t, ScenarioNotification{Sink: SinkSlack}.Applies(false))
+	require.False(t, ScenarioNotification{Sink: SinkSlack}.Applies(true))
+	require.True(t, ScenarioNotification{Sink: SinkSlack, On: NotifyAlways}.Applies(true))
+	require.Equal(t, []string{"the email sink needs recipients in 'to'"}, ScenarioNotification{Sink: SinkEmail}.Validate())
+	require.Equal(t, []string{"'to' is not supported by the slack sink"}, ScenarioNotification{Sink: SinkSlack, To: []string{"qe@example.com"}}.Validate())
+}
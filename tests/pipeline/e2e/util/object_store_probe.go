@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/objectprobe"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// RunObjectStoreProbe runs object-store-probe from image in a pod of the namespace, so the throughput between the
+// cluster and the bucket of the object store settings is measured with a probe object of sizeMiB MiB. The settings
+// are passed to the pod in a secret deleted afterwards, with the keys of a data connection.
+func RunObjectStoreProbe(t *testing.T, client kubernetes.Interface, namespace, image string, config ObjectStoreConfig, sizeMiB int, timeout time.Duration) (objectprobe.Result, error) {
+	if config.UsesOIDC() {
+		return objectprobe.Result{}, fmt.Errorf("the object store probe does not support OIDC authentication")
+	}
+	ctx := context.Background()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: GenerateName("object-store-probe")},
+		StringData: map[string]string{
+			ObjectStoreEndpointKey:  config.Endpoint,
+			ObjectStoreBucketKey:    config.Bucket,
+			ObjectStoreRegionKey:    config.Region,
+			ObjectStoreAccessKeyKey: config.AccessKey,
+			ObjectStoreSecretKeyKey: config.SecretKey,
+		},
+	}
+	secrets := client.CoreV1().Secrets(namespace)
+	secret, err := secrets.Create(ctx, secret, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create object store probe secret")
+	defer func() { _ = secrets.Delete(context.Background(), secret.Name, metav1.DeleteOptions{}) }()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: GenerateName("object-store-probe")},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{{
+				Name:    "probe",
+				Image:   image,
+				Command: []string{"object-store-probe", "-size", strconv.Itoa(sizeMiB)},
+				EnvFrom: []corev1.EnvFromSource{{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name}}}},
+			}},
+		},
+	}
+	pods := client.CoreV1().Pods(namespace)
+	created, err := pods.Create(ctx, pod, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create object store probe pod")
+	defer func() { _ = pods.Delete(context.Background(), created.Name, metav1.DeleteOptions{}) }()
+
+	deadline := time.After(timeout)
+	tick := time.Tick(10 * time.Second)
+	for {
+		select {
+		case <-deadline:
+			return objectprobe.Result{}, fmt.Errorf("object store probe pod %s did not complete within %s", created.Name, timeout)
+		case <-tick:
+			current, err := pods.Get(ctx, created.Name, metav1.GetOptions{})
+			require.NoError(t, err, "Failed to retrieve object store probe pod")
+			if current.Status.Phase != corev1.PodSucceeded && current.Status.Phase != corev1.PodFailed {
+				continue
+			}
+
+			logs, err := pods.GetLogs(created.Name, &corev1.PodLogOptions{}).DoRaw(ctx)
+			require.NoError(t, err, "Failed to retrieve object store probe logs")
+			if current.Status.Phase == corev1.PodFailed {
+				return objectprobe.Result{}, fmt.Errorf("object store probe failed, output:\n%s", logs)
+			}
+			return ParseObjectStoreProbe(logs)
+		}
+	}
+}
+
+// ParseObjectStoreProbe reads the result printed by object-store-probe on the last line of its output
+func ParseObjectStoreProbe(logs []byte) (objectprobe.Result, error) {
+	var result objectprobe.Result
+	lines := bytes.Split(bytes.TrimSpace(logs), []byte("\n"))
+	if err := json.Unmarshal(lines[len(lines)-1], &result); err != nil {
+		return result, fmt.Errorf("failed to parse object store probe output: %w", err)
+	}
+	return result, nil
+}
+
+// RenderObjectStoreThroughput renders the throughput measured between the cluster and the bucket as markdown
+func RenderObjectStoreThroughput(result objectprobe.Result, bucket string) string {
+	var report strings.Builder
+	report.WriteString("# Object store throughput\n\n")
+	fmt.Fprintf(&report, "Measured from inside the cluster with a %d MiB object in bucket %s.\n\n", result.Bytes>>20, bucket)
+	report.WriteString("| Direction | Throughput | Duration |\n")
+	report.WriteString("|---|---|---|\n")
+	fmt.Fprintf(&report, "| Upload | %.1f MiB/s | %s |\n", result.UploadMiBps, result.Upload.Round(time.Millisecond))
+	fmt.Fprintf(&report, "| Download | %.1f MiB/s | %s |\n", result.DownloadMiBps, result.Download.Round(time.Millisecond))
+	return report.String()
+}
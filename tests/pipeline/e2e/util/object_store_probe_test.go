@@ -0,0 +1,39 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/objectprobe"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectStoreProbeReport(t *testing.T) {
+	logs := `2025/03/01 12:00:00 Probing bucket runs at s3.example.com with 256 MiB
+{"bytes":268435456,"upload":8000000000,"download":2000000000,"upload_mibps":32,"download_mibps":128}
+`
+	result, err := ParseObjectStoreProbe([]byte(logs))
+	require.NoError(t, err)
+	require.Equal(t, objectprobe.Result{Bytes: 256 << 20, Upload: 8 * time.Second, Download: 2 * time.Second, UploadMiBps: 32, DownloadMiBps: 128}, result)
+
+	_, err = ParseObjectStoreProbe([]byte("2025/03/01 12:00:00 AWS_S3_ENDPOINT and AWS_S3_BUCKET must be set\n"))
+	require.Error(t, err)
+
+	report := RenderObjectStoreThroughput(result, "runs")
+	require.Contains(t, report, "with a 256 MiB object in bucket runs")
+	require.Contains(t, report, "| Upload | 32.0 MiB/s | 8s |")
+	require.Contains(t, report, "| Download | 128.0 MiB/s | 2s |")
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Keys of the object store connection secret, following the RHOAI data connection layout
+const (
+	ObjectStoreEndpointKey         = "AWS_S3_ENDPOINT"
+	ObjectStoreBucketKey           = "AWS_S3_BUCKET"
+	ObjectStoreRegionKey           = "AWS_DEFAULT_REGION"
+	ObjectStoreAccessKeyKey        = "AWS_ACCESS_KEY_ID"
+	ObjectStoreSecretKeyKey        = "AWS_SECRET_ACCESS_KEY"
+	ObjectStoreOIDCTokenKey        = "OIDC_TOKEN"
+	ObjectStoreOIDCTokenURLKey     = "OIDC_TOKEN_URL"
+	ObjectStoreOIDCClientIDKey     = "OIDC_CLIENT_ID"
+	ObjectStoreOIDCClientSecretKey = "OIDC_CLIENT_SECRET"
+)
+
+// ObjectStoreConfig describes how to reach the bucket holding the run artifacts.
+// Either an access/secret key pair or OIDC settings are used for authentication.
+type ObjectStoreConfig struct {
+	Endpoint  string
+	Bucket    string
+	Region    string
+	AccessKey string
+	SecretKey string
+
+	// OIDCToken is a pre-issued bearer token, used as is
+	OIDCToken string
+	// OIDCTokenURL, OIDCClientID and OIDCClientSecret acquire and refresh bearer tokens with the client credentials grant
+	OIDCTokenURL     string
+	OIDCClientID     string
+	OIDCClientSecret string
+}
+
+// UsesOIDC reports whether the object store is authenticated with bearer tokens
+func (c ObjectStoreConfig) UsesOIDC() bool {
+	return c.OIDCToken != "" || c.OIDCTokenURL != ""
+}
+
+// ObjectStoreConfigFromEnv reads the object store settings from environment variables named after the secret keys
+func ObjectStoreConfigFromEnv() ObjectStoreConfig {
+	return objectStoreConfigFromMap(func(key string) string { return os.Getenv(key) })
+}
+
+// ObjectStoreConfigFromSecret reads the object store settings from a data connection secret
+func ObjectStoreConfigFromSecret(t *testing.T, client kubernetes.Interface, namespace, name string) ObjectStoreConfig {
+	secret, err := client.CoreV1().Secrets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	require.NoError(t, err, "Failed to retrieve object store secret")
+	return objectStoreConfigFromMap(func(key string) string { return string(secret.Data[key]) })
+}
+
+func objectStoreConfigFromMap(get func(string) string) ObjectStoreConfig {
+	return ObjectStoreConfig{
+		Endpoint:         get(ObjectStoreEndpointKey),
+		Bucket:           get(ObjectStoreBucketKey),
+		Region:           get(ObjectStoreRegionKey),
+		AccessKey:        get(ObjectStoreAccessKeyKey),
+		SecretKey:        get(ObjectStoreSecretKeyKey),
+		OIDCToken:        get(ObjectStoreOIDCTokenKey),
+		OIDCTokenURL:     get(ObjectStoreOIDCTokenURLKey),
+		OIDCClientID:     get(ObjectStoreOIDCClientIDKey),
+		OIDCClientSecret: get(ObjectStoreOIDCClientSecretKey),
+	}
+}
+
+// OIDCTokenSource returns a token source for the OIDC settings, refreshing tokens shortly before they expire
+func (c ObjectStoreConfig) OIDCTokenSource() oauth2.TokenSource {
+	if c.OIDCTokenURL == "" {
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: c.OIDCToken, TokenType: "Bearer"})
+	}
+	config := clientcredentials.Config{
+		ClientID:     c.OIDCClientID,
+		ClientSecret: c.OIDCClientSecret,
+		TokenURL:     c.OIDCTokenURL,
+	}
+	return config.TokenSource(context.Background())
+}
+
+// NewObjectStoreClient creates an S3 client for the configured endpoint
+func NewObjectStoreClient(config ObjectStoreConfig) (*minio.Client, error) {
+	if config.Endpoint == "" || config.Bucket == "" {
+		return nil, fmt.Errorf("object store endpoint and bucket must be set")
+	}
+	rawEndpoint := config.Endpoint
+	if !strings.Contains(rawEndpoint, "://") {
+		rawEndpoint = "https://" + rawEndpoint
+	}
+	endpoint, err := url.Parse(rawEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid object store endpoint '%s': %w", config.Endpoint, err)
+	}
+
+	options := &minio.Options{
+		Secure: endpoint.Scheme != "http",
+		Region: config.Region,
+	}
+	if config.UsesOIDC() {
+		// Requests are left unsigned, the gateway in front of the store authenticates the bearer token
+		options.Creds = credentials.NewStaticV4("", "", "")
+		options.Transport = &oauth2.Transport{
+			Source: oauth2.ReuseTokenSource(nil, config.OIDCTokenSource()),
+			Base:   http.DefaultTransport,
+		}
+	} else {
+		options.Creds = credentials.NewStaticV4(config.AccessKey, config.SecretKey, "")
+	}
+
+	return minio.New(endpoint.Host, options)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectStoreClientOIDC(t *testing.T) {
+	tokenRequests := 0
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "client_credentials", r.Form.Get("grant_type"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"oidc-token","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+
+	var authorization []string
+	storeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = append(authorization, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer storeServer.Close()
+
+	client, err := NewObjectStoreClient(ObjectStoreConfig{
+		Endpoint:         storeServer.URL,
+		Bucket:           "bucket",
+		Region:           "us-east-1",
+		OIDCTokenURL:     tokenServer.URL,
+		OIDCClientID:     "client",
+		OIDCClientSecret: "secret",
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		exists, err := client.BucketExists(context.Background(), "bucket")
+		require.NoError(t, err)
+		require.True(t, exists)
+	}
+	require.Equal(t, []string{"Bearer oidc-token", "Bearer oidc-token"}, authorization)
+	require.Equal(t, 1, tokenRequests, "token should be reused until it expires")
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// decimalNumber matches the numbers given as strings the rules accept, which are valid resource quantities as well
+var decimalNumber = regexp.MustCompile(`^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)([eE][+-]?[0-9]+)?$`)
+
+// scpLikeGitURL is the git@host:path form of the SSH URLs of the repositories, as is_ssh of sdg/components.py
+var scpLikeGitURL = regexp.MustCompile(`^git@[\w.-]+:.+`)
+
+// quantityExponent captures the decimal exponent of a resource quantity
+var quantityExponent = regexp.MustCompile(`[eE][+-]?0*([0-9]+)$`)
+
+// paramRule returns why a parameter value cannot run, or an empty string
+type paramRule func(value interface{}) string
+
+// pipelineParamRules are the constraints on the pipeline parameters the type check of the compiled pipeline does not
+// express, which a run would otherwise only hit deep in a task, e.g. a PyTorchJob requesting -1 GPU
+var pipelineParamRules = map[string]paramRule{
+	"eval_gpu_identifier":                qualifiedName,
+	"eval_judge_secret":                  objectName(false),
+	"final_eval_batch_size":              autoOrPositiveInteger,
+	"final_eval_few_shots":               integerAtLeast(0),
+	"final_eval_max_workers":             autoOrPositiveInteger,
+	"k8s_storage_class_name":             objectName(false),
+	"k8s_storage_size":                   positiveQuantity,
+	"mt_bench_max_workers":               autoOrPositiveInteger,
+	"output_model_registry_api_url":      urlWithScheme(true, "http", "https"),
+	"output_oci_model_uri":               ociReference,
+	"output_oci_registry_secret":         objectName(true),
+	"sdg_base_model":                     urlWithScheme(false, "s3", "oci"),
+	"sdg_batch_size":                     integerAtLeast(1),
+	"sdg_max_batch_len":                  integerAtLeast(1),
+	"sdg_num_workers":                    integerAtLeast(1),
+	"sdg_repo_pr":                        integerAtLeast(0),
+	"sdg_repo_secret":                    objectName(false),
+	"sdg_repo_url":                       gitRepoURL,
+	"sdg_sample_size":                    fraction,
+	"sdg_scale_factor":                   integerAtLeast(1),
+	"sdg_teacher_secret":                 objectName(false),
+	"train_cpu_per_worker":               positiveQuantity,
+	"train_effective_batch_size_phase_1": integerAtLeast(1),
+	"train_effective_batch_size_phase_2": integerAtLeast(1),
+	"train_gpu_identifier":               qualifiedName,
+	"train_gpu_per_worker":               integerAtLeast(1),
+	"train_learning_rate_phase_1":        positiveNumber,
+	"train_learning_rate_phase_2":        positiveNumber,
+	"train_max_batch_len":                integerAtLeast(1),
+	"train_memory_per_worker":            positiveQuantity,
+	"train_num_epochs_phase_1":           integerAtLeast(1),
+	"train_num_epochs_phase_2":           integerAtLeast(1),
+	"train_num_warmup_steps_phase_1":     integerAtLeast(0),
+	"train_num_warmup_steps_phase_2":     integerAtLeast(0),
+	"train_num_workers":                  integerAtLeast(1),
+	"train_save_samples":                 integerAtLeast(0),
+	"train_seed":                         integerAtLeast(0),
+}
+
+// ValidatePipelineParams reports the parameter values a run would fail on, e.g. negative GPU counts, unparsable
+// resource quantities or URLs without a scheme, one problem naming the parameter per invalid value. Parameters
+// without a rule are left to the type check of CheckParameterContract.
+func ValidatePipelineParams(params map[string]interface{}) []string {
+	var problems []string
+	for name, value := range params {
+		rule, ok := pipelineParamRules[name]
+		if !ok {
+			continue
+		}
+		if problem := rule(value); problem != "" {
+			problems = append(problems, fmt.Sprintf("parameter '%s' %s, got %s", name, problem, describeParamValue(value)))
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+// ValidateWorkflowRunConfig reports the settings of a run the backends would render into an invalid run: the
+// parameter values ValidatePipelineParams rejects and a negative timeout
+func ValidateWorkflowRunConfig(config WorkflowRunConfig) []string {
+	problems := ValidatePipelineParams(config.Params)
+	if config.Timeout < 0 {
+		problems = append(problems, fmt.Sprintf("timeout must not be negative, got %s", config.Timeout))
+	}
+	return problems
+}
+
+func describeParamValue(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprintf("%v (%T)", value, value)
+}
+
+// paramNumber returns the value of a numeric parameter. Numbers given as strings, as the workflow backends render
+// them, are parsed.
+func paramNumber(value interface{}) (float64, bool) {
+	var number float64
+	switch v := value.(type) {
+	case int:
+		number = float64(v)
+	case int64:
+		number = float64(v)
+	case float64:
+		number = v
+	case string:
+		if !decimalNumber.MatchString(v) {
+			return 0, false
+		}
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, false
+		}
+		number = parsed
+	default:
+		return 0, false
+	}
+	return number, !math.IsNaN(number) && !math.IsInf(number, 0)
+}
+
+func integerAtLeast(min int) paramRule {
+	return func(value interface{}) string {
+		number, ok := paramNumber(value)
+		if !ok || number != math.Trunc(number) || number < float64(min) {
+			return fmt.Sprintf("must be an integer of at least %d", min)
+		}
+		return ""
+	}
+}
+
+func positiveNumber(value interface{}) string {
+	if number, ok := paramNumber(value); !ok || number <= 0 {
+		return "must be a positive number"
+	}
+	return ""
+}
+
+func fraction(value interface{}) string {
+	if number, ok := paramNumber(value); !ok || number <= 0 || number > 1 {
+		return "must be a fraction in (0, 1]"
+	}
+	return ""
+}
+
+func autoOrPositiveInteger(value interface{}) string {
+	if value == "auto" {
+		return ""
+	}
+	if integerAtLeast(1)(value) != "" {
+		return "must be 'auto' or a positive integer"
+	}
+	return ""
+}
+
+func positiveQuantity(value interface{}) string {
+	s, ok := value.(string)
+	if !ok {
+		if _, numeric := paramNumber(value); !numeric {
+			return "must be a resource quantity"
+		}
+		s = fmt.Sprint(value)
+	}
+	// Parsing does not return on extreme exponents, e.g. 1e-999999999
+	if exponent := quantityExponent.FindStringSubmatch(s); len(s) > 64 || exponent != nil && len(exponent[1]) > 2 {
+		return "must be a resource quantity, e.g. 100Gi"
+	}
+	quantity, err := resource.ParseQuantity(s)
+	if err != nil {
+		return "must be a resource quantity, e.g. 100Gi"
+	}
+	if quantity.Sign() <= 0 {
+		return "must be a positive resource quantity"
+	}
+	return ""
+}
+
+func qualifiedName(value interface{}) string {
+	s, ok := value.(string)
+	if !ok || len(validation.IsQualifiedName(s)) > 0 {
+		return "must be a resource name, e.g. nvidia.com/gpu"
+	}
+	return ""
+}
+
+// objectName accepts the name of a Kubernetes object, e.g. a Secret, and an empty string when optional
+func objectName(optional bool) paramRule {
+	return func(value interface{}) string {
+		s, ok := value.(string)
+		if ok && (s == "" && optional || s != "" && len(validation.IsDNS1123Subdomain(s)) == 0) {
+			return ""
+		}
+		return "must be the name of a Kubernetes object"
+	}
+}
+
+// urlWithScheme accepts a URL with a host and one of the schemes, and an empty string when optional
+func urlWithScheme(optional bool, schemes ...string) paramRule {
+	return func(value interface{}) string {
+		s, ok := value.(string)
+		if ok && s == "" && optional {
+			return ""
+		}
+		problem := fmt.Sprintf("must be a %s URL", strings.Join(schemes, ", "))
+		if !ok {
+			return problem
+		}
+		parsed, err := url.Parse(s)
+		if err != nil || parsed.Host == "" {
+			return problem
+		}
+		for _, scheme := range schemes {
+			if parsed.Scheme == scheme {
+				return ""
+			}
+		}
+		return problem
+	}
+}
+
+// gitRepoURL accepts a http, https, ssh or git URL with a host, or a git@host:path SSH URL
+func gitRepoURL(value interface{}) string {
+	if s, ok := value.(string); ok && scpLikeGitURL.MatchString(s) {
+		return ""
+	}
+	if problem := urlWithScheme(false, "http", "https", "ssh", "git")(value); problem != "" {
+		return problem + " or git@host:path"
+	}
+	return ""
+}
+
+// ociReference accepts an image reference with a registry host, prefixed by oci:// or not, and an empty string as
+// the parameter is optional
+func ociReference(value interface{}) string {
+	s, ok := value.(string)
+	if ok && s == "" {
+		return ""
+	}
+	reference := strings.TrimPrefix(s, "oci://")
+	registry, repository, found := strings.Cut(reference, "/")
+	if !ok || !found || repository == "" || strings.ContainsAny(reference, " \t\n") ||
+		len(validation.IsDNS1123Subdomain(strings.ToLower(strings.Split(registry, ":")[0]))) > 0 {
+		return "must be an OCI image reference, e.g. oci://quay.io/org/model:tag"
+	}
+	return ""
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestValidatePipelineParams(t *testing.T) {
+	require.Empty(t, ValidatePipelineParams(testWorkflowRunConfig().Params))
+	require.Empty(t, ValidatePipelineParams(map[string]interface{}{
+		"sdg_base_model":                 "oci://registry.redhat.io/rhelai1/modelcar-granite-8b-code-instruct:latest",
+		"sdg_sample_size":                1,
+		"k8s_storage_size":               "100Gi",
+		"train_cpu_per_worker":           "500m",
+		"final_eval_batch_size":          "8",
+		"output_model_registry_api_url":  "",
+		"output_oci_model_uri":           "quay.io/org/model:1",
+		"sdg_repo_url":                   "git@github.com:org/taxonomy.git",
+		"train_gpu_identifier":           "nvidia.com/gpu",
+		"train_num_warmup_steps_phase_1": 0,
+		"unknown_parameter":              -1,
+	}))
+
+	require.Equal(t, []string{
+		"parameter 'final_eval_max_workers' must be 'auto' or a positive integer, got \"many\"",
+		"parameter 'k8s_storage_size' must be a resource quantity, e.g. 100Gi, got \"100 GB\"",
+		"parameter 'sdg_base_model' must be a s3, oci URL, got \"granite-7b-starter\"",
+		"parameter 'sdg_repo_url' must be a http, https, ssh, git URL or git@host:path, got \"github.com/instructlab/taxonomy\"",
+		"parameter 'sdg_sample_size' must be a fraction in (0, 1], got 2 (int)",
+		"parameter 'train_effective_batch_size_phase_1' must be an integer of at least 1, got 1.5 (float64)",
+		"parameter 'train_gpu_per_worker' must be an integer of at least 1, got -1 (int)",
+		"parameter 'train_learning_rate_phase_1' must be a positive number, got 0 (int)",
+		"parameter 'train_memory_per_worker' must be a positive resource quantity, got \"-8Gi\"",
+		"parameter 'train_seed' must be an integer of at least 0, got true (bool)",
+	}, ValidatePipelineParams(map[string]interface{}{
+		"final_eval_max_workers":             "many",
+		"k8s_storage_size":                   "100 GB",
+		"sdg_base_model":                     "granite-7b-starter",
+		"sdg_repo_url":                       "github.com/instructlab/taxonomy",
+		"sdg_sample_size":                    2,
+		"train_effective_batch_size_phase_1": 1.5,
+		"train_gpu_per_worker":               -1,
+		"train_learning_rate_phase_1":        0,
+		"train_memory_per_worker":            "-8Gi",
+		"train_seed":                         true,
+	}))
+
+	config := testWorkflowRunConfig()
+	config.Timeout = -time.Hour
+	require.Equal(t, []string{"timeout must not be negative, got -1h0m0s"}, ValidateWorkflowRunConfig(config))
+}
+
+// FuzzWorkflowRunParams renders runs with a parameter replaced by arbitrary values and checks each is either
+// rejected by the validation, naming the parameter, or renders into a run whose GPU requests are valid quantities
+func FuzzWorkflowRunParams(f *testing.F) {
+	for _, seed := range []struct {
+		name, value string
+		timeout     int64
+	}{
+		{"train_gpu_per_worker", "-1", 0},
+		{"train_gpu_per_worker", "1.5", 0},
+		{"train_gpu_per_worker", "1e3", 0},
+		{"train_gpu_per_worker", " 2", 0},
+		{"train_gpu_per_worker", "0x10", 0},
+		{"train_learning_rate_phase_1", "NaN", 0},
+		{"train_learning_rate_phase_1", "-Inf", 0},
+		{"sdg_scale_factor", "", 0},
+		{"k8s_storage_size", "1e-999999999", 0},
+		{"train_memory_per_worker", "8E", 0},
+		{"sdg_repo_url", "https://", 0},
+		{"sdg_repo_url", "://github.com", 0},
+		{"sdg_repo_url", "http://[::1", 0},
+		{"sdg_repo_url", "git@github.com:org/taxonomy.git", 0},
+		{"sdg_repo_url", "git@:taxonomy", 0},
+		{"mt_bench_max_workers", "AUTO", 0},
+		{"train_num_epochs_phase_1", "1", -int64(time.Minute)},
+		{"train_max_batch_len", "20000", int64(8 * time.Hour)},
+	} {
+		f.Add(seed.name, seed.value, seed.timeout)
+	}
+
+	workflow, err := LoadWorkflow("../resources/workflow_tasks.yaml")
+	require.NoError(f, err)
+	f.Fuzz(func(t *testing.T, name, value string, timeout int64) {
+		if _, ok := pipelineParamRules[name]; !ok {
+			name = "train_gpu_per_worker"
+		}
+		config := testWorkflowRunConfig()
+		config.Params[name] = value
+		config.Timeout = time.Duration(timeout)
+
+		problems := ValidateWorkflowRunConfig(config)
+		run, err := TektonBackend{}.NewRun(workflow, config)
+		if len(problems) > 0 {
+			require.Error(t, err)
+			require.True(t, strings.HasPrefix(err.Error(), "invalid run ilab-x1: "), err.Error())
+			for _, problem := range problems {
+				require.True(t, strings.Contains(problem, "'"+name+"'") || strings.HasPrefix(problem, "timeout"), problem)
+			}
+			return
+		}
+		require.NoError(t, err)
+		tasks, _, _ := unstructured.NestedSlice(run.Object, "spec", "pipelineSpec", "tasks")
+		for _, task := range tasks {
+			steps, _, _ := unstructured.NestedSlice(task.(map[string]interface{}), "taskSpec", "steps")
+			limits, _, _ := unstructured.NestedStringMap(steps[0].(map[string]interface{}), "computeResources", "limits")
+			for _, limit := range limits {
+				quantity, err := resource.ParseQuantity(limit)
+				require.NoError(t, err)
+				require.Positive(t, quantity.Sign())
+			}
+		}
+	})
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// WaitForRunningTrainingPod waits until a PyTorchJob pod of the run is running and returns it
+func WaitForRunningTrainingPod(t *testing.T, client kubernetes.Interface, namespace, runID string, timeout time.Duration) (corev1.Pod, error) {
+	deadline := time.After(timeout)
+	tick := time.Tick(10 * time.Second)
+	for {
+		select {
+		case <-deadline:
+			return corev1.Pod{}, fmt.Errorf("no training pod of run %s was running within %s", runID, timeout)
+		case <-tick:
+			for _, pod := range GetTrainingPods(t, client, namespace, runID) {
+				if pod.Status.Phase == corev1.PodRunning {
+					return pod, nil
+				}
+			}
+		}
+	}
+}
+
+// ListTrainingJobPods lists the pods of a PyTorchJob
+func ListTrainingJobPods(client kubernetes.Interface, namespace, job string) ([]corev1.Pod, error) {
+	pods, err := client.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", TrainingJobNameLabel, job),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the pods of PyTorchJob %s: %w", job, err)
+	}
+	return pods.Items, nil
+}
+
+// WaitForTrainingJobPodsDeleted waits until the pods of a suspended PyTorchJob are deleted, freeing their GPUs
+func WaitForTrainingJobPodsDeleted(client kubernetes.Interface, namespace, job string, timeout time.Duration) error {
+	deadline := time.After(timeout)
+	tick := time.Tick(10 * time.Second)
+	for {
+		select {
+		case <-deadline:
+			return fmt.Errorf("the pods of PyTorchJob %s were not deleted within %s", job, timeout)
+		case <-tick:
+			pods, err := ListTrainingJobPods(client, namespace, job)
+			if err != nil {
+				return err
+			}
+			if len(pods) == 0 {
+				return nil
+			}
+		}
+	}
+}
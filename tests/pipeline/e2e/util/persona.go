@@ -0,0 +1,183 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/teamrbac"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// NamespaceAdminRole is the aggregated cluster role granting namespace-admin permissions when bound in a namespace
+const NamespaceAdminRole = "admin"
+
+// NamespaceAdminPersona is a service account holding namespace-admin permissions only, standing in for a customer
+// who is not cluster-admin
+type NamespaceAdminPersona struct {
+	Namespace      string
+	ServiceAccount string
+	Token          string
+}
+
+// AccessCheck is an action the namespace-admin persona must, or must not, be allowed to perform
+type AccessCheck struct {
+	Attributes authorizationv1.ResourceAttributes
+	Allowed    bool
+}
+
+// NamespaceAdminAccessChecks lists the namespace-scoped actions the persona needs and the cluster-scoped actions it
+// must be denied
+func NamespaceAdminAccessChecks(namespace string) []AccessCheck {
+	return []AccessCheck{
+		{authorizationv1.ResourceAttributes{Namespace: namespace, Verb: "create", Resource: "pods"}, true},
+		{authorizationv1.ResourceAttributes{Namespace: namespace, Verb: "create", Resource: "secrets"}, true},
+		{authorizationv1.ResourceAttributes{Namespace: namespace, Verb: "create", Group: "rbac.authorization.k8s.io", Resource: "rolebindings"}, true},
+		{authorizationv1.ResourceAttributes{Namespace: namespace, Verb: "create", Group: "kubeflow.org", Resource: "pytorchjobs"}, true},
+		{authorizationv1.ResourceAttributes{Verb: "create", Group: "rbac.authorization.k8s.io", Resource: "clusterroles"}, false},
+		{authorizationv1.ResourceAttributes{Verb: "create", Group: "rbac.authorization.k8s.io", Resource: "clusterrolebindings"}, false},
+		{authorizationv1.ResourceAttributes{Verb: "list", Resource: "nodes"}, false},
+	}
+}
+
+// TeamAccessChecks lists every namespaced action the team RBAC grants, derived from its policy rules, and the actions
+// beyond running workflows it must be denied
+func TeamAccessChecks(namespace string) []AccessCheck {
+	var checks []AccessCheck
+	for _, rule := range teamrbac.PolicyRules {
+		for _, group := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				resource, subresource, _ := strings.Cut(resource, "/")
+				for _, verb := range rule.Verbs {
+					checks = append(checks, AccessCheck{authorizationv1.ResourceAttributes{
+						Namespace: namespace, Verb: verb, Group: group, Resource: resource, Subresource: subresource,
+					}, true})
+				}
+			}
+		}
+	}
+	return append(checks,
+		AccessCheck{authorizationv1.ResourceAttributes{Namespace: namespace, Verb: "create", Resource: "pods"}, false},
+		AccessCheck{authorizationv1.ResourceAttributes{Namespace: namespace, Verb: "create", Group: "rbac.authorization.k8s.io", Resource: "rolebindings"}, false},
+		AccessCheck{authorizationv1.ResourceAttributes{Namespace: namespace, Verb: "delete", Group: "kubeflow.org", Resource: "pytorchjobs"}, false},
+		AccessCheck{authorizationv1.ResourceAttributes{Verb: "list", Resource: "nodes"}, false},
+	)
+}
+
+// CreateTeamPersona applies the team RBAC generated for the namespace and requests a token for the service account
+// of the team, valid for the given duration
+func CreateTeamPersona(t *testing.T, client kubernetes.Interface, namespace, team string, duration time.Duration) NamespaceAdminPersona {
+	ctx := context.Background()
+	manifest, err := teamrbac.Objects(teamrbac.Options{Team: team, Namespace: namespace})
+	require.NoError(t, err, "Failed to generate the team RBAC")
+	_, err = client.CoreV1().ServiceAccounts(namespace).Create(ctx, manifest.ServiceAccount, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create the team service account")
+	_, err = client.RbacV1().Roles(namespace).Create(ctx, manifest.Role, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create the team role")
+	_, err = client.RbacV1().RoleBindings(namespace).Create(ctx, manifest.RoleBinding, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to bind the team role")
+
+	seconds := int64(duration.Seconds())
+	token, err := client.CoreV1().ServiceAccounts(namespace).CreateToken(ctx, manifest.ServiceAccount.Name, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &seconds},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to request a token for the team service account")
+
+	return NamespaceAdminPersona{Namespace: namespace, ServiceAccount: manifest.ServiceAccount.Name, Token: token.Status.Token}
+}
+
+// DeleteTeamPersona removes the service account, role and role binding of the team
+func DeleteTeamPersona(t *testing.T, client kubernetes.Interface, persona NamespaceAdminPersona) {
+	ctx := context.Background()
+	_ = client.RbacV1().RoleBindings(persona.Namespace).Delete(ctx, persona.ServiceAccount, metav1.DeleteOptions{})
+	_ = client.RbacV1().Roles(persona.Namespace).Delete(ctx, persona.ServiceAccount, metav1.DeleteOptions{})
+	_ = client.CoreV1().ServiceAccounts(persona.Namespace).Delete(ctx, persona.ServiceAccount, metav1.DeleteOptions{})
+}
+
+// CreateNamespaceAdminPersona creates a service account bound to the namespace-admin role in the namespace and
+// requests a token for it, valid for the given duration
+func CreateNamespaceAdminPersona(t *testing.T, client kubernetes.Interface, namespace string, duration time.Duration) NamespaceAdminPersona {
+	ctx := context.Background()
+	account, err := client.CoreV1().ServiceAccounts(namespace).Create(ctx, &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: GenerateName("namespace-admin")},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create the namespace-admin service account")
+
+	_, err = client.RbacV1().RoleBindings(namespace).Create(ctx, &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: account.Name},
+		RoleRef:    rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: NamespaceAdminRole},
+		Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Name: account.Name, Namespace: namespace}},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to bind the namespace-admin role")
+
+	seconds := int64(duration.Seconds())
+	token, err := client.CoreV1().ServiceAccounts(namespace).CreateToken(ctx, account.Name, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &seconds},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to request a token for the namespace-admin service account")
+
+	return NamespaceAdminPersona{Namespace: namespace, ServiceAccount: account.Name, Token: token.Status.Token}
+}
+
+// DeleteNamespaceAdminPersona removes the service account and role binding of the persona
+func DeleteNamespaceAdminPersona(t *testing.T, client kubernetes.Interface, persona NamespaceAdminPersona) {
+	ctx := context.Background()
+	_ = client.RbacV1().RoleBindings(persona.Namespace).Delete(ctx, persona.ServiceAccount, metav1.DeleteOptions{})
+	_ = client.CoreV1().ServiceAccounts(persona.Namespace).Delete(ctx, persona.ServiceAccount, metav1.DeleteOptions{})
+}
+
+// PersonaKubeconfig renders a kubeconfig authenticating as the persona against the cluster of the given config
+func PersonaKubeconfig(config *rest.Config, persona NamespaceAdminPersona) ([]byte, error) {
+	kubeconfig := clientcmdapi.NewConfig()
+	kubeconfig.Clusters["cluster"] = &clientcmdapi.Cluster{
+		Server:                   config.Host,
+		CertificateAuthority:     config.TLSClientConfig.CAFile,
+		CertificateAuthorityData: config.TLSClientConfig.CAData,
+		InsecureSkipTLSVerify:    config.TLSClientConfig.Insecure,
+	}
+	kubeconfig.AuthInfos[persona.ServiceAccount] = &clientcmdapi.AuthInfo{Token: persona.Token}
+	kubeconfig.Contexts["persona"] = &clientcmdapi.Context{Cluster: "cluster", AuthInfo: persona.ServiceAccount, Namespace: persona.Namespace}
+	kubeconfig.CurrentContext = "persona"
+	return clientcmd.Write(*kubeconfig)
+}
+
+// CheckAccess reviews the access checks as the user of the client and returns the unexpected outcomes
+func CheckAccess(t *testing.T, client kubernetes.Interface, checks []AccessCheck) []string {
+	var problems []string
+	for _, check := range checks {
+		attributes := check.Attributes
+		review, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(context.Background(), &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &attributes},
+		}, metav1.CreateOptions{})
+		require.NoError(t, err, "Failed to review access")
+		if review.Status.Allowed != check.Allowed {
+			problems = append(problems, fmt.Sprintf("%s %s/%s in namespace '%s': allowed %t, expected %t",
+				attributes.Verb, attributes.Group, attributes.Resource, attributes.Namespace, review.Status.Allowed, check.Allowed))
+		}
+	}
+	return problems
+}
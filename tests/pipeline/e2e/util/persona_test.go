@@ -0,0 +1,56 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func TestPersonaKubeconfig(t *testing.T) {
+	admin := &rest.Config{Host: "https://api.cluster.example.com:6443", BearerToken: "cluster-admin-token"}
+	admin.TLSClientConfig.CAData = []byte("ca")
+	persona := NamespaceAdminPersona{Namespace: "ilab", ServiceAccount: "ilab-test-namespace-admin-x7k2p", Token: "persona-token"}
+
+	data, err := PersonaKubeconfig(admin, persona)
+	require.NoError(t, err)
+	config, err := clientcmd.NewClientConfigFromBytes(data)
+	require.NoError(t, err)
+
+	restConfig, err := config.ClientConfig()
+	require.NoError(t, err)
+	require.Equal(t, "https://api.cluster.example.com:6443", restConfig.Host)
+	require.Equal(t, "persona-token", restConfig.BearerToken)
+	require.Equal(t, []byte("ca"), restConfig.TLSClientConfig.CAData)
+
+	namespace, _, err := config.Namespace()
+	require.NoError(t, err)
+	require.Equal(t, "ilab", namespace)
+}
+
+func TestTeamAccessChecks(t *testing.T) {
+	checks := TeamAccessChecks("ilab")
+	require.Contains(t, checks, AccessCheck{authorizationv1.ResourceAttributes{Namespace: "ilab", Verb: "get", Resource: "pods", Subresource: "log"}, true})
+	require.Contains(t, checks, AccessCheck{authorizationv1.ResourceAttributes{Namespace: "ilab", Verb: "create", Resource: "secrets"}, true})
+	require.Contains(t, checks, AccessCheck{authorizationv1.ResourceAttributes{Namespace: "ilab", Verb: "create", Resource: "pods"}, false})
+	for _, check := range checks {
+		if check.Allowed {
+			require.Equal(t, "ilab", check.Attributes.Namespace)
+		}
+	}
+}
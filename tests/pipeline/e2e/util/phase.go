@@ -0,0 +1,165 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// PhaseAnnotation holds the phase a pipeline run is currently in
+	PhaseAnnotation = "ilab.opendatahub.io/phase"
+	// ProgressAnnotation holds the approximate completion percentage of a pipeline run
+	ProgressAnnotation = "ilab.opendatahub.io/progress"
+)
+
+// RunPhase is the current phase of a pipeline run and its approximate completion percentage
+type RunPhase struct {
+	Phase   string
+	Percent int
+}
+
+// PipelinePhases lists the phases of the pipeline in execution order, training is reported once per training phase
+var PipelinePhases = []string{"prerequisites", "sdg", "data-processing", "model-to-pvc", "training-phase-1", "training-phase-2", "mt-bench", "final-eval", "metrics-report", "upload-model"}
+
+var functionPhases = map[string]string{
+	"test_model_connection":      "prerequisites",
+	"test_model_registry":        "prerequisites",
+	"test_oci_model":             "prerequisites",
+	"test_sdg_params":            "prerequisites",
+	"test_training_operator":     "prerequisites",
+	"sdg_op":                     "sdg",
+	"data_processing_op":         "data-processing",
+	"model_to_pvc_op":            "model-to-pvc",
+	"run_mt_bench_op":            "mt-bench",
+	"run_final_eval_op":          "final-eval",
+	"generate_metrics_report_op": "metrics-report",
+	"upload_model_op":            "upload-model",
+}
+
+// TaskPhase returns the pipeline phase a task pod belongs to, or an empty string for tasks outside the tracked phases
+func TaskPhase(task TaskPod) string {
+	if task.Function == "pytorch_job_launcher_op" {
+		return fmt.Sprintf("training-phase-%v", task.Parameters["phase_num"])
+	}
+	return functionPhases[task.Function]
+}
+
+// CurrentRunPhase returns the latest phase reached by the given task pods, the percentage counts the phases before it
+func CurrentRunPhase(tasks []TaskPod) RunPhase {
+	latest := -1
+	for _, task := range tasks {
+		if i := pipelinePhaseIndex(TaskPhase(task)); i > latest {
+			latest = i
+		}
+	}
+	if latest < 0 {
+		return RunPhase{Phase: "pending"}
+	}
+	return RunPhase{Phase: PipelinePhases[latest], Percent: latest * 100 / len(PipelinePhases)}
+}
+
+// ReadRunPhase reads the phase annotations of a pod, reporting false when the pod was not annotated
+func ReadRunPhase(pod corev1.Pod) (RunPhase, bool) {
+	phase, ok := pod.Annotations[PhaseAnnotation]
+	if !ok {
+		return RunPhase{}, false
+	}
+	percent, _ := strconv.Atoi(pod.Annotations[ProgressAnnotation])
+	return RunPhase{Phase: phase, Percent: percent}, true
+}
+
+// AnnotateRunPhase computes the current phase of a pipeline run and annotates every active pod of the run with it,
+// so that the pod YAML alone tells where the run is
+func AnnotateRunPhase(client kubernetes.Interface, namespace, runID string) (RunPhase, error) {
+	ctx := context.Background()
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", RunIDLabel, runID),
+	})
+	if err != nil {
+		return RunPhase{}, fmt.Errorf("failed to list pipeline run pods: %w", err)
+	}
+
+	var tasks []TaskPod
+	for _, pod := range pods.Items {
+		if task, ok := ParseTaskPod(pod); ok {
+			tasks = append(tasks, task)
+		}
+	}
+	phase := CurrentRunPhase(tasks)
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]string{
+			PhaseAnnotation:    phase.Phase,
+			ProgressAnnotation: strconv.Itoa(phase.Percent),
+		}},
+	})
+	if err != nil {
+		return phase, err
+	}
+	return phase, patchActivePods(client, namespace, pods.Items, patch)
+}
+
+// annotateActivePods applies the annotation patch to the pods matching the label selector which have not terminated
+func annotateActivePods(client kubernetes.Interface, namespace, selector string, patch []byte) error {
+	pods, err := client.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+	return patchActivePods(client, namespace, pods.Items, patch)
+}
+
+func patchActivePods(client kubernetes.Interface, namespace string, pods []corev1.Pod, patch []byte) error {
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if _, err := client.CoreV1().Pods(namespace).Patch(context.Background(), pod.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return fmt.Errorf("failed to annotate pod %s: %w", pod.Name, err)
+		}
+	}
+	return nil
+}
+
+// WatchRunPhase annotates the pods of a pipeline run with its phase at every interval until the returned stop
+// function is called. Errors are passed to report, as the annotations are informational only.
+func WatchRunPhase(client kubernetes.Interface, namespace, runID string, interval time.Duration, report func(RunPhase, error)) (stop func()) {
+	return watchRunPhase(clock.RealClock{}, client, namespace, runID, interval, report)
+}
+
+func watchRunPhase(clk clock.Clock, client kubernetes.Interface, namespace, runID string, interval time.Duration, report func(RunPhase, error)) (stop func()) {
+	done := make(chan struct{})
+	tick := clk.Tick(interval)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-tick:
+				report(AnnotateRunPhase(client, namespace, runID))
+			}
+		}
+	}()
+	return func() { close(done) }
+}
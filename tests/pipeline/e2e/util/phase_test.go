@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestCurrentRunPhase(t *testing.T) {
+	require.Equal(t, RunPhase{Phase: "pending"}, CurrentRunPhase(nil))
+
+	tasks := []TaskPod{
+		{Function: "test_model_connection"},
+		{Function: "sdg_op"},
+		{Function: "pytorch_job_launcher_op", Parameters: map[string]interface{}{"phase_num": 1.0}},
+	}
+	require.Equal(t, RunPhase{Phase: "training-phase-1", Percent: 40}, CurrentRunPhase(tasks))
+
+	tasks = append(tasks, TaskPod{Function: "pytorch_job_launcher_op", Parameters: map[string]interface{}{"phase_num": 2.0}})
+	require.Equal(t, RunPhase{Phase: "training-phase-2", Percent: 50}, CurrentRunPhase(tasks))
+}
+
+func TestReadRunPhase(t *testing.T) {
+	_, ok := ReadRunPhase(corev1.Pod{})
+	require.False(t, ok)
+
+	pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		PhaseAnnotation:    "final-eval",
+		ProgressAnnotation: "70",
+	}}}
+	phase, ok := ReadRunPhase(pod)
+	require.True(t, ok)
+	require.Equal(t, RunPhase{Phase: "final-eval", Percent: 70}, phase)
+}
+
+func TestWatchRunPhase(t *testing.T) {
+	clock := testingclock.NewFakeClock(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	reports := make(chan RunPhase, 10)
+	stop := watchRunPhase(clock, fake.NewSimpleClientset(), "ns", "run-1", time.Minute, func(phase RunPhase, err error) {
+		require.NoError(t, err)
+		reports <- phase
+	})
+
+	// The phase is only annotated once the interval elapsed
+	clock.Step(30 * time.Second)
+	require.Never(t, func() bool { return len(reports) > 0 }, 50*time.Millisecond, 10*time.Millisecond)
+	clock.Step(30 * time.Second)
+	require.Equal(t, RunPhase{Phase: "pending"}, <-reports)
+
+	stop()
+	clock.Step(time.Minute)
+	require.Never(t, func() bool { return len(reports) > 0 }, 50*time.Millisecond, 10*time.Millisecond)
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// TrainingContainerName is the container of the PyTorchJob pods, named by the Training Operator
+const TrainingContainerName = "pytorch"
+
+// PodExecTimeout is the time a command run in a pod is given to complete
+const PodExecTimeout = 2 * time.Minute
+
+// NvidiaSMIQuery lists the GPUs of a container with their memory and utilization, parsed by ParseNvidiaSMI
+var NvidiaSMIQuery = []string{"nvidia-smi", "--query-gpu=index,name,memory.used,memory.total,utilization.gpu", "--format=csv,noheader,nounits"}
+
+// ExecInPod runs the command in the container of the pod, the first container when empty, and returns its standard
+// output. A command exiting with a non-zero status returns an error with its standard error.
+func ExecInPod(t *testing.T, client kubernetes.Interface, namespace, pod, container string, command ...string) (string, error) {
+	request := client.CoreV1().RESTClient().Post().Resource("pods").Namespace(namespace).Name(pod).SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{Container: container, Command: command, Stdout: true, Stderr: true}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(NewKubeConfig(t), http.MethodPost, request.URL())
+	if err != nil {
+		return "", fmt.Errorf("failed to exec in pod %s: %w", pod, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), PodExecTimeout)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	if err := executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr}); err != nil {
+		return stdout.String(), fmt.Errorf("'%s' failed in pod %s: %w: %s", strings.Join(command, " "), pod, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// ExecInTrainingPod runs the command in the training container of a running PyTorchJob pod of the run, waiting for
+// one within timeout. It returns the pod the command ran in with its standard output.
+func ExecInTrainingPod(t *testing.T, client kubernetes.Interface, namespace, runID string, timeout time.Duration, command ...string) (corev1.Pod, string, error) {
+	pod, err := WaitForRunningTrainingPod(t, client, namespace, runID, timeout)
+	if err != nil {
+		return pod, "", err
+	}
+	output, err := ExecInPod(t, client, namespace, pod.Name, TrainingContainerName, command...)
+	return pod, output, err
+}
+
+// PortForwardToPod forwards a free local port to the port of the pod and returns the local port. The forwarding stops
+// at the end of the test.
+func PortForwardToPod(t *testing.T, client kubernetes.Interface, namespace, pod string, port int) (int, error) {
+	config := NewKubeConfig(t)
+	transport, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return 0, fmt.Errorf("failed to port-forward to pod %s: %w", pod, err)
+	}
+	url := client.CoreV1().RESTClient().Post().Resource("pods").Namespace(namespace).Name(pod).SubResource("portforward").URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	stop, ready := make(chan struct{}), make(chan struct{})
+	forwarder, err := portforward.New(dialer, []string{fmt.Sprintf("0:%d", port)}, stop, ready, io.Discard, io.Discard)
+	if err != nil {
+		return 0, fmt.Errorf("failed to port-forward to pod %s: %w", pod, err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- forwarder.ForwardPorts() }()
+
+	select {
+	case err := <-done:
+		return 0, fmt.Errorf("failed to port-forward to pod %s: %w", pod, err)
+	case <-ready:
+	}
+	t.Cleanup(func() {
+		close(stop)
+		<-done
+	})
+	ports, err := forwarder.GetPorts()
+	if err != nil || len(ports) == 0 {
+		return 0, fmt.Errorf("failed to read the port forwarded to pod %s: %v", pod, err)
+	}
+	return int(ports[0].Local), nil
+}
+
+// GPUState is a GPU listed by nvidia-smi
+type GPUState struct {
+	Index          int
+	Name           string
+	MemoryUsedMiB  int64
+	MemoryTotalMiB int64
+	// Utilization is the percentage of time the GPU was busy over the last sample period
+	Utilization int
+}
+
+// ParseNvidiaSMI parses the output of NvidiaSMIQuery
+func ParseNvidiaSMI(output string) ([]GPUState, error) {
+	var gpus []GPUState
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("unexpected nvidia-smi line '%s', expected 5 fields", line)
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		index, indexErr := strconv.Atoi(fields[0])
+		used, usedErr := strconv.ParseInt(fields[2], 10, 64)
+		total, totalErr := strconv.ParseInt(fields[3], 10, 64)
+		utilization, utilizationErr := strconv.Atoi(fields[4])
+		if indexErr != nil || usedErr != nil || totalErr != nil || utilizationErr != nil {
+			return nil, fmt.Errorf("unexpected nvidia-smi line '%s', expected numbers", line)
+		}
+		gpus = append(gpus, GPUState{Index: index, Name: fields[1], MemoryUsedMiB: used, MemoryTotalMiB: total, Utilization: utilization})
+	}
+	return gpus, nil
+}
+
+// NCCLEnv returns the NCCL_ variables of the output of env
+func NCCLEnv(output string) map[string]string {
+	env := map[string]string{}
+	for _, line := range strings.Split(output, "\n") {
+		name, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if ok && strings.HasPrefix(name, "NCCL_") {
+			env[name] = value
+		}
+	}
+	return env
+}
+
+// ParseEnvList reads comma-separated NAME=VALUE pairs, e.g. NCCL_DEBUG=INFO,NCCL_IB_DISABLE=1
+func ParseEnvList(value string) (map[string]string, error) {
+	env := map[string]string{}
+	for _, field := range strings.Split(value, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid variable '%s', expected NAME=VALUE", field)
+		}
+		env[name] = value
+	}
+	return env, nil
+}
+
+// CheckEnv reports the expected variables missing from env or set to another value, by name
+func CheckEnv(env, expected map[string]string) []string {
+	names := make([]string, 0, len(expected))
+	for name := range expected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var problems []string
+	for _, name := range names {
+		value, ok := env[name]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("%s is not set, expected %s", name, expected[name]))
+		case value != expected[name]:
+			problems = append(problems, fmt.Sprintf("%s is %s, expected %s", name, value, expected[name]))
+		}
+	}
+	return problems
+}
+
+// RenderPodDebug renders the GPUs and NCCL variables seen in a training pod as a Markdown report
+func RenderPodDebug(pod string, gpus []GPUState, nccl map[string]string) string {
+	var report strings.Builder
+	fmt.Fprintf(&report, "# Training pod %s\n\n", pod)
+	report.WriteString("| GPU | Name | Memory used | Utilization |\n")
+	report.WriteString("|---|---|---|---|\n")
+	for _, gpu := range gpus {
+		fmt.Fprintf(&report, "| %d | %s | %d / %d MiB | %d%% |\n", gpu.Index, gpu.Name, gpu.MemoryUsedMiB, gpu.MemoryTotalMiB, gpu.Utilization)
+	}
+	report.WriteString("\n## NCCL environment\n\n")
+	if len(nccl) == 0 {
+		report.WriteString("No NCCL_ variables set.\n")
+		return report.String()
+	}
+	names := make([]string, 0, len(nccl))
+	for name := range nccl {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&report, "- `%s=%s`\n", name, nccl[name])
+	}
+	return report.String()
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNvidiaSMI(t *testing.T) {
+	gpus, err := ParseNvidiaSMI("0, NVIDIA A100-SXM4-80GB, 61234, 81920, 97\n1, NVIDIA A100-SXM4-80GB, 4, 81920, 0\n")
+	require.NoError(t, err)
+	require.Equal(t, []GPUState{
+		{Index: 0, Name: "NVIDIA A100-SXM4-80GB", MemoryUsedMiB: 61234, MemoryTotalMiB: 81920, Utilization: 97},
+		{Index: 1, Name: "NVIDIA A100-SXM4-80GB", MemoryUsedMiB: 4, MemoryTotalMiB: 81920, Utilization: 0},
+	}, gpus)
+
+	gpus, err = ParseNvidiaSMI("")
+	require.NoError(t, err)
+	require.Empty(t, gpus)
+
+	_, err = ParseNvidiaSMI("No devices were found")
+	require.EqualError(t, err, "unexpected nvidia-smi line 'No devices were found', expected 5 fields")
+	_, err = ParseNvidiaSMI("0, NVIDIA A100-SXM4-80GB, [N/A], 81920, 97")
+	require.Error(t, err)
+}
+
+func TestNCCLEnv(t *testing.T) {
+	env := NCCLEnv("PATH=/usr/bin\nNCCL_DEBUG=INFO\nNCCL_SOCKET_IFNAME=eth0\nPET_NCCL_DEBUG=WARN\nNCCL_IB_HCA=mlx5_0=1\n")
+	require.Equal(t, map[string]string{"NCCL_DEBUG": "INFO", "NCCL_SOCKET_IFNAME": "eth0", "NCCL_IB_HCA": "mlx5_0=1"}, env)
+
+	expected, err := ParseEnvList("NCCL_DEBUG=INFO, NCCL_IB_DISABLE=1,NCCL_SOCKET_IFNAME=ib0")
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"NCCL_IB_DISABLE is not set, expected 1",
+		"NCCL_SOCKET_IFNAME is eth0, expected ib0",
+	}, CheckEnv(env, expected))
+
+	_, err = ParseEnvList("NCCL_DEBUG")
+	require.EqualError(t, err, "invalid variable 'NCCL_DEBUG', expected NAME=VALUE")
+}
+
+func TestRenderPodDebug(t *testing.T) {
+	report := RenderPodDebug("train-worker-0", []GPUState{{Index: 0, Name: "NVIDIA L40S", MemoryUsedMiB: 1024, MemoryTotalMiB: 46068, Utilization: 55}}, nil)
+	require.Contains(t, report, "| 0 | NVIDIA L40S | 1024 / 46068 MiB | 55% |")
+	require.Contains(t, report, "No NCCL_ variables set.")
+	report = RenderPodDebug("train-worker-0", nil, map[string]string{"NCCL_DEBUG": "INFO"})
+	require.Contains(t, report, "- `NCCL_DEBUG=INFO`")
+}
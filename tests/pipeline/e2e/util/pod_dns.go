@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// PodDNS is the name resolution injected into the pods the tests create, for model endpoints that only resolve through
+// an internal DNS the cluster DNS does not forward to. The fields are those of a pod spec.
+type PodDNS struct {
+	HostAliases []corev1.HostAlias   `json:"hostAliases,omitempty"`
+	DNSPolicy   corev1.DNSPolicy     `json:"dnsPolicy,omitempty"`
+	DNSConfig   *corev1.PodDNSConfig `json:"dnsConfig,omitempty"`
+}
+
+// LoadPodDNS reads the name resolution of the pods from a YAML file in the pod spec format, rejecting unknown fields
+func LoadPodDNS(path string) (PodDNS, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return PodDNS{}, err
+	}
+	var dns PodDNS
+	if err := yaml.UnmarshalStrict(data, &dns); err != nil {
+		return PodDNS{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if problems := dns.Validate(); len(problems) > 0 {
+		return PodDNS{}, fmt.Errorf("invalid pod DNS settings in %s: %v", path, problems)
+	}
+	return dns, nil
+}
+
+// PodDNSFromEnv reads the name resolution of the pods from the file set in POD_DNS_CONFIG, none when it is not set
+func PodDNSFromEnv() (PodDNS, error) {
+	path := os.Getenv("POD_DNS_CONFIG")
+	if path == "" {
+		return PodDNS{}, nil
+	}
+	return LoadPodDNS(path)
+}
+
+// IsZero is satisfied when no name resolution is injected
+func (d PodDNS) IsZero() bool {
+	return len(d.HostAliases) == 0 && d.DNSPolicy == "" && d.DNSConfig == nil
+}
+
+// Validate returns the settings the API server would reject when creating the pods
+func (d PodDNS) Validate() []string {
+	var problems []string
+	for i, alias := range d.HostAliases {
+		if net.ParseIP(alias.IP) == nil {
+			problems = append(problems, fmt.Sprintf("hostAliases[%d]: invalid IP '%s'", i, alias.IP))
+		}
+		if len(alias.Hostnames) == 0 {
+			problems = append(problems, fmt.Sprintf("hostAliases[%d]: no hostnames", i))
+		}
+	}
+	switch d.DNSPolicy {
+	case "", corev1.DNSClusterFirst, corev1.DNSDefault:
+	case corev1.DNSNone:
+		if d.DNSConfig == nil || len(d.DNSConfig.Nameservers) == 0 {
+			problems = append(problems, "dnsPolicy None needs nameservers in dnsConfig")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("unsupported dnsPolicy '%s'", d.DNSPolicy))
+	}
+	if d.DNSConfig != nil {
+		for _, nameserver := range d.DNSConfig.Nameservers {
+			if net.ParseIP(nameserver) == nil {
+				problems = append(problems, fmt.Sprintf("dnsConfig: invalid nameserver '%s'", nameserver))
+			}
+		}
+	}
+	return problems
+}
+
+// ApplyTo adds the host aliases to a pod spec and sets its DNS policy and config
+func (d PodDNS) ApplyTo(spec *corev1.PodSpec) {
+	spec.HostAliases = append(spec.HostAliases, d.HostAliases...)
+	if d.DNSPolicy != "" {
+		spec.DNSPolicy = d.DNSPolicy
+	}
+	if d.DNSConfig != nil {
+		spec.DNSConfig = d.DNSConfig.DeepCopy()
+	}
+}
+
+// Unstructured returns the settings as the fields of a pod spec in an unstructured object, e.g. a pod template of a
+// workflow engine
+func (d PodDNS) Unstructured() (map[string]interface{}, error) {
+	fields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert the pod DNS settings: %w", err)
+	}
+	return fields, nil
+}
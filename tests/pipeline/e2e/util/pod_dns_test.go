@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestLoadPodDNS(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	dns, err := LoadPodDNS(write("dns.yaml", `
+hostAliases:
+  - ip: 10.0.0.5
+    hostnames: [teacher.corp.internal, judge.corp.internal]
+dnsConfig:
+  nameservers: [10.0.0.53]
+  searches: [corp.internal]
+`))
+	require.NoError(t, err)
+	require.Equal(t, []corev1.HostAlias{{IP: "10.0.0.5", Hostnames: []string{"teacher.corp.internal", "judge.corp.internal"}}}, dns.HostAliases)
+
+	// The host aliases are added to those of the pod, the DNS policy of the pod is kept when not set
+	spec := corev1.PodSpec{DNSPolicy: corev1.DNSClusterFirst, HostAliases: []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"proxy"}}}}
+	dns.ApplyTo(&spec)
+	require.Len(t, spec.HostAliases, 2)
+	require.Equal(t, corev1.DNSClusterFirst, spec.DNSPolicy)
+	require.Equal(t, []string{"10.0.0.53"}, spec.DNSConfig.Nameservers)
+
+	_, err = LoadPodDNS(write("unknown.yaml", "hostAlias: []\n"))
+	require.ErrorContains(t, err, `unknown field "hostAlias"`)
+	_, err = LoadPodDNS(write("invalid.yaml", `
+hostAliases: [{ip: teacher, hostnames: []}]
+dnsPolicy: None
+`))
+	require.ErrorContains(t, err, "hostAliases[0]: invalid IP 'teacher'")
+	require.ErrorContains(t, err, "hostAliases[0]: no hostnames")
+	require.ErrorContains(t, err, "dnsPolicy None needs nameservers in dnsConfig")
+
+	t.Setenv("POD_DNS_CONFIG", "")
+	dns, err = PodDNSFromEnv()
+	require.NoError(t, err)
+	require.True(t, dns.IsZero())
+}
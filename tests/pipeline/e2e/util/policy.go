@@ -0,0 +1,118 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PolicyRules describes the checks applied to every workload created by a pipeline run
+type PolicyRules struct {
+	AllowedRegistries     []string `mapstructure:"allowed_registries"`
+	RequireResourceLimits bool     `mapstructure:"require_resource_limits"`
+	RequiredLabels        []string `mapstructure:"required_labels"`
+	// ArbitraryUID requires the pods of the run, training pods included, to run with the UIDs OpenShift assigns
+	ArbitraryUID bool `mapstructure:"arbitrary_uid"`
+}
+
+// PolicyViolation is a single failed rule for a single resource
+type PolicyViolation struct {
+	Resource string
+	Rule     string
+	Message  string
+}
+
+func (v PolicyViolation) String() string {
+	return fmt.Sprintf("%s: [%s] %s", v.Resource, v.Rule, v.Message)
+}
+
+// LoadPolicyRules reads the policy rules from a YAML file
+func LoadPolicyRules(t *testing.T, path string) PolicyRules {
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig(), "Error loading policy rules")
+
+	var rules PolicyRules
+	require.NoError(t, v.Unmarshal(&rules), "Error parsing policy rules")
+	return rules
+}
+
+// EvaluatePodPolicy returns the violations of the given rules by a single pod
+func EvaluatePodPolicy(rules PolicyRules, pod *corev1.Pod) []PolicyViolation {
+	var violations []PolicyViolation
+	resource := fmt.Sprintf("Pod/%s", pod.Name)
+
+	for _, label := range rules.RequiredLabels {
+		if _, ok := pod.Labels[label]; !ok {
+			violations = append(violations, PolicyViolation{resource, "required-label", fmt.Sprintf("label %q is missing", label)})
+		}
+	}
+
+	containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for _, container := range containers {
+		if len(rules.AllowedRegistries) > 0 && !imageFromAllowedRegistry(container.Image, rules.AllowedRegistries) {
+			violations = append(violations, PolicyViolation{resource, "allowed-registry", fmt.Sprintf("container %q uses image %q from a registry that is not allowed", container.Name, container.Image)})
+		}
+		if rules.RequireResourceLimits {
+			for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+				if _, ok := container.Resources.Limits[name]; !ok {
+					violations = append(violations, PolicyViolation{resource, "resource-limits", fmt.Sprintf("container %q has no %s limit", container.Name, name)})
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// CheckRunPolicies evaluates the policy rules against every pod created by the given pipeline run
+func CheckRunPolicies(t *testing.T, client kubernetes.Interface, namespace, runID string, rules PolicyRules) []PolicyViolation {
+	pods := GetRunPods(t, client, namespace, runID)
+
+	var violations []PolicyViolation
+	for i := range pods {
+		violations = append(violations, EvaluatePodPolicy(rules, &pods[i])...)
+	}
+
+	if rules.ArbitraryUID {
+		ns, err := client.CoreV1().Namespaces().Get(context.Background(), namespace, metav1.GetOptions{})
+		require.NoError(t, err, "Failed to retrieve namespace %s", namespace)
+		uids, groups, err := NamespaceUIDRanges(ns)
+		require.NoError(t, err, "Failed to read the UID ranges of namespace %s", namespace)
+		trainingPods, err := ListTrainingPods(client, namespace, pods)
+		require.NoError(t, err, "Failed to list training pods")
+		pods = append(pods, trainingPods...)
+		for i := range pods {
+			violations = append(violations, CheckArbitraryUID(&pods[i], uids, groups)...)
+		}
+	}
+	return violations
+}
+
+func imageFromAllowedRegistry(image string, registries []string) bool {
+	for _, registry := range registries {
+		if strings.HasPrefix(image, strings.TrimSuffix(registry, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
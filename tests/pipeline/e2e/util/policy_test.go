@@ -0,0 +1,56 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEvaluatePodPolicy(t *testing.T) {
+	rules := PolicyRules{
+		AllowedRegistries:     []string{"quay.io"},
+		RequireResourceLimits: true,
+		RequiredLabels:        []string{RunIDLabel},
+	}
+	limits := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("1"),
+		corev1.ResourceMemory: resource.MustParse("1Gi"),
+	}
+
+	compliant := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "ok", Labels: map[string]string{RunIDLabel: "run"}},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "main", Image: "quay.io/opendatahub/image:latest", Resources: corev1.ResourceRequirements{Limits: limits}},
+		}},
+	}
+	require.Empty(t, EvaluatePodPolicy(rules, compliant))
+
+	nonCompliant := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "bad"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "main", Image: "quay.io.evil.com/image:latest"},
+		}},
+	}
+	violations := EvaluatePodPolicy(rules, nonCompliant)
+	require.Len(t, violations, 4)
+	require.Equal(t, "required-label", violations[0].Rule)
+	require.Equal(t, "allowed-registry", violations[1].Rule)
+	require.Equal(t, "resource-limits", violations[2].Rule)
+	require.Equal(t, "Pod/bad", violations[3].Resource)
+}
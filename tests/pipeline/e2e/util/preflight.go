@@ -0,0 +1,138 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/watcher"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	TrainingOperatorDeployment = "kubeflow-training-operator"
+	PreflightImage             = "quay.io/quay/busybox:latest"
+)
+
+var (
+	CustomResourceDefinitionGVR = schema.GroupVersionResource{
+		Group:    "apiextensions.k8s.io",
+		Version:  "v1",
+		Resource: "customresourcedefinitions",
+	}
+	PyTorchJobGVR = schema.GroupVersionResource{
+		Group:    "kubeflow.org",
+		Version:  "v1",
+		Resource: "pytorchjobs",
+	}
+)
+
+// CheckTrainingOperatorDeployment verifies the Training Operator deployment has ready replicas
+func CheckTrainingOperatorDeployment(t *testing.T, client kubernetes.Interface, namespace string) error {
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.Background(), TrainingOperatorDeployment, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("training operator deployment not found in namespace '%s': %w", namespace, err)
+	}
+	if deployment.Status.ReadyReplicas < 1 {
+		return fmt.Errorf("training operator deployment in namespace '%s' has no ready replicas", namespace)
+	}
+	return nil
+}
+
+// CheckCRDEstablished verifies the named CustomResourceDefinition exists and is established
+func CheckCRDEstablished(t *testing.T, client dynamic.Interface, name string) error {
+	crd, err := client.Resource(CustomResourceDefinitionGVR).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("CRD '%s' not found: %w", name, err)
+	}
+	if !hasTrueCondition(crd, "Established") {
+		return fmt.Errorf("CRD '%s' is not established", name)
+	}
+	return nil
+}
+
+// RunPyTorchJobSmokeTest runs a 1-replica busybox PyTorchJob and waits for it to succeed
+func RunPyTorchJobSmokeTest(t *testing.T, client dynamic.Interface, namespace string, timeout time.Duration) error {
+	job := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "kubeflow.org/v1",
+		"kind":       "PyTorchJob",
+		"metadata":   map[string]interface{}{"generateName": GenerateName("preflight")},
+		"spec": map[string]interface{}{
+			"pytorchReplicaSpecs": map[string]interface{}{
+				"Master": map[string]interface{}{
+					"replicas":      int64(1),
+					"restartPolicy": "Never",
+					"template": map[string]interface{}{
+						"spec": map[string]interface{}{
+							"containers": []interface{}{
+								map[string]interface{}{
+									"name":    "pytorch",
+									"image":   PreflightImage,
+									"command": []interface{}{"sh", "-c", "echo training operator preflight"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}
+
+	jobs := client.Resource(PyTorchJobGVR).Namespace(namespace)
+	created, err := jobs.Create(context.Background(), job, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create preflight PyTorchJob")
+	defer func() {
+		propagation := metav1.DeletePropagationBackground
+		_ = jobs.Delete(context.Background(), created.GetName(), metav1.DeleteOptions{PropagationPolicy: &propagation})
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	key := ctrlclient.ObjectKey{Namespace: namespace, Name: created.GetName()}
+	if err := watcher.Until(ctx, NewWatchClient(t), key, watcher.NewPyTorchJob(), watcher.PyTorchJobSucceeded); err != nil {
+		return fmt.Errorf("preflight PyTorchJob did not succeed: %w", err)
+	}
+	return nil
+}
+
+// TrainingOperatorPreflight checks the Training Operator is healthy before committing to a full run
+func TrainingOperatorPreflight(t *testing.T, client kubernetes.Interface, dynamicClient dynamic.Interface, settings ProductSettings, namespace string) error {
+	if err := CheckTrainingOperatorDeployment(t, client, settings.ApplicationsNamespace); err != nil {
+		return err
+	}
+	if err := CheckCRDEstablished(t, dynamicClient, "pytorchjobs.kubeflow.org"); err != nil {
+		return err
+	}
+	return RunPyTorchJobSmokeTest(t, dynamicClient, namespace, 5*time.Minute)
+}
+
+func hasTrueCondition(obj *unstructured.Unstructured, conditionType string) bool {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if ok && condition["type"] == conditionType && condition["status"] == "True" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type ProductMode string
+
+const (
+	ProductModeODH   ProductMode = "odh"
+	ProductModeRHOAI ProductMode = "rhoai"
+)
+
+// ProductSettings holds the names that differ between Open Data Hub and RHOAI installs
+type ProductSettings struct {
+	Mode                  ProductMode
+	OperatorNamespace     string
+	OperatorDeployment    string
+	ApplicationsNamespace string
+}
+
+var productSettings = map[ProductMode]ProductSettings{
+	ProductModeODH: {
+		Mode:                  ProductModeODH,
+		OperatorNamespace:     "opendatahub-operators",
+		OperatorDeployment:    "opendatahub-operator-controller-manager",
+		ApplicationsNamespace: "opendatahub",
+	},
+	ProductModeRHOAI: {
+		Mode:                  ProductModeRHOAI,
+		OperatorNamespace:     "redhat-ods-operator",
+		OperatorDeployment:    "rhods-operator",
+		ApplicationsNamespace: "redhat-ods-applications",
+	},
+}
+
+// GetProductSettings returns the settings for the given product mode
+func GetProductSettings(mode ProductMode) (ProductSettings, error) {
+	settings, ok := productSettings[mode]
+	if !ok {
+		return ProductSettings{}, fmt.Errorf("unknown product mode '%s', expected '%s' or '%s'", mode, ProductModeODH, ProductModeRHOAI)
+	}
+	return settings, nil
+}
+
+// DetectProductMode finds which product is installed by looking for its operator deployment
+func DetectProductMode(t *testing.T, client kubernetes.Interface) (ProductMode, error) {
+	for _, mode := range []ProductMode{ProductModeRHOAI, ProductModeODH} {
+		settings := productSettings[mode]
+		_, err := client.AppsV1().Deployments(settings.OperatorNamespace).Get(context.Background(), settings.OperatorDeployment, metav1.GetOptions{})
+		if err == nil {
+			return mode, nil
+		}
+		if !errors.IsNotFound(err) {
+			require.NoError(t, err, "Failed to look up operator deployment")
+		}
+	}
+	return "", fmt.Errorf("neither the RHOAI nor the Open Data Hub operator was found on the cluster")
+}
+
+// ResolveProductSettings uses PRODUCT_MODE when set and detects the installed product otherwise
+func ResolveProductSettings(t *testing.T, client kubernetes.Interface) ProductSettings {
+	mode := ProductMode(os.Getenv("PRODUCT_MODE"))
+	if mode == "" {
+		detected, err := DetectProductMode(t, client)
+		require.NoError(t, err, "Failed to detect product mode, set PRODUCT_MODE explicitly")
+		mode = detected
+	}
+
+	settings, err := GetProductSettings(mode)
+	require.NoError(t, err, "Invalid PRODUCT_MODE")
+	return settings
+}
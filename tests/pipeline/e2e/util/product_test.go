@@ -0,0 +1,49 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestResolveProductSettings(t *testing.T) {
+	operator := func(namespace, name string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	}
+
+	t.Setenv("PRODUCT_MODE", "")
+	settings := ResolveProductSettings(t, fake.NewSimpleClientset(operator("redhat-ods-operator", "rhods-operator")))
+	require.Equal(t, ProductModeRHOAI, settings.Mode)
+	require.Equal(t, "redhat-ods-applications", settings.ApplicationsNamespace)
+
+	settings = ResolveProductSettings(t, fake.NewSimpleClientset(operator("opendatahub-operators", "opendatahub-operator-controller-manager")))
+	require.Equal(t, ProductModeODH, settings.Mode)
+	require.Equal(t, "opendatahub", settings.ApplicationsNamespace)
+
+	_, err := DetectProductMode(t, fake.NewSimpleClientset())
+	require.Error(t, err)
+
+	// PRODUCT_MODE wins over the installed operator
+	t.Setenv("PRODUCT_MODE", "odh")
+	settings = ResolveProductSettings(t, fake.NewSimpleClientset(operator("redhat-ods-operator", "rhods-operator")))
+	require.Equal(t, ProductModeODH, settings.Mode)
+
+	_, err = GetProductSettings("other")
+	require.Error(t, err)
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/provenance"
+)
+
+// RunTaskArtifacts groups the objects of the artifacts of a run by the prefix of the task which wrote them,
+// <pipeline root>/<pipeline>/<run ID>/<task>/, the provenance files left out
+func RunTaskArtifacts(objects []ArtifactObject, runID string) map[string][]ArtifactObject {
+	tasks := map[string][]ArtifactObject{}
+	for _, object := range objects {
+		index := strings.Index(object.Key, "/"+runID+"/")
+		if index < 0 {
+			continue
+		}
+		start := index + len(runID) + 2
+		end := strings.Index(object.Key[start:], "/")
+		if end < 0 {
+			continue
+		}
+		prefix := object.Key[:start+end+1]
+		if name := object.Key[len(prefix):]; name == provenance.StatementFile || name == provenance.SignatureFile {
+			if _, ok := tasks[prefix]; !ok {
+				tasks[prefix] = nil
+			}
+			continue
+		}
+		tasks[prefix] = append(tasks[prefix], object)
+	}
+	return tasks
+}
+
+// TaskImages returns the images of the task pods of a run by the directory of the task artifacts, e.g. the images
+// of upload_model_op under upload-model-op
+func TaskImages(tasks []TaskPod) map[string][]string {
+	images := map[string]map[string]bool{}
+	for _, task := range tasks {
+		dir := strings.ReplaceAll(task.Function, "_", "-")
+		if images[dir] == nil {
+			images[dir] = map[string]bool{}
+		}
+		for _, container := range task.Pod.Spec.Containers {
+			images[dir][container.Image] = true
+		}
+	}
+	result := map[string][]string{}
+	for dir, set := range images {
+		for image := range set {
+			result[dir] = append(result[dir], image)
+		}
+		sort.Strings(result[dir])
+	}
+	return result
+}
+
+// SignRunArtifacts stores a signed provenance statement next to the artifacts of every task of a run, covering
+// them. The predicate holds the run inputs, the task and its images are set for each task, and the pipeline from
+// the artifact keys when the predicate leaves it empty. It returns the
+// statements by task prefix.
+func SignRunArtifacts(ctx context.Context, store ArtifactStore, prefix, runID string, predicate provenance.Predicate, images map[string][]string, key crypto.Signer) (map[string]provenance.Statement, error) {
+	objects, err := store.List(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the artifacts of run %s: %w", runID, err)
+	}
+	statements := map[string]provenance.Statement{}
+	for taskPrefix, artifacts := range RunTaskArtifacts(objects, runID) {
+		if len(artifacts) == 0 {
+			continue
+		}
+		digests := map[string]string{}
+		for _, artifact := range artifacts {
+			digest, err := digestObject(ctx, store, artifact.Key)
+			if err != nil {
+				return nil, err
+			}
+			digests[strings.TrimPrefix(artifact.Key, taskPrefix)] = digest
+		}
+
+		// The task prefix is <pipeline root>/<pipeline>/<run ID>/<task>/
+		segments := strings.Split(strings.TrimSuffix(taskPrefix, "/"), "/")
+		task := segments[len(segments)-1]
+		taskPredicate := predicate
+		taskPredicate.RunID = runID
+		taskPredicate.Task = task
+		if taskPredicate.Pipeline == "" && len(segments) >= 3 {
+			taskPredicate.Pipeline = segments[len(segments)-3]
+		}
+		taskPredicate.Images = append([]string(nil), images[task]...)
+		statement, err := provenance.NewStatement(digests, taskPredicate)
+		if err != nil {
+			return nil, err
+		}
+		data, signature, err := provenance.Sign(statement, key)
+		if err != nil {
+			return nil, err
+		}
+		for name, content := range map[string][]byte{provenance.StatementFile: data, provenance.SignatureFile: signature} {
+			if err := store.Put(ctx, taskPrefix+name, bytes.NewReader(content), int64(len(content))); err != nil {
+				return nil, fmt.Errorf("failed to store %s: %w", taskPrefix+name, err)
+			}
+		}
+		statements[taskPrefix] = statement
+	}
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("no artifact of run %s found under '%s'", runID, prefix)
+	}
+	return statements, nil
+}
+
+// VerifyRunArtifacts verifies the provenance statement of every task of a run and the artifacts it covers. Task
+// artifacts without a statement fail the verification.
+func VerifyRunArtifacts(ctx context.Context, store ArtifactStore, prefix, runID string, key crypto.PublicKey) (map[string]provenance.Statement, error) {
+	objects, err := store.List(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the artifacts of run %s: %w", runID, err)
+	}
+	tasks := RunTaskArtifacts(objects, runID)
+	taskPrefixes := make([]string, 0, len(tasks))
+	for taskPrefix := range tasks {
+		taskPrefixes = append(taskPrefixes, taskPrefix)
+	}
+	sort.Strings(taskPrefixes)
+
+	statements := map[string]provenance.Statement{}
+	for _, taskPrefix := range taskPrefixes {
+		data, err := readObject(ctx, store, taskPrefix+provenance.StatementFile)
+		if err != nil {
+			return nil, fmt.Errorf("no provenance statement for %s: %w", taskPrefix, err)
+		}
+		signature, err := readObject(ctx, store, taskPrefix+provenance.SignatureFile)
+		if err != nil {
+			return nil, fmt.Errorf("no provenance signature for %s: %w", taskPrefix, err)
+		}
+		statement, err := provenance.Verify(data, signature, key)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", taskPrefix, err)
+		}
+		err = provenance.VerifySubjects(statement, func(name string) (io.ReadCloser, error) {
+			return store.Get(ctx, taskPrefix+name)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", taskPrefix, err)
+		}
+		statements[taskPrefix] = statement
+	}
+	return statements, nil
+}
+
+func digestObject(ctx context.Context, store ArtifactStore, key string) (string, error) {
+	content, err := store.Get(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	defer content.Close()
+	digest, err := provenance.Digest(content)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	return digest, nil
+}
+
+func readObject(ctx context.Context, store ArtifactStore, key string) ([]byte, error) {
+	content, err := store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+	return io.ReadAll(content)
+}
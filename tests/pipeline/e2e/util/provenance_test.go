@@ -0,0 +1,76 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/provenance"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSignRunArtifacts(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewPVCArtifactStore(t.TempDir())
+	require.NoError(t, err)
+	put := func(key, content string) {
+		require.NoError(t, store.Put(ctx, key, strings.NewReader(content), int64(len(content))))
+	}
+	prefix := "runs/20250301T120000Z-abc/"
+	put(prefix+"ilab/run/sdg-to-artifact-op/1/sdg/skills_train_msgs.jsonl", `{"messages": []}`)
+	put(prefix+"ilab/run/upload-model-op/2/model/config.json", `{"model_type": "granite"}`)
+	put(prefix+"ilab/run/upload-model-op/2/model/model.safetensors", "weights")
+	put(prefix+"ilab/other/upload-model-op/2/model/config.json", `{}`)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	images := TaskImages([]TaskPod{{
+		Function: "upload_model_op",
+		Pod:      corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{{Image: "quay.io/upload:1"}, {Image: "quay.io/launcher:1"}}}},
+	}})
+	require.Equal(t, map[string][]string{"upload-model-op": {"quay.io/launcher:1", "quay.io/upload:1"}}, images)
+
+	signed, err := SignRunArtifacts(ctx, store, prefix, "run", provenance.Predicate{Inputs: map[string]interface{}{"sdg_scale_factor": 30.0}}, images, key)
+	require.NoError(t, err)
+	require.Len(t, signed, 2)
+	model := signed[prefix+"ilab/run/upload-model-op/"]
+	require.Equal(t, "upload-model-op", model.Predicate.Task)
+	require.Equal(t, "ilab", model.Predicate.Pipeline)
+	require.Equal(t, []string{"quay.io/launcher:1", "quay.io/upload:1"}, model.Predicate.Images)
+	require.Equal(t, []string{"2/model/config.json", "2/model/model.safetensors"}, []string{model.Subject[0].Name, model.Subject[1].Name})
+
+	verified, err := VerifyRunArtifacts(ctx, store, prefix, "run", key.Public())
+	require.NoError(t, err)
+	require.Equal(t, signed, verified)
+
+	// Signing again leaves the previous statements out of the subjects
+	resigned, err := SignRunArtifacts(ctx, store, prefix, "run", provenance.Predicate{}, images, key)
+	require.NoError(t, err)
+	require.Len(t, resigned[prefix+"ilab/run/upload-model-op/"].Subject, 2)
+
+	put(prefix+"ilab/run/upload-model-op/2/model/model.safetensors", "tampered")
+	_, err = VerifyRunArtifacts(ctx, store, prefix, "run", key.Public())
+	require.ErrorContains(t, err, "2/model/model.safetensors: digest")
+
+	put(prefix+"ilab/run/upload-model-op/2/model/model.safetensors", "weights")
+	put(prefix+"ilab/run/metrics-report-op/3/metrics.json", "{}")
+	_, err = VerifyRunArtifacts(ctx, store, prefix, "run", key.Public())
+	require.ErrorContains(t, err, "no provenance statement for "+prefix+"ilab/run/metrics-report-op/")
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/clock"
+)
+
+// waitForFirstConsumerReason is the event reason of a PVC whose storage class binds it once a pod uses it, Pending is
+// then expected until the pod is scheduled
+const waitForFirstConsumerReason = "WaitForFirstConsumer"
+
+// PendingPVC is a PVC created during a run which is still not bound
+type PendingPVC struct {
+	Name         string
+	StorageClass string
+	AccessModes  []corev1.PersistentVolumeAccessMode
+	Pending      time.Duration
+	// Reason is the message of the latest event of the PVC, e.g. the provisioning failure
+	Reason string
+}
+
+func (p PendingPVC) String() string {
+	modes := make([]string, len(p.AccessModes))
+	for i, mode := range p.AccessModes {
+		modes[i] = string(mode)
+	}
+	reason := p.Reason
+	if reason == "" {
+		reason = "no event"
+	}
+	return fmt.Sprintf("PVC %s (storage class %s, %s) pending for %s: %s", p.Name, p.StorageClass, strings.Join(modes, ","), p.Pending.Round(time.Second), reason)
+}
+
+// PendingPVCs returns the PVCs created since the given time which are still Pending at now, waiting for their first
+// consumer left out. events are the events of the namespace.
+func PendingPVCs(pvcs []corev1.PersistentVolumeClaim, events []corev1.Event, since, now time.Time) []PendingPVC {
+	latest := map[string]corev1.Event{}
+	for _, event := range events {
+		if event.InvolvedObject.Kind != "PersistentVolumeClaim" {
+			continue
+		}
+		if current, ok := latest[event.InvolvedObject.Name]; !ok || eventTime(event).After(eventTime(current)) {
+			latest[event.InvolvedObject.Name] = event
+		}
+	}
+
+	var pending []PendingPVC
+	for _, pvc := range pvcs {
+		if pvc.Status.Phase != corev1.ClaimPending || pvc.CreationTimestamp.Time.Before(since) {
+			continue
+		}
+		event := latest[pvc.Name]
+		if event.Reason == waitForFirstConsumerReason {
+			continue
+		}
+		storageClass := ""
+		if pvc.Spec.StorageClassName != nil {
+			storageClass = *pvc.Spec.StorageClassName
+		}
+		pending = append(pending, PendingPVC{
+			Name:         pvc.Name,
+			StorageClass: storageClass,
+			AccessModes:  pvc.Spec.AccessModes,
+			Pending:      now.Sub(pvc.CreationTimestamp.Time),
+			Reason:       event.Message,
+		})
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Name < pending[j].Name })
+	return pending
+}
+
+func eventTime(event corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	return event.EventTime.Time
+}
+
+// PVCAlert reports a PVC pending past a threshold of WatchPendingPVCs, Failed when past the failure threshold
+type PVCAlert struct {
+	PendingPVC
+	Failed bool
+}
+
+// WatchPendingPVCs reports the PVCs created since the given time which stay Pending, e.g. because the storage class
+// does not support ReadWriteMany or its provisioner is down, once after alertAfter and once after failAfter, until the
+// returned stop function is called. The PVCs pending at every check are logged with debugf.
+func WatchPendingPVCs(client kubernetes.Interface, namespace string, since time.Time, alertAfter, failAfter, interval time.Duration, debugf func(string, ...interface{}), report func(PVCAlert, error)) (stop func()) {
+	return watchPendingPVCs(clock.RealClock{}, client, namespace, since, alertAfter, failAfter, interval, debugf, report)
+}
+
+func watchPendingPVCs(clk clock.Clock, client kubernetes.Interface, namespace string, since time.Time, alertAfter, failAfter, interval time.Duration, debugf func(string, ...interface{}), report func(PVCAlert, error)) (stop func()) {
+	done := make(chan struct{})
+	tick := clk.Tick(interval)
+	alerted, failed := map[string]bool{}, map[string]bool{}
+	events := &capabilityGate{capability: EventsCapability}
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-tick:
+				pending, err := listPendingPVCs(client, namespace, since, clk.Now(), events)
+				if notice := events.notice(); notice != nil {
+					report(PVCAlert{}, notice)
+				}
+				if err != nil {
+					report(PVCAlert{}, err)
+					continue
+				}
+				debugf("%d PVCs of namespace %s pending", len(pending), namespace)
+				for _, pvc := range pending {
+					debugf("PVC %s of storage class %s pending for %s: %s", pvc.Name, pvc.StorageClass, pvc.Pending.Round(time.Second), pvc.Reason)
+					if pvc.Pending >= failAfter && !failed[pvc.Name] {
+						failed[pvc.Name], alerted[pvc.Name] = true, true
+						report(PVCAlert{PendingPVC: pvc, Failed: true}, nil)
+					} else if pvc.Pending >= alertAfter && !alerted[pvc.Name] {
+						alerted[pvc.Name] = true
+						report(PVCAlert{PendingPVC: pvc}, nil)
+					}
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// listPendingPVCs lists the pending PVCs, without the reason they are pending when events cannot be listed
+func listPendingPVCs(client kubernetes.Interface, namespace string, since, now time.Time, events *capabilityGate) ([]PendingPVC, error) {
+	pvcs, err := client.CoreV1().PersistentVolumeClaims(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PVCs: %w", err)
+	}
+	var pvcEvents []corev1.Event
+	if events.available() {
+		list, err := client.CoreV1().Events(namespace).List(context.Background(), metav1.ListOptions{
+			FieldSelector: "involvedObject.kind=PersistentVolumeClaim",
+		})
+		switch {
+		case err == nil:
+			pvcEvents = list.Items
+		case !events.deny(err):
+			return nil, fmt.Errorf("failed to list PVC events: %w", err)
+		}
+	}
+	return PendingPVCs(pvcs.Items, pvcEvents, since, now), nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func pendingPVC(name, storageClass string, created time.Time) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", CreationTimestamp: metav1.NewTime(created)},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClass,
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+	}
+}
+
+func pvcEvent(name, pvc, reason, message string, at time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "ns"},
+		InvolvedObject: corev1.ObjectReference{Kind: "PersistentVolumeClaim", Name: pvc},
+		Type:           corev1.EventTypeWarning,
+		Reason:         reason,
+		Message:        message,
+		LastTimestamp:  metav1.NewTime(at),
+	}
+}
+
+func TestPendingPVCs(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	old := pendingPVC("old", "nfs", start.Add(-time.Hour))
+	bound := pendingPVC("bound", "nfs", start)
+	bound.Status.Phase = corev1.ClaimBound
+	consumer := pendingPVC("consumer", "gp3", start)
+	output := pendingPVC("output", "nfs", start.Add(time.Minute))
+	pvcs := []corev1.PersistentVolumeClaim{*old, *bound, *consumer, *output}
+	events := []corev1.Event{
+		*pvcEvent("e1", "output", "ExternalProvisioning", "waiting for a volume to be created", start.Add(time.Minute)),
+		*pvcEvent("e2", "output", "ProvisioningFailed", "storageclass does not support ReadWriteMany", start.Add(2*time.Minute)),
+		*pvcEvent("e3", "consumer", "WaitForFirstConsumer", "waiting for first consumer to be created before binding", start),
+	}
+
+	pending := PendingPVCs(pvcs, events, start, start.Add(5*time.Minute))
+	require.Equal(t, []PendingPVC{{
+		Name:         "output",
+		StorageClass: "nfs",
+		AccessModes:  []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+		Pending:      4 * time.Minute,
+		Reason:       "storageclass does not support ReadWriteMany",
+	}}, pending)
+	require.Equal(t, "PVC output (storage class nfs, ReadWriteMany) pending for 4m0s: storageclass does not support ReadWriteMany", pending[0].String())
+}
+
+func TestWatchPendingPVCs(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := testingclock.NewFakeClock(start)
+	client := fake.NewSimpleClientset(pendingPVC("sdg", "nfs", start))
+	alerts := make(chan PVCAlert, 10)
+	stop := watchPendingPVCs(clock, client, "ns", start, 2*time.Minute, 5*time.Minute, time.Minute, t.Logf, func(alert PVCAlert, err error) {
+		require.NoError(t, err)
+		alerts <- alert
+	})
+	defer stop()
+
+	clock.Step(time.Minute)
+	require.Never(t, func() bool { return len(alerts) > 0 }, 50*time.Millisecond, 10*time.Millisecond)
+	clock.Step(time.Minute)
+	alert := <-alerts
+	require.Equal(t, "sdg", alert.Name)
+	require.False(t, alert.Failed)
+
+	// The alert is reported once
+	clock.Step(time.Minute)
+	require.Never(t, func() bool { return len(alerts) > 0 }, 50*time.Millisecond, 10*time.Millisecond)
+	clock.Step(2 * time.Minute)
+	alert = <-alerts
+	require.True(t, alert.Failed)
+	require.Equal(t, 5*time.Minute, alert.Pending)
+
+	// Bound PVCs are not reported anymore
+	pvc := pendingPVC("model-cache", "nfs", clock.Now())
+	pvc.Status.Phase = corev1.ClaimBound
+	_, err := client.CoreV1().PersistentVolumeClaims("ns").Create(context.Background(), pvc, metav1.CreateOptions{})
+	require.NoError(t, err)
+	clock.Step(10 * time.Minute)
+	require.Never(t, func() bool { return len(alerts) > 0 }, 50*time.Millisecond, 10*time.Millisecond)
+}
+
+func TestWatchPendingPVCsWithoutEvents(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := testingclock.NewFakeClock(start)
+	client := fake.NewSimpleClientset(pendingPVC("sdg", "nfs", start))
+	eventLists := 0
+	client.PrependReactor("list", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		eventLists++
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "events"}, "", errors.New("no RBAC policy matched"))
+	})
+	alerts, notices := make(chan PVCAlert, 10), make(chan error, 10)
+	stop := watchPendingPVCs(clock, client, "ns", start, 2*time.Minute, 5*time.Minute, time.Minute, t.Logf, func(alert PVCAlert, err error) {
+		if err != nil {
+			notices <- err
+			return
+		}
+		alerts <- alert
+	})
+	defer stop()
+
+	// The missing permission is reported once and the PVCs are still watched, without the reason they are pending
+	clock.Step(time.Minute)
+	var missing *MissingCapabilityError
+	require.ErrorAs(t, <-notices, &missing)
+	require.Equal(t, EventsCapability, missing.Capability)
+	clock.Step(time.Minute)
+	alert := <-alerts
+	require.Equal(t, "sdg", alert.Name)
+	require.Empty(t, alert.Reason)
+	require.Empty(t, notices)
+	require.Equal(t, 1, eventLists)
+}
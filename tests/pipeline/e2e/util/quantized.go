@@ -0,0 +1,169 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Formats of a quantized output model
+const (
+	QuantizedFormatGGUF = "gguf"
+	QuantizedFormatInt8 = "int8"
+)
+
+// quantizedVerificationScript reads the header of the artifact at ARTIFACT_URL with range requests, so the model is
+// not downloaded, and prints its format and tensor counts
+const quantizedVerificationScript = `import json, os, struct, urllib.request
+url = os.environ["ARTIFACT_URL"]
+def read(start, length):
+    request = urllib.request.Request(url, headers={"Range": f"bytes={start}-{start + length - 1}"})
+    with urllib.request.urlopen(request) as response:
+        return response.read()
+head = read(0, 24)
+if head[:4] == b"GGUF":
+    version, tensors = struct.unpack("<IQ", head[4:16])
+    print(f"format=gguf version={version} tensors={tensors} int8_tensors=0")
+else:
+    header = json.loads(read(8, struct.unpack("<Q", head[:8])[0]))
+    dtypes = [tensor["dtype"] for name, tensor in header.items() if name != "__metadata__"]
+    print(f"format=safetensors version=0 tensors={len(dtypes)} int8_tensors={dtypes.count('I8')}")
+`
+
+var (
+	quantizedReportPattern = regexp.MustCompile(`format=(\S+) version=(\d+) tensors=(\d+) int8_tensors=(\d+)`)
+	weightsPattern         = regexp.MustCompile(`^model(-\d+-of-\d+)?\.safetensors$`)
+)
+
+// QuantizedReport is what the verification pod read from the header of a quantized artifact
+type QuantizedReport struct {
+	// Format is gguf or safetensors
+	Format      string
+	Version     int
+	Tensors     int
+	Int8Tensors int
+}
+
+// FindQuantizedArtifact returns the key of the model weights of the given quantization format
+func FindQuantizedArtifact(keys []string, format string) (string, error) {
+	for _, key := range keys {
+		name := path.Base(key)
+		switch format {
+		case QuantizedFormatGGUF:
+			if strings.HasSuffix(name, ".gguf") {
+				return key, nil
+			}
+		case QuantizedFormatInt8:
+			if weightsPattern.MatchString(name) {
+				return key, nil
+			}
+		default:
+			return "", fmt.Errorf("unknown quantization format '%s'", format)
+		}
+	}
+	return "", fmt.Errorf("no %s model artifact found", format)
+}
+
+// ParseQuantizedVerification reads the output of the verification pod
+func ParseQuantizedVerification(logs string) (QuantizedReport, error) {
+	match := quantizedReportPattern.FindStringSubmatch(logs)
+	if match == nil {
+		return QuantizedReport{}, fmt.Errorf("artifact header could not be read")
+	}
+	version, _ := strconv.Atoi(match[2])
+	tensors, _ := strconv.Atoi(match[3])
+	int8Tensors, _ := strconv.Atoi(match[4])
+	return QuantizedReport{Format: match[1], Version: version, Tensors: tensors, Int8Tensors: int8Tensors}, nil
+}
+
+// CheckQuantizedReport verifies the artifact is a loadable model of the expected quantization format
+func CheckQuantizedReport(report QuantizedReport, format string) error {
+	switch {
+	case format == QuantizedFormatGGUF && report.Format != "gguf":
+		return fmt.Errorf("artifact is %s, not GGUF", report.Format)
+	case format == QuantizedFormatInt8 && report.Format != "safetensors":
+		return fmt.Errorf("artifact is %s, not safetensors", report.Format)
+	case report.Tensors == 0:
+		return fmt.Errorf("%s artifact holds no tensors", report.Format)
+	case format == QuantizedFormatInt8 && report.Int8Tensors == 0:
+		return fmt.Errorf("none of the %d tensors of the artifact is INT8", report.Tensors)
+	}
+	return nil
+}
+
+// VerifyQuantizedArtifact reads the header of the artifact at the given URL, typically a presigned object store URL,
+// in a verification pod running the given image, which must provide python3. The error holds the pod output.
+func VerifyQuantizedArtifact(t *testing.T, client kubernetes.Interface, namespace, image, artifactURL, format string, timeout time.Duration) (QuantizedReport, error) {
+	resources := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("512Mi"),
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: GenerateName("quantized-verify")},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{{
+				Name:      "verify",
+				Image:     image,
+				Command:   []string{"python3", "-c", quantizedVerificationScript},
+				Env:       []corev1.EnvVar{{Name: "ARTIFACT_URL", Value: artifactURL}},
+				Resources: corev1.ResourceRequirements{Requests: resources, Limits: resources},
+			}},
+		},
+	}
+
+	pods := client.CoreV1().Pods(namespace)
+	created, err := pods.Create(context.Background(), pod, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create quantized artifact verification pod")
+	defer func() { _ = pods.Delete(context.Background(), created.Name, metav1.DeleteOptions{}) }()
+
+	deadline := time.After(timeout)
+	tick := time.Tick(5 * time.Second)
+	for {
+		select {
+		case <-deadline:
+			return QuantizedReport{}, fmt.Errorf("verification pod %s did not complete within %s", created.Name, timeout)
+		case <-tick:
+			current, err := pods.Get(context.Background(), created.Name, metav1.GetOptions{})
+			require.NoError(t, err, "Failed to retrieve quantized artifact verification pod")
+			if current.Status.Phase != corev1.PodSucceeded && current.Status.Phase != corev1.PodFailed {
+				continue
+			}
+
+			logs, err := pods.GetLogs(created.Name, &corev1.PodLogOptions{}).DoRaw(context.Background())
+			require.NoError(t, err, "Failed to retrieve quantized artifact verification logs")
+			report, err := ParseQuantizedVerification(string(logs))
+			if err == nil {
+				err = CheckQuantizedReport(report, format)
+			}
+			if err != nil {
+				return report, fmt.Errorf("%w, verification output:\n%s", err, logs)
+			}
+			return report, nil
+		}
+	}
+}
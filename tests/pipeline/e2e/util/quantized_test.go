@@ -0,0 +1,53 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindQuantizedArtifact(t *testing.T) {
+	prefix := "runs/20250301T120000Z-abc/ilab/run/upload-model-op/model/"
+	keys := []string{prefix + "config.json", prefix + "model-00001-of-00002.safetensors", prefix + "model-q8_0.gguf"}
+
+	key, err := FindQuantizedArtifact(keys, QuantizedFormatGGUF)
+	require.NoError(t, err)
+	require.Equal(t, prefix+"model-q8_0.gguf", key)
+
+	key, err = FindQuantizedArtifact(keys, QuantizedFormatInt8)
+	require.NoError(t, err)
+	require.Equal(t, prefix+"model-00001-of-00002.safetensors", key)
+
+	_, err = FindQuantizedArtifact(keys[:1], QuantizedFormatGGUF)
+	require.ErrorContains(t, err, "no gguf model artifact found")
+	_, err = FindQuantizedArtifact(keys, "awq")
+	require.ErrorContains(t, err, "unknown quantization format")
+}
+
+func TestCheckQuantizedReport(t *testing.T) {
+	report, err := ParseQuantizedVerification("format=gguf version=3 tensors=291 int8_tensors=0\n")
+	require.NoError(t, err)
+	require.Equal(t, QuantizedReport{Format: "gguf", Version: 3, Tensors: 291}, report)
+	require.NoError(t, CheckQuantizedReport(report, QuantizedFormatGGUF))
+	require.ErrorContains(t, CheckQuantizedReport(report, QuantizedFormatInt8), "not safetensors")
+
+	report, err = ParseQuantizedVerification("format=safetensors version=0 tensors=450 int8_tensors=0")
+	require.NoError(t, err)
+	require.ErrorContains(t, CheckQuantizedReport(report, QuantizedFormatInt8), "none of the 450 tensors")
+
+	_, err = ParseQuantizedVerification("Traceback (most recent call last):")
+	require.Error(t, err)
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/recorder"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+)
+
+const recordingProxyPort = 8080
+
+// RecordingProxyConfig describes a recording proxy in front of a teacher or judge endpoint
+type RecordingProxyConfig struct {
+	Name      string
+	Namespace string
+	// Image is built from tests/Containerfile
+	Image              string
+	Target             string
+	SampleRate         float64
+	InsecureSkipVerify bool
+	// DNS is the name resolution of the proxy pod, to reach a target only an internal DNS resolves
+	DNS PodDNS
+}
+
+// ProxiedEndpoint splits a model server endpoint into the target of the proxy and the endpoint to reach it through
+// the proxy, keeping the path, e.g. https://judge.example.com/v1 becomes https://judge.example.com and <proxy>/v1
+func ProxiedEndpoint(endpoint, proxyURL string) (string, string, error) {
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Host == "" {
+		return "", "", fmt.Errorf("invalid model server endpoint '%s'", endpoint)
+	}
+	return parsed.Scheme + "://" + parsed.Host, proxyURL + parsed.Path, nil
+}
+
+// DeployRecordingProxy deploys the recording proxy and waits for it to become ready. It returns the URL of the proxy.
+func DeployRecordingProxy(t *testing.T, client kubernetes.Interface, config RecordingProxyConfig, timeout time.Duration) string {
+	labels := map[string]string{"app": config.Name}
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: config.Name, Labels: labels},
+		Spec: corev1.ServiceSpec{
+			Selector: labels,
+			Ports:    []corev1.ServicePort{{Name: "http", Port: recordingProxyPort, TargetPort: intstr.FromInt(recordingProxyPort)}},
+		},
+	}
+	_, err := client.CoreV1().Services(config.Namespace).Create(context.Background(), service, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create recording proxy service")
+
+	resources := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("500m"),
+		corev1.ResourceMemory: resource.MustParse("256Mi"),
+	}
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: config.Name, Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:    "proxy",
+						Image:   config.Image,
+						Command: []string{"recording-proxy"},
+						Args: []string{
+							fmt.Sprintf("--listen=:%d", recordingProxyPort),
+							"--target=" + config.Target,
+							"--sample-rate=" + strconv.FormatFloat(config.SampleRate, 'f', -1, 64),
+							"--insecure-skip-verify=" + strconv.FormatBool(config.InsecureSkipVerify),
+						},
+						Ports:     []corev1.ContainerPort{{ContainerPort: recordingProxyPort}},
+						Resources: corev1.ResourceRequirements{Requests: resources, Limits: resources},
+						ReadinessProbe: &corev1.Probe{
+							ProbeHandler: corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(recordingProxyPort)}},
+						},
+					}},
+				},
+			},
+		},
+	}
+	config.DNS.ApplyTo(&deployment.Spec.Template.Spec)
+	_, err = client.AppsV1().Deployments(config.Namespace).Create(context.Background(), deployment, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create recording proxy deployment")
+
+	require.NoError(t, WaitForDeploymentReady(t, client, config.Namespace, config.Name, timeout), "Recording proxy did not become ready")
+	return fmt.Sprintf("http://%s.%s.svc:%d", config.Name, config.Namespace, recordingProxyPort)
+}
+
+// CollectRecordings returns the exchanges recorded by a recording proxy, as JSON lines
+func CollectRecordings(t *testing.T, client kubernetes.Interface, namespace, name string) []byte {
+	var recordings []byte
+	for _, line := range recordingProxyLines(t, client, namespace, name) {
+		if !bytes.HasPrefix(line, []byte(`{"usage":`)) {
+			recordings = append(append(recordings, line...), '\n')
+		}
+	}
+	return recordings
+}
+
+// CollectTokenUsage returns the token usage last reported by a recording proxy
+func CollectTokenUsage(t *testing.T, client kubernetes.Interface, namespace, name string) recorder.Usage {
+	var usage recorder.UsageRecord
+	for _, line := range recordingProxyLines(t, client, namespace, name) {
+		if bytes.HasPrefix(line, []byte(`{"usage":`)) {
+			require.NoError(t, json.Unmarshal(line, &usage), "Failed to parse token usage")
+		}
+	}
+	return usage.Usage
+}
+
+// RenderTokenUsage renders the token usage of each model server as a markdown table
+func RenderTokenUsage(usage map[string]recorder.Usage) string {
+	var roles []string
+	for role := range usage {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+
+	var b strings.Builder
+	b.WriteString("# Token usage\n\n")
+	b.WriteString("| Endpoint | Requests | Prompt tokens | Completion tokens | Estimated requests |\n")
+	b.WriteString("|---|---|---|---|---|\n")
+	for _, role := range roles {
+		u := usage[role]
+		fmt.Fprintf(&b, "| %s | %d | %d | %d | %d |\n", role, u.Requests, u.PromptTokens, u.CompletionTokens, u.Estimated)
+	}
+	b.WriteString("\nEstimated requests reported no usage, their tokens are approximated from the payload sizes.\n")
+	return b.String()
+}
+
+// recordingProxyLines returns the JSON lines of the recording proxy logs, the other lines are messages of the
+// proxy itself
+func recordingProxyLines(t *testing.T, client kubernetes.Interface, namespace, name string) [][]byte {
+	pods, err := client.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{LabelSelector: "app=" + name})
+	require.NoError(t, err, "Failed to list recording proxy pods")
+
+	var lines [][]byte
+	for _, pod := range pods.Items {
+		logs, err := client.CoreV1().Pods(namespace).GetLogs(pod.Name, &corev1.PodLogOptions{}).DoRaw(context.Background())
+		require.NoError(t, err, "Failed to retrieve recording proxy logs")
+		for _, line := range bytes.Split(logs, []byte("\n")) {
+			if len(line) > 0 && line[0] == '{' {
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines
+}
+
+// DeleteRecordingProxy removes everything DeployRecordingProxy created, and the secret "<name>-secret" pointing at it
+func DeleteRecordingProxy(t *testing.T, client kubernetes.Interface, namespace, name string) {
+	ctx := context.Background()
+	_ = client.AppsV1().Deployments(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	_ = client.CoreV1().Services(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	_ = client.CoreV1().Secrets(namespace).Delete(ctx, name+"-secret", metav1.DeleteOptions{})
+}
@@ -0,0 +1,39 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/recorder"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxiedEndpoint(t *testing.T) {
+	target, proxied, err := ProxiedEndpoint("https://judge.apps.example.com/v1", "http://proxy.ns.svc:8080")
+	require.NoError(t, err)
+	require.Equal(t, "https://judge.apps.example.com", target)
+	require.Equal(t, "http://proxy.ns.svc:8080/v1", proxied)
+
+	_, _, err = ProxiedEndpoint("judge", "http://proxy.ns.svc:8080")
+	require.Error(t, err)
+}
+
+func TestRenderTokenUsage(t *testing.T) {
+	report := RenderTokenUsage(map[string]recorder.Usage{
+		"teacher": {Requests: 10, PromptTokens: 2000, CompletionTokens: 5000},
+		"judge":   {Requests: 4, PromptTokens: 800, CompletionTokens: 100, Estimated: 1},
+	})
+	require.Contains(t, report, "| judge | 4 | 800 | 100 | 1 |\n| teacher | 10 | 2000 | 5000 | 0 |")
+}
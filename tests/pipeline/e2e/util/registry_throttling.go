@@ -0,0 +1,207 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/clock"
+)
+
+// registryThrottlingPattern matches the pull errors of a registry rejecting or dropping requests under load, as
+// opposed to a missing image or denied access
+var registryThrottlingPattern = regexp.MustCompile(`(?i)\b429\b|too ?many ?requests|rate limit|i/o timeout|TLS handshake timeout|context deadline exceeded|connection reset by peer|\b50[23]\b`)
+
+// ThrottledPull is a pod of a run failing to pull an image because the registry throttles it
+type ThrottledPull struct {
+	Pod   string
+	Image string
+	// Message is the latest pull failure of the pod
+	Message string
+	Created time.Time
+	// Recreatable is true when a controller recreates the pod once deleted, e.g. the Training Operator for the
+	// PyTorchJob pods. Argo does not recreate the task pods, which the pipeline runs without retry policy.
+	Recreatable bool
+}
+
+// IsRegistryThrottling reports whether a pull failure is transient registry throttling
+func IsRegistryThrottling(message string) bool {
+	return registryThrottlingPattern.MatchString(message)
+}
+
+// ThrottledPulls returns the pods waiting on an image pull whose latest pull failure is registry throttling.
+// events are the pod events of the namespace.
+func ThrottledPulls(pods []corev1.Pod, events []corev1.Event) []ThrottledPull {
+	latest := map[string]corev1.Event{}
+	for _, event := range events {
+		if event.InvolvedObject.Kind != "Pod" || event.Reason != "Failed" {
+			continue
+		}
+		if current, ok := latest[event.InvolvedObject.Name]; !ok || eventTime(event).After(eventTime(current)) {
+			latest[event.InvolvedObject.Name] = event
+		}
+	}
+
+	var throttled []ThrottledPull
+	for _, pod := range pods {
+		statuses := append(append([]corev1.ContainerStatus(nil), pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+		for _, status := range statuses {
+			waiting := status.State.Waiting
+			if waiting == nil || (waiting.Reason != "ErrImagePull" && waiting.Reason != "ImagePullBackOff") {
+				continue
+			}
+			message := waiting.Message
+			if event, ok := latest[pod.Name]; ok && event.InvolvedObject.UID == pod.UID {
+				message = event.Message
+			}
+			if !IsRegistryThrottling(message) {
+				continue
+			}
+			throttled = append(throttled, ThrottledPull{
+				Pod:         pod.Name,
+				Image:       status.Image,
+				Message:     message,
+				Created:     pod.CreationTimestamp.Time,
+				Recreatable: isRecreatedByController(pod),
+			})
+			break
+		}
+	}
+	return throttled
+}
+
+func isRecreatedByController(pod corev1.Pod) bool {
+	owner := metav1.GetControllerOf(&pod)
+	return owner != nil && owner.Kind != "Workflow"
+}
+
+// PullRetryPolicy limits the recreations of the pods of WatchRegistryThrottling. A pod is recreated once it exists
+// for the backoff, which doubles after every recreation of the pod.
+type PullRetryPolicy struct {
+	MaxRetries int
+	Backoff    time.Duration
+}
+
+// backoff returns the time to wait before the given retry of a pod, counted from 0
+func (p PullRetryPolicy) backoff(retry int) time.Duration {
+	return p.Backoff << retry
+}
+
+// PullRetry reports a throttled pull of WatchRegistryThrottling and the action taken
+type PullRetry struct {
+	ThrottledPull
+	// Retry counts the recreations of the pod, this one included when Recreated
+	Retry     int
+	Recreated bool
+	// Exhausted is true once the pod is still throttled after MaxRetries recreations
+	Exhausted bool
+}
+
+func (r PullRetry) String() string {
+	switch {
+	case r.Exhausted:
+		return fmt.Sprintf("pod %s still fails to pull %s after %d recreations: %s", r.Pod, r.Image, r.Retry, r.Message)
+	case r.Recreated:
+		return fmt.Sprintf("recreated pod %s throttled pulling %s (retry %d): %s", r.Pod, r.Image, r.Retry, r.Message)
+	default:
+		return fmt.Sprintf("pod %s is throttled pulling %s, left to the kubelet pull backoff as it would not be recreated: %s", r.Pod, r.Image, r.Message)
+	}
+}
+
+// WatchRegistryThrottling deletes the pods of a run throttled pulling their image, once per backoff and at most
+// MaxRetries times, so their controller recreates them, possibly on another node, instead of leaving them to the
+// kubelet pull backoff. Pods no controller would recreate are only reported, once. It runs until the returned stop
+// function is called.
+func WatchRegistryThrottling(client kubernetes.Interface, namespace, runID string, policy PullRetryPolicy, interval time.Duration, report func(PullRetry, error)) (stop func()) {
+	return watchRegistryThrottling(clock.RealClock{}, client, namespace, runID, policy, interval, report)
+}
+
+func watchRegistryThrottling(clk clock.Clock, client kubernetes.Interface, namespace, runID string, policy PullRetryPolicy, interval time.Duration, report func(PullRetry, error)) (stop func()) {
+	done := make(chan struct{})
+	tick := clk.Tick(interval)
+	// Pods are recreated under the same name by the Training Operator, so retries are counted by name
+	retries, reported := map[string]int{}, map[string]bool{}
+	events := &capabilityGate{capability: EventsCapability}
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-tick:
+				throttled, err := listThrottledPulls(client, namespace, runID, events)
+				if notice := events.notice(); notice != nil {
+					report(PullRetry{}, notice)
+				}
+				if err != nil {
+					report(PullRetry{}, err)
+					continue
+				}
+				now := clk.Now()
+				for _, pull := range throttled {
+					retry := retries[pull.Pod]
+					switch {
+					case !pull.Recreatable || retry >= policy.MaxRetries:
+						if !reported[pull.Pod] {
+							reported[pull.Pod] = true
+							report(PullRetry{ThrottledPull: pull, Retry: retry, Exhausted: pull.Recreatable}, nil)
+						}
+					case now.Sub(pull.Created) >= policy.backoff(retry):
+						err := client.CoreV1().Pods(namespace).Delete(context.Background(), pull.Pod, metav1.DeleteOptions{})
+						if err != nil {
+							report(PullRetry{ThrottledPull: pull, Retry: retry}, fmt.Errorf("failed to delete pod %s: %w", pull.Pod, err))
+							continue
+						}
+						retries[pull.Pod]++
+						report(PullRetry{ThrottledPull: pull, Retry: retries[pull.Pod], Recreated: true}, nil)
+					}
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// listThrottledPulls lists the throttled pulls of a run, recognized from the message of the container status only
+// when events cannot be listed
+func listThrottledPulls(client kubernetes.Interface, namespace, runID string, events *capabilityGate) ([]ThrottledPull, error) {
+	runPods, err := client.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", RunIDLabel, runID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the pods of run %s: %w", runID, err)
+	}
+	trainingPods, err := ListTrainingPods(client, namespace, runPods.Items)
+	if err != nil {
+		return nil, err
+	}
+	var podEvents []corev1.Event
+	if events.available() {
+		list, err := client.CoreV1().Events(namespace).List(context.Background(), metav1.ListOptions{
+			FieldSelector: "involvedObject.kind=Pod,reason=Failed",
+		})
+		switch {
+		case err == nil:
+			podEvents = list.Items
+		case !events.deny(err):
+			return nil, fmt.Errorf("failed to list pod events: %w", err)
+		}
+	}
+	return ThrottledPulls(append(runPods.Items, trainingPods...), podEvents), nil
+}
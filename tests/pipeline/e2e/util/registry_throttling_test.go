@@ -0,0 +1,122 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func pullingPod(name, ownerKind, reason, message string) *corev1.Pod {
+	controller := true
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)),
+			Namespace:         "ns",
+			UID:               types.UID(name + "-uid"),
+			Labels:            map[string]string{RunIDLabel: "run"},
+			OwnerReferences:   []metav1.OwnerReference{{Kind: ownerKind, Name: "owner", Controller: &controller}},
+		},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			Name:  "main",
+			Image: "quay.io/opendatahub/training:latest",
+			State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: reason, Message: message}},
+		}}},
+	}
+}
+
+func TestThrottledPulls(t *testing.T) {
+	pods := []corev1.Pod{
+		*pullingPod("train-phase-1-master-0", "PyTorchJob", "ImagePullBackOff", `Back-off pulling image "quay.io/opendatahub/training:latest"`),
+		*pullingPod("sdg-op", "Workflow", "ErrImagePull", "unexpected status code 429 Too Many Requests"),
+		*pullingPod("missing", "PyTorchJob", "ErrImagePull", "manifest unknown"),
+		*pullingPod("running", "PyTorchJob", "", ""),
+	}
+	pods[3].Status.ContainerStatuses[0].State = corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	event := func(pod, message string, at time.Time) corev1.Event {
+		return corev1.Event{
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: pod, UID: types.UID(pod + "-uid")},
+			Reason:         "Failed",
+			Message:        message,
+			LastTimestamp:  metav1.NewTime(at),
+		}
+	}
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	events := []corev1.Event{
+		event("train-phase-1-master-0", "Failed to pull image: toomanyrequests: Rate limit exceeded", start.Add(time.Minute)),
+		event("train-phase-1-master-0", "Error: ErrImagePull", start),
+	}
+
+	require.Equal(t, []ThrottledPull{
+		{Pod: "train-phase-1-master-0", Image: "quay.io/opendatahub/training:latest", Message: "Failed to pull image: toomanyrequests: Rate limit exceeded", Created: start, Recreatable: true},
+		{Pod: "sdg-op", Image: "quay.io/opendatahub/training:latest", Message: "unexpected status code 429 Too Many Requests", Created: start},
+	}, ThrottledPulls(pods, events))
+	require.True(t, IsRegistryThrottling("dial tcp 1.2.3.4:443: i/o timeout"))
+	require.False(t, IsRegistryThrottling("unauthorized: access to the requested resource is not authorized"))
+}
+
+func TestWatchRegistryThrottling(t *testing.T) {
+	clock := testingclock.NewFakeClock(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	throttled := func(name, ownerKind string) *corev1.Pod {
+		pod := pullingPod(name, ownerKind, "ErrImagePull", "429 Too Many Requests")
+		pod.CreationTimestamp = metav1.NewTime(clock.Now())
+		return pod
+	}
+	client := fake.NewSimpleClientset(throttled("train-phase-1-master-0", "PyTorchJob"), throttled("sdg-op", "Workflow"))
+	retries := make(chan PullRetry, 10)
+	stop := watchRegistryThrottling(clock, client, "ns", "run", PullRetryPolicy{MaxRetries: 2, Backoff: time.Minute}, time.Minute, func(retry PullRetry, err error) {
+		require.NoError(t, err)
+		retries <- retry
+	})
+	defer stop()
+
+	// Task pods are reported once, as Argo would not recreate them, the training pod is deleted after the backoff
+	clock.Step(time.Minute)
+	reported := map[string]PullRetry{}
+	for i := 0; i < 2; i++ {
+		retry := <-retries
+		reported[retry.Pod] = retry
+	}
+	require.False(t, reported["sdg-op"].Recreated || reported["sdg-op"].Exhausted)
+	require.True(t, reported["train-phase-1-master-0"].Recreated)
+	require.Equal(t, 1, reported["train-phase-1-master-0"].Retry)
+	_, err := client.CoreV1().Pods("ns").Get(context.Background(), "train-phase-1-master-0", metav1.GetOptions{})
+	require.Error(t, err)
+
+	// The recreated pod waits twice as long
+	_, err = client.CoreV1().Pods("ns").Create(context.Background(), throttled("train-phase-1-master-0", "PyTorchJob"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	clock.Step(time.Minute)
+	require.Never(t, func() bool { return len(retries) > 0 }, 50*time.Millisecond, 10*time.Millisecond)
+	clock.Step(time.Minute)
+	retry := <-retries
+	require.Equal(t, 2, retry.Retry)
+	require.True(t, retry.Recreated)
+
+	_, err = client.CoreV1().Pods("ns").Create(context.Background(), throttled("train-phase-1-master-0", "PyTorchJob"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	clock.Step(time.Minute)
+	retry = <-retries
+	require.True(t, retry.Exhausted)
+	require.Contains(t, retry.String(), "still fails to pull quay.io/opendatahub/training:latest after 2 recreations")
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// DefaultArtifactsDir is where reports and collected artifacts are written when ARTIFACTS_DIR is not set
+const DefaultArtifactsDir = "artifacts"
+
+// ArtifactsDir returns the directory for reports and collected artifacts, creating it if needed
+func ArtifactsDir(t *testing.T) string {
+	dir := os.Getenv("ARTIFACTS_DIR")
+	if dir == "" {
+		dir = DefaultArtifactsDir
+	}
+	require.NoError(t, os.MkdirAll(dir, 0o755), "Failed to create artifacts directory")
+	return dir
+}
+
+// WriteArtifact writes a file into the artifacts directory and returns its path
+func WriteArtifact(t *testing.T, name string, content []byte) string {
+	path := filepath.Join(ArtifactsDir(t), name)
+	require.NoError(t, os.WriteFile(path, content, 0o644), "Failed to write artifact %s", name)
+	return path
+}
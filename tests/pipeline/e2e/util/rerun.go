@@ -0,0 +1,63 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FailureMessages returns the error messages of a run and of its failed tasks
+func (d RunDetails) FailureMessages() []string {
+	var messages []string
+	if d.Error.Message != "" {
+		messages = append(messages, d.Error.Message)
+	}
+	for _, task := range d.RunDetails.TaskDetails {
+		if task.Error.Message != "" {
+			messages = append(messages, fmt.Sprintf("%s: %s", task.DisplayName, task.Error.Message))
+		}
+	}
+	return messages
+}
+
+// CachedTasks returns the display names of the tasks the run reused from a previous run
+func (d RunDetails) CachedTasks() []string {
+	var cached []string
+	for _, task := range d.RunDetails.TaskDetails {
+		if task.State == "CACHED" {
+			cached = append(cached, task.DisplayName)
+		}
+	}
+	return cached
+}
+
+// CheckIdempotentRerun verifies a second run over the outputs of a successful one either succeeded, reusing or
+// redoing the completed work, or failed with a clear "already exists" message
+func CheckIdempotentRerun(details RunDetails) error {
+	switch details.State {
+	case "SUCCEEDED":
+		return nil
+	case "FAILED":
+		messages := details.FailureMessages()
+		for _, message := range messages {
+			if strings.Contains(strings.ToLower(message), "already exists") {
+				return nil
+			}
+		}
+		return fmt.Errorf("second run failed without an \"already exists\" message: %s", strings.Join(messages, "; "))
+	default:
+		return fmt.Errorf("second run ended with status %s", details.State)
+	}
+}
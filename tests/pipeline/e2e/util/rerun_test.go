@@ -0,0 +1,42 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckIdempotentRerun(t *testing.T) {
+	parse := func(body string) RunDetails {
+		var details RunDetails
+		require.NoError(t, json.Unmarshal([]byte(body), &details))
+		return details
+	}
+
+	cached := parse(`{"run_id": "b", "state": "SUCCEEDED", "run_details": {"task_details": [
+		{"display_name": "sdg-op", "state": "CACHED"}, {"display_name": "pytorchjob-manifest-op", "state": "SUCCEEDED"}]}}`)
+	require.NoError(t, CheckIdempotentRerun(cached))
+	require.Equal(t, []string{"sdg-op"}, cached.CachedTasks())
+
+	exists := parse(`{"state": "FAILED", "run_details": {"task_details": [
+		{"display_name": "createpvc", "state": "FAILED", "error": {"message": "persistentvolumeclaims \"data\" already exists"}}]}}`)
+	require.NoError(t, CheckIdempotentRerun(exists))
+
+	unclear := parse(`{"state": "FAILED", "error": {"message": "exit status 1"}}`)
+	require.EqualError(t, CheckIdempotentRerun(unclear), `second run failed without an "already exists" message: exit status 1`)
+	require.EqualError(t, CheckIdempotentRerun(RunDetails{State: "CANCELED"}), "second run ended with status CANCELED")
+}
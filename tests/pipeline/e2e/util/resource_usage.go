@@ -0,0 +1,240 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
+)
+
+var trainingPodPattern = regexp.MustCompile(`^train-phase-(\d+)-`)
+
+// PodUsage is the CPU and memory usage of a pod reported by the metrics server
+type PodUsage struct {
+	CPUMillis   int64
+	MemoryBytes int64
+}
+
+// PhaseUsage accounts the resource usage of the pods of a pipeline phase, summed over the pods running concurrently
+type PhaseUsage struct {
+	PeakCPUMillis   int64
+	PeakMemoryBytes int64
+	// CPUCoreSeconds and MemoryByteSeconds integrate the usage over the samples
+	CPUCoreSeconds    float64
+	MemoryByteSeconds float64
+	Pods              map[string]bool
+}
+
+// ResourceUsage accounts the resource usage of a pipeline run by phase
+type ResourceUsage map[string]*PhaseUsage
+
+type podMetricsList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Containers []struct {
+			Usage map[string]string `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+// ListPodMetrics returns the current usage of the pods of a namespace from the metrics server
+func ListPodMetrics(client kubernetes.Interface, namespace string) (map[string]PodUsage, error) {
+	body, err := client.CoreV1().RESTClient().Get().
+		AbsPath("/apis/metrics.k8s.io/v1beta1/namespaces", namespace, "pods").
+		DoRaw(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve pod metrics: %w", err)
+	}
+	var list podMetricsList
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("invalid pod metrics: %w", err)
+	}
+
+	usage := map[string]PodUsage{}
+	for _, item := range list.Items {
+		var pod PodUsage
+		for _, container := range item.Containers {
+			if cpu, err := resource.ParseQuantity(container.Usage["cpu"]); err == nil {
+				pod.CPUMillis += cpu.MilliValue()
+			}
+			if memory, err := resource.ParseQuantity(container.Usage["memory"]); err == nil {
+				pod.MemoryBytes += memory.Value()
+			}
+		}
+		usage[item.Metadata.Name] = pod
+	}
+	return usage, nil
+}
+
+// RunPodPhases maps the task pods and training pods of a run to their pipeline phase
+func RunPodPhases(tasks []TaskPod, trainingPods []corev1.Pod) map[string]string {
+	phases := map[string]string{}
+	for _, task := range tasks {
+		if phase := TaskPhase(task); phase != "" {
+			phases[task.Pod.Name] = phase
+		}
+	}
+	for _, pod := range trainingPods {
+		if match := trainingPodPattern.FindStringSubmatch(pod.Name); match != nil {
+			phases[pod.Name] = "training-phase-" + match[1]
+		}
+	}
+	return phases
+}
+
+// Record accounts a sample of pod metrics, taken every interval, to the phases of the pods
+func (u ResourceUsage) Record(podPhases map[string]string, metrics map[string]PodUsage, interval time.Duration) {
+	sampled := map[string]PodUsage{}
+	for pod, phase := range podPhases {
+		usage, ok := metrics[pod]
+		if !ok {
+			continue
+		}
+		total := sampled[phase]
+		total.CPUMillis += usage.CPUMillis
+		total.MemoryBytes += usage.MemoryBytes
+		sampled[phase] = total
+
+		if u[phase] == nil {
+			u[phase] = &PhaseUsage{Pods: map[string]bool{}}
+		}
+		u[phase].Pods[pod] = true
+	}
+
+	for phase, total := range sampled {
+		usage := u[phase]
+		if total.CPUMillis > usage.PeakCPUMillis {
+			usage.PeakCPUMillis = total.CPUMillis
+		}
+		if total.MemoryBytes > usage.PeakMemoryBytes {
+			usage.PeakMemoryBytes = total.MemoryBytes
+		}
+		usage.CPUCoreSeconds += float64(total.CPUMillis) / 1000 * interval.Seconds()
+		usage.MemoryByteSeconds += float64(total.MemoryBytes) * interval.Seconds()
+	}
+}
+
+// WatchResourceUsage samples the usage of the pods of a pipeline run, training pods included, at every interval,
+// until the returned stop function is called, which returns the usage by phase. Sampling errors are passed to report.
+func WatchResourceUsage(client kubernetes.Interface, namespace, runID string, interval time.Duration, report func(error)) (stop func() ResourceUsage) {
+	return watchResourceUsage(client, namespace, interval, report, func() (map[string]string, error) {
+		tasks, err := ListRunTaskPods(client, namespace, runID)
+		if err != nil {
+			return nil, err
+		}
+		var runPods []corev1.Pod
+		for _, task := range tasks {
+			runPods = append(runPods, task.Pod)
+		}
+		trainingPods, err := ListTrainingPods(client, namespace, runPods)
+		if err != nil {
+			return nil, err
+		}
+		return RunPodPhases(tasks, trainingPods), nil
+	})
+}
+
+// WatchWorkflowResourceUsage samples the usage of the task pods of a workflow run as WatchResourceUsage does for a
+// pipeline run, each pod accounted to the phase of its task
+func WatchWorkflowResourceUsage(client kubernetes.Interface, namespace, runID string, workflow Workflow, interval time.Duration, report func(error)) (stop func() ResourceUsage) {
+	return watchResourceUsage(client, namespace, interval, report, func() (map[string]string, error) {
+		pods, err := ListWorkflowTaskPods(client, namespace, runID)
+		if err != nil {
+			return nil, err
+		}
+		phases := map[string]string{}
+		for _, pod := range pods {
+			if phase, ok := workflow.taskPhase(pod.Labels[WorkflowTaskLabel]); ok {
+				phases[pod.Name] = phase
+			}
+		}
+		return phases, nil
+	})
+}
+
+// watchResourceUsage samples the usage of the pods returned by podPhases, which maps them to their phase
+func watchResourceUsage(client kubernetes.Interface, namespace string, interval time.Duration, report func(error), podPhases func() (map[string]string, error)) (stop func() ResourceUsage) {
+	usage := ResourceUsage{}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	metrics := &capabilityGate{capability: PodMetricsCapability}
+	go func() {
+		defer close(stopped)
+		tick := time.Tick(interval)
+		for {
+			select {
+			case <-done:
+				return
+			case <-tick:
+				// Without the metrics there is nothing to sample, the usage stays empty
+				if !metrics.available() {
+					continue
+				}
+				err := sampleResourceUsage(client, namespace, interval, usage, metrics, podPhases)
+				if notice := metrics.notice(); notice != nil {
+					report(notice)
+				} else if err != nil {
+					report(err)
+				}
+			}
+		}
+	}()
+	return func() ResourceUsage {
+		close(done)
+		<-stopped
+		return usage
+	}
+}
+
+func sampleResourceUsage(client kubernetes.Interface, namespace string, interval time.Duration, usage ResourceUsage, metricsGate *capabilityGate, podPhases func() (map[string]string, error)) error {
+	phases, err := podPhases()
+	if err != nil {
+		return err
+	}
+	metrics, err := ListPodMetrics(client, namespace)
+	if err != nil {
+		metricsGate.deny(err)
+		return err
+	}
+	usage.Record(phases, metrics, interval)
+	return nil
+}
+
+// RenderResourceUsage renders the resource usage of a run by phase as a Markdown report
+func RenderResourceUsage(usage ResourceUsage) string {
+	const gib = 1 << 30
+	var report strings.Builder
+	report.WriteString("# Resource usage\n\n")
+	report.WriteString("| Phase | Pods | Peak CPU (cores) | Peak memory (GiB) | CPU (core-hours) | Memory (GiB-hours) |\n")
+	report.WriteString("|---|---|---|---|---|---|\n")
+	for _, phase := range PipelinePhases {
+		u, ok := usage[phase]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&report, "| %s | %d | %.2f | %.2f | %.3f | %.3f |\n", phase, len(u.Pods),
+			float64(u.PeakCPUMillis)/1000, float64(u.PeakMemoryBytes)/gib, u.CPUCoreSeconds/3600, u.MemoryByteSeconds/gib/3600)
+	}
+	return report.String()
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResourceUsage(t *testing.T) {
+	tasks := []TaskPod{
+		{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "run-sdg"}}, Function: "sdg_op"},
+		{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "run-launcher"}}, Function: "pytorch_job_launcher_op", Parameters: map[string]interface{}{"phase_num": float64(1)}},
+	}
+	training := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "train-phase-1-abc-master-0"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "train-phase-1-abc-worker-0"}},
+	}
+	phases := RunPodPhases(tasks, training)
+	require.Equal(t, map[string]string{
+		"run-sdg":                    "sdg",
+		"run-launcher":               "training-phase-1",
+		"train-phase-1-abc-master-0": "training-phase-1",
+		"train-phase-1-abc-worker-0": "training-phase-1",
+	}, phases)
+
+	usage := ResourceUsage{}
+	usage.Record(phases, map[string]PodUsage{
+		"run-sdg":                    {CPUMillis: 500, MemoryBytes: 1 << 30},
+		"train-phase-1-abc-master-0": {CPUMillis: 2000, MemoryBytes: 4 << 30},
+		"train-phase-1-abc-worker-0": {CPUMillis: 1000, MemoryBytes: 2 << 30},
+	}, time.Minute)
+	usage.Record(phases, map[string]PodUsage{"run-sdg": {CPUMillis: 1500, MemoryBytes: 1 << 29}}, time.Minute)
+
+	require.Equal(t, int64(1500), usage["sdg"].PeakCPUMillis)
+	require.Equal(t, int64(1<<30), usage["sdg"].PeakMemoryBytes)
+	require.InDelta(t, 120, usage["sdg"].CPUCoreSeconds, 0.001)
+	require.Equal(t, int64(3000), usage["training-phase-1"].PeakCPUMillis)
+	require.Len(t, usage["training-phase-1"].Pods, 2)
+
+	require.Equal(t, "# Resource usage\n\n"+
+		"| Phase | Pods | Peak CPU (cores) | Peak memory (GiB) | CPU (core-hours) | Memory (GiB-hours) |\n"+
+		"|---|---|---|---|---|---|\n"+
+		"| sdg | 1 | 1.50 | 1.00 | 0.033 | 0.025 |\n"+
+		"| training-phase-1 | 2 | 3.00 | 6.00 | 0.050 | 0.100 |\n", RenderResourceUsage(usage))
+}
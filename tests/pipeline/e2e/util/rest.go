@@ -18,11 +18,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"k8s.io/utils/clock"
 )
 
 type PipelineRequest struct {
@@ -31,7 +34,8 @@ type PipelineRequest struct {
 		PipelineID string `json:"pipeline_id"`
 	} `json:"pipeline_version_reference"`
 	RuntimeConfig struct {
-		Parameters map[string]interface{} `json:"parameters"`
+		Parameters   map[string]interface{} `json:"parameters"`
+		PipelineRoot string                 `json:"pipeline_root,omitempty"`
 	} `json:"runtime_config"`
 }
 
@@ -70,18 +74,104 @@ func RetrievePipelineId(t *testing.T, pipelineServerURL, pipelineDisplayName, be
 	return "", fmt.Errorf("pipeline with display name '%s' not found", pipelineDisplayName)
 }
 
+// UploadPipeline uploads a compiled pipeline under the given display name and returns its pipeline ID
+func UploadPipeline(t *testing.T, pipelineServerURL, pipelineDisplayName string, pipelineYAML []byte, bearerToken string) (string, error) {
+	client := &http.Client{}
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("uploadfile", "pipeline.yaml")
+	require.NoError(t, err, "Failed to create multipart form")
+	_, err = part.Write(pipelineYAML)
+	require.NoError(t, err, "Failed to write pipeline to multipart form")
+	require.NoError(t, writer.Close(), "Failed to close multipart form")
+
+	uploadURL := fmt.Sprintf("%s/apis/v2beta1/pipelines/upload?name=%s&display_name=%s", pipelineServerURL, url.QueryEscape(pipelineDisplayName), url.QueryEscape(pipelineDisplayName))
+	req, err := http.NewRequest("POST", uploadURL, body)
+	require.NoError(t, err, "Failed to create HTTP request")
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Add("Authorization", "Bearer "+bearerToken)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err, "Failed to execute HTTP request")
+	defer resp.Body.Close()
+
+	responseData, err := io.ReadAll(resp.Body)
+	require.NoError(t, err, "Failed to read response body")
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("pipeline upload failed with status %d: %s", resp.StatusCode, string(responseData))
+	}
+
+	var response map[string]interface{}
+	err = json.Unmarshal(responseData, &response)
+	require.NoError(t, err, "Failed to parse response")
+
+	pipelineID, ok := response["pipeline_id"].(string)
+	if !ok {
+		return "", fmt.Errorf("pipeline_id not found in response")
+	}
+	return pipelineID, nil
+}
+
+// DeletePipeline deletes an uploaded pipeline, its versions first as the pipeline server refuses to delete a
+// pipeline with versions
+func DeletePipeline(t *testing.T, pipelineServerURL, pipelineID, bearerToken string) error {
+	do := func(method, path string) ([]byte, error) {
+		req, err := http.NewRequest(method, pipelineServerURL+path, nil)
+		require.NoError(t, err, "Failed to create HTTP request")
+		req.Header.Add("Authorization", "Bearer "+bearerToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err, "Failed to execute HTTP request")
+		defer resp.Body.Close()
+
+		responseData, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, "Failed to read response body")
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("%s %s failed with status %d: %s", method, path, resp.StatusCode, string(responseData))
+		}
+		return responseData, nil
+	}
+
+	versionsPath := fmt.Sprintf("/apis/v2beta1/pipelines/%s/versions", pipelineID)
+	responseData, err := do("GET", versionsPath)
+	if err != nil {
+		return err
+	}
+	var versions struct {
+		PipelineVersions []struct {
+			PipelineVersionID string `json:"pipeline_version_id"`
+		} `json:"pipeline_versions"`
+	}
+	err = json.Unmarshal(responseData, &versions)
+	require.NoError(t, err, "Failed to parse pipeline versions")
+
+	for _, version := range versions.PipelineVersions {
+		if _, err := do("DELETE", versionsPath+"/"+version.PipelineVersionID); err != nil {
+			return err
+		}
+	}
+	_, err = do("DELETE", fmt.Sprintf("/apis/v2beta1/pipelines/%s", pipelineID))
+	return err
+}
+
 // TriggerPipeline starts the pipeline and returns the run ID
 func TriggerPipeline(t *testing.T, pipelineServerURL, pipelineID, pipelineDisplayName string, parameters map[string]interface{}, bearerToken string) (string, error) {
+	return TriggerPipelineWithRoot(t, pipelineServerURL, pipelineID, pipelineDisplayName, parameters, "", bearerToken)
+}
+
+// TriggerPipelineWithRoot starts the pipeline with its artifacts stored under the given pipeline root and returns
+// the run ID. An empty pipeline root keeps the default of the pipeline server.
+func TriggerPipelineWithRoot(t *testing.T, pipelineServerURL, pipelineID, pipelineDisplayName string, parameters map[string]interface{}, pipelineRoot, bearerToken string) (string, error) {
 	client := &http.Client{}
 	payload := PipelineRequest{
 		DisplayName: pipelineDisplayName,
 		PipelineVersionReference: struct {
 			PipelineID string `json:"pipeline_id"`
 		}{PipelineID: pipelineID},
-		RuntimeConfig: struct {
-			Parameters map[string]interface{} `json:"parameters"`
-		}{Parameters: parameters},
 	}
+	payload.RuntimeConfig.Parameters = parameters
+	payload.RuntimeConfig.PipelineRoot = pipelineRoot
 
 	payloadBytes, err := json.Marshal(payload)
 	require.NoError(t, err, "Failed to marshal pipeline request payload")
@@ -109,47 +199,100 @@ func TriggerPipeline(t *testing.T, pipelineServerURL, pipelineID, pipelineDispla
 	return runID, nil
 }
 
-// WaitForPipelineSuccess polls the pipeline run status until it succeeds or times out
-func WaitForPipelineSuccess(t *testing.T, pipelineServerURL, runID string, bearerToken string) error {
-	client := &http.Client{}
+// RunDetails is the state of a pipeline run and of its tasks, as reported by the pipeline server
+type RunDetails struct {
+	RunID string `json:"run_id"`
+	State string `json:"state"`
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+	RunDetails struct {
+		TaskDetails []struct {
+			DisplayName string `json:"display_name"`
+			State       string `json:"state"`
+			Error       struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		} `json:"task_details"`
+	} `json:"run_details"`
+}
 
-	url := fmt.Sprintf("%s/apis/v2beta1/runs/%s", pipelineServerURL, runID)
+// Finished reports whether the run reached a final state
+func (d RunDetails) Finished() bool {
+	switch d.State {
+	case "SUCCEEDED", "SKIPPED", "FAILED", "CANCELING", "CANCELED", "PAUSED":
+		return true
+	}
+	return false
+}
 
-	timeout := time.After(2*time.Hour + 10*time.Minute)
-	tick := time.Tick(1 * time.Minute) // Poll every 1 minute
+// GetRunDetails retrieves the state of a pipeline run and of its tasks
+func GetRunDetails(t *testing.T, pipelineServerURL, runID, bearerToken string) (RunDetails, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/apis/v2beta1/runs/%s", pipelineServerURL, runID), nil)
+	require.NoError(t, err, "Failed to create HTTP request")
 
-	for {
-		select {
-		case <-timeout:
-			return fmt.Errorf("pipeline run %s timed out", runID)
-		case <-tick:
-			req, err := http.NewRequest("GET", url, nil)
-			require.NoError(t, err, "Failed to create HTTP request")
+	// Add Bearer token for authorization
+	req.Header.Add("Authorization", "Bearer "+bearerToken)
 
-			// Add Bearer token for authorization
-			req.Header.Add("Authorization", "Bearer "+bearerToken)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err, "Failed to retrieve pipeline run status")
+	defer resp.Body.Close()
 
-			resp, err := client.Do(req)
-			require.NoError(t, err, "Failed to retrieve pipeline run status")
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err, "Failed to read response body")
 
-			body, err := io.ReadAll(resp.Body)
-			require.NoError(t, err, "Failed to read response body")
+	var details RunDetails
+	err = json.Unmarshal(body, &details)
+	require.NoError(t, err, "Failed to parse pipeline run status")
+	if details.State == "" {
+		return details, fmt.Errorf("invalid state format in pipeline run status")
+	}
+	return details, nil
+}
 
-			var data map[string]interface{}
-			err = json.Unmarshal(body, &data)
-			require.NoError(t, err, "Failed to parse pipeline run status")
+// DefaultRunTimeout is the time a pipeline run is waited for unless the test sets another timeout
+const DefaultRunTimeout = 2*time.Hour + 10*time.Minute
 
-			state, ok := data["state"].(string)
-			if !ok {
-				return fmt.Errorf("invalid state format in pipeline run status")
-			}
+// WaitForPipelineCompletion polls the pipeline run status until it reaches a final state or times out
+func WaitForPipelineCompletion(t *testing.T, pipelineServerURL, runID string, bearerToken string) (RunDetails, error) {
+	return WaitForPipelineCompletionWithin(t, pipelineServerURL, runID, bearerToken, DefaultRunTimeout)
+}
+
+// WaitForPipelineCompletionWithin polls the pipeline run status until it reaches a final state or the timeout expires
+func WaitForPipelineCompletionWithin(t *testing.T, pipelineServerURL, runID string, bearerToken string, timeout time.Duration) (RunDetails, error) {
+	return waitForPipelineCompletion(t, clock.RealClock{}, pipelineServerURL, runID, bearerToken, timeout)
+}
 
-			switch state {
-			case "SUCCEEDED":
-				return nil
-			case "SKIPPED", "FAILED", "CANCELING", "CANCELED", "PAUSED":
-				return fmt.Errorf("pipeline run failed with status: %s", state)
+func waitForPipelineCompletion(t *testing.T, clk clock.Clock, pipelineServerURL, runID string, bearerToken string, timeout time.Duration) (RunDetails, error) {
+	deadline := clk.After(timeout)
+	tick := clk.Tick(1 * time.Minute) // Poll every 1 minute
+
+	for {
+		select {
+		case <-deadline:
+			return RunDetails{}, fmt.Errorf("pipeline run %s timed out after %s", runID, timeout)
+		case <-tick:
+			details, err := GetRunDetails(t, pipelineServerURL, runID, bearerToken)
+			if err != nil || details.Finished() {
+				return details, err
 			}
 		}
 	}
 }
+
+// WaitForPipelineSuccess polls the pipeline run status until it succeeds or times out
+func WaitForPipelineSuccess(t *testing.T, pipelineServerURL, runID string, bearerToken string) error {
+	return WaitForPipelineSuccessWithin(t, pipelineServerURL, runID, bearerToken, DefaultRunTimeout)
+}
+
+// WaitForPipelineSuccessWithin polls the pipeline run status until it succeeds or the timeout expires
+func WaitForPipelineSuccessWithin(t *testing.T, pipelineServerURL, runID string, bearerToken string, timeout time.Duration) error {
+	details, err := WaitForPipelineCompletionWithin(t, pipelineServerURL, runID, bearerToken, timeout)
+	if err != nil {
+		return err
+	}
+	if details.State != "SUCCEEDED" {
+		return fmt.Errorf("pipeline run failed with status: %s", details.State)
+	}
+	return nil
+}
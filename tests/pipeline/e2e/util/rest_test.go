@@ -0,0 +1,137 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestUploadPipeline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "POST", r.Method)
+		require.Equal(t, "/apis/v2beta1/pipelines/upload", r.URL.Path)
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		require.Equal(t, "my pipeline", r.URL.Query().Get("display_name"))
+
+		file, header, err := r.FormFile("uploadfile")
+		require.NoError(t, err)
+		defer file.Close()
+		content, err := io.ReadAll(file)
+		require.NoError(t, err)
+		require.Equal(t, "pipeline.yaml", header.Filename)
+
+		if string(content) == "invalid" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid pipeline spec"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"pipeline_id": "p-1", "display_name": "my pipeline"})
+	}))
+	defer server.Close()
+
+	pipelineID, err := UploadPipeline(t, server.URL, "my pipeline", []byte("pipelineInfo: {}"), "token")
+	require.NoError(t, err)
+	require.Equal(t, "p-1", pipelineID)
+
+	_, err = UploadPipeline(t, server.URL, "my pipeline", []byte("invalid"), "token")
+	require.ErrorContains(t, err, "status 400")
+	require.ErrorContains(t, err, "invalid pipeline spec")
+}
+
+func TestDeletePipeline(t *testing.T) {
+	var requests []string
+	failVersion := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == "GET":
+			_, _ = w.Write([]byte(`{"pipeline_versions":[{"pipeline_version_id":"v-1"},{"pipeline_version_id":"v-2"}]}`))
+		case r.URL.Path == "/apis/v2beta1/pipelines/p-1/versions/"+failVersion:
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	require.NoError(t, DeletePipeline(t, server.URL, "p-1", "token"))
+	require.Equal(t, []string{
+		"GET /apis/v2beta1/pipelines/p-1/versions",
+		"DELETE /apis/v2beta1/pipelines/p-1/versions/v-1",
+		"DELETE /apis/v2beta1/pipelines/p-1/versions/v-2",
+		"DELETE /apis/v2beta1/pipelines/p-1",
+	}, requests)
+
+	// The pipeline is kept when one of its versions cannot be deleted
+	requests, failVersion = nil, "v-1"
+	err := DeletePipeline(t, server.URL, "p-1", "token")
+	require.ErrorContains(t, err, "status 403")
+	require.Equal(t, []string{"GET /apis/v2beta1/pipelines/p-1/versions", "DELETE /apis/v2beta1/pipelines/p-1/versions/v-1"}, requests)
+}
+
+func TestWaitForPipelineCompletion(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/apis/v2beta1/runs/run-1", r.URL.Path)
+		state := "RUNNING"
+		if polls.Add(1) >= 3 {
+			state = "SUCCEEDED"
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"run_id": "run-1", "state": state})
+	}))
+	defer server.Close()
+
+	// wait polls the run in the background, stepping the clock a poll interval at a time until it returns
+	wait := func(timeout time.Duration) (RunDetails, time.Duration, error) {
+		clock := testingclock.NewFakeClock(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+		start := clock.Now()
+		type result struct {
+			details RunDetails
+			err     error
+		}
+		done := make(chan result)
+		go func() {
+			details, err := waitForPipelineCompletion(t, clock, server.URL, "run-1", "token", timeout)
+			done <- result{details, err}
+		}()
+		for {
+			select {
+			case r := <-done:
+				return r.details, clock.Since(start), r.err
+			case <-time.After(10 * time.Millisecond):
+				clock.Step(time.Minute)
+			}
+		}
+	}
+
+	details, elapsed, err := wait(time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, "SUCCEEDED", details.State)
+	require.Less(t, elapsed, time.Hour)
+
+	polls.Store(-1000)
+	_, elapsed, err = wait(10 * time.Minute)
+	require.ErrorContains(t, err, "timed out after 10m0s")
+	require.GreaterOrEqual(t, elapsed, 10*time.Minute)
+}
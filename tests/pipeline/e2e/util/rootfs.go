@@ -0,0 +1,193 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/watcher"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// writablePathEnvSuffixes identifies environment variables pointing at directories the process writes to
+var writablePathEnvSuffixes = []string{"HOME", "CACHE", "CACHE_DIR", "TMPDIR"}
+
+// RootFSFinding is the read-only root filesystem audit result of one container
+type RootFSFinding struct {
+	Pod                string
+	Container          string
+	Image              string
+	ReadOnlyRootFS     bool
+	Writables          []string
+	UncoveredWritables []string
+}
+
+// AuditReadOnlyRootFS reports, for every container, whether its root filesystem is read-only and which of the paths
+// it writes to are not backed by a volume and would break under a read-only root filesystem
+func AuditReadOnlyRootFS(pods []corev1.Pod) []RootFSFinding {
+	var findings []RootFSFinding
+	for _, pod := range pods {
+		for _, container := range pod.Spec.Containers {
+			finding := RootFSFinding{
+				Pod:            pod.Name,
+				Container:      container.Name,
+				Image:          container.Image,
+				ReadOnlyRootFS: container.SecurityContext != nil && container.SecurityContext.ReadOnlyRootFilesystem != nil && *container.SecurityContext.ReadOnlyRootFilesystem,
+				Writables:      writablePaths(container),
+			}
+			for _, writable := range finding.Writables {
+				if !coveredByWritableMount(writable, container.VolumeMounts) {
+					finding.UncoveredWritables = append(finding.UncoveredWritables, writable)
+				}
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// EmptyDirsForWritablePaths returns the emptyDir volumes and mounts that make the given paths writable
+func EmptyDirsForWritablePaths(paths []string) ([]corev1.Volume, []corev1.VolumeMount) {
+	var volumes []corev1.Volume
+	var mounts []corev1.VolumeMount
+	for i, writable := range paths {
+		name := fmt.Sprintf("writable-%d", i)
+		volumes = append(volumes, corev1.Volume{Name: name, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}})
+		mounts = append(mounts, corev1.VolumeMount{Name: name, MountPath: writable})
+	}
+	return volumes, mounts
+}
+
+// ImageWritablePaths returns the writable paths of the audited containers by image, the paths every container of an
+// image needs when run from that image with a read-only root filesystem
+func ImageWritablePaths(findings []RootFSFinding) map[string][]string {
+	paths := map[string]map[string]bool{}
+	for _, finding := range findings {
+		if paths[finding.Image] == nil {
+			paths[finding.Image] = map[string]bool{}
+		}
+		for _, writable := range finding.Writables {
+			paths[finding.Image][writable] = true
+		}
+	}
+
+	images := map[string][]string{}
+	for image, writables := range paths {
+		for writable := range writables {
+			images[image] = append(images[image], writable)
+		}
+		sort.Strings(images[image])
+	}
+	return images
+}
+
+// ReadOnlyRootFSProbePod returns a pod running the image with a read-only root filesystem and an emptyDir volume for
+// each writable path, which succeeds when it can write to every path and fails on the first path it cannot write to
+func ReadOnlyRootFSProbePod(generateName, image string, writables []string) *corev1.Pod {
+	readOnly := true
+	volumes, mounts := EmptyDirsForWritablePaths(writables)
+
+	var script strings.Builder
+	for _, writable := range writables {
+		fmt.Fprintf(&script, "touch '%s/.rootfs-probe' || { echo 'cannot write to %s'; exit 1; }\n", writable, writable)
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: generateName},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Volumes:       volumes,
+			Containers: []corev1.Container{{
+				Name:            "probe",
+				Image:           image,
+				Command:         []string{"/bin/sh", "-c", script.String()},
+				SecurityContext: &corev1.SecurityContext{ReadOnlyRootFilesystem: &readOnly},
+				VolumeMounts:    mounts,
+			}},
+		},
+	}
+}
+
+// RunReadOnlyRootFSProbe runs a probe pod until it terminates and deletes it. It returns an error with the pod logs
+// when the probe failed.
+func RunReadOnlyRootFSProbe(t *testing.T, client kubernetes.Interface, namespace string, pod *corev1.Pod, timeout time.Duration) error {
+	created, err := client.CoreV1().Pods(namespace).Create(context.Background(), pod, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create read-only root filesystem probe")
+	defer func() {
+		if err := client.CoreV1().Pods(namespace).Delete(context.Background(), created.Name, metav1.DeleteOptions{}); err != nil {
+			t.Logf("Failed to delete probe pod %s: %v", created.Name, err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	err = watcher.Until(ctx, NewWatchClient(t), ctrlclient.ObjectKeyFromObject(created), &corev1.Pod{}, watcher.PodSucceeded)
+	if err != nil && ctx.Err() == nil {
+		logs, _ := client.CoreV1().Pods(namespace).GetLogs(created.Name, &corev1.PodLogOptions{}).DoRaw(context.Background())
+		return fmt.Errorf("probe pod %s failed: %s", created.Name, strings.TrimSpace(string(logs)))
+	}
+	return err
+}
+
+// RenderRootFSAudit renders the audit findings as a markdown report
+func RenderRootFSAudit(findings []RootFSFinding) string {
+	var report strings.Builder
+	report.WriteString("# Read-only root filesystem audit\n\n")
+	report.WriteString("| Pod | Container | Read-only root | Writable paths without a volume |\n")
+	report.WriteString("|---|---|---|---|\n")
+	for _, finding := range findings {
+		fmt.Fprintf(&report, "| %s | %s | %t | %s |\n", finding.Pod, finding.Container, finding.ReadOnlyRootFS, strings.Join(finding.UncoveredWritables, ", "))
+	}
+	return report.String()
+}
+
+func writablePaths(container corev1.Container) []string {
+	paths := map[string]bool{"/tmp": true}
+	for _, env := range container.Env {
+		if !strings.HasPrefix(env.Value, "/") {
+			continue
+		}
+		for _, suffix := range writablePathEnvSuffixes {
+			if strings.HasSuffix(env.Name, suffix) {
+				paths[path.Clean(env.Value)] = true
+			}
+		}
+	}
+
+	var sorted []string
+	for p := range paths {
+		sorted = append(sorted, p)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+func coveredByWritableMount(writable string, mounts []corev1.VolumeMount) bool {
+	for _, mount := range mounts {
+		mountPath := path.Clean(mount.MountPath)
+		if !mount.ReadOnly && (writable == mountPath || strings.HasPrefix(writable, mountPath+"/")) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAuditReadOnlyRootFS(t *testing.T) {
+	readOnly := true
+	pods := []corev1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{Name: "train"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{
+				Name:            "hardened",
+				SecurityContext: &corev1.SecurityContext{ReadOnlyRootFilesystem: &readOnly},
+				Env:             []corev1.EnvVar{{Name: "HF_HOME", Value: "/tmp/hf"}},
+				VolumeMounts:    []corev1.VolumeMount{{Name: "tmp", MountPath: "/tmp"}},
+			},
+			{
+				Name:         "legacy",
+				Env:          []corev1.EnvVar{{Name: "HOME", Value: "/opt/app-root/src"}, {Name: "MODEL", Value: "/model"}},
+				VolumeMounts: []corev1.VolumeMount{{Name: "model", MountPath: "/tmp", ReadOnly: true}},
+			},
+		}},
+	}}
+
+	findings := AuditReadOnlyRootFS(pods)
+	require.Len(t, findings, 2)
+	require.True(t, findings[0].ReadOnlyRootFS)
+	require.Empty(t, findings[0].UncoveredWritables)
+	require.False(t, findings[1].ReadOnlyRootFS)
+	require.Equal(t, []string{"/opt/app-root/src", "/tmp"}, findings[1].UncoveredWritables)
+
+	volumes, mounts := EmptyDirsForWritablePaths(findings[1].UncoveredWritables)
+	require.Len(t, volumes, 2)
+	require.NotNil(t, volumes[0].EmptyDir)
+	require.Equal(t, "/opt/app-root/src", mounts[0].MountPath)
+}
+
+func TestReadOnlyRootFSProbePod(t *testing.T) {
+	findings := []RootFSFinding{
+		{Image: "workbench:1", Writables: []string{"/opt/app-root/src", "/tmp"}},
+		{Image: "workbench:1", Writables: []string{"/tmp", "/tmp/hf"}},
+		{Image: "rhelai:1", Writables: []string{"/tmp"}},
+	}
+	images := ImageWritablePaths(findings)
+	require.Equal(t, map[string][]string{"workbench:1": {"/opt/app-root/src", "/tmp", "/tmp/hf"}, "rhelai:1": {"/tmp"}}, images)
+
+	pod := ReadOnlyRootFSProbePod("probe-", "workbench:1", images["workbench:1"])
+	container := pod.Spec.Containers[0]
+	require.True(t, *container.SecurityContext.ReadOnlyRootFilesystem)
+	require.Len(t, pod.Spec.Volumes, 3)
+	require.Equal(t, "/tmp/hf", container.VolumeMounts[2].MountPath)
+	require.Contains(t, container.Command[2], "touch '/opt/app-root/src/.rootfs-probe'")
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// LoadRunLabels reads the labels set on the runs, e.g. app and team, from a YAML file, failing on labels Kubernetes
+// would reject
+func LoadRunLabels(t *testing.T, path string) map[string]string {
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig(), "Error loading run labels")
+
+	var runLabels struct {
+		Labels map[string]string `mapstructure:"labels"`
+	}
+	require.NoError(t, v.UnmarshalExact(&runLabels), "Error parsing run labels")
+	require.NoError(t, CostLabels{Labels: runLabels.Labels}.Validate(), "Invalid run labels in %s", path)
+	return runLabels.Labels
+}
+
+// RunResource is a resource created during a run, with its labels and the controller owning it
+type RunResource struct {
+	Kind   string
+	Name   string
+	Labels map[string]string
+	// Owner is the kind and name of the controller of the resource, e.g. PyTorchJob/train, empty without one
+	Owner string
+}
+
+func (r RunResource) String() string {
+	return r.Kind + "/" + r.Name
+}
+
+// ListRunResources lists the Jobs, PyTorchJobs, PVCs and pods of the namespace created since the start of a run,
+// whatever their labels, so resources dropping the labels of the run are found. The namespace is expected to be
+// dedicated to the runs. PyTorchJobs are skipped when the Training Operator is not installed.
+func ListRunResources(client kubernetes.Interface, dynamicClient dynamic.Interface, namespace string, since time.Time) ([]RunResource, error) {
+	ctx := context.Background()
+	// Creation timestamps have a precision of a second
+	since = since.Truncate(time.Second)
+	var resources []RunResource
+	add := func(kind string, meta metav1.Object) {
+		if meta.GetCreationTimestamp().Time.Before(since) {
+			return
+		}
+		resource := RunResource{Kind: kind, Name: meta.GetName(), Labels: meta.GetLabels()}
+		if owner := metav1.GetControllerOfNoCopy(meta); owner != nil {
+			resource.Owner = owner.Kind + "/" + owner.Name
+		}
+		resources = append(resources, resource)
+	}
+
+	jobs, err := client.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	for i := range jobs.Items {
+		add("Job", &jobs.Items[i])
+	}
+	pytorchJobs, err := dynamicClient.Resource(PyTorchJobGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to list PyTorchJobs: %w", err)
+	}
+	if err == nil {
+		for i := range pytorchJobs.Items {
+			add("PyTorchJob", &pytorchJobs.Items[i])
+		}
+	}
+	pvcs, err := client.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PVCs: %w", err)
+	}
+	for i := range pvcs.Items {
+		add("PersistentVolumeClaim", &pvcs.Items[i])
+	}
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for i := range pods.Items {
+		add("Pod", &pods.Items[i])
+	}
+	return resources, nil
+}
+
+// LabelDrop is a resource of a run missing labels of the run, or carrying another value
+type LabelDrop struct {
+	RunResource
+	// Missing are the expected labels, as key=value, the resource does not carry
+	Missing []string
+	// DroppedBy tells where the labels were lost: by the controller of the resource when its owner carries them, by
+	// whatever created the resource otherwise
+	DroppedBy string
+}
+
+func (d LabelDrop) String() string {
+	return fmt.Sprintf("%s is missing %s, dropped by %s", d.RunResource, strings.Join(d.Missing, ", "), d.DroppedBy)
+}
+
+// CheckRunLabels returns the resources of a run missing any of the labels, sorted by kind and name
+func CheckRunLabels(resources []RunResource, labels map[string]string) []LabelDrop {
+	byName := map[string]RunResource{}
+	for _, resource := range resources {
+		byName[resource.String()] = resource
+	}
+
+	var drops []LabelDrop
+	for _, resource := range resources {
+		var missing []string
+		for _, key := range sortedKeys(labels) {
+			if resource.Labels[key] != labels[key] {
+				missing = append(missing, key+"="+labels[key])
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		droppedBy := "the creator of the " + resource.Kind
+		if owner, ok := byName[resource.Owner]; ok && len(CheckRunLabels([]RunResource{owner}, labels)) == 0 {
+			droppedBy = fmt.Sprintf("the controller of %s, which carries them", owner)
+		} else if resource.Owner != "" {
+			droppedBy = fmt.Sprintf("%s or earlier", resource.Owner)
+		}
+		drops = append(drops, LabelDrop{RunResource: resource, Missing: missing, DroppedBy: droppedBy})
+	}
+	sort.Slice(drops, func(i, j int) bool { return drops[i].String() < drops[j].String() })
+	return drops
+}
+
+// RenderLabelDrops renders the label drops of a run as a Markdown report ready to file as an issue: the labels
+// expected, and for every kind of resource which resources dropped which labels and where
+func RenderLabelDrops(runID string, labels map[string]string, drops []LabelDrop) string {
+	var report strings.Builder
+	fmt.Fprintf(&report, "# Run labels not propagated in run %s\n\n", runID)
+	report.WriteString("Quota, network policy and cleanup tooling select the resources of a run by its labels. ")
+	report.WriteString("The run was labeled with:\n\n")
+	for _, key := range sortedKeys(labels) {
+		fmt.Fprintf(&report, "- `%s=%s`\n", key, labels[key])
+	}
+	if len(drops) == 0 {
+		report.WriteString("\nEvery resource of the run carries them.\n")
+		return report.String()
+	}
+
+	byKind := map[string][]LabelDrop{}
+	var kinds []string
+	for _, drop := range drops {
+		if _, ok := byKind[drop.Kind]; !ok {
+			kinds = append(kinds, drop.Kind)
+		}
+		byKind[drop.Kind] = append(byKind[drop.Kind], drop)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		fmt.Fprintf(&report, "\n## %s\n\n", kind)
+		report.WriteString("| Resource | Owner | Missing labels | Dropped by |\n")
+		report.WriteString("|---|---|---|---|\n")
+		for _, drop := range byKind[kind] {
+			owner := drop.Owner
+			if owner == "" {
+				owner = "-"
+			}
+			fmt.Fprintf(&report, "| %s | %s | %s | %s |\n", drop.Name, owner, "`"+strings.Join(drop.Missing, "`, `")+"`", drop.DroppedBy)
+		}
+	}
+	return report.String()
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRunLabels(t *testing.T) {
+	require.Equal(t, map[string]string{"app": "ilab-e2e", "team": "ilab-on-ocp"}, LoadRunLabels(t, "../resources/run_labels.yaml"))
+
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	labels := map[string]string{"app": "ilab", "team": "ilab-on-ocp", RunIDLabel: "run-1"}
+	meta := func(name string, created time.Time, labels map[string]string, owner ...metav1.OwnerReference) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: "ns", CreationTimestamp: metav1.NewTime(created), Labels: labels, OwnerReferences: owner}
+	}
+	controller := true
+	ownedBy := func(kind, name string) metav1.OwnerReference {
+		return metav1.OwnerReference{Kind: kind, Name: name, Controller: &controller}
+	}
+	client := fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: meta("sdg", start.Add(time.Minute), labels)},
+		&corev1.Pod{ObjectMeta: meta("earlier", start.Add(-time.Minute), nil)},
+		&corev1.Pod{ObjectMeta: meta("train-master-0", start.Add(3*time.Minute), map[string]string{"app": "ilab"}, ownedBy("PyTorchJob", "train"))},
+		&corev1.PersistentVolumeClaim{ObjectMeta: meta("data", start, map[string]string{RunIDLabel: "run-1"})},
+		&batchv1.Job{ObjectMeta: meta("importer", start.Add(time.Minute), labels)},
+	)
+	job := &unstructured.Unstructured{}
+	job.SetAPIVersion("kubeflow.org/v1")
+	job.SetKind("PyTorchJob")
+	job.SetNamespace("ns")
+	job.SetName("train")
+	job.SetCreationTimestamp(metav1.NewTime(start.Add(2 * time.Minute)))
+	job.SetLabels(labels)
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		PyTorchJobGVR: "PyTorchJobList",
+	}, job)
+
+	resources, err := ListRunResources(client, dynamicClient, "ns", start)
+	require.NoError(t, err)
+	require.Len(t, resources, 5)
+	drops := CheckRunLabels(resources, labels)
+	require.Equal(t, []string{
+		"PersistentVolumeClaim/data is missing app=ilab, team=ilab-on-ocp, dropped by the creator of the PersistentVolumeClaim",
+		"Pod/train-master-0 is missing pipeline/runid=run-1, team=ilab-on-ocp, dropped by the controller of PyTorchJob/train, which carries them",
+	}, []string{drops[0].String(), drops[1].String()})
+
+	report := RenderLabelDrops("run-1", labels, drops)
+	require.Contains(t, report, "- `team=ilab-on-ocp`\n")
+	require.Contains(t, report, "## Pod\n\n| Resource | Owner | Missing labels | Dropped by |\n|---|---|---|---|\n"+
+		"| train-master-0 | PyTorchJob/train | `pipeline/runid=run-1`, `team=ilab-on-ocp` | the controller of PyTorchJob/train, which carries them |\n")
+	require.Contains(t, RenderLabelDrops("run-1", labels, nil), "Every resource of the run carries them.")
+}
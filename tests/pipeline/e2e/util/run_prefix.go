@@ -0,0 +1,147 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+const (
+	// RunPrefixRoot is the bucket prefix under which every run stores its outputs
+	RunPrefixRoot = "runs/"
+
+	runPrefixTimeLayout = "20060102T150405Z"
+)
+
+// NewRunPrefix returns a unique bucket prefix for a run started at the given time, e.g.
+// "runs/20250101T120000Z-3f2b.../", so concurrent runs never write to the same keys
+func NewRunPrefix(start time.Time) string {
+	return fmt.Sprintf("%s%s-%s/", RunPrefixRoot, start.UTC().Format(runPrefixTimeLayout), uuid.NewUUID())
+}
+
+// ParseRunPrefix returns the run prefix an object key belongs to and the start time encoded in it
+func ParseRunPrefix(key string) (string, time.Time, bool) {
+	if !strings.HasPrefix(key, RunPrefixRoot) {
+		return "", time.Time{}, false
+	}
+	name, _, found := strings.Cut(strings.TrimPrefix(key, RunPrefixRoot), "/")
+	if !found || len(name) <= len(runPrefixTimeLayout) || name[len(runPrefixTimeLayout)] != '-' {
+		return "", time.Time{}, false
+	}
+	start, err := time.Parse(runPrefixTimeLayout, name[:len(runPrefixTimeLayout)])
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return RunPrefixRoot + name + "/", start, true
+}
+
+// CheckRunArtifactsScoped verifies the artifacts of a run were all written under its prefix. The pipeline server
+// stores artifacts under keys containing the run ID.
+func CheckRunArtifactsScoped(store ArtifactStore, prefix, runID string) error {
+	objects, err := store.List(context.Background(), "")
+	if err != nil {
+		return err
+	}
+	scoped := 0
+	for _, object := range objects {
+		if !strings.Contains(object.Key, runID) {
+			continue
+		}
+		if !strings.HasPrefix(object.Key, prefix) {
+			return fmt.Errorf("artifact %s of run %s is outside of the run prefix %s", object.Key, runID, prefix)
+		}
+		scoped++
+	}
+	if scoped == 0 {
+		return fmt.Errorf("no artifact of run %s found under %s", runID, prefix)
+	}
+	return nil
+}
+
+// ListObjectKeys returns the keys of the objects stored under a prefix
+func ListObjectKeys(store ArtifactStore, prefix string) ([]string, error) {
+	objects, err := store.List(context.Background(), prefix)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(objects))
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+	}
+	return keys, nil
+}
+
+// RunPrefixUsage summarizes the objects stored under a run prefix
+type RunPrefixUsage struct {
+	Prefix  string
+	Start   time.Time
+	Objects int
+	Size    int64
+}
+
+// ListRunPrefixes returns the run prefixes of a store sorted by start time, objects outside of run prefixes are ignored
+func ListRunPrefixes(store ArtifactStore) ([]RunPrefixUsage, error) {
+	objects, err := store.List(context.Background(), RunPrefixRoot)
+	if err != nil {
+		return nil, err
+	}
+	usage := map[string]*RunPrefixUsage{}
+	for _, object := range objects {
+		prefix, start, ok := ParseRunPrefix(object.Key)
+		if !ok {
+			continue
+		}
+		if usage[prefix] == nil {
+			usage[prefix] = &RunPrefixUsage{Prefix: prefix, Start: start}
+		}
+		usage[prefix].Objects++
+		usage[prefix].Size += object.Size
+	}
+
+	var prefixes []RunPrefixUsage
+	for _, u := range usage {
+		prefixes = append(prefixes, *u)
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return prefixes[i].Start.Before(prefixes[j].Start) })
+	return prefixes, nil
+}
+
+// ExpiredRunPrefixes returns the run prefixes started before the retention window
+func ExpiredRunPrefixes(prefixes []RunPrefixUsage, now time.Time, retention time.Duration) []RunPrefixUsage {
+	var expired []RunPrefixUsage
+	for _, prefix := range prefixes {
+		if prefix.Start.Before(now.Add(-retention)) {
+			expired = append(expired, prefix)
+		}
+	}
+	return expired
+}
+
+// DeleteRunPrefix removes every object stored under a run prefix
+func DeleteRunPrefix(store ArtifactStore, prefix string) error {
+	if _, _, ok := ParseRunPrefix(prefix); !ok {
+		return fmt.Errorf("'%s' is not a run prefix", prefix)
+	}
+	keys, err := ListObjectKeys(store, prefix)
+	if err != nil {
+		return err
+	}
+	return store.Delete(context.Background(), keys...)
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunPrefix(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC)
+	prefix := NewRunPrefix(start)
+	require.True(t, strings.HasPrefix(prefix, "runs/20250301T123000Z-"))
+	require.NotEqual(t, prefix, NewRunPrefix(start))
+
+	parsed, parsedStart, ok := ParseRunPrefix(prefix + "ilab/run-id/sdg-op/output")
+	require.True(t, ok)
+	require.Equal(t, prefix, parsed)
+	require.True(t, start.Equal(parsedStart))
+
+	for _, key := range []string{"models/granite", "runs/", "runs/latest/model", "runs/2025-03-01-abc/model"} {
+		_, _, ok := ParseRunPrefix(key)
+		require.False(t, ok, key)
+	}
+
+}
+
+func TestExpiredRunPrefixes(t *testing.T) {
+	now := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	prefixes := []RunPrefixUsage{
+		{Prefix: "runs/20250301T000000Z-a/", Start: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{Prefix: "runs/20250308T000000Z-b/", Start: time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC)},
+	}
+
+	expired := ExpiredRunPrefixes(prefixes, now, 7*24*time.Hour)
+	require.Len(t, expired, 1)
+	require.Equal(t, "runs/20250301T000000Z-a/", expired[0].Prefix)
+	require.Empty(t, ExpiredRunPrefixes(prefixes, now, 30*24*time.Hour))
+}
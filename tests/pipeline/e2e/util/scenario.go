@@ -0,0 +1,298 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ChaosEvictPod evicts the first running pod of a task, as a node drain does
+const ChaosEvictPod = "evict-pod"
+
+// Scenario is a pipeline run defined declaratively in a YAML file under tests/scenarios, see schema.json there
+type Scenario struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	// Orchestrator runs the scenario on the pipeline server, OrchestratorKFP by default, on one of WorkflowBackends or as
+	// standalone pods with OrchestratorPod
+	Orchestrator string       `yaml:"orchestrator"`
+	GPUs         ScenarioGPUs `yaml:"gpus"`
+	// Zones places the training workers in one zone with ZonesSingle, or across zones with ZonesSpread
+	Zones  string         `yaml:"zones"`
+	Images ScenarioImages `yaml:"images"`
+	// Compare runs the scenario once with each training image candidate instead, in parallel
+	Compare    []ScenarioCandidate    `yaml:"compare"`
+	Phases     []string               `yaml:"phases"`
+	Params     map[string]interface{} `yaml:"params"`
+	Chaos      []ChaosAction          `yaml:"chaos"`
+	Thresholds ScenarioThresholds     `yaml:"thresholds"`
+	Budget     ScenarioBudget         `yaml:"budget"`
+	Assertions []ScenarioAssertion    `yaml:"assertions"`
+	Checks     []string               `yaml:"checks"`
+	// Notify are the sinks notified of the outcome of the scenario
+	Notify []ScenarioNotification `yaml:"notify"`
+}
+
+// ScenarioGPUs is the training topology of a scenario, zero values keep pipeline_params.yaml
+type ScenarioGPUs struct {
+	PerWorker int `yaml:"per_worker"`
+	Workers   int `yaml:"workers"`
+}
+
+// ScenarioImages replaces the images compiled into pipeline.yaml, empty values keep the baseline
+type ScenarioImages struct {
+	SDG      string `yaml:"sdg"`
+	Training string `yaml:"training"`
+}
+
+// ScenarioCandidate is a training image candidate of a comparison, run in its own namespace
+type ScenarioCandidate struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace"`
+	Training  string `yaml:"training"`
+}
+
+// ChaosAction is a disruption applied to the run of a scenario once the task is running
+type ChaosAction struct {
+	Action string `yaml:"action"`
+	Task   string `yaml:"task"`
+}
+
+// ScenarioThresholds are the limits a scenario run must stay within, zero values are not checked
+type ScenarioThresholds struct {
+	MaxDuration          time.Duration `yaml:"max_duration"`
+	MaxSDGInvalidRowRate *float64      `yaml:"max_sdg_invalid_row_rate"`
+}
+
+// LoadScenarios reads and validates every scenario file in a directory, in file name order, against the schema.json
+// of the directory
+func LoadScenarios(dir string) ([]Scenario, error) {
+	schema, err := LoadJSONSchema(filepath.Join(dir, "schema.json"))
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var scenarios []Scenario
+	names := map[string]string{}
+	for _, file := range files {
+		scenario, err := LoadScenario(file, schema)
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := names[scenario.Name]; ok {
+			return nil, fmt.Errorf("scenario '%s' of %s is already defined in %s", scenario.Name, file, other)
+		}
+		names[scenario.Name] = file
+		scenarios = append(scenarios, scenario)
+	}
+	if len(scenarios) == 0 {
+		return nil, fmt.Errorf("no scenario files found in %s", dir)
+	}
+	return scenarios, nil
+}
+
+// LoadScenario reads a scenario file, rejecting values breaking the schema
+func LoadScenario(path string, schema *JSONSchema) (Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Scenario{}, err
+	}
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return Scenario{}, fmt.Errorf("failed to parse scenario %s: %w", path, err)
+	}
+	if problems := schema.Validate(document); len(problems) > 0 {
+		return Scenario{}, fmt.Errorf("scenario %s breaks the schema: %v", path, problems)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var scenario Scenario
+	if err := decoder.Decode(&scenario); err != nil {
+		return Scenario{}, fmt.Errorf("failed to parse scenario %s: %w", path, err)
+	}
+	if problems := scenario.Validate(); len(problems) > 0 {
+		return Scenario{}, fmt.Errorf("invalid scenario %s: %v", path, problems)
+	}
+	return scenario, nil
+}
+
+// Validate returns the values of the scenario the schema cannot check
+func (s Scenario) Validate() []string {
+	var problems []string
+	if _, ok := s.Params["train_gpu_per_worker"]; ok && s.GPUs.PerWorker > 0 {
+		problems = append(problems, "parameter 'train_gpu_per_worker' conflicts with gpus.per_worker")
+	}
+	if _, ok := s.Params["train_num_workers"]; ok && s.GPUs.Workers > 0 {
+		problems = append(problems, "parameter 'train_num_workers' conflicts with gpus.workers")
+	}
+	if _, ok := s.Params[TopologySpreadParameter]; ok && s.Zones == ZonesSpread {
+		problems = append(problems, fmt.Sprintf("parameter '%s' conflicts with zones", TopologySpreadParameter))
+	}
+	for i, chaos := range s.Chaos {
+		if _, ok := functionPhases[chaos.Task]; !ok && chaos.Task != "pytorch_job_launcher_op" {
+			problems = append(problems, fmt.Sprintf("chaos %d: task '%s' is not a component function of the pipeline", i, chaos.Task))
+		}
+	}
+	if _, err := s.Budget.Amounts(); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if s.Orchestrator != "" && s.Orchestrator != OrchestratorKFP {
+		// The workflow tasks are not KFP tasks, the steps reading the task pods of a pipeline run do not apply to them
+		unsupported := []struct {
+			field string
+			set   bool
+		}{{"chaos", len(s.Chaos) > 0}, {"budget", !s.Budget.IsZero()}, {"assertions", len(s.Assertions) > 0}, {"checks", len(s.Checks) > 0}, {"thresholds.max_sdg_invalid_row_rate", s.Thresholds.MaxSDGInvalidRowRate != nil}, {"zones", s.Zones != ""}}
+		for _, u := range unsupported {
+			if u.set {
+				problems = append(problems, fmt.Sprintf("%s is not supported with the %s orchestrator", u.field, s.Orchestrator))
+			}
+		}
+	}
+	if len(s.Compare) > 0 {
+		problems = append(problems, s.validateComparison()...)
+	}
+	for i, notification := range s.Notify {
+		for _, problem := range notification.Validate() {
+			problems = append(problems, fmt.Sprintf("notify %d: %s", i, problem))
+		}
+	}
+	for i, assertion := range s.Assertions {
+		if _, err := CompileAssertion(assertion.Expr); err != nil {
+			problems = append(problems, fmt.Sprintf("assertion %d: %v", i, err))
+		}
+	}
+	return problems
+}
+
+// validateComparison returns the problems of the candidates of a comparison, which run side by side as workflows
+func (s Scenario) validateComparison() []string {
+	var problems []string
+	if len(s.Compare) != 2 {
+		problems = append(problems, fmt.Sprintf("compare needs 2 candidates, got %d", len(s.Compare)))
+	}
+	if s.Orchestrator == "" || s.Orchestrator == OrchestratorKFP {
+		problems = append(problems, "compare is only supported with the tekton, argo and pod orchestrators")
+	}
+	if s.Images.Training != "" {
+		problems = append(problems, "images.training conflicts with the training images of compare")
+	}
+	names, namespaces := map[string]bool{}, map[string]bool{}
+	for _, candidate := range s.Compare {
+		if names[candidate.Name] {
+			problems = append(problems, fmt.Sprintf("compare: candidate '%s' is defined twice", candidate.Name))
+		}
+		if namespaces[candidate.Namespace] {
+			problems = append(problems, fmt.Sprintf("compare: namespace '%s' is used by both candidates", candidate.Namespace))
+		}
+		names[candidate.Name], namespaces[candidate.Namespace] = true, true
+	}
+	return problems
+}
+
+// ParameterOverrides returns the pipeline parameters of the scenario, the GPU topology included
+func (s Scenario) ParameterOverrides() map[string]interface{} {
+	overrides := map[string]interface{}{}
+	for name, value := range s.Params {
+		overrides[name] = value
+	}
+	if s.GPUs.PerWorker > 0 {
+		overrides["train_gpu_per_worker"] = s.GPUs.PerWorker
+	}
+	if s.GPUs.Workers > 0 {
+		overrides["train_num_workers"] = s.GPUs.Workers
+	}
+	return overrides
+}
+
+// CheckScenarioPhases returns the phases of a scenario without a succeeded task pod in the run
+func CheckScenarioPhases(tasks []TaskPod, phases []string) []string {
+	executed := map[string]bool{}
+	for _, task := range tasks {
+		if task.Pod.Status.Phase == corev1.PodSucceeded {
+			executed[TaskPhase(task)] = true
+		}
+	}
+	var missing []string
+	for _, phase := range phases {
+		if !executed[phase] {
+			missing = append(missing, fmt.Sprintf("phase %s did not execute", phase))
+		}
+	}
+	return missing
+}
+
+// ChaosEvent is the outcome of a chaos action applied to a task pod
+type ChaosEvent struct {
+	Action ChaosAction
+	Target TaskPod
+	Err    error
+}
+
+// WatchChaos applies the chaos actions to a pipeline run in order, each as soon as its task is running, until the
+// returned stop function is called. Stop returns the applied actions, the actions not applied yet are left out.
+func WatchChaos(client kubernetes.Interface, namespace, runID string, actions []ChaosAction, interval time.Duration) (stop func() []ChaosEvent) {
+	done := make(chan struct{})
+	finished := make(chan []ChaosEvent)
+	go func() {
+		var events []ChaosEvent
+		defer func() { finished <- events }()
+
+		tick := time.Tick(interval)
+		for _, action := range actions {
+			for applied := false; !applied; {
+				select {
+				case <-done:
+					return
+				case <-tick:
+					tasks, err := ListRunTaskPods(client, namespace, runID)
+					if err != nil {
+						continue
+					}
+					for _, task := range tasks {
+						if task.Function == action.Task && task.Pod.Status.Phase == corev1.PodRunning {
+							eviction := &policyv1.Eviction{ObjectMeta: metav1.ObjectMeta{Name: task.Pod.Name, Namespace: task.Pod.Namespace}}
+							err := client.PolicyV1().Evictions(namespace).Evict(context.Background(), eviction)
+							events = append(events, ChaosEvent{Action: action, Target: task, Err: err})
+							applied = true
+							break
+						}
+					}
+				}
+			}
+		}
+	}()
+	return func() []ChaosEvent {
+		close(done)
+		return <-finished
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+// ScenarioAssertion is an expression in CEL syntax, see Expression for the supported subset, over the data collected
+// from a scenario run, which must evaluate to true. The expression sees the variables:
+//   - duration: run duration in seconds
+//   - phases: duration in seconds by phase, for the phases that executed
+//   - scores: score by name, for the scores found in the task logs
+//   - usage: by phase, peak_cpu_millis, peak_memory_bytes, cpu_core_seconds and memory_byte_seconds
+type ScenarioAssertion struct {
+	Name string `yaml:"name"`
+	Expr string `yaml:"expr"`
+}
+
+// ScenarioData is the data collected from a scenario run that the assertions are evaluated against
+type ScenarioData struct {
+	Duration time.Duration
+	Phases   map[string]time.Duration
+	Scores   map[string]float64
+	Usage    ResourceUsage
+}
+
+// ScoreSource locates a score in an eval report artifact of the run, e.g. the mt_bench_output artifact holding
+// mt_bench_data.json, by the name of a top-level field of the report
+type ScoreSource struct {
+	Artifact string `mapstructure:"artifact"`
+	Field    string `mapstructure:"field"`
+}
+
+// ScoreRules lists the scores collected for the scenario assertions
+type ScoreRules struct {
+	Scores map[string]ScoreSource `mapstructure:"scores"`
+}
+
+// assertionVariables are the variables of the scenario assertions
+var assertionVariables = []string{"duration", "phases", "scores", "usage"}
+
+// CompileAssertion parses an assertion, rejecting syntax errors and unknown variables
+func CompileAssertion(expr string) (*Expression, error) {
+	return ParseExpression(expr, assertionVariables)
+}
+
+// EvaluateAssertions returns the assertions that are false or fail to evaluate against the collected data
+func EvaluateAssertions(assertions []ScenarioAssertion, data ScenarioData) []string {
+	phases := map[string]interface{}{}
+	for phase, duration := range data.Phases {
+		phases[phase] = duration.Seconds()
+	}
+	scores := map[string]interface{}{}
+	for name, score := range data.Scores {
+		scores[name] = score
+	}
+	usage := map[string]interface{}{}
+	for phase, phaseUsage := range data.Usage {
+		usage[phase] = map[string]interface{}{
+			"peak_cpu_millis":     float64(phaseUsage.PeakCPUMillis),
+			"peak_memory_bytes":   float64(phaseUsage.PeakMemoryBytes),
+			"cpu_core_seconds":    phaseUsage.CPUCoreSeconds,
+			"memory_byte_seconds": phaseUsage.MemoryByteSeconds,
+		}
+	}
+	vars := map[string]interface{}{
+		"duration": data.Duration.Seconds(),
+		"phases":   phases,
+		"scores":   scores,
+		"usage":    usage,
+	}
+
+	var failures []string
+	for _, assertion := range assertions {
+		name := assertion.Name
+		if name == "" {
+			name = assertion.Expr
+		}
+		expression, err := CompileAssertion(assertion.Expr)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("assertion '%s' is invalid: %v", name, err))
+			continue
+		}
+		result, err := expression.Eval(vars)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("assertion '%s' failed to evaluate: %v", name, err))
+			continue
+		}
+		if value, ok := result.(bool); !ok {
+			failures = append(failures, fmt.Sprintf("assertion '%s' evaluates to %s, not bool", name, typeName(result)))
+		} else if !value {
+			failures = append(failures, fmt.Sprintf("assertion '%s' is false", name))
+		}
+	}
+	return failures
+}
+
+// PhaseDurations returns the duration of every phase of a run, from the start of its first pod to the end of its
+// last container
+func PhaseDurations(tasks []TaskPod) map[string]time.Duration {
+	starts := map[string]time.Time{}
+	ends := map[string]time.Time{}
+	for _, task := range tasks {
+		phase := TaskPhase(task)
+		if phase == "" || task.Pod.Status.StartTime == nil {
+			continue
+		}
+		if start, ok := starts[phase]; !ok || task.Pod.Status.StartTime.Time.Before(start) {
+			starts[phase] = task.Pod.Status.StartTime.Time
+		}
+		for _, status := range task.Pod.Status.ContainerStatuses {
+			if status.State.Terminated != nil && status.State.Terminated.FinishedAt.Time.After(ends[phase]) {
+				ends[phase] = status.State.Terminated.FinishedAt.Time
+			}
+		}
+	}
+
+	durations := map[string]time.Duration{}
+	for phase, start := range starts {
+		if end, ok := ends[phase]; ok {
+			durations[phase] = end.Sub(start)
+		}
+	}
+	return durations
+}
+
+// LoadScoreRules reads the score rules from a YAML file
+func LoadScoreRules(t *testing.T, path string) ScoreRules {
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig(), "Error loading score rules")
+
+	var rules ScoreRules
+	require.NoError(t, v.Unmarshal(&rules), "Error parsing score rules")
+	return rules
+}
+
+// ReportArtifactKeys returns the keys of the report artifacts of a run, by artifact name, among the keys of the
+// artifact store. KFP stores the artifacts of a run under <pipeline root>/<pipeline>/<run ID>/<task>/<artifact>.
+func ReportArtifactKeys(keys []string, runID string, rules ScoreRules) map[string]string {
+	found := map[string]string{}
+	for _, key := range keys {
+		if !strings.Contains(key, "/"+runID+"/") {
+			continue
+		}
+		for _, source := range rules.Scores {
+			if strings.HasSuffix(key, "/"+source.Artifact) {
+				found[source.Artifact] = key
+			}
+		}
+	}
+	return found
+}
+
+// ParseScores reads the scores from the report artifacts by artifact name. Scores of missing artifacts are left out.
+func ParseScores(reports map[string][]byte, rules ScoreRules) (map[string]float64, error) {
+	scores := map[string]float64{}
+	for name, source := range rules.Scores {
+		data, ok := reports[source.Artifact]
+		if !ok {
+			continue
+		}
+		var report map[string]interface{}
+		if err := json.Unmarshal(data, &report); err != nil {
+			return nil, fmt.Errorf("report %s of score %s is not JSON: %w", source.Artifact, name, err)
+		}
+		switch value := report[source.Field].(type) {
+		case float64:
+			scores[name] = value
+		case string:
+			// Some reports hold scores as strings, e.g. max_score
+			score, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("field %s of report %s is not a number: %w", source.Field, source.Artifact, err)
+			}
+			scores[name] = score
+		default:
+			return nil, fmt.Errorf("report %s has no numeric field %s", source.Artifact, source.Field)
+		}
+	}
+	return scores, nil
+}
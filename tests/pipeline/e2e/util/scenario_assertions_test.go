@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEvaluateAssertions(t *testing.T) {
+	data := ScenarioData{
+		Duration: 2 * time.Hour,
+		Phases:   map[string]time.Duration{"sdg": 20 * time.Minute, "training-phase-1": time.Hour},
+		Scores:   map[string]float64{"mt_bench": 6.5},
+		Usage:    ResourceUsage{"sdg": {PeakCPUMillis: 4000, PeakMemoryBytes: 8 << 30}},
+	}
+	require.Empty(t, EvaluateAssertions([]ScenarioAssertion{
+		{Expr: "duration <= 3.0 * 3600.0"},
+		{Expr: `phases["training-phase-1"] > phases["sdg"]`},
+		{Expr: `scores["mt_bench"] >= 6.0 && !("mmlu_branch" in scores)`},
+		{Expr: `usage["sdg"]["peak_cpu_millis"] <= 4000.0`},
+	}, data))
+
+	require.Equal(t, []string{
+		"assertion 'fast run' is false",
+		`assertion 'scores["mmlu_branch"] > 0.5' failed to evaluate: no such key: mmlu_branch`,
+		"assertion 'phases' evaluates to map(string, double), not bool",
+	}, EvaluateAssertions([]ScenarioAssertion{
+		{Name: "fast run", Expr: "duration < 3600.0"},
+		{Expr: `scores["mmlu_branch"] > 0.5`},
+		{Expr: "phases"},
+	}, data))
+}
+
+func TestPhaseDurations(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	task := func(function string, started, finished time.Duration) TaskPod {
+		startTime := metav1.NewTime(start.Add(started))
+		return TaskPod{Function: function, Pod: corev1.Pod{Status: corev1.PodStatus{
+			StartTime: &startTime,
+			ContainerStatuses: []corev1.ContainerStatus{{State: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{FinishedAt: metav1.NewTime(start.Add(finished))},
+			}}},
+		}}}
+	}
+	running := task("run_mt_bench_op", 3*time.Hour, 0)
+	running.Pod.Status.ContainerStatuses = nil
+
+	require.Equal(t, map[string]time.Duration{
+		"prerequisites": 5 * time.Minute,
+		"sdg":           40 * time.Minute,
+	}, PhaseDurations([]TaskPod{
+		task("test_model_connection", 0, 2*time.Minute),
+		task("test_sdg_params", time.Minute, 5*time.Minute),
+		task("sdg_op", 10*time.Minute, 20*time.Minute),
+		task("sdg_op", 25*time.Minute, 50*time.Minute),
+		task("unknown_op", 0, time.Hour),
+		running,
+	}))
+}
+
+func TestParseScores(t *testing.T) {
+	rules := ScoreRules{Scores: map[string]ScoreSource{
+		"mt_bench":    {Artifact: "mt_bench_output", Field: "best_score"},
+		"mmlu_branch": {Artifact: "mmlu_branch_output", Field: "trained_model_score"},
+	}}
+	keys := ReportArtifactKeys([]string{
+		"ilab/run-1/pvc-to-mt-bench-op/mt_bench_output",
+		"ilab/run-0/pvc-to-mmlu-branch-op/mmlu_branch_output",
+		"ilab/run-1/sdg-op/sdg",
+	}, "run-1", rules)
+	require.Equal(t, map[string]string{"mt_bench_output": "ilab/run-1/pvc-to-mt-bench-op/mt_bench_output"}, keys)
+
+	scores, err := ParseScores(map[string][]byte{
+		"mt_bench_output": []byte(`{"best_model": "/output/phase_2/model/hf_format/samples_1", "best_score": 6.25, "reports": []}`),
+	}, rules)
+	require.NoError(t, err)
+	require.Equal(t, map[string]float64{"mt_bench": 6.25}, scores)
+
+	_, err = ParseScores(map[string][]byte{"mmlu_branch_output": []byte(`{"report_title": "KNOWLEDGE EVALUATION REPORT"}`)}, rules)
+	require.EqualError(t, err, "report mmlu_branch_output has no numeric field trained_model_score")
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ScenarioBudget is the peak of cluster resources a scenario run may hold at once, empty values are not checked.
+// CPU, memory and storage are Kubernetes quantities, e.g. 16, 128Gi and 500Gi.
+type ScenarioBudget struct {
+	GPUs int64 `yaml:"gpus"`
+	// GPUResource is the resource the GPUs are counted in, nvidia.com/gpu by default
+	GPUResource string `yaml:"gpu_resource"`
+	CPU         string `yaml:"cpu"`
+	Memory      string `yaml:"memory"`
+	Storage     string `yaml:"storage"`
+}
+
+// IsZero reports whether the budget declares no resource
+func (b ScenarioBudget) IsZero() bool {
+	return b.GPUs == 0 && b.CPU == "" && b.Memory == "" && b.Storage == ""
+}
+
+// Resource returns the GPU resource of the budget
+func (b ScenarioBudget) Resource() string {
+	if b.GPUResource != "" {
+		return b.GPUResource
+	}
+	return DefaultGPUResource
+}
+
+// Amounts returns the budget as resource amounts, the resources left out of the budget set to -1
+func (b ScenarioBudget) Amounts() (ResourceAmounts, error) {
+	amounts := ResourceAmounts{GPUs: -1, CPUMillis: -1, MemoryBytes: -1, StorageBytes: -1}
+	if b.GPUs > 0 {
+		amounts.GPUs = b.GPUs
+	}
+	for _, field := range []struct {
+		name   string
+		value  string
+		amount *int64
+		milli  bool
+	}{
+		{"cpu", b.CPU, &amounts.CPUMillis, true},
+		{"memory", b.Memory, &amounts.MemoryBytes, false},
+		{"storage", b.Storage, &amounts.StorageBytes, false},
+	} {
+		if field.value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(field.value)
+		if err != nil {
+			return ResourceAmounts{}, fmt.Errorf("invalid %s budget '%s': %w", field.name, field.value, err)
+		}
+		if field.milli {
+			*field.amount = quantity.MilliValue()
+		} else {
+			*field.amount = quantity.Value()
+		}
+	}
+	return amounts, nil
+}
+
+// ResourceAmounts are amounts of the resources of a budget. A negative amount is unknown or unlimited.
+type ResourceAmounts struct {
+	GPUs         int64
+	CPUMillis    int64
+	MemoryBytes  int64
+	StorageBytes int64
+}
+
+// GetClusterCapacity returns the resources of the schedulable nodes which the pods not terminated do not request,
+// and the storage the resource quotas of the namespace leave, unlimited without a storage quota
+func GetClusterCapacity(client kubernetes.Interface, namespace, gpuResource string) (ResourceAmounts, error) {
+	nodes, err := client.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return ResourceAmounts{}, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	pods, err := client.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{
+		FieldSelector: "status.phase!=Succeeded,status.phase!=Failed",
+	})
+	if err != nil {
+		return ResourceAmounts{}, fmt.Errorf("failed to list pods: %w", err)
+	}
+	quotas, err := client.CoreV1().ResourceQuotas(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return ResourceAmounts{}, fmt.Errorf("failed to list the resource quotas of %s: %w", namespace, err)
+	}
+	return ClusterCapacity(nodes.Items, pods.Items, quotas.Items, gpuResource), nil
+}
+
+// ClusterCapacity computes the free resources of GetClusterCapacity
+func ClusterCapacity(nodes []corev1.Node, pods []corev1.Pod, quotas []corev1.ResourceQuota, gpuResource string) ResourceAmounts {
+	var free ResourceAmounts
+	schedulable := map[string]bool{}
+	for _, node := range nodes {
+		if node.Spec.Unschedulable {
+			continue
+		}
+		schedulable[node.Name] = true
+		allocatable := node.Status.Allocatable
+		free.GPUs += allocatable.Name(corev1.ResourceName(gpuResource), resource.DecimalSI).Value()
+		free.CPUMillis += allocatable.Cpu().MilliValue()
+		free.MemoryBytes += allocatable.Memory().Value()
+	}
+	for _, pod := range pods {
+		if !schedulable[pod.Spec.NodeName] {
+			continue
+		}
+		requests := podRequests(pod)
+		free.GPUs -= requests.Name(corev1.ResourceName(gpuResource), resource.DecimalSI).Value()
+		free.CPUMillis -= requests.Cpu().MilliValue()
+		free.MemoryBytes -= requests.Memory().Value()
+	}
+
+	free.StorageBytes = -1
+	for _, quota := range quotas {
+		hard, ok := quota.Status.Hard[corev1.ResourceRequestsStorage]
+		if !ok {
+			continue
+		}
+		used := quota.Status.Used[corev1.ResourceRequestsStorage]
+		if left := hard.Value() - used.Value(); free.StorageBytes < 0 || left < free.StorageBytes {
+			free.StorageBytes = left
+		}
+	}
+	return free
+}
+
+// podRequests returns the requests a pod is scheduled with: the sum of its containers, or the largest init
+// container when it requests more
+func podRequests(pod corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		for name, quantity := range container.Resources.Requests {
+			total := requests[name]
+			total.Add(quantity)
+			requests[name] = total
+		}
+	}
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if quantity.Cmp(requests[name]) > 0 {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	return requests
+}
+
+// RunPeakUsage returns the peak resources a run held: the CPU and memory of its busiest phase, the GPUs its pods of a
+// phase requested at once, and the storage requested by the PVCs created since the run started. The phases of a run
+// do not overlap, so the peak of a phase is the peak of the run.
+func RunPeakUsage(usage ResourceUsage, podPhases map[string]string, pods []corev1.Pod, pvcs []corev1.PersistentVolumeClaim, gpuResource string, since time.Time) ResourceAmounts {
+	var peak ResourceAmounts
+	for _, phase := range usage {
+		if phase.PeakCPUMillis > peak.CPUMillis {
+			peak.CPUMillis = phase.PeakCPUMillis
+		}
+		if phase.PeakMemoryBytes > peak.MemoryBytes {
+			peak.MemoryBytes = phase.PeakMemoryBytes
+		}
+	}
+
+	gpus := map[string]int64{}
+	for pod, requested := range PodGPURequests(pods, gpuResource) {
+		if phase, ok := podPhases[pod]; ok {
+			gpus[phase] += requested
+		}
+	}
+	for _, requested := range gpus {
+		if requested > peak.GPUs {
+			peak.GPUs = requested
+		}
+	}
+
+	for _, pvc := range pvcs {
+		if pvc.CreationTimestamp.Time.Before(since) {
+			continue
+		}
+		storage := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		peak.StorageBytes += storage.Value()
+	}
+	return peak
+}
+
+// CheckBudgetCapacity returns the resources of a budget the cluster cannot provide
+func CheckBudgetCapacity(budget ScenarioBudget, free ResourceAmounts) ([]string, error) {
+	amounts, err := budget.Amounts()
+	if err != nil {
+		return nil, err
+	}
+	return compareAmounts(amounts, free, budget.Resource(), "the budget needs %s, the cluster has %s free"), nil
+}
+
+// CheckBudgetUsage returns the resources a run used beyond its budget
+func CheckBudgetUsage(budget ScenarioBudget, peak ResourceAmounts) ([]string, error) {
+	amounts, err := budget.Amounts()
+	if err != nil {
+		return nil, err
+	}
+	return compareAmounts(peak, amounts, budget.Resource(), "the run peaked at %s, over the budget of %s"), nil
+}
+
+// compareAmounts reports the resources where need exceeds have, both amounts known
+func compareAmounts(need, have ResourceAmounts, gpuResource, format string) []string {
+	var problems []string
+	for _, amount := range []struct {
+		need, have int64
+		render     func(int64) string
+	}{
+		{need.GPUs, have.GPUs, func(v int64) string { return fmt.Sprintf("%d %s", v, gpuResource) }},
+		{need.CPUMillis, have.CPUMillis, func(v int64) string { return resource.NewMilliQuantity(v, resource.DecimalSI).String() + " CPU" }},
+		{need.MemoryBytes, have.MemoryBytes, func(v int64) string { return resource.NewQuantity(v, resource.BinarySI).String() + " memory" }},
+		{need.StorageBytes, have.StorageBytes, func(v int64) string { return resource.NewQuantity(v, resource.BinarySI).String() + " storage" }},
+	} {
+		if amount.need >= 0 && amount.have >= 0 && amount.need > amount.have {
+			problems = append(problems, fmt.Sprintf(format, amount.render(amount.need), amount.render(amount.have)))
+		}
+	}
+	return problems
+}
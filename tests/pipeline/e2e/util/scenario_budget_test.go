@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestClusterCapacity(t *testing.T) {
+	node := func(name string, unschedulable bool, gpus string) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("16"),
+				corev1.ResourceMemory: resource.MustParse("64Gi"),
+				DefaultGPUResource:    resource.MustParse(gpus),
+			}},
+		}
+	}
+	pod := func(node string, requests corev1.ResourceList, init corev1.ResourceList) corev1.Pod {
+		return corev1.Pod{Spec: corev1.PodSpec{
+			NodeName:       node,
+			Containers:     []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: requests}}},
+			InitContainers: []corev1.Container{{Resources: corev1.ResourceRequirements{Requests: init}}},
+		}}
+	}
+	nodes := []corev1.Node{node("gpu-1", false, "4"), node("gpu-2", false, "4"), node("cordoned", true, "8")}
+	pods := []corev1.Pod{
+		pod("gpu-1", corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4"), DefaultGPUResource: resource.MustParse("1")},
+			corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")}),
+		pod("cordoned", corev1.ResourceList{DefaultGPUResource: resource.MustParse("8")}, nil),
+	}
+
+	free := ClusterCapacity(nodes, pods, nil, DefaultGPUResource)
+	require.Equal(t, ResourceAmounts{GPUs: 7, CPUMillis: 28000, MemoryBytes: 120 << 30, StorageBytes: -1}, free)
+
+	quotas := []corev1.ResourceQuota{
+		{Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{corev1.ResourceRequestsStorage: resource.MustParse("500Gi")},
+			Used: corev1.ResourceList{corev1.ResourceRequestsStorage: resource.MustParse("100Gi")},
+		}},
+		{Status: corev1.ResourceQuotaStatus{Hard: corev1.ResourceList{corev1.ResourcePods: resource.MustParse("10")}}},
+	}
+	require.Equal(t, int64(400<<30), ClusterCapacity(nodes, pods, quotas, DefaultGPUResource).StorageBytes)
+}
+
+func TestScenarioBudget(t *testing.T) {
+	budget := ScenarioBudget{GPUs: 4, CPU: "16", Memory: "64Gi", Storage: "200Gi"}
+	problems, err := CheckBudgetCapacity(budget, ResourceAmounts{GPUs: 2, CPUMillis: 32000, MemoryBytes: 32 << 30, StorageBytes: -1})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"the budget needs 4 nvidia.com/gpu, the cluster has 2 nvidia.com/gpu free",
+		"the budget needs 64Gi memory, the cluster has 32Gi memory free",
+	}, problems)
+
+	// A budget without CPU leaves the CPU usage unchecked
+	problems, err = CheckBudgetUsage(ScenarioBudget{GPUs: 4, Storage: "200Gi"}, ResourceAmounts{GPUs: 4, CPUMillis: 64000, StorageBytes: 250 << 30})
+	require.NoError(t, err)
+	require.Equal(t, []string{"the run peaked at 250Gi storage, over the budget of 200Gi storage"}, problems)
+
+	_, err = CheckBudgetUsage(ScenarioBudget{CPU: "many"}, ResourceAmounts{})
+	require.ErrorContains(t, err, "invalid cpu budget 'many'")
+}
+
+func TestRunPeakUsage(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	usage := ResourceUsage{
+		"sdg":              {PeakCPUMillis: 8000, PeakMemoryBytes: 48 << 30},
+		"training-phase-1": {PeakCPUMillis: 12000, PeakMemoryBytes: 32 << 30},
+	}
+	gpuPod := func(name string, gpus string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{DefaultGPUResource: resource.MustParse(gpus)},
+			}}}},
+		}
+	}
+	pods := []corev1.Pod{gpuPod("train-phase-1-master-0", "2"), gpuPod("train-phase-1-worker-0", "2"), gpuPod("mt-bench", "1")}
+	podPhases := map[string]string{"train-phase-1-master-0": "training-phase-1", "train-phase-1-worker-0": "training-phase-1", "mt-bench": "mt-bench"}
+	pvc := func(created time.Time, storage string) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)},
+			Spec: corev1.PersistentVolumeClaimSpec{Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(storage)},
+			}},
+		}
+	}
+	pvcs := []corev1.PersistentVolumeClaim{pvc(start.Add(-time.Hour), "1Ti"), pvc(start.Add(time.Minute), "100Gi"), pvc(start.Add(time.Hour), "50Gi")}
+
+	require.Equal(t, ResourceAmounts{GPUs: 4, CPUMillis: 12000, MemoryBytes: 48 << 30, StorageBytes: 150 << 30},
+		RunPeakUsage(usage, podPhases, pods, pvcs, DefaultGPUResource, start))
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestLoadScenarios(t *testing.T) {
+	scenarios, err := LoadScenarios("../../../scenarios")
+	require.NoError(t, err)
+	require.NotEmpty(t, scenarios)
+
+	schema, err := LoadJSONSchema("../../../scenarios/schema.json")
+	require.NoError(t, err)
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	scenario, err := LoadScenario(write("eviction.yaml", `
+name: eviction
+gpus: {per_worker: 2}
+phases: [sdg, training-phase-1]
+params: {sdg_scale_factor: 5}
+chaos: [{action: evict-pod, task: sdg_op}]
+thresholds: {max_duration: 6h30m, max_sdg_invalid_row_rate: 0.2}
+budget: {gpus: 2, cpu: "24", memory: 96Gi}
+`), schema)
+	require.NoError(t, err)
+	require.Equal(t, ScenarioBudget{GPUs: 2, CPU: "24", Memory: "96Gi"}, scenario.Budget)
+	require.Equal(t, 6*time.Hour+30*time.Minute, scenario.Thresholds.MaxDuration)
+	require.Equal(t, map[string]interface{}{"sdg_scale_factor": 5, "train_gpu_per_worker": 2}, scenario.ParameterOverrides())
+
+	_, err = LoadScenario(write("unknown.yaml", "name: unknown\ngpu: 4\n"), schema)
+	require.ErrorContains(t, err, "$: unknown property gpu")
+
+	_, err = LoadScenario(write("schema.yaml", `
+name: ""
+gpus: {workers: -2}
+phases: [training]
+chaos: [{action: kill-node, task: sdg_op}, {action: evict-pod, task: sdg_op}]
+thresholds: {max_duration: 6 hours, max_sdg_invalid_row_rate: 2}
+budget: {gpus: 0, memory: 96GB}
+`), schema)
+	require.ErrorContains(t, err, "$.name: '' is shorter than 1 characters")
+	require.ErrorContains(t, err, "$.gpus.workers: -2 is less than 0")
+	require.ErrorContains(t, err, "$.phases[0]: training is not one of")
+	require.ErrorContains(t, err, "$.chaos: 2 items, at most 1 allowed")
+	require.ErrorContains(t, err, "$.chaos[0].action: kill-node is not one of [evict-pod]")
+	require.ErrorContains(t, err, "$.thresholds.max_duration: '6 hours' does not match")
+	require.ErrorContains(t, err, "$.thresholds.max_sdg_invalid_row_rate: 2 is greater than 1")
+	require.ErrorContains(t, err, "$.budget.gpus: 0 is less than 1")
+	require.ErrorContains(t, err, "$.budget.memory: '96GB' does not match")
+
+	_, err = LoadScenario(write("invalid.yaml", `
+name: invalid
+gpus: {workers: 2}
+params: {train_num_workers: 4}
+chaos: [{action: evict-pod, task: train_op}]
+assertions: [{expr: runtime < 3600}]
+notify: [{sink: email}]
+`), schema)
+	require.ErrorContains(t, err, "'train_num_workers' conflicts with gpus.workers")
+	require.ErrorContains(t, err, "notify 0: the email sink needs recipients in 'to'")
+	require.ErrorContains(t, err, "task 'train_op' is not a component function")
+	require.ErrorContains(t, err, "assertion 0: undeclared reference to 'runtime' at offset 0")
+
+	_, err = LoadScenario(write("tekton.yaml", `
+name: tekton
+orchestrator: tekton
+chaos: [{action: evict-pod, task: sdg_op}]
+checks: [policy]
+`), schema)
+	require.ErrorContains(t, err, "[chaos is not supported with the tekton orchestrator checks is not supported with the tekton orchestrator]")
+
+	_, err = LoadScenario(write("compare.yaml", `
+name: compare
+images: {training: quay.io/ilab/training:1.4}
+compare:
+  - {name: current, namespace: ilab-a, training: quay.io/ilab/training:1.4}
+`), schema)
+	require.ErrorContains(t, err, "[compare needs 2 candidates, got 1 compare is only supported with the tekton, argo and pod orchestrators images.training conflicts with the training images of compare]")
+
+	_, err = LoadScenario(write("compare.yaml", `
+name: compare
+orchestrator: argo
+compare:
+  - {name: current, namespace: ilab-a, training: quay.io/ilab/training:1.4}
+  - {name: current, namespace: ilab-a, training: quay.io/ilab/training:1.5}
+`), schema)
+	require.ErrorContains(t, err, "[compare: candidate 'current' is defined twice compare: namespace 'ilab-a' is used by both candidates]")
+	require.NoError(t, os.Remove(filepath.Join(dir, "compare.yaml")))
+
+	data, err := os.ReadFile("../../../scenarios/schema.json")
+	require.NoError(t, err)
+	write("schema.json", string(data))
+	write("duplicate.yaml", "name: eviction\n")
+	require.NoError(t, os.Remove(filepath.Join(dir, "unknown.yaml")))
+	require.NoError(t, os.Remove(filepath.Join(dir, "schema.yaml")))
+	require.NoError(t, os.Remove(filepath.Join(dir, "invalid.yaml")))
+	require.NoError(t, os.Remove(filepath.Join(dir, "tekton.yaml")))
+	_, err = LoadScenarios(dir)
+	require.ErrorContains(t, err, "scenario 'eviction' of")
+}
+
+// The JSON schema for editors must describe the same fields as the Scenario struct
+func TestScenarioSchema(t *testing.T) {
+	data, err := os.ReadFile("../../../scenarios/schema.json")
+	require.NoError(t, err)
+	var schema struct {
+		Properties map[string]json.RawMessage `json:"properties"`
+	}
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	var properties, fields []string
+	for name := range schema.Properties {
+		properties = append(properties, name)
+	}
+	scenario := reflect.TypeOf(Scenario{})
+	for i := 0; i < scenario.NumField(); i++ {
+		fields = append(fields, strings.Split(scenario.Field(i).Tag.Get("yaml"), ",")[0])
+	}
+	sort.Strings(properties)
+	sort.Strings(fields)
+	require.Equal(t, fields, properties)
+
+	var phases struct {
+		Items struct {
+			Enum []string `json:"enum"`
+		} `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(schema.Properties["phases"], &phases))
+	require.Equal(t, PipelinePhases, phases.Items.Enum)
+
+	var chaos struct {
+		Items struct {
+			Properties struct {
+				Action struct {
+					Enum []string `json:"enum"`
+				} `json:"action"`
+			} `json:"properties"`
+		} `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(schema.Properties["chaos"], &chaos))
+	require.Equal(t, []string{ChaosEvictPod}, chaos.Items.Properties.Action.Enum)
+}
+
+func TestCheckScenarioPhases(t *testing.T) {
+	task := func(function string, phase corev1.PodPhase, params map[string]interface{}) TaskPod {
+		return TaskPod{Function: function, Parameters: params, Pod: corev1.Pod{Status: corev1.PodStatus{Phase: phase}}}
+	}
+	tasks := []TaskPod{
+		task("sdg_op", corev1.PodFailed, nil),
+		task("sdg_op", corev1.PodSucceeded, nil),
+		task("pytorch_job_launcher_op", corev1.PodSucceeded, map[string]interface{}{"phase_num": 1}),
+		task("pytorch_job_launcher_op", corev1.PodFailed, map[string]interface{}{"phase_num": 2}),
+	}
+	require.Empty(t, CheckScenarioPhases(tasks, []string{"sdg", "training-phase-1"}))
+	require.Equal(t, []string{"phase training-phase-2 did not execute", "phase final-eval did not execute"},
+		CheckScenarioPhases(tasks, []string{"sdg", "training-phase-2", "final-eval"}))
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// PodLatency breaks down the time a pod took from its creation to running and logging
+type PodLatency struct {
+	Pod   string
+	Phase string
+	// Scheduling is the time from creation to the pod being scheduled on a node
+	Scheduling time.Duration
+	// Startup is the time from scheduling to the first container running, image pulls included
+	Startup time.Duration
+	// FirstLog is the time from the first container running to its first log line, zero when nothing was logged
+	FirstLog time.Duration
+}
+
+// ToRunning is the time from creation to the first container running
+func (l PodLatency) ToRunning() time.Duration {
+	return l.Scheduling + l.Startup
+}
+
+// MeasurePodLatency computes the latency of a pod from its status and the time of its first log line, reporting
+// false for pods that never ran
+func MeasurePodLatency(pod corev1.Pod, phase string, firstLog time.Time) (PodLatency, bool) {
+	created := pod.CreationTimestamp.Time
+	var scheduled time.Time
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionTrue {
+			scheduled = condition.LastTransitionTime.Time
+		}
+	}
+	var running time.Time
+	for _, status := range pod.Status.ContainerStatuses {
+		started := time.Time{}
+		if status.State.Running != nil {
+			started = status.State.Running.StartedAt.Time
+		} else if status.State.Terminated != nil {
+			started = status.State.Terminated.StartedAt.Time
+		}
+		if !started.IsZero() && (running.IsZero() || started.Before(running)) {
+			running = started
+		}
+	}
+	if scheduled.IsZero() || running.IsZero() {
+		return PodLatency{}, false
+	}
+
+	latency := PodLatency{Pod: pod.Name, Phase: phase, Scheduling: scheduled.Sub(created), Startup: running.Sub(scheduled)}
+	if !firstLog.IsZero() && firstLog.After(running) {
+		latency.FirstLog = firstLog.Sub(running)
+	}
+	return latency, true
+}
+
+// FirstLogTime returns the time of the first log line of the main container of a pod, zero when nothing was logged
+func FirstLogTime(client kubernetes.Interface, pod corev1.Pod) (time.Time, error) {
+	container := pod.Spec.Containers[0].Name
+	for _, c := range pod.Spec.Containers {
+		if c.Name == "main" {
+			container = c.Name
+		}
+	}
+	limit := int64(1024)
+	logs, err := client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container:  container,
+		Timestamps: true,
+		LimitBytes: &limit,
+	}).DoRaw(context.Background())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to retrieve logs of pod %s: %w", pod.Name, err)
+	}
+	line, err := bufio.NewReader(strings.NewReader(string(logs))).ReadString('\n')
+	if line == "" && err != nil {
+		return time.Time{}, nil
+	}
+	timestamp, _, _ := strings.Cut(line, " ")
+	return time.Parse(time.RFC3339Nano, timestamp)
+}
+
+// LatencyOutliers returns the pods which took longer than factor times the median of their phase, and longer than
+// minimum, to run
+func LatencyOutliers(latencies []PodLatency, factor float64, minimum time.Duration) []PodLatency {
+	byPhase := map[string][]time.Duration{}
+	for _, latency := range latencies {
+		byPhase[latency.Phase] = append(byPhase[latency.Phase], latency.ToRunning())
+	}
+	var outliers []PodLatency
+	for _, latency := range latencies {
+		median := medianDuration(byPhase[latency.Phase])
+		if latency.ToRunning() > minimum && float64(latency.ToRunning()) > factor*float64(median) {
+			outliers = append(outliers, latency)
+		}
+	}
+	return outliers
+}
+
+// RenderSchedulingLatency renders the latencies by phase and their outliers as a Markdown report
+func RenderSchedulingLatency(latencies []PodLatency, outliers []PodLatency) string {
+	byPhase := map[string][]PodLatency{}
+	for _, latency := range latencies {
+		byPhase[latency.Phase] = append(byPhase[latency.Phase], latency)
+	}
+
+	var report strings.Builder
+	report.WriteString("# Scheduling latency\n\n")
+	report.WriteString("| Phase | Pods | Median scheduling | Median startup | Max to running | Median to first log |\n")
+	report.WriteString("|---|---|---|---|---|---|\n")
+	for _, phase := range PipelinePhases {
+		pods := byPhase[phase]
+		if len(pods) == 0 {
+			continue
+		}
+		var scheduling, startup, firstLog []time.Duration
+		var maxRunning time.Duration
+		for _, pod := range pods {
+			scheduling = append(scheduling, pod.Scheduling)
+			startup = append(startup, pod.Startup)
+			firstLog = append(firstLog, pod.FirstLog)
+			if pod.ToRunning() > maxRunning {
+				maxRunning = pod.ToRunning()
+			}
+		}
+		fmt.Fprintf(&report, "| %s | %d | %s | %s | %s | %s |\n", phase, len(pods),
+			medianDuration(scheduling).Round(time.Second), medianDuration(startup).Round(time.Second),
+			maxRunning.Round(time.Second), medianDuration(firstLog).Round(time.Second))
+	}
+
+	if len(outliers) > 0 {
+		report.WriteString("\n## Outliers\n\n")
+		report.WriteString("| Pod | Phase | Scheduling | Startup |\n")
+		report.WriteString("|---|---|---|---|\n")
+		for _, outlier := range outliers {
+			fmt.Fprintf(&report, "| %s | %s | %s | %s |\n", outlier.Pod, outlier.Phase, outlier.Scheduling.Round(time.Second), outlier.Startup.Round(time.Second))
+		}
+	}
+	return report.String()
+}
+
+func medianDuration(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration{}, durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMeasurePodLatency(t *testing.T) {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "run-sdg", CreationTimestamp: metav1.NewTime(created)},
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(created.Add(5 * time.Second))}},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{StartedAt: metav1.NewTime(created.Add(2 * time.Minute))}}},
+				{State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(created.Add(3 * time.Minute))}}},
+			},
+		},
+	}
+	latency, ok := MeasurePodLatency(pod, "sdg", created.Add(2*time.Minute+10*time.Second))
+	require.True(t, ok)
+	require.Equal(t, PodLatency{Pod: "run-sdg", Phase: "sdg", Scheduling: 5 * time.Second, Startup: 115 * time.Second, FirstLog: 10 * time.Second}, latency)
+	require.Equal(t, 2*time.Minute, latency.ToRunning())
+
+	pod.Status.ContainerStatuses = nil
+	_, ok = MeasurePodLatency(pod, "sdg", time.Time{})
+	require.False(t, ok)
+}
+
+func TestLatencyOutliers(t *testing.T) {
+	latencies := []PodLatency{
+		{Pod: "a", Phase: "mt-bench", Scheduling: time.Second, Startup: 20 * time.Second},
+		{Pod: "b", Phase: "mt-bench", Scheduling: time.Second, Startup: 30 * time.Second},
+		{Pod: "c", Phase: "mt-bench", Scheduling: 9 * time.Minute, Startup: time.Minute},
+		{Pod: "d", Phase: "sdg", Scheduling: time.Second, Startup: 10 * time.Second},
+	}
+	outliers := LatencyOutliers(latencies, 3, 2*time.Minute)
+	require.Equal(t, []PodLatency{latencies[2]}, outliers)
+
+	report := RenderSchedulingLatency(latencies, outliers)
+	require.Contains(t, report, "| sdg | 1 | 1s | 10s | 11s | 0s |\n")
+	require.Contains(t, report, "| mt-bench | 3 | 1s | 30s | 10m0s | 0s |\n")
+	require.Contains(t, report, "| c | mt-bench | 9m0s | 1m0s |\n")
+}
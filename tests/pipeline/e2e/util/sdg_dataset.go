@@ -0,0 +1,220 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+// SDGDatasetRules configures the validation of the dataset generated by sdg_op. Generation failures surface in the
+// output, a failed batch leaves the rows it should have produced out of the dataset or writes malformed rows.
+type SDGDatasetRules struct {
+	// MaxInvalidRowRate is the tolerated fraction of rows failing validation
+	MaxInvalidRowRate float64 `mapstructure:"max_invalid_row_rate"`
+	// MinSamples is the minimum number of rows of the training mixes
+	MinSamples int `mapstructure:"min_samples"`
+}
+
+// SDGDatasetFile is the validation outcome of a JSON lines file of the SDG output
+type SDGDatasetFile struct {
+	Path    string
+	Rows    int
+	Invalid int
+	// FirstError describes the first invalid row
+	FirstError string
+}
+
+// SDGDatasetStats tallies the validation outcomes of the JSON lines files of the SDG output
+type SDGDatasetStats struct {
+	Files []SDGDatasetFile
+}
+
+// Rows returns the number of rows of all the files
+func (s SDGDatasetStats) Rows() int {
+	rows := 0
+	for _, file := range s.Files {
+		rows += file.Rows
+	}
+	return rows
+}
+
+// Invalid returns the number of rows failing validation in all the files
+func (s SDGDatasetStats) Invalid() int {
+	invalid := 0
+	for _, file := range s.Files {
+		invalid += file.Invalid
+	}
+	return invalid
+}
+
+// InvalidRowRate is the fraction of rows failing validation
+func (s SDGDatasetStats) InvalidRowRate() float64 {
+	if rows := s.Rows(); rows > 0 {
+		return float64(s.Invalid()) / float64(rows)
+	}
+	return 0
+}
+
+// TrainingRows returns the number of valid rows of the skills and knowledge training mixes
+func (s SDGDatasetStats) TrainingRows() int {
+	rows := 0
+	for _, file := range s.Files {
+		if isTrainingMix(file.Path) {
+			rows += file.Rows - file.Invalid
+		}
+	}
+	return rows
+}
+
+// LoadSDGDatasetRules reads the SDG dataset rules from a YAML file
+func LoadSDGDatasetRules(t *testing.T, path string) SDGDatasetRules {
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig(), "Error loading SDG dataset rules")
+
+	var rules SDGDatasetRules
+	require.NoError(t, v.Unmarshal(&rules), "Error parsing SDG dataset rules")
+	return rules
+}
+
+// SDGDatasetKeys returns the keys of the JSON lines files of the sdg artifact of a run, by path relative to the
+// artifact, among the keys of the artifact store
+func SDGDatasetKeys(keys []string, runID string) map[string]string {
+	found := map[string]string{}
+	for _, key := range keys {
+		if file, ok := sdgArtifactPath(key, runID); ok && strings.HasSuffix(file, ".jsonl") {
+			found[file] = key
+		}
+	}
+	return found
+}
+
+// ValidateSDGDatasetFile validates each row of a JSON lines file of the SDG output. Every row must be a JSON object,
+// the rows of the training mixes must also hold a non-empty list of messages with a role and a non-empty content.
+func ValidateSDGDatasetFile(path string, content io.Reader) (SDGDatasetFile, error) {
+	file := SDGDatasetFile{Path: path}
+	scanner := bufio.NewScanner(content)
+	// Rows of the knowledge mixes embed whole documents
+	scanner.Buffer(make([]byte, 0, 1024*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		file.Rows++
+		if err := validateSDGRow(scanner.Bytes(), isTrainingMix(path)); err != nil {
+			file.Invalid++
+			if file.FirstError == "" {
+				file.FirstError = fmt.Sprintf("line %d: %v", line, err)
+			}
+		}
+	}
+	return file, scanner.Err()
+}
+
+// CheckSDGDatasetStats verifies the SDG output holds training mixes with enough valid rows and no empty file, and
+// stays within the tolerated rate of invalid rows
+func CheckSDGDatasetStats(stats SDGDatasetStats, rules SDGDatasetRules) []string {
+	if len(stats.Files) == 0 {
+		return []string{"no JSON lines file found in the sdg artifact"}
+	}
+	sort.Slice(stats.Files, func(i, j int) bool { return stats.Files[i].Path < stats.Files[j].Path })
+
+	var failures []string
+	mixes := 0
+	for _, file := range stats.Files {
+		if isTrainingMix(file.Path) {
+			mixes++
+		}
+		if file.Rows == 0 {
+			failures = append(failures, fmt.Sprintf("%s is empty", file.Path))
+		}
+	}
+	if mixes == 0 {
+		failures = append(failures, "no skills_train_msgs or knowledge_train_msgs file found in the sdg artifact")
+	}
+	if rows := stats.TrainingRows(); rows < rules.MinSamples {
+		failures = append(failures, fmt.Sprintf("training mixes have %d valid rows, expected at least %d", rows, rules.MinSamples))
+	}
+	if rate := stats.InvalidRowRate(); rate > rules.MaxInvalidRowRate {
+		failures = append(failures, fmt.Sprintf("SDG invalid row rate %.2f exceeds the tolerated rate %.2f (%d of %d rows)", rate, rules.MaxInvalidRowRate, stats.Invalid(), stats.Rows()))
+		for _, file := range stats.Files {
+			if file.Invalid > 0 {
+				failures = append(failures, fmt.Sprintf("%s has %d invalid rows of %d, %s", file.Path, file.Invalid, file.Rows, file.FirstError))
+			}
+		}
+	}
+	return failures
+}
+
+// sdgArtifactPath returns the path of an object relative to the sdg artifact of a run. sdg_to_artifact_op copies
+// the SDG output directory into the sdg artifact, stored under
+// <pipeline root>/<pipeline>/<run ID>/sdg-to-artifact-op/<execution>/sdg.
+func sdgArtifactPath(key, runID string) (string, bool) {
+	if !strings.Contains(key, "/"+runID+"/") {
+		return "", false
+	}
+	index := strings.Index(key, "/sdg-to-artifact-op/")
+	if index < 0 {
+		return "", false
+	}
+	rest := key[index+len("/sdg-to-artifact-op/"):]
+	start := strings.Index(rest, "/sdg/")
+	if start < 0 {
+		return "", false
+	}
+	return rest[start+len("/sdg/"):], true
+}
+
+// isTrainingMix tells whether a file of the SDG output is a training mix read by the training phases
+func isTrainingMix(file string) bool {
+	name := path.Base(file)
+	return strings.HasPrefix(name, "skills_train_msgs") || strings.HasPrefix(name, "knowledge_train_msgs")
+}
+
+func validateSDGRow(data []byte, training bool) error {
+	var row map[string]interface{}
+	if err := json.Unmarshal(data, &row); err != nil {
+		return fmt.Errorf("not a JSON object: %w", err)
+	}
+	if !training {
+		return nil
+	}
+	messages, ok := row["messages"].([]interface{})
+	if !ok || len(messages) == 0 {
+		return fmt.Errorf("no messages")
+	}
+	for i, message := range messages {
+		fields, ok := message.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("message %d is not an object", i)
+		}
+		if role, _ := fields["role"].(string); role == "" {
+			return fmt.Errorf("message %d has no role", i)
+		}
+		if content, _ := fields["content"].(string); strings.TrimSpace(content) == "" {
+			return fmt.Errorf("message %d has no content", i)
+		}
+	}
+	return nil
+}
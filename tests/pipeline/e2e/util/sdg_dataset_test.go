@@ -0,0 +1,69 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSDGDataset(t *testing.T) {
+	root := "pipelines/ilab/run-1/"
+	keys := SDGDatasetKeys([]string{
+		root + "sdg-to-artifact-op/12/sdg/skills_train_msgs_2025-03-01T12_00_00.jsonl",
+		root + "sdg-to-artifact-op/12/sdg/node_datasets_2025-03-01T12_00_00/compositional_skills_writing_p10.jsonl",
+		root + "sdg-to-artifact-op/12/sdg/skills_recipe_2025-03-01T12_00_00.yaml",
+		root + "sdg-op/11/sdg/skills_train_msgs.jsonl",
+		"pipelines/ilab/run-2/sdg-to-artifact-op/3/sdg/skills_train_msgs.jsonl",
+	}, "run-1")
+	require.Equal(t, map[string]string{
+		"skills_train_msgs_2025-03-01T12_00_00.jsonl":                              root + "sdg-to-artifact-op/12/sdg/skills_train_msgs_2025-03-01T12_00_00.jsonl",
+		"node_datasets_2025-03-01T12_00_00/compositional_skills_writing_p10.jsonl": root + "sdg-to-artifact-op/12/sdg/node_datasets_2025-03-01T12_00_00/compositional_skills_writing_p10.jsonl",
+	}, keys)
+
+	mix, err := ValidateSDGDatasetFile("skills_train_msgs.jsonl", strings.NewReader(`{"messages": [{"role": "user", "content": "Write a haiku"}, {"role": "assistant", "content": "Autumn moonlight"}]}
+
+{"messages": [{"role": "user", "content": ""}]}
+{"messages": []}
+not json
+`))
+	require.NoError(t, err)
+	require.Equal(t, SDGDatasetFile{Path: "skills_train_msgs.jsonl", Rows: 4, Invalid: 3, FirstError: "line 3: message 0 has no content"}, mix)
+
+	leaf, err := ValidateSDGDatasetFile("node_datasets/writing.jsonl", strings.NewReader(`{"task_description": "haiku"}`+"\n"))
+	require.NoError(t, err)
+	require.Equal(t, SDGDatasetFile{Path: "node_datasets/writing.jsonl", Rows: 1}, leaf)
+
+	rules := SDGDatasetRules{MaxInvalidRowRate: 0.5, MinSamples: 1}
+	stats := SDGDatasetStats{Files: []SDGDatasetFile{mix, leaf}}
+	require.Equal(t, 0.6, stats.InvalidRowRate())
+	require.Equal(t, 1, stats.TrainingRows())
+	require.Equal(t, []string{
+		"SDG invalid row rate 0.60 exceeds the tolerated rate 0.50 (3 of 5 rows)",
+		"skills_train_msgs.jsonl has 3 invalid rows of 4, line 3: message 0 has no content",
+	}, CheckSDGDatasetStats(stats, rules))
+
+	rules.MaxInvalidRowRate = 0.6
+	require.Empty(t, CheckSDGDatasetStats(stats, rules))
+
+	rules.MinSamples = 2
+	stats.Files = append(stats.Files, SDGDatasetFile{Path: "node_datasets/empty.jsonl"})
+	require.Equal(t, []string{"node_datasets/empty.jsonl is empty", "training mixes have 1 valid rows, expected at least 2"}, CheckSDGDatasetStats(stats, rules))
+
+	require.Equal(t, []string{"no skills_train_msgs or knowledge_train_msgs file found in the sdg artifact", "training mixes have 0 valid rows, expected at least 2"},
+		CheckSDGDatasetStats(SDGDatasetStats{Files: []SDGDatasetFile{leaf}}, rules))
+	require.Equal(t, []string{"no JSON lines file found in the sdg artifact"}, CheckSDGDatasetStats(SDGDatasetStats{}, rules))
+}
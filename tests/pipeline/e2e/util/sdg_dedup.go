@@ -0,0 +1,256 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math/bits"
+	"sort"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+// maxNearDuplicateDistance is the largest distance the SimHash bands find every near duplicate for: two hashes
+// within 7 bits share one of their 8 bands of 8 bits
+const maxNearDuplicateDistance = 7
+
+// sdgDedupTopDuplicates is the number of most repeated rows reported
+const sdgDedupTopDuplicates = 5
+
+// SDGDedupRules configures the duplicate check of the SDG output
+type SDGDedupRules struct {
+	MaxDuplicateRate     float64 `mapstructure:"max_duplicate_rate"`
+	MaxNearDuplicateRate float64 `mapstructure:"max_near_duplicate_rate"`
+	// NearDuplicateDistance is the largest Hamming distance between the SimHashes of near duplicates
+	NearDuplicateDistance int `mapstructure:"near_duplicate_distance"`
+}
+
+// LoadSDGDedupRules reads the SDG duplicate rules from a YAML file
+func LoadSDGDedupRules(t *testing.T, path string) SDGDedupRules {
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig(), "Error loading SDG duplicate rules")
+
+	var rules SDGDedupRules
+	require.NoError(t, v.Unmarshal(&rules), "Error parsing SDG duplicate rules")
+	return rules
+}
+
+// SDGDuplicate is a row repeated in the training mixes
+type SDGDuplicate struct {
+	Count   int
+	Excerpt string
+}
+
+// SDGDedupStats tallies the duplicates of the training mixes
+type SDGDedupStats struct {
+	Rows int
+	// Duplicates counts the rows repeating an earlier row
+	Duplicates int
+	// NearDuplicates counts the rows close to an earlier row without repeating one
+	NearDuplicates int
+	// Top are the most repeated rows
+	Top []SDGDuplicate
+}
+
+// DuplicateRate is the fraction of rows repeating an earlier row
+func (s SDGDedupStats) DuplicateRate() float64 {
+	if s.Rows == 0 {
+		return 0
+	}
+	return float64(s.Duplicates) / float64(s.Rows)
+}
+
+// NearDuplicateRate is the fraction of rows close to an earlier row without repeating one
+func (s SDGDedupStats) NearDuplicateRate() float64 {
+	if s.Rows == 0 {
+		return 0
+	}
+	return float64(s.NearDuplicates) / float64(s.Rows)
+}
+
+// SDGDeduplicator finds the exact duplicates of the rows of the training mixes by the SHA-256 of their normalized
+// text, and their near duplicates by the SimHash of their words
+type SDGDeduplicator struct {
+	distance int
+	stats    SDGDedupStats
+	exact    map[[sha256.Size]byte]*SDGDuplicate
+	order    [][sha256.Size]byte
+	hashes   []uint64
+	bands    [8]map[uint8][]int
+}
+
+// NewSDGDeduplicator returns a deduplicator of the rows within distance of each other
+func NewSDGDeduplicator(distance int) (*SDGDeduplicator, error) {
+	if distance < 0 || distance > maxNearDuplicateDistance {
+		return nil, fmt.Errorf("near duplicate distance %d is not between 0 and %d", distance, maxNearDuplicateDistance)
+	}
+	d := &SDGDeduplicator{distance: distance, exact: map[[sha256.Size]byte]*SDGDuplicate{}}
+	for i := range d.bands {
+		d.bands[i] = map[uint8][]int{}
+	}
+	return d, nil
+}
+
+// AddFile adds the rows of a JSON lines file of the SDG output, if it is a training mix. A row is compared by the
+// content of its assistant messages; rows which are not valid are left to the dataset check.
+func (d *SDGDeduplicator) AddFile(path string, content io.Reader) error {
+	if !isTrainingMix(path) {
+		return nil
+	}
+	scanner := bufio.NewScanner(content)
+	// Rows of the knowledge mixes embed whole documents
+	scanner.Buffer(make([]byte, 0, 1024*1024), 64*1024*1024)
+	for scanner.Scan() {
+		if text, ok := sdgRowResponses(scanner.Bytes()); ok {
+			d.Add(text)
+		}
+	}
+	return scanner.Err()
+}
+
+// Add adds the text of a row
+func (d *SDGDeduplicator) Add(text string) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsNumber(r) })
+	d.stats.Rows++
+
+	key := sha256.Sum256([]byte(strings.Join(words, " ")))
+	if duplicate, ok := d.exact[key]; ok {
+		duplicate.Count++
+		d.stats.Duplicates++
+		return
+	}
+	d.exact[key] = &SDGDuplicate{Count: 1, Excerpt: sdgExcerpt(text)}
+	d.order = append(d.order, key)
+
+	hash := simHash(words)
+	if d.nearDuplicate(hash) {
+		d.stats.NearDuplicates++
+	}
+	index := len(d.hashes)
+	d.hashes = append(d.hashes, hash)
+	for band := range d.bands {
+		value := uint8(hash >> (8 * band))
+		d.bands[band][value] = append(d.bands[band][value], index)
+	}
+}
+
+// Stats returns the duplicates of the rows added so far
+func (d *SDGDeduplicator) Stats() SDGDedupStats {
+	stats := d.stats
+	stats.Top = nil
+	for _, key := range d.order {
+		if duplicate := d.exact[key]; duplicate.Count > 1 {
+			stats.Top = append(stats.Top, *duplicate)
+		}
+	}
+	sort.SliceStable(stats.Top, func(i, j int) bool { return stats.Top[i].Count > stats.Top[j].Count })
+	if len(stats.Top) > sdgDedupTopDuplicates {
+		stats.Top = stats.Top[:sdgDedupTopDuplicates]
+	}
+	return stats
+}
+
+// nearDuplicate tells whether an earlier distinct row is within the distance of a hash
+func (d *SDGDeduplicator) nearDuplicate(hash uint64) bool {
+	for band := range d.bands {
+		for _, index := range d.bands[band][uint8(hash>>(8*band))] {
+			if bits.OnesCount64(d.hashes[index]^hash) <= d.distance {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CheckSDGDedupStats verifies the duplicate and near duplicate rates of the training mixes stay within the rules
+func CheckSDGDedupStats(stats SDGDedupStats, rules SDGDedupRules) []string {
+	var failures []string
+	if rate := stats.DuplicateRate(); rate > rules.MaxDuplicateRate {
+		failures = append(failures, fmt.Sprintf("SDG duplicate rate %.2f exceeds the tolerated rate %.2f (%d of %d rows), the teacher may be repeating itself",
+			rate, rules.MaxDuplicateRate, stats.Duplicates, stats.Rows))
+		for _, duplicate := range stats.Top {
+			failures = append(failures, fmt.Sprintf("%d rows respond '%s'", duplicate.Count, duplicate.Excerpt))
+		}
+	}
+	if rate := stats.NearDuplicateRate(); rate > rules.MaxNearDuplicateRate {
+		failures = append(failures, fmt.Sprintf("SDG near duplicate rate %.2f exceeds the tolerated rate %.2f (%d of %d rows)",
+			rate, rules.MaxNearDuplicateRate, stats.NearDuplicates, stats.Rows))
+	}
+	return failures
+}
+
+// simHash returns the 64-bit SimHash of the words of a text. Responses are short, the hashes of longer shingles of
+// words drift too far apart for a reworded response to stay close.
+func simHash(words []string) uint64 {
+	var weights [64]int
+	for _, word := range words {
+		h := fnv.New64a()
+		h.Write([]byte(word))
+		value := h.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if value&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+	var hash uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			hash |= 1 << bit
+		}
+	}
+	return hash
+}
+
+// sdgRowResponses returns the content of the assistant messages of a row of a training mix
+func sdgRowResponses(data []byte) (string, bool) {
+	var row struct {
+		Messages []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(data, &row); err != nil {
+		return "", false
+	}
+	var responses []string
+	for _, message := range row.Messages {
+		if message.Role == "assistant" {
+			responses = append(responses, message.Content)
+		}
+	}
+	return strings.Join(responses, "\n"), len(responses) > 0
+}
+
+// sdgExcerpt returns the start of a text on one line
+func sdgExcerpt(text string) string {
+	const length = 80
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > length {
+		return string(runes[:length]) + "..."
+	}
+	return text
+}
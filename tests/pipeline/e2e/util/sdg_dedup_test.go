@@ -0,0 +1,60 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSDGDeduplicator(t *testing.T) {
+	rules := LoadSDGDedupRules(t, "../resources/sdg_dedup.yaml")
+	deduplicator, err := NewSDGDeduplicator(rules.NearDuplicateDistance)
+	require.NoError(t, err)
+
+	row := func(answer string) string {
+		return fmt.Sprintf(`{"messages":[{"role":"user","content":"question"},{"role":"assistant","content":%q}]}`, answer)
+	}
+	long := "The mitochondria is the organelle producing most of the chemical energy of the cell, in the form of adenosine triphosphate, through oxidative phosphorylation along its inner membrane"
+	rows := []string{
+		row("I cannot answer this question."),
+		row("I cannot answer this question."),
+		row("i CANNOT answer  this question"),
+		row(long),
+		row(strings.Replace(long, "most", "much", 1)),
+		row("Paris is the capital of France."),
+		`{"messages":[{"role":"user","content":"no response"}]}`,
+		"not json",
+	}
+	require.NoError(t, deduplicator.AddFile("skills_train_msgs_2025.jsonl", strings.NewReader(strings.Join(rows, "\n"))))
+	require.NoError(t, deduplicator.AddFile("node_datasets/knowledge_a.jsonl", strings.NewReader(rows[0])))
+
+	stats := deduplicator.Stats()
+	require.Equal(t, 6, stats.Rows)
+	require.Equal(t, 2, stats.Duplicates)
+	require.Equal(t, 1, stats.NearDuplicates)
+	require.Equal(t, []SDGDuplicate{{Count: 3, Excerpt: "I cannot answer this question."}}, stats.Top)
+
+	require.Equal(t, []string{
+		"SDG duplicate rate 0.33 exceeds the tolerated rate 0.05 (2 of 6 rows), the teacher may be repeating itself",
+		"3 rows respond 'I cannot answer this question.'",
+	}, CheckSDGDedupStats(stats, rules))
+	require.Empty(t, CheckSDGDedupStats(stats, SDGDedupRules{MaxDuplicateRate: 0.5, MaxNearDuplicateRate: 0.5}))
+
+	_, err = NewSDGDeduplicator(8)
+	require.EqualError(t, err, "near duplicate distance 8 is not between 0 and 7")
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// SDGRecipeScenario lists the SDG recipes that must and must not run for a taxonomy
+type SDGRecipeScenario struct {
+	Required []string `mapstructure:"required"`
+	Excluded []string `mapstructure:"excluded"`
+}
+
+// SDGRecipeRules maps every SDG recipe to the glob patterns of the files it writes into the SDG output, relative to
+// the sdg artifact, and every scenario to its expected recipes
+type SDGRecipeRules struct {
+	RecipeFiles map[string][]string          `mapstructure:"recipe_files"`
+	Scenarios   map[string]SDGRecipeScenario `mapstructure:"scenarios"`
+}
+
+// LoadSDGRecipeRules reads the SDG recipe rules from a YAML file
+func LoadSDGRecipeRules(t *testing.T, path string) SDGRecipeRules {
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig(), "Error loading SDG recipe rules")
+
+	var rules SDGRecipeRules
+	require.NoError(t, v.Unmarshal(&rules), "Error parsing SDG recipe rules")
+	return rules
+}
+
+// SDGArtifactFiles returns the sizes of the files of the sdg artifact of a run, by path relative to the artifact,
+// among the objects of the artifact store
+func SDGArtifactFiles(objects []ArtifactObject, runID string) map[string]int64 {
+	files := map[string]int64{}
+	for _, object := range objects {
+		if file, ok := sdgArtifactPath(object.Key, runID); ok {
+			files[file] = object.Size
+		}
+	}
+	return files
+}
+
+// DetectSDGRecipes counts the non-empty files of the SDG output written by every recipe
+func DetectSDGRecipes(files map[string]int64, recipeFiles map[string][]string) (map[string]int, error) {
+	counts := map[string]int{}
+	for recipe, patterns := range recipeFiles {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid file pattern for recipe '%s': %w", recipe, err)
+			}
+			for file, size := range files {
+				if matched, _ := path.Match(pattern, file); matched && size > 0 {
+					counts[recipe]++
+				}
+			}
+		}
+	}
+	return counts, nil
+}
+
+// CheckSDGRecipes returns the required recipes that did not run and the excluded recipes that did
+func CheckSDGRecipes(counts map[string]int, scenario SDGRecipeScenario) []string {
+	var failures []string
+	for _, recipe := range scenario.Required {
+		if counts[recipe] == 0 {
+			failures = append(failures, fmt.Sprintf("SDG recipe '%s' did not run", recipe))
+		}
+	}
+	for _, recipe := range scenario.Excluded {
+		if counts[recipe] > 0 {
+			failures = append(failures, fmt.Sprintf("SDG recipe '%s' ran although it is not expected for the taxonomy", recipe))
+		}
+	}
+	sort.Strings(failures)
+	return failures
+}
+
+// CheckTrainingPhases verifies a training launcher pod of every given phase completed successfully
+func CheckTrainingPhases(tasks []TaskPod, phases ...int) error {
+	for _, phase := range phases {
+		succeeded := false
+		for _, task := range tasks {
+			if task.Function == "pytorch_job_launcher_op" && fmt.Sprint(task.Parameters["phase_num"]) == fmt.Sprint(phase) &&
+				task.Pod.Status.Phase == corev1.PodSucceeded {
+				succeeded = true
+			}
+		}
+		if !succeeded {
+			return fmt.Errorf("training phase %d did not complete", phase)
+		}
+	}
+	return nil
+}
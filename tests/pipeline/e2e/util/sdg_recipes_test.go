@@ -0,0 +1,62 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestCheckSDGRecipes(t *testing.T) {
+	root := "pipelines/ilab/run-1/sdg-to-artifact-op/7/sdg/"
+	files := SDGArtifactFiles([]ArtifactObject{
+		{Key: root + "knowledge_train_msgs_2025-03-01T12_00_00.jsonl", Size: 2048},
+		{Key: root + "skills_train_msgs_2025-03-01T12_00_00.jsonl", Size: 0},
+		{Key: root + "node_datasets_2025-03-01T12_00_00/knowledge_science_astronomy_task.yaml", Size: 120},
+		{Key: root + "node_datasets_2025-03-01T12_00_00/mmlubench_knowledge_science_astronomy.jsonl", Size: 512},
+		{Key: "pipelines/ilab/run-2/sdg-to-artifact-op/3/sdg/skills_train_msgs.jsonl", Size: 64},
+	}, "run-1")
+	require.Len(t, files, 4)
+
+	recipeFiles := map[string][]string{
+		"knowledge": {"knowledge_train_msgs*.jsonl", "node_datasets_*/knowledge_*_task.yaml"},
+		"skills":    {"skills_train_msgs*.jsonl"},
+	}
+	counts, err := DetectSDGRecipes(files, recipeFiles)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"knowledge": 2}, counts)
+
+	require.Empty(t, CheckSDGRecipes(counts, SDGRecipeScenario{Required: []string{"knowledge"}, Excluded: []string{"skills"}}))
+	require.Equal(t, []string{
+		"SDG recipe 'knowledge' ran although it is not expected for the taxonomy",
+		"SDG recipe 'skills' did not run",
+	}, CheckSDGRecipes(counts, SDGRecipeScenario{Required: []string{"skills"}, Excluded: []string{"knowledge"}}))
+
+	_, err = DetectSDGRecipes(files, map[string][]string{"knowledge": {"["}})
+	require.Error(t, err)
+}
+
+func TestCheckTrainingPhases(t *testing.T) {
+	launcher := func(phase float64, status corev1.PodPhase) TaskPod {
+		return TaskPod{
+			Pod:        corev1.Pod{Status: corev1.PodStatus{Phase: status}},
+			Function:   "pytorch_job_launcher_op",
+			Parameters: map[string]interface{}{"phase_num": phase},
+		}
+	}
+	require.NoError(t, CheckTrainingPhases([]TaskPod{launcher(1, corev1.PodSucceeded), launcher(2, corev1.PodSucceeded)}, 1, 2))
+	require.EqualError(t, CheckTrainingPhases([]TaskPod{launcher(1, corev1.PodSucceeded), launcher(2, corev1.PodFailed)}, 1, 2), "training phase 2 did not complete")
+}
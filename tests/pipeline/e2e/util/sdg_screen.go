@@ -0,0 +1,326 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+// SDGScreenModelCategory is the category of the rows flagged by the model-based screen
+const SDGScreenModelCategory = "model"
+
+// maxSDGScreenExamples bounds the hits quoted in the report
+const maxSDGScreenExamples = 20
+
+// SDGScreenRules configures the spot scan of the SDG output for personal data and toxic content
+type SDGScreenRules struct {
+	SampleSize int               `mapstructure:"sample_size"`
+	PII        map[string]string `mapstructure:"pii"`
+	Toxicity   []string          `mapstructure:"toxicity"`
+}
+
+// LoadSDGScreenRules reads the SDG screen rules from a YAML file
+func LoadSDGScreenRules(t *testing.T, path string) SDGScreenRules {
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig(), "Error loading SDG screen rules")
+
+	var rules SDGScreenRules
+	require.NoError(t, v.Unmarshal(&rules), "Error parsing SDG screen rules")
+	return rules
+}
+
+// SDGRow is the text of a row of the SDG output
+type SDGRow struct {
+	Path string
+	Line int
+	Text string
+}
+
+// SDGRowSampler keeps a uniform sample of the rows added to it, by reservoir sampling with a fixed seed so reruns
+// over the same dataset screen the same rows
+type SDGRowSampler struct {
+	size   int
+	seen   int
+	rows   []SDGRow
+	random *rand.Rand
+}
+
+// NewSDGRowSampler returns a sampler keeping size rows
+func NewSDGRowSampler(size int) *SDGRowSampler {
+	return &SDGRowSampler{size: size, random: rand.New(rand.NewSource(1))}
+}
+
+// AddFile adds the rows of a JSON lines file of the SDG output. The text of a row is the content of its messages,
+// or the string values of the rows without messages; rows which are not JSON objects are left to the dataset check.
+func (s *SDGRowSampler) AddFile(path string, content io.Reader) error {
+	scanner := bufio.NewScanner(content)
+	// Rows of the knowledge mixes embed whole documents
+	scanner.Buffer(make([]byte, 0, 1024*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text, ok := sdgRowText(scanner.Bytes())
+		if !ok {
+			continue
+		}
+		s.seen++
+		row := SDGRow{Path: path, Line: line, Text: text}
+		if len(s.rows) < s.size {
+			s.rows = append(s.rows, row)
+		} else if i := s.random.Intn(s.seen); i < s.size {
+			s.rows[i] = row
+		}
+	}
+	return scanner.Err()
+}
+
+// Rows returns the sampled rows, in file and line order
+func (s *SDGRowSampler) Rows() []SDGRow {
+	rows := append([]SDGRow(nil), s.rows...)
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Path != rows[j].Path {
+			return rows[i].Path < rows[j].Path
+		}
+		return rows[i].Line < rows[j].Line
+	})
+	return rows
+}
+
+// Seen returns the number of rows added to the sampler
+func (s *SDGRowSampler) Seen() int {
+	return s.seen
+}
+
+// SDGScreenHit is a sampled row flagged by the screen. Match is redacted so the report does not spread the personal
+// data it flags.
+type SDGScreenHit struct {
+	Path     string
+	Line     int
+	Category string
+	Match    string
+}
+
+// SDGScreenReport counts the sampled rows with a hit, by category
+type SDGScreenReport struct {
+	Rows int
+	// Total is the number of rows of the dataset the sample was drawn from
+	Total int
+	Hits  map[string]int
+	// Flagged is the number of rows with at least one hit
+	Flagged  int
+	Examples []SDGScreenHit
+	// ModelErrors counts the rows the model-based screen failed to classify
+	ModelErrors int
+}
+
+// SDGScreen flags personal data with regular expressions and toxic content with keywords, and optionally asks a
+// model whether a row holds either
+type SDGScreen struct {
+	pii      map[string]*regexp.Regexp
+	toxicity *regexp.Regexp
+	// Classifier is the optional model-based screen, reporting whether a text holds personal data or toxic content
+	Classifier func(ctx context.Context, text string) (bool, error)
+}
+
+// NewSDGScreen compiles the rules of the screen
+func NewSDGScreen(rules SDGScreenRules) (*SDGScreen, error) {
+	screen := &SDGScreen{pii: map[string]*regexp.Regexp{}}
+	for kind, pattern := range rules.PII {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern of PII %s: %w", kind, err)
+		}
+		screen.pii[kind] = compiled
+	}
+	if len(rules.Toxicity) > 0 {
+		keywords := make([]string, len(rules.Toxicity))
+		for i, keyword := range rules.Toxicity {
+			keywords[i] = regexp.QuoteMeta(keyword)
+		}
+		screen.toxicity = regexp.MustCompile(`(?i)\b(?:` + strings.Join(keywords, "|") + `)\b`)
+	}
+	return screen, nil
+}
+
+// Screen scans the sampled rows, total being the number of rows they were sampled from
+func (s *SDGScreen) Screen(ctx context.Context, rows []SDGRow, total int) SDGScreenReport {
+	kinds := make([]string, 0, len(s.pii))
+	for kind := range s.pii {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	report := SDGScreenReport{Rows: len(rows), Total: total, Hits: map[string]int{}}
+	for _, row := range rows {
+		var hits []SDGScreenHit
+		for _, kind := range kinds {
+			if match := s.pii[kind].FindString(row.Text); match != "" {
+				hits = append(hits, SDGScreenHit{Path: row.Path, Line: row.Line, Category: "pii:" + kind, Match: redact(match)})
+			}
+		}
+		if s.toxicity != nil {
+			if match := s.toxicity.FindString(row.Text); match != "" {
+				hits = append(hits, SDGScreenHit{Path: row.Path, Line: row.Line, Category: "toxicity", Match: redact(match)})
+			}
+		}
+		if s.Classifier != nil {
+			flagged, err := s.Classifier(ctx, row.Text)
+			if err != nil {
+				report.ModelErrors++
+			} else if flagged {
+				hits = append(hits, SDGScreenHit{Path: row.Path, Line: row.Line, Category: SDGScreenModelCategory})
+			}
+		}
+
+		if len(hits) > 0 {
+			report.Flagged++
+		}
+		for _, hit := range hits {
+			report.Hits[hit.Category]++
+			if len(report.Examples) < maxSDGScreenExamples {
+				report.Examples = append(report.Examples, hit)
+			}
+		}
+	}
+	return report
+}
+
+// RenderSDGScreen renders the screen of the SDG output as markdown
+func RenderSDGScreen(report SDGScreenReport) string {
+	categories := make([]string, 0, len(report.Hits))
+	for category := range report.Hits {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	var b strings.Builder
+	b.WriteString("# SDG output screen\n\n")
+	fmt.Fprintf(&b, "%d rows sampled of %d, %d flagged.\n\n", report.Rows, report.Total, report.Flagged)
+	b.WriteString("| Category | Rows |\n|---|---|\n")
+	for _, category := range categories {
+		fmt.Fprintf(&b, "| %s | %d |\n", category, report.Hits[category])
+	}
+	if report.ModelErrors > 0 {
+		fmt.Fprintf(&b, "\nThe model failed to classify %d rows.\n", report.ModelErrors)
+	}
+	if len(report.Examples) > 0 {
+		b.WriteString("\n| File | Line | Category | Match |\n|---|---|---|---|\n")
+		for _, hit := range report.Examples {
+			fmt.Fprintf(&b, "| %s | %d | %s | %s |\n", hit.Path, hit.Line, hit.Category, hit.Match)
+		}
+	}
+	b.WriteString("\nHits are candidates for review: the regexes and keywords neither prove nor rule out personal data or toxic content.\n")
+	return b.String()
+}
+
+// sdgScreenPrompt asks the model for a one-word verdict on a row
+const sdgScreenPrompt = "Does the following text contain personal data, such as names with contact details, " +
+	"addresses, phone numbers or identification numbers, or toxic, hateful or abusive content? Answer with yes or no only.\n\n"
+
+// ModelSDGScreenClassifier asks the model of a model server whether a text holds personal data or toxic content
+func ModelSDGScreenClassifier(client *http.Client, secret ModelServerSecret) func(ctx context.Context, text string) (bool, error) {
+	return func(ctx context.Context, text string) (bool, error) {
+		body, err := json.Marshal(map[string]interface{}{
+			"model":       secret.ModelName,
+			"messages":    []map[string]string{{"role": "user", "content": sdgScreenPrompt + text}},
+			"max_tokens":  3,
+			"temperature": 0,
+		})
+		if err != nil {
+			return false, err
+		}
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(secret.Endpoint, "/")+"/chat/completions", bytes.NewReader(body))
+		if err != nil {
+			return false, err
+		}
+		request.Header.Set("Authorization", "Bearer "+secret.APIToken)
+		request.Header.Set("Content-Type", "application/json")
+		response, err := client.Do(request)
+		if err != nil {
+			return false, err
+		}
+		defer response.Body.Close()
+		if response.StatusCode != http.StatusOK {
+			return false, fmt.Errorf("chat completion returned %s", response.Status)
+		}
+
+		var completion struct {
+			Choices []struct {
+				Message struct {
+					Content string `json:"content"`
+				} `json:"message"`
+			} `json:"choices"`
+		}
+		if err := json.NewDecoder(response.Body).Decode(&completion); err != nil {
+			return false, fmt.Errorf("invalid chat completion: %w", err)
+		}
+		if len(completion.Choices) == 0 {
+			return false, fmt.Errorf("chat completion has no choices")
+		}
+		answer := strings.ToLower(strings.TrimSpace(completion.Choices[0].Message.Content))
+		return strings.HasPrefix(answer, "yes"), nil
+	}
+}
+
+// sdgRowText returns the text of a row of the SDG output
+func sdgRowText(data []byte) (string, bool) {
+	var row map[string]interface{}
+	if err := json.Unmarshal(data, &row); err != nil {
+		return "", false
+	}
+	var texts []string
+	if messages, ok := row["messages"].([]interface{}); ok {
+		for _, message := range messages {
+			if fields, ok := message.(map[string]interface{}); ok {
+				if content, ok := fields["content"].(string); ok {
+					texts = append(texts, content)
+				}
+			}
+		}
+		return strings.Join(texts, "\n"), true
+	}
+	keys := make([]string, 0, len(row))
+	for key := range row {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if value, ok := row[key].(string); ok {
+			texts = append(texts, value)
+		}
+	}
+	return strings.Join(texts, "\n"), true
+}
+
+// redact keeps the first two characters of a match
+func redact(match string) string {
+	runes := []rune(match)
+	if len(runes) <= 2 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:2]) + strings.Repeat("*", len(runes)-2)
+}
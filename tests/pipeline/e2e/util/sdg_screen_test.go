@@ -0,0 +1,99 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSDGRowSampler(t *testing.T) {
+	var lines []string
+	for i := 0; i < 100; i++ {
+		lines = append(lines, fmt.Sprintf(`{"messages":[{"role":"user","content":"question %d"},{"role":"assistant","content":"answer"}]}`, i))
+	}
+	lines = append(lines, "not json", `{"task_description":"knowledge","document":"text","leaf_node_path":"x"}`)
+
+	sample := func() []SDGRow {
+		sampler := NewSDGRowSampler(10)
+		require.NoError(t, sampler.AddFile("skills_train_msgs.jsonl", strings.NewReader(strings.Join(lines, "\n"))))
+		require.Equal(t, 101, sampler.Seen())
+		return sampler.Rows()
+	}
+	rows := sample()
+	require.Len(t, rows, 10)
+	require.Equal(t, rows, sample())
+	require.Less(t, rows[0].Line, rows[9].Line)
+
+	text, ok := sdgRowText([]byte(lines[3]))
+	require.True(t, ok)
+	require.Equal(t, "question 3\nanswer", text)
+	text, _ = sdgRowText([]byte(lines[101]))
+	require.Equal(t, "text\nx\nknowledge", text)
+}
+
+func TestSDGScreen(t *testing.T) {
+	rules := LoadSDGScreenRules(t, "../resources/sdg_screen.yaml")
+	screen, err := NewSDGScreen(rules)
+	require.NoError(t, err)
+
+	judge := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		answer := "No."
+		switch {
+		case strings.Contains(request.Messages[0].Content, "Bob lives"):
+			answer = "Yes"
+		case strings.Contains(request.Messages[0].Content, "failure"):
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, `{"choices":[{"message":{"content":%q}}]}`, answer)
+	}))
+	defer judge.Close()
+	screen.Classifier = ModelSDGScreenClassifier(judge.Client(), ModelServerSecret{Endpoint: judge.URL + "/v1", ModelName: "judge"})
+
+	report := screen.Screen(context.Background(), []SDGRow{
+		{Path: "a.jsonl", Line: 1, Text: "Contact jane.doe@example.com or call 555-123-4567."},
+		{Path: "a.jsonl", Line: 2, Text: "Shut up, you idiot."},
+		{Path: "a.jsonl", Line: 3, Text: "Photosynthesis converts light into chemical energy."},
+		{Path: "a.jsonl", Line: 4, Text: "Bob lives on the corner of the street."},
+		{Path: "a.jsonl", Line: 5, Text: "A failure of the model server."},
+		{Path: "a.jsonl", Line: 6, Text: "Version 1.2.3 is stupidly fast."},
+	}, 60)
+	require.Equal(t, map[string]int{"pii:email": 1, "pii:phone": 1, "toxicity": 1, "model": 1}, report.Hits)
+	require.Equal(t, 3, report.Flagged)
+	require.Equal(t, 1, report.ModelErrors)
+	require.Equal(t, SDGScreenHit{Path: "a.jsonl", Line: 1, Category: "pii:email", Match: "ja******************"}, report.Examples[0])
+
+	rendered := RenderSDGScreen(report)
+	require.Contains(t, rendered, "6 rows sampled of 60, 3 flagged.")
+	require.Contains(t, rendered, "| toxicity | 1 |")
+	require.Contains(t, rendered, "| a.jsonl | 2 | toxicity | Sh***** |")
+	require.NotContains(t, rendered, "jane.doe")
+
+	_, err = NewSDGScreen(SDGScreenRules{PII: map[string]string{"broken": "("}})
+	require.ErrorContains(t, err, "invalid pattern of PII broken")
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CheckSharedEndpoint verifies a teacher and a judge secret point at the same model server: endpoint, model name,
+// API token and CA
+func CheckSharedEndpoint(teacher, judge ModelServerSecret) []string {
+	var problems []string
+	if normalizeEndpoint(teacher.Endpoint) != normalizeEndpoint(judge.Endpoint) {
+		problems = append(problems, fmt.Sprintf("the teacher endpoint %s differs from the judge endpoint %s", teacher.Endpoint, judge.Endpoint))
+	}
+	if teacher.ModelName != judge.ModelName {
+		problems = append(problems, fmt.Sprintf("the teacher model %s differs from the judge model %s", teacher.ModelName, judge.ModelName))
+	}
+	if teacher.APIToken != judge.APIToken {
+		problems = append(problems, "the teacher and judge API tokens differ")
+	}
+	if teacher.CA != judge.CA {
+		problems = append(problems, fmt.Sprintf("the teacher CA %+v differs from the judge CA %+v", teacher.CA, judge.CA))
+	}
+	return problems
+}
+
+// normalizeEndpoint lowercases the scheme and host of an endpoint and drops its trailing slash
+func normalizeEndpoint(endpoint string) string {
+	parsed, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil {
+		return endpoint
+	}
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	parsed.Host = strings.ToLower(parsed.Host)
+	return parsed.String()
+}
+
+// CheckRunSharedSecret verifies the SDG task and the eval tasks of a run read the same model server secret
+func CheckRunSharedSecret(tasks []TaskPod) []string {
+	secrets := map[string][]string{}
+	for _, task := range tasks {
+		for _, param := range []string{"sdg_secret_name", "judge_secret_name"} {
+			if name, ok := task.Parameters[param]; ok {
+				secrets[fmt.Sprint(name)] = append(secrets[fmt.Sprint(name)], task.Function)
+			}
+		}
+	}
+	switch len(secrets) {
+	case 0:
+		return []string{"no SDG or eval task of the run reads a model server secret"}
+	case 1:
+		return nil
+	}
+	var uses []string
+	for name, functions := range secrets {
+		uses = append(uses, fmt.Sprintf("%s by %s", name, strings.Join(functions, ", ")))
+	}
+	sort.Strings(uses)
+	return []string{"the SDG and eval tasks read different secrets: " + strings.Join(uses, "; ")}
+}
+
+// LoadResult sums up the requests of ConcurrentLoad
+type LoadResult struct {
+	Requests int
+	Failures int
+	// Errors are the distinct failures
+	Errors   []string
+	Duration time.Duration
+}
+
+// ConcurrentLoad sends requests short chat completions to the model server of a secret from concurrency clients at
+// once, as SDG and eval do when they share the model server
+func ConcurrentLoad(ctx context.Context, client *http.Client, secret ModelServerSecret, concurrency, requests int) LoadResult {
+	body, _ := json.Marshal(map[string]interface{}{
+		"model":      secret.ModelName,
+		"messages":   []map[string]string{{"role": "user", "content": "Reply with OK."}},
+		"max_tokens": 8,
+	})
+
+	var mu sync.Mutex
+	result := LoadResult{Requests: requests}
+	errors := map[string]bool{}
+	queue := make(chan struct{}, requests)
+	for i := 0; i < requests; i++ {
+		queue <- struct{}{}
+	}
+	close(queue)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range queue {
+				if err := chatCompletion(ctx, client, secret, body); err != nil {
+					mu.Lock()
+					result.Failures++
+					errors[err.Error()] = true
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	result.Duration = time.Since(start)
+
+	for err := range errors {
+		result.Errors = append(result.Errors, err)
+	}
+	sort.Strings(result.Errors)
+	return result
+}
+
+func chatCompletion(ctx context.Context, client *http.Client, secret ModelServerSecret, body []byte) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(secret.Endpoint, "/")+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer "+secret.APIToken)
+	request.Header.Set("Content-Type", "application/json")
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("chat completion returned %s", response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/fakellm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSharedEndpoint(t *testing.T) {
+	teacher := ModelServerSecret{APIToken: "token", Endpoint: "https://Mixtral.example.com/v1/", ModelName: "mixtral"}
+	require.Empty(t, CheckSharedEndpoint(teacher, ModelServerSecret{APIToken: "token", Endpoint: "https://mixtral.example.com/v1", ModelName: "mixtral"}))
+
+	judge := ModelServerSecret{APIToken: "other", Endpoint: "https://prometheus.example.com/v1", ModelName: "mixtral", CA: KubeRootCA}
+	require.Equal(t, []string{
+		"the teacher endpoint https://Mixtral.example.com/v1/ differs from the judge endpoint https://prometheus.example.com/v1",
+		"the teacher and judge API tokens differ",
+		"the teacher CA {ConfigMap: Key:} differs from the judge CA {ConfigMap:kube-root-ca.crt Key:ca.crt}",
+	}, CheckSharedEndpoint(teacher, judge))
+}
+
+func TestCheckRunSharedSecret(t *testing.T) {
+	tasks := []TaskPod{
+		{Function: "sdg_op", Parameters: map[string]interface{}{"sdg_secret_name": "model-secret"}},
+		{Function: "run_mt_bench_op", Parameters: map[string]interface{}{"judge_secret_name": "model-secret"}},
+		{Function: "run_final_eval_op", Parameters: map[string]interface{}{"judge_secret_name": "model-secret"}},
+	}
+	require.Empty(t, CheckRunSharedSecret(tasks))
+
+	tasks[2].Parameters["judge_secret_name"] = "judge-secret"
+	require.Equal(t, []string{"the SDG and eval tasks read different secrets: judge-secret by run_final_eval_op; model-secret by sdg_op, run_mt_bench_op"}, CheckRunSharedSecret(tasks))
+	require.Equal(t, []string{"no SDG or eval task of the run reads a model server secret"}, CheckRunSharedSecret(nil))
+}
+
+func TestConcurrentLoad(t *testing.T) {
+	server := httptest.NewServer(fakellm.NewServer(fakellm.Config{ModelName: "mixtral", APIKey: "token", ErrorRate: 0.25, ErrorStatus: http.StatusServiceUnavailable}))
+	defer server.Close()
+
+	secret := ModelServerSecret{APIToken: "token", Endpoint: server.URL + "/v1", ModelName: "mixtral"}
+	result := ConcurrentLoad(context.Background(), server.Client(), secret, 4, 16)
+	require.Equal(t, 16, result.Requests)
+	require.Equal(t, 4, result.Failures)
+	require.Equal(t, []string{"chat completion returned 503 Service Unavailable"}, result.Errors)
+
+	secret.APIToken = "wrong"
+	result = ConcurrentLoad(context.Background(), server.Client(), secret, 2, 2)
+	require.Equal(t, 2, result.Failures)
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/clock"
+)
+
+// SoakLimits bounds the growth of the namespace resources and the API error rate over a soak test
+type SoakLimits struct {
+	// MaxGrowthPerRun is the growth of a resource count per run, by resource, above which it leaks
+	MaxGrowthPerRun map[string]float64 `mapstructure:"max_growth_per_run"`
+	MaxAPIErrorRate float64            `mapstructure:"max_api_error_rate"`
+}
+
+// LoadSoakLimits reads the soak limits from a YAML file
+func LoadSoakLimits(t *testing.T, path string) SoakLimits {
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig(), "Error loading soak limits")
+
+	var limits SoakLimits
+	require.NoError(t, v.Unmarshal(&limits), "Error parsing soak limits")
+	return limits
+}
+
+// SoakSample records one run of a soak test and the namespace resources left after it
+type SoakSample struct {
+	Run       int            `json:"run"`
+	RunID     string         `json:"run_id,omitempty"`
+	Succeeded bool           `json:"succeeded"`
+	Duration  time.Duration  `json:"duration"`
+	Resources map[string]int `json:"resources"`
+}
+
+// CountNamespaceResources counts the resources of a namespace soak tests watch for leaks: pvcs, secrets,
+// configmaps, completed-pods and failed-pods
+func CountNamespaceResources(ctx context.Context, client kubernetes.Interface, namespace string) (map[string]int, error) {
+	pvcs, err := client.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PVCs: %w", err)
+	}
+	secrets, err := client.CoreV1().Secrets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+	configMaps, err := client.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ConfigMaps: %w", err)
+	}
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+
+	counts := map[string]int{
+		"pvcs":           len(pvcs.Items),
+		"secrets":        len(secrets.Items),
+		"configmaps":     len(configMaps.Items),
+		"completed-pods": 0,
+		"failed-pods":    0,
+	}
+	for _, pod := range pods.Items {
+		switch pod.Status.Phase {
+		case corev1.PodSucceeded:
+			counts["completed-pods"]++
+		case corev1.PodFailed:
+			counts["failed-pods"]++
+		}
+	}
+	return counts, nil
+}
+
+// ResourceGrowth returns the growth per run of every resource count of the samples, the least squares slope of the
+// count over the run number. Fewer than two samples grow by nothing.
+func ResourceGrowth(samples []SoakSample) map[string]float64 {
+	growth := map[string]float64{}
+	if len(samples) < 2 {
+		return growth
+	}
+	var meanRun float64
+	for _, sample := range samples {
+		meanRun += float64(sample.Run)
+	}
+	meanRun /= float64(len(samples))
+
+	for resource := range samples[0].Resources {
+		var meanCount float64
+		for _, sample := range samples {
+			meanCount += float64(sample.Resources[resource])
+		}
+		meanCount /= float64(len(samples))
+
+		var covariance, variance float64
+		for _, sample := range samples {
+			covariance += (float64(sample.Run) - meanRun) * (float64(sample.Resources[resource]) - meanCount)
+			variance += (float64(sample.Run) - meanRun) * (float64(sample.Run) - meanRun)
+		}
+		if variance > 0 {
+			growth[resource] = covariance / variance
+		}
+	}
+	return growth
+}
+
+// CheckSoak returns the resources growing faster than their limit over the samples and an API error rate over
+// the limit. Resources without a limit are not checked.
+func CheckSoak(samples []SoakSample, probes APIProbeStats, limits SoakLimits) []string {
+	var problems []string
+	growth := ResourceGrowth(samples)
+	resources := make([]string, 0, len(limits.MaxGrowthPerRun))
+	for resource := range limits.MaxGrowthPerRun {
+		resources = append(resources, resource)
+	}
+	sort.Strings(resources)
+	for _, resource := range resources {
+		if growth[resource] > limits.MaxGrowthPerRun[resource] {
+			problems = append(problems, fmt.Sprintf("%s grow by %.2f per run, above %.2f", resource, growth[resource], limits.MaxGrowthPerRun[resource]))
+		}
+	}
+	if rate := probes.ErrorRate(); rate > limits.MaxAPIErrorRate {
+		problems = append(problems, fmt.Sprintf("%.2f%% of the API probes failed, above %.2f%%: %v", 100*rate, 100*limits.MaxAPIErrorRate, probes.Errors))
+	}
+	return problems
+}
+
+// APIProbeStats counts the API probes of a soak test by target, e.g. "pipeline-server"
+type APIProbeStats struct {
+	Requests map[string]int `json:"requests"`
+	Failures map[string]int `json:"failures"`
+	// Errors counts the failures by message
+	Errors map[string]int `json:"errors"`
+}
+
+// ErrorRate returns the fraction of the probes that failed
+func (s APIProbeStats) ErrorRate() float64 {
+	var requests, failures int
+	for target, count := range s.Requests {
+		requests += count
+		failures += s.Failures[target]
+	}
+	if requests == 0 {
+		return 0
+	}
+	return float64(failures) / float64(requests)
+}
+
+// APIProbe checks an API is available, e.g. by requesting its health endpoint
+type APIProbe func(ctx context.Context) error
+
+// HTTPProbe requests a URL with the bearer token, failing on transport errors and on statuses other than 200
+func HTTPProbe(client *http.Client, url, bearerToken string) APIProbe {
+	return func(ctx context.Context) error {
+		request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		if bearerToken != "" {
+			request.Header.Set("Authorization", "Bearer "+bearerToken)
+		}
+		response, err := client.Do(request)
+		if err != nil {
+			return err
+		}
+		defer response.Body.Close()
+		if response.StatusCode != http.StatusOK {
+			return fmt.Errorf("%s returned %s", url, response.Status)
+		}
+		return nil
+	}
+}
+
+// ProbeAPIs runs the probes by target at every interval until the returned stop function is called, which returns
+// their stats
+func ProbeAPIs(probes map[string]APIProbe, interval time.Duration) (stop func() APIProbeStats) {
+	return probeAPIs(clock.RealClock{}, probes, interval)
+}
+
+func probeAPIs(clk clock.Clock, probes map[string]APIProbe, interval time.Duration) (stop func() APIProbeStats) {
+	stats := APIProbeStats{Requests: map[string]int{}, Failures: map[string]int{}, Errors: map[string]int{}}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	tick := clk.Tick(interval)
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			case <-tick:
+				for target, probe := range probes {
+					ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+					err := probe(ctx)
+					cancel()
+					stats.Requests[target]++
+					if err != nil {
+						stats.Failures[target]++
+						stats.Errors[fmt.Sprintf("%s: %v", target, err)]++
+					}
+				}
+			}
+		}
+	}()
+	return func() APIProbeStats {
+		close(done)
+		<-stopped
+		return stats
+	}
+}
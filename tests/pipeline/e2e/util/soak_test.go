@@ -0,0 +1,92 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestLoadSoakLimits(t *testing.T) {
+	limits := LoadSoakLimits(t, "../resources/soak_limits.yaml")
+	require.Equal(t, 0.1, limits.MaxGrowthPerRun["pvcs"])
+	require.Equal(t, 1.0, limits.MaxGrowthPerRun["completed-pods"])
+	require.Equal(t, 0.01, limits.MaxAPIErrorRate)
+}
+
+func TestCountNamespaceResources(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "output", Namespace: "ns"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "judge-secret", Namespace: "ns"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "sdg", Namespace: "ns"}, Status: corev1.PodStatus{Phase: corev1.PodSucceeded}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "train", Namespace: "ns"}, Status: corev1.PodStatus{Phase: corev1.PodFailed}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "eval", Namespace: "ns"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+	)
+	counts, err := CountNamespaceResources(context.Background(), client, "ns")
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"pvcs": 1, "secrets": 1, "configmaps": 0, "completed-pods": 1, "failed-pods": 1}, counts)
+}
+
+func TestCheckSoak(t *testing.T) {
+	samples := []SoakSample{
+		{Run: 1, Resources: map[string]int{"pvcs": 3, "secrets": 10, "completed-pods": 20}},
+		{Run: 2, Resources: map[string]int{"pvcs": 6, "secrets": 10, "completed-pods": 20}},
+		{Run: 3, Resources: map[string]int{"pvcs": 9, "secrets": 11, "completed-pods": 21}},
+		{Run: 4, Resources: map[string]int{"pvcs": 12, "secrets": 10, "completed-pods": 20}},
+	}
+	growth := ResourceGrowth(samples)
+	require.InDelta(t, 3, growth["pvcs"], 1e-9)
+	require.InDelta(t, 0, growth["completed-pods"], 0.2)
+	require.Empty(t, ResourceGrowth(samples[:1]))
+
+	limits := SoakLimits{MaxGrowthPerRun: map[string]float64{"pvcs": 0.1, "secrets": 0.1, "completed-pods": 1}, MaxAPIErrorRate: 0.01}
+	probes := APIProbeStats{
+		Requests: map[string]int{"pipeline-server": 50, "api-server": 50},
+		Failures: map[string]int{"pipeline-server": 2},
+		Errors:   map[string]int{"pipeline-server: 503 Service Unavailable": 2},
+	}
+	require.Equal(t, []string{
+		"pvcs grow by 3.00 per run, above 0.10",
+		"2.00% of the API probes failed, above 1.00%: map[pipeline-server: 503 Service Unavailable:2]",
+	}, CheckSoak(samples, probes, limits))
+	require.Zero(t, APIProbeStats{}.ErrorRate())
+}
+
+func TestProbeAPIs(t *testing.T) {
+	clock := testingclock.NewFakeClock(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	calls := make(chan string, 10)
+	stop := probeAPIs(clock, map[string]APIProbe{
+		"pipeline-server": func(ctx context.Context) error {
+			calls <- "pipeline-server"
+			return errors.New("connection refused")
+		},
+	}, time.Minute)
+
+	clock.Step(time.Minute)
+	require.Equal(t, "pipeline-server", <-calls)
+	clock.Step(time.Minute)
+	require.Equal(t, "pipeline-server", <-calls)
+	stats := stop()
+	require.Equal(t, 2, stats.Requests["pipeline-server"])
+	require.Equal(t, 2, stats.Failures["pipeline-server"])
+	require.Equal(t, map[string]int{"pipeline-server: connection refused": 2}, stats.Errors)
+}
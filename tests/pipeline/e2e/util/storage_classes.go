@@ -0,0 +1,116 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultIOBoundSpread is the relative spread of the duration of a phase across storage classes above which the phase
+// is reported as I/O-bound
+const DefaultIOBoundSpread = 0.2
+
+// StorageClassResult is the outcome of the run on a storage class
+type StorageClassResult struct {
+	StorageClass string
+	RunID        string
+	Duration     time.Duration
+	Phases       map[string]time.Duration
+	Err          error
+}
+
+// IOBoundPhases returns the phases, in pipeline order, whose duration varies across the successful runs by more than
+// spread, relative to the fastest run. The runs only differ by their storage class, so the duration of these phases
+// depends on the storage.
+func IOBoundPhases(results []StorageClassResult, spread float64) []string {
+	var phases []string
+	for _, phase := range PipelinePhases {
+		var fastest, slowest time.Duration
+		count := 0
+		for _, result := range results {
+			duration, ok := result.Phases[phase]
+			if result.Err != nil || !ok {
+				continue
+			}
+			if count == 0 || duration < fastest {
+				fastest = duration
+			}
+			if duration > slowest {
+				slowest = duration
+			}
+			count++
+		}
+		if count > 1 && fastest > 0 && float64(slowest-fastest)/float64(fastest) > spread {
+			phases = append(phases, phase)
+		}
+	}
+	return phases
+}
+
+// RenderStorageClassReport renders the phase durations of the runs on every storage class as a markdown table, with
+// the I/O-bound phases marked
+func RenderStorageClassReport(results []StorageClassResult, spread float64) string {
+	ioBound := map[string]bool{}
+	for _, phase := range IOBoundPhases(results, spread) {
+		ioBound[phase] = true
+	}
+
+	var report strings.Builder
+	report.WriteString("# Storage classes\n\n")
+	fmt.Fprintf(&report, "Phases marked I/O-bound vary by more than %.0f%% across the storage classes.\n\n", spread*100)
+	report.WriteString("| Phase |")
+	for _, result := range results {
+		fmt.Fprintf(&report, " %s |", result.StorageClass)
+	}
+	report.WriteString("\n|---|" + strings.Repeat("---|", len(results)) + "\n")
+	for _, phase := range PipelinePhases {
+		row := fmt.Sprintf("| %s |", phase)
+		if ioBound[phase] {
+			row = fmt.Sprintf("| %s (I/O-bound) |", phase)
+		}
+		found := false
+		for _, result := range results {
+			duration, ok := result.Phases[phase]
+			if !ok {
+				row += " - |"
+				continue
+			}
+			row += fmt.Sprintf(" %s |", duration.Round(time.Second))
+			found = true
+		}
+		if found {
+			report.WriteString(row + "\n")
+		}
+	}
+	report.WriteString("| Total |")
+	for _, result := range results {
+		fmt.Fprintf(&report, " %s |", result.Duration.Round(time.Second))
+	}
+	report.WriteString("\n| Result |")
+	for _, result := range results {
+		status := "PASS"
+		if result.Err != nil {
+			status = fmt.Sprintf("FAIL: %s", result.Err)
+		}
+		fmt.Fprintf(&report, " %s |", status)
+	}
+	report.WriteString("\n| Run ID |")
+	for _, result := range results {
+		fmt.Fprintf(&report, " %s |", result.RunID)
+	}
+	report.WriteString("\n")
+	return report.String()
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageClassReport(t *testing.T) {
+	results := []StorageClassResult{
+		{StorageClass: "nfs-csi", RunID: "run-1", Duration: 3 * time.Hour, Phases: map[string]time.Duration{
+			"sdg": 30 * time.Minute, "model-to-pvc": 20 * time.Minute, "training-phase-1": time.Hour,
+		}},
+		{StorageClass: "ocs-storagecluster-cephfs", RunID: "run-2", Duration: 2 * time.Hour, Phases: map[string]time.Duration{
+			"sdg": 31 * time.Minute, "model-to-pvc": 5 * time.Minute, "training-phase-1": time.Hour,
+		}},
+		// Failed runs are left out of the comparison
+		{StorageClass: "slow", RunID: "run-3", Phases: map[string]time.Duration{"sdg": 5 * time.Hour}, Err: errors.New("pipeline run failed with status: FAILED")},
+	}
+	require.Equal(t, []string{"model-to-pvc"}, IOBoundPhases(results, DefaultIOBoundSpread))
+	require.Empty(t, IOBoundPhases(results[:1], DefaultIOBoundSpread))
+
+	report := RenderStorageClassReport(results, DefaultIOBoundSpread)
+	require.Contains(t, report, "| Phase | nfs-csi | ocs-storagecluster-cephfs | slow |")
+	require.Contains(t, report, "| sdg | 30m0s | 31m0s | 5h0m0s |")
+	require.Contains(t, report, "| model-to-pvc (I/O-bound) | 20m0s | 5m0s | - |")
+	require.Contains(t, report, "| Result | PASS | PASS | FAIL: pipeline run failed with status: FAILED |")
+	require.NotContains(t, report, "| mt-bench |")
+}
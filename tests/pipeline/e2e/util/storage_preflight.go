@@ -0,0 +1,204 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// StoragePreflightConfig sizes the fio benchmark and holds the minimums the storage is expected to reach
+type StoragePreflightConfig struct {
+	// Size is the size of the file of every fio job, the test PVC is twice as large
+	Size             string        `mapstructure:"size"`
+	Runtime          time.Duration `mapstructure:"runtime"`
+	MinSeqWriteMiBps float64       `mapstructure:"min_seq_write_mibps"`
+	MinSeqReadMiBps  float64       `mapstructure:"min_seq_read_mibps"`
+	MinRandReadIOPS  float64       `mapstructure:"min_rand_read_iops"`
+	MinRandWriteIOPS float64       `mapstructure:"min_rand_write_iops"`
+}
+
+// LoadStoragePreflightConfig reads the storage preflight configuration from a YAML file
+func LoadStoragePreflightConfig(t *testing.T, path string) StoragePreflightConfig {
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig(), "Error loading storage preflight configuration")
+
+	var config StoragePreflightConfig
+	require.NoError(t, v.Unmarshal(&config), "Error parsing storage preflight configuration")
+	return config
+}
+
+// StorageBenchmark is the throughput and IOPS fio measured on a PVC
+type StorageBenchmark struct {
+	SeqWriteMiBps float64
+	SeqReadMiBps  float64
+	RandReadIOPS  float64
+	RandWriteIOPS float64
+}
+
+// fioArgs returns the fio command running the sequential write, sequential read, random write and random read jobs
+// one after the other in dir
+func fioArgs(dir string, config StoragePreflightConfig) []string {
+	args := []string{"fio", "--directory=" + dir, "--size=" + config.Size, fmt.Sprintf("--runtime=%d", int(config.Runtime.Seconds())),
+		"--time_based", "--direct=1", "--ioengine=libaio", "--group_reporting", "--output-format=json"}
+	jobs := []struct{ name, rw, bs, iodepth string }{
+		{"seq-write", "write", "1M", "8"},
+		{"seq-read", "read", "1M", "8"},
+		{"rand-write", "randwrite", "4k", "32"},
+		{"rand-read", "randread", "4k", "32"},
+	}
+	for _, job := range jobs {
+		args = append(args, "--name="+job.name, "--rw="+job.rw, "--bs="+job.bs, "--iodepth="+job.iodepth, "--stonewall")
+	}
+	return args
+}
+
+// ParseFioOutput reads the benchmark from the JSON output of the fio jobs, fio reports bandwidths in KiB/s
+func ParseFioOutput(output []byte) (StorageBenchmark, error) {
+	var benchmark StorageBenchmark
+	// fio may print warnings before the JSON document
+	if start := bytes.IndexByte(output, '{'); start > 0 {
+		output = output[start:]
+	}
+	var result struct {
+		Jobs []struct {
+			Name  string `json:"jobname"`
+			Read  fioStats
+			Write fioStats
+		} `json:"jobs"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return benchmark, fmt.Errorf("failed to parse fio output: %w", err)
+	}
+	found := map[string]bool{}
+	for _, job := range result.Jobs {
+		switch job.Name {
+		case "seq-write":
+			benchmark.SeqWriteMiBps = job.Write.Bandwidth / 1024
+		case "seq-read":
+			benchmark.SeqReadMiBps = job.Read.Bandwidth / 1024
+		case "rand-write":
+			benchmark.RandWriteIOPS = job.Write.IOPS
+		case "rand-read":
+			benchmark.RandReadIOPS = job.Read.IOPS
+		default:
+			continue
+		}
+		found[job.Name] = true
+	}
+	if len(found) != 4 {
+		return benchmark, fmt.Errorf("fio output misses jobs, found %d of 4", len(found))
+	}
+	return benchmark, nil
+}
+
+type fioStats struct {
+	Bandwidth float64 `json:"bw"`
+	IOPS      float64 `json:"iops"`
+}
+
+// Shortfalls returns the measures of the benchmark below the configured minimums, with their effect on the run
+func (b StorageBenchmark) Shortfalls(config StoragePreflightConfig) []string {
+	var shortfalls []string
+	if b.SeqWriteMiBps < config.MinSeqWriteMiBps {
+		shortfalls = append(shortfalls, fmt.Sprintf("sequential write of %.0f MiB/s is below the %.0f MiB/s minimum, checkpointing will be slow", b.SeqWriteMiBps, config.MinSeqWriteMiBps))
+	}
+	if b.SeqReadMiBps < config.MinSeqReadMiBps {
+		shortfalls = append(shortfalls, fmt.Sprintf("sequential read of %.0f MiB/s is below the %.0f MiB/s minimum, loading models and checkpoints will be slow", b.SeqReadMiBps, config.MinSeqReadMiBps))
+	}
+	if b.RandReadIOPS < config.MinRandReadIOPS {
+		shortfalls = append(shortfalls, fmt.Sprintf("random read of %.0f IOPS is below the %.0f IOPS minimum", b.RandReadIOPS, config.MinRandReadIOPS))
+	}
+	if b.RandWriteIOPS < config.MinRandWriteIOPS {
+		shortfalls = append(shortfalls, fmt.Sprintf("random write of %.0f IOPS is below the %.0f IOPS minimum", b.RandWriteIOPS, config.MinRandWriteIOPS))
+	}
+	return shortfalls
+}
+
+// RunStoragePreflight runs the fio benchmark in image on a test PVC of the storage class, as the pipeline creates its
+// PVCs, and returns the measures. The PVC and the pod are deleted afterwards.
+func RunStoragePreflight(t *testing.T, client kubernetes.Interface, namespace, image, storageClass string, config StoragePreflightConfig, timeout time.Duration) (StorageBenchmark, error) {
+	ctx := context.Background()
+	size := resource.MustParse(config.Size)
+	size.Add(size)
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: GenerateName("storage-preflight")},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+			Resources:   corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: size}},
+		},
+	}
+	if storageClass != "" {
+		pvc.Spec.StorageClassName = &storageClass
+	}
+	pvcs := client.CoreV1().PersistentVolumeClaims(namespace)
+	pvc, err := pvcs.Create(ctx, pvc, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create storage preflight PVC")
+	defer func() { _ = pvcs.Delete(context.Background(), pvc.Name, metav1.DeleteOptions{}) }()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: GenerateName("storage-preflight")},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{{
+				Name:         "fio",
+				Image:        image,
+				Command:      fioArgs("/data", config),
+				VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: "/data"}},
+			}},
+			Volumes: []corev1.Volume{{
+				Name:         "data",
+				VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvc.Name}},
+			}},
+		},
+	}
+	pods := client.CoreV1().Pods(namespace)
+	created, err := pods.Create(ctx, pod, metav1.CreateOptions{})
+	require.NoError(t, err, "Failed to create storage preflight pod")
+	defer func() { _ = pods.Delete(context.Background(), created.Name, metav1.DeleteOptions{}) }()
+
+	deadline := time.After(timeout)
+	tick := time.Tick(10 * time.Second)
+	for {
+		select {
+		case <-deadline:
+			return StorageBenchmark{}, fmt.Errorf("storage preflight pod %s did not complete within %s", created.Name, timeout)
+		case <-tick:
+			current, err := pods.Get(ctx, created.Name, metav1.GetOptions{})
+			require.NoError(t, err, "Failed to retrieve storage preflight pod")
+			if current.Status.Phase != corev1.PodSucceeded && current.Status.Phase != corev1.PodFailed {
+				continue
+			}
+
+			logs, err := pods.GetLogs(created.Name, &corev1.PodLogOptions{}).DoRaw(ctx)
+			require.NoError(t, err, "Failed to retrieve storage preflight logs")
+			if current.Status.Phase == corev1.PodFailed {
+				return StorageBenchmark{}, fmt.Errorf("fio failed on storage class %s, output:\n%s", storageClass, logs)
+			}
+			return ParseFioOutput(logs)
+		}
+	}
+}
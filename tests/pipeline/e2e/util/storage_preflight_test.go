@@ -0,0 +1,57 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const fioOutput = `fio: file hash not empty on exit
+{
+  "fio version" : "fio-3.35",
+  "jobs" : [
+    {"jobname" : "seq-write", "read" : {"bw" : 0, "iops" : 0.0}, "write" : {"bw" : 51200, "iops" : 50.0}},
+    {"jobname" : "seq-read", "read" : {"bw" : 409600, "iops" : 400.0}, "write" : {"bw" : 0, "iops" : 0.0}},
+    {"jobname" : "rand-write", "read" : {"bw" : 0, "iops" : 0.0}, "write" : {"bw" : 2400, "iops" : 600.5}},
+    {"jobname" : "rand-read", "read" : {"bw" : 8000, "iops" : 2000.0}, "write" : {"bw" : 0, "iops" : 0.0}}
+  ]
+}`
+
+func TestParseFioOutput(t *testing.T) {
+	benchmark, err := ParseFioOutput([]byte(fioOutput))
+	require.NoError(t, err)
+	require.Equal(t, StorageBenchmark{SeqWriteMiBps: 50, SeqReadMiBps: 400, RandReadIOPS: 2000, RandWriteIOPS: 600.5}, benchmark)
+
+	_, err = ParseFioOutput([]byte(`{"jobs": [{"jobname": "seq-write"}]}`))
+	require.ErrorContains(t, err, "found 1 of 4")
+	_, err = ParseFioOutput([]byte("fio: command not found"))
+	require.Error(t, err)
+}
+
+func TestStorageBenchmarkShortfalls(t *testing.T) {
+	config := LoadStoragePreflightConfig(t, "../resources/storage_preflight.yaml")
+	require.Equal(t, 30*time.Second, config.Runtime)
+
+	benchmark, err := ParseFioOutput([]byte(fioOutput))
+	require.NoError(t, err)
+	shortfalls := benchmark.Shortfalls(config)
+	require.Len(t, shortfalls, 1)
+	require.Contains(t, shortfalls[0], "sequential write of 50 MiB/s is below the 100 MiB/s minimum, checkpointing will be slow")
+
+	require.Empty(t, StorageBenchmark{SeqWriteMiBps: 500, SeqReadMiBps: 500, RandReadIOPS: 5000, RandWriteIOPS: 5000}.Shortfalls(config))
+	require.Contains(t, fioArgs("/data", config), "--runtime=30")
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// TaskPod is a pod executing a pipeline component, with the component function and its resolved input parameters
+type TaskPod struct {
+	Pod        corev1.Pod
+	Function   string
+	Parameters map[string]interface{}
+}
+
+type executorInput struct {
+	Inputs struct {
+		ParameterValues map[string]interface{} `json:"parameterValues"`
+	} `json:"inputs"`
+}
+
+// ParseTaskPod extracts the component function and input parameters from the launcher arguments of a pod
+func ParseTaskPod(pod corev1.Pod) (TaskPod, bool) {
+	task := TaskPod{Pod: pod}
+	found := false
+	for _, container := range pod.Spec.Containers {
+		args := append(append([]string{}, container.Command...), container.Args...)
+		for i := 0; i < len(args)-1; i++ {
+			switch args[i] {
+			case "--function_to_execute":
+				task.Function = args[i+1]
+			case "--executor_input":
+				var input executorInput
+				if json.Unmarshal([]byte(args[i+1]), &input) == nil && input.Inputs.ParameterValues != nil {
+					task.Parameters = input.Inputs.ParameterValues
+					found = true
+				}
+			}
+		}
+	}
+	return task, found && task.Function != ""
+}
+
+// GetRunTaskPods returns the pods of a pipeline run that executed a component function
+func GetRunTaskPods(t *testing.T, client kubernetes.Interface, namespace, runID string) []TaskPod {
+	var tasks []TaskPod
+	for _, pod := range GetRunPods(t, client, namespace, runID) {
+		if task, ok := ParseTaskPod(pod); ok {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// ListRunTaskPods returns the pods of a pipeline run that executed a component function, returning errors for callers
+// outside of the test goroutine
+func ListRunTaskPods(client kubernetes.Interface, namespace, runID string) ([]TaskPod, error) {
+	pods, err := client.CoreV1().Pods(namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", RunIDLabel, runID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pipeline run pods: %w", err)
+	}
+	var tasks []TaskPod
+	for _, pod := range pods.Items {
+		if task, ok := ParseTaskPod(pod); ok {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// CheckTaskParameters verifies that every pod running the given component function received the expected parameter values
+func CheckTaskParameters(tasks []TaskPod, function string, expected map[string]interface{}) error {
+	matched := false
+	for _, task := range tasks {
+		if task.Function != function {
+			continue
+		}
+		matched = true
+		for name, want := range expected {
+			got, ok := task.Parameters[name]
+			if !ok {
+				return fmt.Errorf("%s pod %s is missing parameter '%s'", function, task.Pod.Name, name)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				return fmt.Errorf("%s pod %s has parameter '%s' set to '%v', expected '%v'", function, task.Pod.Name, name, got, want)
+			}
+		}
+	}
+	if !matched {
+		return fmt.Errorf("no pod found for component function '%s'", function)
+	}
+	return nil
+}
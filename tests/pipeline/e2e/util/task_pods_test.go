@@ -0,0 +1,46 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCheckTaskParameters(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "final-eval"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:    "main",
+			Command: []string{"/kfp-launcher/launch", "--executor_input", `{"inputs":{"parameterValues":{"batch_size":"8","max_workers":"auto","merge_system_user_message":true,"few_shots":5}}}`},
+			Args:    []string{"sh", "-ec", "program", "--executor_input", "{{$}}", "--function_to_execute", "run_final_eval_op"},
+		}}},
+	}
+
+	task, ok := ParseTaskPod(pod)
+	require.True(t, ok)
+	require.Equal(t, "run_final_eval_op", task.Function)
+
+	tasks := []TaskPod{task}
+	require.NoError(t, CheckTaskParameters(tasks, "run_final_eval_op", map[string]interface{}{
+		"batch_size":                "8",
+		"merge_system_user_message": true,
+		"few_shots":                 5,
+	}))
+	require.Error(t, CheckTaskParameters(tasks, "run_final_eval_op", map[string]interface{}{"batch_size": "auto"}))
+	require.Error(t, CheckTaskParameters(tasks, "run_mt_bench_op", nil))
+}
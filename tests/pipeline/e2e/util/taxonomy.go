@@ -0,0 +1,242 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// TaxonomyLeaf is a leaf of a taxonomy, a directory holding a qna.yaml
+type TaxonomyLeaf struct {
+	// Path is the directory of the leaf relative to the taxonomy root, e.g. compositional_skills/writing/freeform/haiku
+	Path         string
+	Knowledge    bool
+	SeedExamples int
+}
+
+// SeedExampleRules configures the cross-check of the SDG output volume against the seed examples of the taxonomy
+type SeedExampleRules struct {
+	// LeafFiles names the node dataset file holding the samples of a skills and a knowledge leaf, {leaf} stands for
+	// the leaf path with "/" replaced by "_"
+	LeafFiles struct {
+		Skills    string `mapstructure:"skills"`
+		Knowledge string `mapstructure:"knowledge"`
+	} `mapstructure:"leaf_files"`
+	MaxRatioDeviation float64 `mapstructure:"max_ratio_deviation"`
+}
+
+type qnaFile struct {
+	SeedExamples []interface{}          `yaml:"seed_examples"`
+	Document     map[string]interface{} `yaml:"document"`
+}
+
+// LoadSeedExampleRules reads the seed example rules from a YAML file
+func LoadSeedExampleRules(t *testing.T, path string) SeedExampleRules {
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig(), "Error loading seed example rules")
+
+	var rules SeedExampleRules
+	require.NoError(t, v.Unmarshal(&rules), "Error parsing seed example rules")
+	return rules
+}
+
+// WalkTaxonomy returns the leaves of the taxonomy checked out at root, with the seed examples of their qna.yaml
+func WalkTaxonomy(root string) ([]TaxonomyLeaf, error) {
+	var leaves []TaxonomyLeaf
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), ".") && path != root {
+			return filepath.SkipDir
+		}
+		if entry.IsDir() || entry.Name() != "qna.yaml" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var qna qnaFile
+		if err := yaml.Unmarshal(data, &qna); err != nil {
+			return fmt.Errorf("invalid qna.yaml %s: %w", path, err)
+		}
+		leafPath, err := filepath.Rel(root, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		leaves = append(leaves, TaxonomyLeaf{
+			Path:         filepath.ToSlash(leafPath),
+			Knowledge:    qna.Document != nil,
+			SeedExamples: len(qna.SeedExamples),
+		})
+		return nil
+	})
+	return leaves, err
+}
+
+// LeafDatasetKeys returns the keys of the node dataset files of the sdg artifact of a run, by leaf path, among the
+// keys of the artifact store. SDG writes the samples of every leaf into node_datasets_<timestamp>/.
+func LeafDatasetKeys(keys []string, runID string, leaves []TaxonomyLeaf, rules SeedExampleRules) map[string]string {
+	names := map[string]string{}
+	for _, leaf := range leaves {
+		template := rules.LeafFiles.Skills
+		if leaf.Knowledge {
+			template = rules.LeafFiles.Knowledge
+		}
+		names[strings.ReplaceAll(template, "{leaf}", strings.ReplaceAll(leaf.Path, "/", "_"))] = leaf.Path
+	}
+
+	found := map[string]string{}
+	for _, key := range keys {
+		file, ok := sdgArtifactPath(key, runID)
+		if !ok || !strings.HasPrefix(file, "node_datasets_") {
+			continue
+		}
+		if leaf, ok := names[path.Base(file)]; ok {
+			found[leaf] = key
+		}
+	}
+	return found
+}
+
+// CheckSeedProportionality returns the leaves with seed examples for which no samples were generated, and the leaves
+// whose samples per seed example deviate by more than the given factor from the median of the leaves of the same
+// type, as SDG generates knowledge and skills samples at different rates
+func CheckSeedProportionality(leaves []TaxonomyLeaf, samples map[string]int, maxDeviation float64) []string {
+	var failures []string
+	ratios := map[bool]map[string]float64{false: {}, true: {}}
+	for _, leaf := range leaves {
+		if leaf.SeedExamples == 0 {
+			continue
+		}
+		if samples[leaf.Path] == 0 {
+			failures = append(failures, fmt.Sprintf("leaf %s with %d seed examples was skipped, no samples were generated", leaf.Path, leaf.SeedExamples))
+			continue
+		}
+		ratios[leaf.Knowledge][leaf.Path] = float64(samples[leaf.Path]) / float64(leaf.SeedExamples)
+	}
+	if maxDeviation <= 0 {
+		return failures
+	}
+
+	for knowledge, typeRatios := range ratios {
+		if len(typeRatios) == 0 {
+			continue
+		}
+		leafType := "skills"
+		if knowledge {
+			leafType = "knowledge"
+		}
+		median := medianRatio(typeRatios)
+		for leafPath, ratio := range typeRatios {
+			if ratio > median*maxDeviation || ratio < median/maxDeviation {
+				failures = append(failures, fmt.Sprintf("leaf %s generated %.1f samples per seed example, the median of the %s leaves is %.1f", leafPath, ratio, leafType, median))
+			}
+		}
+	}
+	sort.Strings(failures)
+	return failures
+}
+
+// LeafCoverage is the number of samples SDG generated for a taxonomy leaf
+type LeafCoverage struct {
+	Leaf TaxonomyLeaf
+	// Found tells whether the sdg artifact holds a node dataset file for the leaf
+	Found   bool
+	Samples int
+}
+
+// Dropped tells whether SDG generated no sample for a leaf with seed examples
+func (c LeafCoverage) Dropped() bool {
+	return c.Leaf.SeedExamples > 0 && c.Samples == 0
+}
+
+// SDGCoverage maps the leaves of the taxonomy to their samples, by leaf path, the dropped leaves first
+func SDGCoverage(leaves []TaxonomyLeaf, files map[string]string, samples map[string]int) []LeafCoverage {
+	coverage := make([]LeafCoverage, 0, len(leaves))
+	for _, leaf := range leaves {
+		_, found := files[leaf.Path]
+		coverage = append(coverage, LeafCoverage{Leaf: leaf, Found: found, Samples: samples[leaf.Path]})
+	}
+	sort.SliceStable(coverage, func(i, j int) bool {
+		if coverage[i].Dropped() != coverage[j].Dropped() {
+			return coverage[i].Dropped()
+		}
+		return coverage[i].Leaf.Path < coverage[j].Leaf.Path
+	})
+	return coverage
+}
+
+// RenderSDGCoverage renders the samples of every taxonomy leaf as markdown
+func RenderSDGCoverage(coverage []LeafCoverage) string {
+	dropped := 0
+	for _, leaf := range coverage {
+		if leaf.Dropped() {
+			dropped++
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("# SDG coverage\n\n")
+	fmt.Fprintf(&b, "%d taxonomy leaves, %d without samples.\n\n", len(coverage), dropped)
+	b.WriteString("| Leaf | Type | Seed examples | Samples | Samples per seed example | Status |\n|---|---|---|---|---|---|\n")
+	for _, leaf := range coverage {
+		leafType := "skills"
+		if leaf.Leaf.Knowledge {
+			leafType = "knowledge"
+		}
+		ratio := "-"
+		if leaf.Leaf.SeedExamples > 0 {
+			ratio = fmt.Sprintf("%.1f", float64(leaf.Samples)/float64(leaf.Leaf.SeedExamples))
+		}
+		status := "ok"
+		switch {
+		case leaf.Leaf.SeedExamples == 0:
+			status = "no seed examples"
+		case !leaf.Found:
+			status = "**dropped**, no node dataset"
+		case leaf.Dropped():
+			status = "**dropped**, no valid sample"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %d | %d | %s | %s |\n", leaf.Leaf.Path, leafType, leaf.Leaf.SeedExamples, leaf.Samples, ratio, status)
+	}
+	return b.String()
+}
+
+func medianRatio(ratios map[string]float64) float64 {
+	values := make([]float64, 0, len(ratios))
+	for _, ratio := range ratios {
+		values = append(values, ratio)
+	}
+	sort.Float64s(values)
+	middle := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[middle-1] + values[middle]) / 2
+	}
+	return values[middle]
+}
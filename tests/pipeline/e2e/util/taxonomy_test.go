@@ -0,0 +1,143 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalkTaxonomy(t *testing.T) {
+	root := t.TempDir()
+	writeQNA := func(dir, content string) {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, dir, "qna.yaml"), []byte(content), 0o644))
+	}
+	writeQNA("compositional_skills/writing/freeform/haiku", `
+version: 2
+task_description: Write haikus
+seed_examples:
+  - question: Write a haiku about the sea
+    answer: Waves
+  - question: Write a haiku about snow
+    answer: Flakes
+`)
+	writeQNA("knowledge/science/astronomy", `
+version: 3
+domain: astronomy
+seed_examples:
+  - context: Planets orbit stars
+    questions_and_answers:
+      - question: What do planets orbit?
+        answer: Stars
+document:
+  repo: https://github.com/example/docs
+  commit: abc
+  patterns: ["*.md"]
+`)
+	writeQNA(".git/hooks", "seed_examples: [{}]")
+
+	leaves, err := WalkTaxonomy(root)
+	require.NoError(t, err)
+	require.Equal(t, []TaxonomyLeaf{
+		{Path: "compositional_skills/writing/freeform/haiku", SeedExamples: 2},
+		{Path: "knowledge/science/astronomy", Knowledge: true, SeedExamples: 1},
+	}, leaves)
+}
+
+func TestLeafDatasetKeys(t *testing.T) {
+	var rules SeedExampleRules
+	rules.LeafFiles.Skills = "{leaf}.jsonl"
+	rules.LeafFiles.Knowledge = "{leaf}_p07.jsonl"
+	leaves := []TaxonomyLeaf{
+		{Path: "compositional_skills/writing/freeform/haiku", SeedExamples: 2},
+		{Path: "knowledge/science/astronomy", Knowledge: true, SeedExamples: 1},
+	}
+	root := "pipelines/ilab/run-1/sdg-to-artifact-op/7/sdg/node_datasets_2025-03-01T12_00_00/"
+	require.Equal(t, map[string]string{
+		"compositional_skills/writing/freeform/haiku": root + "compositional_skills_writing_freeform_haiku.jsonl",
+		"knowledge/science/astronomy":                 root + "knowledge_science_astronomy_p07.jsonl",
+	}, LeafDatasetKeys([]string{
+		root + "compositional_skills_writing_freeform_haiku.jsonl",
+		root + "knowledge_science_astronomy.jsonl",
+		root + "knowledge_science_astronomy_p07.jsonl",
+		root + "knowledge_science_astronomy_p10.jsonl",
+		"pipelines/ilab/run-1/sdg-to-artifact-op/7/sdg/knowledge_science_astronomy_p07.jsonl",
+	}, "run-1", leaves, rules))
+}
+
+func TestCheckSeedProportionality(t *testing.T) {
+	leaves := []TaxonomyLeaf{
+		{Path: "compositional_skills/writing/freeform/haiku", SeedExamples: 2},
+		{Path: "compositional_skills/writing/freeform/limerick", SeedExamples: 4},
+		{Path: "knowledge/science/astronomy", Knowledge: true, SeedExamples: 1},
+		{Path: "knowledge/science/geology", Knowledge: true, SeedExamples: 2},
+	}
+	// Knowledge leaves generate many more samples per seed example than skills leaves
+	samples := map[string]int{
+		"compositional_skills/writing/freeform/haiku":    60,
+		"compositional_skills/writing/freeform/limerick": 100,
+		"knowledge/science/astronomy":                    900,
+		"knowledge/science/geology":                      1500,
+	}
+	require.Empty(t, CheckSeedProportionality(leaves, samples, 5))
+
+	leaves = append(leaves,
+		TaxonomyLeaf{Path: "compositional_skills/extraction/tables", SeedExamples: 5},
+		TaxonomyLeaf{Path: "foundational_skills/reasoning", SeedExamples: 30},
+		TaxonomyLeaf{Path: "knowledge/science/biology", Knowledge: true, SeedExamples: 3})
+	samples["foundational_skills/reasoning"] = 30
+	samples["knowledge/science/biology"] = 90
+	require.Equal(t, []string{
+		"leaf compositional_skills/extraction/tables with 5 seed examples was skipped, no samples were generated",
+		"leaf foundational_skills/reasoning generated 1.0 samples per seed example, the median of the skills leaves is 25.0",
+		"leaf knowledge/science/biology generated 30.0 samples per seed example, the median of the knowledge leaves is 750.0",
+	}, CheckSeedProportionality(leaves, samples, 5))
+}
+
+func TestSDGCoverage(t *testing.T) {
+	leaves := []TaxonomyLeaf{
+		{Path: "compositional_skills/writing/freeform/haiku", SeedExamples: 2},
+		{Path: "compositional_skills/writing/freeform/limerick", SeedExamples: 4},
+		{Path: "knowledge/science/astronomy", Knowledge: true, SeedExamples: 1},
+		{Path: "knowledge/science/geology", Knowledge: true},
+	}
+	files := map[string]string{
+		"compositional_skills/writing/freeform/haiku":    "haiku.jsonl",
+		"compositional_skills/writing/freeform/limerick": "limerick.jsonl",
+	}
+	samples := map[string]int{"compositional_skills/writing/freeform/haiku": 60}
+
+	coverage := SDGCoverage(leaves, files, samples)
+	var order []string
+	for _, leaf := range coverage {
+		order = append(order, leaf.Leaf.Path)
+	}
+	require.Equal(t, []string{
+		"compositional_skills/writing/freeform/limerick",
+		"knowledge/science/astronomy",
+		"compositional_skills/writing/freeform/haiku",
+		"knowledge/science/geology",
+	}, order)
+
+	report := RenderSDGCoverage(coverage)
+	require.Contains(t, report, "4 taxonomy leaves, 2 without samples.")
+	require.Contains(t, report, "| compositional_skills/writing/freeform/limerick | skills | 4 | 0 | 0.0 | **dropped**, no valid sample |")
+	require.Contains(t, report, "| knowledge/science/astronomy | knowledge | 1 | 0 | 0.0 | **dropped**, no node dataset |")
+	require.Contains(t, report, "| compositional_skills/writing/freeform/haiku | skills | 2 | 60 | 30.0 | ok |")
+	require.Contains(t, report, "| knowledge/science/geology | knowledge | 0 | 0 | - | no seed examples |")
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/dag"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/watcher"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// TektonPipelineRunLabel is the label Tekton sets on the TaskRuns and pods of a PipelineRun
+	TektonPipelineRunLabel = "tekton.dev/pipelineRun"
+	// TektonPipelineTaskLabel is the label Tekton sets on the TaskRuns and pods of a pipeline task
+	TektonPipelineTaskLabel = "tekton.dev/pipelineTask"
+)
+
+var (
+	TektonPipelineRunGVR = schema.GroupVersionResource{
+		Group:    "tekton.dev",
+		Version:  "v1",
+		Resource: "pipelineruns",
+	}
+	TektonTaskRunGVR = schema.GroupVersionResource{
+		Group:    "tekton.dev",
+		Version:  "v1",
+		Resource: "taskruns",
+	}
+)
+
+// TektonBackend runs the workflow as a Tekton PipelineRun of OpenShift Pipelines
+type TektonBackend struct{}
+
+func (TektonBackend) CRD() string                           { return "pipelineruns.tekton.dev" }
+func (TektonBackend) Kind() schema.GroupVersionKind         { return watcher.TektonPipelineRunGVK }
+func (TektonBackend) Resource() schema.GroupVersionResource { return TektonPipelineRunGVR }
+
+func (TektonBackend) Succeeded(run *unstructured.Unstructured) (bool, error) {
+	return watcher.TektonPipelineRunSucceeded(run)
+}
+
+func (TektonBackend) PodSelector(name string) string {
+	return fmt.Sprintf("%s=%s", TektonPipelineRunLabel, name)
+}
+
+// NewRun builds a PipelineRun of the workflow with an inline pipeline spec, each task running after the tasks
+// producing its inputs. The PipelineRun carries its name as run ID label and the labels of the run, which Tekton
+// propagates to the TaskRuns and pods, so the checks selecting the pods of a run by run ID apply to it. The data PVC
+// carries them as well.
+func (TektonBackend) NewRun(workflow Workflow, config WorkflowRunConfig) (*unstructured.Unstructured, error) {
+	names, err := workflowParams(workflow, config)
+	if err != nil {
+		return nil, err
+	}
+	graph, err := workflow.Graph()
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []interface{}
+	for _, task := range workflow.Tasks {
+		step := workflowTaskContainer(task, config, func(param string) string { return fmt.Sprintf("$(params.%s)", param) },
+			"$(workspaces.data.path)", "$(workspaces.model.path)")
+		step["name"] = task.Name
+		step["script"] = task.Script
+		if resources, ok := step["resources"]; ok {
+			delete(step, "resources")
+			step["computeResources"] = resources
+		}
+
+		var taskParams, specParams []interface{}
+		for _, name := range task.Params {
+			taskParams = append(taskParams, map[string]interface{}{"name": name, "value": fmt.Sprintf("$(params.%s)", name)})
+			specParams = append(specParams, map[string]interface{}{"name": name, "type": "string"})
+		}
+
+		pipelineTask := map[string]interface{}{
+			"name": task.Name,
+			"workspaces": []interface{}{
+				map[string]interface{}{"name": "data", "workspace": "data"},
+				map[string]interface{}{"name": "model", "workspace": "model"},
+			},
+			"taskSpec": map[string]interface{}{
+				"metadata": map[string]interface{}{"labels": map[string]interface{}{WorkflowTaskLabel: task.Name}},
+				"params":   specParams,
+				"workspaces": []interface{}{
+					map[string]interface{}{"name": "data"},
+					map[string]interface{}{"name": "model", "readOnly": true},
+				},
+				"steps": []interface{}{step},
+			},
+		}
+		if len(taskParams) > 0 {
+			pipelineTask["params"] = taskParams
+		}
+		if upstream := graph.Upstream(task.Name); len(upstream) > 0 {
+			pipelineTask["runAfter"] = unstructuredStrings(upstream)
+		}
+		tasks = append(tasks, pipelineTask)
+	}
+
+	var pipelineParams, runParams []interface{}
+	for _, name := range names {
+		pipelineParams = append(pipelineParams, map[string]interface{}{"name": name, "type": "string"})
+		runParams = append(runParams, map[string]interface{}{"name": name, "value": fmt.Sprint(config.Params[name])})
+	}
+
+	data := map[string]interface{}{"name": "data", "volumeClaimTemplate": map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": unstructuredLabels(config.RunLabels())},
+		"spec":     workflowClaimSpec(workflow, config),
+	}}
+	if config.DataPVC != "" {
+		data = map[string]interface{}{"name": "data", "persistentVolumeClaim": map[string]interface{}{"claimName": config.DataPVC}}
+	}
+	spec := map[string]interface{}{
+		"pipelineSpec": map[string]interface{}{
+			"params": pipelineParams,
+			"workspaces": []interface{}{
+				map[string]interface{}{"name": "data"},
+				map[string]interface{}{"name": "model"},
+			},
+			"tasks": tasks,
+		},
+		"params": runParams,
+		"workspaces": []interface{}{
+			data,
+			map[string]interface{}{"name": "model", "persistentVolumeClaim": map[string]interface{}{"claimName": config.ModelPVC}},
+		},
+	}
+	if config.Timeout > 0 {
+		spec["timeouts"] = map[string]interface{}{"pipeline": config.Timeout.String()}
+	}
+	if !config.DNS.IsZero() {
+		podTemplate, err := config.DNS.Unstructured()
+		if err != nil {
+			return nil, err
+		}
+		spec["taskRunTemplate"] = map[string]interface{}{"podTemplate": podTemplate}
+	}
+
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "tekton.dev/v1",
+		"kind":       "PipelineRun",
+		"metadata": map[string]interface{}{
+			"name":   config.Name,
+			"labels": unstructuredLabels(config.RunLabels()),
+		},
+		"spec": spec,
+	}}, nil
+}
+
+// TaskStates lists the TaskRuns of a PipelineRun and returns their state
+func (TektonBackend) TaskStates(client dynamic.Interface, namespace, name string, workflow Workflow) ([]WorkflowTaskState, error) {
+	taskRuns, err := client.Resource(TektonTaskRunGVR).Namespace(namespace).List(context.Background(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", TektonPipelineRunLabel, name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the TaskRuns of PipelineRun %s: %w", name, err)
+	}
+	return TektonTaskRunStates(workflow, taskRuns.Items), nil
+}
+
+// TektonTaskRunStates returns the state of the TaskRuns of the workflow tasks, skipping the TaskRuns of other tasks
+func TektonTaskRunStates(workflow Workflow, taskRuns []unstructured.Unstructured) []WorkflowTaskState {
+	var states []WorkflowTaskState
+	for _, taskRun := range taskRuns {
+		task := taskRun.GetLabels()[TektonPipelineTaskLabel]
+		phase, ok := workflow.taskPhase(task)
+		if !ok {
+			continue
+		}
+		state := WorkflowTaskState{Task: task, Phase: phase, State: dag.Running}
+		conditions, _, _ := unstructured.NestedSlice(taskRun.Object, "status", "conditions")
+		for _, c := range conditions {
+			if condition, ok := c.(map[string]interface{}); ok && condition["type"] == "Succeeded" {
+				switch condition["status"] {
+				case "True":
+					state.State = dag.Succeeded
+				case "False":
+					state.State = dag.Failed
+				}
+			}
+		}
+		states = append(states, state)
+	}
+	return states
+}
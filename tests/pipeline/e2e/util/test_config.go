@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// TestConfigDirEnv names the directory a Secret holding the test inputs is mounted at, when the suite runs in a pod
+const TestConfigDirEnv = "TEST_CONFIG_DIR"
+
+// LoadTestConfigDir reads a Secret volume into a map of environment variables, one per key. The data links and
+// timestamped directories (..data, ..2025_01_01...) the kubelet maintains next to the keys are skipped, and the
+// trailing newline editors leave in files is trimmed.
+func LoadTestConfigDir(dir string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the test config %s: %w", dir, err)
+	}
+	values := map[string]string{}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), "..") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the test config %s: %w", dir, err)
+		}
+		if info.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the test config %s: %w", dir, err)
+		}
+		values[entry.Name()] = strings.TrimRight(string(data), "\r\n")
+	}
+	return values, nil
+}
+
+// ValidateTestConfig returns the places where the test config breaks its schema, the keys unknown to the suite
+// included when the schema disallows additional properties
+func ValidateTestConfig(schema *JSONSchema, values map[string]string) []string {
+	document := map[string]interface{}{}
+	for key, value := range values {
+		document[key] = value
+	}
+	return schema.Validate(document)
+}
+
+// ApplyTestConfig exports the test config as environment variables and returns the keys it set. Variables already
+// set in the environment win, so a single input can be overridden on the pod without editing the Secret.
+func ApplyTestConfig(values map[string]string) ([]string, error) {
+	var applied []string
+	for key, value := range values {
+		if _, ok := os.LookupEnv(key); ok {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return nil, fmt.Errorf("failed to set %s: %w", key, err)
+		}
+		applied = append(applied, key)
+	}
+	sort.Strings(applied)
+	return applied, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTestConfigDir(t *testing.T) {
+	// Lay the directory out as the kubelet does for a Secret volume
+	dir := t.TempDir()
+	data := filepath.Join(dir, "..2025_01_01_00_00_00.000000000")
+	require.NoError(t, os.Mkdir(data, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(data, "PIPELINE_SERVER_URL"), []byte("https://ds-pipeline\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(data, "SOAK_DURATION"), []byte("2h"), 0o644))
+	require.NoError(t, os.Symlink(filepath.Base(data), filepath.Join(dir, "..data")))
+	require.NoError(t, os.Symlink(filepath.Join("..data", "PIPELINE_SERVER_URL"), filepath.Join(dir, "PIPELINE_SERVER_URL")))
+	require.NoError(t, os.Symlink(filepath.Join("..data", "SOAK_DURATION"), filepath.Join(dir, "SOAK_DURATION")))
+
+	values, err := LoadTestConfigDir(dir)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"PIPELINE_SERVER_URL": "https://ds-pipeline", "SOAK_DURATION": "2h"}, values)
+
+	_, err = LoadTestConfigDir(filepath.Join(dir, "missing"))
+	require.Error(t, err)
+}
+
+func TestValidateTestConfig(t *testing.T) {
+	schema, err := LoadJSONSchema("../resources/test_config.schema.json")
+	require.NoError(t, err)
+
+	require.Empty(t, ValidateTestConfig(schema, map[string]string{
+		"PIPELINE_SERVER_URL":   "https://ds-pipeline",
+		"BEARER_TOKEN":          "token",
+		"PIPELINE_DISPLAY_NAME": "instructlab",
+		"PIPELINE_NAMESPACE":    "ilab",
+		"ENABLE_SOAK_TEST":      "true",
+		"SOAK_DURATION":         "1h30m",
+		"SOAK_RUNS":             "3",
+		"ETA_OVERRUN_FACTOR":    "1.5",
+	}))
+	require.ElementsMatch(t, []string{
+		"$: BEARER_TOKEN is required",
+		"$: PIPELINE_DISPLAY_NAME is required",
+		"$: PIPELINE_NAMESPACE is required",
+		"$.ENABLE_SOAK_TEST: yes is not one of [true false]",
+		"$.SOAK_DURATION: 'two hours' does not match ^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$",
+		"$: unknown property UNKNOWN_KEY",
+	}, ValidateTestConfig(schema, map[string]string{
+		"PIPELINE_SERVER_URL": "https://ds-pipeline",
+		"ENABLE_SOAK_TEST":    "yes",
+		"SOAK_DURATION":       "two hours",
+		"UNKNOWN_KEY":         "value",
+	}))
+}
+
+func TestApplyTestConfig(t *testing.T) {
+	t.Setenv("PIPELINE_NAMESPACE", "from-env")
+	t.Setenv("PIPELINE_DISPLAY_NAME", "")
+	require.NoError(t, os.Unsetenv("PIPELINE_DISPLAY_NAME"))
+
+	applied, err := ApplyTestConfig(map[string]string{"PIPELINE_NAMESPACE": "from-secret", "PIPELINE_DISPLAY_NAME": "instructlab"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"PIPELINE_DISPLAY_NAME"}, applied)
+	require.Equal(t, "from-env", os.Getenv("PIPELINE_NAMESPACE"))
+	require.Equal(t, "instructlab", os.Getenv("PIPELINE_DISPLAY_NAME"))
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+// TimeBudgetConfiguration is a configuration of the run the time budget mode may choose
+type TimeBudgetConfiguration struct {
+	Name string `mapstructure:"name"`
+	// Params replace the pipeline parameters of the run
+	Params map[string]interface{} `mapstructure:"params"`
+	// SkipPhases are the phases the params leave out of the run, not estimated
+	SkipPhases []string `mapstructure:"skip_phases"`
+}
+
+// TimeBudgetConfig describes how the time budget mode downscopes a run
+type TimeBudgetConfig struct {
+	// Margin is the fraction added to the estimates for the variance of the phase durations
+	Margin float64 `mapstructure:"margin"`
+	// Scaling lists, by phase, the numeric parameters the duration of the phase is proportional to
+	Scaling map[string][]string `mapstructure:"scaling"`
+	// Configurations are the configurations to choose from, in order of preference
+	Configurations []TimeBudgetConfiguration `mapstructure:"configurations"`
+}
+
+// LoadTimeBudget reads the configurations of the time budget mode from a YAML file
+func LoadTimeBudget(t *testing.T, path string) TimeBudgetConfig {
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig(), "Error loading time budget configurations")
+
+	var config TimeBudgetConfig
+	require.NoError(t, v.Unmarshal(&config), "Error parsing time budget configurations")
+	require.NotEmpty(t, config.Configurations, "No time budget configurations in %s", path)
+	return config
+}
+
+// TimeBudgetEstimate is the expected duration of a run with a configuration
+type TimeBudgetEstimate struct {
+	Configuration TimeBudgetConfiguration
+	// Phases are the expected durations of the phases, by phase
+	Phases map[string]time.Duration
+	// Total is the sum of the phases with the margin
+	Total time.Duration
+	// Err explains why the configuration could not be estimated
+	Err error
+}
+
+// Fits is satisfied when the configuration is expected to complete within the budget
+func (e TimeBudgetEstimate) Fits(budget time.Duration) bool {
+	return e.Err == nil && e.Total <= budget
+}
+
+// NumericParams returns the numeric pipeline parameters, recorded in the run history to scale the estimates
+func NumericParams(params map[string]interface{}) map[string]float64 {
+	numbers := map[string]float64{}
+	for name, value := range params {
+		if _, isBool := value.(bool); isBool {
+			continue
+		}
+		if number, ok := paramNumber(value); ok {
+			numbers[name] = number
+		}
+	}
+	return numbers
+}
+
+// Estimate returns the expected duration of a run with every configuration, in order. The parameters of a run are
+// base replaced by the params of the configuration. The configurations with params that are not in definitions, when
+// given, are not estimated.
+func (c TimeBudgetConfig) Estimate(history []RunHistoryEntry, base map[string]interface{}, definitions map[string]PipelineParameterSpec) []TimeBudgetEstimate {
+	estimates := make([]TimeBudgetEstimate, 0, len(c.Configurations))
+	for _, configuration := range c.Configurations {
+		estimate := TimeBudgetEstimate{Configuration: configuration}
+		if definitions != nil {
+			if missing := MissingPipelineInputs(definitions, configuration.Params); len(missing) > 0 {
+				sort.Strings(missing)
+				estimate.Err = fmt.Errorf("the pipeline does not expose %v yet", missing)
+				estimates = append(estimates, estimate)
+				continue
+			}
+		}
+		params := map[string]interface{}{}
+		for name, value := range base {
+			params[name] = value
+		}
+		for name, value := range configuration.Params {
+			params[name] = value
+		}
+		estimate.Phases, estimate.Err = c.EstimatePhases(history, params, base, configuration.SkipPhases)
+		var total time.Duration
+		for _, duration := range estimate.Phases {
+			total += duration
+		}
+		estimate.Total = time.Duration(float64(total) * (1 + c.Margin))
+		estimates = append(estimates, estimate)
+	}
+	return estimates
+}
+
+// EstimatePhases returns the expected duration of every phase of the run history but the skipped ones for a run with
+// params. A phase with scaling parameters takes the median of its durations per unit of the product of the
+// parameters, times the product for params, the entries without recorded parameters having run with base. The other
+// phases take their median.
+func (c TimeBudgetConfig) EstimatePhases(history []RunHistoryEntry, params, base map[string]interface{}, skip []string) (map[string]time.Duration, error) {
+	if len(history) == 0 {
+		return nil, fmt.Errorf("no run history to estimate from")
+	}
+	skipped := map[string]bool{}
+	for _, phase := range skip {
+		skipped[phase] = true
+	}
+	baseNumbers := NumericParams(base)
+	numbers := NumericParams(params)
+
+	medians := PhaseMedians(history)
+	names := make([]string, 0, len(medians))
+	for phase := range medians {
+		names = append(names, phase)
+	}
+	sort.Strings(names)
+	phases := map[string]time.Duration{}
+	for _, phase := range names {
+		median := medians[phase]
+		if skipped[phase] {
+			continue
+		}
+		scaling := c.Scaling[phase]
+		if len(scaling) == 0 {
+			phases[phase] = median
+			continue
+		}
+		units, err := scalingProduct(numbers, scaling)
+		if err != nil {
+			return nil, fmt.Errorf("phase %s: %w", phase, err)
+		}
+		var perUnit []time.Duration
+		for _, entry := range history {
+			duration, ok := entry.Phases[phase]
+			if !ok {
+				continue
+			}
+			recorded := map[string]float64{}
+			for name, value := range baseNumbers {
+				recorded[name] = value
+			}
+			for name, value := range entry.Params {
+				recorded[name] = value
+			}
+			if entryUnits, err := scalingProduct(recorded, scaling); err == nil && entryUnits > 0 {
+				perUnit = append(perUnit, time.Duration(float64(duration)/entryUnits))
+			}
+		}
+		if len(perUnit) == 0 {
+			return nil, fmt.Errorf("phase %s: no run history with %v set", phase, scaling)
+		}
+		phases[phase] = time.Duration(float64(medianDuration(perUnit)) * units)
+	}
+	return phases, nil
+}
+
+func scalingProduct(numbers map[string]float64, names []string) (float64, error) {
+	product := 1.0
+	for _, name := range names {
+		value, ok := numbers[name]
+		if !ok {
+			return 0, fmt.Errorf("parameter %s is not a number", name)
+		}
+		product *= value
+	}
+	return product, nil
+}
+
+// FirstFitting returns the first estimate fitting the budget
+func FirstFitting(estimates []TimeBudgetEstimate, budget time.Duration) (TimeBudgetEstimate, bool) {
+	for _, estimate := range estimates {
+		if estimate.Fits(budget) {
+			return estimate, true
+		}
+	}
+	return TimeBudgetEstimate{}, false
+}
+
+// RenderTimeBudget renders the estimates of the configurations against the budget as a Markdown report
+func RenderTimeBudget(budget time.Duration, estimates []TimeBudgetEstimate) string {
+	var report strings.Builder
+	fmt.Fprintf(&report, "# Time budget of %s\n\n", budget)
+	report.WriteString("| Configuration | Params | Skipped phases | Estimate | Fits |\n")
+	report.WriteString("|---|---|---|---|---|\n")
+	for _, estimate := range estimates {
+		names := make([]string, 0, len(estimate.Configuration.Params))
+		for name := range estimate.Configuration.Params {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		params := make([]string, 0, len(names))
+		for _, name := range names {
+			params = append(params, fmt.Sprintf("%s=%v", name, estimate.Configuration.Params[name]))
+		}
+		result, fits := formatDuration(estimate.Total), "no"
+		if estimate.Err != nil {
+			result = estimate.Err.Error()
+		} else if estimate.Fits(budget) {
+			fits = "yes"
+		}
+		fmt.Fprintf(&report, "| %s | %s | %s | %s | %s |\n", estimate.Configuration.Name, strings.Join(params, ", "),
+			strings.Join(estimate.Configuration.SkipPhases, ", "), result, fits)
+	}
+	return report.String()
+}
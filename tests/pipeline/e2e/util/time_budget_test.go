@@ -0,0 +1,92 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeBudgetEstimate(t *testing.T) {
+	base := map[string]interface{}{"sdg_scale_factor": 30, "train_num_epochs_phase_1": 1, "train_num_epochs_phase_2": 1, "sdg_pipeline": "simple"}
+	history := []RunHistoryEntry{
+		// Recorded before the parameters were, run with base
+		{RunID: "a", Phases: map[string]time.Duration{"sdg": 30 * time.Minute, "training-phase-1": time.Hour, "training-phase-2": time.Hour, "mt-bench": 20 * time.Minute}},
+		{RunID: "b", Phases: map[string]time.Duration{"sdg": 10 * time.Minute, "training-phase-1": 20 * time.Minute, "training-phase-2": 20 * time.Minute, "mt-bench": 40 * time.Minute},
+			Params: map[string]float64{"sdg_scale_factor": 10, "train_num_epochs_phase_1": 1, "train_num_epochs_phase_2": 1}},
+	}
+	config := TimeBudgetConfig{
+		Margin: 0.5,
+		Scaling: map[string][]string{
+			"sdg":              {"sdg_scale_factor"},
+			"training-phase-1": {"sdg_scale_factor", "train_num_epochs_phase_1"},
+			"training-phase-2": {"sdg_scale_factor", "train_num_epochs_phase_2"},
+		},
+		Configurations: []TimeBudgetConfiguration{
+			{Name: "full", Params: map[string]interface{}{"train_num_epochs_phase_1": 2, "train_num_epochs_phase_2": 2}},
+			{Name: "small", Params: map[string]interface{}{"sdg_scale_factor": 10}},
+			{Name: "no-eval", Params: map[string]interface{}{"sdg_scale_factor": 5, "skip_mt_bench": true}, SkipPhases: []string{"mt-bench"}},
+		},
+	}
+	definitions := map[string]PipelineParameterSpec{"sdg_scale_factor": {}, "train_num_epochs_phase_1": {}, "train_num_epochs_phase_2": {}}
+
+	estimates := config.Estimate(history, base, definitions)
+	require.Len(t, estimates, 3)
+	require.NoError(t, estimates[0].Err)
+	require.Equal(t, map[string]time.Duration{
+		"sdg": 30 * time.Minute, "training-phase-1": 2 * time.Hour, "training-phase-2": 2 * time.Hour, "mt-bench": 30 * time.Minute,
+	}, estimates[0].Phases)
+	require.Equal(t, 450*time.Minute, estimates[0].Total)
+	require.Equal(t, 120*time.Minute, estimates[1].Total)
+	require.EqualError(t, estimates[2].Err, "the pipeline does not expose [skip_mt_bench] yet")
+
+	chosen, ok := FirstFitting(estimates, 3*time.Hour)
+	require.True(t, ok)
+	require.Equal(t, "small", chosen.Configuration.Name)
+	_, ok = FirstFitting(estimates, time.Hour)
+	require.False(t, ok)
+
+	// Without the definitions every configuration is estimated, the skipped phases left out
+	estimates = config.Estimate(history, base, nil)
+	require.Equal(t, map[string]time.Duration{"sdg": 5 * time.Minute, "training-phase-1": 10 * time.Minute, "training-phase-2": 10 * time.Minute}, estimates[2].Phases)
+	require.Equal(t, 37*time.Minute+30*time.Second, estimates[2].Total)
+
+	estimates = config.Estimate(nil, base, nil)
+	require.EqualError(t, estimates[0].Err, "no run history to estimate from")
+	_, err := config.EstimatePhases(history, map[string]interface{}{"sdg_scale_factor": "auto"}, base, nil)
+	require.EqualError(t, err, "phase sdg: parameter sdg_scale_factor is not a number", "the phases are estimated in a stable order")
+
+	report := RenderTimeBudget(time.Hour, config.Estimate(history, base, definitions))
+	require.Contains(t, report, "| small | sdg_scale_factor=10 |  | 2h0m0s | no |")
+	require.Contains(t, report, "| no-eval | sdg_scale_factor=5, skip_mt_bench=true | mt-bench | the pipeline does not expose [skip_mt_bench] yet | no |")
+}
+
+func TestLoadTimeBudget(t *testing.T) {
+	config := LoadTimeBudget(t, "../resources/time_budget.yaml")
+	definitions, err := LoadPipelineInputDefinitions("../../../../pipeline.yaml")
+	require.NoError(t, err)
+	for _, configuration := range config.Configurations {
+		require.Empty(t, MissingPipelineInputs(definitions, configuration.Params), "configuration %s", configuration.Name)
+	}
+	for phase, names := range config.Scaling {
+		require.Contains(t, PipelinePhases, phase)
+		params := map[string]interface{}{}
+		for _, name := range names {
+			params[name] = nil
+		}
+		require.Empty(t, MissingPipelineInputs(definitions, params), "scaling of %s", phase)
+	}
+}
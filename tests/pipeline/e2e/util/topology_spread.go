@@ -0,0 +1,202 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// TopologySpreadParameter is the pipeline input, to be exposed, holding the topology spread constraints of the
+	// training pods
+	TopologySpreadParameter = "train_topology_spread_constraints"
+	// ZoneLabel is the well-known node label holding the zone of the node
+	ZoneLabel = "topology.kubernetes.io/zone"
+	// HostnameLabel is the well-known node label holding the hostname of the node
+	HostnameLabel = "kubernetes.io/hostname"
+)
+
+// TopologySpread is a topology spread constraint of the training pods, scoped by the launcher to the pods of their
+// PyTorchJob
+type TopologySpread struct {
+	TopologyKey string `mapstructure:"topology_key"`
+	MaxSkew     int    `mapstructure:"max_skew"`
+	// WhenUnsatisfiable is DoNotSchedule or ScheduleAnyway, only the placement of DoNotSchedule spreads is asserted
+	WhenUnsatisfiable string `mapstructure:"when_unsatisfiable"`
+}
+
+// TopologySpreadConfig is the spread of the training workers by variant, e.g. across zones or hosts
+type TopologySpreadConfig struct {
+	NumWorkers int                         `mapstructure:"num_workers"`
+	Variants   map[string][]TopologySpread `mapstructure:"variants"`
+	// MaxCrossZoneSlowdown bounds the training duration of the zone variant relative to the host variant, 0 only
+	// reports it
+	MaxCrossZoneSlowdown float64 `mapstructure:"max_cross_zone_slowdown"`
+}
+
+// LoadTopologySpread reads the topology spread variants from a YAML file
+func LoadTopologySpread(t *testing.T, path string) TopologySpreadConfig {
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig(), "Error loading topology spread")
+
+	var config TopologySpreadConfig
+	require.NoError(t, v.Unmarshal(&config), "Error parsing topology spread")
+	return config
+}
+
+// TopologySpreadParameterValue returns the value of the topology spread pipeline input, the constraints in the
+// Kubernetes format
+func TopologySpreadParameterValue(spreads []TopologySpread) []interface{} {
+	value := make([]interface{}, 0, len(spreads))
+	for _, spread := range spreads {
+		value = append(value, map[string]interface{}{
+			"maxSkew":           spread.MaxSkew,
+			"topologyKey":       spread.TopologyKey,
+			"whenUnsatisfiable": spread.WhenUnsatisfiable,
+		})
+	}
+	return value
+}
+
+// PodsPerDomain counts the scheduled pods in each domain of a topology key. Like the scheduler, the domains are the
+// values of the key on the nodes matching the node selector, so domains without pods are counted as 0.
+func PodsPerDomain(pods []corev1.Pod, nodes []corev1.Node, key string, nodeSelector map[string]string) map[string]int {
+	domains := map[string]int{}
+	nodeDomain := map[string]string{}
+	for _, node := range nodes {
+		domain, ok := node.Labels[key]
+		if !ok {
+			continue
+		}
+		nodeDomain[node.Name] = domain
+		if _, ok := domains[domain]; !ok && matchesNodeSelector(node, nodeSelector) {
+			domains[domain] = 0
+		}
+	}
+	for _, pod := range pods {
+		if domain, ok := nodeDomain[pod.Spec.NodeName]; ok {
+			domains[domain]++
+		}
+	}
+	return domains
+}
+
+// CheckTopologySpread reports the training pods which do not carry the topology spread constraints, and the
+// PyTorchJobs whose pods are spread with a higher skew than a DoNotSchedule constraint allows
+func CheckTopologySpread(pods []corev1.Pod, nodes []corev1.Node, spreads []TopologySpread) []string {
+	var problems []string
+	byJob := map[string][]corev1.Pod{}
+	for _, pod := range pods {
+		byJob[pod.Labels[TrainingJobNameLabel]] = append(byJob[pod.Labels[TrainingJobNameLabel]], pod)
+		for _, spread := range spreads {
+			if !hasTopologySpread(pod, spread) {
+				problems = append(problems, fmt.Sprintf("pod %s has no %s topology spread constraint on %s with maxSkew %d", pod.Name, spread.WhenUnsatisfiable, spread.TopologyKey, spread.MaxSkew))
+			}
+		}
+	}
+	jobs := make([]string, 0, len(byJob))
+	for job := range byJob {
+		jobs = append(jobs, job)
+	}
+	sort.Strings(jobs)
+	for _, job := range jobs {
+		for _, spread := range spreads {
+			if spread.WhenUnsatisfiable != string(corev1.DoNotSchedule) {
+				continue
+			}
+			domains := PodsPerDomain(byJob[job], nodes, spread.TopologyKey, byJob[job][0].Spec.NodeSelector)
+			if skew := domainSkew(domains); skew > spread.MaxSkew {
+				problems = append(problems, fmt.Sprintf("the pods of %s are spread on %s with skew %d, above maxSkew %d: %v", job, spread.TopologyKey, skew, spread.MaxSkew, domains))
+			}
+		}
+	}
+	return problems
+}
+
+// TrainingJobDurations returns how long each PyTorchJob of the pods trained, from the start of its first pod to the
+// end of its last container. The jobs not finished yet are left out.
+func TrainingJobDurations(pods []corev1.Pod) map[string]time.Duration {
+	started, finished := map[string]time.Time{}, map[string]time.Time{}
+	unfinished := map[string]bool{}
+	for _, pod := range pods {
+		job := pod.Labels[TrainingJobNameLabel]
+		if pod.Status.StartTime == nil || len(pod.Status.ContainerStatuses) == 0 {
+			unfinished[job] = true
+			continue
+		}
+		if start, ok := started[job]; !ok || pod.Status.StartTime.Time.Before(start) {
+			started[job] = pod.Status.StartTime.Time
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			terminated := status.State.Terminated
+			if terminated == nil {
+				unfinished[job] = true
+				continue
+			}
+			if terminated.FinishedAt.After(finished[job]) {
+				finished[job] = terminated.FinishedAt.Time
+			}
+		}
+	}
+	durations := map[string]time.Duration{}
+	for job, start := range started {
+		if !unfinished[job] {
+			durations[job] = finished[job].Sub(start)
+		}
+	}
+	return durations
+}
+
+func hasTopologySpread(pod corev1.Pod, spread TopologySpread) bool {
+	for _, constraint := range pod.Spec.TopologySpreadConstraints {
+		if constraint.TopologyKey == spread.TopologyKey && int(constraint.MaxSkew) == spread.MaxSkew &&
+			string(constraint.WhenUnsatisfiable) == spread.WhenUnsatisfiable {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesNodeSelector(node corev1.Node, selector map[string]string) bool {
+	for key, value := range selector {
+		if node.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func domainSkew(domains map[string]int) int {
+	if len(domains) == 0 {
+		return 0
+	}
+	lowest, highest := -1, 0
+	for _, count := range domains {
+		if lowest < 0 || count < lowest {
+			lowest = count
+		}
+		if count > highest {
+			highest = count
+		}
+	}
+	return highest - lowest
+}
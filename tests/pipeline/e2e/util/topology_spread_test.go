@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCheckTopologySpread(t *testing.T) {
+	node := func(name, zone string, gpu bool) corev1.Node {
+		labels := map[string]string{HostnameLabel: name, ZoneLabel: zone}
+		if gpu {
+			labels["nvidia.com/gpu.present"] = "true"
+		}
+		return corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	nodes := []corev1.Node{node("a1", "a", true), node("a2", "a", true), node("b1", "b", true), node("c1", "c", false)}
+	zone := TopologySpread{TopologyKey: ZoneLabel, MaxSkew: 1, WhenUnsatisfiable: "DoNotSchedule"}
+	pod := func(name, job, node string, spreads ...TopologySpread) corev1.Pod {
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{TrainingJobNameLabel: job}},
+			Spec:       corev1.PodSpec{NodeName: node, NodeSelector: map[string]string{"nvidia.com/gpu.present": "true"}},
+		}
+		for _, spread := range spreads {
+			pod.Spec.TopologySpreadConstraints = append(pod.Spec.TopologySpreadConstraints, corev1.TopologySpreadConstraint{
+				TopologyKey: spread.TopologyKey, MaxSkew: int32(spread.MaxSkew), WhenUnsatisfiable: corev1.UnsatisfiableConstraintAction(spread.WhenUnsatisfiable),
+			})
+		}
+		return pod
+	}
+
+	// Zone c has no node matching the selector, it is not a domain of the spread
+	require.Equal(t, map[string]int{"a": 1, "b": 1}, PodsPerDomain([]corev1.Pod{pod("m", "job", "a1"), pod("w", "job", "b1")}, nodes, ZoneLabel, map[string]string{"nvidia.com/gpu.present": "true"}))
+	require.Empty(t, CheckTopologySpread([]corev1.Pod{
+		pod("phase-1-master-0", "phase-1", "a1", zone), pod("phase-1-worker-0", "phase-1", "b1", zone),
+		pod("phase-2-master-0", "phase-2", "a2", zone), pod("phase-2-worker-0", "phase-2", "b1", zone),
+	}, nodes, []TopologySpread{zone}))
+	require.Equal(t, []string{
+		"pod phase-1-worker-0 has no DoNotSchedule topology spread constraint on topology.kubernetes.io/zone with maxSkew 1",
+		"the pods of phase-1 are spread on topology.kubernetes.io/zone with skew 2, above maxSkew 1: map[a:2 b:0]",
+	}, CheckTopologySpread([]corev1.Pod{pod("phase-1-master-0", "phase-1", "a1", zone), pod("phase-1-worker-0", "phase-1", "a2")}, nodes, []TopologySpread{zone}))
+
+	// A ScheduleAnyway spread is only a preference, its placement is not asserted
+	host := TopologySpread{TopologyKey: HostnameLabel, MaxSkew: 1, WhenUnsatisfiable: "ScheduleAnyway"}
+	require.Empty(t, CheckTopologySpread([]corev1.Pod{pod("master-0", "job", "a1", host), pod("worker-0", "job", "a1", host)}, nodes, []TopologySpread{host}))
+	require.Equal(t, []interface{}{map[string]interface{}{"maxSkew": 1, "topologyKey": ZoneLabel, "whenUnsatisfiable": "DoNotSchedule"}}, TopologySpreadParameterValue([]TopologySpread{zone}))
+}
+
+func TestTrainingJobDurations(t *testing.T) {
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	pod := func(job string, started, finished time.Duration) corev1.Pod {
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{TrainingJobNameLabel: job}}}
+		startTime := metav1.NewTime(start.Add(started))
+		pod.Status.StartTime = &startTime
+		state := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+		if finished > 0 {
+			state = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{FinishedAt: metav1.NewTime(start.Add(finished))}}
+		}
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "pytorch", State: state}}
+		return pod
+	}
+	require.Equal(t, map[string]time.Duration{"phase-1": 50 * time.Minute}, TrainingJobDurations([]corev1.Pod{
+		pod("phase-1", time.Minute, 45*time.Minute), pod("phase-1", 0, 50*time.Minute),
+		pod("phase-2", time.Hour, 0), pod("phase-2", time.Hour, 2*time.Hour),
+	}))
+}
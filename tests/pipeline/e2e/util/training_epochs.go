@@ -0,0 +1,126 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// TrainingReplicaTypeLabel is set by the Training Operator on every PyTorchJob pod to its replica type
+const TrainingReplicaTypeLabel = "training.kubeflow.org/replica-type"
+
+// PhaseEpochs is the number of epochs of a training phase and its early-stopping criteria
+type PhaseEpochs struct {
+	Epochs int `mapstructure:"epochs"`
+	// EarlyStoppingPatience is the number of epochs without improvement before the phase stops, 0 disables it
+	EarlyStoppingPatience int     `mapstructure:"early_stopping_patience"`
+	EarlyStoppingMinDelta float64 `mapstructure:"early_stopping_min_delta"`
+}
+
+// TrainingEpochsConfig holds the epochs of both training phases and how to find the epochs in the training logs
+type TrainingEpochsConfig struct {
+	Phase1       PhaseEpochs `mapstructure:"phase_1"`
+	Phase2       PhaseEpochs `mapstructure:"phase_2"`
+	EpochPattern string      `mapstructure:"epoch_pattern"`
+}
+
+// LoadTrainingEpochsConfig reads the training epochs configuration from a YAML file
+func LoadTrainingEpochsConfig(t *testing.T, path string) TrainingEpochsConfig {
+	v := viper.New()
+	v.SetConfigFile(path)
+	require.NoError(t, v.ReadInConfig(), "Error loading training epochs configuration")
+
+	var config TrainingEpochsConfig
+	require.NoError(t, v.Unmarshal(&config), "Error parsing training epochs configuration")
+	_, err := regexp.Compile(config.EpochPattern)
+	require.NoError(t, err, "Invalid epoch_pattern in the training epochs configuration")
+	return config
+}
+
+// Phase returns the epochs of training phase 1 or 2
+func (c TrainingEpochsConfig) Phase(phase int) PhaseEpochs {
+	if phase == 2 {
+		return c.Phase2
+	}
+	return c.Phase1
+}
+
+// ParameterOverrides returns the pipeline parameters setting the epochs of both phases, and their early-stopping
+// criteria when it is enabled
+func (c TrainingEpochsConfig) ParameterOverrides() map[string]interface{} {
+	overrides := map[string]interface{}{}
+	for _, phase := range []int{1, 2} {
+		epochs := c.Phase(phase)
+		overrides[fmt.Sprintf("train_num_epochs_phase_%d", phase)] = epochs.Epochs
+		if epochs.EarlyStoppingPatience > 0 {
+			overrides[fmt.Sprintf("train_early_stopping_patience_phase_%d", phase)] = epochs.EarlyStoppingPatience
+			overrides[fmt.Sprintf("train_early_stopping_min_delta_phase_%d", phase)] = epochs.EarlyStoppingMinDelta
+		}
+	}
+	return overrides
+}
+
+// CountEpochs returns the number of distinct epochs the pattern matches in the logs, the first group of the pattern
+// holding the epoch number
+func CountEpochs(logs []byte, pattern *regexp.Regexp) int {
+	epochs := map[int]bool{}
+	for _, match := range pattern.FindAllSubmatch(logs, -1) {
+		if len(match) < 2 {
+			continue
+		}
+		if epoch, err := strconv.Atoi(string(match[1])); err == nil {
+			epochs[epoch] = true
+		}
+	}
+	return len(epochs)
+}
+
+// CheckEpochsRun verifies a training phase ran the requested epochs, or fewer but at least one when early stopping
+// is enabled, so an argument silently dropped or defaulted by the workflow is caught
+func CheckEpochsRun(phase int, requested PhaseEpochs, run int) error {
+	if requested.EarlyStoppingPatience > 0 {
+		if run < 1 || run > requested.Epochs {
+			return fmt.Errorf("training phase %d ran %d epochs, expected between 1 and %d with early stopping", phase, run, requested.Epochs)
+		}
+		return nil
+	}
+	if run != requested.Epochs {
+		return fmt.Errorf("training phase %d ran %d epochs, expected %d", phase, run, requested.Epochs)
+	}
+	return nil
+}
+
+// TrainingEpochsRun counts the epochs the master pod of a PyTorchJob logged
+func TrainingEpochsRun(client kubernetes.Interface, trainingPods []corev1.Pod, job string, pattern *regexp.Regexp) (int, error) {
+	for _, pod := range trainingPods {
+		if pod.Labels[TrainingJobNameLabel] != job || pod.Labels[TrainingReplicaTypeLabel] != "master" {
+			continue
+		}
+		logs, err := client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{}).DoRaw(context.Background())
+		if err != nil {
+			return 0, fmt.Errorf("failed to retrieve logs of pod %s: %w", pod.Name, err)
+		}
+		return CountEpochs(logs, pattern), nil
+	}
+	return 0, fmt.Errorf("no master pod found for PyTorchJob %s", job)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCountEpochs(t *testing.T) {
+	pattern := regexp.MustCompile(`Epoch (\d+)`)
+	logs := []byte("Epoch 0:  50%|#####     | 1/2\nEpoch 0: 100%|##########| 2/2\nEpoch 1: 100%|##########| 2/2\nsaving checkpoint\n")
+	require.Equal(t, 2, CountEpochs(logs, pattern))
+	require.Equal(t, 0, CountEpochs([]byte("loading model\n"), pattern))
+}
+
+func TestCheckEpochsRun(t *testing.T) {
+	require.NoError(t, CheckEpochsRun(1, PhaseEpochs{Epochs: 2}, 2))
+	require.ErrorContains(t, CheckEpochsRun(2, PhaseEpochs{Epochs: 3}, 10), "training phase 2 ran 10 epochs, expected 3")
+
+	earlyStopping := PhaseEpochs{Epochs: 5, EarlyStoppingPatience: 1}
+	require.NoError(t, CheckEpochsRun(1, earlyStopping, 3))
+	require.Error(t, CheckEpochsRun(1, earlyStopping, 0))
+	require.Error(t, CheckEpochsRun(1, earlyStopping, 6))
+}
+
+func TestTrainingEpochsParameterOverrides(t *testing.T) {
+	config := TrainingEpochsConfig{
+		Phase1: PhaseEpochs{Epochs: 2},
+		Phase2: PhaseEpochs{Epochs: 4, EarlyStoppingPatience: 2, EarlyStoppingMinDelta: 0.01},
+	}
+	require.Equal(t, map[string]interface{}{
+		"train_num_epochs_phase_1":               2,
+		"train_num_epochs_phase_2":               4,
+		"train_early_stopping_patience_phase_2":  2,
+		"train_early_stopping_min_delta_phase_2": 0.01,
+	}, config.ParameterOverrides())
+}
+
+func TestTrainingEpochsRun(t *testing.T) {
+	worker := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "worker", Namespace: "ns", Labels: map[string]string{
+		TrainingJobNameLabel: "train-phase-1-run-abc", TrainingReplicaTypeLabel: "worker",
+	}}}
+	_, err := TrainingEpochsRun(fake.NewSimpleClientset(), []corev1.Pod{worker}, "train-phase-1-run-abc", regexp.MustCompile(`Epoch (\d+)`))
+	require.ErrorContains(t, err, "no master pod found for PyTorchJob train-phase-1-run-abc")
+}
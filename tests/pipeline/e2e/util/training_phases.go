@@ -0,0 +1,98 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// CheckOnlyTrainingPhases verifies only the given training phases ran: a pytorch_job_launcher_op task with its phase_num
+// and a PyTorchJob named after the phase and the workflow of the run, and none for the other phase
+func CheckOnlyTrainingPhases(tasks []TaskPod, runPods, trainingPods []corev1.Pod, phases []int) []string {
+	var problems []string
+	expected := map[string]bool{}
+	for _, phase := range phases {
+		expected[fmt.Sprint(phase)] = true
+	}
+
+	launched := map[string]bool{}
+	for _, task := range tasks {
+		if task.Function == "pytorch_job_launcher_op" {
+			launched[fmt.Sprint(task.Parameters["phase_num"])] = true
+		}
+	}
+	if missing, unexpected := compareSets(expected, launched); len(missing)+len(unexpected) > 0 {
+		problems = append(problems, fmt.Sprintf("training phases %v were not launched, phases %v were launched unexpectedly", missing, unexpected))
+	}
+
+	names := TrainingJobNames(runPods)
+	if len(names) != 2 {
+		return append(problems, "the workflow of the run was not found, the PyTorchJob names cannot be checked")
+	}
+	expectedJobs := map[string]bool{}
+	for _, phase := range phases {
+		if phase >= 1 && phase <= len(names) {
+			expectedJobs[names[phase-1]] = true
+		}
+	}
+	jobs := map[string]bool{}
+	for _, pod := range trainingPods {
+		jobs[pod.Labels[TrainingJobNameLabel]] = true
+	}
+	if missing, unexpected := compareSets(expectedJobs, jobs); len(missing)+len(unexpected) > 0 {
+		problems = append(problems, fmt.Sprintf("PyTorchJobs %v did not run, PyTorchJobs %v ran unexpectedly", missing, unexpected))
+	}
+	return problems
+}
+
+// CheckLauncherInput verifies a parameter of the launcher of a training phase is set to the value, e.g. the
+// checkpoint phase 2 starts from
+func CheckLauncherInput(tasks []TaskPod, phase int, value string) error {
+	for _, task := range tasks {
+		if task.Function != "pytorch_job_launcher_op" || fmt.Sprint(task.Parameters["phase_num"]) != fmt.Sprint(phase) {
+			continue
+		}
+		var inputs []string
+		for name, got := range task.Parameters {
+			if fmt.Sprint(got) == value {
+				return nil
+			}
+			inputs = append(inputs, fmt.Sprintf("%s=%v", name, got))
+		}
+		sort.Strings(inputs)
+		return fmt.Errorf("no input of the phase %d launcher %s is set to '%s', inputs: %s", phase, task.Pod.Name, value, strings.Join(inputs, ", "))
+	}
+	return fmt.Errorf("no launcher found for training phase %d", phase)
+}
+
+// compareSets returns the sorted keys of expected missing from actual, and of actual not expected
+func compareSets(expected, actual map[string]bool) (missing, unexpected []string) {
+	for key := range expected {
+		if !actual[key] {
+			missing = append(missing, key)
+		}
+	}
+	for key := range actual {
+		if !expected[key] {
+			unexpected = append(unexpected, key)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(unexpected)
+	return missing, unexpected
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCheckOnlyTrainingPhases(t *testing.T) {
+	launcher := func(phase float64, checkpoint string) TaskPod {
+		return TaskPod{
+			Pod:        corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "launcher"}},
+			Function:   "pytorch_job_launcher_op",
+			Parameters: map[string]interface{}{"phase_num": phase, "name_suffix": "run-abc-sdg", "input_checkpoint": checkpoint},
+		}
+	}
+	runPods := []corev1.Pod{{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{WorkflowLabel: "run-abc"}}}}
+	trainingPod := func(job string) corev1.Pod {
+		return corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{TrainingJobNameLabel: job}}}
+	}
+
+	tasks := []TaskPod{launcher(2, "/checkpoints/phase_1")}
+	require.Empty(t, CheckOnlyTrainingPhases(tasks, runPods, []corev1.Pod{trainingPod("train-phase-2-run-abc")}, []int{2}))
+	require.NoError(t, CheckLauncherInput(tasks, 2, "/checkpoints/phase_1"))
+	require.ErrorContains(t, CheckLauncherInput(tasks, 2, "/other"), "input_checkpoint=/checkpoints/phase_1")
+	require.ErrorContains(t, CheckLauncherInput(tasks, 1, "/other"), "no launcher found for training phase 1")
+
+	problems := CheckOnlyTrainingPhases([]TaskPod{launcher(1, ""), launcher(2, "")}, runPods,
+		[]corev1.Pod{trainingPod("train-phase-1-run-abc"), trainingPod("train-phase-2-run-abc")}, []int{1})
+	require.Equal(t, []string{
+		"training phases [] were not launched, phases [2] were launched unexpectedly",
+		"PyTorchJobs [] did not run, PyTorchJobs [train-phase-2-run-abc] ran unexpectedly",
+	}, problems)
+
+	require.Contains(t, CheckOnlyTrainingPhases(tasks, nil, nil, []int{2}), "the workflow of the run was not found, the PyTorchJob names cannot be checked")
+}
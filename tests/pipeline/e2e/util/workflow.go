@@ -0,0 +1,367 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/dag"
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// OrchestratorKFP runs a scenario as a run of the compiled pipeline on the pipeline server
+	OrchestratorKFP = "kfp"
+	// OrchestratorTekton runs a scenario as a Tekton PipelineRun of workflow_tasks.yaml
+	OrchestratorTekton = "tekton"
+	// OrchestratorArgo runs a scenario as an Argo Workflow of workflow_tasks.yaml
+	OrchestratorArgo = "argo"
+	// OrchestratorPod runs a scenario as standalone pods of workflow_tasks.yaml, without a workflow engine
+	OrchestratorPod = "pod"
+)
+
+// WorkflowModelInput is the input artifact of the workflow holding the base model, the name of a PVC
+const WorkflowModelInput = "base-model"
+
+// WorkflowTaskLabel is the label the backends set on the pod of a workflow task, holding the name of the task
+const WorkflowTaskLabel = "ilab.opendatahub.io/workflow-task"
+
+// WorkflowBackends are the workflow engines scenarios may run on besides the pipeline server, by orchestrator name
+var WorkflowBackends = map[string]WorkflowBackend{
+	OrchestratorTekton: TektonBackend{},
+	OrchestratorArgo:   ArgoBackend{},
+}
+
+// WorkflowBackend runs the tasks of workflow_tasks.yaml as a custom resource of a workflow engine
+type WorkflowBackend interface {
+	// CRD is the name of the CustomResourceDefinition of the runs
+	CRD() string
+	// Kind is the kind of the runs
+	Kind() schema.GroupVersionKind
+	// Resource is the resource of the runs
+	Resource() schema.GroupVersionResource
+	// NewRun builds a run of the workflow
+	NewRun(workflow Workflow, config WorkflowRunConfig) (*unstructured.Unstructured, error)
+	// TaskStates returns the state of the tasks of a run, skipping the tasks which did not start
+	TaskStates(client dynamic.Interface, namespace, name string, workflow Workflow) ([]WorkflowTaskState, error)
+	// Succeeded is satisfied by a successful run and fails on a failed run
+	Succeeded(run *unstructured.Unstructured) (bool, error)
+	// PodSelector is the label selector of the pods of a run
+	PodSelector(name string) string
+}
+
+// Workflow is the workflow of the pipeline as tasks of a workflow engine, see resources/workflow_tasks.yaml
+type Workflow struct {
+	WorkspaceSize string         `yaml:"workspace_size"`
+	Tasks         []WorkflowTask `yaml:"tasks"`
+}
+
+// WorkflowTask is a task of the workflow, run after the previous task
+type WorkflowTask struct {
+	Name      string   `yaml:"name"`
+	Phase     string   `yaml:"phase"`
+	Image     string   `yaml:"image"`
+	Secret    string   `yaml:"secret"`
+	GPUsParam string   `yaml:"gpus_param"`
+	Params    []string `yaml:"params"`
+	Inputs    []string `yaml:"inputs"`
+	Outputs   []string `yaml:"outputs"`
+	Script    string   `yaml:"script"`
+}
+
+// WorkflowRunConfig holds the settings of a run of the workflow
+type WorkflowRunConfig struct {
+	// Name is the name of the run and the run ID its pods are labeled with
+	Name   string
+	Images ImageCombination
+	// Params are the pipeline parameters, the tasks receive those they list
+	Params   map[string]interface{}
+	ModelPVC string
+	// DataPVC holds the outputs of an earlier run the tasks resume from, a PVC is created for the run when empty
+	DataPVC      string
+	StorageClass string
+	GPUResource  string
+	Timeout      time.Duration
+	// Labels are set on the run and every resource it creates, besides the run ID label
+	Labels map[string]string
+	// DNS is the name resolution of the task pods
+	DNS PodDNS
+}
+
+// RunLabels returns the labels of the run and of the resources it creates: the labels of the config and the run ID
+func (c WorkflowRunConfig) RunLabels() map[string]string {
+	labels := map[string]string{}
+	for key, value := range c.Labels {
+		labels[key] = value
+	}
+	labels[RunIDLabel] = c.Name
+	return labels
+}
+
+// WorkflowTaskState is the state of a task in a run of the workflow
+type WorkflowTaskState struct {
+	Task  string
+	Phase string
+	State dag.State
+}
+
+// LoadWorkflow reads the workflow tasks, rejecting unknown fields, phases and images and dependency cycles
+func LoadWorkflow(path string) (Workflow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Workflow{}, err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var workflow Workflow
+	if err := decoder.Decode(&workflow); err != nil {
+		return Workflow{}, fmt.Errorf("failed to parse workflow %s: %w", path, err)
+	}
+	if len(workflow.Tasks) == 0 {
+		return Workflow{}, fmt.Errorf("workflow %s has no tasks", path)
+	}
+	names := map[string]bool{}
+	for _, task := range workflow.Tasks {
+		if task.Name == "" || names[task.Name] {
+			return Workflow{}, fmt.Errorf("workflow %s: task names must be unique and not empty, got '%s'", path, task.Name)
+		}
+		names[task.Name] = true
+		if pipelinePhaseIndex(task.Phase) < 0 {
+			return Workflow{}, fmt.Errorf("workflow %s: task %s has unknown phase '%s'", path, task.Name, task.Phase)
+		}
+		if task.Image != "sdg" && task.Image != "training" {
+			return Workflow{}, fmt.Errorf("workflow %s: task %s must use the sdg or training image, got '%s'", path, task.Name, task.Image)
+		}
+	}
+	if _, err := workflow.Graph(); err != nil {
+		return Workflow{}, fmt.Errorf("workflow %s: %w", path, err)
+	}
+	return workflow, nil
+}
+
+// Graph returns the DAG of the tasks, which depend on the tasks producing their inputs
+func (w Workflow) Graph() (*dag.Graph, error) {
+	var phases []dag.Phase
+	for _, task := range w.Tasks {
+		phases = append(phases, dag.Phase{Name: task.Name, Inputs: task.Inputs, Outputs: task.Outputs})
+	}
+	return dag.New(phases)
+}
+
+// Subset returns the workflow of the tasks of a subgraph of the workflow graph, in execution order
+func (w Workflow) Subset(graph *dag.Graph) Workflow {
+	subset := Workflow{WorkspaceSize: w.WorkspaceSize}
+	for _, name := range graph.Names() {
+		for _, task := range w.Tasks {
+			if task.Name == name {
+				subset.Tasks = append(subset.Tasks, task)
+			}
+		}
+	}
+	return subset
+}
+
+// Phases returns the phases of the tasks in the order they are defined
+func (w Workflow) Phases() []string {
+	var phases []string
+	for _, task := range w.Tasks {
+		phases = append(phases, task.Phase)
+	}
+	return phases
+}
+
+// taskPhase returns the phase of the named task, reporting false for tasks outside the workflow
+func (w Workflow) taskPhase(name string) (string, bool) {
+	for _, task := range w.Tasks {
+		if task.Name == name {
+			return task.Phase, true
+		}
+	}
+	return "", false
+}
+
+// WorkflowRunPhase returns the latest phase reached by the tasks of a run, as CurrentRunPhase does for the task pods
+// of a pipeline run
+func WorkflowRunPhase(states []WorkflowTaskState) RunPhase {
+	latest := -1
+	for _, state := range states {
+		if i := pipelinePhaseIndex(state.Phase); i > latest {
+			latest = i
+		}
+	}
+	if latest < 0 {
+		return RunPhase{Phase: "pending"}
+	}
+	return RunPhase{Phase: PipelinePhases[latest], Percent: latest * 100 / len(PipelinePhases)}
+}
+
+// CheckWorkflowPhases returns the phases without a succeeded task, as CheckScenarioPhases does for task pods
+func CheckWorkflowPhases(states []WorkflowTaskState, phases []string) []string {
+	executed := map[string]bool{}
+	for _, state := range states {
+		if state.State == dag.Succeeded {
+			executed[state.Phase] = true
+		}
+	}
+	var missing []string
+	for _, phase := range phases {
+		if !executed[phase] {
+			missing = append(missing, fmt.Sprintf("phase %s did not execute", phase))
+		}
+	}
+	return missing
+}
+
+// annotateWorkflowRun annotates a run and its active pods with its phase, as AnnotateRunPhase does for a pipeline run
+func annotateWorkflowRun(client kubernetes.Interface, dynamicClient dynamic.Interface, backend WorkflowBackend, namespace, name string, phase RunPhase) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]string{
+			PhaseAnnotation:    phase.Phase,
+			ProgressAnnotation: strconv.Itoa(phase.Percent),
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := dynamicClient.Resource(backend.Resource()).Namespace(namespace).Patch(context.Background(), name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to annotate %s %s: %w", backend.Kind().Kind, name, err)
+	}
+	return annotateActivePods(client, namespace, backend.PodSelector(name), patch)
+}
+
+// workflowParams returns the names of the parameters the tasks receive, sorted, and fails on parameters without value
+// and on invalid settings, before anything is rendered
+func workflowParams(workflow Workflow, config WorkflowRunConfig) ([]string, error) {
+	if problems := ValidateWorkflowRunConfig(config); len(problems) > 0 {
+		return nil, fmt.Errorf("invalid run %s: %s", config.Name, strings.Join(problems, "; "))
+	}
+	params := config.Params
+	used := map[string]bool{}
+	for _, task := range workflow.Tasks {
+		for _, name := range task.Params {
+			if _, ok := params[name]; !ok {
+				return nil, fmt.Errorf("task %s: no value for parameter '%s'", task.Name, name)
+			}
+			used[name] = true
+		}
+		if task.GPUsParam != "" {
+			if _, ok := params[task.GPUsParam]; !ok {
+				return nil, fmt.Errorf("task %s: no value for GPU parameter '%s'", task.Name, task.GPUsParam)
+			}
+		}
+	}
+	var names []string
+	for name := range used {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// workflowTaskContainer returns the fields of the container of a task shared by the backends: the image, the
+// environment and the GPU resources. value returns the reference to a parameter in the syntax of the backend, and
+// dataDir and modelDir are the mount paths of the workspaces.
+func workflowTaskContainer(task WorkflowTask, config WorkflowRunConfig, value func(param string) string, dataDir, modelDir string) map[string]interface{} {
+	image := config.Images.SDGImage
+	if task.Image == "training" {
+		image = config.Images.TrainingImage
+	}
+
+	env := []interface{}{
+		map[string]interface{}{"name": "DATA_DIR", "value": dataDir},
+		map[string]interface{}{"name": "MODEL_DIR", "value": modelDir},
+	}
+	for _, name := range task.Params {
+		env = append(env, map[string]interface{}{"name": strings.ToUpper(name), "value": value(name)})
+	}
+	if task.Secret != "" {
+		for _, variable := range [][2]string{{"API_TOKEN", "api_token"}, {"MODEL_NAME", "model_name"}, {"ENDPOINT", "endpoint"}} {
+			env = append(env, map[string]interface{}{
+				"name": variable[0],
+				"valueFrom": map[string]interface{}{"secretKeyRef": map[string]interface{}{
+					"name": task.Secret,
+					"key":  variable[1],
+				}},
+			})
+		}
+	}
+
+	container := map[string]interface{}{"image": image, "env": env}
+	if task.GPUsParam != "" {
+		gpuResource := config.GPUResource
+		if gpuResource == "" {
+			gpuResource = DefaultGPUResource
+		}
+		quantity := fmt.Sprint(config.Params[task.GPUsParam])
+		container["resources"] = map[string]interface{}{
+			"requests": map[string]interface{}{gpuResource: quantity},
+			"limits":   map[string]interface{}{gpuResource: quantity},
+		}
+	}
+	return container
+}
+
+// workflowClaimSpec returns the spec of the PVC of the data shared by the tasks of a run
+func workflowClaimSpec(workflow Workflow, config WorkflowRunConfig) map[string]interface{} {
+	spec := map[string]interface{}{
+		"accessModes": []interface{}{"ReadWriteOnce"},
+		"resources":   map[string]interface{}{"requests": map[string]interface{}{"storage": workflow.WorkspaceSize}},
+	}
+	if config.StorageClass != "" {
+		spec["storageClassName"] = config.StorageClass
+	}
+	return spec
+}
+
+// unstructuredLabels returns labels as the map of an unstructured object
+func unstructuredLabels(labels map[string]string) map[string]interface{} {
+	values := map[string]interface{}{}
+	for key, value := range labels {
+		values[key] = value
+	}
+	return values
+}
+
+// unstructuredStrings returns strings as the list of an unstructured object
+func unstructuredStrings(values []string) []interface{} {
+	list := make([]interface{}, len(values))
+	for i, value := range values {
+		list[i] = value
+	}
+	return list
+}
+
+func pipelinePhaseIndex(phase string) int {
+	for i, p := range PipelinePhases {
+		if p == phase {
+			return i
+		}
+	}
+	return -1
+}
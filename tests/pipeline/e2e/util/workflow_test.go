@@ -0,0 +1,215 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/dag"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func testWorkflowRunConfig() WorkflowRunConfig {
+	return WorkflowRunConfig{
+		Name:   "ilab-x1",
+		Images: ImageCombination{SDGImage: "sdg:1", TrainingImage: "training:1"},
+		Params: map[string]interface{}{
+			"sdg_repo_url":                       "https://github.com/instructlab/taxonomy.git",
+			"sdg_repo_branch":                    "",
+			"sdg_pipeline":                       "simple",
+			"sdg_scale_factor":                   30,
+			"train_gpu_per_worker":               2,
+			"train_num_epochs_phase_1":           1,
+			"train_effective_batch_size_phase_1": 3840,
+			"train_learning_rate_phase_1":        0.1,
+			"train_max_batch_len":                20000,
+			"train_seed":                         42,
+			"mt_bench_max_workers":               "auto",
+		},
+		ModelPVC:     "granite",
+		StorageClass: "nfs-csi",
+		Timeout:      8 * time.Hour,
+	}
+}
+
+func testTaskRun(pipelineRun, task, status string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "tekton.dev/v1",
+		"kind":       "TaskRun",
+		"metadata": map[string]interface{}{
+			"name":      pipelineRun + "-" + task,
+			"namespace": "ilab",
+			"labels":    map[string]interface{}{TektonPipelineRunLabel: pipelineRun, TektonPipelineTaskLabel: task},
+		},
+		"status": map[string]interface{}{"conditions": []interface{}{
+			map[string]interface{}{"type": "Succeeded", "status": status},
+		}},
+	}}
+}
+
+func TestLoadWorkflow(t *testing.T) {
+	workflow, err := LoadWorkflow("../resources/workflow_tasks.yaml")
+	require.NoError(t, err)
+	require.Equal(t, []string{"sdg", "training-phase-1", "mt-bench"}, workflow.Phases())
+
+	path := filepath.Join(t.TempDir(), "workflow_tasks.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("tasks:\n  - name: sdg\n    phase: generate\n    image: sdg\n"), 0o600))
+	_, err = LoadWorkflow(path)
+	require.ErrorContains(t, err, "task sdg has unknown phase 'generate'")
+
+	graph, err := workflow.Graph()
+	require.NoError(t, err)
+	require.Equal(t, []string{"sdg"}, graph.Upstream("train"))
+	require.Equal(t, []string{"base-model"}, graph.Inputs())
+	resume, err := graph.Range("train", "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"training-phase-1", "mt-bench"}, workflow.Subset(resume).Phases())
+
+	require.NoError(t, os.WriteFile(path, []byte(`
+tasks:
+  - {name: sdg, phase: sdg, image: sdg, inputs: [checkpoints], outputs: [sdg-data]}
+  - {name: train, phase: training-phase-1, image: training, inputs: [sdg-data], outputs: [checkpoints]}
+`), 0o600))
+	_, err = LoadWorkflow(path)
+	require.ErrorContains(t, err, "phases [sdg train] depend on each other")
+}
+
+func TestTektonNewRun(t *testing.T) {
+	workflow, err := LoadWorkflow("../resources/workflow_tasks.yaml")
+	require.NoError(t, err)
+	config := testWorkflowRunConfig()
+	config.Labels = map[string]string{"team": "ilab-on-ocp"}
+	run, err := TektonBackend{}.NewRun(workflow, config)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{RunIDLabel: "ilab-x1", "team": "ilab-on-ocp"}, run.GetLabels())
+	workspaces, _, _ := unstructured.NestedSlice(run.Object, "spec", "workspaces")
+	claimLabels, _, _ := unstructured.NestedStringMap(workspaces[0].(map[string]interface{}), "volumeClaimTemplate", "metadata", "labels")
+	require.Equal(t, run.GetLabels(), claimLabels)
+
+	tasks, _, _ := unstructured.NestedSlice(run.Object, "spec", "pipelineSpec", "tasks")
+	require.Len(t, tasks, 3)
+	train := tasks[1].(map[string]interface{})
+	require.Equal(t, []interface{}{"sdg"}, train["runAfter"])
+	taskLabels, _, _ := unstructured.NestedStringMap(train, "taskSpec", "metadata", "labels")
+	require.Equal(t, map[string]string{WorkflowTaskLabel: "train"}, taskLabels)
+	step := train["taskSpec"].(map[string]interface{})["steps"].([]interface{})[0].(map[string]interface{})
+	require.Equal(t, "training:1", step["image"])
+	require.Equal(t, "2", step["computeResources"].(map[string]interface{})["limits"].(map[string]interface{})["nvidia.com/gpu"])
+	require.Contains(t, step["env"], map[string]interface{}{"name": "TRAIN_NUM_EPOCHS_PHASE_1", "value": "$(params.train_num_epochs_phase_1)"})
+	require.Contains(t, step["env"], map[string]interface{}{"name": "DATA_DIR", "value": "$(workspaces.data.path)"})
+	sdgStep := tasks[0].(map[string]interface{})["taskSpec"].(map[string]interface{})["steps"].([]interface{})[0].(map[string]interface{})
+	require.Contains(t, sdgStep["env"], map[string]interface{}{
+		"name":      "ENDPOINT",
+		"valueFrom": map[string]interface{}{"secretKeyRef": map[string]interface{}{"name": "teacher-secret", "key": "endpoint"}},
+	})
+
+	runParams, _, _ := unstructured.NestedSlice(run.Object, "spec", "params")
+	require.Contains(t, runParams, map[string]interface{}{"name": "train_learning_rate_phase_1", "value": "0.1"})
+	require.NotContains(t, runParams, map[string]interface{}{"name": "train_gpu_per_worker", "value": "2"})
+	timeout, _, _ := unstructured.NestedString(run.Object, "spec", "timeouts", "pipeline")
+	require.Equal(t, "8h0m0s", timeout)
+	_, found, _ := unstructured.NestedMap(run.Object, "spec", "taskRunTemplate")
+	require.False(t, found)
+
+	config.DNS = PodDNS{HostAliases: []corev1.HostAlias{{IP: "10.0.0.5", Hostnames: []string{"teacher.internal"}}}}
+	run, err = TektonBackend{}.NewRun(workflow, config)
+	require.NoError(t, err)
+	podTemplate, _, _ := unstructured.NestedMap(run.Object, "spec", "taskRunTemplate", "podTemplate")
+	require.Equal(t, map[string]interface{}{"hostAliases": []interface{}{
+		map[string]interface{}{"ip": "10.0.0.5", "hostnames": []interface{}{"teacher.internal"}},
+	}}, podTemplate)
+
+	delete(config.Params, "train_seed")
+	_, err = TektonBackend{}.NewRun(workflow, config)
+	require.EqualError(t, err, "task train: no value for parameter 'train_seed'")
+}
+
+func TestArgoNewRun(t *testing.T) {
+	workflow, err := LoadWorkflow("../resources/workflow_tasks.yaml")
+	require.NoError(t, err)
+	config := testWorkflowRunConfig()
+	config.Labels = map[string]string{"team": "ilab-on-ocp"}
+	run, err := ArgoBackend{}.NewRun(workflow, config)
+	require.NoError(t, err)
+
+	podLabels, _, _ := unstructured.NestedStringMap(run.Object, "spec", "podMetadata", "labels")
+	require.Equal(t, map[string]string{RunIDLabel: "ilab-x1", "team": "ilab-on-ocp"}, podLabels)
+	require.Equal(t, podLabels, run.GetLabels())
+	claims, _, _ := unstructured.NestedSlice(run.Object, "spec", "volumeClaimTemplates")
+	claimLabels, _, _ := unstructured.NestedStringMap(claims[0].(map[string]interface{}), "metadata", "labels")
+	require.Equal(t, podLabels, claimLabels)
+	deadline, _, _ := unstructured.NestedInt64(run.Object, "spec", "activeDeadlineSeconds")
+	require.Equal(t, int64(8*3600), deadline)
+
+	templates, _, _ := unstructured.NestedSlice(run.Object, "spec", "templates")
+	require.Len(t, templates, 4)
+	dagTasks := templates[0].(map[string]interface{})["dag"].(map[string]interface{})["tasks"].([]interface{})
+	require.Equal(t, map[string]interface{}{
+		"name":         "eval",
+		"template":     "eval",
+		"dependencies": []interface{}{"train"},
+		"arguments": map[string]interface{}{"parameters": []interface{}{
+			map[string]interface{}{"name": "mt_bench_max_workers", "value": "{{workflow.parameters.mt_bench_max_workers}}"},
+		}},
+	}, dagTasks[2])
+	taskLabels, _, _ := unstructured.NestedStringMap(templates[2].(map[string]interface{}), "metadata", "labels")
+	require.Equal(t, map[string]string{WorkflowTaskLabel: "train"}, taskLabels)
+	script := templates[2].(map[string]interface{})["script"].(map[string]interface{})
+	require.Equal(t, "training:1", script["image"])
+	require.Contains(t, script["env"], map[string]interface{}{"name": "TRAIN_SEED", "value": "{{inputs.parameters.train_seed}}"})
+	require.Contains(t, script["env"], map[string]interface{}{"name": "MODEL_DIR", "value": "/model"})
+	require.Equal(t, "2", script["resources"].(map[string]interface{})["requests"].(map[string]interface{})["nvidia.com/gpu"])
+
+	parameters, _, _ := unstructured.NestedSlice(run.Object, "spec", "arguments", "parameters")
+	require.Contains(t, parameters, map[string]interface{}{"name": "sdg_scale_factor", "value": "30"})
+
+	config.DNS = PodDNS{DNSConfig: &corev1.PodDNSConfig{Searches: []string{"corp.internal"}}}
+	run, err = ArgoBackend{}.NewRun(workflow, config)
+	require.NoError(t, err)
+	searches, _, _ := unstructured.NestedStringSlice(run.Object, "spec", "dnsConfig", "searches")
+	require.Equal(t, []string{"corp.internal"}, searches)
+}
+
+func TestWorkflowTaskStates(t *testing.T) {
+	workflow, err := LoadWorkflow("../resources/workflow_tasks.yaml")
+	require.NoError(t, err)
+	states := TektonTaskRunStates(workflow, []unstructured.Unstructured{
+		*testTaskRun("run", "sdg", "True"),
+		*testTaskRun("run", "train", "Unknown"),
+		*testTaskRun("run", "other", "True"),
+	})
+	expected := []WorkflowTaskState{
+		{Task: "sdg", Phase: "sdg", State: dag.Succeeded},
+		{Task: "train", Phase: "training-phase-1", State: dag.Running},
+	}
+	require.Equal(t, expected, states)
+
+	run := &unstructured.Unstructured{Object: map[string]interface{}{"status": map[string]interface{}{"nodes": map[string]interface{}{
+		"run":            map[string]interface{}{"type": "DAG", "displayName": "run", "phase": "Running"},
+		"run-1111111111": map[string]interface{}{"type": "Pod", "displayName": "sdg", "phase": "Succeeded"},
+		"run-2222222222": map[string]interface{}{"type": "Pod", "displayName": "train", "phase": "Running"},
+	}}}}
+	require.Equal(t, expected, ArgoNodeStates(workflow, run))
+	run.Object["status"].(map[string]interface{})["nodes"].(map[string]interface{})["run-2222222222"].(map[string]interface{})["phase"] = "Error"
+	require.Equal(t, dag.Failed, ArgoNodeStates(workflow, run)[1].State)
+
+	require.Equal(t, RunPhase{Phase: "training-phase-1", Percent: 40}, WorkflowRunPhase(states))
+	require.Equal(t, RunPhase{Phase: "pending"}, WorkflowRunPhase(nil))
+	require.Equal(t, []string{"phase training-phase-1 did not execute", "phase mt-bench did not execute"}, CheckWorkflowPhases(states, workflow.Phases()))
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ZonesSingle pins the training workers of a scenario to the zone with the most GPU nodes
+	ZonesSingle = "single"
+	// ZonesSpread spreads the training workers of a scenario evenly across zones
+	ZonesSpread = "spread"
+)
+
+// GPUNodesByZone groups the names of the nodes with allocatable GPUs of the given resource by zone, leaving out the
+// nodes without a zone
+func GPUNodesByZone(nodes []corev1.Node, gpuResource string) map[string][]string {
+	byZone := map[string][]string{}
+	for _, node := range nodes {
+		gpus, ok := node.Status.Allocatable[corev1.ResourceName(gpuResource)]
+		zone := node.Labels[ZoneLabel]
+		if !ok || gpus.IsZero() || zone == "" {
+			continue
+		}
+		byZone[zone] = append(byZone[zone], node.Name)
+	}
+	return byZone
+}
+
+// ZonePlacementOverrides returns the pipeline parameters placing the training workers of a scenario by zone, given
+// the GPU nodes by zone: a node selector on the zone with the most GPU nodes for ZonesSingle, added to the node
+// selectors of the scenario parameters, or a zone topology spread constraint for ZonesSpread
+func (s Scenario) ZonePlacementOverrides(gpuNodesByZone map[string][]string) (map[string]interface{}, error) {
+	zones := make([]string, 0, len(gpuNodesByZone))
+	for zone := range gpuNodesByZone {
+		zones = append(zones, zone)
+	}
+	sort.Slice(zones, func(i, j int) bool {
+		if len(gpuNodesByZone[zones[i]]) != len(gpuNodesByZone[zones[j]]) {
+			return len(gpuNodesByZone[zones[i]]) > len(gpuNodesByZone[zones[j]])
+		}
+		return zones[i] < zones[j]
+	})
+
+	switch s.Zones {
+	case ZonesSingle:
+		if len(zones) == 0 {
+			return nil, fmt.Errorf("no GPU nodes with a %s label found", ZoneLabel)
+		}
+		selectors := map[string]interface{}{}
+		if scenarioSelectors, ok := s.Params["train_node_selectors"].(map[string]interface{}); ok {
+			for key, value := range scenarioSelectors {
+				selectors[key] = value
+			}
+		}
+		selectors[ZoneLabel] = zones[0]
+		return map[string]interface{}{"train_node_selectors": selectors}, nil
+	case ZonesSpread:
+		if len(zones) < 2 {
+			return nil, fmt.Errorf("spreading the training workers needs GPU nodes in 2 zones at least, found %v", zones)
+		}
+		spread := TopologySpread{TopologyKey: ZoneLabel, MaxSkew: 1, WhenUnsatisfiable: string(corev1.DoNotSchedule)}
+		return map[string]interface{}{TopologySpreadParameter: TopologySpreadParameterValue([]TopologySpread{spread})}, nil
+	}
+	return map[string]interface{}{}, nil
+}
+
+// ZoneRun is the run of a scenario placing its training workers by zone
+type ZoneRun struct {
+	Scenario string
+	Zones    string
+	// PodsPerZone counts the training pods in each zone
+	PodsPerZone map[string]int
+	// Phases are the durations of the training phases, by phase
+	Phases map[string]time.Duration
+}
+
+// RenderZoneComparison renders the runs of the scenarios placing their training workers by zone as a Markdown
+// report, with the phase-2 duration of the spread runs relative to the first single zone run
+func RenderZoneComparison(runs []ZoneRun) string {
+	var report strings.Builder
+	report.WriteString("# Cross-zone training comparison\n\n")
+	report.WriteString("| Scenario | Zones | Training pods per zone | training-phase-1 | training-phase-2 |\n")
+	report.WriteString("|---|---|---|---|---|\n")
+	var baseline time.Duration
+	for _, run := range runs {
+		zones := make([]string, 0, len(run.PodsPerZone))
+		for zone, pods := range run.PodsPerZone {
+			zones = append(zones, fmt.Sprintf("%s: %d", zone, pods))
+		}
+		sort.Strings(zones)
+		fmt.Fprintf(&report, "| %s | %s | %s | %s | %s |\n", run.Scenario, run.Zones, strings.Join(zones, ", "),
+			formatDuration(run.Phases["training-phase-1"]), formatDuration(run.Phases["training-phase-2"]))
+		if baseline == 0 && run.Zones == ZonesSingle {
+			baseline = run.Phases["training-phase-2"]
+		}
+	}
+	if baseline == 0 {
+		return report.String()
+	}
+	report.WriteString("\n")
+	for _, run := range runs {
+		if phase2 := run.Phases["training-phase-2"]; run.Zones == ZonesSpread && phase2 > 0 {
+			fmt.Fprintf(&report, "Phase 2 of %s across zones took %.2fx the single zone run.\n", run.Scenario, float64(phase2)/float64(baseline))
+		}
+	}
+	return report.String()
+}
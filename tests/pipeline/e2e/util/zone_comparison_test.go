@@ -0,0 +1,77 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testUtil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestZonePlacementOverrides(t *testing.T) {
+	node := func(name, zone, gpus string) corev1.Node {
+		node := corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}}}
+		if zone != "" {
+			node.Labels[ZoneLabel] = zone
+		}
+		node.Status.Allocatable = corev1.ResourceList{DefaultGPUResource: resource.MustParse(gpus)}
+		return node
+	}
+	gpuNodes := GPUNodesByZone([]corev1.Node{
+		node("a1", "a", "8"), node("b1", "b", "8"), node("b2", "b", "4"), node("c1", "c", "0"), node("unzoned", "", "8"),
+	}, DefaultGPUResource)
+	require.Equal(t, map[string][]string{"a": {"a1"}, "b": {"b1", "b2"}}, gpuNodes)
+
+	// The zone selector is added to the node selectors of the scenario
+	single := Scenario{Zones: ZonesSingle, Params: map[string]interface{}{"train_node_selectors": map[string]interface{}{"gpu": "h100"}}}
+	overrides, err := single.ZonePlacementOverrides(gpuNodes)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"train_node_selectors": map[string]interface{}{"gpu": "h100", ZoneLabel: "b"}}, overrides)
+	_, err = single.ZonePlacementOverrides(nil)
+	require.EqualError(t, err, "no GPU nodes with a topology.kubernetes.io/zone label found")
+
+	spread := Scenario{Zones: ZonesSpread}
+	overrides, err = spread.ZonePlacementOverrides(gpuNodes)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{TopologySpreadParameter: []interface{}{
+		map[string]interface{}{"maxSkew": 1, "topologyKey": ZoneLabel, "whenUnsatisfiable": "DoNotSchedule"},
+	}}, overrides)
+	_, err = spread.ZonePlacementOverrides(map[string][]string{"a": {"a1"}})
+	require.EqualError(t, err, "spreading the training workers needs GPU nodes in 2 zones at least, found [a]")
+}
+
+func TestRenderZoneComparison(t *testing.T) {
+	report := RenderZoneComparison([]ZoneRun{
+		{Scenario: "cross-zone-training", Zones: ZonesSpread, PodsPerZone: map[string]int{"b": 2, "a": 2},
+			Phases: map[string]time.Duration{"training-phase-1": time.Hour, "training-phase-2": 3 * time.Hour}},
+		{Scenario: "single-zone-training", Zones: ZonesSingle, PodsPerZone: map[string]int{"a": 4, "b": 0},
+			Phases: map[string]time.Duration{"training-phase-1": 50 * time.Minute, "training-phase-2": 2 * time.Hour}},
+	})
+	require.Equal(t, `# Cross-zone training comparison
+
+| Scenario | Zones | Training pods per zone | training-phase-1 | training-phase-2 |
+|---|---|---|---|---|
+| cross-zone-training | spread | a: 2, b: 2 | 1h0m0s | 3h0m0s |
+| single-zone-training | single | a: 4, b: 0 | 50m0s | 2h0m0s |
+
+Phase 2 of cross-zone-training across zones took 1.50x the single zone run.
+`, report)
+
+	// Without a single zone run there is nothing to compare to
+	require.NotContains(t, RenderZoneComparison([]ZoneRun{{Scenario: "cross-zone-training", Zones: ZonesSpread}}), "took")
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package odh
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	TestUtil "github.com/opendatahub-io/ilab-on-ocp/tests/pipeline/e2e/util"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/dag"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+// runWorkflowScenario runs the SDG, training and eval tasks of resources/workflow_tasks.yaml with the executor of
+// the scenario orchestrator, a workflow engine or standalone pods, with the images and parameters of the scenario,
+// and checks the run against its phases and duration threshold, and that every resource it created carries the labels
+// of run_labels.yaml. A scenario comparing training images runs once with each candidate instead.
+func runWorkflowScenario(t *testing.T, config pipelineTestConfig, scenario TestUtil.Scenario) {
+	if len(scenario.Compare) > 0 {
+		runComparisonScenario(t, config, scenario)
+		return
+	}
+	images := TestUtil.LoadImageMatrix(t, "../e2e/resources/image_matrix.yaml").Baseline
+	if scenario.Images.SDG != "" {
+		images.SDGImage = scenario.Images.SDG
+	}
+	if scenario.Images.Training != "" {
+		images.TrainingImage = scenario.Images.Training
+	}
+
+	name := TestUtil.GenerateName(scenario.Orchestrator) + rand.String(5)
+	run := runWorkflow(t, config, scenario, pipelineNamespace(t), name, images)
+	require.NoError(t, run.err, "Run %s did not complete successfully", name)
+	checkWorkflowRun(t, scenario, run)
+	// Unlike the pipeline server, the orchestrators are given the labels to set on everything they create
+	checkRunLabels(t, name, run.start, run.config.RunLabels(), true)
+}
+
+// workflowRun is a run of the workflow tasks, with the state of its tasks as reported by the executor
+type workflowRun struct {
+	config   TestUtil.WorkflowRunConfig
+	start    time.Time
+	duration time.Duration
+	states   []TestUtil.WorkflowTaskState
+	err      error
+}
+
+// runWorkflow runs the workflow tasks in a namespace with the executor of the scenario orchestrator and waits for the
+// run to end. The watchers are started with the name of the run before it is created and stopped once it ended.
+func runWorkflow(t *testing.T, config pipelineTestConfig, scenario TestUtil.Scenario, namespace, name string, images TestUtil.ImageCombination, watchers ...runWatcher) workflowRun {
+	modelPVC := os.Getenv("WORKFLOW_MODEL_PVC")
+	require.NotEmpty(t, modelPVC, "WORKFLOW_MODEL_PVC environment variable must be set")
+
+	workflow, err := TestUtil.LoadWorkflow("../e2e/resources/workflow_tasks.yaml")
+	require.NoError(t, err, "Failed to load the workflow tasks")
+	graph, err := workflow.Graph()
+	require.NoError(t, err)
+
+	timeout := config.runTimeout
+	if limit := scenario.Thresholds.MaxDuration; limit+10*time.Minute > timeout {
+		timeout = limit + 10*time.Minute
+	}
+	params := loadPipelineParams(t, scenario.ParameterOverrides())
+	storageClass, _ := params["k8s_storage_class_name"].(string)
+	run := workflowRun{
+		config: TestUtil.WorkflowRunConfig{
+			Name:         name,
+			Images:       images,
+			Params:       params,
+			StorageClass: storageClass,
+			Timeout:      timeout,
+			Labels:       TestUtil.LoadRunLabels(t, "../e2e/resources/run_labels.yaml"),
+			DNS:          podDNS(t),
+		},
+	}
+	executor := workflowExecutor(t, scenario.Orchestrator, namespace, workflow, run.config)
+
+	report := func(status dag.Status) {
+		t.Logf("Task %s of run %s is %s", status.Phase, name, status.State)
+		for i, state := range run.states {
+			if state.Task == status.Phase {
+				run.states[i].State = status.State
+				return
+			}
+		}
+		for _, task := range workflow.Tasks {
+			if task.Name == status.Phase {
+				run.states = append(run.states, TestUtil.WorkflowTaskState{Task: task.Name, Phase: task.Phase, State: status.State})
+			}
+		}
+	}
+
+	var stops []func()
+	for _, watch := range watchers {
+		stops = append(stops, watch(name))
+	}
+	t.Logf("Run %s started with the %s orchestrator in %s....", name, scenario.Orchestrator, namespace)
+	run.start = time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	_, run.err = dag.Run(ctx, executor, graph, dag.Artifacts{TestUtil.WorkflowModelInput: modelPVC}, report)
+	run.duration = time.Since(run.start)
+	for _, stop := range stops {
+		stop()
+	}
+	return run
+}
+
+// checkWorkflowRun checks a successful run against the phases and duration threshold of the scenario
+func checkWorkflowRun(t *testing.T, scenario TestUtil.Scenario, run workflowRun) {
+	t.Logf("Scenario %s completed in %s", scenario.Name, run.duration.Round(time.Second))
+	if limit := scenario.Thresholds.MaxDuration; limit > 0 && run.duration > limit {
+		t.Errorf("Scenario %s took %s, more than the %s threshold", scenario.Name, run.duration.Round(time.Second), limit)
+	}
+	for _, missing := range TestUtil.CheckWorkflowPhases(run.states, scenario.Phases) {
+		t.Errorf("Scenario %s: %s", scenario.Name, missing)
+	}
+}
+
+// workflowExecutor returns the executor of an orchestrator and deletes what it creates at the end of the test
+func workflowExecutor(t *testing.T, orchestrator, namespace string, workflow TestUtil.Workflow, config TestUtil.WorkflowRunConfig) dag.Executor {
+	if orchestrator == TestUtil.OrchestratorPod {
+		client := TestUtil.NewKubeClient(t)
+		t.Cleanup(func() {
+			selector := metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", TestUtil.RunIDLabel, config.Name)}
+			if err := client.CoreV1().Pods(namespace).DeleteCollection(context.Background(), metav1.DeleteOptions{}, selector); err != nil {
+				t.Logf("Failed to delete the pods of run %s: %v", config.Name, err)
+			}
+			if err := client.CoreV1().PersistentVolumeClaims(namespace).DeleteCollection(context.Background(), metav1.DeleteOptions{}, selector); err != nil {
+				t.Logf("Failed to delete the PVC of run %s: %v", config.Name, err)
+			}
+		})
+		return &TestUtil.PodExecutor{Client: TestUtil.NewWatchClient(t), Namespace: namespace, Workflow: workflow, Config: config}
+	}
+
+	backend, ok := TestUtil.WorkflowBackends[orchestrator]
+	require.True(t, ok, "Unknown orchestrator %s", orchestrator)
+	dynamicClient := TestUtil.NewDynamicClient(t)
+	require.NoError(t, TestUtil.CheckCRDEstablished(t, dynamicClient, backend.CRD()), "The %s orchestrator is not installed", orchestrator)
+	t.Cleanup(func() {
+		propagation := metav1.DeletePropagationBackground
+		err := dynamicClient.Resource(backend.Resource()).Namespace(namespace).Delete(context.Background(), config.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+		if err != nil && !apierrors.IsNotFound(err) {
+			t.Logf("Failed to delete %s %s: %v", backend.Kind().Kind, config.Name, err)
+		}
+	})
+	return &TestUtil.WorkflowExecutor{
+		Backend:       backend,
+		Client:        TestUtil.NewKubeClient(t),
+		DynamicClient: dynamicClient,
+		Namespace:     namespace,
+		Workflow:      workflow,
+		Config:        config,
+		Interval:      time.Minute,
+		Logf:          t.Logf,
+	}
+}
+
+// podDNS returns the name resolution injected into the pods the tests create, from the file set in POD_DNS_CONFIG
+func podDNS(t *testing.T) TestUtil.PodDNS {
+	dns, err := TestUtil.PodDNSFromEnv()
+	require.NoError(t, err, "Invalid POD_DNS_CONFIG")
+	return dns
+}
@@ -0,0 +1,600 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bootstrap checks the prerequisites of the ilab pipeline on a cluster, as listed in the README, and installs
+// the missing operators and components through OLM. Every step is idempotent: the resources which exist are kept, so
+// a bootstrap can be run again until the cluster is ready.
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	SubscriptionGVR          = schema.GroupVersionResource{Group: "operators.coreos.com", Version: "v1alpha1", Resource: "subscriptions"}
+	OperatorGroupGVR         = schema.GroupVersionResource{Group: "operators.coreos.com", Version: "v1", Resource: "operatorgroups"}
+	ClusterServiceVersionGVR = schema.GroupVersionResource{Group: "operators.coreos.com", Version: "v1alpha1", Resource: "clusterserviceversions"}
+	NodeFeatureDiscoveryGVR  = schema.GroupVersionResource{Group: "nfd.openshift.io", Version: "v1", Resource: "nodefeaturediscoveries"}
+	ClusterPolicyGVR         = schema.GroupVersionResource{Group: "nvidia.com", Version: "v1", Resource: "clusterpolicies"}
+	DataScienceClusterGVR    = schema.GroupVersionResource{Group: "datasciencecluster.opendatahub.io", Version: "v1", Resource: "datascienceclusters"}
+)
+
+const (
+	// DefaultTimeout is the time an installed step is waited for unless the bootstrap sets another timeout
+	DefaultTimeout = 15 * time.Minute
+	// DefaultPollInterval is the time between two checks of an installed step unless the bootstrap sets another one
+	DefaultPollInterval = 10 * time.Second
+)
+
+const (
+	// CreatedByLabel marks the resources the bootstrap created, which Uninstall deletes
+	CreatedByLabel = "ilab.opendatahub.io/created-by"
+	// CreatedByValue is the value of CreatedByLabel
+	CreatedByValue = "ilab-bootstrap"
+	// PreviousComponentsAnnotation records on a DataScienceCluster the bootstrap did not create the management states
+	// of the components it changed, as a JSON object, so Uninstall can restore them
+	PreviousComponentsAnnotation = "ilab.opendatahub.io/bootstrap-previous-components"
+)
+
+// GPUResource is the extended resource of the NVIDIA GPUs
+const GPUResource = corev1.ResourceName("nvidia.com/gpu")
+
+// NVIDIAPCILabel is set by NFD on the nodes with an NVIDIA PCI device, which the GPU operator deploys its driver to
+const NVIDIAPCILabel = "feature.node.kubernetes.io/pci-10de.present"
+
+// Operator is an operator installed through an OLM subscription
+type Operator struct {
+	Package   string
+	Channel   string
+	Source    string
+	Namespace string
+	// AllNamespaces makes the operator group watch every namespace rather than the namespace of the operator
+	AllNamespaces bool
+}
+
+var (
+	// NFDOperator is the Node Feature Discovery operator, which labels the GPU nodes
+	NFDOperator = Operator{Package: "nfd", Channel: "stable", Source: "redhat-operators", Namespace: "openshift-nfd"}
+	// GPUOperator is the NVIDIA GPU operator. Only 24.6 runs the CUDA version of the training image, see the README.
+	GPUOperator = Operator{Package: "gpu-operator-certified", Channel: "v24.6", Source: "certified-operators", Namespace: "nvidia-gpu-operator"}
+	// RHOAIOperator is the OpenShift AI operator
+	RHOAIOperator = Operator{Package: "rhods-operator", Channel: "stable", Source: "redhat-operators", Namespace: "redhat-ods-operator", AllNamespaces: true}
+)
+
+// RequiredDSCComponents are the DataScienceCluster components the ilab pipeline depends on, with the ready condition
+// types they report. Older operator releases use the lowercase variants.
+var RequiredDSCComponents = map[string][]string{
+	"datasciencepipelines": {"DataSciencePipelinesReady", "data-science-pipelines-operatorReady"},
+	"trainingoperator":     {"TrainingOperatorReady", "trainingoperatorReady"},
+	"kserve":               {"KserveReady", "kserveReady"},
+	"modelregistry":        {"ModelRegistryReady", "model-registry-operatorReady"},
+}
+
+// Status is the outcome of the check of a step
+type Status struct {
+	Ready bool
+	// Detail describes what was found, or what is missing
+	Detail string
+}
+
+// Step is a prerequisite of the pipeline
+type Step struct {
+	Name  string
+	Check func(ctx context.Context) (Status, error)
+	// Install creates the missing resources of the step, nil when the step cannot be installed, e.g. GPU nodes
+	Install func(ctx context.Context) error
+	// Fix tells how to fix the step by hand
+	Fix string
+}
+
+// Result is the outcome of a step
+type Result struct {
+	Name      string `json:"name"`
+	Ready     bool   `json:"ready"`
+	Detail    string `json:"detail"`
+	Installed bool   `json:"installed,omitempty"`
+	Fix       string `json:"fix,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Bootstrap holds the clients and the settings of the steps
+type Bootstrap struct {
+	Client  kubernetes.Interface
+	Dynamic dynamic.Interface
+	// Namespace is the data science project the storage is probed in
+	Namespace string
+	// StorageClass is the storage class of the pipeline PVCs, the default storage class when empty
+	StorageClass string
+	// ResourcePrefix prefixes the generated name of the storage probe PVC, as the resource prefix of the e2e tests
+	ResourcePrefix string
+	// MinGPUsPerNode is the number of GPUs a node needs for the training, 4 as per the README
+	MinGPUsPerNode int64
+	// Timeout is the time an installed step or the storage probe is waited for, DefaultTimeout when zero
+	Timeout time.Duration
+	// PollInterval is the time between two checks of an installed step, DefaultPollInterval when zero
+	PollInterval time.Duration
+}
+
+// Steps returns the steps in the order they are installed, each depending on the previous ones
+func (b *Bootstrap) Steps() []Step {
+	return []Step{
+		b.operatorStep(NFDOperator, "node feature discovery operator"),
+		{
+			Name:    "node feature discovery",
+			Check:   b.checkNFD,
+			Install: b.createFromExample(NFDOperator, NodeFeatureDiscoveryGVR, "NodeFeatureDiscovery"),
+			Fix:     "create a NodeFeatureDiscovery in openshift-nfd and check the GPU nodes have an NVIDIA PCI device",
+		},
+		b.operatorStep(GPUOperator, "NVIDIA GPU operator"),
+		{
+			Name:    "GPU cluster policy",
+			Check:   b.checkClusterPolicy,
+			Install: b.createFromExample(GPUOperator, ClusterPolicyGVR, "ClusterPolicy"),
+			Fix:     "create a ClusterPolicy from the example of the GPU operator and check the pods of nvidia-gpu-operator",
+		},
+		{
+			Name:  "GPU nodes",
+			Check: b.checkGPUNodes,
+			Fix:   fmt.Sprintf("add a node with at least %d NVIDIA GPUs, e.g. A100s", b.MinGPUsPerNode),
+		},
+		b.operatorStep(RHOAIOperator, "OpenShift AI operator"),
+		{
+			Name:    "DataScienceCluster components",
+			Check:   b.checkDSC,
+			Install: b.installDSC,
+			Fix:     "set the components " + strings.Join(sortedKeys(RequiredDSCComponents), ", ") + " of the DataScienceCluster to Managed",
+		},
+		{
+			Name:  "ReadWriteMany storage",
+			Check: b.checkRWXStorage,
+			Fix:   "add a storage class with dynamic provisioning of ReadWriteMany volumes, e.g. NFS for non-production clusters",
+		},
+	}
+}
+
+// Run checks the steps in order, installing those which are not ready when install is set and waiting for them to
+// become ready. The steps after a step which is not ready are still checked, to report everything missing.
+func (b *Bootstrap) Run(ctx context.Context, steps []Step, install bool) []Result {
+	var results []Result
+	for _, step := range steps {
+		result := Result{Name: step.Name}
+		status, err := step.Check(ctx)
+		if err == nil && !status.Ready && install && step.Install != nil {
+			if err = step.Install(ctx); err == nil {
+				result.Installed = true
+				status, err = b.wait(ctx, step)
+			}
+		}
+		result.Ready, result.Detail = status.Ready, status.Detail
+		if err != nil {
+			result.Error = err.Error()
+		}
+		if !result.Ready {
+			result.Fix = step.Fix
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// Ready reports whether every step is ready
+func Ready(results []Result) bool {
+	for _, result := range results {
+		if !result.Ready {
+			return false
+		}
+	}
+	return true
+}
+
+// RenderResults renders the results as a table, with the fixes of the steps which are not ready
+func RenderResults(results []Result) string {
+	var b strings.Builder
+	b.WriteString("| Step | Ready | Detail |\n|---|---|---|\n")
+	for _, result := range results {
+		ready := "yes"
+		if !result.Ready {
+			ready = "**no**"
+		}
+		detail := result.Detail
+		if result.Installed {
+			detail = "installed, " + detail
+		}
+		if result.Error != "" {
+			detail += " (" + result.Error + ")"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s |\n", result.Name, ready, detail)
+	}
+	if !Ready(results) {
+		b.WriteString("\nTo fix:\n")
+		for _, result := range results {
+			if result.Fix != "" {
+				fmt.Fprintf(&b, "- %s: %s\n", result.Name, result.Fix)
+			}
+		}
+	}
+	return b.String()
+}
+
+func (b *Bootstrap) wait(ctx context.Context, step Step) (Status, error) {
+	ctx, cancel := context.WithTimeout(ctx, b.timeout())
+	defer cancel()
+	for {
+		status, err := step.Check(ctx)
+		if err != nil || status.Ready {
+			return status, err
+		}
+		select {
+		case <-ctx.Done():
+			status.Detail += fmt.Sprintf(", not ready after %s", b.timeout())
+			return status, nil
+		case <-time.After(b.pollInterval()):
+		}
+	}
+}
+
+func (b *Bootstrap) timeout() time.Duration {
+	if b.Timeout == 0 {
+		return DefaultTimeout
+	}
+	return b.Timeout
+}
+
+func (b *Bootstrap) pollInterval() time.Duration {
+	if b.PollInterval == 0 {
+		return DefaultPollInterval
+	}
+	return b.PollInterval
+}
+
+func (b *Bootstrap) operatorStep(operator Operator, name string) Step {
+	return Step{
+		Name: name,
+		Check: func(ctx context.Context) (Status, error) {
+			csv, err := operatorCSV(ctx, b.Dynamic, operator)
+			if err != nil || csv == nil {
+				return Status{Detail: fmt.Sprintf("no %s operator in %s", operator.Package, operator.Namespace)}, err
+			}
+			phase, _, _ := unstructured.NestedString(csv.Object, "status", "phase")
+			return Status{Ready: phase == "Succeeded", Detail: fmt.Sprintf("%s %s", csv.GetName(), phase)}, nil
+		},
+		Install: func(ctx context.Context) error { return b.subscribe(ctx, operator) },
+		Fix:     fmt.Sprintf("install the %s operator from %s, channel %s, in %s", operator.Package, operator.Source, operator.Channel, operator.Namespace),
+	}
+}
+
+// subscribe creates the namespace, the operator group and the subscription of an operator, keeping those which exist
+func (b *Bootstrap) subscribe(ctx context.Context, operator Operator) error {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: operator.Namespace, Labels: map[string]string{CreatedByLabel: CreatedByValue}}}
+	_, err := b.Client.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create namespace %s: %w", operator.Namespace, err)
+	}
+	groups, err := b.Dynamic.Resource(OperatorGroupGVR).Namespace(operator.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list the operator groups of %s: %w", operator.Namespace, err)
+	}
+	// A namespace has a single operator group, which may have been created with another operator
+	if len(groups.Items) == 0 {
+		spec := map[string]interface{}{}
+		if !operator.AllNamespaces {
+			spec["targetNamespaces"] = []interface{}{operator.Namespace}
+		}
+		group := newObject("operators.coreos.com/v1", "OperatorGroup", operator.Namespace, operator.Package, spec)
+		if _, err := b.Dynamic.Resource(OperatorGroupGVR).Namespace(operator.Namespace).Create(ctx, group, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create the operator group of %s: %w", operator.Package, err)
+		}
+	}
+	subscription := newObject("operators.coreos.com/v1alpha1", "Subscription", operator.Namespace, operator.Package, map[string]interface{}{
+		"name":                operator.Package,
+		"channel":             operator.Channel,
+		"source":              operator.Source,
+		"sourceNamespace":     "openshift-marketplace",
+		"installPlanApproval": "Automatic",
+	})
+	if _, err := b.Dynamic.Resource(SubscriptionGVR).Namespace(operator.Namespace).Create(ctx, subscription, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to subscribe to %s: %w", operator.Package, err)
+	}
+	return nil
+}
+
+// createFromExample returns the installation of the custom resource of an operator from the example its CSV provides
+// in the alm-examples annotation, as the console does. The resource is kept when one exists.
+func (b *Bootstrap) createFromExample(operator Operator, gvr schema.GroupVersionResource, kind string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		namespace := ""
+		if gvr != ClusterPolicyGVR && gvr != DataScienceClusterGVR {
+			namespace = operator.Namespace
+		}
+		resources := b.Dynamic.Resource(gvr).Namespace(namespace)
+		list, err := resources.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to list the %s resources: %w", kind, err)
+		}
+		if len(list.Items) > 0 {
+			return nil
+		}
+		csv, err := operatorCSV(ctx, b.Dynamic, operator)
+		if err != nil {
+			return err
+		}
+		if csv == nil {
+			return fmt.Errorf("the %s operator is not installed", operator.Package)
+		}
+		var examples []map[string]interface{}
+		if err := json.Unmarshal([]byte(csv.GetAnnotations()["alm-examples"]), &examples); err != nil {
+			return fmt.Errorf("invalid alm-examples of %s: %w", csv.GetName(), err)
+		}
+		for _, example := range examples {
+			if example["kind"] != kind {
+				continue
+			}
+			object := &unstructured.Unstructured{Object: example}
+			object.SetNamespace(namespace)
+			object.SetLabels(map[string]string{CreatedByLabel: CreatedByValue})
+			if _, err := resources.Create(ctx, object, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+				return fmt.Errorf("failed to create the %s: %w", kind, err)
+			}
+			return nil
+		}
+		return fmt.Errorf("%s has no example %s", csv.GetName(), kind)
+	}
+}
+
+func (b *Bootstrap) checkNFD(ctx context.Context) (Status, error) {
+	list, err := b.Dynamic.Resource(NodeFeatureDiscoveryGVR).Namespace(NFDOperator.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return Status{}, fmt.Errorf("failed to list the NodeFeatureDiscovery resources: %w", err)
+	}
+	if err != nil || len(list.Items) == 0 {
+		return Status{Detail: "no NodeFeatureDiscovery"}, nil
+	}
+	nodes, err := b.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: NVIDIAPCILabel + "=true"})
+	if err != nil {
+		return Status{}, fmt.Errorf("failed to list the nodes: %w", err)
+	}
+	if len(nodes.Items) == 0 {
+		return Status{Detail: "no node labeled with an NVIDIA PCI device yet"}, nil
+	}
+	return Status{Ready: true, Detail: fmt.Sprintf("%d nodes with an NVIDIA PCI device", len(nodes.Items))}, nil
+}
+
+func (b *Bootstrap) checkClusterPolicy(ctx context.Context) (Status, error) {
+	list, err := b.Dynamic.Resource(ClusterPolicyGVR).List(ctx, metav1.ListOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return Status{}, fmt.Errorf("failed to list the ClusterPolicy resources: %w", err)
+	}
+	if err != nil || len(list.Items) == 0 {
+		return Status{Detail: "no ClusterPolicy"}, nil
+	}
+	state, _, _ := unstructured.NestedString(list.Items[0].Object, "status", "state")
+	return Status{Ready: state == "ready", Detail: fmt.Sprintf("ClusterPolicy %s %s", list.Items[0].GetName(), state)}, nil
+}
+
+func (b *Bootstrap) checkGPUNodes(ctx context.Context) (Status, error) {
+	nodes, err := b.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return Status{}, fmt.Errorf("failed to list the nodes: %w", err)
+	}
+	var gpuNodes int
+	var most int64
+	for _, node := range nodes.Items {
+		gpus := node.Status.Allocatable[GPUResource]
+		if gpus.Value() > 0 {
+			gpuNodes++
+		}
+		if gpus.Value() > most {
+			most = gpus.Value()
+		}
+	}
+	detail := fmt.Sprintf("%d nodes with GPUs, at most %d GPUs per node", gpuNodes, most)
+	return Status{Ready: most >= b.MinGPUsPerNode, Detail: detail}, nil
+}
+
+func (b *Bootstrap) checkDSC(ctx context.Context) (Status, error) {
+	list, err := b.Dynamic.Resource(DataScienceClusterGVR).List(ctx, metav1.ListOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return Status{}, fmt.Errorf("failed to list the DataScienceClusters: %w", err)
+	}
+	if err != nil || len(list.Items) == 0 {
+		return Status{Detail: "no DataScienceCluster"}, nil
+	}
+	dsc := list.Items[0]
+	var missing []string
+	for _, component := range sortedKeys(RequiredDSCComponents) {
+		state, _, _ := unstructured.NestedString(dsc.Object, "spec", "components", component, "managementState")
+		if state != "Managed" {
+			missing = append(missing, component+" not managed")
+		} else if !hasTrueCondition(&dsc, RequiredDSCComponents[component]) {
+			missing = append(missing, component+" not ready")
+		}
+	}
+	if len(missing) > 0 {
+		return Status{Detail: strings.Join(missing, ", ")}, nil
+	}
+	return Status{Ready: true, Detail: "DataScienceCluster " + dsc.GetName() + " with every component ready"}, nil
+}
+
+// installDSC creates the DataScienceCluster when there is none, and sets the required components to Managed. The
+// previous states of the components of a DataScienceCluster which existed are recorded in
+// PreviousComponentsAnnotation, the states recorded by an earlier bootstrap are kept.
+func (b *Bootstrap) installDSC(ctx context.Context) error {
+	if err := b.createFromExample(RHOAIOperator, DataScienceClusterGVR, "DataScienceCluster")(ctx); err != nil {
+		return err
+	}
+	list, err := b.Dynamic.Resource(DataScienceClusterGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list the DataScienceClusters: %w", err)
+	}
+	if len(list.Items) != 1 {
+		return fmt.Errorf("expected exactly one DataScienceCluster, found %d", len(list.Items))
+	}
+	dsc := list.Items[0]
+	components := map[string]interface{}{}
+	previous := map[string]string{}
+	if recorded := dsc.GetAnnotations()[PreviousComponentsAnnotation]; recorded != "" {
+		if err := json.Unmarshal([]byte(recorded), &previous); err != nil {
+			return fmt.Errorf("invalid %s annotation of DataScienceCluster %s: %w", PreviousComponentsAnnotation, dsc.GetName(), err)
+		}
+	}
+	for component := range RequiredDSCComponents {
+		components[component] = map[string]interface{}{"managementState": "Managed"}
+		state, _, _ := unstructured.NestedString(dsc.Object, "spec", "components", component, "managementState")
+		if _, recorded := previous[component]; !recorded && state != "Managed" {
+			previous[component] = state
+		}
+	}
+	metadata := map[string]interface{}{}
+	if dsc.GetLabels()[CreatedByLabel] != CreatedByValue && len(previous) > 0 {
+		recorded, err := json.Marshal(previous)
+		if err != nil {
+			return err
+		}
+		metadata["annotations"] = map[string]interface{}{PreviousComponentsAnnotation: string(recorded)}
+	}
+	patch, err := json.Marshal(map[string]interface{}{"metadata": metadata, "spec": map[string]interface{}{"components": components}})
+	if err != nil {
+		return err
+	}
+	_, err = b.Dynamic.Resource(DataScienceClusterGVR).Patch(ctx, dsc.GetName(), types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to enable the DataScienceCluster components: %w", err)
+	}
+	return nil
+}
+
+// checkRWXStorage provisions a ReadWriteMany PVC of the storage class in the namespace and waits for it to be bound.
+// A storage class binding the volumes of its PVCs once a pod uses them is reported without a probe.
+func (b *Bootstrap) checkRWXStorage(ctx context.Context) (Status, error) {
+	if b.Namespace == "" {
+		return Status{Detail: "no namespace to probe the storage in"}, nil
+	}
+	class, err := b.storageClass(ctx)
+	if err != nil || class == "" {
+		return Status{Detail: "no default storage class"}, err
+	}
+	storageClass, err := b.Client.StorageV1().StorageClasses().Get(ctx, class, metav1.GetOptions{})
+	if err != nil {
+		return Status{Detail: "storage class " + class + " not found"}, nil
+	}
+	if storageClass.VolumeBindingMode != nil && *storageClass.VolumeBindingMode == "WaitForFirstConsumer" {
+		return Status{Detail: fmt.Sprintf("storage class %s binds its volumes on first use, the ReadWriteMany support of %s is not probed", class, storageClass.Provisioner)}, nil
+	}
+	pvcs := b.Client.CoreV1().PersistentVolumeClaims(b.Namespace)
+	pvc, err := pvcs.Create(ctx, &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: b.ResourcePrefix + "bootstrap-rwx-"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+			StorageClassName: &class,
+			Resources:        corev1.VolumeResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")}},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return Status{}, fmt.Errorf("failed to create the probe PVC in %s: %w", b.Namespace, err)
+	}
+	defer func() { _ = pvcs.Delete(context.Background(), pvc.Name, metav1.DeleteOptions{}) }()
+
+	probeCtx, cancel := context.WithTimeout(ctx, b.timeout())
+	defer cancel()
+	for {
+		pvc, err = pvcs.Get(probeCtx, pvc.Name, metav1.GetOptions{})
+		if err == nil && pvc.Status.Phase == corev1.ClaimBound {
+			return Status{Ready: true, Detail: fmt.Sprintf("storage class %s provisions ReadWriteMany volumes", class)}, nil
+		}
+		select {
+		case <-probeCtx.Done():
+			return Status{Detail: fmt.Sprintf("ReadWriteMany PVC of storage class %s not bound after %s", class, b.timeout())}, nil
+		case <-time.After(b.pollInterval()):
+		}
+	}
+}
+
+func (b *Bootstrap) storageClass(ctx context.Context) (string, error) {
+	if b.StorageClass != "" {
+		return b.StorageClass, nil
+	}
+	classes, err := b.Client.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list the storage classes: %w", err)
+	}
+	for _, class := range classes.Items {
+		if class.Annotations["storageclass.kubernetes.io/is-default-class"] == "true" {
+			return class.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// operatorCSV returns the ClusterServiceVersion of an operator, nil when it is not installed
+func operatorCSV(ctx context.Context, client dynamic.Interface, operator Operator) (*unstructured.Unstructured, error) {
+	list, err := client.Resource(ClusterServiceVersionGVR).Namespace(operator.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the ClusterServiceVersions of %s: %w", operator.Namespace, err)
+	}
+	for i := range list.Items {
+		if strings.HasPrefix(list.Items[i].GetName(), operator.Package+".") {
+			return &list.Items[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func newObject(apiVersion, kind, namespace, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	object.SetAPIVersion(apiVersion)
+	object.SetKind(kind)
+	object.SetNamespace(namespace)
+	object.SetName(name)
+	object.SetLabels(map[string]string{CreatedByLabel: CreatedByValue})
+	return object
+}
+
+func hasTrueCondition(obj *unstructured.Unstructured, conditionTypes []string) bool {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["status"] != "True" {
+			continue
+		}
+		for _, conditionType := range conditionTypes {
+			if condition["type"] == conditionType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sortedKeys[T any](values map[string]T) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func object(apiVersion, kind, namespace, name string, fields map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: fields}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func newDynamicClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		SubscriptionGVR:          "SubscriptionList",
+		OperatorGroupGVR:         "OperatorGroupList",
+		ClusterServiceVersionGVR: "ClusterServiceVersionList",
+		NodeFeatureDiscoveryGVR:  "NodeFeatureDiscoveryList",
+		ClusterPolicyGVR:         "ClusterPolicyList",
+		DataScienceClusterGVR:    "DataScienceClusterList",
+	}, objects...)
+}
+
+func TestBootstrapCheck(t *testing.T) {
+	csv := func(namespace, name, phase string) *unstructured.Unstructured {
+		return object("operators.coreos.com/v1alpha1", "ClusterServiceVersion", namespace, name, map[string]interface{}{"status": map[string]interface{}{"phase": phase}})
+	}
+	gpuNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpu-0", Labels: map[string]string{NVIDIAPCILabel: "true"}},
+		Status:     corev1.NodeStatus{Allocatable: corev1.ResourceList{GPUResource: resource.MustParse("2")}},
+	}
+	dsc := object("datasciencecluster.opendatahub.io/v1", "DataScienceCluster", "", "default-dsc", map[string]interface{}{
+		"spec": map[string]interface{}{"components": map[string]interface{}{
+			"datasciencepipelines": map[string]interface{}{"managementState": "Managed"},
+			"trainingoperator":     map[string]interface{}{"managementState": "Managed"},
+			"kserve":               map[string]interface{}{"managementState": "Managed"},
+			"modelregistry":        map[string]interface{}{"managementState": "Removed"},
+		}},
+		"status": map[string]interface{}{"conditions": []interface{}{
+			map[string]interface{}{"type": "DataSciencePipelinesReady", "status": "True"},
+			map[string]interface{}{"type": "trainingoperatorReady", "status": "True"},
+			map[string]interface{}{"type": "KserveReady", "status": "False"},
+		}},
+	})
+	immediate := storagev1.VolumeBindingImmediate
+	client := fake.NewSimpleClientset(gpuNode, &storagev1.StorageClass{
+		ObjectMeta:        metav1.ObjectMeta{Name: "nfs", Annotations: map[string]string{"storageclass.kubernetes.io/is-default-class": "true"}},
+		Provisioner:       "nfs.csi.k8s.io",
+		VolumeBindingMode: &immediate,
+	})
+	client.PrependReactor("get", "persistentvolumeclaims", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		name := action.(k8stesting.GetAction).GetName()
+		return true, &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name}, Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound}}, nil
+	})
+	var probeName string
+	client.PrependReactor("create", "persistentvolumeclaims", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		probeName = action.(k8stesting.CreateAction).GetObject().(*corev1.PersistentVolumeClaim).GenerateName
+		return false, nil, nil
+	})
+	dynamicClient := newDynamicClient(
+		csv("openshift-nfd", "nfd.4.16.0-202410011135", "Succeeded"),
+		object("nfd.openshift.io/v1", "NodeFeatureDiscovery", "openshift-nfd", "nfd-instance", map[string]interface{}{}),
+		csv("nvidia-gpu-operator", "gpu-operator-certified.v24.6.2", "Succeeded"),
+		object("nvidia.com/v1", "ClusterPolicy", "", "gpu-cluster-policy", map[string]interface{}{"status": map[string]interface{}{"state": "notReady"}}),
+		csv("redhat-ods-operator", "rhods-operator.2.19.0", "Installing"),
+		dsc,
+	)
+	b := &Bootstrap{Client: client, Dynamic: dynamicClient, Namespace: "ilab", MinGPUsPerNode: 4, ResourcePrefix: "ilab-test-"}
+
+	results := b.Run(context.Background(), b.Steps(), false)
+	require.False(t, Ready(results))
+	details := map[string]string{}
+	for _, result := range results {
+		require.False(t, result.Installed)
+		require.Empty(t, result.Error)
+		details[result.Name] = result.Detail
+		require.Equal(t, !result.Ready, result.Fix != "", result.Name)
+	}
+	require.Equal(t, map[string]string{
+		"node feature discovery operator": "nfd.4.16.0-202410011135 Succeeded",
+		"node feature discovery":          "1 nodes with an NVIDIA PCI device",
+		"NVIDIA GPU operator":             "gpu-operator-certified.v24.6.2 Succeeded",
+		"GPU cluster policy":              "ClusterPolicy gpu-cluster-policy notReady",
+		"GPU nodes":                       "1 nodes with GPUs, at most 2 GPUs per node",
+		"OpenShift AI operator":           "rhods-operator.2.19.0 Installing",
+		"DataScienceCluster components":   "kserve not ready, modelregistry not managed",
+		"ReadWriteMany storage":           "storage class nfs provisions ReadWriteMany volumes",
+	}, details)
+	// The probe PVC is named after the resource prefix, and deleted
+	require.Equal(t, "ilab-test-bootstrap-rwx-", probeName)
+	pvcs, err := client.CoreV1().PersistentVolumeClaims("ilab").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Empty(t, pvcs.Items)
+
+	report := RenderResults(results)
+	require.Contains(t, report, "| GPU nodes | **no** | 1 nodes with GPUs, at most 2 GPUs per node |\n")
+	require.Contains(t, report, "\nTo fix:\n")
+	require.Contains(t, report, "- GPU nodes: add a node with at least 4 NVIDIA GPUs, e.g. A100s\n")
+}
+
+func TestBootstrapInstall(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "nvidia-gpu-operator"}})
+	dynamicClient := newDynamicClient(
+		object("operators.coreos.com/v1", "OperatorGroup", "nvidia-gpu-operator", "existing", map[string]interface{}{}),
+	)
+	b := &Bootstrap{Client: client, Dynamic: dynamicClient, Timeout: 10 * time.Millisecond, PollInterval: time.Millisecond}
+
+	// The operator is subscribed to but does not install in the fake cluster
+	results := b.Run(ctx, []Step{b.operatorStep(GPUOperator, "NVIDIA GPU operator")}, true)
+	require.Equal(t, []Result{{
+		Name:      "NVIDIA GPU operator",
+		Detail:    "no gpu-operator-certified operator in nvidia-gpu-operator, not ready after 10ms",
+		Installed: true,
+		Fix:       "install the gpu-operator-certified operator from certified-operators, channel v24.6, in nvidia-gpu-operator",
+	}}, results)
+	subscription, err := dynamicClient.Resource(SubscriptionGVR).Namespace("nvidia-gpu-operator").Get(ctx, "gpu-operator-certified", metav1.GetOptions{})
+	require.NoError(t, err)
+	channel, _, _ := unstructured.NestedString(subscription.Object, "spec", "channel")
+	require.Equal(t, "v24.6", channel)
+	// The existing operator group of the namespace is kept
+	groups, err := dynamicClient.Resource(OperatorGroupGVR).Namespace("nvidia-gpu-operator").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, groups.Items, 1)
+	// Installing again is a no-op
+	require.NoError(t, b.subscribe(ctx, GPUOperator))
+
+	require.NoError(t, b.subscribe(ctx, NFDOperator))
+	group, err := dynamicClient.Resource(OperatorGroupGVR).Namespace("openshift-nfd").Get(ctx, "nfd", metav1.GetOptions{})
+	require.NoError(t, err)
+	targets, _, _ := unstructured.NestedStringSlice(group.Object, "spec", "targetNamespaces")
+	require.Equal(t, []string{"openshift-nfd"}, targets)
+	_, err = client.CoreV1().Namespaces().Get(ctx, "openshift-nfd", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	// The DataScienceCluster is created from the example of the operator, with the required components managed
+	csv := object("operators.coreos.com/v1alpha1", "ClusterServiceVersion", "redhat-ods-operator", "rhods-operator.2.19.0", map[string]interface{}{})
+	csv.SetAnnotations(map[string]string{"alm-examples": `[{"apiVersion":"dscinitialization.opendatahub.io/v1","kind":"DSCInitialization","metadata":{"name":"default-dsci"}},
+		{"apiVersion":"datasciencecluster.opendatahub.io/v1","kind":"DataScienceCluster","metadata":{"name":"default-dsc"},
+		"spec":{"components":{"codeflare":{"managementState":"Removed"},"trainingoperator":{"managementState":"Removed"}}}}]`})
+	_, err = dynamicClient.Resource(ClusterServiceVersionGVR).Namespace("redhat-ods-operator").Create(ctx, csv, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, b.installDSC(ctx))
+	require.NoError(t, b.installDSC(ctx))
+	dsc, err := dynamicClient.Resource(DataScienceClusterGVR).Get(ctx, "default-dsc", metav1.GetOptions{})
+	require.NoError(t, err)
+	for _, component := range []string{"trainingoperator", "datasciencepipelines", "kserve", "modelregistry"} {
+		state, _, _ := unstructured.NestedString(dsc.Object, "spec", "components", component, "managementState")
+		require.Equal(t, "Managed", state, component)
+	}
+	state, _, _ := unstructured.NestedString(dsc.Object, "spec", "components", "codeflare", "managementState")
+	require.Equal(t, "Removed", state)
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// Uninstall reverses what the bootstrap did, in the reverse order of the steps:
+// - it restores the components of a DataScienceCluster it did not create;
+// - it deletes the custom resources it created and waits for them to be gone, as their operators remove their
+// finalizers;
+// - it deletes the operators it subscribed to, with their ClusterServiceVersion, their operator group and their
+// namespace when it created them.
+//
+// The resources it did not create are kept, e.g. an operator installed beforehand. Returns the actions taken, or
+// those that would be taken with dryRun. The other steps are still applied when one fails, the failures are returned.
+func (b *Bootstrap) Uninstall(ctx context.Context, dryRun bool) ([]string, error) {
+	var actions, failures []string
+	do := func(action string, apply func() error) {
+		if dryRun {
+			actions = append(actions, action)
+			return
+		}
+		if err := apply(); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", action, err))
+			return
+		}
+		actions = append(actions, action)
+	}
+	fail := func(err error) {
+		failures = append(failures, err.Error())
+	}
+	selector := metav1.ListOptions{LabelSelector: CreatedByLabel + "=" + CreatedByValue}
+
+	dscs, err := b.Dynamic.Resource(DataScienceClusterGVR).List(ctx, metav1.ListOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		fail(fmt.Errorf("failed to list the DataScienceClusters: %w", err))
+	}
+	if err == nil {
+		for _, dsc := range dscs.Items {
+			recorded := dsc.GetAnnotations()[PreviousComponentsAnnotation]
+			if dsc.GetLabels()[CreatedByLabel] == CreatedByValue || recorded == "" {
+				continue
+			}
+			do("restore the components of DataScienceCluster "+dsc.GetName(), func() error {
+				return restoreComponents(ctx, b.Dynamic, dsc.GetName(), recorded)
+			})
+		}
+	}
+
+	for _, resource := range []struct {
+		gvr       schema.GroupVersionResource
+		kind      string
+		namespace string
+	}{
+		{DataScienceClusterGVR, "DataScienceCluster", ""},
+		{ClusterPolicyGVR, "ClusterPolicy", ""},
+		{NodeFeatureDiscoveryGVR, "NodeFeatureDiscovery", NFDOperator.Namespace},
+	} {
+		resources := b.Dynamic.Resource(resource.gvr).Namespace(resource.namespace)
+		list, err := resources.List(ctx, selector)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			fail(fmt.Errorf("failed to list the %s resources: %w", resource.kind, err))
+			continue
+		}
+		for _, object := range list.Items {
+			do(fmt.Sprintf("delete %s %s", resource.kind, object.GetName()), func() error {
+				return b.deleteAndWait(ctx, resources, object.GetName())
+			})
+		}
+	}
+
+	for _, operator := range []Operator{RHOAIOperator, GPUOperator, NFDOperator} {
+		subscriptions := b.Dynamic.Resource(SubscriptionGVR).Namespace(operator.Namespace)
+		list, err := subscriptions.List(ctx, selector)
+		if err != nil && !errors.IsNotFound(err) {
+			fail(fmt.Errorf("failed to list the subscriptions of %s: %w", operator.Namespace, err))
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		for _, subscription := range list.Items {
+			do(fmt.Sprintf("delete Subscription %s/%s", operator.Namespace, subscription.GetName()), func() error {
+				return ignoreNotFound(subscriptions.Delete(ctx, subscription.GetName(), metav1.DeleteOptions{}))
+			})
+			// Deleting the subscription leaves the operator running, its ClusterServiceVersion removes it
+			csv, _, _ := unstructured.NestedString(subscription.Object, "status", "installedCSV")
+			if csv != "" {
+				do(fmt.Sprintf("delete ClusterServiceVersion %s/%s", operator.Namespace, csv), func() error {
+					return ignoreNotFound(b.Dynamic.Resource(ClusterServiceVersionGVR).Namespace(operator.Namespace).Delete(ctx, csv, metav1.DeleteOptions{}))
+				})
+			}
+		}
+		groups := b.Dynamic.Resource(OperatorGroupGVR).Namespace(operator.Namespace)
+		list, err = groups.List(ctx, selector)
+		if err != nil && !errors.IsNotFound(err) {
+			fail(fmt.Errorf("failed to list the operator groups of %s: %w", operator.Namespace, err))
+		}
+		if err == nil {
+			for _, group := range list.Items {
+				do(fmt.Sprintf("delete OperatorGroup %s/%s", operator.Namespace, group.GetName()), func() error {
+					return ignoreNotFound(groups.Delete(ctx, group.GetName(), metav1.DeleteOptions{}))
+				})
+			}
+		}
+		namespace, err := b.Client.CoreV1().Namespaces().Get(ctx, operator.Namespace, metav1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			fail(fmt.Errorf("failed to get namespace %s: %w", operator.Namespace, err))
+		}
+		if err == nil && namespace.Labels[CreatedByLabel] == CreatedByValue {
+			do("delete Namespace "+operator.Namespace, func() error {
+				return ignoreNotFound(b.Client.CoreV1().Namespaces().Delete(ctx, operator.Namespace, metav1.DeleteOptions{}))
+			})
+		}
+	}
+
+	if len(failures) > 0 {
+		return actions, fmt.Errorf("uninstall incomplete: %v", failures)
+	}
+	return actions, nil
+}
+
+// restoreComponents sets the components of a DataScienceCluster back to the management states recorded by the
+// bootstrap, and removes the record. A component which had no state is set to Removed.
+func restoreComponents(ctx context.Context, client dynamic.Interface, name, recorded string) error {
+	var previous map[string]string
+	if err := json.Unmarshal([]byte(recorded), &previous); err != nil {
+		return fmt.Errorf("invalid %s annotation: %w", PreviousComponentsAnnotation, err)
+	}
+	components := map[string]interface{}{}
+	for component, state := range previous {
+		if state == "" {
+			state = "Removed"
+		}
+		components[component] = map[string]interface{}{"managementState": state}
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]interface{}{PreviousComponentsAnnotation: nil}},
+		"spec":     map[string]interface{}{"components": components},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = client.Resource(DataScienceClusterGVR).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// deleteAndWait deletes a resource and waits for it to be gone, its operator removing its finalizers
+func (b *Bootstrap) deleteAndWait(ctx context.Context, resources dynamic.ResourceInterface, name string) error {
+	if err := resources.Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+		return ignoreNotFound(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, b.timeout())
+	defer cancel()
+	for {
+		_, err := resources.Get(ctx, name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("still present after %s", b.timeout())
+		case <-time.After(b.pollInterval()):
+		}
+	}
+}
+
+func ignoreNotFound(err error) error {
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestUninstall(t *testing.T) {
+	ctx := context.Background()
+	// The GPU operator and the DataScienceCluster existed before the bootstrap
+	client := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "nvidia-gpu-operator"}})
+	nfdCSV := object("operators.coreos.com/v1alpha1", "ClusterServiceVersion", "openshift-nfd", "nfd.4.16.0", map[string]interface{}{})
+	nfdCSV.SetAnnotations(map[string]string{"alm-examples": `[{"apiVersion":"nfd.openshift.io/v1","kind":"NodeFeatureDiscovery","metadata":{"name":"nfd-instance"},"spec":{}}]`})
+	dynamicClient := newDynamicClient(
+		object("operators.coreos.com/v1alpha1", "Subscription", "nvidia-gpu-operator", "gpu-operator-certified", map[string]interface{}{
+			"status": map[string]interface{}{"installedCSV": "gpu-operator-certified.v24.6.2"},
+		}),
+		object("operators.coreos.com/v1", "OperatorGroup", "nvidia-gpu-operator", "nvidia-gpu-operator", map[string]interface{}{}),
+		object("datasciencecluster.opendatahub.io/v1", "DataScienceCluster", "", "default-dsc", map[string]interface{}{
+			"spec": map[string]interface{}{"components": map[string]interface{}{
+				"datasciencepipelines": map[string]interface{}{"managementState": "Managed"},
+				"trainingoperator":     map[string]interface{}{"managementState": "Removed"},
+			}},
+		}),
+		nfdCSV,
+	)
+	b := &Bootstrap{Client: client, Dynamic: dynamicClient, Timeout: 10 * time.Millisecond, PollInterval: time.Millisecond}
+
+	require.NoError(t, b.subscribe(ctx, GPUOperator))
+	require.NoError(t, b.subscribe(ctx, NFDOperator))
+	require.NoError(t, b.createFromExample(NFDOperator, NodeFeatureDiscoveryGVR, "NodeFeatureDiscovery")(ctx))
+	require.NoError(t, b.installDSC(ctx))
+	// A second bootstrap keeps the states recorded by the first one
+	require.NoError(t, b.installDSC(ctx))
+	dsc, err := dynamicClient.Resource(DataScienceClusterGVR).Get(ctx, "default-dsc", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"kserve":"","modelregistry":"","trainingoperator":"Removed"}`, dsc.GetAnnotations()[PreviousComponentsAnnotation])
+	subscriptions := dynamicClient.Resource(SubscriptionGVR).Namespace("openshift-nfd")
+	subscription, err := subscriptions.Get(ctx, "nfd", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NoError(t, unstructured.SetNestedField(subscription.Object, "nfd.4.16.0", "status", "installedCSV"))
+	_, err = subscriptions.Update(ctx, subscription, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	expected := []string{
+		"restore the components of DataScienceCluster default-dsc",
+		"delete NodeFeatureDiscovery nfd-instance",
+		"delete Subscription openshift-nfd/nfd",
+		"delete ClusterServiceVersion openshift-nfd/nfd.4.16.0",
+		"delete OperatorGroup openshift-nfd/nfd",
+		"delete Namespace openshift-nfd",
+	}
+	actions, err := b.Uninstall(ctx, true)
+	require.NoError(t, err)
+	require.Equal(t, expected, actions)
+	_, err = subscriptions.Get(ctx, "nfd", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	actions, err = b.Uninstall(ctx, false)
+	require.NoError(t, err)
+	require.Equal(t, expected, actions)
+	gone := func(_ *unstructured.Unstructured, err error) {
+		require.True(t, errors.IsNotFound(err), err)
+	}
+	gone(subscriptions.Get(ctx, "nfd", metav1.GetOptions{}))
+	gone(dynamicClient.Resource(ClusterServiceVersionGVR).Namespace("openshift-nfd").Get(ctx, "nfd.4.16.0", metav1.GetOptions{}))
+	gone(dynamicClient.Resource(NodeFeatureDiscoveryGVR).Namespace("openshift-nfd").Get(ctx, "nfd-instance", metav1.GetOptions{}))
+	_, err = client.CoreV1().Namespaces().Get(ctx, "openshift-nfd", metav1.GetOptions{})
+	require.True(t, errors.IsNotFound(err), err)
+	// The GPU operator is kept
+	_, err = dynamicClient.Resource(SubscriptionGVR).Namespace("nvidia-gpu-operator").Get(ctx, "gpu-operator-certified", metav1.GetOptions{})
+	require.NoError(t, err)
+	_, err = client.CoreV1().Namespaces().Get(ctx, "nvidia-gpu-operator", metav1.GetOptions{})
+	require.NoError(t, err)
+	dsc, err = dynamicClient.Resource(DataScienceClusterGVR).Get(ctx, "default-dsc", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotContains(t, dsc.GetAnnotations(), PreviousComponentsAnnotation)
+	for component, expected := range map[string]string{"datasciencepipelines": "Managed", "trainingoperator": "Removed", "kserve": "Removed", "modelregistry": "Removed"} {
+		state, _, _ := unstructured.NestedString(dsc.Object, "spec", "components", component, "managementState")
+		require.Equal(t, expected, state, component)
+	}
+
+	actions, err = b.Uninstall(ctx, false)
+	require.NoError(t, err)
+	require.Empty(t, actions)
+}
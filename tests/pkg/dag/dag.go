@@ -0,0 +1,327 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dag describes a workflow as a DAG of phases exchanging named artifacts, and runs it with pluggable
+// executors. The dependencies of a phase are the phases producing its inputs, so a subgraph of the phases, e.g. for
+// a partial run, only needs the artifacts its phases do not produce as inputs. Executors run the phases on an
+// execution backend, FuncExecutor runs them as Go functions.
+package dag
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Phase is a step of a workflow, run once the phases producing its inputs succeeded
+type Phase struct {
+	Name    string
+	Inputs  []string
+	Outputs []string
+}
+
+// Graph is a validated DAG of phases
+type Graph struct {
+	// phases are in execution order
+	phases []Phase
+	// producers are the phases producing the artifacts, by artifact
+	producers map[string]string
+}
+
+// Artifacts are the locations of artifacts, by artifact name, in a form the executor producing or consuming them
+// understands, e.g. a path or a PVC
+type Artifacts map[string]string
+
+// State is the state of a phase in a run
+type State string
+
+const (
+	Pending   State = "Pending"
+	Running   State = "Running"
+	Succeeded State = "Succeeded"
+	Failed    State = "Failed"
+)
+
+// Status is the state of a phase reported by an executor while it runs a graph
+type Status struct {
+	Phase string
+	State State
+}
+
+// Executor runs the phases of a graph, in dependency order, on an execution backend
+type Executor interface {
+	// Execute runs the phases given the locations of the inputs of the graph and returns the locations of the
+	// outputs of the phases. The states of the phases are passed to report as the executor learns them.
+	Execute(ctx context.Context, graph *Graph, inputs Artifacts, report func(Status)) (Artifacts, error)
+}
+
+// New validates the phases: unique names, artifacts produced by one phase at most and no cycle. Phases are executed
+// in dependency order, phases ready at the same time in the order they are given.
+func New(phases []Phase) (*Graph, error) {
+	index := map[string]int{}
+	producers := map[string]string{}
+	for i, phase := range phases {
+		if phase.Name == "" {
+			return nil, fmt.Errorf("phase %d has no name", i)
+		}
+		if _, ok := index[phase.Name]; ok {
+			return nil, fmt.Errorf("phase %s is defined twice", phase.Name)
+		}
+		index[phase.Name] = i
+		for _, output := range phase.Outputs {
+			if producer, ok := producers[output]; ok {
+				return nil, fmt.Errorf("artifact %s is produced by phases %s and %s", output, producer, phase.Name)
+			}
+			producers[output] = phase.Name
+		}
+	}
+
+	// Kahn's algorithm, picking the first ready phase in the given order
+	pending := map[string]int{}
+	dependents := map[string][]string{}
+	for _, phase := range phases {
+		for _, upstream := range upstreamOf(phase, producers) {
+			if upstream == phase.Name {
+				return nil, fmt.Errorf("phase %s consumes its own output", phase.Name)
+			}
+			pending[phase.Name]++
+			dependents[upstream] = append(dependents[upstream], phase.Name)
+		}
+	}
+	var ready []int
+	for i, phase := range phases {
+		if pending[phase.Name] == 0 {
+			ready = append(ready, i)
+		}
+	}
+	var ordered []Phase
+	for len(ready) > 0 {
+		sort.Ints(ready)
+		next := phases[ready[0]]
+		ready = ready[1:]
+		ordered = append(ordered, next)
+		for _, dependent := range dependents[next.Name] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, index[dependent])
+			}
+		}
+	}
+	if len(ordered) < len(phases) {
+		var cycle []string
+		for _, phase := range phases {
+			if pending[phase.Name] > 0 {
+				cycle = append(cycle, phase.Name)
+			}
+		}
+		return nil, fmt.Errorf("phases %v depend on each other", cycle)
+	}
+	return &Graph{phases: ordered, producers: producers}, nil
+}
+
+// Phases returns the phases in execution order
+func (g *Graph) Phases() []Phase {
+	return append([]Phase(nil), g.phases...)
+}
+
+// Names returns the names of the phases in execution order
+func (g *Graph) Names() []string {
+	var names []string
+	for _, phase := range g.phases {
+		names = append(names, phase.Name)
+	}
+	return names
+}
+
+// Phase returns the named phase, reporting false when the graph does not hold it
+func (g *Graph) Phase(name string) (Phase, bool) {
+	for _, phase := range g.phases {
+		if phase.Name == name {
+			return phase, true
+		}
+	}
+	return Phase{}, false
+}
+
+// Upstream returns the phases of the graph producing the inputs of the named phase, in execution order
+func (g *Graph) Upstream(name string) []string {
+	phase, ok := g.Phase(name)
+	if !ok {
+		return nil
+	}
+	upstream := map[string]bool{}
+	for _, name := range upstreamOf(phase, g.producers) {
+		upstream[name] = true
+	}
+	var ordered []string
+	for _, p := range g.phases {
+		if upstream[p.Name] {
+			ordered = append(ordered, p.Name)
+		}
+	}
+	return ordered
+}
+
+// Inputs returns the artifacts the phases consume and no phase of the graph produces, sorted
+func (g *Graph) Inputs() []string {
+	inputs := map[string]bool{}
+	for _, phase := range g.phases {
+		for _, input := range phase.Inputs {
+			if _, ok := g.producers[input]; !ok {
+				inputs[input] = true
+			}
+		}
+	}
+	var sorted []string
+	for input := range inputs {
+		sorted = append(sorted, input)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// Select returns the subgraph of the named phases. The artifacts the other phases produce become inputs of the
+// subgraph, to be provided by an earlier run.
+func (g *Graph) Select(names ...string) (*Graph, error) {
+	selected := map[string]bool{}
+	for _, name := range names {
+		if _, ok := g.Phase(name); !ok {
+			return nil, fmt.Errorf("unknown phase %s", name)
+		}
+		selected[name] = true
+	}
+	var phases []Phase
+	for _, phase := range g.phases {
+		if selected[phase.Name] {
+			phases = append(phases, phase)
+		}
+	}
+	return New(phases)
+}
+
+// Range returns the subgraph of the phases depending on from, and of the phases to depends on, both included. An
+// empty from starts at the first phases and an empty to ends at the last phases, so Range("", "training-phase-1")
+// stops after training-phase-1 and Range("mt-bench", "") resumes at mt-bench.
+func (g *Graph) Range(from, to string) (*Graph, error) {
+	for _, name := range []string{from, to} {
+		if _, ok := g.Phase(name); name != "" && !ok {
+			return nil, fmt.Errorf("unknown phase %s", name)
+		}
+	}
+
+	downstream := map[string]bool{from: true}
+	for _, phase := range g.phases {
+		for _, upstream := range g.Upstream(phase.Name) {
+			if downstream[upstream] {
+				downstream[phase.Name] = true
+			}
+		}
+	}
+	upstream := map[string]bool{to: true}
+	for i := len(g.phases) - 1; i >= 0; i-- {
+		if upstream[g.phases[i].Name] {
+			for _, name := range g.Upstream(g.phases[i].Name) {
+				upstream[name] = true
+			}
+		}
+	}
+
+	var names []string
+	for _, phase := range g.phases {
+		if (from == "" || downstream[phase.Name]) && (to == "" || upstream[phase.Name]) {
+			names = append(names, phase.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("phase %s does not depend on phase %s", to, from)
+	}
+	return g.Select(names...)
+}
+
+// Run runs the graph with the executor after checking every input of the graph is provided, and checks every output
+// of the phases was produced
+func Run(ctx context.Context, executor Executor, graph *Graph, inputs Artifacts, report func(Status)) (Artifacts, error) {
+	for _, input := range graph.Inputs() {
+		if _, ok := inputs[input]; !ok {
+			return nil, fmt.Errorf("input %s is not provided", input)
+		}
+	}
+	outputs, err := executor.Execute(ctx, graph, inputs, report)
+	if err != nil {
+		return outputs, err
+	}
+	for _, phase := range graph.phases {
+		for _, output := range phase.Outputs {
+			if _, ok := outputs[output]; !ok {
+				return outputs, fmt.Errorf("phase %s did not produce %s", phase.Name, output)
+			}
+		}
+	}
+	return outputs, nil
+}
+
+// Func runs a phase given the locations of its inputs and returns the locations of its outputs
+type Func func(ctx context.Context, inputs Artifacts) (Artifacts, error)
+
+// FuncExecutor runs the phases as Go functions in the calling process, by phase name, one after the other. It runs
+// the phases calling APIs directly, and stands in for an execution backend in tests.
+type FuncExecutor map[string]Func
+
+// Execute runs the function of every phase in execution order, stopping at the first failure
+func (e FuncExecutor) Execute(ctx context.Context, graph *Graph, inputs Artifacts, report func(Status)) (Artifacts, error) {
+	for _, phase := range graph.phases {
+		if _, ok := e[phase.Name]; !ok {
+			return nil, fmt.Errorf("no function for phase %s", phase.Name)
+		}
+	}
+
+	available := Artifacts{}
+	for name, location := range inputs {
+		available[name] = location
+	}
+	outputs := Artifacts{}
+	for _, phase := range graph.phases {
+		if err := ctx.Err(); err != nil {
+			return outputs, err
+		}
+		phaseInputs := Artifacts{}
+		for _, input := range phase.Inputs {
+			phaseInputs[input] = available[input]
+		}
+		report(Status{Phase: phase.Name, State: Running})
+		produced, err := e[phase.Name](ctx, phaseInputs)
+		if err != nil {
+			report(Status{Phase: phase.Name, State: Failed})
+			return outputs, fmt.Errorf("phase %s failed: %w", phase.Name, err)
+		}
+		for name, location := range produced {
+			available[name] = location
+			outputs[name] = location
+		}
+		report(Status{Phase: phase.Name, State: Succeeded})
+	}
+	return outputs, nil
+}
+
+// upstreamOf returns the producers of the inputs of a phase, without duplicates
+func upstreamOf(phase Phase, producers map[string]string) []string {
+	seen := map[string]bool{}
+	var upstream []string
+	for _, input := range phase.Inputs {
+		if producer, ok := producers[input]; ok && !seen[producer] {
+			seen[producer] = true
+			upstream = append(upstream, producer)
+		}
+	}
+	return upstream
+}
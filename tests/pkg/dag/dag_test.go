@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dag
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testGraph(t *testing.T) *Graph {
+	graph, err := New([]Phase{
+		{Name: "train", Inputs: []string{"data", "model"}, Outputs: []string{"checkpoints"}},
+		{Name: "sdg", Inputs: []string{"taxonomy"}, Outputs: []string{"data"}},
+		{Name: "eval", Inputs: []string{"checkpoints"}, Outputs: []string{"report"}},
+		{Name: "lint", Inputs: []string{"taxonomy"}},
+	})
+	require.NoError(t, err)
+	return graph
+}
+
+func TestNew(t *testing.T) {
+	graph := testGraph(t)
+	require.Equal(t, []string{"sdg", "train", "eval", "lint"}, graph.Names())
+	require.Equal(t, []string{"sdg"}, graph.Upstream("train"))
+	require.Empty(t, graph.Upstream("sdg"))
+	require.Equal(t, []string{"model", "taxonomy"}, graph.Inputs())
+
+	_, err := New([]Phase{{Name: "a", Outputs: []string{"x"}}, {Name: "b", Outputs: []string{"x"}}})
+	require.EqualError(t, err, "artifact x is produced by phases a and b")
+	_, err = New([]Phase{{Name: "a", Inputs: []string{"y"}, Outputs: []string{"x"}}, {Name: "b", Inputs: []string{"x"}, Outputs: []string{"y"}}})
+	require.EqualError(t, err, "phases [a b] depend on each other")
+	_, err = New([]Phase{{Name: "a"}, {Name: "a"}})
+	require.EqualError(t, err, "phase a is defined twice")
+}
+
+func TestSelectAndRange(t *testing.T) {
+	graph := testGraph(t)
+
+	eval, err := graph.Select("eval")
+	require.NoError(t, err)
+	require.Equal(t, []string{"checkpoints"}, eval.Inputs())
+
+	untilTrain, err := graph.Range("", "train")
+	require.NoError(t, err)
+	require.Equal(t, []string{"sdg", "train"}, untilTrain.Names())
+
+	fromTrain, err := graph.Range("train", "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"train", "eval"}, fromTrain.Names())
+	require.Equal(t, []string{"data", "model"}, fromTrain.Inputs())
+
+	_, err = graph.Range("eval", "sdg")
+	require.EqualError(t, err, "phase sdg does not depend on phase eval")
+	_, err = graph.Select("deploy")
+	require.EqualError(t, err, "unknown phase deploy")
+}
+
+func TestRunFuncExecutor(t *testing.T) {
+	graph := testGraph(t)
+	var states []Status
+	report := func(status Status) { states = append(states, status) }
+	executor := FuncExecutor{
+		"sdg": func(ctx context.Context, inputs Artifacts) (Artifacts, error) {
+			return Artifacts{"data": inputs["taxonomy"] + "/sdg"}, nil
+		},
+		"train": func(ctx context.Context, inputs Artifacts) (Artifacts, error) {
+			require.Equal(t, Artifacts{"data": "/taxonomy/sdg", "model": "/granite"}, inputs)
+			return Artifacts{"checkpoints": "/checkpoints"}, nil
+		},
+		"eval": func(ctx context.Context, inputs Artifacts) (Artifacts, error) {
+			return Artifacts{"report": inputs["checkpoints"] + "/report.json"}, nil
+		},
+		"lint": func(ctx context.Context, inputs Artifacts) (Artifacts, error) { return nil, nil },
+	}
+
+	_, err := Run(context.Background(), executor, graph, Artifacts{"taxonomy": "/taxonomy"}, report)
+	require.EqualError(t, err, "input model is not provided")
+
+	outputs, err := Run(context.Background(), executor, graph, Artifacts{"taxonomy": "/taxonomy", "model": "/granite"}, report)
+	require.NoError(t, err)
+	require.Equal(t, Artifacts{"data": "/taxonomy/sdg", "checkpoints": "/checkpoints", "report": "/checkpoints/report.json"}, outputs)
+	require.Equal(t, Status{Phase: "sdg", State: Running}, states[0])
+	require.Equal(t, Status{Phase: "lint", State: Succeeded}, states[len(states)-1])
+
+	executor["eval"] = func(ctx context.Context, inputs Artifacts) (Artifacts, error) {
+		return nil, errors.New("judge unreachable")
+	}
+	_, err = Run(context.Background(), executor, graph, Artifacts{"taxonomy": "/taxonomy", "model": "/granite"}, report)
+	require.EqualError(t, err, "phase eval failed: judge unreachable")
+	require.Equal(t, Status{Phase: "eval", State: Failed}, states[len(states)-1])
+
+	executor["eval"] = func(ctx context.Context, inputs Artifacts) (Artifacts, error) { return nil, nil }
+	_, err = Run(context.Background(), executor, graph, Artifacts{"taxonomy": "/taxonomy", "model": "/granite"}, report)
+	require.EqualError(t, err, "phase eval did not produce report")
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package demotaxonomy ships the tiny taxonomy of the demo scenario, two skills with their seed examples, served as a
+// git repository so the demo depends on no taxonomy repository. Skills only, as knowledge leaves need a document
+// repository.
+package demotaxonomy
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/gitserve"
+	"sigs.k8s.io/yaml"
+)
+
+//go:embed taxonomy
+var files embed.FS
+
+// Signature is the author of the commit of the taxonomy, fixed so every server serves the same commit
+var Signature = gitserve.Signature{Name: "ilab-on-ocp", Email: "ilab-on-ocp@opendatahub.io", When: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+// Files returns the files of the taxonomy from its root
+func Files() fs.FS {
+	root, err := fs.Sub(files, "taxonomy")
+	if err != nil {
+		panic(err)
+	}
+	return root
+}
+
+// Repository returns the taxonomy committed to a git repository
+func Repository() (*gitserve.Repository, error) {
+	return gitserve.NewRepository(Files(), Signature, "Demo taxonomy")
+}
+
+// Leaf is a skill of the taxonomy
+type Leaf struct {
+	// Path is the directory of the leaf from the taxonomy root
+	Path            string
+	TaskDescription string
+	SeedExamples    int
+	// Grounded skills give a context with every seed example
+	Grounded bool
+}
+
+type qna struct {
+	Version         int    `json:"version"`
+	CreatedBy       string `json:"created_by"`
+	TaskDescription string `json:"task_description"`
+	SeedExamples    []struct {
+		Context  string `json:"context"`
+		Question string `json:"question"`
+		Answer   string `json:"answer"`
+	} `json:"seed_examples"`
+}
+
+// Leaves returns the skills of the taxonomy, by path
+func Leaves() ([]Leaf, error) {
+	var leaves []Leaf
+	err := fs.WalkDir(Files(), ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.Name() != "qna.yaml" {
+			return err
+		}
+		data, err := fs.ReadFile(Files(), name)
+		if err != nil {
+			return err
+		}
+		var leaf qna
+		if err := yaml.UnmarshalStrict(data, &leaf); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		grounded := len(leaf.SeedExamples) > 0
+		for _, example := range leaf.SeedExamples {
+			grounded = grounded && example.Context != ""
+		}
+		leaves = append(leaves, Leaf{Path: path.Dir(name), TaskDescription: leaf.TaskDescription, SeedExamples: len(leaf.SeedExamples), Grounded: grounded})
+		return nil
+	})
+	sort.Slice(leaves, func(i, j int) bool { return leaves[i].Path < leaves[j].Path })
+	return leaves, err
+}
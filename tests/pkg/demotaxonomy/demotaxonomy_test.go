@@ -0,0 +1,42 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package demotaxonomy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaves(t *testing.T) {
+	leaves, err := Leaves()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"compositional_skills/technology/openshift/oc_commands",
+		"compositional_skills/writing/grounded/summarization/release_notes",
+	}, []string{leaves[0].Path, leaves[1].Path})
+	require.False(t, leaves[0].Grounded)
+	require.True(t, leaves[1].Grounded)
+	for _, leaf := range leaves {
+		// SDG rejects skills with fewer than 5 seed examples
+		require.GreaterOrEqual(t, leaf.SeedExamples, 5, leaf.Path)
+		require.NotEmpty(t, leaf.TaskDescription, leaf.Path)
+	}
+
+	repo, err := Repository()
+	require.NoError(t, err)
+	again, err := Repository()
+	require.NoError(t, err)
+	require.Equal(t, repo.Commit(), again.Commit())
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package egressprobe checks the endpoints a run reaches, e.g. the teacher, the judge and the object store, can be
+// reached from a pod of the cluster, and finds the address the pod egresses with, so firewall allow-lists can be set
+// up before the run
+package egressprobe
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Target is an endpoint of the run
+type Target struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// ParseTarget reads a target given as name=url
+func ParseTarget(value string) (Target, error) {
+	name, endpoint, ok := strings.Cut(value, "=")
+	if !ok || name == "" || endpoint == "" {
+		return Target{}, fmt.Errorf("invalid target '%s', expected name=url", value)
+	}
+	return Target{Name: name, URL: endpoint}, nil
+}
+
+// TargetResult is the connectivity to a target. Any HTTP response, an error status included, shows the firewall
+// lets the traffic through.
+type TargetResult struct {
+	Target
+	// Addresses are the addresses the host of the target resolves to
+	Addresses []string      `json:"addresses,omitempty"`
+	Connected bool          `json:"connected"`
+	Latency   time.Duration `json:"latency,omitempty"`
+	Status    int           `json:"status,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// Result is the outcome of a probe
+type Result struct {
+	// EgressIP is the address the echo service saw the probe come from, empty without an echo service
+	EgressIP  string         `json:"egress_ip,omitempty"`
+	EchoError string         `json:"echo_error,omitempty"`
+	Targets   []TargetResult `json:"targets"`
+}
+
+// Unreachable returns the targets the probe could not connect to
+func (r Result) Unreachable() []TargetResult {
+	var unreachable []TargetResult
+	for _, target := range r.Targets {
+		if !target.Connected {
+			unreachable = append(unreachable, target)
+		}
+	}
+	return unreachable
+}
+
+// Probe resolves and connects to every target, then sends it an HTTP request, each within timeout. When echoURL is
+// set, the egress address is read from the response of the echo service, a plain text address such as the one of
+// https://api.ipify.org.
+func Probe(ctx context.Context, targets []Target, echoURL string, timeout time.Duration) Result {
+	// The TLS handshake is part of the connectivity, the certificates are checked by the validation of the endpoints
+	client := &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+	}
+	var result Result
+	if echoURL != "" {
+		result.EgressIP, result.EchoError = echo(ctx, client, echoURL)
+	}
+	for _, target := range targets {
+		result.Targets = append(result.Targets, probeTarget(ctx, client, target, timeout))
+	}
+	return result
+}
+
+func probeTarget(ctx context.Context, client *http.Client, target Target, timeout time.Duration) TargetResult {
+	result := TargetResult{Target: target}
+	parsed, err := url.Parse(target.URL)
+	if err != nil || parsed.Host == "" {
+		result.Error = fmt.Sprintf("invalid URL '%s'", target.URL)
+		return result
+	}
+	port := parsed.Port()
+	if port == "" {
+		port = "443"
+		if parsed.Scheme == "http" {
+			port = "80"
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	addresses, err := net.DefaultResolver.LookupHost(ctx, parsed.Hostname())
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to resolve %s: %v", parsed.Hostname(), err)
+		return result
+	}
+	result.Addresses = addresses
+
+	start := time.Now()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(parsed.Hostname(), port))
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to connect to %s: %v", net.JoinHostPort(parsed.Hostname(), port), err)
+		return result
+	}
+	result.Latency = time.Since(start)
+	conn.Close()
+	result.Connected = true
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, target.URL, nil)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		// A proxy or a firewall inspecting the traffic may let the connection through and drop the request
+		result.Error = fmt.Sprintf("connected, but the HTTP request failed: %v", err)
+		return result
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 1<<20))
+	result.Status = response.StatusCode
+	return result
+}
+
+func echo(ctx context.Context, client *http.Client, echoURL string) (string, string) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, echoURL, nil)
+	if err != nil {
+		return "", err.Error()
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return "", err.Error()
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(response.Body, 256))
+	if err != nil {
+		return "", err.Error()
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Sprintf("status %d from %s", response.StatusCode, echoURL)
+	}
+	address := strings.TrimSpace(string(body))
+	if net.ParseIP(address) == nil {
+		return "", fmt.Sprintf("%s did not answer with an address: %q", echoURL, address)
+	}
+	return address, ""
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package egressprobe
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ip":
+			_, _ = w.Write([]byte("203.0.113.7\n"))
+		case "/html":
+			_, _ = w.Write([]byte("<html>203.0.113.7</html>"))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+	// A listener closed right away gives a port nothing listens on
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closed := "http://" + listener.Addr().String()
+	listener.Close()
+
+	result := Probe(context.Background(), []Target{
+		{Name: "judge", URL: server.URL + "/v1"},
+		{Name: "teacher", URL: closed + "/v1"},
+		{Name: "object-store", URL: "s3"},
+	}, server.URL+"/ip", 5*time.Second)
+	require.Equal(t, "203.0.113.7", result.EgressIP)
+	require.Len(t, result.Targets, 3)
+
+	// An error status still shows the endpoint is reachable
+	judge := result.Targets[0]
+	require.True(t, judge.Connected)
+	require.Equal(t, http.StatusUnauthorized, judge.Status)
+	require.Equal(t, []string{"127.0.0.1"}, judge.Addresses)
+	require.Empty(t, judge.Error)
+
+	require.False(t, result.Targets[1].Connected)
+	require.Contains(t, result.Targets[1].Error, "failed to connect to "+listener.Addr().String())
+	require.Equal(t, "invalid URL 's3'", result.Targets[2].Error)
+	require.Equal(t, []string{"teacher", "object-store"}, []string{result.Unreachable()[0].Name, result.Unreachable()[1].Name})
+
+	result = Probe(context.Background(), nil, server.URL+"/html", 5*time.Second)
+	require.Empty(t, result.EgressIP)
+	require.Contains(t, result.EchoError, "did not answer with an address")
+
+	_, err = ParseTarget("judge")
+	require.EqualError(t, err, "invalid target 'judge', expected name=url")
+	target, err := ParseTarget("judge=https://judge.example.com/v1?a=b")
+	require.NoError(t, err)
+	require.Equal(t, Target{Name: "judge", URL: "https://judge.example.com/v1?a=b"}, target)
+}
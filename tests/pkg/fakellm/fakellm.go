@@ -0,0 +1,274 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fakellm implements a stub OpenAI-compatible server with canned deterministic responses, standing in for
+// the teacher and judge model servers in mock runs
+package fakellm
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultResponse satisfies the rating format expected from the judge
+const DefaultResponse = "Rating: [[5]]"
+
+// Rule answers the prompts containing Match with one of its responses
+type Rule struct {
+	Match     string
+	Responses []string
+}
+
+// DefaultRules answer the prompt types of the SDG pipelines, recognized by the output tags the prompts ask for, with
+// completions their parsers and filters accept. Evaluation prompts quote the generated text, so they are matched
+// before the generation prompts.
+var DefaultRules = []Rule{
+	// Relevancy of knowledge answers and quality of skill question/answer pairs, kept at a score of 2 or more
+	{Match: "[Start of Score]", Responses: []string{"[Start of Feedback]\nThe answer addresses the question.\n[End of Feedback]\n[Start of Score]\n2\n[End of Score]"}},
+	// Quality of generated questions, kept at a rating of 1
+	{Match: "[Start of Rating]", Responses: []string{"[Start of Explanation]\nThe question is clear and answerable.\n[End of Explanation]\n[Start of Rating]\n1.0\n[End of Rating]"}},
+	// Faithfulness of knowledge answers to the document, kept when the answer is YES
+	{Match: "[Start of Answer]", Responses: []string{"[Start of Explanation]\nThe answer is supported by the document.\n[End of Explanation]\n[Start of Answer]\nYES\n[End of Answer]"}},
+	// Responses to generated skill questions
+	{Match: "[Start of Response]", Responses: []string{"[Start of Response]\nThis is a stub response.\n[End of Response]"}},
+	// Skill questions
+	{Match: "[Start of Question]", Responses: []string{"[Start of Question]\nWhat does the stub answer?\n[End of Question]"}},
+	// Knowledge question/answer pairs
+	{Match: "[QUESTION]", Responses: []string{"[QUESTION]\nWhat does the document describe?\n[ANSWER]\nThe document describes a stub.\n[END]"}},
+}
+
+// Config holds the canned responses and the latency and error injection knobs
+type Config struct {
+	ModelName string
+	// Rules pick the canned completions by prompt type, the first rule matching the content of a request applies
+	Rules []Rule
+	// Responses are the canned completions of the requests no rule matches, one is picked deterministically from the
+	// content of each request
+	Responses []string
+	// Latency delays every completion
+	Latency time.Duration
+	// ErrorRate is the fraction of completions failed with ErrorStatus, spread evenly over the requests
+	ErrorRate   float64
+	ErrorStatus int
+	// APIKey, when set, must be sent as bearer token
+	APIKey string
+}
+
+// Server serves /v1/models, /v1/chat/completions, /v1/completions and /health
+type Server struct {
+	config Config
+	mux    *http.ServeMux
+	mutex  sync.Mutex
+	count  int
+}
+
+// NewServer creates a server with the given configuration, using defaults for the unset fields
+func NewServer(config Config) *Server {
+	if config.ModelName == "" {
+		config.ModelName = "fake"
+	}
+	if len(config.Responses) == 0 {
+		config.Responses = []string{DefaultResponse}
+	}
+	if config.ErrorStatus == 0 {
+		config.ErrorStatus = http.StatusInternalServerError
+	}
+
+	s := &Server{config: config, mux: http.NewServeMux()}
+	s.mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {})
+	s.mux.HandleFunc("/v1/models", s.authorized(s.models))
+	s.mux.HandleFunc("/v1/chat/completions", s.authorized(s.completion(true)))
+	s.mux.HandleFunc("/v1/completions", s.authorized(s.completion(false)))
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// completionRequest holds the fields of chat and text completion requests the server uses
+type completionRequest struct {
+	Messages []struct {
+		Content interface{} `json:"content"`
+	} `json:"messages"`
+	Prompt interface{} `json:"prompt"`
+	N      int         `json:"n"`
+	Stream bool        `json:"stream"`
+}
+
+func (s *Server) models(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"object": "list",
+		"data":   []interface{}{map[string]interface{}{"id": s.config.ModelName, "object": "model", "owned_by": "fakellm"}},
+	})
+}
+
+func (s *Server) completion(chat bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		var request completionRequest
+		if err := json.Unmarshal(body, &request); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request: "+err.Error())
+			return
+		}
+
+		time.Sleep(s.config.Latency)
+		if s.injectError() {
+			writeError(w, s.config.ErrorStatus, "injected error")
+			return
+		}
+
+		prompt := request.promptText()
+		content := s.Response(prompt)
+		n := request.N
+		if n < 1 {
+			n = 1
+		}
+		if request.Stream {
+			s.stream(w, chat, content, n)
+			return
+		}
+
+		var choices []interface{}
+		for i := 0; i < n; i++ {
+			if chat {
+				choices = append(choices, map[string]interface{}{
+					"index":         i,
+					"message":       map[string]interface{}{"role": "assistant", "content": content},
+					"finish_reason": "stop",
+				})
+			} else {
+				choices = append(choices, map[string]interface{}{"index": i, "text": content, "finish_reason": "stop"})
+			}
+		}
+		object := "text_completion"
+		if chat {
+			object = "chat.completion"
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"id":      "fakellm",
+			"object":  object,
+			"created": time.Now().Unix(),
+			"model":   s.config.ModelName,
+			"choices": choices,
+			"usage":   usage(prompt, content, n),
+		})
+	}
+}
+
+// stream answers with server-sent events: one chunk per choice, a final chunk with the usage, then [DONE]
+func (s *Server) stream(w http.ResponseWriter, chat bool, content string, n int) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+	object := "text_completion"
+	if chat {
+		object = "chat.completion.chunk"
+	}
+	for i := 0; i < n; i++ {
+		choice := map[string]interface{}{"index": i, "text": content, "finish_reason": "stop"}
+		if chat {
+			choice = map[string]interface{}{"index": i, "delta": map[string]interface{}{"role": "assistant", "content": content}, "finish_reason": "stop"}
+		}
+		writeEvent(w, map[string]interface{}{"id": "fakellm", "object": object, "model": s.config.ModelName, "choices": []interface{}{choice}})
+	}
+	writeEvent(w, map[string]interface{}{"id": "fakellm", "object": object, "model": s.config.ModelName, "choices": []interface{}{}, "usage": usage("", content, n)})
+	fmt.Fprint(w, "data: [DONE]\n\n")
+}
+
+// Response returns the canned response for a prompt, always the same one for the same prompt
+func (s *Server) Response(prompt string) string {
+	responses := s.config.Responses
+	for _, rule := range s.config.Rules {
+		if strings.Contains(prompt, rule.Match) && len(rule.Responses) > 0 {
+			responses = rule.Responses
+			break
+		}
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(prompt))
+	return responses[int(hash.Sum32()%uint32(len(responses)))]
+}
+
+// injectError decides deterministically whether the next completion fails, spreading the errors evenly
+func (s *Server) injectError() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.count++
+	return int(float64(s.count)*s.config.ErrorRate) != int(float64(s.count-1)*s.config.ErrorRate)
+}
+
+func (s *Server) authorized(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.config.APIKey != "" && r.Header.Get("Authorization") != "Bearer "+s.config.APIKey {
+			writeError(w, http.StatusUnauthorized, "invalid API key")
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// promptText concatenates the text of the messages or the prompt of the request
+func (r completionRequest) promptText() string {
+	var parts []string
+	for _, message := range r.Messages {
+		parts = append(parts, fmt.Sprint(message.Content))
+	}
+	if r.Prompt != nil {
+		parts = append(parts, fmt.Sprint(r.Prompt))
+	}
+	return strings.Join(parts, "\n")
+}
+
+// usage approximates token counts with four bytes per token
+func usage(prompt, content string, n int) map[string]int {
+	promptTokens, completionTokens := len(prompt)/4, n*len(content)/4
+	return map[string]int{
+		"prompt_tokens":     promptTokens,
+		"completion_tokens": completionTokens,
+		"total_tokens":      promptTokens + completionTokens,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"error": map[string]interface{}{"message": message, "type": "fakellm_error", "code": status},
+	})
+}
+
+func writeEvent(w http.ResponseWriter, event interface{}) {
+	data, _ := json.Marshal(event)
+	fmt.Fprintf(w, "data: %s\n\n", data)
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakellm
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func post(t *testing.T, url, apiKey, body string) (int, string) {
+	req, err := http.NewRequest("POST", url, strings.NewReader(body))
+	require.NoError(t, err)
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(data)
+}
+
+func TestChatCompletions(t *testing.T) {
+	server := httptest.NewServer(NewServer(Config{ModelName: "judge", Responses: []string{"one", "two", "three"}}))
+	defer server.Close()
+
+	request := `{"model":"judge","messages":[{"role":"user","content":"Rate this answer"}],"n":2}`
+	status, body := post(t, server.URL+"/v1/chat/completions", "", request)
+	require.Equal(t, http.StatusOK, status)
+
+	var response struct {
+		Model   string `json:"model"`
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens int `json:"prompt_tokens"`
+		} `json:"usage"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &response))
+	require.Equal(t, "judge", response.Model)
+	require.Len(t, response.Choices, 2)
+	require.Contains(t, []string{"one", "two", "three"}, response.Choices[0].Message.Content)
+	require.Equal(t, 4, response.Usage.PromptTokens)
+
+	// The same prompt always gets the same response
+	_, again := post(t, server.URL+"/v1/chat/completions", "", request)
+	require.JSONEq(t, withoutCreated(t, body), withoutCreated(t, again))
+}
+
+func TestStreamAndModels(t *testing.T) {
+	server := httptest.NewServer(NewServer(Config{}))
+	defer server.Close()
+
+	status, body := post(t, server.URL+"/v1/chat/completions", "", `{"messages":[{"role":"user","content":"hi"}],"stream":true}`)
+	require.Equal(t, http.StatusOK, status)
+	require.Contains(t, body, `"content":"Rating: [[5]]"`)
+	require.True(t, strings.HasSuffix(body, "data: [DONE]\n\n"))
+
+	resp, err := http.Get(server.URL + "/v1/models")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	require.Contains(t, string(data), `"id":"fake"`)
+}
+
+func TestInjection(t *testing.T) {
+	server := httptest.NewServer(NewServer(Config{ErrorRate: 0.5, ErrorStatus: http.StatusTooManyRequests, Latency: 10 * time.Millisecond, APIKey: "secret"}))
+	defer server.Close()
+
+	status, _ := post(t, server.URL+"/v1/completions", "wrong", `{"prompt":"hi"}`)
+	require.Equal(t, http.StatusUnauthorized, status)
+
+	var statuses []int
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		status, _ := post(t, server.URL+"/v1/completions", "secret", `{"prompt":"hi"}`)
+		statuses = append(statuses, status)
+	}
+	require.Equal(t, []int{http.StatusOK, http.StatusTooManyRequests, http.StatusOK, http.StatusTooManyRequests}, statuses)
+	require.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+}
+
+func TestRules(t *testing.T) {
+	server := NewServer(Config{Rules: DefaultRules})
+	require.Contains(t, server.Response("Rate the question. [Start of Question]\nWhy?\n[End of Question] Answer with [Start of Rating]"), "[Start of Rating]\n1.0\n[End of Rating]")
+	require.Contains(t, server.Response("Write a response to [Start of Question]\nWhy?\n[End of Question] as [Start of Response]"), "[Start of Response]")
+	require.Contains(t, server.Response("Generate questions as [Start of Question]"), "[End of Question]")
+	require.Contains(t, server.Response("Answer with [QUESTION] and [ANSWER] tags"), "[ANSWER]")
+	require.Equal(t, DefaultResponse, server.Response(`Reply strictly in the format "[[rating]]"`))
+}
+
+func withoutCreated(t *testing.T, body string) string {
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(body), &response))
+	delete(response, "created")
+	data, _ := json.Marshal(response)
+	return string(data)
+}
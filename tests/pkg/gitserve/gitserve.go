@@ -0,0 +1,176 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gitserve serves a tree of files as a git repository over the dumb HTTP protocol, which git clone falls back
+// to when the server does not speak the smart protocol, so a pipeline task can clone files shipped with the tests
+// without a git server
+package gitserve
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Branch is the branch holding the commit, the HEAD of the repository
+const Branch = "main"
+
+// Signature is the author and committer of the commit. A fixed time gives the same commit for the same files.
+type Signature struct {
+	Name  string
+	Email string
+	When  time.Time
+}
+
+// Repository is an in-memory git repository holding a single commit of a tree of files
+type Repository struct {
+	commit string
+	// objects are the compressed loose objects by ID
+	objects map[string][]byte
+}
+
+// NewRepository commits the files to a new repository, leaving out the empty directories git cannot hold
+func NewRepository(files fs.FS, signature Signature, message string) (*Repository, error) {
+	repo := &Repository{objects: map[string][]byte{}}
+	tree, err := repo.writeTree(files, ".")
+	if err != nil {
+		return nil, err
+	}
+	if tree == "" {
+		return nil, fmt.Errorf("no files to commit")
+	}
+	person := fmt.Sprintf("%s <%s> %d +0000", signature.Name, signature.Email, signature.When.Unix())
+	commit := fmt.Sprintf("tree %s\nauthor %s\ncommitter %s\n\n%s\n", tree, person, person, strings.TrimRight(message, "\n"))
+	repo.commit, err = repo.write("commit", []byte(commit))
+	if err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// Commit returns the ID of the commit
+func (r *Repository) Commit() string {
+	return r.commit
+}
+
+func (r *Repository) writeTree(files fs.FS, dir string) (string, error) {
+	entries, err := fs.ReadDir(files, dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	type treeEntry struct {
+		mode, name, id string
+	}
+	var tree []treeEntry
+	for _, entry := range entries {
+		name := path.Join(dir, entry.Name())
+		switch {
+		case entry.IsDir():
+			id, err := r.writeTree(files, name)
+			if err != nil {
+				return "", err
+			}
+			if id != "" {
+				tree = append(tree, treeEntry{"40000", entry.Name(), id})
+			}
+		case entry.Type().IsRegular():
+			content, err := fs.ReadFile(files, name)
+			if err != nil {
+				return "", fmt.Errorf("failed to read %s: %w", name, err)
+			}
+			id, err := r.write("blob", content)
+			if err != nil {
+				return "", err
+			}
+			tree = append(tree, treeEntry{"100644", entry.Name(), id})
+		}
+	}
+	if len(tree) == 0 {
+		return "", nil
+	}
+
+	// Git orders the entries by name, directories as if their name ended with a slash
+	sortName := func(entry treeEntry) string {
+		if entry.mode == "40000" {
+			return entry.name + "/"
+		}
+		return entry.name
+	}
+	sort.Slice(tree, func(i, j int) bool { return sortName(tree[i]) < sortName(tree[j]) })
+	var content bytes.Buffer
+	for _, entry := range tree {
+		id, _ := hex.DecodeString(entry.id)
+		fmt.Fprintf(&content, "%s %s\x00", entry.mode, entry.name)
+		content.Write(id)
+	}
+	return r.write("tree", content.Bytes())
+}
+
+// write stores an object and returns its ID, the SHA-1 of its header and content
+func (r *Repository) write(kind string, content []byte) (string, error) {
+	object := append([]byte(fmt.Sprintf("%s %d\x00", kind, len(content))), content...)
+	sum := sha1.Sum(object)
+	id := hex.EncodeToString(sum[:])
+
+	var compressed bytes.Buffer
+	writer := zlib.NewWriter(&compressed)
+	if _, err := writer.Write(object); err != nil {
+		return "", fmt.Errorf("failed to compress %s %s: %w", kind, id, err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to compress %s %s: %w", kind, id, err)
+	}
+	r.objects[id] = compressed.Bytes()
+	return id, nil
+}
+
+// Handler serves the repositories under /<name>.git/ over the dumb HTTP protocol
+func Handler(repositories map[string]*Repository) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, file, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), ".git/")
+		repo := repositories[name]
+		if !ok || repo == nil || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+			http.NotFound(w, r)
+			return
+		}
+		switch {
+		case file == "info/refs":
+			// Answering the smart protocol discovery in plain text makes git fall back to the dumb protocol
+			w.Header().Set("Content-Type", "text/plain")
+			fmt.Fprintf(w, "%s\trefs/heads/%s\n", repo.commit, Branch)
+		case file == "HEAD":
+			w.Header().Set("Content-Type", "text/plain")
+			fmt.Fprintf(w, "ref: refs/heads/%s\n", Branch)
+		case file == "objects/info/packs":
+			w.Header().Set("Content-Type", "text/plain")
+		case strings.HasPrefix(file, "objects/"):
+			object, ok := repo.objects[strings.Replace(strings.TrimPrefix(file, "objects/"), "/", "", 1)]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "application/x-git-loose-object")
+			_, _ = w.Write(object)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
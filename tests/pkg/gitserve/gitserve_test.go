@@ -0,0 +1,74 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitserve
+
+import (
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServeRepository(t *testing.T) {
+	files := fstest.MapFS{
+		"README.md":                               {Data: []byte("# Taxonomy\n")},
+		"compositional_skills/writing/qna.yaml":   {Data: []byte("version: 3\n")},
+		"compositional_skills/writing.md":         {Data: []byte("ordered before the directory\n")},
+		"compositional_skills/empty/.placeholder": {Data: nil},
+	}
+	signature := Signature{Name: "demo", Email: "demo@example.com", When: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	repo, err := NewRepository(files, signature, "Demo taxonomy")
+	require.NoError(t, err)
+	again, err := NewRepository(files, signature, "Demo taxonomy")
+	require.NoError(t, err)
+	require.Equal(t, repo.Commit(), again.Commit(), "the same files and signature must give the same commit")
+
+	_, err = NewRepository(fstest.MapFS{}, signature, "Empty")
+	require.EqualError(t, err, "no files to commit")
+
+	git, err := exec.LookPath("git")
+	if err != nil {
+		t.Skip("git is not installed, the repository is not cloned")
+	}
+	server := httptest.NewServer(Handler(map[string]*Repository{"taxonomy": repo}))
+	defer server.Close()
+
+	dir := filepath.Join(t.TempDir(), "taxonomy")
+	clone := exec.Command(git, "clone", server.URL+"/taxonomy.git", dir)
+	clone.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	output, err := clone.CombinedOutput()
+	require.NoError(t, err, "git clone failed: %s", output)
+
+	content, err := os.ReadFile(filepath.Join(dir, "compositional_skills", "writing", "qna.yaml"))
+	require.NoError(t, err)
+	require.Equal(t, "version: 3\n", string(content))
+	head, err := exec.Command(git, "-C", dir, "rev-parse", "HEAD").Output()
+	require.NoError(t, err)
+	require.Equal(t, repo.Commit(), strings.TrimSpace(string(head)))
+	branch, err := exec.Command(git, "-C", dir, "branch", "--show-current").Output()
+	require.NoError(t, err)
+	require.Equal(t, Branch, strings.TrimSpace(string(branch)))
+	output, err = exec.Command(git, "-C", dir, "fsck", "--strict").CombinedOutput()
+	require.NoError(t, err, "git fsck failed: %s", output)
+
+	clone = exec.Command(git, "clone", server.URL+"/missing.git", filepath.Join(t.TempDir(), "missing"))
+	clone.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	require.Error(t, clone.Run())
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpuqueue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/clock"
+)
+
+// Request describes the Lease to acquire and the queue entry waiting for it
+type Request struct {
+	Namespace string
+	Name      string
+	// GenerateName is the generateName of the queue entry
+	GenerateName string
+	Holder       string
+	Team         string
+	Priority     int
+	// Takeover is the time after which a holder or a waiting run that stopped renewing is presumed crashed
+	Takeover time.Duration
+	Timeout  time.Duration
+	// Clock times the polls and the renewals, the wall clock when nil
+	Clock clock.Clock
+}
+
+// Held is a Lease acquired from the queue, renewed in the background until released
+type Held struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+	holder    string
+	clock     clock.Clock
+	done      chan struct{}
+}
+
+// LeaseAvailable reports whether the Lease can be taken by holder: it is free, already held by holder, or its holder
+// has not renewed it within the takeover timeout and is presumed crashed
+func LeaseAvailable(lease *coordinationv1.Lease, holder string, now time.Time, takeover time.Duration) bool {
+	current := lease.Spec.HolderIdentity
+	if current == nil || *current == "" || *current == holder {
+		return true
+	}
+	renewed := lease.Spec.RenewTime
+	if renewed == nil {
+		renewed = lease.Spec.AcquireTime
+	}
+	return renewed == nil || now.Sub(renewed.Time) > takeover
+}
+
+// Acquire queues for the Lease and takes it once it is available and the request is first in the queue. The Lease is
+// renewed until released, so that it is only taken over from crashed holders. Progress is reported to logf.
+func Acquire(ctx context.Context, client kubernetes.Interface, request Request, logf func(format string, args ...interface{})) (*Held, error) {
+	if request.Clock == nil {
+		request.Clock = clock.RealClock{}
+	}
+	leases := client.CoordinationV1().Leases(request.Namespace)
+	entry, err := Enqueue(ctx, client, request.Namespace, request.Name, request.GenerateName, request.Holder, request.Team, request.Priority, request.Clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = Remove(context.Background(), client, request.Namespace, entry.Name) }()
+
+	deadline := request.Clock.After(request.Timeout)
+	tick := request.Clock.Tick(30 * time.Second)
+	for {
+		now := metav1.NewMicroTime(request.Clock.Now())
+		lease, err := leases.Get(ctx, request.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			lease = &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Name: request.Name}}
+			_, err = leases.Create(ctx, lease, metav1.CreateOptions{})
+			if err == nil || apierrors.IsAlreadyExists(err) {
+				continue
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve lease %s: %w", request.Name, err)
+		}
+
+		entries, err := List(ctx, client, request.Namespace, request.Name)
+		if err != nil {
+			return nil, err
+		}
+		active, stale := Split(entries, now.Time, request.Takeover)
+		for _, e := range stale {
+			if e.Name != entry.Name {
+				logf("Removing stale queue entry %s of %s", e.Name, e.Holder)
+				_ = Remove(ctx, client, request.Namespace, e.Name)
+			}
+		}
+
+		queue := Order(active, LastGrants(lease))
+		first := len(queue) > 0 && queue[0].Name == entry.Name
+		if first && LeaseAvailable(lease, request.Holder, now.Time, request.Takeover) {
+			err = take(ctx, client, lease, request, entry.Team, now, logf)
+			if err == nil {
+				held := &Held{client: client, namespace: request.Namespace, name: request.Name, holder: request.Holder, clock: request.Clock, done: make(chan struct{})}
+				go held.renew(request.Takeover / 3)
+				return held, nil
+			}
+			// The resource version makes a concurrent acquisition fail with a conflict
+			if !apierrors.IsConflict(err) {
+				return nil, fmt.Errorf("failed to take lease %s: %w", request.Name, err)
+			}
+		} else if !first {
+			logf("Waiting for lease %s, %d runs queued ahead", request.Name, queuePosition(queue, entry.Name))
+		} else {
+			logf("Waiting for lease %s, held by %s", request.Name, *lease.Spec.HolderIdentity)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline:
+			return nil, fmt.Errorf("failed to acquire lease %s within %s", request.Name, request.Timeout)
+		case <-tick:
+			_ = Heartbeat(ctx, client, request.Namespace, entry, request.Clock.Now())
+		}
+	}
+}
+
+func take(ctx context.Context, client kubernetes.Interface, lease *coordinationv1.Lease, request Request, team string, now metav1.MicroTime, logf func(format string, args ...interface{})) error {
+	if lease.Spec.HolderIdentity != nil && *lease.Spec.HolderIdentity != "" && *lease.Spec.HolderIdentity != request.Holder {
+		logf("Taking over lease %s from %s, not renewed for more than %s", request.Name, *lease.Spec.HolderIdentity, request.Takeover)
+	}
+	transitions := int32(0)
+	if lease.Spec.LeaseTransitions != nil {
+		transitions = *lease.Spec.LeaseTransitions + 1
+	}
+	lease.Spec = leaseSpec(request.Holder, now, request.Takeover)
+	lease.Spec.LeaseTransitions = &transitions
+	RecordGrant(lease, team, now.Time)
+	_, err := client.CoordinationV1().Leases(request.Namespace).Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+func queuePosition(queue []Entry, name string) int {
+	for i, entry := range queue {
+		if entry.Name == name {
+			return i
+		}
+	}
+	return len(queue)
+}
+
+// Release stops renewing the Lease and frees it, unless it was taken over in the meantime
+func (l *Held) Release() error {
+	close(l.done)
+	leases := l.client.CoordinationV1().Leases(l.namespace)
+	lease, err := leases.Get(context.Background(), l.name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to retrieve lease %s: %w", l.name, err)
+	}
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != l.holder {
+		return fmt.Errorf("lease %s was taken over by another holder", l.name)
+	}
+	lease.Spec.HolderIdentity = nil
+	_, err = leases.Update(context.Background(), lease, metav1.UpdateOptions{})
+	return err
+}
+
+func (l *Held) renew(interval time.Duration) {
+	tick := l.clock.Tick(interval)
+	for {
+		select {
+		case <-l.done:
+			return
+		case <-tick:
+			leases := l.client.CoordinationV1().Leases(l.namespace)
+			lease, err := leases.Get(context.Background(), l.name, metav1.GetOptions{})
+			if err != nil || lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != l.holder {
+				continue
+			}
+			now := metav1.NewMicroTime(l.clock.Now())
+			lease.Spec.RenewTime = &now
+			_, _ = leases.Update(context.Background(), lease, metav1.UpdateOptions{})
+		}
+	}
+}
+
+func leaseSpec(holder string, now metav1.MicroTime, takeover time.Duration) coordinationv1.LeaseSpec {
+	duration := int32(takeover.Seconds())
+	return coordinationv1.LeaseSpec{
+		HolderIdentity:       &holder,
+		LeaseDurationSeconds: &duration,
+		AcquireTime:          &now,
+		RenewTime:            &now,
+	}
+}
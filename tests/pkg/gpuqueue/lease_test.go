@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpuqueue
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func TestLeaseAvailable(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	takeover := 10 * time.Minute
+	lease := func(holder string, renewed time.Time) *coordinationv1.Lease {
+		renewTime := metav1.NewMicroTime(renewed)
+		return &coordinationv1.Lease{Spec: coordinationv1.LeaseSpec{HolderIdentity: &holder, RenewTime: &renewTime}}
+	}
+
+	require.True(t, LeaseAvailable(&coordinationv1.Lease{}, "me", now, takeover))
+	require.True(t, LeaseAvailable(lease("", now), "me", now, takeover))
+	require.True(t, LeaseAvailable(lease("me", now), "me", now, takeover))
+	require.False(t, LeaseAvailable(lease("other", now.Add(-time.Minute)), "me", now, takeover))
+	require.True(t, LeaseAvailable(lease("other", now.Add(-time.Hour)), "me", now, takeover))
+}
+
+// newQueueClient returns a fake client holding the Lease for holder since renewed, naming the queue entries as the API
+// server does
+func newQueueClient(holder string, renewed time.Time) *fake.Clientset {
+	renewTime := metav1.NewMicroTime(renewed)
+	client := fake.NewSimpleClientset(&coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: LeaseName},
+		Spec:       coordinationv1.LeaseSpec{HolderIdentity: &holder, RenewTime: &renewTime},
+	})
+	client.PrependReactor("create", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		configMap := action.(k8stesting.CreateAction).GetObject().(*corev1.ConfigMap)
+		configMap.Name = configMap.GenerateName + "1"
+		return false, nil, nil
+	})
+	return client
+}
+
+// waitingLogf returns a logf signaling on waiting once Acquire waits for its next poll
+func waitingLogf(t *testing.T, waiting chan<- struct{}) func(format string, args ...interface{}) {
+	return func(format string, args ...interface{}) {
+		t.Logf(format, args...)
+		if strings.HasPrefix(format, "Waiting for lease") {
+			select {
+			case waiting <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+func TestAcquireTakeover(t *testing.T) {
+	clock := testingclock.NewFakeClock(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	client := newQueueClient("crashed", clock.Now())
+	request := Request{Namespace: "ns", Name: LeaseName, GenerateName: EntryName + "-", Holder: "me", Takeover: 5 * time.Minute, Timeout: time.Hour, Clock: clock}
+
+	waiting := make(chan struct{}, 1)
+	acquired := make(chan *Held)
+	failed := make(chan error, 1)
+	go func() {
+		held, err := Acquire(context.Background(), client, request, waitingLogf(t, waiting))
+		if err != nil {
+			failed <- err
+			return
+		}
+		acquired <- held
+	}()
+
+	// The Lease is taken over once its holder did not renew it within the takeover timeout
+	<-waiting
+	clock.Step(6 * time.Minute)
+	var held *Held
+	select {
+	case held = <-acquired:
+	case err := <-failed:
+		require.NoError(t, err)
+	}
+
+	lease, err := client.CoordinationV1().Leases("ns").Get(context.Background(), LeaseName, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, "me", *lease.Spec.HolderIdentity)
+	require.True(t, lease.Spec.AcquireTime.Time.Equal(clock.Now()))
+	require.NoError(t, held.Release())
+}
+
+func TestAcquireTimeout(t *testing.T) {
+	clock := testingclock.NewFakeClock(time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC))
+	client := newQueueClient("other", clock.Now())
+	request := Request{Namespace: "ns", Name: LeaseName, GenerateName: EntryName + "-", Holder: "me", Takeover: time.Hour, Timeout: 10 * time.Minute, Clock: clock}
+
+	waiting := make(chan struct{}, 1)
+	failed := make(chan error)
+	go func() {
+		_, err := Acquire(context.Background(), client, request, waitingLogf(t, waiting))
+		failed <- err
+	}()
+
+	<-waiting
+	clock.Step(11 * time.Minute)
+	require.ErrorContains(t, <-failed, "failed to acquire lease")
+
+	// The queue entry is removed on failure
+	entries, err := List(context.Background(), client, "ns", LeaseName)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
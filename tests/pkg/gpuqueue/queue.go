@@ -0,0 +1,185 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gpuqueue queues the runs waiting for the GPU Lease of a shared cluster. Entries are ConfigMaps labeled
+// with the Lease name; they are served by priority, then fairly across teams, then in FIFO order within a team.
+package gpuqueue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// LeaseName is the name of the Lease serializing the GPU-heavy runs of a shared cluster, after the resource prefix
+	// of the e2e tests
+	LeaseName = "gpu"
+	// EntryName is the name of the queue entries, after the resource prefix of the e2e tests and before the suffix
+	// generated by the API server
+	EntryName = "gpu-queue"
+	// QueueLabel marks the queue entries of a Lease, its value is the Lease name
+	QueueLabel          = "ilab.opendatahub.io/gpu-queue"
+	TeamAnnotation      = "ilab.opendatahub.io/team"
+	PriorityAnnotation  = "ilab.opendatahub.io/priority"
+	HolderAnnotation    = "ilab.opendatahub.io/holder"
+	HeartbeatAnnotation = "ilab.opendatahub.io/heartbeat"
+	// LastGrantsAnnotation records on the Lease when each team was last granted it, as a JSON object
+	LastGrantsAnnotation = "ilab.opendatahub.io/last-grants"
+
+	// DefaultTeam is used for entries without a team
+	DefaultTeam = "default"
+)
+
+// Entry is a run waiting for the Lease
+type Entry struct {
+	Name      string
+	Holder    string
+	Team      string
+	Priority  int
+	Enqueued  time.Time
+	Heartbeat time.Time
+}
+
+// Enqueue adds an entry for holder to the queue of a Lease, named after generateName
+func Enqueue(ctx context.Context, client kubernetes.Interface, namespace, lease, generateName, holder, team string, priority int, now time.Time) (Entry, error) {
+	if team == "" {
+		team = DefaultTeam
+	}
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		GenerateName: generateName,
+		Labels:       map[string]string{QueueLabel: lease},
+		Annotations: map[string]string{
+			TeamAnnotation:      team,
+			PriorityAnnotation:  strconv.Itoa(priority),
+			HolderAnnotation:    holder,
+			HeartbeatAnnotation: now.UTC().Format(time.RFC3339),
+		},
+	}}
+	created, err := client.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{})
+	if err != nil {
+		return Entry{}, fmt.Errorf("failed to enqueue %s: %w", holder, err)
+	}
+	return entryFromConfigMap(*created), nil
+}
+
+// List returns the entries of the queue of a Lease, in no particular order
+func List(ctx context.Context, client kubernetes.Interface, namespace, lease string) ([]Entry, error) {
+	configMaps, err := client.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", QueueLabel, lease),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the queue of %s: %w", lease, err)
+	}
+	var entries []Entry
+	for _, configMap := range configMaps.Items {
+		entries = append(entries, entryFromConfigMap(configMap))
+	}
+	return entries, nil
+}
+
+// Heartbeat tells the queue the waiting run is still alive
+func Heartbeat(ctx context.Context, client kubernetes.Interface, namespace string, entry Entry, now time.Time) error {
+	patch, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]string{
+			HeartbeatAnnotation: now.UTC().Format(time.RFC3339),
+		}},
+	})
+	_, err := client.CoreV1().ConfigMaps(namespace).Patch(ctx, entry.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// Remove deletes an entry from the queue
+func Remove(ctx context.Context, client kubernetes.Interface, namespace, name string) error {
+	return client.CoreV1().ConfigMaps(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+// Split separates the entries whose waiting run stopped sending heartbeats, e.g. a crashed test
+func Split(entries []Entry, now time.Time, staleAfter time.Duration) (active, stale []Entry) {
+	for _, entry := range entries {
+		if now.Sub(entry.Heartbeat) > staleAfter {
+			stale = append(stale, entry)
+		} else {
+			active = append(active, entry)
+		}
+	}
+	return active, stale
+}
+
+// Order sorts the entries in serving order: by priority, then the team granted the Lease the longest time ago,
+// then by enqueue time
+func Order(entries []Entry, lastGrants map[string]time.Time) []Entry {
+	ordered := append([]Entry{}, entries...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		if a.Team != b.Team {
+			grantA, grantB := lastGrants[a.Team], lastGrants[b.Team]
+			if !grantA.Equal(grantB) {
+				return grantA.Before(grantB)
+			}
+		}
+		if !a.Enqueued.Equal(b.Enqueued) {
+			return a.Enqueued.Before(b.Enqueued)
+		}
+		return a.Name < b.Name
+	})
+	return ordered
+}
+
+// LastGrants reads when each team was last granted the Lease
+func LastGrants(lease *coordinationv1.Lease) map[string]time.Time {
+	grants := map[string]time.Time{}
+	if value, ok := lease.Annotations[LastGrantsAnnotation]; ok {
+		_ = json.Unmarshal([]byte(value), &grants)
+	}
+	return grants
+}
+
+// RecordGrant records on the Lease that team was granted it
+func RecordGrant(lease *coordinationv1.Lease, team string, now time.Time) {
+	grants := LastGrants(lease)
+	grants[team] = now.UTC()
+	value, _ := json.Marshal(grants)
+	if lease.Annotations == nil {
+		lease.Annotations = map[string]string{}
+	}
+	lease.Annotations[LastGrantsAnnotation] = string(value)
+}
+
+func entryFromConfigMap(configMap corev1.ConfigMap) Entry {
+	priority, _ := strconv.Atoi(configMap.Annotations[PriorityAnnotation])
+	heartbeat, err := time.Parse(time.RFC3339, configMap.Annotations[HeartbeatAnnotation])
+	if err != nil {
+		heartbeat = configMap.CreationTimestamp.Time
+	}
+	return Entry{
+		Name:      configMap.Name,
+		Holder:    configMap.Annotations[HolderAnnotation],
+		Team:      configMap.Annotations[TeamAnnotation],
+		Priority:  priority,
+		Enqueued:  configMap.CreationTimestamp.Time,
+		Heartbeat: heartbeat,
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpuqueue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+)
+
+func names(entries []Entry) []string {
+	var result []string
+	for _, entry := range entries {
+		result = append(result, entry.Name)
+	}
+	return result
+}
+
+func TestOrder(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Name: "a1", Team: "a", Enqueued: start},
+		{Name: "a2", Team: "a", Enqueued: start.Add(time.Minute)},
+		{Name: "b1", Team: "b", Enqueued: start.Add(2 * time.Minute)},
+		{Name: "urgent", Team: "b", Priority: 10, Enqueued: start.Add(3 * time.Minute)},
+	}
+
+	// FIFO when no team was granted the lease yet
+	require.Equal(t, []string{"urgent", "a1", "a2", "b1"}, names(Order(entries, nil)))
+
+	// Team a was served last, team b goes first among equal priorities
+	lastGrants := map[string]time.Time{"a": start.Add(-time.Minute), "b": start.Add(-time.Hour)}
+	require.Equal(t, []string{"urgent", "b1", "a1", "a2"}, names(Order(entries, lastGrants)))
+}
+
+func TestSplit(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	active, stale := Split([]Entry{
+		{Name: "alive", Heartbeat: now.Add(-time.Minute)},
+		{Name: "crashed", Heartbeat: now.Add(-time.Hour)},
+	}, now, 10*time.Minute)
+	require.Equal(t, []string{"alive"}, names(active))
+	require.Equal(t, []string{"crashed"}, names(stale))
+}
+
+func TestRecordGrant(t *testing.T) {
+	lease := &coordinationv1.Lease{}
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	RecordGrant(lease, "a", now)
+	RecordGrant(lease, "b", now.Add(time.Hour))
+	require.Equal(t, map[string]time.Time{"a": now, "b": now.Add(time.Hour)}, LastGrants(lease))
+}
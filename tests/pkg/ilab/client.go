@@ -0,0 +1,519 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ilab launches and monitors ilab pipeline runs from Go, e.g. for dashboards or chatbots, without going
+// through the e2e tests or the Python SDK. The runs are submitted to the pipeline server of a Data Science Pipelines
+// application, the way the e2e tests run the pipeline.
+package ilab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/runcontrol"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// DefaultPollInterval is the time WatchRun waits between two reads of a run unless the client sets another interval
+const DefaultPollInterval = 30 * time.Second
+
+// DSPAGVR is the resource of the Data Science Pipelines applications, whose status holds the URL of the pipeline server
+var DSPAGVR = schema.GroupVersionResource{
+	Group:    "datasciencepipelinesapplications.opendatahub.io",
+	Version:  "v1alpha1",
+	Resource: "datasciencepipelinesapplications",
+}
+
+// Client submits ilab runs to a pipeline server and follows them
+type Client struct {
+	Server runcontrol.PipelineServer
+	// Kube reads the pods of the runs in Namespace, for StreamLogs
+	Kube      kubernetes.Interface
+	Namespace string
+	// PollInterval is the time WatchRun waits between two reads of a run, DefaultPollInterval when zero
+	PollInterval time.Duration
+}
+
+// RunSpec describes a run of an ilab pipeline uploaded to the pipeline server
+type RunSpec struct {
+	// Pipeline is the display name of the pipeline
+	Pipeline string `json:"pipeline"`
+	// PipelineVersion is the ID of the version of the pipeline to run, the latest version when empty
+	PipelineVersion string `json:"pipeline_version,omitempty"`
+	// DisplayName names the run, the pipeline name when empty
+	DisplayName string `json:"display_name"`
+	// Params are the pipeline parameters, the pipeline defaults apply to the others
+	Params map[string]interface{} `json:"params"`
+	// PipelineRoot is where the artifacts of the run are stored, the default of the pipeline server when empty
+	PipelineRoot string `json:"pipeline_root"`
+}
+
+// RunStatus is the state of a run and of its tasks, as reported by the pipeline server
+type RunStatus struct {
+	RunID       string    `json:"run_id"`
+	DisplayName string    `json:"display_name"`
+	CreatedAt   time.Time `json:"created_at"`
+	// State is the state of the run on the pipeline server, e.g. RUNNING or SUCCEEDED
+	State string `json:"state"`
+	// Error is the error of a failed run
+	Error string       `json:"error,omitempty"`
+	Tasks []TaskStatus `json:"tasks,omitempty"`
+}
+
+// TaskStatus is the state of a task of a run
+type TaskStatus struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+	Error string `json:"error,omitempty"`
+}
+
+// Finished reports whether the run reached a final state
+func (s RunStatus) Finished() bool {
+	switch s.State {
+	case "SUCCEEDED", "SKIPPED", "FAILED", "CANCELING", "CANCELED", "PAUSED":
+		return true
+	}
+	return false
+}
+
+// Succeeded reports whether the run succeeded
+func (s RunStatus) Succeeded() bool {
+	return s.State == "SUCCEEDED"
+}
+
+// Artifact is an output artifact of a task of a run
+type Artifact struct {
+	ID   string
+	Task string
+	// Name is the name of the task output
+	Name string
+	URI  string
+	// Path is the file the artifact was downloaded to, empty when it could not be downloaded
+	Path string
+	// Error tells why the artifact was not downloaded, e.g. the pipeline server does not serve directories
+	Error string
+}
+
+// NewClient creates a client for the pipeline server of the current namespace of a kubeconfig, the default
+// kubeconfig when the path is empty. The server URL is read from the status of the Data Science Pipelines application
+// of the namespace, and the requests are authenticated with the bearer token of the kubeconfig, as `oc login` sets.
+func NewClient(kubeconfig string) (*Client, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the kubeconfig: %w", err)
+	}
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the namespace of the kubeconfig: %w", err)
+	}
+	token := config.BearerToken
+	if token == "" && config.BearerTokenFile != "" {
+		data, err := os.ReadFile(config.BearerTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the bearer token: %w", err)
+		}
+		token = string(bytes.TrimSpace(data))
+	}
+	if token == "" {
+		return nil, fmt.Errorf("the kubeconfig has no bearer token, log in with `oc login`")
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	serverURL, err := PipelineServerURL(context.Background(), dynamicClient, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{Server: runcontrol.PipelineServer{URL: serverURL, BearerToken: token}, Kube: client, Namespace: namespace}, nil
+}
+
+// PipelineServerURL returns the external URL of the pipeline server of the Data Science Pipelines application of a
+// namespace
+func PipelineServerURL(ctx context.Context, client dynamic.Interface, namespace string) (string, error) {
+	list, err := client.Resource(DSPAGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list the Data Science Pipelines applications of namespace %s: %w", namespace, err)
+	}
+	if len(list.Items) != 1 {
+		return "", fmt.Errorf("expected one Data Science Pipelines application in namespace %s, found %d", namespace, len(list.Items))
+	}
+	dspa := list.Items[0]
+	for _, field := range []string{"externalUrl", "url"} {
+		value, _, _ := unstructured.NestedString(dspa.Object, "status", "components", "apiServer", field)
+		if value != "" {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("the Data Science Pipelines application %s has no pipeline server URL yet", dspa.GetName())
+}
+
+// SubmitRun starts a run of a pipeline, of its latest version unless the spec names one, and returns the run ID
+func (c *Client) SubmitRun(ctx context.Context, spec RunSpec) (string, error) {
+	pipelineID, err := c.pipelineID(ctx, spec.Pipeline)
+	if err != nil {
+		return "", err
+	}
+	displayName := spec.DisplayName
+	if displayName == "" {
+		displayName = spec.Pipeline
+	}
+	runtimeConfig := map[string]interface{}{"parameters": spec.Params}
+	if spec.PipelineRoot != "" {
+		runtimeConfig["pipeline_root"] = spec.PipelineRoot
+	}
+	versionReference := map[string]interface{}{"pipeline_id": pipelineID}
+	if spec.PipelineVersion != "" {
+		versionReference["pipeline_version_id"] = spec.PipelineVersion
+	}
+	request := map[string]interface{}{
+		"display_name":               displayName,
+		"pipeline_version_reference": versionReference,
+		"runtime_config":             runtimeConfig,
+	}
+	var created struct {
+		RunID string `json:"run_id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/apis/v2beta1/runs", request, &created); err != nil {
+		return "", fmt.Errorf("failed to submit a run of pipeline %s: %w", spec.Pipeline, err)
+	}
+	if created.RunID == "" {
+		return "", fmt.Errorf("the pipeline server returned no run ID")
+	}
+	return created.RunID, nil
+}
+
+// GetRun returns the state of a run and of its tasks
+func (c *Client) GetRun(ctx context.Context, runID string) (RunStatus, error) {
+	details, err := c.getRun(ctx, runID)
+	if err != nil {
+		return RunStatus{}, err
+	}
+	return details.status(), nil
+}
+
+// GetRunSpec returns the spec a run was submitted with: its pipeline and pipeline version, parameters and pipeline root
+func (c *Client) GetRunSpec(ctx context.Context, runID string) (RunSpec, error) {
+	details, err := c.getRun(ctx, runID)
+	if err != nil {
+		return RunSpec{}, err
+	}
+	reference := details.PipelineVersionReference
+	if reference.PipelineID == "" {
+		return RunSpec{}, fmt.Errorf("run %s has no pipeline version reference", runID)
+	}
+	var pipeline struct {
+		DisplayName string `json:"display_name"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/apis/v2beta1/pipelines/"+url.PathEscape(reference.PipelineID), nil, &pipeline); err != nil {
+		return RunSpec{}, fmt.Errorf("failed to get pipeline %s of run %s: %w", reference.PipelineID, runID, err)
+	}
+	return RunSpec{
+		Pipeline:        pipeline.DisplayName,
+		PipelineVersion: reference.PipelineVersionID,
+		DisplayName:     details.DisplayName,
+		Params:          details.RuntimeConfig.Parameters,
+		PipelineRoot:    details.RuntimeConfig.PipelineRoot,
+	}, nil
+}
+
+// ListRuns returns the runs of the pipeline server, the most recent first, without the state of their tasks
+func (c *Client) ListRuns(ctx context.Context) ([]RunStatus, error) {
+	var runs []RunStatus
+	pageToken := ""
+	for {
+		var page struct {
+			Runs          []run  `json:"runs"`
+			NextPageToken string `json:"next_page_token"`
+		}
+		query := url.Values{"page_size": {strconv.Itoa(100)}, "sort_by": {"created_at desc"}}
+		if pageToken != "" {
+			query.Set("page_token", pageToken)
+		}
+		if err := c.do(ctx, http.MethodGet, "/apis/v2beta1/runs?"+query.Encode(), nil, &page); err != nil {
+			return runs, fmt.Errorf("failed to list the runs: %w", err)
+		}
+		for _, details := range page.Runs {
+			status := details.status()
+			status.Tasks = nil
+			runs = append(runs, status)
+		}
+		if page.NextPageToken == "" {
+			return runs, nil
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+// WatchRun reads the state of a run every PollInterval until it finishes, and returns its final state. report is
+// called with the first state and every change, e.g. a task starting or failing. The watch stops when the context is
+// done.
+func (c *Client) WatchRun(ctx context.Context, runID string, report func(RunStatus)) (RunStatus, error) {
+	interval := c.PollInterval
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var last RunStatus
+	for {
+		status, err := c.GetRun(ctx, runID)
+		if err != nil {
+			return last, err
+		}
+		if report != nil && !reflect.DeepEqual(status, last) {
+			report(status)
+		}
+		last = status
+		if status.Finished() {
+			return status, nil
+		}
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// FetchArtifacts downloads the output artifacts of the tasks of a run to dir, as <task>/<output>, suffixed with the
+// artifact ID when an output has several artifacts. The pipeline server
+// serves single files only, the artifacts it cannot serve are returned with their error and no path.
+func (c *Client) FetchArtifacts(ctx context.Context, runID, dir string) ([]Artifact, error) {
+	details, err := c.getRun(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	var artifacts []Artifact
+	for _, task := range details.RunDetails.TaskDetails {
+		for _, name := range sortedOutputs(task.Outputs) {
+			ids := task.Outputs[name].ArtifactIDs
+			for _, id := range ids {
+				artifact := Artifact{ID: string(id), Task: task.DisplayName, Name: name}
+				path := filepath.Join(dir, task.DisplayName, name)
+				if len(ids) > 1 {
+					path += "-" + artifact.ID
+				}
+				if err := c.fetchArtifact(ctx, &artifact, path); err != nil {
+					if ctx.Err() != nil {
+						return artifacts, ctx.Err()
+					}
+					artifact.Error = err.Error()
+				}
+				artifacts = append(artifacts, artifact)
+			}
+		}
+	}
+	return artifacts, nil
+}
+
+// Terminate stops a run, its tasks not started yet never execute
+func (c *Client) Terminate(ctx context.Context, runID string) error {
+	return c.Server.Terminate(ctx, runID)
+}
+
+func (c *Client) fetchArtifact(ctx context.Context, artifact *Artifact, path string) error {
+	var details struct {
+		URI         string `json:"uri"`
+		DownloadURL string `json:"download_url"`
+		Error       struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/apis/v2beta1/artifacts/%s?view=DOWNLOAD", url.PathEscape(artifact.ID)), nil, &details); err != nil {
+		return err
+	}
+	artifact.URI = details.URI
+	if details.DownloadURL == "" {
+		if details.Error.Message != "" {
+			return fmt.Errorf("%s", details.Error.Message)
+		}
+		return fmt.Errorf("the pipeline server returned no download URL")
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, details.DownloadURL, nil)
+	if err != nil {
+		return err
+	}
+	response, err := c.httpClient().Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to download artifact %s", artifact.ID)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading artifact %s failed with status %d", artifact.ID, response.StatusCode)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, response.Body); err != nil {
+		return fmt.Errorf("failed to download artifact %s: %w", artifact.ID, err)
+	}
+	artifact.Path = path
+	return nil
+}
+
+// run is a run as returned by the pipeline server
+type run struct {
+	RunID       string    `json:"run_id"`
+	DisplayName string    `json:"display_name"`
+	CreatedAt   time.Time `json:"created_at"`
+	State       string    `json:"state"`
+	Error       struct {
+		Message string `json:"message"`
+	} `json:"error"`
+	PipelineVersionReference struct {
+		PipelineID        string `json:"pipeline_id"`
+		PipelineVersionID string `json:"pipeline_version_id"`
+	} `json:"pipeline_version_reference"`
+	RuntimeConfig struct {
+		Parameters   map[string]interface{} `json:"parameters"`
+		PipelineRoot string                 `json:"pipeline_root"`
+	} `json:"runtime_config"`
+	RunDetails struct {
+		TaskDetails []struct {
+			DisplayName string `json:"display_name"`
+			State       string `json:"state"`
+			Error       struct {
+				Message string `json:"message"`
+			} `json:"error"`
+			Outputs map[string]struct {
+				// The artifact IDs are int64 values, which the JSON mapping of protobuf encodes as strings
+				ArtifactIDs []json.Number `json:"artifact_ids"`
+			} `json:"outputs"`
+		} `json:"task_details"`
+	} `json:"run_details"`
+}
+
+func (r run) status() RunStatus {
+	status := RunStatus{RunID: r.RunID, DisplayName: r.DisplayName, CreatedAt: r.CreatedAt, State: r.State, Error: r.Error.Message}
+	for _, task := range r.RunDetails.TaskDetails {
+		status.Tasks = append(status.Tasks, TaskStatus{Name: task.DisplayName, State: task.State, Error: task.Error.Message})
+	}
+	return status
+}
+
+func (c *Client) getRun(ctx context.Context, runID string) (run, error) {
+	var details run
+	if err := c.do(ctx, http.MethodGet, "/apis/v2beta1/runs/"+url.PathEscape(runID), nil, &details); err != nil {
+		return details, fmt.Errorf("failed to get run %s: %w", runID, err)
+	}
+	if details.State == "" {
+		return details, fmt.Errorf("run %s has no state", runID)
+	}
+	return details, nil
+}
+
+func (c *Client) pipelineID(ctx context.Context, displayName string) (string, error) {
+	pageToken := ""
+	for {
+		var page struct {
+			Pipelines []struct {
+				PipelineID  string `json:"pipeline_id"`
+				DisplayName string `json:"display_name"`
+			} `json:"pipelines"`
+			NextPageToken string `json:"next_page_token"`
+		}
+		query := url.Values{"page_size": {strconv.Itoa(100)}}
+		if pageToken != "" {
+			query.Set("page_token", pageToken)
+		}
+		if err := c.do(ctx, http.MethodGet, "/apis/v2beta1/pipelines?"+query.Encode(), nil, &page); err != nil {
+			return "", fmt.Errorf("failed to list the pipelines: %w", err)
+		}
+		for _, pipeline := range page.Pipelines {
+			if pipeline.DisplayName == displayName {
+				return pipeline.PipelineID, nil
+			}
+		}
+		if page.NextPageToken == "" {
+			return "", fmt.Errorf("pipeline with display name '%s' not found", displayName)
+		}
+		pageToken = page.NextPageToken
+	}
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, c.Server.URL+path, reader)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer "+c.Server.BearerToken)
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	response, err := c.httpClient().Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d: %s", response.StatusCode, bytes.TrimSpace(data))
+	}
+	return json.Unmarshal(data, result)
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.Server.Client != nil {
+		return c.Server.Client
+	}
+	return http.DefaultClient
+}
+
+func sortedOutputs[T any](outputs map[string]T) []string {
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ilab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/runcontrol"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	var submitted map[string]interface{}
+	states := []string{"PENDING", "RUNNING", "RUNNING", "SUCCEEDED"}
+	polls := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/download/model-card" {
+			_, _ = w.Write([]byte("# Model card"))
+			return
+		}
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		switch r.Method + " " + r.URL.Path {
+		case "GET /apis/v2beta1/pipelines":
+			if r.URL.Query().Get("page_token") == "" {
+				_, _ = w.Write([]byte(`{"pipelines":[{"pipeline_id":"p-0","display_name":"other"}],"next_page_token":"2"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"pipelines":[{"pipeline_id":"p-1","display_name":"instructlab"}]}`))
+		case "POST /apis/v2beta1/runs":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&submitted))
+			_, _ = w.Write([]byte(`{"run_id":"run-1"}`))
+		case "GET /apis/v2beta1/runs/run-1":
+			state := states[polls]
+			if polls < len(states)-1 {
+				polls++
+			}
+			_, _ = w.Write([]byte(`{"run_id":"run-1","state":"` + state + `","run_details":{"task_details":[
+				{"display_name":"sdg-op","state":"` + state + `","outputs":{"model_card":{"artifact_ids":["7"]},"model":{"artifact_ids":[8]}}}]}}`))
+		case "GET /apis/v2beta1/artifacts/7":
+			require.Equal(t, "DOWNLOAD", r.URL.Query().Get("view"))
+			_, _ = w.Write([]byte(`{"artifact_id":"7","uri":"s3://bucket/card.md","download_url":"` + server.URL + `/download/model-card"}`))
+		case "GET /apis/v2beta1/artifacts/8":
+			_, _ = w.Write([]byte(`{"artifact_id":"8","uri":"s3://bucket/model","error":{"message":"the artifact is a directory"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("not found"))
+		}
+	}))
+	defer server.Close()
+	client := &Client{Server: runcontrol.PipelineServer{URL: server.URL, BearerToken: "token"}, PollInterval: time.Millisecond}
+
+	runID, err := client.SubmitRun(ctx, RunSpec{Pipeline: "instructlab", Params: map[string]interface{}{"train_num_epochs_phase_1": 2}})
+	require.NoError(t, err)
+	require.Equal(t, "run-1", runID)
+	require.Equal(t, "instructlab", submitted["display_name"])
+	require.Equal(t, map[string]interface{}{"pipeline_id": "p-1"}, submitted["pipeline_version_reference"])
+	require.Equal(t, map[string]interface{}{"parameters": map[string]interface{}{"train_num_epochs_phase_1": float64(2)}}, submitted["runtime_config"])
+	_, err = client.SubmitRun(ctx, RunSpec{Pipeline: "missing"})
+	require.EqualError(t, err, "pipeline with display name 'missing' not found")
+
+	// The second RUNNING state is unchanged and not reported
+	var reported []string
+	status, err := client.WatchRun(ctx, runID, func(status RunStatus) { reported = append(reported, status.State) })
+	require.NoError(t, err)
+	require.True(t, status.Succeeded())
+	require.Equal(t, []string{"PENDING", "RUNNING", "SUCCEEDED"}, reported)
+	require.Equal(t, []TaskStatus{{Name: "sdg-op", State: "SUCCEEDED"}}, status.Tasks)
+
+	_, err = client.GetRun(ctx, "run-2")
+	require.EqualError(t, err, "failed to get run run-2: status 404: not found")
+
+	dir := t.TempDir()
+	artifacts, err := client.FetchArtifacts(ctx, runID, dir)
+	require.NoError(t, err)
+	require.Equal(t, []Artifact{
+		{ID: "8", Task: "sdg-op", Name: "model", URI: "s3://bucket/model", Error: "the artifact is a directory"},
+		{ID: "7", Task: "sdg-op", Name: "model_card", URI: "s3://bucket/card.md", Path: filepath.Join(dir, "sdg-op", "model_card")},
+	}, artifacts)
+	card, err := os.ReadFile(artifacts[1].Path)
+	require.NoError(t, err)
+	require.Equal(t, "# Model card", string(card))
+}
+
+func TestPipelineServerURL(t *testing.T) {
+	dspa := &unstructured.Unstructured{}
+	dspa.SetAPIVersion("datasciencepipelinesapplications.opendatahub.io/v1alpha1")
+	dspa.SetKind("DataSciencePipelinesApplication")
+	dspa.SetNamespace("ilab")
+	dspa.SetName("dspa")
+	client := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		DSPAGVR: "DataSciencePipelinesApplicationList",
+	}, dspa)
+
+	_, err := PipelineServerURL(context.Background(), client, "ilab")
+	require.EqualError(t, err, "the Data Science Pipelines application dspa has no pipeline server URL yet")
+	require.NoError(t, unstructured.SetNestedField(dspa.Object, "https://ds-pipeline-dspa-ilab.apps.example.com", "status", "components", "apiServer", "externalUrl"))
+	_, err = client.Resource(DSPAGVR).Namespace("ilab").Update(context.Background(), dspa, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	url, err := PipelineServerURL(context.Background(), client, "ilab")
+	require.NoError(t, err)
+	require.Equal(t, "https://ds-pipeline-dspa-ilab.apps.example.com", url)
+
+	_, err = PipelineServerURL(context.Background(), client, "other")
+	require.EqualError(t, err, "expected one Data Science Pipelines application in namespace other, found 0")
+}
+
+func TestListRunsAndStreamLogs(t *testing.T) {
+	ctx := context.Background()
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/apis/v2beta1/runs":
+			require.Equal(t, "created_at desc", r.URL.Query().Get("sort_by"))
+			_, _ = w.Write([]byte(`{"runs":[{"run_id":"run-2","display_name":"instructlab","created_at":"2025-03-01T10:00:00Z","state":"RUNNING",
+				"run_details":{"task_details":[{"display_name":"sdg-op","state":"RUNNING"}]}},{"run_id":"run-1","state":"FAILED","error":{"message":"sdg-op failed"}}]}`))
+		case "/apis/v2beta1/runs/run-2":
+			state := "RUNNING"
+			if polls++; polls > 1 {
+				state = "SUCCEEDED"
+			}
+			_, _ = w.Write([]byte(`{"run_id":"run-2","state":"` + state + `"}`))
+		}
+	}))
+	defer server.Close()
+	pod := func(name string, created time.Time, containers ...string) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ilab", CreationTimestamp: metav1.NewTime(created),
+			Labels: map[string]string{runcontrol.RunIDLabel: "run-2"}}}
+		for _, container := range containers {
+			pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: container})
+		}
+		return pod
+	}
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	kube := fake.NewSimpleClientset(
+		pod("train", start.Add(time.Hour), "main"),
+		pod("sdg", start, "wait", "main"),
+		pod("train-phase-1-worker-0", start.Add(2*time.Hour), "pytorch"),
+	)
+	client := &Client{Server: runcontrol.PipelineServer{URL: server.URL}, Kube: kube, Namespace: "ilab", PollInterval: time.Millisecond}
+
+	runs, err := client.ListRuns(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []RunStatus{
+		{RunID: "run-2", DisplayName: "instructlab", CreatedAt: start, State: "RUNNING"},
+		{RunID: "run-1", State: "FAILED", Error: "sdg-op failed"},
+	}, runs)
+
+	// The fake clientset returns "fake logs" for every container, the task pods are streamed once in creation order
+	var logs bytes.Buffer
+	require.NoError(t, client.StreamLogs(ctx, "run-2", &logs))
+	require.Equal(t, "==> sdg <==\nfake logs==> train <==\nfake logs", logs.String())
+}
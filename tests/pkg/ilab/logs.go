@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ilab
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/runcontrol"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TaskContainer is the container of the task pods running the task, the other containers are the Argo executor
+const TaskContainer = "main"
+
+// StreamLogs writes the logs of the task pods of a run to w as they are produced, until the run finishes. The pods
+// are followed one after another in the order they were created, each preceded by a "==> <pod> <==" line.
+func (c *Client) StreamLogs(ctx context.Context, runID string, w io.Writer) error {
+	if c.Kube == nil {
+		return fmt.Errorf("the client has no Kubernetes client to read the logs with")
+	}
+	interval := c.PollInterval
+	if interval == 0 {
+		interval = DefaultPollInterval
+	}
+	streamed := map[string]bool{}
+	for {
+		// The state is read before listing the pods, the pods of a finished run are all listed
+		status, err := c.GetRun(ctx, runID)
+		if err != nil {
+			return err
+		}
+		pods, err := c.Kube.CoreV1().Pods(c.Namespace).List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", runcontrol.RunIDLabel, runID)})
+		if err != nil {
+			return fmt.Errorf("failed to list the pods of run %s: %w", runID, err)
+		}
+		sort.Slice(pods.Items, func(i, j int) bool {
+			return pods.Items[i].CreationTimestamp.Before(&pods.Items[j].CreationTimestamp)
+		})
+		for _, pod := range pods.Items {
+			if streamed[pod.Name] || !hasContainer(pod, TaskContainer) {
+				continue
+			}
+			streamed[pod.Name] = true
+			if err := c.streamPodLogs(ctx, pod, w); err != nil {
+				return err
+			}
+		}
+		if status.Finished() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (c *Client) streamPodLogs(ctx context.Context, pod corev1.Pod, w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "==> %s <==\n", pod.Name); err != nil {
+		return err
+	}
+	stream, err := c.Kube.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: TaskContainer, Follow: true}).Stream(ctx)
+	if err != nil {
+		// A pod which did not start has no logs yet, the next pods are still streamed
+		_, err = fmt.Fprintf(w, "failed to read the logs of %s: %v\n", pod.Name, err)
+		return err
+	}
+	defer stream.Close()
+	_, err = io.Copy(w, stream)
+	return err
+}
+
+func hasContainer(pod corev1.Pod, name string) bool {
+	for _, container := range pod.Spec.Containers {
+		if container.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package loglevel lets the verbosity of the watchers of a pipeline run be raised while they run, from the level key
+// of a ConfigMap, so an issue appearing hours into a run can be debugged without restarting the run at debug level
+package loglevel
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultConfigMap is the ConfigMap the level is read from unless another one is set
+const DefaultConfigMap = "ilab-log-level"
+
+// ConfigMapKey is the key of the ConfigMap holding the level
+const ConfigMapKey = "level"
+
+// Level is a log verbosity
+type Level int32
+
+const (
+	Info Level = iota
+	Debug
+)
+
+func (l Level) String() string {
+	if l == Debug {
+		return "debug"
+	}
+	return "info"
+}
+
+// ParseLevel reads info or debug
+func ParseLevel(value string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "info":
+		return Info, nil
+	case "debug":
+		return Debug, nil
+	}
+	return Info, fmt.Errorf("invalid log level '%s', expected info or debug", value)
+}
+
+// Logger logs the debug messages only while its level is Debug. Its methods can be called concurrently.
+type Logger struct {
+	logf    func(format string, args ...interface{})
+	initial Level
+	level   atomic.Int32
+}
+
+// New creates a logger at the initial level, logging with logf
+func New(initial Level, logf func(format string, args ...interface{})) *Logger {
+	logger := &Logger{logf: logf, initial: initial}
+	logger.level.Store(int32(initial))
+	return logger
+}
+
+// Level returns the current level
+func (l *Logger) Level() Level {
+	return Level(l.level.Load())
+}
+
+// SetLevel changes the level, and logs the change
+func (l *Logger) SetLevel(level Level) {
+	if previous := Level(l.level.Swap(int32(level))); previous != level {
+		l.logf("Log level changed from %s to %s", previous, level)
+	}
+}
+
+// Infof logs a message at every level
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.logf(format, args...)
+}
+
+// Debugf logs a message at the Debug level only
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	if l.Level() == Debug {
+		l.logf(format, args...)
+	}
+}
+
+// Watch reads the level of a ConfigMap every interval until the context is done. A missing ConfigMap or key restores
+// the initial level, an invalid level is reported once and ignored.
+func (l *Logger) Watch(ctx context.Context, client kubernetes.Interface, namespace, name string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var reported string
+	for {
+		level, err := readLevel(ctx, client, namespace, name, l.initial)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if err.Error() != reported {
+				reported = err.Error()
+				l.logf("Failed to read the log level: %v", err)
+			}
+		} else {
+			reported = ""
+			l.SetLevel(level)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func readLevel(ctx context.Context, client kubernetes.Interface, namespace, name string, initial Level) (Level, error) {
+	configMap, err := client.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return initial, nil
+	}
+	if err != nil {
+		return initial, fmt.Errorf("failed to get ConfigMap %s: %w", name, err)
+	}
+	value, ok := configMap.Data[ConfigMapKey]
+	if !ok {
+		return initial, nil
+	}
+	level, err := ParseLevel(value)
+	if err != nil {
+		return initial, fmt.Errorf("ConfigMap %s: %w", name, err)
+	}
+	return level, nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loglevel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// lines records the messages logged concurrently
+type lines struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *lines) logf(format string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, fmt.Sprintf(format, args...))
+}
+
+func (l *lines) get() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string{}, l.messages...)
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel(" DEBUG ")
+	require.NoError(t, err)
+	require.Equal(t, Debug, level)
+	level, err = ParseLevel("info")
+	require.NoError(t, err)
+	require.Equal(t, Info, level)
+	_, err = ParseLevel("trace")
+	require.EqualError(t, err, "invalid log level 'trace', expected info or debug")
+}
+
+func TestWatch(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	logged := &lines{}
+	logger := New(Info, logged.logf)
+	logger.Debugf("hidden")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		logger.Watch(ctx, client, "ilab", DefaultConfigMap, time.Millisecond)
+	}()
+
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: DefaultConfigMap}, Data: map[string]string{ConfigMapKey: "debug"}}
+	_, err := client.CoreV1().ConfigMaps("ilab").Create(ctx, configMap, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return logger.Level() == Debug }, 5*time.Second, time.Millisecond)
+	logger.Debugf("shown")
+
+	configMap.Data[ConfigMapKey] = "verbose"
+	_, err = client.CoreV1().ConfigMaps("ilab").Update(ctx, configMap, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return len(logged.get()) == 3 }, 5*time.Second, time.Millisecond)
+	require.Equal(t, Debug, logger.Level(), "an invalid level is ignored")
+
+	require.NoError(t, client.CoreV1().ConfigMaps("ilab").Delete(ctx, DefaultConfigMap, metav1.DeleteOptions{}))
+	require.Eventually(t, func() bool { return logger.Level() == Info }, 5*time.Second, time.Millisecond)
+	cancel()
+	<-done
+
+	require.Equal(t, []string{
+		"Log level changed from info to debug",
+		"shown",
+		"Failed to read the log level: ConfigMap ilab-log-level: invalid log level 'verbose', expected info or debug",
+		"Log level changed from debug to info",
+	}, logged.get())
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logshipper copies the container logs of the pipeline run pods to a directory while they run, so the logs
+// survive the deletion or eviction of the pods. The directory is meant to be a PVC mounted into the shipper.
+package logshipper
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// DefaultSelectors select the pods of the pipeline runs and of their PyTorchJobs
+var DefaultSelectors = []string{"pipeline/runid", "training.kubeflow.org/job-name"}
+
+// Shipper follows the logs of every started container of the pods matching its label selectors
+type Shipper struct {
+	Client    kubernetes.Interface
+	Namespace string
+	Selectors []string
+	Dir       string
+	// Interval is the time between two listings of the pods
+	Interval time.Duration
+	Logf     func(format string, args ...interface{})
+	// Debugf logs the details of the shipping, e.g. a loglevel.Logger raised to debug during a run, nothing when nil
+	Debugf func(format string, args ...interface{})
+}
+
+// Run ships the logs until the context is done, then waits for the logs being followed to be copied
+func (s *Shipper) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	followed := map[string]bool{}
+	tick := time.NewTicker(s.Interval)
+	defer tick.Stop()
+	for {
+		for _, selector := range s.Selectors {
+			pods, err := s.Client.CoreV1().Pods(s.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+			if err != nil {
+				s.Logf("Failed to list the pods of %s: %v", selector, err)
+				continue
+			}
+			s.debugf("Listed %d pods of %s", len(pods.Items), selector)
+			for _, pod := range pods.Items {
+				statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+				for _, status := range statuses {
+					if status.State.Running == nil && status.State.Terminated == nil {
+						continue
+					}
+					path := LogPath(pod.Name, status.Name, status.RestartCount)
+					if followed[path] {
+						continue
+					}
+					followed[path] = true
+					s.debugf("Following the logs of %s/%s to %s", pod.Name, status.Name, path)
+					wg.Add(1)
+					go func(pod, container, path string) {
+						defer wg.Done()
+						if err := s.follow(ctx, pod, container, path); err != nil {
+							s.Logf("Failed to ship the logs of %s/%s: %v", pod, container, err)
+							return
+						}
+						s.debugf("Shipped the logs of %s/%s", pod, container)
+					}(pod.Name, status.Name, path)
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+	}
+}
+
+func (s *Shipper) debugf(format string, args ...interface{}) {
+	if s.Debugf != nil {
+		s.Debugf(format, args...)
+	}
+}
+
+// LogPath is the path of the logs of a container instance relative to the directory: "<pod>/<container>.log", with
+// the restart count before the extension for restarted containers
+func LogPath(pod, container string, restarts int32) string {
+	if restarts > 0 {
+		return filepath.Join(pod, fmt.Sprintf("%s.%d.log", container, restarts))
+	}
+	return filepath.Join(pod, container+".log")
+}
+
+func (s *Shipper) follow(ctx context.Context, pod, container, path string) error {
+	stream, err := s.Client.CoreV1().Pods(s.Namespace).GetLogs(pod, &corev1.PodLogOptions{Container: container, Follow: true}).Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	path = filepath.Join(s.Dir, path)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, stream); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return file.Close()
+}
+
+// Archive writes the files of the directory as a gzipped tarball. Files still growing are archived with the size
+// they had when reached.
+func Archive(dir string, w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: filepath.ToSlash(name), Mode: 0o644, Size: info.Size(), ModTime: info.ModTime()}); err != nil {
+			return err
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		_, err = io.CopyN(tw, file, info.Size())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
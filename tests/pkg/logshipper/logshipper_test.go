@@ -0,0 +1,78 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logshipper
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestShipper(t *testing.T) {
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	waiting := corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}}
+	client := fake.NewSimpleClientset(
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "sdg", Namespace: "ilab", Labels: map[string]string{"pipeline/runid": "run"}},
+			Status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{{Name: "kfp-launcher", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}}},
+				ContainerStatuses:     []corev1.ContainerStatus{{Name: "main", State: running, RestartCount: 1}, {Name: "wait", State: waiting}},
+			},
+		},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ilab"}, Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "main", State: running}}}},
+	)
+
+	dir := t.TempDir()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	shipper := &Shipper{Client: client, Namespace: "ilab", Selectors: DefaultSelectors, Dir: dir, Interval: 10 * time.Millisecond, Logf: t.Logf}
+	shipper.Run(ctx)
+
+	for _, path := range []string{"sdg/kfp-launcher.log", "sdg/main.1.log"} {
+		logs, err := os.ReadFile(filepath.Join(dir, path))
+		require.NoError(t, err)
+		require.Equal(t, "fake logs", string(logs))
+	}
+	require.NoFileExists(t, filepath.Join(dir, "sdg/wait.log"))
+	require.NoDirExists(t, filepath.Join(dir, "other"))
+
+	var archive bytes.Buffer
+	require.NoError(t, Archive(dir, &archive))
+	gz, err := gzip.NewReader(&archive)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	files := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[header.Name] = string(content)
+	}
+	require.Equal(t, map[string]string{"sdg/kfp-launcher.log": "fake logs", "sdg/main.1.log": "fake logs"}, files)
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package objectprobe measures the upload and download throughput between the cluster and the bucket of the run
+// artifacts, as slow egress silently adds hours to the runs
+package objectprobe
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// Bucket is the object store probed
+type Bucket interface {
+	Put(ctx context.Context, key string, content io.Reader, size int64) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Result is the throughput measured by a probe
+type Result struct {
+	Bytes         int64         `json:"bytes"`
+	Upload        time.Duration `json:"upload"`
+	Download      time.Duration `json:"download"`
+	UploadMiBps   float64       `json:"upload_mibps"`
+	DownloadMiBps float64       `json:"download_mibps"`
+}
+
+// Probe uploads size random bytes under the key, downloads them back and deletes the object
+func Probe(ctx context.Context, bucket Bucket, key string, size int64) (Result, error) {
+	result := Result{Bytes: size}
+	start := time.Now()
+	if err := bucket.Put(ctx, key, io.LimitReader(rand.Reader, size), size); err != nil {
+		return result, fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	result.Upload = time.Since(start)
+	defer func() { _ = bucket.Delete(context.Background(), key) }()
+
+	start = time.Now()
+	content, err := bucket.Get(ctx, key)
+	if err != nil {
+		return result, fmt.Errorf("failed to download %s: %w", key, err)
+	}
+	defer content.Close()
+	read, err := io.Copy(io.Discard, content)
+	if err != nil {
+		return result, fmt.Errorf("failed to download %s: %w", key, err)
+	}
+	result.Download = time.Since(start)
+	if read != size {
+		return result, fmt.Errorf("downloaded %d bytes of %s, uploaded %d", read, key, size)
+	}
+
+	result.UploadMiBps = mibps(size, result.Upload)
+	result.DownloadMiBps = mibps(size, result.Download)
+	return result, nil
+}
+
+func mibps(size int64, duration time.Duration) float64 {
+	if duration <= 0 {
+		return 0
+	}
+	return float64(size) / (1 << 20) / duration.Seconds()
+}
+
+// S3Bucket is a bucket of an S3 compatible object store
+type S3Bucket struct {
+	Client *minio.Client
+	Name   string
+}
+
+func (b S3Bucket) Put(ctx context.Context, key string, content io.Reader, size int64) error {
+	_, err := b.Client.PutObject(ctx, b.Name, key, content, size, minio.PutObjectOptions{})
+	return err
+}
+
+func (b S3Bucket) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return b.Client.GetObject(ctx, b.Name, key, minio.GetObjectOptions{})
+}
+
+func (b S3Bucket) Delete(ctx context.Context, key string) error {
+	return b.Client.RemoveObject(ctx, b.Name, key, minio.RemoveObjectOptions{})
+}
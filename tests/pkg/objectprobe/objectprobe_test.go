@@ -0,0 +1,70 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package objectprobe
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type memoryBucket struct {
+	objects map[string][]byte
+	// truncate drops bytes of the downloads
+	truncate int
+}
+
+func (b *memoryBucket) Put(ctx context.Context, key string, content io.Reader, size int64) error {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return errors.New("size mismatch")
+	}
+	b.objects[key] = data
+	return nil
+}
+
+func (b *memoryBucket) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	data, ok := b.objects[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return io.NopCloser(bytes.NewReader(data[b.truncate:])), nil
+}
+
+func (b *memoryBucket) Delete(ctx context.Context, key string) error {
+	delete(b.objects, key)
+	return nil
+}
+
+func TestProbe(t *testing.T) {
+	bucket := &memoryBucket{objects: map[string][]byte{}}
+	result, err := Probe(context.Background(), bucket, "probe/object", 4<<20)
+	require.NoError(t, err)
+	require.Equal(t, int64(4<<20), result.Bytes)
+	require.Positive(t, result.UploadMiBps)
+	require.Positive(t, result.DownloadMiBps)
+	require.Empty(t, bucket.objects, "The probe object must be deleted")
+
+	bucket.truncate = 10
+	_, err = Probe(context.Background(), bucket, "probe/object", 1<<20)
+	require.ErrorContains(t, err, "downloaded 1048566 bytes")
+	require.Empty(t, bucket.objects)
+}
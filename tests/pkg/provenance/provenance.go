@@ -0,0 +1,254 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package provenance signs the artifacts of a pipeline run with a provenance statement: the SHA-256 digests of the
+// artifacts, the run inputs and the images that produced them. The statement follows the in-toto Statement layout,
+// and its signature is the base64 encoded signature `cosign sign-blob` produces with an ECDSA P-256 key, so the
+// statements of the run can be verified with `cosign verify-blob` as well as with Verify.
+package provenance
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	// StatementFile and SignatureFile are stored next to the artifacts they cover
+	StatementFile = "provenance.json"
+	SignatureFile = "provenance.json.sig"
+	// StatementType and PredicateType identify the statements of the pipeline runs
+	StatementType = "https://in-toto.io/Statement/v1"
+	PredicateType = "https://github.com/opendatahub-io/ilab-on-ocp/provenance/v1"
+)
+
+// Statement attests that the subjects were produced by the run of the predicate
+type Statement struct {
+	Type          string    `json:"_type"`
+	Subject       []Subject `json:"subject"`
+	PredicateType string    `json:"predicateType"`
+	Predicate     Predicate `json:"predicate"`
+}
+
+// Subject is an artifact by path relative to the statement, and its digest by algorithm
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Predicate describes how the subjects were produced
+type Predicate struct {
+	RunID    string `json:"runId"`
+	Pipeline string `json:"pipeline"`
+	// Task is the pipeline task which produced the subjects
+	Task   string                 `json:"task"`
+	Inputs map[string]interface{} `json:"inputs"`
+	// ConfigDigest is the digest of the inputs, see ConfigDigest
+	ConfigDigest string    `json:"configDigest"`
+	Images       []string  `json:"images"`
+	SignedAt     time.Time `json:"signedAt"`
+}
+
+// Digest returns the hex encoded SHA-256 digest of a content
+func Digest(content io.Reader) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, content); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// ConfigDigest returns the digest of the run inputs, from their JSON encoding with sorted keys
+func ConfigDigest(inputs map[string]interface{}) (string, error) {
+	data, err := json.Marshal(inputs)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode the run inputs: %w", err)
+	}
+	digest, err := Digest(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + digest, nil
+}
+
+// NewStatement returns the statement of subjects digested by path, the predicate config digest computed from its
+// inputs
+func NewStatement(digests map[string]string, predicate Predicate) (Statement, error) {
+	if len(digests) == 0 {
+		return Statement{}, errors.New("no artifact to sign")
+	}
+	digest, err := ConfigDigest(predicate.Inputs)
+	if err != nil {
+		return Statement{}, err
+	}
+	predicate.ConfigDigest = digest
+	sort.Strings(predicate.Images)
+
+	statement := Statement{Type: StatementType, PredicateType: PredicateType, Predicate: predicate}
+	for name, digest := range digests {
+		statement.Subject = append(statement.Subject, Subject{Name: name, Digest: map[string]string{"sha256": digest}})
+	}
+	sort.Slice(statement.Subject, func(i, j int) bool { return statement.Subject[i].Name < statement.Subject[j].Name })
+	return statement, nil
+}
+
+// ParsePrivateKey reads an unencrypted PKCS #8 PEM private key, ECDSA or Ed25519
+func ParsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block in the private key")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	switch key := key.(type) {
+	case *ecdsa.PrivateKey:
+		return key, nil
+	case ed25519.PrivateKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type %T, use ECDSA or Ed25519", key)
+	}
+}
+
+// ParsePublicKey reads a PKIX PEM public key, ECDSA or Ed25519
+func ParsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block in the public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	switch key.(type) {
+	case *ecdsa.PublicKey, ed25519.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T, use ECDSA or Ed25519", key)
+	}
+}
+
+// Sign encodes a statement and returns it with its base64 encoded signature. ECDSA signs the SHA-256 digest of the
+// statement, Ed25519 the statement itself.
+func Sign(statement Statement, key crypto.Signer) (data, signature []byte, err error) {
+	data, err = json.MarshalIndent(statement, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode the statement: %w", err)
+	}
+	var raw []byte
+	if _, ok := key.(ed25519.PrivateKey); ok {
+		raw, err = key.Sign(rand.Reader, data, crypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(data)
+		raw, err = key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to sign the statement: %w", err)
+	}
+	return data, []byte(base64.StdEncoding.EncodeToString(raw)), nil
+}
+
+// Verify checks the signature of an encoded statement and returns the statement
+func Verify(data, signature []byte, key crypto.PublicKey) (Statement, error) {
+	raw, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signature)))
+	if err != nil {
+		return Statement{}, fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	var valid bool
+	switch key := key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		valid = ecdsa.VerifyASN1(key, digest[:], raw)
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(key, data, raw)
+	default:
+		return Statement{}, fmt.Errorf("unsupported public key type %T", key)
+	}
+	if !valid {
+		return Statement{}, errors.New("the signature of the statement is invalid")
+	}
+
+	var statement Statement
+	if err := json.Unmarshal(data, &statement); err != nil {
+		return Statement{}, fmt.Errorf("invalid statement: %w", err)
+	}
+	if statement.Type != StatementType || statement.PredicateType != PredicateType {
+		return Statement{}, fmt.Errorf("unexpected statement type %s with predicate %s", statement.Type, statement.PredicateType)
+	}
+	return statement, nil
+}
+
+// VerifySubjects checks the digest of every subject of a statement against the content open returns for its name
+func VerifySubjects(statement Statement, open func(name string) (io.ReadCloser, error)) error {
+	var problems []error
+	for _, subject := range statement.Subject {
+		expected, ok := subject.Digest["sha256"]
+		if !ok {
+			problems = append(problems, fmt.Errorf("%s: no sha256 digest", subject.Name))
+			continue
+		}
+		content, err := open(subject.Name)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", subject.Name, err))
+			continue
+		}
+		digest, err := Digest(content)
+		content.Close()
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", subject.Name, err))
+		} else if digest != expected {
+			problems = append(problems, fmt.Errorf("%s: digest %s does not match the signed %s", subject.Name, digest, expected))
+		}
+	}
+	return errors.Join(problems...)
+}
+
+// VerifyDir verifies the statement of a directory downloaded from the artifact store, and every artifact it covers
+func VerifyDir(dir string, key crypto.PublicKey) (Statement, error) {
+	data, err := os.ReadFile(filepath.Join(dir, StatementFile))
+	if err != nil {
+		return Statement{}, err
+	}
+	signature, err := os.ReadFile(filepath.Join(dir, SignatureFile))
+	if err != nil {
+		return Statement{}, err
+	}
+	statement, err := Verify(data, signature, key)
+	if err != nil {
+		return Statement{}, err
+	}
+	err = VerifySubjects(statement, func(name string) (io.ReadCloser, error) {
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			return nil, fmt.Errorf("subject is outside of %s", dir)
+		}
+		return os.Open(filepath.Join(dir, filepath.FromSlash(name)))
+	})
+	return statement, err
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provenance
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func encodeKeys(t *testing.T, private crypto.Signer) (privatePEM, publicPEM []byte) {
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	public, err := x509.MarshalPKIXPublicKey(private.Public())
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public})
+}
+
+func TestSignAndVerifyDir(t *testing.T) {
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	for name, generated := range map[string]crypto.Signer{"ecdsa": ecdsaKey, "ed25519": ed25519Key} {
+		t.Run(name, func(t *testing.T) {
+			privatePEM, publicPEM := encodeKeys(t, generated)
+			private, err := ParsePrivateKey(privatePEM)
+			require.NoError(t, err)
+			public, err := ParsePublicKey(publicPEM)
+			require.NoError(t, err)
+
+			dir := t.TempDir()
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, "model"), 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "model", "config.json"), []byte(`{"model_type": "granite"}`), 0o644))
+			digest, err := Digest(strings.NewReader(`{"model_type": "granite"}`))
+			require.NoError(t, err)
+
+			statement, err := NewStatement(map[string]string{"model/config.json": digest}, Predicate{
+				RunID:  "run",
+				Task:   "upload-model-op",
+				Inputs: map[string]interface{}{"train_num_epochs_phase_1": 2, "sdg_scale_factor": 30},
+				Images: []string{"quay.io/training:1", "quay.io/sdg:1"},
+			})
+			require.NoError(t, err)
+			require.Equal(t, []string{"quay.io/sdg:1", "quay.io/training:1"}, statement.Predicate.Images)
+			// The digest does not depend on the order of the inputs
+			configDigest, err := ConfigDigest(map[string]interface{}{"sdg_scale_factor": 30, "train_num_epochs_phase_1": 2})
+			require.NoError(t, err)
+			require.Equal(t, configDigest, statement.Predicate.ConfigDigest)
+
+			data, signature, err := Sign(statement, private)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, StatementFile), data, 0o644))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, SignatureFile), signature, 0o644))
+
+			verified, err := VerifyDir(dir, public)
+			require.NoError(t, err)
+			require.Equal(t, statement.Subject, verified.Subject)
+			require.Equal(t, "run", verified.Predicate.RunID)
+
+			// A modified artifact breaks its digest
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "model", "config.json"), []byte(`{"model_type": "llama"}`), 0o644))
+			_, err = VerifyDir(dir, public)
+			require.ErrorContains(t, err, "model/config.json: digest")
+
+			// A modified statement breaks its signature
+			tampered := strings.Replace(string(data), digest, strings.Repeat("0", len(digest)), 1)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, StatementFile), []byte(tampered), 0o644))
+			_, err = VerifyDir(dir, public)
+			require.ErrorContains(t, err, "the signature of the statement is invalid")
+		})
+	}
+}
+
+func TestVerifyDirOutside(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	statement, err := NewStatement(map[string]string{"../secret": "00"}, Predicate{})
+	require.NoError(t, err)
+	data, signature, err := Sign(statement, key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, StatementFile), data, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, SignatureFile), signature, 0o644))
+	_, err = VerifyDir(dir, key.Public())
+	require.ErrorContains(t, err, "../secret: subject is outside of")
+
+	_, err = NewStatement(nil, Predicate{})
+	require.ErrorContains(t, err, "no artifact to sign")
+}
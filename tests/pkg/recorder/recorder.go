@@ -0,0 +1,275 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package recorder implements a reverse proxy capturing sampled request/response payloads exchanged with an
+// OpenAI-compatible endpoint, such as the teacher or judge model servers
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMaxBodyBytes is the number of bytes of a payload kept in a record
+const DefaultMaxBodyBytes = 64 * 1024
+
+// usageTailBytes is the number of trailing bytes of a response kept to read the usage from, which completions report
+// at the end of the response
+const usageTailBytes = 16 * 1024
+
+// Exchange is a recorded request/response pair. Headers are not recorded so API tokens never end up in records.
+type Exchange struct {
+	Time       time.Time       `json:"time"`
+	Method     string          `json:"method"`
+	Path       string          `json:"path"`
+	Status     int             `json:"status"`
+	DurationMs int64           `json:"duration_ms"`
+	Request    json.RawMessage `json:"request,omitempty"`
+	Response   json.RawMessage `json:"response,omitempty"`
+}
+
+// Usage tallies the tokens of the completions served through the proxy
+type Usage struct {
+	Requests         int `json:"requests"`
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	// Estimated counts the requests whose response reported no usage, their tokens are estimated from the payload sizes
+	Estimated int `json:"estimated"`
+}
+
+// UsageRecord is the line written to the sink with the cumulative usage after every completion, telling it apart
+// from exchanges. The last usage record holds the totals.
+type UsageRecord struct {
+	Usage Usage `json:"usage"`
+}
+
+// bytesPerToken approximates the tokenization of English text when a response reports no usage
+const bytesPerToken = 4
+
+// Proxy forwards every request to the target and writes a sample of the exchanges to the sink as JSON lines
+type Proxy struct {
+	// SampleRate is the fraction of exchanges recorded, between 0 and 1
+	SampleRate float64
+	// MaxBodyBytes bounds the recorded payloads, longer payloads are recorded truncated as strings
+	MaxBodyBytes int
+
+	proxy *httputil.ReverseProxy
+	sink  io.Writer
+	mutex sync.Mutex
+	count int
+	usage Usage
+}
+
+// NewProxy creates a proxy to the scheme and host of target, recording to sink
+func NewProxy(target *url.URL, transport http.RoundTripper, sink io.Writer) *Proxy {
+	proxy := &httputil.ReverseProxy{
+		Director: func(req *http.Request) {
+			req.URL.Scheme = target.Scheme
+			req.URL.Host = target.Host
+			req.Host = target.Host
+		},
+		Transport: transport,
+	}
+	return &Proxy{SampleRate: 1, MaxBodyBytes: DefaultMaxBodyBytes, proxy: proxy, sink: sink}
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	sampled := p.sample()
+
+	start := time.Now()
+	var requestBody []byte
+	if req.Body != nil {
+		requestBody, _ = io.ReadAll(req.Body)
+		req.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+	capture := &captureWriter{ResponseWriter: w, status: http.StatusOK, limit: p.MaxBodyBytes}
+	p.proxy.ServeHTTP(capture, req)
+
+	if strings.HasSuffix(req.URL.Path, "/completions") && capture.status == http.StatusOK {
+		p.account(requestBody, capture)
+	}
+	if !sampled {
+		return
+	}
+	p.write(Exchange{
+		Time:       start.UTC(),
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		Status:     capture.status,
+		DurationMs: time.Since(start).Milliseconds(),
+		Request:    p.payload(requestBody, len(requestBody)),
+		Response:   p.payload(capture.body.Bytes(), capture.size),
+	})
+}
+
+// sample decides deterministically whether the next exchange is recorded, spreading the records evenly
+func (p *Proxy) sample() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.count++
+	return int(float64(p.count)*p.SampleRate) != int(float64(p.count-1)*p.SampleRate)
+}
+
+// Usage returns the tokens tallied so far
+func (p *Proxy) Usage() Usage {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.usage
+}
+
+// account tallies the tokens of a completion and writes the cumulative usage to the sink, under the same lock so
+// the usage records are written in order and the proxy can stop at any time without losing the totals
+func (p *Proxy) account(requestBody []byte, capture *captureWriter) {
+	prompt, completion, ok := ParseUsage(capture.usageBody())
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.usage.Requests++
+	if !ok {
+		prompt, completion = len(requestBody)/bytesPerToken, capture.size/bytesPerToken
+		p.usage.Estimated++
+	}
+	p.usage.PromptTokens += prompt
+	p.usage.CompletionTokens += completion
+	p.writeLocked(UsageRecord{Usage: p.usage})
+}
+
+// ParseUsage reads the token usage reported in a completion response, either a JSON document or a stream of
+// server-sent events where the last chunk holds the usage. The body may be the tail of a longer response, the usage
+// object is then read from where it starts.
+func ParseUsage(body []byte) (int, int, bool) {
+	type usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	}
+	type usageResponse struct {
+		Usage *usage `json:"usage"`
+	}
+
+	var response usageResponse
+	if json.Unmarshal(body, &response) == nil {
+		if response.Usage == nil {
+			return 0, 0, false
+		}
+		return response.Usage.PromptTokens, response.Usage.CompletionTokens, true
+	}
+	if !bytes.Contains(body, []byte("data:")) {
+		start := bytes.LastIndex(body, []byte(`"usage":`))
+		if start < 0 {
+			return 0, 0, false
+		}
+		var tail usage
+		if json.NewDecoder(bytes.NewReader(body[start+len(`"usage":`):])).Decode(&tail) != nil {
+			return 0, 0, false
+		}
+		return tail.PromptTokens, tail.CompletionTokens, true
+	}
+
+	prompt, completion, found := 0, 0, false
+	for _, line := range strings.Split(string(body), "\n") {
+		data, ok := strings.CutPrefix(strings.TrimSpace(line), "data:")
+		if !ok {
+			continue
+		}
+		var chunk usageResponse
+		if json.Unmarshal([]byte(strings.TrimSpace(data)), &chunk) == nil && chunk.Usage != nil {
+			prompt, completion, found = chunk.Usage.PromptTokens, chunk.Usage.CompletionTokens, true
+		}
+	}
+	return prompt, completion, found
+}
+
+func (p *Proxy) write(record interface{}) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.writeLocked(record)
+}
+
+// writeLocked writes a record to the sink while the caller holds the mutex
+func (p *Proxy) writeLocked(record interface{}) {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+	_, _ = p.sink.Write(append(line, '\n'))
+}
+
+// payload keeps JSON bodies as is and records anything else, e.g. streamed responses, as a string
+func (p *Proxy) payload(body []byte, size int) json.RawMessage {
+	if size == 0 {
+		return nil
+	}
+	if size <= p.MaxBodyBytes && len(body) == size && json.Valid(body) {
+		return body
+	}
+	if len(body) > p.MaxBodyBytes {
+		body = body[:p.MaxBodyBytes]
+	}
+	text := string(body)
+	if size > len(body) {
+		text += "...(truncated)"
+	}
+	encoded, _ := json.Marshal(text)
+	return encoded
+}
+
+// captureWriter keeps the status, the first bytes and the last bytes of a response while passing it through
+type captureWriter struct {
+	http.ResponseWriter
+	status int
+	limit  int
+	size   int
+	body   bytes.Buffer
+	tail   []byte
+}
+
+func (w *captureWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *captureWriter) Write(data []byte) (int, error) {
+	w.size += len(data)
+	if remaining := w.limit - w.body.Len(); remaining > 0 {
+		if len(data) < remaining {
+			remaining = len(data)
+		}
+		w.body.Write(data[:remaining])
+	}
+	w.tail = append(w.tail, data...)
+	if len(w.tail) > usageTailBytes {
+		w.tail = append(w.tail[:0], w.tail[len(w.tail)-usageTailBytes:]...)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// usageBody returns the whole response when it was captured, its last bytes otherwise
+func (w *captureWriter) usageBody() []byte {
+	if w.body.Len() == w.size {
+		return w.body.Bytes()
+	}
+	return w.tail
+}
+
+// Flush keeps streamed completions flowing through the proxy
+func (w *captureWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
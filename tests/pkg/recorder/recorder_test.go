@@ -0,0 +1,131 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recorder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxy(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"echo":` + string(body) + `}`))
+	}))
+	defer upstream.Close()
+
+	target, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+	var sink bytes.Buffer
+	proxy := NewProxy(target, http.DefaultTransport, &sink)
+	proxy.SampleRate = 0.5
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	for i := 0; i < 4; i++ {
+		req, err := http.NewRequest("POST", server.URL+"/v1/chat/completions", strings.NewReader(`{"n":1}`))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer token")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		require.JSONEq(t, `{"echo":{"n":1}}`, string(body))
+	}
+
+	require.Equal(t, Usage{Requests: 4, PromptTokens: 4, CompletionTokens: 16, Estimated: 4}, proxy.Usage())
+
+	var exchanges []Exchange
+	var usage []UsageRecord
+	scanner := bufio.NewScanner(&sink)
+	for scanner.Scan() {
+		if bytes.HasPrefix(scanner.Bytes(), []byte(`{"usage":`)) {
+			var record UsageRecord
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+			usage = append(usage, record)
+			continue
+		}
+		var exchange Exchange
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &exchange))
+		exchanges = append(exchanges, exchange)
+	}
+	require.Len(t, usage, 4, "A usage record is written after every completion")
+	require.Equal(t, proxy.Usage(), usage[3].Usage)
+	require.Len(t, exchanges, 2)
+	require.Equal(t, "/v1/chat/completions", exchanges[0].Path)
+	require.Equal(t, http.StatusOK, exchanges[0].Status)
+	require.JSONEq(t, `{"n":1}`, string(exchanges[0].Request))
+	require.JSONEq(t, `{"echo":{"n":1}}`, string(exchanges[0].Response))
+	require.NotContains(t, sink.String(), "token")
+}
+
+func TestPayloadTruncation(t *testing.T) {
+	proxy := &Proxy{MaxBodyBytes: 4}
+	require.JSONEq(t, `"{\"ab...(truncated)"`, string(proxy.payload([]byte(`{"abc":1}`), 9)))
+	require.JSONEq(t, `"data"`, string(proxy.payload([]byte("data"), 4)))
+	require.Nil(t, proxy.payload(nil, 0))
+}
+
+func TestParseUsage(t *testing.T) {
+	prompt, completion, ok := ParseUsage([]byte(`{"choices":[],"usage":{"prompt_tokens":12,"completion_tokens":30,"total_tokens":42}}`))
+	require.True(t, ok)
+	require.Equal(t, 12, prompt)
+	require.Equal(t, 30, completion)
+
+	stream := "data: {\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\n\ndata: {\"choices\":[],\"usage\":{\"prompt_tokens\":5,\"completion_tokens\":1}}\n\ndata: [DONE]\n"
+	prompt, completion, ok = ParseUsage([]byte(stream))
+	require.True(t, ok)
+	require.Equal(t, 5, prompt)
+	require.Equal(t, 1, completion)
+
+	prompt, completion, ok = ParseUsage([]byte(`xxx"}}],"usage":{"prompt_tokens":3,"completion_tokens":9}}`))
+	require.True(t, ok)
+	require.Equal(t, 3, prompt)
+	require.Equal(t, 9, completion)
+
+	_, _, ok = ParseUsage([]byte(`{"choices":[]}`))
+	require.False(t, ok)
+}
+
+func TestUsageOfLongResponse(t *testing.T) {
+	content := strings.Repeat("x", 2*DefaultMaxBodyBytes)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"` + content + `"}}],"usage":{"prompt_tokens":7,"completion_tokens":20000}}`))
+	}))
+	defer upstream.Close()
+
+	target, err := url.Parse(upstream.URL)
+	require.NoError(t, err)
+	proxy := NewProxy(target, http.DefaultTransport, io.Discard)
+	server := httptest.NewServer(proxy)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/v1/chat/completions", "application/json", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	require.Equal(t, Usage{Requests: 1, PromptTokens: 7, CompletionTokens: 20000}, proxy.Usage())
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runcontrol
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+// CancellationFile is the file of the output directory recording the cancellation of a run
+const CancellationFile = "cancellation.json"
+
+// PipelineServer is the pipeline server the runs were started on
+type PipelineServer struct {
+	URL         string
+	BearerToken string
+	Client      *http.Client
+}
+
+// Cancellation records the cleanup of a canceled run
+type Cancellation struct {
+	RunID       string    `json:"run_id"`
+	Workflow    string    `json:"workflow"`
+	State       string    `json:"state"`
+	CanceledAt  time.Time `json:"canceled_at"`
+	Logs        []string  `json:"logs"`
+	DeletedJobs []string  `json:"deleted_jobs"`
+	DeletedPVCs []string  `json:"deleted_pvcs"`
+	// Errors are the cleanup steps that failed, the other steps are still applied
+	Errors []string `json:"errors,omitempty"`
+}
+
+// RunPVCNames returns the names of the PVCs the pipeline creates for an Argo workflow, each named after the workflow
+// and the suffix of its CreatePVC task. The DeletePVC tasks of a canceled run do not execute.
+func RunPVCNames(workflow string) []string {
+	return []string{workflow + "-sdg", workflow + "-model-cache", workflow + "-output"}
+}
+
+// Cancel terminates a pipeline run and cleans up the resources its remaining tasks would have deleted: the logs of
+// the run pods are saved into the output directory first, then the run is terminated on the pipeline server and its
+// PyTorchJobs and PVCs are deleted. The cancellation is recorded in the CancellationFile of the output directory.
+// Cleanup steps are applied even when others fail, the failures are recorded and returned.
+func Cancel(ctx context.Context, client kubernetes.Interface, dynamicClient dynamic.Interface, server PipelineServer, namespace, runID, dir string, now time.Time) (Cancellation, error) {
+	cancellation := Cancellation{RunID: runID, State: "CANCELED", CanceledAt: now.UTC()}
+	fail := func(err error) {
+		cancellation.Errors = append(cancellation.Errors, err.Error())
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return cancellation, err
+	}
+
+	workflow, err := RunWorkflow(ctx, client, namespace, runID)
+	if err != nil {
+		fail(err)
+	}
+	cancellation.Workflow = workflow
+
+	logs, err := SaveRunLogs(ctx, client, namespace, runID, workflow, dir)
+	cancellation.Logs = logs
+	if err != nil {
+		fail(err)
+	}
+
+	if err := server.Terminate(ctx, runID); err != nil {
+		fail(err)
+	}
+
+	if workflow != "" {
+		jobs := dynamicClient.Resource(PyTorchJobGVR).Namespace(namespace)
+		for _, name := range TrainingJobNames(workflow) {
+			propagation := metav1.DeletePropagationBackground
+			err := jobs.Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+			switch {
+			case err == nil:
+				cancellation.DeletedJobs = append(cancellation.DeletedJobs, name)
+			case !errors.IsNotFound(err):
+				fail(fmt.Errorf("failed to delete PyTorchJob %s: %w", name, err))
+			}
+		}
+		for _, name := range RunPVCNames(workflow) {
+			err := client.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+			switch {
+			case err == nil:
+				cancellation.DeletedPVCs = append(cancellation.DeletedPVCs, name)
+			case !errors.IsNotFound(err):
+				fail(fmt.Errorf("failed to delete PVC %s: %w", name, err))
+			}
+		}
+	}
+
+	record, err := json.MarshalIndent(cancellation, "", "  ")
+	if err != nil {
+		return cancellation, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, CancellationFile), record, 0o644); err != nil {
+		return cancellation, err
+	}
+	if len(cancellation.Errors) > 0 {
+		return cancellation, fmt.Errorf("cancellation of run %s incomplete: %v", runID, cancellation.Errors)
+	}
+	return cancellation, nil
+}
+
+// SaveRunLogs writes the logs of every container of the run pods and of the PyTorchJob pods of the workflow into
+// the directory, one "<pod>_<container>.log" file each, and returns the file names
+func SaveRunLogs(ctx context.Context, client kubernetes.Interface, namespace, runID, workflow, dir string) ([]string, error) {
+	selectors := []string{fmt.Sprintf("%s=%s", RunIDLabel, runID)}
+	if workflow != "" {
+		jobs := TrainingJobNames(workflow)
+		selectors = append(selectors, fmt.Sprintf("%s in (%s,%s)", TrainingJobNameLabel, jobs[0], jobs[1]))
+	}
+
+	var files []string
+	for _, selector := range selectors {
+		pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return files, fmt.Errorf("failed to list the pods of run %s: %w", runID, err)
+		}
+		for _, pod := range pods.Items {
+			for _, container := range pod.Spec.Containers {
+				name := fmt.Sprintf("%s_%s.log", pod.Name, container.Name)
+				if err := saveLog(ctx, client, pod, container.Name, filepath.Join(dir, name)); err != nil {
+					return files, err
+				}
+				files = append(files, name)
+			}
+		}
+	}
+	return files, nil
+}
+
+func saveLog(ctx context.Context, client kubernetes.Interface, pod corev1.Pod, container, path string) error {
+	stream, err := client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{Container: container}).Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read the logs of %s/%s: %w", pod.Name, container, err)
+	}
+	defer stream.Close()
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(file, stream)
+	return err
+}
+
+// Terminate terminates a run on the pipeline server. Argo stops the running task pods, the tasks not started yet
+// never execute.
+func (s PipelineServer) Terminate(ctx context.Context, runID string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/apis/v2beta1/runs/%s:terminate", s.URL, runID), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Authorization", "Bearer "+s.BearerToken)
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to terminate run %s: %w", runID, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("terminating run %s failed with status %d: %s", runID, resp.StatusCode, string(body))
+	}
+	return nil
+}
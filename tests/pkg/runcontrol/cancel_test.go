@@ -0,0 +1,104 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runcontrol
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCancel(t *testing.T) {
+	ctx := context.Background()
+	var terminated []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		terminated = append(terminated, r.Method+" "+r.URL.Path)
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	pod := func(name string, labels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ilab", Labels: labels},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "main"}}},
+		}
+	}
+	pvc := func(name string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ilab"}}
+	}
+	client := fake.NewSimpleClientset(
+		pod("sdg", map[string]string{RunIDLabel: "run", WorkflowLabel: "instructlab-x7k2p"}),
+		pod("train-phase-1-instructlab-x7k2p-master-0", map[string]string{TrainingJobNameLabel: "train-phase-1-instructlab-x7k2p"}),
+		pod("other", map[string]string{RunIDLabel: "other"}),
+		pvc("instructlab-x7k2p-sdg"),
+		pvc("instructlab-x7k2p-output"),
+		pvc("instructlab-other-sdg"),
+	)
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "kubeflow.org/v1",
+		"kind":       "PyTorchJob",
+		"metadata":   map[string]interface{}{"name": "train-phase-1-instructlab-x7k2p", "namespace": "ilab"},
+	}})
+
+	dir := t.TempDir()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	cancellation, err := Cancel(ctx, client, dynamicClient, PipelineServer{URL: server.URL, BearerToken: "token"}, "ilab", "run", dir, now)
+	require.NoError(t, err)
+	require.Equal(t, []string{"POST /apis/v2beta1/runs/run:terminate"}, terminated)
+	require.Equal(t, Cancellation{
+		RunID:       "run",
+		Workflow:    "instructlab-x7k2p",
+		State:       "CANCELED",
+		CanceledAt:  now,
+		Logs:        []string{"sdg_main.log", "train-phase-1-instructlab-x7k2p-master-0_main.log"},
+		DeletedJobs: []string{"train-phase-1-instructlab-x7k2p"},
+		DeletedPVCs: []string{"instructlab-x7k2p-sdg", "instructlab-x7k2p-output"},
+	}, cancellation)
+
+	pvcs, err := client.CoreV1().PersistentVolumeClaims("ilab").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, pvcs.Items, 1)
+	logs, err := os.ReadFile(filepath.Join(dir, "sdg_main.log"))
+	require.NoError(t, err)
+	require.Equal(t, "fake logs", string(logs))
+
+	var recorded Cancellation
+	data, err := os.ReadFile(filepath.Join(dir, CancellationFile))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &recorded))
+	require.Equal(t, cancellation, recorded)
+
+	// The remaining steps are applied when the pipeline server refuses the termination
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "run not found", http.StatusNotFound)
+	})
+	cancellation, err = Cancel(ctx, client, dynamicClient, PipelineServer{URL: server.URL, BearerToken: "token"}, "ilab", "run", dir, now)
+	require.ErrorContains(t, err, "terminating run run failed with status 404")
+	require.Len(t, cancellation.Logs, 2)
+	require.Empty(t, cancellation.DeletedPVCs)
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package runcontrol pauses, resumes and cancels pipeline runs, e.g. to free the GPUs of a shared cluster for a
+// while. Training is paused by suspending the PyTorchJobs of the run through spec.runPolicy.suspend: the Training
+// Operator deletes the pods of a suspended job and recreates them when the job is resumed.
+package runcontrol
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// RunIDLabel is the label the pipeline server sets on every pod of a pipeline run
+	RunIDLabel = "pipeline/runid"
+	// WorkflowLabel is set by Argo on every pod of a workflow, i.e. of a pipeline run
+	WorkflowLabel = "workflows.argoproj.io/workflow"
+	// TrainingJobNameLabel is set by the Training Operator on every PyTorchJob pod
+	TrainingJobNameLabel = "training.kubeflow.org/job-name"
+	// StateAnnotation records on a PyTorchJob whether it was paused or resumed
+	StateAnnotation = "ilab.opendatahub.io/run-state"
+	// StateChangedAnnotation records when the state of StateAnnotation was set, in RFC 3339 format
+	StateChangedAnnotation = "ilab.opendatahub.io/run-state-changed-at"
+	// StatePaused and StateResumed are the values of StateAnnotation
+	StatePaused  = "paused"
+	StateResumed = "resumed"
+)
+
+// PyTorchJobGVR is the resource of the PyTorchJobs of the Training Operator
+var PyTorchJobGVR = schema.GroupVersionResource{
+	Group:    "kubeflow.org",
+	Version:  "v1",
+	Resource: "pytorchjobs",
+}
+
+// TrainingJob is a PyTorchJob of a pipeline run
+type TrainingJob struct {
+	Name      string
+	Suspended bool
+	// State and Changed are read from the annotations set by Pause and Resume, empty for jobs never paused
+	State   string
+	Changed string
+}
+
+// TrainingJobNames returns the names of the PyTorchJobs the training phases of an Argo workflow create. The launcher
+// names them "train-phase-<phase>-" after the SDG PVC name "<workflow>-sdg" passed through Python's rstrip("-sdg"),
+// which strips any trailing '-', 's', 'd' and 'g' as TrimRight does.
+func TrainingJobNames(workflow string) []string {
+	suffix := strings.TrimRight(workflow+"-sdg", "-sdg")
+	return []string{"train-phase-1-" + suffix, "train-phase-2-" + suffix}
+}
+
+// RunWorkflow returns the Argo workflow of a pipeline run, read from the labels of its pods
+func RunWorkflow(ctx context.Context, client kubernetes.Interface, namespace, runID string) (string, error) {
+	pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", RunIDLabel, runID)})
+	if err != nil {
+		return "", fmt.Errorf("failed to list the pods of run %s: %w", runID, err)
+	}
+	for _, pod := range pods.Items {
+		if workflow := pod.Labels[WorkflowLabel]; workflow != "" {
+			return workflow, nil
+		}
+	}
+	return "", fmt.Errorf("no pod of run %s found in namespace %s", runID, namespace)
+}
+
+// RunTrainingJobs returns the PyTorchJobs a pipeline run created so far, in phase order
+func RunTrainingJobs(ctx context.Context, client kubernetes.Interface, dynamicClient dynamic.Interface, namespace, runID string) ([]TrainingJob, error) {
+	workflow, err := RunWorkflow(ctx, client, namespace, runID)
+	if err != nil {
+		return nil, err
+	}
+	var jobs []TrainingJob
+	for _, name := range TrainingJobNames(workflow) {
+		job, err := dynamicClient.Resource(PyTorchJobGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			continue
+		}
+		jobs = append(jobs, trainingJob(job))
+	}
+	return jobs, nil
+}
+
+func trainingJob(job *unstructured.Unstructured) TrainingJob {
+	suspended, _, _ := unstructured.NestedBool(job.Object, "spec", "runPolicy", "suspend")
+	annotations := job.GetAnnotations()
+	return TrainingJob{
+		Name:      job.GetName(),
+		Suspended: suspended,
+		State:     annotations[StateAnnotation],
+		Changed:   annotations[StateChangedAnnotation],
+	}
+}
+
+// Pause suspends a PyTorchJob, its pods are deleted and their GPUs freed. The launcher task of the pipeline keeps
+// waiting for the job, the pause counts against its job timeout.
+func Pause(ctx context.Context, client dynamic.Interface, namespace, name string, now time.Time) error {
+	return setSuspended(ctx, client, namespace, name, true, StatePaused, now)
+}
+
+// Resume resumes a suspended PyTorchJob, its pods are recreated and the training starts over in them
+func Resume(ctx context.Context, client dynamic.Interface, namespace, name string, now time.Time) error {
+	return setSuspended(ctx, client, namespace, name, false, StateResumed, now)
+}
+
+func setSuspended(ctx context.Context, client dynamic.Interface, namespace, name string, suspend bool, state string, now time.Time) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				StateAnnotation:        state,
+				StateChangedAnnotation: now.UTC().Format(time.RFC3339),
+			},
+		},
+		"spec": map[string]interface{}{
+			"runPolicy": map[string]interface{}{"suspend": suspend},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := client.Resource(PyTorchJobGVR).Namespace(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to set the suspension of PyTorchJob %s to %t: %w", name, suspend, err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runcontrol
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestTrainingJobNames(t *testing.T) {
+	require.Equal(t, []string{"train-phase-1-instructlab-x7k2p", "train-phase-2-instructlab-x7k2p"}, TrainingJobNames("instructlab-x7k2p"))
+	// rstrip("-sdg") strips the trailing characters of the workflow name too
+	require.Equal(t, []string{"train-phase-1-instructlab-x7k", "train-phase-2-instructlab-x7k"}, TrainingJobNames("instructlab-x7kgd"))
+}
+
+func TestPauseResume(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewSimpleClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "sdg",
+		Namespace: "ilab",
+		Labels:    map[string]string{RunIDLabel: "run", WorkflowLabel: "instructlab-x7k2p"},
+	}})
+	job := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "kubeflow.org/v1",
+		"kind":       "PyTorchJob",
+		"metadata":   map[string]interface{}{"name": "train-phase-1-instructlab-x7k2p", "namespace": "ilab"},
+		"spec":       map[string]interface{}{"runPolicy": map[string]interface{}{}},
+	}}
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), job)
+
+	jobs, err := RunTrainingJobs(ctx, client, dynamicClient, "ilab", "run")
+	require.NoError(t, err)
+	require.Equal(t, []TrainingJob{{Name: "train-phase-1-instructlab-x7k2p"}}, jobs)
+
+	paused := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, Pause(ctx, dynamicClient, "ilab", jobs[0].Name, paused))
+	jobs, err = RunTrainingJobs(ctx, client, dynamicClient, "ilab", "run")
+	require.NoError(t, err)
+	require.Equal(t, []TrainingJob{{Name: "train-phase-1-instructlab-x7k2p", Suspended: true, State: StatePaused, Changed: "2025-03-01T12:00:00Z"}}, jobs)
+
+	require.NoError(t, Resume(ctx, dynamicClient, "ilab", jobs[0].Name, paused.Add(10*time.Minute)))
+	jobs, err = RunTrainingJobs(ctx, client, dynamicClient, "ilab", "run")
+	require.NoError(t, err)
+	require.Equal(t, []TrainingJob{{Name: "train-phase-1-instructlab-x7k2p", State: StateResumed, Changed: "2025-03-01T12:10:00Z"}}, jobs)
+
+	require.Error(t, Pause(ctx, dynamicClient, "ilab", "train-phase-2-instructlab-x7k2p", paused))
+	_, err = RunTrainingJobs(ctx, client, dynamicClient, "ilab", "other")
+	require.ErrorContains(t, err, "no pod of run other found")
+}
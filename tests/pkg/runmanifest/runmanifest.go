@@ -0,0 +1,237 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package runmanifest exports what a pipeline run needs to be reproduced into a single manifest file, and replays the
+// manifest as a new run, e.g. to reproduce the failure of a customer run on another cluster. The manifest holds the
+// pipeline version and parameters of the run, its pipeline root, under which its data is stored, the images its pods
+// ran with their resolved digests, and the commit of the taxonomy it cloned.
+package runmanifest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/ilab"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/runcontrol"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Version is the version of the manifest format
+const Version = 1
+
+// Manifest is everything needed to reproduce a run
+type Manifest struct {
+	Version    int       `json:"version"`
+	RunID      string    `json:"run_id"`
+	ExportedAt time.Time `json:"exported_at"`
+	// Run is the spec the run was submitted with, its pipeline root is where the data of the run is stored
+	Run      ilab.RunSpec `json:"run"`
+	Taxonomy Taxonomy     `json:"taxonomy"`
+	Images   []Image      `json:"images"`
+}
+
+// Taxonomy is the taxonomy repository a run cloned, from the sdg_repo_* parameters
+type Taxonomy struct {
+	URL    string `json:"url"`
+	Branch string `json:"branch,omitempty"`
+	PR     int64  `json:"pr,omitempty"`
+	// Commit is the commit the branch or pull request pointed to when the run was exported
+	Commit string `json:"commit"`
+}
+
+// Ref is the git ref the pipeline clones, the head of the pull request when one is set
+func (t Taxonomy) Ref() string {
+	if t.PR > 0 {
+		return fmt.Sprintf("refs/pull/%d/head", t.PR)
+	}
+	branch := t.Branch
+	if branch == "" {
+		branch = "main"
+	}
+	return "refs/heads/" + branch
+}
+
+// Image is an image the pods of a run ran and the digest it resolved to, empty when no container of the image started
+type Image struct {
+	Image  string `json:"image"`
+	Digest string `json:"digest"`
+}
+
+// Export reads the spec of a run from the pipeline server, the images of its pods and the commit of its taxonomy
+// into a manifest. The commit is resolved with `git ls-remote`, with the git credentials of the caller.
+func Export(ctx context.Context, client *ilab.Client, runID string, now time.Time) (Manifest, error) {
+	spec, err := client.GetRunSpec(ctx, runID)
+	if err != nil {
+		return Manifest{}, err
+	}
+	manifest := Manifest{Version: Version, RunID: runID, ExportedAt: now.UTC(), Run: spec, Taxonomy: taxonomyOf(spec.Params)}
+	if manifest.Images, err = RunImages(ctx, client, runID); err != nil {
+		return manifest, err
+	}
+	if len(manifest.Images) == 0 {
+		return manifest, fmt.Errorf("no pods found for run %s in namespace %s, the images of the run are unknown", runID, client.Namespace)
+	}
+	if manifest.Taxonomy.Commit, err = ResolveCommit(ctx, manifest.Taxonomy); err != nil {
+		return manifest, err
+	}
+	return manifest, nil
+}
+
+// Replay submits a new run with the spec of the manifest and returns its run ID. The pipeline clones a branch or a pull
+// request, not a commit, so the replay is refused when the ref moved since the export unless force is set.
+func Replay(ctx context.Context, client *ilab.Client, manifest Manifest, force bool) (string, error) {
+	if manifest.Version != Version {
+		return "", fmt.Errorf("unsupported manifest version %d, expected %d", manifest.Version, Version)
+	}
+	commit, err := ResolveCommit(ctx, manifest.Taxonomy)
+	if err != nil {
+		return "", err
+	}
+	if commit != manifest.Taxonomy.Commit && !force {
+		return "", fmt.Errorf("%s of %s moved from %s to %s since the export, the replay would clone another taxonomy",
+			manifest.Taxonomy.Ref(), manifest.Taxonomy.URL, manifest.Taxonomy.Commit, commit)
+	}
+	spec := manifest.Run
+	spec.DisplayName = fmt.Sprintf("%s replay of %s", spec.Pipeline, manifest.RunID)
+	return client.SubmitRun(ctx, spec)
+}
+
+// CheckImages compares the images of a replay with the images of the manifest, and returns the differences: the
+// images which resolved to another digest, the images of the manifest the replay did not run and the new ones
+func CheckImages(manifest Manifest, replayed []Image) []string {
+	recorded := map[string]string{}
+	for _, image := range manifest.Images {
+		recorded[image.Image] = image.Digest
+	}
+	var problems []string
+	for _, image := range replayed {
+		digest, ok := recorded[image.Image]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("image %s was not run by run %s", image.Image, manifest.RunID))
+		case digest != "" && image.Digest != "" && digest != image.Digest:
+			problems = append(problems, fmt.Sprintf("image %s resolved to %s, run %s ran %s", image.Image, image.Digest, manifest.RunID, digest))
+		}
+		delete(recorded, image.Image)
+	}
+	for _, image := range manifest.Images {
+		if _, ok := recorded[image.Image]; ok {
+			problems = append(problems, fmt.Sprintf("image %s of run %s was not run by the replay", image.Image, manifest.RunID))
+		}
+	}
+	return problems
+}
+
+// RunImages returns the images of the pods of a run, init containers included, with their resolved digests
+func RunImages(ctx context.Context, client *ilab.Client, runID string) ([]Image, error) {
+	pods, err := client.Kube.CoreV1().Pods(client.Namespace).List(ctx, metav1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", runcontrol.RunIDLabel, runID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the pods of run %s: %w", runID, err)
+	}
+	digests := map[string]string{}
+	record := func(containers []corev1.Container, statuses []corev1.ContainerStatus) {
+		resolved := map[string]string{}
+		for _, status := range statuses {
+			resolved[status.Name] = imageIDDigest(status.ImageID)
+		}
+		for _, container := range containers {
+			if resolved[container.Name] != "" || digests[container.Image] == "" {
+				digests[container.Image] = resolved[container.Name]
+			}
+		}
+	}
+	for _, pod := range pods.Items {
+		record(pod.Spec.InitContainers, pod.Status.InitContainerStatuses)
+		record(pod.Spec.Containers, pod.Status.ContainerStatuses)
+	}
+	images := make([]Image, 0, len(digests))
+	for image, digest := range digests {
+		images = append(images, Image{Image: image, Digest: digest})
+	}
+	sort.Slice(images, func(i, j int) bool { return images[i].Image < images[j].Image })
+	return images, nil
+}
+
+// imageIDDigest returns the digest of the image ID of a container status, e.g. docker-pullable://quay.io/x@sha256:...
+// or sha256:... depending on the container runtime
+func imageIDDigest(imageID string) string {
+	if index := strings.LastIndex(imageID, "@"); index >= 0 {
+		return imageID[index+1:]
+	}
+	if strings.HasPrefix(imageID, "sha256:") {
+		return imageID
+	}
+	return ""
+}
+
+// taxonomyOf reads the taxonomy repository of the sdg_repo_* parameters, the defaults of the pipeline apply to the
+// missing ones
+func taxonomyOf(params map[string]interface{}) Taxonomy {
+	taxonomy := Taxonomy{Branch: "main"}
+	taxonomy.URL, _ = params["sdg_repo_url"].(string)
+	if branch, ok := params["sdg_repo_branch"].(string); ok {
+		taxonomy.Branch = branch
+	}
+	// The pipeline server returns numbers as JSON numbers, whatever the parameter type
+	if pr, ok := params["sdg_repo_pr"].(float64); ok {
+		taxonomy.PR = int64(pr)
+	}
+	return taxonomy
+}
+
+// ResolveCommit returns the commit the ref of the taxonomy points to
+func ResolveCommit(ctx context.Context, taxonomy Taxonomy) (string, error) {
+	if taxonomy.URL == "" {
+		return "", fmt.Errorf("the run has no sdg_repo_url")
+	}
+	command := exec.CommandContext(ctx, "git", "ls-remote", taxonomy.URL, taxonomy.Ref())
+	command.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	output, err := command.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s of %s: %w", taxonomy.Ref(), taxonomy.URL, err)
+	}
+	fields := strings.Fields(string(output))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("%s of %s not found", taxonomy.Ref(), taxonomy.URL)
+	}
+	return fields[0], nil
+}
+
+// Write writes a manifest to a file
+func Write(path string, manifest Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Read reads a manifest from a file
+func Read(path string) (Manifest, error) {
+	var manifest Manifest
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return manifest, err
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+	return manifest, nil
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runmanifest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/gitserve"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/ilab"
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/runcontrol"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func runPod(name, runID string, images map[string]string) *corev1.Pod {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ilab", Labels: map[string]string{runcontrol.RunIDLabel: runID}}}
+	for image, imageID := range images {
+		container := name + "-" + filepath.Base(image)
+		pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: container, Image: image})
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{Name: container, ImageID: imageID})
+	}
+	return pod
+}
+
+func TestExportReplay(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed, the taxonomy commit cannot be resolved")
+	}
+	ctx := context.Background()
+	signature := gitserve.Signature{Name: "demo", Email: "demo@example.com", When: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	repo, err := gitserve.NewRepository(fstest.MapFS{"qna.yaml": {Data: []byte("version: 3\n")}}, signature, "Taxonomy")
+	require.NoError(t, err)
+	git := httptest.NewServer(gitserve.Handler(map[string]*gitserve.Repository{"taxonomy": repo}))
+	defer git.Close()
+
+	var submitted map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /apis/v2beta1/runs/run-1":
+			_, _ = w.Write([]byte(`{"run_id":"run-1","display_name":"customer run","state":"FAILED",
+				"pipeline_version_reference":{"pipeline_id":"p-1","pipeline_version_id":"v-3"},
+				"runtime_config":{"pipeline_root":"s3://bucket/runs/1","parameters":{
+					"sdg_repo_url":"` + git.URL + `/taxonomy.git","sdg_repo_branch":"main","sdg_repo_pr":0,"train_seed":42}}}`))
+		case "GET /apis/v2beta1/pipelines/p-1":
+			_, _ = w.Write([]byte(`{"pipeline_id":"p-1","display_name":"instructlab"}`))
+		case "GET /apis/v2beta1/pipelines":
+			_, _ = w.Write([]byte(`{"pipelines":[{"pipeline_id":"p-1","display_name":"instructlab"}]}`))
+		case "POST /apis/v2beta1/runs":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&submitted))
+			_, _ = w.Write([]byte(`{"run_id":"run-2"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	kube := fake.NewSimpleClientset(
+		runPod("sdg", "run-1", map[string]string{"quay.io/ilab/sdg:v1": "quay.io/ilab/sdg@sha256:aaa"}),
+		runPod("train", "run-1", map[string]string{"quay.io/ilab/train:v1": "sha256:bbb", "quay.io/ilab/launcher:v1": ""}),
+		runPod("other", "run-9", map[string]string{"quay.io/ilab/other:v1": "sha256:ccc"}),
+	)
+	client := &ilab.Client{Server: runcontrol.PipelineServer{URL: server.URL, BearerToken: "token"}, Kube: kube, Namespace: "ilab"}
+
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	manifest, err := Export(ctx, client, "run-1", now)
+	require.NoError(t, err)
+	require.Equal(t, ilab.RunSpec{
+		Pipeline:        "instructlab",
+		PipelineVersion: "v-3",
+		DisplayName:     "customer run",
+		PipelineRoot:    "s3://bucket/runs/1",
+		Params:          map[string]interface{}{"sdg_repo_url": git.URL + "/taxonomy.git", "sdg_repo_branch": "main", "sdg_repo_pr": float64(0), "train_seed": float64(42)},
+	}, manifest.Run)
+	require.Equal(t, Taxonomy{URL: git.URL + "/taxonomy.git", Branch: "main", Commit: repo.Commit()}, manifest.Taxonomy)
+	require.Equal(t, []Image{
+		{Image: "quay.io/ilab/launcher:v1"},
+		{Image: "quay.io/ilab/sdg:v1", Digest: "sha256:aaa"},
+		{Image: "quay.io/ilab/train:v1", Digest: "sha256:bbb"},
+	}, manifest.Images)
+
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	require.NoError(t, Write(path, manifest))
+	read, err := Read(path)
+	require.NoError(t, err)
+	require.Equal(t, manifest, read)
+
+	runID, err := Replay(ctx, client, read, false)
+	require.NoError(t, err)
+	require.Equal(t, "run-2", runID)
+	require.Equal(t, "instructlab replay of run-1", submitted["display_name"])
+	require.Equal(t, map[string]interface{}{"pipeline_id": "p-1", "pipeline_version_id": "v-3"}, submitted["pipeline_version_reference"])
+	require.Equal(t, map[string]interface{}{"pipeline_root": "s3://bucket/runs/1", "parameters": manifest.Run.Params}, submitted["runtime_config"])
+
+	moved := read
+	moved.Taxonomy.Commit = "0000000000000000000000000000000000000000"
+	_, err = Replay(ctx, client, moved, false)
+	require.ErrorContains(t, err, "refs/heads/main of "+git.URL+"/taxonomy.git moved from 0000000000000000000000000000000000000000 to "+repo.Commit())
+	_, err = Replay(ctx, client, moved, true)
+	require.NoError(t, err)
+
+	_, err = Export(ctx, client, "run-missing", now)
+	require.Error(t, err)
+}
+
+func TestCheckImages(t *testing.T) {
+	manifest := Manifest{RunID: "run-1", Images: []Image{
+		{Image: "quay.io/ilab/launcher:v1"},
+		{Image: "quay.io/ilab/sdg:v1", Digest: "sha256:aaa"},
+		{Image: "quay.io/ilab/train:v1", Digest: "sha256:bbb"},
+	}}
+	require.Empty(t, CheckImages(manifest, manifest.Images))
+	require.Equal(t, []string{
+		"image quay.io/ilab/sdg:v1 resolved to sha256:ddd, run run-1 ran sha256:aaa",
+		"image quay.io/ilab/train:v2 was not run by run run-1",
+		"image quay.io/ilab/train:v1 of run run-1 was not run by the replay",
+	}, CheckImages(manifest, []Image{
+		{Image: "quay.io/ilab/launcher:v1", Digest: "sha256:eee"},
+		{Image: "quay.io/ilab/sdg:v1", Digest: "sha256:ddd"},
+		{Image: "quay.io/ilab/train:v2", Digest: "sha256:bbb"},
+	}))
+}
+
+func TestTaxonomyRef(t *testing.T) {
+	require.Equal(t, "refs/heads/main", Taxonomy{}.Ref())
+	require.Equal(t, "refs/heads/release", Taxonomy{Branch: "release"}.Ref())
+	require.Equal(t, "refs/pull/12/head", Taxonomy{Branch: "main", PR: 12}.Ref())
+	require.Equal(t, Taxonomy{URL: "https://github.com/x/taxonomy", Branch: "main", PR: 12}, taxonomyOf(map[string]interface{}{"sdg_repo_url": "https://github.com/x/taxonomy", "sdg_repo_pr": float64(12)}))
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runservice
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// RoleViewer may list the runs, read their state and stream their logs
+	RoleViewer = "viewer"
+	// RoleSubmitter may also submit runs
+	RoleSubmitter = "submitter"
+	// RoleAdmin may also terminate runs
+	RoleAdmin = "admin"
+)
+
+// roleRanks orders the roles, a role grants the permissions of the lower roles
+var roleRanks = map[string]int{RoleViewer: 1, RoleSubmitter: 2, RoleAdmin: 3}
+
+// ForwardedTokenHeader is the header the OpenShift OAuth proxy passes the token of the user in, with --pass-access-token
+const ForwardedTokenHeader = "X-Forwarded-Access-Token"
+
+// DefaultReviewTTL is the time the result of a token review is reused for
+const DefaultReviewTTL = time.Minute
+
+// RoleBinding grants a role to OpenShift users and groups, e.g. the group system:authenticated
+type RoleBinding struct {
+	Role   string   `json:"role"`
+	Users  []string `json:"users,omitempty"`
+	Groups []string `json:"groups,omitempty"`
+}
+
+// Roles maps the users of the run service to their roles
+type Roles struct {
+	Bindings []RoleBinding `json:"bindings"`
+}
+
+// LoadRoles reads the role bindings of a YAML file
+func LoadRoles(path string) (Roles, error) {
+	var roles Roles
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return roles, err
+	}
+	if err := yaml.UnmarshalStrict(data, &roles); err != nil {
+		return roles, fmt.Errorf("invalid roles file %s: %w", path, err)
+	}
+	for i, binding := range roles.Bindings {
+		if roleRanks[binding.Role] == 0 {
+			return roles, fmt.Errorf("invalid roles file %s: binding %d has unknown role '%s'", path, i, binding.Role)
+		}
+	}
+	return roles, nil
+}
+
+// RoleOf returns the highest role bound to a user or to one of its groups, empty when none is
+func (r Roles) RoleOf(user string, groups []string) string {
+	role := ""
+	for _, binding := range r.Bindings {
+		if roleRanks[binding.Role] > roleRanks[role] && (contains(binding.Users, user) || containsAny(binding.Groups, groups)) {
+			role = binding.Role
+		}
+	}
+	return role
+}
+
+// Authenticator authenticates the requests of the run service with a TokenReview of their bearer token, and
+// authorizes them with the role of the user. The service account of the service needs the system:auth-delegator
+// cluster role to review tokens.
+type Authenticator struct {
+	Client kubernetes.Interface
+	Roles  Roles
+	// TTL is the time the review of a token is reused for, DefaultReviewTTL when zero
+	TTL time.Duration
+
+	now     func() time.Time
+	mu      sync.Mutex
+	reviews map[[sha256.Size]byte]review
+}
+
+type review struct {
+	user    authenticationv1.UserInfo
+	expires time.Time
+}
+
+// NewAuthenticator creates an authenticator reviewing the tokens with the API server of the client
+func NewAuthenticator(client kubernetes.Interface, roles Roles) *Authenticator {
+	return &Authenticator{Client: client, Roles: roles, now: time.Now, reviews: map[[sha256.Size]byte]review{}}
+}
+
+// Wrap authenticates and authorizes the requests before passing them to the handler. The token is read from the
+// Authorization header, or from the ForwardedTokenHeader behind the OAuth proxy. /healthz is not authenticated.
+func (a *Authenticator) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !found {
+			token = r.Header.Get(ForwardedTokenHeader)
+		}
+		if token == "" {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "a bearer token is required"})
+			return
+		}
+		user, err := a.review(r.Context(), token)
+		if err != nil {
+			log.Printf("Token review failed: %v", err)
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "the token is not valid"})
+			return
+		}
+		required := requiredRole(r)
+		role := a.Roles.RoleOf(user.Username, user.Groups)
+		if roleRanks[role] < roleRanks[required] {
+			writeJSON(w, http.StatusForbidden, map[string]string{"error": fmt.Sprintf("%s %s needs the %s role", r.Method, r.URL.Path, required)})
+			return
+		}
+		if r.Method != http.MethodGet {
+			log.Printf("%s (%s): %s %s", user.Username, role, r.Method, r.URL.Path)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requiredRole is the role a request needs: reads need RoleViewer, submissions RoleSubmitter and terminations
+// RoleAdmin
+func requiredRole(r *http.Request) string {
+	switch {
+	case r.Method == http.MethodGet:
+		return RoleViewer
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/runs":
+		return RoleSubmitter
+	}
+	return RoleAdmin
+}
+
+func (a *Authenticator) review(ctx context.Context, token string) (authenticationv1.UserInfo, error) {
+	key := sha256.Sum256([]byte(token))
+	a.mu.Lock()
+	cached, ok := a.reviews[key]
+	if ok && a.now().After(cached.expires) {
+		delete(a.reviews, key)
+		ok = false
+	}
+	a.mu.Unlock()
+	if ok {
+		return cached.user, nil
+	}
+
+	result, err := a.Client.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return authenticationv1.UserInfo{}, err
+	}
+	if !result.Status.Authenticated {
+		return authenticationv1.UserInfo{}, fmt.Errorf("token not authenticated: %s", result.Status.Error)
+	}
+	ttl := a.TTL
+	if ttl == 0 {
+		ttl = DefaultReviewTTL
+	}
+	a.mu.Lock()
+	a.reviews[key] = review{user: result.Status.User, expires: a.now().Add(ttl)}
+	a.mu.Unlock()
+	return result.Status.User, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(values, others []string) bool {
+	for _, other := range others {
+		if contains(values, other) {
+			return true
+		}
+	}
+	return false
+}
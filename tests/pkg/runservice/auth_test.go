@@ -0,0 +1,97 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runservice
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestAuthenticator(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "roles.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`bindings:
+  - role: viewer
+    groups: [system:authenticated]
+  - role: submitter
+    groups: [data-science]
+  - role: admin
+    users: [bob]
+`), 0o644))
+	roles, err := LoadRoles(path)
+	require.NoError(t, err)
+	require.Equal(t, RoleAdmin, roles.RoleOf("bob", []string{"system:authenticated", "data-science"}))
+	require.Equal(t, RoleSubmitter, roles.RoleOf("carol", []string{"system:authenticated", "data-science"}))
+	require.Equal(t, "", roles.RoleOf("system:anonymous", []string{"system:unauthenticated"}))
+
+	users := map[string]authenticationv1.UserInfo{
+		"alice-token": {Username: "alice", Groups: []string{"system:authenticated"}},
+		"carol-token": {Username: "carol", Groups: []string{"system:authenticated", "data-science"}},
+		"bob-token":   {Username: "bob", Groups: []string{"system:authenticated"}},
+	}
+	reviews := 0
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		reviews++
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		user, ok := users[review.Spec.Token]
+		review.Status = authenticationv1.TokenReviewStatus{Authenticated: ok, User: user}
+		return true, review, nil
+	})
+	authenticator := NewAuthenticator(client, roles)
+	now := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	authenticator.now = func() time.Time { return now }
+	handler := authenticator.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	request := func(method, path string, header ...string) int {
+		req := httptest.NewRequest(method, path, nil)
+		for i := 0; i < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+
+	require.Equal(t, http.StatusOK, request("GET", "/healthz"))
+	require.Equal(t, http.StatusUnauthorized, request("GET", "/v1/runs"))
+	require.Equal(t, http.StatusUnauthorized, request("GET", "/v1/runs", "Authorization", "Bearer stolen"))
+	require.Equal(t, http.StatusOK, request("GET", "/v1/runs", "Authorization", "Bearer alice-token"))
+	require.Equal(t, http.StatusForbidden, request("POST", "/v1/runs", "Authorization", "Bearer alice-token"))
+	require.Equal(t, http.StatusOK, request("POST", "/v1/runs", ForwardedTokenHeader, "carol-token"))
+	require.Equal(t, http.StatusForbidden, request("DELETE", "/v1/runs/run-1", ForwardedTokenHeader, "carol-token"))
+	require.Equal(t, http.StatusOK, request("DELETE", "/v1/runs/run-1", "Authorization", "Bearer bob-token"))
+
+	// The reviews are reused until they expire
+	require.Equal(t, 4, reviews)
+	require.Equal(t, http.StatusOK, request("GET", "/v1/runs/run-1", "Authorization", "Bearer alice-token"))
+	require.Equal(t, 4, reviews)
+	now = now.Add(DefaultReviewTTL + time.Second)
+	require.Equal(t, http.StatusOK, request("GET", "/v1/runs/run-1", "Authorization", "Bearer alice-token"))
+	require.Equal(t, 5, reviews)
+
+	require.NoError(t, os.WriteFile(path, []byte("bindings:\n  - role: owner\n    users: [bob]\n"), 0o644))
+	_, err = LoadRoles(path)
+	require.EqualError(t, err, "invalid roles file "+path+": binding 0 has unknown role 'owner'")
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package runservice serves the runs of the ilab Go client over REST, for web UIs and external schedulers:
+//
+//	POST /v1/runs            submits a run of the ilab.RunSpec of the body and returns {"run_id": ...}
+//	GET  /v1/runs            lists the runs, the most recent first
+//	GET  /v1/runs/<id>       returns the state of a run and of its tasks
+//	GET  /v1/runs/<id>/logs  streams the logs of the task pods of a run as plain text until it finishes
+//	DELETE /v1/runs/<id>     terminates a run
+//	GET  /healthz            returns 200 once the service is up
+//
+// Errors are returned as {"error": ...}. The API has no authentication of its own, see Authenticator.
+package runservice
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/ilab"
+)
+
+// Runs launches and follows the runs, *ilab.Client outside of the tests
+type Runs interface {
+	SubmitRun(ctx context.Context, spec ilab.RunSpec) (string, error)
+	GetRun(ctx context.Context, runID string) (ilab.RunStatus, error)
+	ListRuns(ctx context.Context) ([]ilab.RunStatus, error)
+	StreamLogs(ctx context.Context, runID string, w io.Writer) error
+	Terminate(ctx context.Context, runID string) error
+}
+
+// NewHandler returns the handler of the REST API of the runs
+func NewHandler(runs Runs) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/v1/runs", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			list, err := runs.ListRuns(r.Context())
+			if err != nil {
+				writeError(w, http.StatusBadGateway, err)
+				return
+			}
+			if list == nil {
+				list = []ilab.RunStatus{}
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{"runs": list})
+		case http.MethodPost:
+			var spec ilab.RunSpec
+			decoder := json.NewDecoder(r.Body)
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(&spec); err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+			if spec.Pipeline == "" {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "pipeline is required"})
+				return
+			}
+			runID, err := runs.SubmitRun(r.Context(), spec)
+			if err != nil {
+				writeError(w, http.StatusBadGateway, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]string{"run_id": runID})
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/v1/runs/", func(w http.ResponseWriter, r *http.Request) {
+		runID, logs := strings.TrimPrefix(r.URL.Path, "/v1/runs/"), false
+		if strings.HasSuffix(runID, "/logs") {
+			runID, logs = strings.TrimSuffix(runID, "/logs"), true
+		}
+		if runID == "" || strings.Contains(runID, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == http.MethodDelete && !logs {
+			if err := runs.Terminate(r.Context(), runID); err != nil {
+				writeError(w, http.StatusBadGateway, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]string{"run_id": runID})
+			return
+		}
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", "GET, DELETE")
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if !logs {
+			status, err := runs.GetRun(r.Context(), runID)
+			if err != nil {
+				writeError(w, http.StatusBadGateway, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, status)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if err := runs.StreamLogs(r.Context(), runID, flushWriter{w}); err != nil && r.Context().Err() == nil {
+			// The status was sent with the first line of the logs, the error ends the stream
+			log.Printf("Streaming the logs of run %s failed: %v", runID, err)
+			_, _ = io.WriteString(w, "\nerror: "+err.Error()+"\n")
+		}
+	})
+	return mux
+}
+
+// flushWriter sends every write to the client right away, the logs are followed as they are produced
+type flushWriter struct {
+	w http.ResponseWriter
+}
+
+func (f flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if flusher, ok := f.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return n, err
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("Failed to write the response: %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runservice
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/opendatahub-io/ilab-on-ocp/tests/pkg/ilab"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRuns struct {
+	submitted  []ilab.RunSpec
+	terminated []string
+}
+
+func (f *fakeRuns) SubmitRun(ctx context.Context, spec ilab.RunSpec) (string, error) {
+	f.submitted = append(f.submitted, spec)
+	return "run-1", nil
+}
+
+func (f *fakeRuns) GetRun(ctx context.Context, runID string) (ilab.RunStatus, error) {
+	if runID != "run-1" {
+		return ilab.RunStatus{}, fmt.Errorf("failed to get run %s: status 404: not found", runID)
+	}
+	return ilab.RunStatus{RunID: runID, State: "RUNNING", Tasks: []ilab.TaskStatus{{Name: "sdg-op", State: "SUCCEEDED"}}}, nil
+}
+
+func (f *fakeRuns) ListRuns(ctx context.Context) ([]ilab.RunStatus, error) {
+	return []ilab.RunStatus{{RunID: "run-1", DisplayName: "instructlab", State: "RUNNING"}}, nil
+}
+
+func (f *fakeRuns) StreamLogs(ctx context.Context, runID string, w io.Writer) error {
+	_, _ = io.WriteString(w, "==> sdg <==\nGenerating\n")
+	return fmt.Errorf("failed to list the pods of run %s", runID)
+}
+
+func (f *fakeRuns) Terminate(ctx context.Context, runID string) error {
+	f.terminated = append(f.terminated, runID)
+	return nil
+}
+
+func TestHandler(t *testing.T) {
+	runs := &fakeRuns{}
+	server := httptest.NewServer(NewHandler(runs))
+	defer server.Close()
+	request := func(method, path, body string) (int, string) {
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		data, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(data)
+	}
+
+	status, body := request("POST", "/v1/runs", `{"pipeline":"instructlab","params":{"train_num_epochs_phase_1":2}}`)
+	require.Equal(t, http.StatusOK, status)
+	require.JSONEq(t, `{"run_id":"run-1"}`, body)
+	require.Equal(t, []ilab.RunSpec{{Pipeline: "instructlab", Params: map[string]interface{}{"train_num_epochs_phase_1": float64(2)}}}, runs.submitted)
+	status, body = request("POST", "/v1/runs", `{"pipeline_name":"instructlab"}`)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.JSONEq(t, `{"error":"json: unknown field \"pipeline_name\""}`, body)
+	status, body = request("POST", "/v1/runs", `{}`)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.JSONEq(t, `{"error":"pipeline is required"}`, body)
+
+	status, body = request("GET", "/v1/runs", "")
+	require.Equal(t, http.StatusOK, status)
+	require.JSONEq(t, `{"runs":[{"run_id":"run-1","display_name":"instructlab","created_at":"0001-01-01T00:00:00Z","state":"RUNNING"}]}`, body)
+
+	status, body = request("GET", "/v1/runs/run-1", "")
+	require.Equal(t, http.StatusOK, status)
+	require.JSONEq(t, `{"run_id":"run-1","display_name":"","created_at":"0001-01-01T00:00:00Z","state":"RUNNING","tasks":[{"name":"sdg-op","state":"SUCCEEDED"}]}`, body)
+	status, body = request("GET", "/v1/runs/run-2", "")
+	require.Equal(t, http.StatusBadGateway, status)
+	require.JSONEq(t, `{"error":"failed to get run run-2: status 404: not found"}`, body)
+
+	status, body = request("GET", "/v1/runs/run-1/logs", "")
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, "==> sdg <==\nGenerating\n\nerror: failed to list the pods of run run-1\n", body)
+
+	status, body = request("DELETE", "/v1/runs/run-1", "")
+	require.Equal(t, http.StatusOK, status)
+	require.JSONEq(t, `{"run_id":"run-1"}`, body)
+	require.Equal(t, []string{"run-1"}, runs.terminated)
+	status, _ = request("DELETE", "/v1/runs/run-1/logs", "")
+	require.Equal(t, http.StatusMethodNotAllowed, status)
+	status, _ = request("GET", "/v1/runs/run-1/tasks", "")
+	require.Equal(t, http.StatusNotFound, status)
+}
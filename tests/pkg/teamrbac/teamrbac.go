@@ -0,0 +1,138 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package teamrbac generates the minimal RBAC a team needs to run ilab workflows in a namespace. The e2e tests check
+// the same rules, so the manifests handed to customers cannot drift from what the tests validate.
+package teamrbac
+
+import (
+	"bytes"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	readVerbs  = []string{"get", "list", "watch"}
+	writeVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+)
+
+// PolicyRules are the namespaced permissions of a team running ilab workflows. The pipeline server runs the workflow
+// pods and PyTorchJobs as its own service account, so the team only manages the inputs of the runs and follows them.
+var PolicyRules = []rbacv1.PolicyRule{
+	// Teacher, judge and object store credentials, taxonomy and run configuration, model and output volumes
+	{APIGroups: []string{""}, Resources: []string{"secrets", "configmaps", "persistentvolumeclaims"}, Verbs: writeVerbs},
+	// Following the run pods and their logs
+	{APIGroups: []string{""}, Resources: []string{"pods", "pods/log", "events"}, Verbs: readVerbs},
+	// Access to the pipeline server, which is authorized against its route
+	{APIGroups: []string{"datasciencepipelinesapplications.opendatahub.io"}, Resources: []string{"datasciencepipelinesapplications"}, Verbs: readVerbs},
+	{APIGroups: []string{"route.openshift.io"}, Resources: []string{"routes"}, Verbs: readVerbs},
+	// Following the workflows and the training jobs of the runs
+	{APIGroups: []string{"argoproj.io"}, Resources: []string{"workflows"}, Verbs: readVerbs},
+	{APIGroups: []string{"kubeflow.org"}, Resources: []string{"pytorchjobs"}, Verbs: readVerbs},
+}
+
+// Options selects the team, the namespace and how the permissions are granted
+type Options struct {
+	Team      string
+	Namespace string
+	// ClusterRole grants the rules with a ClusterRole, reusable by the teams of every namespace, rather than a Role
+	ClusterRole bool
+	// Groups are bound along with the service account of the team, e.g. the OpenShift group of its members
+	Groups []string
+}
+
+// Name returns the name of the role, binding and service account of a team
+func Name(team string) string {
+	return "ilab-" + team
+}
+
+// Manifest holds the objects granting the rules to a team, either Role or ClusterRole being set
+type Manifest struct {
+	ServiceAccount *corev1.ServiceAccount
+	Role           *rbacv1.Role
+	ClusterRole    *rbacv1.ClusterRole
+	RoleBinding    *rbacv1.RoleBinding
+}
+
+// Objects returns the service account of the team, the Role or ClusterRole holding PolicyRules, and the RoleBinding
+// granting it in the namespace
+func Objects(options Options) (Manifest, error) {
+	if options.Team == "" || options.Namespace == "" {
+		return Manifest{}, fmt.Errorf("the team and the namespace must be set")
+	}
+	name := Name(options.Team)
+	labels := map[string]string{"app.kubernetes.io/part-of": "ilab", "ilab.opendatahub.io/team": options.Team}
+
+	var manifest Manifest
+	manifest.ServiceAccount = &corev1.ServiceAccount{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: options.Namespace, Labels: labels},
+	}
+
+	roleRef := rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: name}
+	if options.ClusterRole {
+		roleRef.Kind = "ClusterRole"
+		manifest.ClusterRole = &rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+			Rules:      PolicyRules,
+		}
+	} else {
+		manifest.Role = &rbacv1.Role{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "Role"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: options.Namespace, Labels: labels},
+			Rules:      PolicyRules,
+		}
+	}
+
+	subjects := []rbacv1.Subject{{Kind: "ServiceAccount", Name: name, Namespace: options.Namespace}}
+	for _, group := range options.Groups {
+		subjects = append(subjects, rbacv1.Subject{Kind: "Group", APIGroup: rbacv1.GroupName, Name: group})
+	}
+	manifest.RoleBinding = &rbacv1.RoleBinding{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "RoleBinding"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: options.Namespace, Labels: labels},
+		RoleRef:    roleRef,
+		Subjects:   subjects,
+	}
+	return manifest, nil
+}
+
+// Render returns the objects of a team as a multi-document YAML manifest, to be applied with `oc apply -f`
+func Render(options Options) ([]byte, error) {
+	manifest, err := Objects(options)
+	if err != nil {
+		return nil, err
+	}
+	objects := []interface{}{manifest.ServiceAccount, manifest.Role, manifest.RoleBinding}
+	if manifest.ClusterRole != nil {
+		objects[1] = manifest.ClusterRole
+	}
+	var rendered bytes.Buffer
+	for i, object := range objects {
+		document, err := yaml.Marshal(object)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render the RBAC of team %s: %w", options.Team, err)
+		}
+		if i > 0 {
+			rendered.WriteString("---\n")
+		}
+		// Objects are rendered before they are created, without the server-set fields
+		rendered.Write(bytes.ReplaceAll(document, []byte("  creationTimestamp: null\n"), nil))
+	}
+	return rendered.Bytes(), nil
+}
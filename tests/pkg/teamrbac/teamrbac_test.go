@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package teamrbac
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func TestObjects(t *testing.T) {
+	_, err := Objects(Options{Team: "data-science"})
+	require.Error(t, err)
+
+	manifest, err := Objects(Options{Team: "data-science", Namespace: "ilab", Groups: []string{"ds"}})
+	require.NoError(t, err)
+	require.Nil(t, manifest.ClusterRole)
+	require.Equal(t, "ilab", manifest.Role.Namespace)
+	require.Equal(t, PolicyRules, manifest.Role.Rules)
+	require.Equal(t, "Role", manifest.RoleBinding.RoleRef.Kind)
+	require.Len(t, manifest.RoleBinding.Subjects, 2)
+	require.Equal(t, "ilab-data-science", manifest.RoleBinding.Subjects[0].Name)
+	require.Equal(t, "Group", manifest.RoleBinding.Subjects[1].Kind)
+
+	manifest, err = Objects(Options{Team: "data-science", Namespace: "ilab", ClusterRole: true})
+	require.NoError(t, err)
+	require.Nil(t, manifest.Role)
+	require.Empty(t, manifest.ClusterRole.Namespace)
+	require.Equal(t, "ClusterRole", manifest.RoleBinding.RoleRef.Kind)
+}
+
+func TestRender(t *testing.T) {
+	rendered, err := Render(Options{Team: "data-science", Namespace: "ilab", ClusterRole: true})
+	require.NoError(t, err)
+	documents := strings.Split(string(rendered), "---\n")
+	require.Len(t, documents, 3)
+	var role map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(documents[1]), &role))
+	require.Equal(t, "ClusterRole", role["kind"])
+	require.NotContains(t, string(rendered), "creationTimestamp")
+}
+
+// TestDocsExample keeps the manifest of docs/team_rbac.md in sync with the rules
+func TestDocsExample(t *testing.T) {
+	docs, err := os.ReadFile("../../../docs/team_rbac.md")
+	require.NoError(t, err)
+	_, example, found := strings.Cut(string(docs), "```yaml\n")
+	require.True(t, found, "No YAML example in docs/team_rbac.md")
+	example, _, _ = strings.Cut(example, "```")
+
+	rendered, err := Render(Options{Team: "data-science", Namespace: "ilab", Groups: []string{"data-science"}})
+	require.NoError(t, err)
+	require.Equal(t, string(rendered), example, "docs/team_rbac.md is out of date, regenerate its example with cmd/team-rbac")
+}
@@ -0,0 +1,290 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package watcher waits for Kubernetes objects to reach a state, with typed predicates over the jobs and pods of
+// pipeline runs. It is built on the controller-runtime client so the tests, the command line tools and an operator
+// share the same conditions.
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// PyTorchJobGVK is the kind of the PyTorchJobs of the Training Operator
+var PyTorchJobGVK = schema.GroupVersionKind{Group: "kubeflow.org", Version: "v1", Kind: "PyTorchJob"}
+
+// TektonPipelineRunGVK is the kind of the PipelineRuns of OpenShift Pipelines
+var TektonPipelineRunGVK = schema.GroupVersionKind{Group: "tekton.dev", Version: "v1", Kind: "PipelineRun"}
+
+// ArgoWorkflowGVK is the kind of the Workflows of Argo Workflows
+var ArgoWorkflowGVK = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Workflow"}
+
+// InstructLabRunGVK is the kind of the InstructLabRuns of api/v1alpha1
+var InstructLabRunGVK = schema.GroupVersionKind{Group: "ilab.opendatahub.io", Version: "v1alpha1", Kind: "InstructLabRun"}
+
+// Predicate tells whether an object reached the awaited state. It returns an error when the object can no longer
+// reach it, e.g. a failed job awaited to complete.
+type Predicate[T client.Object] func(obj T) (bool, error)
+
+// NewClient builds a client able to watch the built-in kinds and unstructured objects
+func NewClient(config *rest.Config) (client.WithWatch, error) {
+	return client.NewWithWatch(config, client.Options{Scheme: clientgoscheme.Scheme})
+}
+
+// NewPyTorchJob returns an unstructured PyTorchJob to wait for
+func NewPyTorchJob() *unstructured.Unstructured {
+	job := &unstructured.Unstructured{}
+	job.SetGroupVersionKind(PyTorchJobGVK)
+	return job
+}
+
+// NewTektonPipelineRun returns an unstructured Tekton PipelineRun to wait for
+func NewTektonPipelineRun() *unstructured.Unstructured {
+	run := &unstructured.Unstructured{}
+	run.SetGroupVersionKind(TektonPipelineRunGVK)
+	return run
+}
+
+// NewInstructLabRun returns an unstructured InstructLabRun to wait for
+func NewInstructLabRun() *unstructured.Unstructured {
+	run := &unstructured.Unstructured{}
+	run.SetGroupVersionKind(InstructLabRunGVK)
+	return run
+}
+
+// Until waits until the object named by key satisfies the predicate, the predicate fails or the context is done.
+// obj receives the object, it must carry its kind when unstructured. A missing object does not satisfy the
+// predicate, it may be created later.
+func Until[T client.Object](ctx context.Context, c client.WithWatch, key client.ObjectKey, obj T, predicate Predicate[T]) error {
+	list, err := listFor(c.Scheme(), obj)
+	if err != nil {
+		return err
+	}
+	for {
+		// Watch before reading the object so no change is missed between both
+		watch, err := c.Watch(ctx, list, client.InNamespace(key.Namespace), client.MatchingFieldsSelector{
+			Selector: fields.OneTermEqualSelector("metadata.name", key.Name),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to watch %s: %w", key, err)
+		}
+		done, err := check(ctx, c, key, obj, predicate)
+	events:
+		for !done && err == nil {
+			select {
+			case <-ctx.Done():
+				err = fmt.Errorf("%s did not reach the awaited state: %w", key, ctx.Err())
+			case event, ok := <-watch.ResultChan():
+				if !ok {
+					// The watch expired, start a new one
+					break events
+				}
+				if meta, isObject := event.Object.(client.Object); isObject && meta.GetName() != key.Name {
+					continue
+				}
+				done, err = check(ctx, c, key, obj, predicate)
+			}
+		}
+		watch.Stop()
+		if done || err != nil {
+			return err
+		}
+	}
+}
+
+func check[T client.Object](ctx context.Context, c client.Client, key client.ObjectKey, obj T, predicate Predicate[T]) (bool, error) {
+	if err := c.Get(ctx, key, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get %s: %w", key, err)
+	}
+	return predicate(obj)
+}
+
+// listFor returns an empty list of the kind of obj
+func listFor(scheme *runtime.Scheme, obj client.Object) (client.ObjectList, error) {
+	gvk, err := apiutil.GVKForObject(obj, scheme)
+	if err != nil {
+		return nil, err
+	}
+	gvk.Kind += "List"
+	if _, ok := obj.(*unstructured.Unstructured); ok {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk)
+		return list, nil
+	}
+	list, err := scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	return list.(client.ObjectList), nil
+}
+
+// JobComplete is satisfied by a Job with the Complete condition and fails on the Failed condition
+func JobComplete(job *batchv1.Job) (bool, error) {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return true, nil
+		case batchv1.JobFailed:
+			return false, fmt.Errorf("job %s failed: %s", job.Name, describe(condition.Reason, condition.Message))
+		}
+	}
+	return false, nil
+}
+
+// PyTorchJobSucceeded is satisfied by a PyTorchJob with the Succeeded condition and fails on the Failed condition
+func PyTorchJobSucceeded(job *unstructured.Unstructured) (bool, error) {
+	conditions, _, _ := unstructured.NestedSlice(job.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["status"] != "True" {
+			continue
+		}
+		switch condition["type"] {
+		case "Succeeded":
+			return true, nil
+		case "Failed":
+			reason, _ := condition["reason"].(string)
+			message, _ := condition["message"].(string)
+			return false, fmt.Errorf("PyTorchJob %s failed: %s", job.GetName(), describe(reason, message))
+		}
+	}
+	return false, nil
+}
+
+// TektonPipelineRunSucceeded is satisfied by a PipelineRun with the Succeeded condition true and fails when the
+// condition is false, which Tekton sets on failed, timed out and cancelled runs
+func TektonPipelineRunSucceeded(run *unstructured.Unstructured) (bool, error) {
+	conditions, _, _ := unstructured.NestedSlice(run.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != "Succeeded" {
+			continue
+		}
+		switch condition["status"] {
+		case "True":
+			return true, nil
+		case "False":
+			reason, _ := condition["reason"].(string)
+			message, _ := condition["message"].(string)
+			return false, fmt.Errorf("PipelineRun %s failed: %s", run.GetName(), describe(reason, message))
+		}
+	}
+	return false, nil
+}
+
+// InstructLabRunSucceeded is satisfied by an InstructLabRun with the Succeeded condition true and fails when the
+// condition is false, or when the controller rejected its spec
+func InstructLabRunSucceeded(run *unstructured.Unstructured) (bool, error) {
+	conditions, _, _ := unstructured.NestedSlice(run.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		reason, _ := condition["reason"].(string)
+		message, _ := condition["message"].(string)
+		switch {
+		case condition["type"] == "Succeeded" && condition["status"] == "True":
+			return true, nil
+		case condition["type"] == "Succeeded" && condition["status"] == "False":
+			return false, fmt.Errorf("InstructLabRun %s failed: %s", run.GetName(), describe(reason, message))
+		case condition["type"] == "Submitted" && condition["status"] == "False" && reason == "InvalidSpec":
+			return false, fmt.Errorf("InstructLabRun %s was not submitted: %s", run.GetName(), describe(reason, message))
+		}
+	}
+	return false, nil
+}
+
+// ArgoWorkflowSucceeded is satisfied by a Workflow in the Succeeded phase and fails in the Failed and Error phases
+func ArgoWorkflowSucceeded(workflow *unstructured.Unstructured) (bool, error) {
+	phase, _, _ := unstructured.NestedString(workflow.Object, "status", "phase")
+	switch phase {
+	case "Succeeded":
+		return true, nil
+	case "Failed", "Error":
+		message, _, _ := unstructured.NestedString(workflow.Object, "status", "message")
+		return false, fmt.Errorf("Workflow %s failed: %s", workflow.GetName(), describe(phase, message))
+	}
+	return false, nil
+}
+
+// PodSucceeded is satisfied by a pod which succeeded and fails when the pod failed
+func PodSucceeded(pod *corev1.Pod) (bool, error) {
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return true, nil
+	case corev1.PodFailed:
+		return false, fmt.Errorf("pod %s failed: %s", pod.Name, describe(pod.Status.Reason, pod.Status.Message))
+	}
+	return false, nil
+}
+
+// PodFailedWithReason is satisfied by a pod which failed with the reason, e.g. Evicted, or with a container
+// terminated for the reason, e.g. OOMKilled. It fails when the pod succeeded or failed for another reason.
+func PodFailedWithReason(reason string) Predicate[*corev1.Pod] {
+	return func(pod *corev1.Pod) (bool, error) {
+		var reasons []string
+		if pod.Status.Reason != "" {
+			reasons = append(reasons, pod.Status.Reason)
+		}
+		for _, status := range append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+			for _, state := range []corev1.ContainerState{status.State, status.LastTerminationState} {
+				if state.Terminated != nil && state.Terminated.Reason != "" {
+					reasons = append(reasons, state.Terminated.Reason)
+				}
+			}
+		}
+		for _, got := range reasons {
+			if got == reason {
+				return true, nil
+			}
+		}
+
+		switch pod.Status.Phase {
+		case corev1.PodSucceeded:
+			return false, fmt.Errorf("pod %s succeeded instead of failing with %s", pod.Name, reason)
+		case corev1.PodFailed:
+			return false, fmt.Errorf("pod %s failed with %s instead of %s", pod.Name, strings.Join(reasons, ", "), reason)
+		}
+		return false, nil
+	}
+}
+
+func describe(reason, message string) string {
+	switch {
+	case reason == "":
+		return message
+	case message == "":
+		return reason
+	}
+	return reason + ": " + message
+}
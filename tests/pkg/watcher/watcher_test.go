@@ -0,0 +1,221 @@
+/*
+Copyright 2025.
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package watcher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestUntilJobComplete(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	c := fake.NewClientBuilder().Build()
+	key := client.ObjectKey{Namespace: "ilab", Name: "data-processing"}
+
+	// The job is created and completes while waiting
+	go func() {
+		job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+		if c.Create(ctx, job) != nil {
+			return
+		}
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+		_ = c.Status().Update(ctx, job)
+	}()
+	job := &batchv1.Job{}
+	require.NoError(t, Until(ctx, c, key, job, JobComplete))
+	require.Equal(t, key.Name, job.Name)
+}
+
+func TestUntilJobFailed(t *testing.T) {
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ilab", Name: "data-processing"},
+		Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded", Message: "Job has reached the specified backoff limit"},
+		}},
+	}
+	c := fake.NewClientBuilder().WithObjects(job).Build()
+	err := Until(context.Background(), c, client.ObjectKeyFromObject(job), &batchv1.Job{}, JobComplete)
+	require.EqualError(t, err, "job data-processing failed: BackoffLimitExceeded: Job has reached the specified backoff limit")
+}
+
+func TestUntilTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ilab", Name: "probe"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}}
+	c := fake.NewClientBuilder().WithObjects(pod).Build()
+	err := Until(ctx, c, client.ObjectKeyFromObject(pod), &corev1.Pod{}, PodSucceeded)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorContains(t, err, "ilab/probe did not reach the awaited state")
+}
+
+func TestUntilPyTorchJobSucceeded(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	scheme.AddKnownTypeWithName(PyTorchJobGVK, &unstructured.Unstructured{})
+	scheme.AddKnownTypeWithName(PyTorchJobGVK.GroupVersion().WithKind("PyTorchJobList"), &unstructured.UnstructuredList{})
+
+	job := NewPyTorchJob()
+	job.SetNamespace("ilab")
+	job.SetName("train-phase-1")
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(job).Build()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	go func() {
+		current := NewPyTorchJob()
+		if c.Get(ctx, client.ObjectKeyFromObject(job), current) != nil {
+			return
+		}
+		_ = unstructured.SetNestedSlice(current.Object, []interface{}{
+			map[string]interface{}{"type": "Running", "status": "False"},
+			map[string]interface{}{"type": "Succeeded", "status": "True"},
+		}, "status", "conditions")
+		_ = c.Update(ctx, current)
+	}()
+	require.NoError(t, Until(ctx, c, client.ObjectKeyFromObject(job), NewPyTorchJob(), PyTorchJobSucceeded))
+}
+
+func TestPyTorchJobFailed(t *testing.T) {
+	job := NewPyTorchJob()
+	job.SetName("train-phase-2")
+	require.NoError(t, unstructured.SetNestedSlice(job.Object, []interface{}{
+		map[string]interface{}{"type": "Failed", "status": "True", "reason": "PyTorchJobFailed", "message": "master-0 exited with code 1"},
+	}, "status", "conditions"))
+	done, err := PyTorchJobSucceeded(job)
+	require.False(t, done)
+	require.EqualError(t, err, "PyTorchJob train-phase-2 failed: PyTorchJobFailed: master-0 exited with code 1")
+}
+
+func TestTektonPipelineRunSucceeded(t *testing.T) {
+	run := NewTektonPipelineRun()
+	run.SetName("ilab-tekton")
+	setSucceeded := func(status, reason, message string) {
+		require.NoError(t, unstructured.SetNestedSlice(run.Object, []interface{}{
+			map[string]interface{}{"type": "Succeeded", "status": status, "reason": reason, "message": message},
+		}, "status", "conditions"))
+	}
+
+	setSucceeded("Unknown", "Running", "Tasks Completed: 1 (Failed: 0, Cancelled 0), Incomplete: 2, Skipped: 0")
+	done, err := TektonPipelineRunSucceeded(run)
+	require.False(t, done)
+	require.NoError(t, err)
+
+	setSucceeded("True", "Succeeded", "Tasks Completed: 3 (Failed: 0, Cancelled 0), Skipped: 0")
+	done, err = TektonPipelineRunSucceeded(run)
+	require.True(t, done)
+	require.NoError(t, err)
+
+	setSucceeded("False", "PipelineRunTimeout", "PipelineRun ilab-tekton failed to finish within 1h0m0s")
+	_, err = TektonPipelineRunSucceeded(run)
+	require.EqualError(t, err, "PipelineRun ilab-tekton failed: PipelineRunTimeout: PipelineRun ilab-tekton failed to finish within 1h0m0s")
+}
+
+func TestInstructLabRunSucceeded(t *testing.T) {
+	run := NewInstructLabRun()
+	run.SetName("granite")
+	setConditions := func(conditions ...map[string]interface{}) {
+		list := make([]interface{}, len(conditions))
+		for i, condition := range conditions {
+			list[i] = condition
+		}
+		require.NoError(t, unstructured.SetNestedSlice(run.Object, list, "status", "conditions"))
+	}
+
+	setConditions(map[string]interface{}{"type": "Submitted", "status": "False", "reason": "SubmitFailed", "message": "status 503"})
+	done, err := InstructLabRunSucceeded(run)
+	require.False(t, done)
+	require.NoError(t, err, "a failed submission is retried")
+
+	setConditions(
+		map[string]interface{}{"type": "Submitted", "status": "True", "reason": "Submitted", "message": "Run run-1 submitted"},
+		map[string]interface{}{"type": "Succeeded", "status": "Unknown", "reason": "Running", "message": "The run has not finished"},
+	)
+	done, err = InstructLabRunSucceeded(run)
+	require.False(t, done)
+	require.NoError(t, err)
+
+	setConditions(map[string]interface{}{"type": "Succeeded", "status": "True", "reason": "SUCCEEDED", "message": "The run succeeded"})
+	done, err = InstructLabRunSucceeded(run)
+	require.True(t, done)
+	require.NoError(t, err)
+
+	setConditions(map[string]interface{}{"type": "Succeeded", "status": "False", "reason": "FAILED", "message": "The run finished in state FAILED"})
+	_, err = InstructLabRunSucceeded(run)
+	require.EqualError(t, err, "InstructLabRun granite failed: FAILED: The run finished in state FAILED")
+
+	setConditions(map[string]interface{}{"type": "Submitted", "status": "False", "reason": "InvalidSpec", "message": "sdg.repoURL is required"})
+	_, err = InstructLabRunSucceeded(run)
+	require.EqualError(t, err, "InstructLabRun granite was not submitted: InvalidSpec: sdg.repoURL is required")
+}
+
+func TestArgoWorkflowSucceeded(t *testing.T) {
+	workflow := &unstructured.Unstructured{}
+	workflow.SetGroupVersionKind(ArgoWorkflowGVK)
+	workflow.SetName("ilab-argo")
+
+	for phase, done := range map[string]bool{"": false, "Running": false, "Succeeded": true} {
+		require.NoError(t, unstructured.SetNestedField(workflow.Object, phase, "status", "phase"))
+		got, err := ArgoWorkflowSucceeded(workflow)
+		require.NoError(t, err)
+		require.Equal(t, done, got, phase)
+	}
+
+	require.NoError(t, unstructured.SetNestedField(workflow.Object, "Failed", "status", "phase"))
+	require.NoError(t, unstructured.SetNestedField(workflow.Object, "child 'ilab-argo-train' failed", "status", "message"))
+	_, err := ArgoWorkflowSucceeded(workflow)
+	require.EqualError(t, err, "Workflow ilab-argo failed: Failed: child 'ilab-argo-train' failed")
+}
+
+func TestPodPredicates(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "train-master-0"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}}
+	done, err := PodFailedWithReason("OOMKilled")(pod)
+	require.False(t, done)
+	require.NoError(t, err)
+
+	// A restarted container killed for running out of memory
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled"}},
+	}}
+	done, err = PodFailedWithReason("OOMKilled")(pod)
+	require.True(t, done)
+	require.NoError(t, err)
+
+	evicted := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "sdg"}, Status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted", Message: "The node was low on resource: memory"}}
+	done, err = PodFailedWithReason("Evicted")(evicted)
+	require.True(t, done)
+	require.NoError(t, err)
+	_, err = PodFailedWithReason("OOMKilled")(evicted)
+	require.EqualError(t, err, "pod sdg failed with Evicted instead of OOMKilled")
+	_, err = PodSucceeded(evicted)
+	require.EqualError(t, err, "pod sdg failed: Evicted: The node was low on resource: memory")
+
+	succeeded := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "probe"}, Status: corev1.PodStatus{Phase: corev1.PodSucceeded}}
+	_, err = PodFailedWithReason("Evicted")(succeeded)
+	require.EqualError(t, err, "pod probe succeeded instead of failing with Evicted")
+	done, err = PodSucceeded(succeeded)
+	require.True(t, done)
+	require.NoError(t, err)
+}